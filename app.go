@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"maps"
 	"math"
 	"os"
 	"path/filepath"
@@ -14,23 +17,42 @@ import (
 	"time"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
 	"gopkg.in/yaml.v3"
 
+	"market-terminal/internal/alerts"
 	"market-terminal/internal/api"
 	"market-terminal/internal/charts"
 	"market-terminal/internal/config"
 	"market-terminal/internal/coordinator"
+	"market-terminal/internal/crash"
 	"market-terminal/internal/database"
+	"market-terminal/internal/datasync"
+	"market-terminal/internal/deeplink"
+	"market-terminal/internal/hotkeys"
+	"market-terminal/internal/keychain"
+	"market-terminal/internal/metrics"
+	"market-terminal/internal/model"
+	"market-terminal/internal/placement"
+	"market-terminal/internal/processors"
+	"market-terminal/internal/scripting"
+	"market-terminal/internal/reports"
 	"market-terminal/internal/scheduler"
+	"market-terminal/internal/shutdown"
+	"market-terminal/internal/sound"
+	"market-terminal/internal/tracing"
+	"market-terminal/internal/tsdb"
 	"market-terminal/internal/utils"
 )
 
 // App struct represents the main application
 type App struct {
 	ctx                context.Context
+	shutdownCtx        context.Context    // Cancelled first thing in ServiceShutdown - in-flight loads and fetches stop
+	cancelShutdown     context.CancelFunc
 	appRef             interface{} // Reference to Wails application (set via SetApp)
 	settingsManager    *config.SettingsManager
-	dataWriter         *database.DataWriter
+	dataWriter         *database.DataWriter // nil in read-only mode
 	dataLoader         *database.DataLoader
 	apiClient          *api.Client
 	querySystem        *api.QuerySystem
@@ -41,13 +63,48 @@ type App struct {
 	coordinator        *coordinator.DataCollectionCoordinator
 	chartTracker      *charts.ChartTracker
 	healthCheck        *coordinator.HealthCheck
+	marketCloseWatcher *scheduler.MarketCloseWatcher
+	preOpenWatcher     *scheduler.PreOpenWatcher // Session warm start at 9:25 ET (API check, databases, schemas)
+	marketDateWatcher  *scheduler.MarketDateWatcher // Emits market-date-changed at the 8:30 ET rollover
+	endOfDayReporter   *reports.EndOfDayReporter
+	syncer             *datasync.Syncer
+	memoryMonitor      *metrics.MemoryMonitor
+	diskMonitor        *metrics.DiskMonitor       // Free space on the data directory's volume
+	activityMonitor    *scheduler.ActivityMonitor // Slows polling when no window has been focused for a while
+	ecoMonitor         *scheduler.EcoMonitor      // Battery saving mode (on battery or turned on by the user)
+	profilerSuspended  string                     // Address of the profiler stopped by eco mode ("" = not suspended)
+	ecoLock            sync.Mutex
+	snapshotScheduler  *scheduler.SnapshotScheduler // Scheduled chart images (chart_snapshots setting)
+	hotkeys            *hotkeys.Manager // System-wide shortcuts (hotkeys setting)
+	scripts            *scripting.Engine // User Lua alert conditions and derived columns (scripts setting)
+	placement          *placement.Manager // Window geometry per monitor configuration
+	placementSave      *time.Timer        // Debounces saving the main window's placement while it is dragged
+	placementLock      sync.Mutex
+	hoveredTicker      string           // Ticker row under the cursor in the main window ("" = none)
+	hoveredTickerLock  sync.Mutex
 	enabledTickers     []string
 	shuttingDown       bool
 	shutdownLock       sync.RWMutex
 	debugPrint         func(string, string)
+	readOnly           bool // Browse-only: scheduler, coordinator and writer are disabled
+	simulated          bool // Running on a simulated clock (--simulate-clock): no fetch history or sync with real days
+	collectorLock      *database.CollectorLock      // This instance's claim on the data directory (nil in read-only mode)
+	dataDirectoryInUse *database.DataDirectoryInUseError // Another collector held the data directory at startup (forced read-only)
+	collectionPaused   bool // Collection paused from the tray (scheduler stopped until resumed)
+	collectorLockLost  *database.CollectorLockInfo // Instance that took the data directory over while running (collection stopped)
+	quitRequested      bool // Quit chosen from the tray - lets the main window close instead of hiding
+	shutdownComplete   bool // Soft shutdown finished - the next quit request goes through
+	shutdownCoordinator *shutdown.Coordinator      // Runs the shutdown steps once within ShutdownFinalFlushTimeout
+	shutdownWindow     *application.WebviewWindow // Shutdown splash (nil when not shown)
 	chartWindows       map[string]*application.WebviewWindow // Track open chart windows
+	chartWindowStates  map[string]*chartWindowState          // Date and per-chart options of open chart windows (for workspaces)
 	chartWindowsLock   sync.RWMutex
 	mainWindow         *application.WebviewWindow // Main application window
+	logWindow          *application.WebviewWindow // Log viewer window (nil when closed)
+	logWindowLock      sync.Mutex
+	keyValidation      *api.KeyValidation // Result of the last ValidateAPIKey (used by CompleteSetup)
+	keyValidationKey   string             // API key keyValidation belongs to
+	keyValidationLock  sync.Mutex
 }
 
 // NewApp creates a new App instance
@@ -83,12 +140,67 @@ func NewApp() *App {
 		nowSystem.Format("2006-01-02 15:04:05 MST"), 
 		nowMarket.Format("2006-01-02 15:04:05 MST"))
 
-	// Initialize database components
-	dataWriter := database.NewDataWriter(settings, debugPrint)
+	// Panics caught in background goroutines write a crash dump (settings included, minus API keys)
+	crash.Configure(appVersion, settingsManager.GetSettings, func(report crash.ReportInfo) {
+		emitEvent("crash:reported", report)
+	})
+
+	// Read-only mode serves existing data directories for browsing (e.g. a synced/SMB copy)
+	// without collecting or writing, so a second install can't double-collect
+	readOnly := settings.ReadOnlyMode || launchReadOnly
+	_, simulated := utils.GetClock().(*utils.SimulatedClock)
+
+	// Only one instance may collect into a data directory (e.g. a double launch would corrupt the day files);
+	// when another live collector holds it, this one opens read-only and the UI explains why
+	var collectorLock *database.CollectorLock
+	var dataDirectoryInUse *database.DataDirectoryInUseError
+	if !readOnly {
+		lock, err := database.AcquireCollectorLock(settings.GetDataDirectory(), debugPrint)
+		if errors.As(err, &dataDirectoryInUse) {
+			readOnly = true
+			log.Printf("Warning: %v - starting read-only", err)
+			utils.Logf("[system] %v - starting read-only", err)
+		} else if err != nil {
+			log.Printf("Warning: Collector lock not taken: %v", err)
+		}
+		collectorLock = lock
+	}
+	if readOnly {
+		log.Printf("Read-only mode: scheduler, coordinator and writer are disabled")
+		utils.Logf("[system] Read-only mode: scheduler, coordinator and writer are disabled")
+	}
+
+	// Initialize database components (no writer in read-only mode)
+	var dataWriter *database.DataWriter
+	if !readOnly {
+		dataWriter = database.NewDataWriter(settings, debugPrint)
+		if err := dataWriter.SetTimeSeriesSink(settings.TimeSeriesSink); err != nil {
+			log.Printf("Warning: Time-series sink disabled: %v", err)
+		}
+	}
 	dataLoader := database.NewDataLoader(settings, debugPrint)
 
+	// Load the database encryption key if one exists (needed to read encrypted days even if encryption is now off)
+	if key, err := keychain.Get(config.DatabaseEncryptionKeyAccount); err == nil {
+		dataLoader.SetEncryptionKey(key)
+	} else if settings.EncryptCompletedDays {
+		log.Printf("Warning: Database encryption enabled but key not available: %v", err)
+	}
+
+	// Register user-defined endpoints before anything builds a query plan
+	if err := api.SetCustomEndpoints(settings.CustomEndpoints); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
 	// Initialize API client
 	apiClient := api.NewClient(settings.APITKey, debugPrint)
+	if dataWriter != nil {
+		// Record mode: raw responses are kept next to each day's database for ReplayRecordedDay
+		dataWriter.RawRecorder().SetEnabled(settings.RecordRawResponses)
+		apiClient.SetResponseRecorder(dataWriter.RawRecorder())
+	}
+	apiClient.SetAdditionalAPIKeys(settings.AdditionalAPIKeys)
+	apiClient.SetRequestPolicies(settings.GetRequestPolicies())
 
 	// Initialize query system
 	querySystem := api.NewQuerySystem(settings, settings.APITKey, apiClient, debugPrint)
@@ -119,8 +231,19 @@ func NewApp() *App {
 		chartTracker:    chartTracker,
 		enabledTickers:  enabledTickers,
 		debugPrint:      debugPrint,
+		readOnly:        readOnly,
+		simulated:       simulated,
+		collectorLock:   collectorLock,
+		dataDirectoryInUse: dataDirectoryInUse,
 		chartWindows:     make(map[string]*application.WebviewWindow),
+		chartWindowStates: make(map[string]*chartWindowState),
+		placement:        placement.NewManager(currentMonitors),
+		shutdownCoordinator: shutdown.NewCoordinator(time.Duration(config.ShutdownFinalFlushTimeout*float64(time.Second)), config.ShutdownAbortGraceSec*time.Second, debugPrint),
 	}
+	app.shutdownCtx, app.cancelShutdown = context.WithCancel(context.Background())
+
+	// Losing the data directory to another instance stops collection here
+	collectorLock.SetOnLost(app.onCollectorLockLost)
 
 	// Initialize data collection coordinator (with reference to app)
 	getShuttingDown := func() bool {
@@ -152,6 +275,52 @@ func NewApp() *App {
 	)
 	app.coordinator = coordinator
 
+	// Warn (and optionally correct market time) when the system clock disagrees with API timestamps
+	clockSkew := coordinator.GetClockSkewMonitor()
+	clockSkew.SetCorrection(settings.CorrectClockSkew)
+	clockSkew.SetOnWarning(app.onClockSkewWarning)
+
+	// Limit background tickers to chart endpoints (and tell the user) when the quota won't last until the close
+	coordinator.GetQuotaSaver().SetEnabled(settings.QuotaSaverEnabled())
+	coordinator.GetQuotaSaver().SetOnActivate(app.onQuotaSaverActivated)
+
+	// Flag rows (and warn) when spot disagrees with the optional secondary quote source
+	coordinator.GetSpotChecker().SetSettings(settings.SpotCheck)
+	coordinator.GetSpotChecker().SetOnDivergence(app.onSpotDivergence)
+
+	// Live alert rules, announced with their sounds (live_alerts)
+	coordinator.GetAlertEngine().SetRules(settings.LiveAlerts.AllRules())
+	coordinator.GetAlertEngine().SetOnTrigger(app.onAlertTriggered)
+
+	// Custom data processors (processors.enabled) add columns and events to collected rows
+	if err := coordinator.GetProcessorRunner().SetEnabled(settings.Processors.Enabled, settings.Processors.Tickers); err != nil {
+		app.debugPrint(fmt.Sprintf("WARNING: %v", err), "error")
+	}
+	coordinator.GetProcessorRunner().SetOnEvent(app.onProcessorEvent)
+
+	// User Lua scripts (scripts.enabled): alert conditions and derived columns from <config dir>/scripts
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	app.scripts = scripting.NewEngine(filepath.Join(configDir, config.ScriptsDirName), debugPrint)
+	app.applyScriptSettings(settings.Scripts)
+
+	// Session event timeline: spot crossing zero_gamma and the major levels
+	coordinator.GetLevelCrossTracker().SetOnCross(app.onLevelCross)
+
+	// Fill a failed endpoint's fields from its last good response (fetch_fallback_max_age_sec)
+	coordinator.GetFetchFallback().SetMaxAgeSec(settings.GetFetchFallbackMaxAgeSec())
+
+	// A ticker due while its previous batch is still fetching is skipped or queued (batch_overlap_policy)
+	coordinator.SetBatchOverlapPolicy(settings.GetBatchOverlapPolicy())
+	coordinator.GetBatchCoalescer().SetWindow(time.Duration(settings.GetBatchCoalesceWindowMs()) * time.Millisecond)
+
+	// Optionally export collection traces to an OpenTelemetry collector
+	if err := coordinator.GetTracer().Configure(settings.Tracing, debugPrint); err != nil {
+		log.Printf("Warning: Trace export disabled: %v", err)
+	}
+
 	// After-hours collection is NOT allowed - only poll during market hours
 	allowAfterHours := false
 	
@@ -161,8 +330,9 @@ func NewApp() *App {
 		getOpenCharts,
 		func(ticker string) {
 			// Callback when a single ticker is ready to fetch
+			// Timers firing within batch_coalesce_window_ms of each other share one batch
 			log.Printf("[FETCH-CALLBACK] ===== onTickerReady called for: %s =====", ticker)
-			coordinator.ProcessTickerBatch([]string{ticker})
+			coordinator.GetBatchCoalescer().Submit(ticker)
 		},
 		debugPrint,
 		allowAfterHours,
@@ -170,6 +340,86 @@ func NewApp() *App {
 	perTickerScheduler.UpdateTickers(enabledTickers)
 	app.perTickerScheduler = perTickerScheduler
 
+	perTickerScheduler.SetStartupStagger(settings.GetStartupStaggerSec())
+
+	// Resume per-ticker intervals from the previous run instead of fetching everything at once
+	// (not on a simulated clock: its fetch times would be compared with the real clock on the next start)
+	if historyPath, err := scheduler.GetFetchHistoryPath(); err == nil && !simulated {
+		fetchHistory, err := scheduler.LoadFetchHistory(historyPath)
+		if err != nil {
+			debugPrint(fmt.Sprintf("Fetch history: %v", err), "error")
+		}
+		perTickerScheduler.SetFetchHistory(fetchHistory)
+	}
+
+	// Initialize market close watcher (end-of-day processing)
+	marketCloseWatcher := scheduler.NewMarketCloseWatcher(debugPrint)
+	marketCloseWatcher.OnMarketClose(app.persistDailyStats)
+	app.marketCloseWatcher = marketCloseWatcher
+
+	// Tell the UI when the market date rolls over, with the chart windows still pinned to the previous date
+	app.marketDateWatcher = scheduler.NewMarketDateWatcher(debugPrint)
+	app.marketDateWatcher.OnMarketDateChange(app.onMarketDateChanged)
+
+	// Session warm start: check the API and create today's databases before the open instead of in the first fetches
+	app.preOpenWatcher = scheduler.NewPreOpenWatcher(app.verifyAPIBeforeOpen, app.prepareMarketDate, app.warmMarketOpen, debugPrint)
+	app.preOpenWatcher.OnStatus(func(status scheduler.PreOpenStatus) {
+		emitEvent("preopen:status", status)
+	})
+
+	// Initialize end-of-day report generator (runs after daily stats)
+	app.endOfDayReporter = reports.NewEndOfDayReporter(
+		dataLoader,
+		settingsManager.GetSettings,
+		coordinator.GetAPIErrorCounts,
+		debugPrint,
+	)
+	marketCloseWatcher.OnMarketClose(func(marketDate time.Time) {
+		app.endOfDayReporter.Run(marketDate, getEnabledTickers(settingsManager.GetSettings()))
+	})
+
+	// Encrypt a day's databases once the market date has rolled over: after-hours rows still go to the day until
+	// then, and by then stats and the report have read it
+	app.marketDateWatcher.OnMarketDateChange(func(previous, current time.Time) {
+		app.encryptCompletedDay(previous)
+	})
+
+	// Keep chart loads within the memory budget instead of letting the webview push the process into swap
+	app.memoryMonitor = metrics.NewMemoryMonitor(settings.GetMemoryBudgetMB(), debugPrint)
+	app.memoryMonitor.OnPressure(func(status metrics.MemoryStatus) {
+		dataLoader.ShrinkCaches(config.MemoryRelieveCacheSize)
+		emitEvent("memory:warning", status)
+	})
+
+	// Warn when the data volume runs low; below the critical threshold store essential columns only (no profiles)
+	// instead of failing mid-session
+	app.diskMonitor = metrics.NewDiskMonitor(settings.GetDataDirectory(), settings.GetDiskSpaceSettings(), debugPrint)
+	app.diskMonitor.OnLevelChange(func(status metrics.DiskStatus) {
+		if app.dataWriter != nil {
+			app.dataWriter.SetEssentialOnly(status.Level == metrics.DiskCritical)
+		}
+		emitEvent("disk:space", status)
+	})
+
+	// Push the completed (possibly encrypted) day to the sync destination
+	app.syncer = datasync.NewSyncer(settingsManager.GetSettings, debugPrint)
+	if dataWriter != nil {
+		// Push a checkpointed copy, not files the writer (after-hours rows) or readers may hold open
+		app.syncer.SetDayCopier(func(date time.Time, dst string) ([]string, error) {
+			dataLoader.ReleaseDay(date)
+			return dataWriter.CopyDay(date, dst)
+		})
+	}
+	marketCloseWatcher.OnMarketClose(func(marketDate time.Time) {
+		syncSettings := settingsManager.GetSettings().Sync
+		if !syncSettings.Enabled || !syncSettings.PushAfterClose || app.simulated {
+			return
+		}
+		if _, err := app.syncer.PushDay(marketDate); err != nil {
+			debugPrint(fmt.Sprintf("Sync: Push after close failed: %v", err), "error")
+		}
+	})
+
 	return app
 }
 
@@ -189,6 +439,47 @@ func getEnabledTickers(settings *config.Settings) []string {
 	return []string{}
 }
 
+// launchReadOnly is set from main.go when the app is started with --read-only
+var launchReadOnly bool
+
+// SetLaunchReadOnly forces read-only mode for this launch (called from main.go before NewApp)
+func SetLaunchReadOnly(readOnly bool) {
+	launchReadOnly = readOnly
+}
+
+// IsReadOnly reports whether the app is running in read-only (browse-only) mode
+func (a *App) IsReadOnly() bool {
+	return a.readOnly
+}
+
+// DataDirectoryLockStatus tells the UI whether another collector forced this instance read-only
+type DataDirectoryLockStatus struct {
+	ReadOnly  bool                        `json:"read_only"`
+	Directory string                      `json:"directory"`
+	Holder    *database.CollectorLockInfo `json:"holder"` // The other collector (nil = this instance holds the lock or never needed it)
+	Lost      bool                        `json:"lost"`   // The other collector took the lock over while this one ran (collection stopped)
+}
+
+// GetDataDirectoryLockStatus reports whether startup found another live collector on the data directory
+func (a *App) GetDataDirectoryLockStatus() DataDirectoryLockStatus {
+	status := DataDirectoryLockStatus{ReadOnly: a.readOnly, Directory: a.settingsManager.GetSettings().GetDataDirectory()}
+	if a.dataDirectoryInUse != nil {
+		holder := a.dataDirectoryInUse.Holder
+		status.Holder = &holder
+	}
+	a.shutdownLock.Lock()
+	if a.collectorLockLost != nil {
+		holder := *a.collectorLockLost
+		status.Holder = &holder
+		status.Lost = true
+	}
+	a.shutdownLock.Unlock()
+	return status
+}
+
+// errReadOnly is returned by bindings that write data when running in read-only mode
+var errReadOnly = errors.New("not available in read-only mode")
+
 // ServiceStartup is called when the app starts (implements ServiceStartup interface)
 func (a *App) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
 	a.ctx = ctx
@@ -226,10 +517,6 @@ func (a *App) ServiceStartup(ctx context.Context, options application.ServiceOpt
 
 	// Create today's data directory (like Python version does)
 	settings := a.settingsManager.GetSettings()
-	dataDir := settings.DataDirectory
-	if dataDir == "" {
-		dataDir = "Tickers"
-	}
 	
 	// Get today's date (handle weekends like Python version)
 	today := time.Now()
@@ -240,21 +527,46 @@ func (a *App) ServiceStartup(ctx context.Context, options application.ServiceOpt
 		today = today.AddDate(0, 0, -2) // Use Friday
 	}
 	
-	dateStr := today.Format("01.02.2006")
-	dataDirPath := fmt.Sprintf("%s %s", dataDir, dateStr)
+	dataDirPath := settings.DayDirectory(today)
 	
-	// Create directory if it doesn't exist
-	if err := os.MkdirAll(dataDirPath, 0755); err != nil {
+	// Create directory if it doesn't exist (read-only mode never creates data directories)
+	if a.readOnly {
+		a.debugPrint("Read-only mode: browsing existing data only, collection disabled", "system")
+	} else if err := os.MkdirAll(dataDirPath, 0755); err != nil {
 		utils.Logf("WARNING: Failed to create data directory %s: %v", dataDirPath, err)
 	} else {
 		utils.Logf("Data directory ready: %s", dataDirPath)
 		a.debugPrint(fmt.Sprintf("Data directory: %s", dataDirPath), "system")
 	}
 
+	// Memory monitoring runs in read-only mode too (chart loads are the main consumer)
+	a.memoryMonitor.Start()
+
+	// Chart windows follow the market date rollover in read-only mode too
+	a.marketDateWatcher.Start()
+
+	// Disk space only matters when collecting
+	if a.dataWriter != nil {
+		// A rollover missed while the app wasn't running leaves the previous trading day to encrypt
+		go func() {
+			defer crash.Recover("encrypt completed day")
+			a.encryptCompletedDay(utils.GetLastTradingDay(a.marketDateWatcher.Current().AddDate(0, 0, -1)))
+		}()
+		a.diskMonitor.Start()
+	}
+
+	// Open today's databases in the background so the main table's first render doesn't cold-open them one by one
+	go a.warmTodayDatabases()
+
 	// Start per-ticker scheduler to begin data collection (non-blocking)
 	go func() {
+		defer crash.Recover("startup")
 		// Small delay to ensure window is fully initialized
 		time.Sleep(500 * time.Millisecond)
+		if a.readOnly {
+			utils.Logf("Read-only mode: not starting scheduler, health check or market close processing")
+			return
+		}
 		if a.perTickerScheduler != nil {
 			// Check if scheduler is already running
 			if a.perTickerScheduler.IsRunning() {
@@ -279,6 +591,53 @@ func (a *App) ServiceStartup(ctx context.Context, options application.ServiceOpt
 				utils.Logf("Health check system started")
 			}
 			
+			// Start market close watcher (end-of-day processing)
+			if a.marketCloseWatcher != nil {
+				a.marketCloseWatcher.Start()
+			}
+			
+			// Pre-open phase (the writer is nil in read-only mode, which never gets here)
+			if a.preOpenWatcher != nil && a.dataWriter != nil {
+				a.preOpenWatcher.Start()
+			}
+			
+			// Publish rate limit telemetry to the main window
+			go a.emitRateLimitStatus()
+			
+			// Unregister tickers whose chart windows are gone
+			go a.reconcileChartWindows()
+			
+			// Compact previous days' databases off-hours
+			go a.runDatabaseMaintenance()
+			
+			// Capture chart images at the configured market times
+			a.snapshotScheduler = scheduler.NewSnapshotScheduler(func() config.ChartSnapshotSettings {
+				return a.settingsManager.GetSettings().ChartSnapshots
+			}, a.captureChartSnapshots, a.debugPrint)
+			a.snapshotScheduler.Start()
+			
+			// Register system-wide shortcuts (open chart under cursor, pause collection, snapshot all)
+			a.hotkeys = hotkeys.NewManager(a.runHotkeyAction, a.debugPrint)
+			a.hotkeys.Apply(settings.Hotkeys)
+			
+			// Drop to collection intervals while nobody is looking at the app
+			a.activityMonitor = scheduler.NewActivityMonitor(settings.GetIdleAfterMinutes(), a.anyWindowFocused, a.setIdle, a.debugPrint)
+			a.activityMonitor.Start()
+			
+			// Save battery: longer intervals, fewer flushes and no profiler while on battery (eco_mode)
+			a.ecoMonitor = scheduler.NewEcoMonitor(settings.GetEcoMode(), a.setEco, a.debugPrint)
+			a.ecoMonitor.Start()
+			
+			// Pull days collected on other machines (runs in background, doesn't block collection)
+			if syncSettings := settings.Sync; syncSettings.Enabled && syncSettings.PullOnStartup && !a.simulated {
+				go func() {
+					time.Sleep(time.Duration(config.SyncStartupDelaySec) * time.Second)
+					if _, err := a.syncer.PullMissing(); err != nil {
+						a.debugPrint(fmt.Sprintf("Sync: Pull on startup failed: %v", err), "error")
+					}
+				}()
+			}
+			
 			// Check API key
 			apiKey := settings.APITKey
 			if apiKey == "" {
@@ -309,7 +668,7 @@ func (a *App) ServiceStartup(ctx context.Context, options application.ServiceOpt
 	}
 	utils.Logf("Window dimensions: %dx%d (saved: %dx%d)", windowWidth, windowHeight, currentSettings.WindowWidth, currentSettings.WindowHeight)
 	
-	mainWindow := createWindowFromApp(a.appRef, application.WebviewWindowOptions{
+	mainOptions := application.WebviewWindowOptions{
 		Title:    "Market Terminal Gexbot",
 		Width:    windowWidth,
 		Height:   windowHeight,
@@ -317,7 +676,15 @@ func (a *App) ServiceStartup(ctx context.Context, options application.ServiceOpt
 		MinHeight: 400,
 		URL:      "/index.html", // Use embedded filesystem
 		BackgroundColour: application.NewRGB(30, 30, 30),
-	})
+	}
+	// Put the window where it was on this monitor configuration (clamped onto a screen); centered otherwise
+	if rect, ok := a.placement.Restore(currentSettings.WindowPlacements); ok {
+		mainOptions.X, mainOptions.Y = rect.X, rect.Y
+		mainOptions.Width, mainOptions.Height = rect.Width, rect.Height
+		mainOptions.InitialPosition = application.WindowXY
+		utils.Logf("Window placement restored for this monitor configuration: %dx%d at %d,%d", rect.Width, rect.Height, rect.X, rect.Y)
+	}
+	mainWindow := createWindowFromApp(a.appRef, mainOptions)
 	
 	if mainWindow == nil {
 		utils.Logf("ERROR: Main window creation failed")
@@ -329,6 +696,42 @@ func (a *App) ServiceStartup(ctx context.Context, options application.ServiceOpt
 		a.debugPrint("Main window created - backend ready", "system")
 		// Note: Frontend will initialize automatically since window is created after backend is ready
 		// The frontend has a timeout fallback that will trigger initialization
+
+		// Close to tray: hide the main window instead of quitting (quit from the tray menu)
+		mainWindow.RegisterHook(events.Common.WindowClosing, func(e *application.WindowEvent) {
+			a.shutdownLock.RLock()
+			quitting := a.quitRequested || a.shuttingDown
+			a.shutdownLock.RUnlock()
+			if quitting || !a.settingsManager.GetSettings().CloseToTray {
+				return
+			}
+			e.Cancel()
+			mainWindow.Hide()
+		})
+		// Moves don't resize, so the frontend's size reports miss them; save the placement once dragging stops
+		mainWindow.OnWindowEvent(events.Common.WindowDidMove, func(e *application.WindowEvent) {
+			a.scheduleMainPlacementSave()
+		})
+		mainWindow.OnWindowEvent(events.Common.WindowFocus, func(e *application.WindowEvent) {
+			a.recordWindowActivity()
+		})
+	}
+
+	// Reopen the chart windows of the last loaded workspace
+	if name := a.settingsManager.GetSettings().LastWorkspace; name != "" && a.mainWindow != nil {
+		go func() {
+			if err := a.loadWorkspace(name, false); err != nil {
+				a.debugPrint(fmt.Sprintf("Workspace: Failed to restore %q: %v", name, err), "error")
+			}
+		}()
+	}
+
+	// --open-chart=TICKER or an mgt:// link on this launch's command line
+	if a.mainWindow != nil {
+		a.handleLaunchArgs(os.Args[1:])
+	}
+	if !a.readOnly {
+		go a.registerURLScheme()
 	}
 
 	utils.Logf("ServiceStartup completed successfully")
@@ -336,14 +739,104 @@ func (a *App) ServiceStartup(ctx context.Context, options application.ServiceOpt
 }
 
 // ServiceShutdown is called when the app shuts down (implements ServiceShutdown interface)
+// Normally the soft shutdown started by shouldQuit has already run and this returns at once; when the app
+// is torn down without a quit request (e.g. the OS session ends) the same steps run here, without the splash
 func (a *App) ServiceShutdown() error {
-	a.debugPrint("Shutting down...", "system")
+	a.runShutdown()
+	return nil
+}
+
+// shouldQuit is the app's ShouldQuit hook: the first quit request starts the soft shutdown (splash window
+// with flush progress) and is refused; the app quits for real once the shutdown steps have finished
+func (a *App) shouldQuit() bool {
+	a.shutdownLock.Lock()
+	if a.shutdownComplete {
+		a.shutdownLock.Unlock()
+		return true
+	}
+	starting := !a.shuttingDown
+	a.shuttingDown = true
+	a.quitRequested = true // Lets the main window close instead of hiding to the tray
+	a.shutdownLock.Unlock()
+	if !starting {
+		return false // Already shutting down - the app quits when it is done
+	}
+
+	a.debugPrint("Quit requested - starting soft shutdown", "system")
+	if a.dataWriter != nil && (a.dataWriter.PendingTickerCount() > 0 || a.writeQueue.GetPendingCount() > 0) {
+		a.openShutdownWindow()
+	}
+	go func() {
+		defer crash.Recover("soft shutdown")
+		a.runShutdown()
+		a.shutdownLock.Lock()
+		a.shutdownComplete = true
+		a.shutdownLock.Unlock()
+		if quitter, ok := a.appRef.(interface{ Quit() }); ok {
+			quitter.Quit()
+		}
+	}()
+	return false
+}
+
+// runShutdown stops collection, flushes pending writes and closes the databases within the
+// ShutdownFinalFlushTimeout budget; runs once (later calls wait for the first to finish)
+func (a *App) runShutdown() {
+	a.shutdownCoordinator.Run([]shutdown.Step{
+		{Name: "Stopping data collection", Run: func(ctx context.Context, report func(int, int)) error {
+			a.stopServices()
+			return nil
+		}},
+		{Name: "Flushing pending writes", Always: true, Run: func(ctx context.Context, report func(int, int)) error {
+			// Close database connections (this will flush pending writes and checkpoint WAL files)
+			// This ensures .db-wal and .db-shm files are cleaned up on shutdown
+			if a.dataWriter == nil {
+				return nil
+			}
+			a.writeQueue.WritePending()
+			if err := a.dataWriter.CloseWithProgress(ctx, report); err != nil {
+				return fmt.Errorf("error closing data writer: %w", err)
+			}
+			a.debugPrint("ServiceShutdown: Data writer closed successfully", "system")
+			return nil
+		}},
+		{Name: "Closing databases", Always: true, Run: func(ctx context.Context, report func(int, int)) error {
+			if a.dataLoader == nil {
+				return nil
+			}
+			if err := a.dataLoader.Close(); err != nil {
+				return fmt.Errorf("error closing data loader: %w", err)
+			}
+			a.debugPrint("ServiceShutdown: Data loader closed successfully", "system")
+			return nil
+		}},
+		{Name: "Finishing", Always: true, Run: func(ctx context.Context, report func(int, int)) error {
+			// Nothing writes the data directory any more
+			a.collectorLock.Release()
+			// Send the last spans (including the final flush) to the trace collector
+			if a.coordinator != nil {
+				a.coordinator.GetTracer().Stop()
+			}
+			// Close API client
+			if a.apiClient != nil {
+				a.apiClient.Close()
+			}
+			return nil
+		}},
+	})
+	a.closeShutdownWindow()
+}
 
+// stopServices is the first shutdown step: everything that collects, schedules or opens windows stops
+func (a *App) stopServices() {
 	// Set shutting down flag
 	a.shutdownLock.Lock()
 	a.shuttingDown = true
 	a.shutdownLock.Unlock()
 
+	// Cancel in-flight chart loads and API fetches so they don't hold up the steps below
+	a.cancelShutdown()
+
 	// Close all chart windows first to prevent WebView2 cleanup errors
 	a.chartWindowsLock.Lock()
 	chartWindowCount := len(a.chartWindows)
@@ -354,6 +847,7 @@ func (a *App) ServiceShutdown() error {
 		}
 	}
 	a.chartWindows = make(map[string]*application.WebviewWindow)
+	a.chartWindowStates = make(map[string]*chartWindowState)
 	a.chartWindowsLock.Unlock()
 	if chartWindowCount > 0 {
 		a.debugPrint(fmt.Sprintf("ServiceShutdown: Closed %d chart window(s)", chartWindowCount), "system")
@@ -364,36 +858,102 @@ func (a *App) ServiceShutdown() error {
 		a.healthCheck.Stop()
 	}
 	
+	// Stop market close watcher
+	if a.marketCloseWatcher != nil {
+		a.marketCloseWatcher.Stop()
+	}
+	if a.preOpenWatcher != nil {
+		a.preOpenWatcher.Stop()
+	}
+	
 	// Stop per-ticker scheduler
 	if a.perTickerScheduler != nil {
 		a.perTickerScheduler.Stop()
 	}
 
-	// Close database connections (this will flush pending writes and checkpoint WAL files)
-	// This ensures .db-wal and .db-shm files are cleaned up on shutdown
-	a.debugPrint("ServiceShutdown: Closing database connections and flushing pending writes", "system")
-	if a.dataWriter != nil {
-		if err := a.dataWriter.Close(); err != nil {
-			a.debugPrint(fmt.Sprintf("ServiceShutdown: Warning - error closing data writer: %v", err), "error")
-		} else {
-			a.debugPrint("ServiceShutdown: Data writer closed successfully", "system")
-		}
+	// Stop memory and disk monitors
+	if a.memoryMonitor != nil {
+		a.memoryMonitor.Stop()
 	}
-	if a.dataLoader != nil {
-		if err := a.dataLoader.Close(); err != nil {
-			a.debugPrint(fmt.Sprintf("ServiceShutdown: Warning - error closing data loader: %v", err), "error")
-		} else {
-			a.debugPrint("ServiceShutdown: Data loader closed successfully", "system")
-		}
+	if a.diskMonitor != nil {
+		a.diskMonitor.Stop()
+	}
+	if a.marketDateWatcher != nil {
+		a.marketDateWatcher.Stop()
 	}
-	a.debugPrint("ServiceShutdown: Database cleanup completed", "system")
 
-	// Close API client
-	if a.apiClient != nil {
-		a.apiClient.Close()
+	// Stop idle detection
+	if a.ecoMonitor != nil {
+		a.ecoMonitor.Stop()
+	}
+	if a.activityMonitor != nil {
+		a.activityMonitor.Stop()
 	}
 
-	return nil
+	// Stop scheduled chart snapshots
+	if a.snapshotScheduler != nil {
+		a.snapshotScheduler.Stop()
+	}
+
+	// Release system-wide shortcuts
+	if a.hotkeys != nil {
+		a.hotkeys.Stop()
+	}
+
+	// Drop a pending placement save (the window is going away)
+	a.placementLock.Lock()
+	if a.placementSave != nil {
+		a.placementSave.Stop()
+	}
+	a.placementLock.Unlock()
+
+	// Stop coordinator fetch workers (in-flight fetches finish first)
+	if a.coordinator != nil {
+		a.coordinator.Stop()
+	}
+}
+
+// GetShutdownProgress returns how far the shutdown has got (polled by the shutdown splash window)
+func (a *App) GetShutdownProgress() shutdown.Progress {
+	return a.shutdownCoordinator.Progress()
+}
+
+// openShutdownWindow hides the app's windows behind a small splash showing the final flush's progress
+func (a *App) openShutdownWindow() {
+	if a.appRef == nil {
+		return
+	}
+	window := createWindowFromApp(a.appRef, application.WebviewWindowOptions{
+		Name:             "shutdown",
+		Title:            "Shutting down",
+		Width:            360,
+		Height:           140,
+		URL:              "/shutdown.html",
+		Frameless:        true,
+		AlwaysOnTop:      true,
+		DisableResize:    true,
+		BackgroundColour: application.NewRGB(30, 30, 30),
+	})
+	if window == nil {
+		return
+	}
+	if a.mainWindow != nil {
+		a.mainWindow.Hide()
+	}
+	a.shutdownLock.Lock()
+	a.shutdownWindow = window
+	a.shutdownLock.Unlock()
+}
+
+// closeShutdownWindow closes the splash opened by openShutdownWindow, if any
+func (a *App) closeShutdownWindow() {
+	a.shutdownLock.Lock()
+	window := a.shutdownWindow
+	a.shutdownWindow = nil
+	a.shutdownLock.Unlock()
+	if window != nil {
+		window.Close()
+	}
 }
 
 // Greet returns a greeting message (for testing)
@@ -403,9 +963,12 @@ func (a *App) Greet(name string) string {
 
 // GetVersion returns the application version
 func (a *App) GetVersion() string {
-	return "1.0.0 (Go/Wails)"
+	return appVersion
 }
 
+// appVersion is reported by GetVersion and recorded in config bundles and crash dumps
+const appVersion = "1.0.0 (Go/Wails)"
+
 // ResizeMainWindow resizes the main window to the specified dimensions
 func (a *App) ResizeMainWindow(width, height int) {
 	if a.mainWindow != nil {
@@ -414,14 +977,20 @@ func (a *App) ResizeMainWindow(width, height int) {
 	}
 }
 
-// SaveWindowSize saves the current window dimensions to settings
+// SaveWindowSize saves the current window dimensions to settings, together with the window's position and
+// screen for the current monitor configuration
 // Uses lightweight SaveWindowDimensions to avoid full settings reload
 func (a *App) SaveWindowSize(width, height int) error {
 	if width < 600 || height < 400 {
 		return nil // Don't save invalid sizes
 	}
 	
-	if err := a.settingsManager.SaveWindowDimensions(width, height); err != nil {
+	placements := a.settingsManager.GetSettings().WindowPlacements
+	if a.mainWindow != nil {
+		x, y := a.mainWindow.Position()
+		placements = a.placement.Record(placements, placement.Rect{X: x, Y: y, Width: width, Height: height})
+	}
+	if err := a.settingsManager.SaveWindowDimensions(width, height, placements); err != nil {
 		a.debugPrint(fmt.Sprintf("Failed to save window size: %v", err), "error")
 		return err
 	}
@@ -429,13 +998,37 @@ func (a *App) SaveWindowSize(width, height int) error {
 	return nil
 }
 
+// scheduleMainPlacementSave saves the main window's placement once it has stopped moving for a moment
+func (a *App) scheduleMainPlacementSave() {
+	a.placementLock.Lock()
+	defer a.placementLock.Unlock()
+	if a.placementSave != nil {
+		a.placementSave.Stop()
+	}
+	a.placementSave = time.AfterFunc(config.WindowPlacementDelayMs*time.Millisecond, func() {
+		if a.mainWindow == nil {
+			return
+		}
+		width, height := a.mainWindow.Size()
+		a.SaveWindowSize(width, height)
+	})
+}
+
+// GetMonitors returns the attached screens and the key under which window placements are saved for them
+func (a *App) GetMonitors() map[string]interface{} {
+	monitors := a.placement.Monitors()
+	if monitors == nil {
+		monitors = []placement.Monitor{}
+	}
+	return map[string]interface{}{
+		"monitors":      monitors,
+		"configuration": placement.ConfigurationKey(monitors),
+	}
+}
+
 // CheckFirstRun checks if this is the first run (no API key configured)
 func (a *App) CheckFirstRun() bool {
-	configPath, err := config.GetConfigPath()
-	if err != nil {
-		log.Printf("CheckFirstRun: Could not get config path: %v", err)
-		return true // Assume first run if we can't get config path
-	}
+	configPath := a.settingsManager.GetConfigPath() // The running profile's file
 	
 	// Check if config file exists
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
@@ -461,6 +1054,24 @@ func (a *App) CheckFirstRun() bool {
 	return apiKey == ""
 }
 
+// ValidateAPIKey probes one endpoint per subscription tier and returns the tiers the key actually has
+// (the setup wizard pre-selects these; CompleteSetup drops selected tiers the key doesn't have)
+func (a *App) ValidateAPIKey(apiKey string) (*api.KeyValidation, error) {
+	apiKey = strings.TrimSpace(apiKey)
+	validation, err := api.ValidateAPIKey(apiKey, a.debugPrint)
+	if err != nil {
+		return validation, err
+	}
+
+	a.keyValidationLock.Lock()
+	a.keyValidation = validation
+	a.keyValidationKey = apiKey
+	a.keyValidationLock.Unlock()
+
+	a.debugPrint(fmt.Sprintf("ValidateAPIKey: valid=%v, tiers=%v", validation.Valid, validation.Tiers), "app")
+	return validation, nil
+}
+
 // CompleteSetup completes the first-time setup
 // apiKey: API key (will be set as environment variable or in config)
 // subscriptionTiers: List of subscription tiers (e.g., ["classic", "state"])
@@ -488,6 +1099,32 @@ func (a *App) CompleteSetup(apiKey string, subscriptionTiers []string, initialTi
 		settings.APISubscriptionTiers = []string{"classic"}
 	}
 	
+	// Drop tiers a validated key doesn't have (they would only produce 403s)
+	a.keyValidationLock.Lock()
+	validation := a.keyValidation
+	if a.keyValidationKey != strings.TrimSpace(apiKey) {
+		validation = nil
+	}
+	a.keyValidationLock.Unlock()
+	if validation != nil && validation.Valid {
+		verified := make([]string, 0, len(settings.APISubscriptionTiers))
+		for _, tier := range settings.APISubscriptionTiers {
+			for _, available := range validation.Tiers {
+				if tier == available {
+					verified = append(verified, tier)
+					break
+				}
+			}
+		}
+		if len(verified) == 0 {
+			verified = validation.Tiers
+		}
+		if len(verified) != len(settings.APISubscriptionTiers) {
+			log.Printf("CompleteSetup: Using validated subscription tiers %v (selected: %v)", verified, settings.APISubscriptionTiers)
+		}
+		settings.APISubscriptionTiers = verified
+	}
+	
 	// Initialize ticker configs if needed
 	if settings.TickerConfigs == nil {
 		settings.TickerConfigs = make(map[string]config.TickerConfig)
@@ -618,6 +1255,91 @@ func (a *App) GetSettings() *config.Settings {
 	return a.settingsManager.GetSettings()
 }
 
+// ProfileSwitchResult is returned by SwitchProfile
+type ProfileSwitchResult struct {
+	Profile         string `json:"profile"`
+	Path            string `json:"path"`
+	RestartRequired bool   `json:"restart_required"` // Running components (writer, data directory, scheduler) keep the current profile until restart
+}
+
+// GetRunningProfile returns the configuration profile this process loaded
+func (a *App) GetRunningProfile() string {
+	return a.settingsManager.GetProfile()
+}
+
+// ListProfiles returns the configuration profiles (config.yaml is "default")
+func (a *App) ListProfiles() ([]config.ProfileInfo, error) {
+	return config.ListProfiles(a.settingsManager.GetProfile())
+}
+
+// CreateProfile creates a named profile, copying the running profile's settings when copyCurrent is set
+// (otherwise it starts from the defaults)
+func (a *App) CreateProfile(name string, copyCurrent bool) (config.ProfileInfo, error) {
+	from := ""
+	if copyCurrent {
+		from = a.settingsManager.GetProfile()
+	}
+	path, err := config.CreateProfile(name, from)
+	if err != nil {
+		return config.ProfileInfo{}, err
+	}
+	a.debugPrint(fmt.Sprintf("CreateProfile: Created profile %q at %s", name, path), "app")
+	return config.ProfileInfo{Name: name, Path: path}, nil
+}
+
+// SwitchProfile makes a profile the one loaded at startup
+// Each profile has its own data directory and writer, so the switch takes effect on the next launch
+func (a *App) SwitchProfile(name string) (ProfileSwitchResult, error) {
+	if name == "" {
+		name = config.DefaultProfileName
+	}
+	if !config.ProfileExists(name) {
+		return ProfileSwitchResult{}, fmt.Errorf("profile %q does not exist", name)
+	}
+	if err := config.SetActiveProfile(name); err != nil {
+		return ProfileSwitchResult{}, err
+	}
+	path, _ := config.ProfilePath(name)
+	result := ProfileSwitchResult{
+		Profile:         name,
+		Path:            path,
+		RestartRequired: name != a.settingsManager.GetProfile(),
+	}
+	a.debugPrint(fmt.Sprintf("SwitchProfile: Active profile is now %q (restart required: %v)", name, result.RestartRequired), "app")
+	emitEvent("profile:switched", result)
+	return result, nil
+}
+
+// DeleteProfile deletes a profile's settings file (not the default, active or running profile)
+// The profile's data directory is left untouched
+func (a *App) DeleteProfile(name string) error {
+	if name == a.settingsManager.GetProfile() {
+		return fmt.Errorf("profile %q is in use by this process", name)
+	}
+	if err := config.DeleteProfile(name); err != nil {
+		return err
+	}
+	a.debugPrint(fmt.Sprintf("DeleteProfile: Deleted profile %q", name), "app")
+	return nil
+}
+
+// GetSettingsValidation returns the problems found in config.yaml when it was last loaded
+// (unknown keys, values of the wrong type and settings SaveSettings would reject), for the startup dialog
+func (a *App) GetSettingsValidation() config.SettingsValidation {
+	return a.settingsManager.Validation()
+}
+
+// GetSettingsDiff describes what saving proposed settings would change (the settings dialog's confirmation)
+func (a *App) GetSettingsDiff(proposed *config.Settings) config.SettingsDiff {
+	current := a.settingsManager.GetSettings()
+	if current == nil {
+		current = config.GetDefaultSettings()
+	}
+	diff := config.DiffSettings(current, proposed)
+	diff.SetEndpoints(coordinator.PlannedEndpoints(current), coordinator.PlannedEndpoints(proposed))
+	return diff
+}
+
 // SaveSettings saves settings
 // Note: API key is preserved from existing settings (not overwritten by frontend)
 func (a *App) SaveSettings(settings *config.Settings) error {
@@ -634,33 +1356,270 @@ func (a *App) SaveSettings(settings *config.Settings) error {
 			ticker, config.CollectionEnabled, config.Display, config.Priority, refreshRateStr), "app")
 	}
 	
-	// Preserve existing API key (frontend shouldn't send it for security)
-	currentSettings := a.settingsManager.GetSettings()
-	if settings.APITKey == "" && currentSettings.APITKey != "" {
-		settings.APITKey = currentSettings.APITKey
-		a.debugPrint(fmt.Sprintf("SaveSettings: Preserved existing API key (length: %d)", len(settings.APITKey)), "app")
+	// Reject invalid polling interval matrix before anything is written
+	if settings.PollingIntervals != nil {
+		if err := settings.PollingIntervals.Validate(); err != nil {
+			a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected invalid polling intervals: %v", err), "error")
+			return fmt.Errorf("invalid polling intervals: %w", err)
+		}
 	}
 	
-	// Save settings (API key will NOT be saved to file - only in memory)
-	if err := a.settingsManager.SaveSettings(settings); err != nil {
-		a.debugPrint(fmt.Sprintf("ERROR: SaveSettings failed: %v", err), "error")
+	// Reject invalid disk space thresholds
+	if settings.DiskSpace != nil {
+		if err := settings.DiskSpace.Validate(); err != nil {
+			a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected invalid disk space settings: %v", err), "error")
+			return fmt.Errorf("invalid disk space settings: %w", err)
+		}
+	}
+	
+	// Reject invalid WAL checkpoint thresholds
+	if settings.WALCheckpoint != nil {
+		if err := settings.WALCheckpoint.Validate(); err != nil {
+			a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected invalid WAL checkpoint settings: %v", err), "error")
+			return fmt.Errorf("invalid WAL checkpoint settings: %w", err)
+		}
+	}
+	
+	// The day directory layout only changes through --migrate-data-layout (existing days would disappear otherwise)
+	if err := settings.ValidateDataLayout(); err != nil {
 		return err
 	}
+	if current := a.settingsManager.GetSettings(); current != nil && settings.DataLayout == "" {
+		settings.DataLayout = current.DataLayout // Not sent (e.g. frontend default settings) - keep it
+	} else if current != nil && settings.GetDataLayout() != current.GetDataLayout() {
+		a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected data_layout change %q -> %q", current.GetDataLayout(), settings.GetDataLayout()), "error")
+		return fmt.Errorf("data_layout can't be changed in settings - run the app with --migrate-data-layout=%s to move existing days", settings.GetDataLayout())
+	}
 	
-	a.debugPrint("Settings saved successfully", "app")
+	// Reject an unknown batch overlap policy
+	if err := settings.ValidateBatchOverlapPolicy(); err != nil {
+		a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected invalid batch overlap policy: %v", err), "error")
+		return err
+	}
 	
-	// Reload settings to ensure consistency
-	reloadedSettings, err := a.settingsManager.LoadSettings()
-	if err != nil {
-		a.debugPrint(fmt.Sprintf("WARNING: Failed to reload settings after save: %v", err), "error")
-	} else {
-		// Preserve API key in reloaded settings (it won't be in file, but should be in memory)
-		if reloadedSettings.APITKey == "" && settings.APITKey != "" {
-			reloadedSettings.APITKey = settings.APITKey
-			a.debugPrint(fmt.Sprintf("SaveSettings: Restored API key in reloaded settings (length: %d)", len(reloadedSettings.APITKey)), "app")
+	// Reject invalid connection pool limits
+	if settings.ConnectionPool != nil {
+		if err := settings.ConnectionPool.Validate(); err != nil {
+			a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected invalid connection pool settings: %v", err), "error")
+			return fmt.Errorf("invalid connection pool settings: %w", err)
+		}
+	}
+	
+	// Reject looping ticker aliases; configs under an old name move to the new one
+	if err := settings.ValidateTickerAliases(); err != nil {
+		a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected invalid ticker aliases: %v", err), "error")
+		return fmt.Errorf("invalid ticker aliases: %w", err)
+	}
+	settings.ApplyTickerAliases()
+	
+	// Reject invalid request timeout/retry policies
+	if settings.RequestPolicies != nil {
+		if err := settings.RequestPolicies.Validate(); err != nil {
+			a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected invalid request policies: %v", err), "error")
+			return fmt.Errorf("invalid request policies: %w", err)
+		}
+	}
+	
+	// Reject invalid custom endpoint definitions
+	if err := config.ValidateCustomEndpoints(settings.CustomEndpoints, api.IsBuiltInEndpoint); err != nil {
+		a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected invalid custom endpoints: %v", err), "error")
+		return fmt.Errorf("invalid custom endpoints: %w", err)
+	}
+	
+	// Reject malformed per-ticker active windows
+	if err := config.ValidateActiveWindows(settings.TickerConfigs); err != nil {
+		a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected invalid active windows: %v", err), "error")
+		return fmt.Errorf("invalid active windows: %w", err)
+	}
+	
+	// Reject invalid chart snapshot times/format
+	if err := settings.ChartSnapshots.Validate(); err != nil {
+		a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected invalid chart snapshot settings: %v", err), "error")
+		return fmt.Errorf("invalid chart snapshots: %w", err)
+	}
+	
+	// Reject a malformed profiler address (applied on the next launch)
+	if err := settings.ValidateProfilerAddress(); err != nil {
+		a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected invalid profiler address: %v", err), "error")
+		return fmt.Errorf("invalid profiler address: %w", err)
+	}
+	
+	// Reject an unusable secondary spot source
+	if err := settings.SpotCheck.Validate(); err != nil {
+		a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected invalid spot check: %v", err), "error")
+		return fmt.Errorf("invalid spot check: %w", err)
+	}
+	
+	// Reject alert rules the engine can't evaluate or sounds that can't be played
+	if err := settings.LiveAlerts.Validate(); err != nil {
+		a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected invalid live alerts: %v", err), "error")
+		return fmt.Errorf("invalid live alerts: %w", err)
+	}
+	
+	// Reject processors that aren't registered (a typo would otherwise silently compute nothing)
+	if err := settings.Processors.Validate(); err != nil {
+		a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected invalid processors: %v", err), "error")
+		return fmt.Errorf("invalid processors: %w", err)
+	}
+	for _, name := range settings.Processors.Enabled {
+		if _, ok := processors.Lookup(name); !ok {
+			return fmt.Errorf("invalid processors: %q is not registered (available: %s)", name, strings.Join(processors.Names(), ", "))
+		}
+	}
+	
+	// Reject a script timeout outside the sandbox limits
+	if err := settings.Scripts.Validate(); err != nil {
+		a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected invalid scripts settings: %v", err), "error")
+		return fmt.Errorf("invalid scripts settings: %w", err)
+	}
+	
+	// Reject an incomplete time-series sink configuration
+	if err := settings.TimeSeriesSink.Validate(); err != nil {
+		a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected invalid time-series sink: %v", err), "error")
+		return fmt.Errorf("invalid time-series sink: %w", err)
+	}
+	
+	// Reject malformed or duplicate hotkeys
+	if err := settings.Hotkeys.Validate(); err != nil {
+		a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected hotkeys: %v", err), "error")
+		return err
+	}
+	
+	// Reject an unknown eco mode or a multiplier that would speed polling up
+	if err := settings.ValidateEcoMode(); err != nil {
+		a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected eco mode: %v", err), "error")
+		return err
+	}
+	
+	// Reject unnamed workspaces or charts without a ticker/usable size
+	for _, workspace := range settings.Workspaces {
+		if err := workspace.Validate(); err != nil {
+			a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected workspace: %v", err), "error")
+			return err
+		}
+	}
+	
+	// Reject trace export without a usable OTLP endpoint
+	if err := settings.Tracing.Validate(); err != nil {
+		a.debugPrint(fmt.Sprintf("ERROR: SaveSettings rejected invalid trace export: %v", err), "error")
+		return fmt.Errorf("invalid trace export: %w", err)
+	}
+	
+	// Preserve existing API key (frontend shouldn't send it for security)
+	currentSettings := a.settingsManager.GetSettings()
+	if settings.APITKey == "" && currentSettings.APITKey != "" {
+		settings.APITKey = currentSettings.APITKey
+		a.debugPrint(fmt.Sprintf("SaveSettings: Preserved existing API key (length: %d)", len(settings.APITKey)), "app")
+	}
+	
+	// What this save changes, for the audit log
+	diff := a.GetSettingsDiff(settings)
+	scriptsChanged := settings.Scripts != currentSettings.Scripts // Reloading resets the scripts' state
+	themeChanged := settings.Theme != currentSettings.Theme || !maps.Equal(settings.ThemeSeriesOverrides, currentSettings.ThemeSeriesOverrides)
+	
+	// Save settings (API key will NOT be saved to file - only in memory)
+	if err := a.settingsManager.SaveSettings(settings); err != nil {
+		a.debugPrint(fmt.Sprintf("ERROR: SaveSettings failed: %v", err), "error")
+		return err
+	}
+	
+	a.debugPrint("Settings saved successfully", "app")
+	utils.Logf("[settings] Applied: %s", diff.Summary())
+	
+	// Reload settings to ensure consistency
+	reloadedSettings, err := a.settingsManager.LoadSettings()
+	if err != nil {
+		a.debugPrint(fmt.Sprintf("WARNING: Failed to reload settings after save: %v", err), "error")
+	} else {
+		// Preserve API key in reloaded settings (it won't be in file, but should be in memory)
+		if reloadedSettings.APITKey == "" && settings.APITKey != "" {
+			reloadedSettings.APITKey = settings.APITKey
+			a.debugPrint(fmt.Sprintf("SaveSettings: Restored API key in reloaded settings (length: %d)", len(reloadedSettings.APITKey)), "app")
 		}
 		a.settingsManager.SetSettings(reloadedSettings)
 		
+		// Open chart windows and the settings page recolor without reopening
+		if themeChanged {
+			emitEvent("theme:changed", reloadedSettings.GetTheme())
+		}
+		
+		// Register custom endpoints (picked up by the next query plan)
+		if err := api.SetCustomEndpoints(reloadedSettings.CustomEndpoints); err != nil {
+			a.debugPrint(fmt.Sprintf("WARNING: %v", err), "error")
+		}
+		if a.querySystem != nil {
+			a.querySystem.InvalidateEndpointCache()
+		}
+		
+		// Update API key rotation (additional keys may have been added/removed)
+		if a.apiClient != nil {
+			a.apiClient.SetAdditionalAPIKeys(reloadedSettings.AdditionalAPIKeys)
+			a.apiClient.SetRequestPolicies(reloadedSettings.GetRequestPolicies())
+		}
+		
+		// Update WAL checkpoint policy, profile encoding and connection pool limits (apply from the next flush)
+		if a.dataWriter != nil {
+			a.dataWriter.SetWALCheckpointSettings(reloadedSettings.GetWALCheckpointSettings())
+			a.dataWriter.SetProfileDeltaCompression(reloadedSettings.ProfileDeltaCompression)
+			a.dataWriter.RawRecorder().SetEnabled(reloadedSettings.RecordRawResponses)
+			if err := a.dataWriter.SetTimeSeriesSink(reloadedSettings.TimeSeriesSink); err != nil {
+				a.debugPrint(fmt.Sprintf("WARNING: SaveSettings could not start time-series sink: %v", err), "error")
+			}
+			a.dataWriter.SetConnectionPoolSettings(reloadedSettings.GetConnectionPoolSettings())
+		}
+		a.dataLoader.SetConnectionPoolSettings(reloadedSettings.GetConnectionPoolSettings())
+		
+		// Update memory budget and disk space thresholds (apply from the next check)
+		if a.memoryMonitor != nil {
+			a.memoryMonitor.SetBudgetMB(reloadedSettings.GetMemoryBudgetMB())
+		}
+		if a.diskMonitor != nil {
+			a.diskMonitor.SetSettings(reloadedSettings.GetDiskSpaceSettings())
+		}
+		
+		// Update clock skew correction and trace export
+		if a.coordinator != nil {
+			a.coordinator.GetClockSkewMonitor().SetCorrection(reloadedSettings.CorrectClockSkew)
+			a.coordinator.GetQuotaSaver().SetEnabled(reloadedSettings.QuotaSaverEnabled())
+			a.coordinator.GetFetchFallback().SetMaxAgeSec(reloadedSettings.GetFetchFallbackMaxAgeSec())
+			a.coordinator.SetBatchOverlapPolicy(reloadedSettings.GetBatchOverlapPolicy())
+			a.coordinator.GetBatchCoalescer().SetWindow(time.Duration(reloadedSettings.GetBatchCoalesceWindowMs()) * time.Millisecond)
+			a.coordinator.GetSpotChecker().SetSettings(reloadedSettings.SpotCheck)
+			a.coordinator.GetAlertEngine().SetRules(reloadedSettings.LiveAlerts.AllRules())
+			if err := a.coordinator.GetProcessorRunner().SetEnabled(reloadedSettings.Processors.Enabled, reloadedSettings.Processors.Tickers); err != nil {
+				a.debugPrint(fmt.Sprintf("WARNING: SaveSettings: %v", err), "error")
+			}
+			if scriptsChanged {
+				a.applyScriptSettings(reloadedSettings.Scripts)
+			}
+			if err := a.coordinator.GetTracer().Configure(reloadedSettings.Tracing, a.debugPrint); err != nil {
+				a.debugPrint(fmt.Sprintf("WARNING: SaveSettings could not start trace export: %v", err), "error")
+			}
+		}
+		
+		// Re-register hotkeys
+		if a.hotkeys != nil {
+			a.hotkeys.Apply(reloadedSettings.Hotkeys)
+		}
+		
+		// Update idle threshold
+		if a.activityMonitor != nil {
+			a.activityMonitor.SetIdleAfterMinutes(reloadedSettings.GetIdleAfterMinutes())
+		}
+		
+		// Update eco mode (a new multiplier applies right away while eco is on)
+		if a.ecoMonitor != nil {
+			a.ecoMonitor.SetMode(reloadedSettings.GetEcoMode())
+			if a.ecoMonitor.Status().Active && a.scheduler != nil {
+				a.scheduler.SetEco(reloadedSettings.GetEcoIntervalMultiplier())
+			}
+		}
+		
+		// Update startup stagger (applies the next time collection starts)
+		if a.perTickerScheduler != nil {
+			a.perTickerScheduler.SetStartupStagger(reloadedSettings.GetStartupStaggerSec())
+		}
+		
 		// Update scheduler settings so it sees new priorities and refresh rates
 		if a.scheduler != nil {
 			a.scheduler.SetSettings(reloadedSettings)
@@ -702,14 +1661,29 @@ func (a *App) SaveSettings(settings *config.Settings) error {
 		
 		if tickersChanged {
 			a.debugPrint(fmt.Sprintf("Enabled tickers changed: %v -> %v", a.enabledTickers, newEnabledTickers), "app")
+			// Tickers being disabled are drained (fetch finishes, writes flushed) instead of dropped
+			newTickersSet := make(map[string]bool, len(newEnabledTickers))
+			for _, ticker := range newEnabledTickers {
+				newTickersSet[ticker] = true
+			}
+			disabledTickers := make([]string, 0)
+			for _, ticker := range a.enabledTickers {
+				if !newTickersSet[ticker] {
+					disabledTickers = append(disabledTickers, ticker)
+				}
+			}
 			a.enabledTickers = newEnabledTickers
 			if a.scheduler != nil {
 				a.scheduler.SetEnabledTickers(newEnabledTickers)
 			}
+			var stoppedGoroutines map[string]<-chan struct{}
 			if a.perTickerScheduler != nil {
-				a.perTickerScheduler.UpdateTickers(newEnabledTickers)
+				stoppedGoroutines = a.perTickerScheduler.UpdateTickers(newEnabledTickers)
 				a.debugPrint(fmt.Sprintf("PerTickerScheduler: Updated to %d enabled tickers", len(newEnabledTickers)), "app")
 			}
+			if len(disabledTickers) > 0 && !a.readOnly {
+				go a.drainDisabledTickers(disabledTickers, stoppedGoroutines)
+			}
 			// Also update the query planner via the coordinator
 			if a.coordinator != nil {
 				a.coordinator.UpdateEnabledTickers(newEnabledTickers)
@@ -728,166 +1702,1704 @@ func (a *App) SaveSettings(settings *config.Settings) error {
 	return nil
 }
 
-// GetEnabledTickers returns the list of enabled tickers
-// Returns empty array if no tickers are enabled (doesn't crash)
-// Always reads from current settings to ensure it reflects the latest state
-func (a *App) GetEnabledTickers() []string {
-	// Always read from current settings, not cached value
-	// This ensures it reflects the latest state even if a.enabledTickers is stale
-	settings := a.settingsManager.GetSettings()
-	
-	// Debug logging
-	a.debugPrint(fmt.Sprintf("GetEnabledTickers: Settings has %d ticker configs", len(settings.TickerConfigs)), "app")
-	for ticker, config := range settings.TickerConfigs {
-		a.debugPrint(fmt.Sprintf("GetEnabledTickers: Ticker %s - CollectionEnabled=%v, Display=%v", 
-			ticker, config.CollectionEnabled, config.Display), "app")
+// ListSettingsHistory returns the previous versions of the running profile's settings file, newest first
+func (a *App) ListSettingsHistory() ([]config.SettingsVersion, error) {
+	return a.settingsManager.ListSettingsHistory()
+}
+
+// RollbackSettings restores a previous version of the settings file (e.g. after accidentally disabling every ticker)
+// It goes through SaveSettings, so it's validated and applied live like any other change - and can itself be rolled back
+// Returns what the rollback changed
+func (a *App) RollbackSettings(version string) (config.SettingsDiff, error) {
+	settings, err := a.settingsManager.LoadSettingsVersion(version)
+	if err != nil {
+		return config.SettingsDiff{}, err
 	}
-	
-	enabled := getEnabledTickers(settings)
-	a.debugPrint(fmt.Sprintf("GetEnabledTickers: Returning %d enabled tickers: %v", len(enabled), enabled), "app")
-	
-	return enabled
+	diff := a.GetSettingsDiff(settings)
+	if err := a.SaveSettings(settings); err != nil {
+		return config.SettingsDiff{}, fmt.Errorf("failed to roll back to settings version %s: %w", version, err)
+	}
+	utils.Logf("[settings] Rolled back to version %s", version)
+	return diff, nil
 }
 
-// GetTickerData loads ticker data from the database
-// dateStr is in format "2006-01-02" (YYYY-MM-DD)
-// Returns map[string][]interface{} where each key is a field name and value is an array of values
-// Returns empty data if database doesn't exist yet (data collection hasn't started)
-// CRITICAL: Uses LoadTickerData instead of LoadFromFile to skip profiles_blob and prevent memory issues
-func (a *App) GetTickerData(ticker string, dateStr string) (map[string]interface{}, error) {
-	// Log memory usage before loading data
-	var mBefore runtime.MemStats
-	runtime.ReadMemStats(&mBefore)
-	a.debugPrint(fmt.Sprintf("GetTickerData: Memory before loading %s: Alloc=%d MB, Sys=%d MB, HeapAlloc=%d MB", 
-		ticker, mBefore.Alloc/1024/1024, mBefore.Sys/1024/1024, mBefore.HeapAlloc/1024/1024), "memory")
-	
-	// Parse date string in ET (not UTC)
-	date, err := utils.ParseDateInET(dateStr)
+// TickersDrainedEvent is the payload of the "tickers:drained" event
+type TickersDrainedEvent struct {
+	Tickers  []string `json:"tickers"`   // Tickers that were disabled
+	TimedOut []string `json:"timed_out"` // Tickers whose in-flight fetch didn't finish within the drain timeout
+}
+
+// drainDisabledTickers lets disabled tickers finish gracefully after a settings change:
+// waits for the ticker's goroutine to exit (in-flight fetch completes), flushes pending writes,
+// unregisters it from the chart tracker, then emits "tickers:drained" to the UI
+func (a *App) drainDisabledTickers(tickers []string, stoppedGoroutines map[string]<-chan struct{}) {
+	deadline := time.Now().Add(time.Duration(config.TickerDrainTimeoutSec) * time.Second)
+	event := TickersDrainedEvent{Tickers: tickers, TimedOut: make([]string, 0)}
+
+	for _, ticker := range tickers {
+		timedOut := false
+
+		// Wait for the scheduling goroutine to exit
+		if done, ok := stoppedGoroutines[ticker]; ok {
+			select {
+			case <-done:
+			case <-time.After(time.Until(deadline)):
+				timedOut = true
+			}
+		}
+
+		// A fetch can also be in flight from another batch
+		for !timedOut && a.coordinator != nil && a.coordinator.IsTickerInProgress(ticker) {
+			if time.Now().After(deadline) {
+				timedOut = true
+				break
+			}
+			time.Sleep(time.Duration(config.TickerDrainPollMs) * time.Millisecond)
+		}
+		if timedOut {
+			event.TimedOut = append(event.TimedOut, ticker)
+			a.debugPrint(fmt.Sprintf("Drain: Timed out waiting for in-flight fetch for %s - flushing anyway", ticker), "error")
+		}
+
+		if a.writeQueue != nil {
+			if err := a.writeQueue.DrainTicker(ticker); err != nil {
+				a.debugPrint(fmt.Sprintf("Drain: Failed to flush pending writes for %s: %v", ticker, err), "error")
+			}
+		}
+		if a.chartTracker != nil {
+			a.chartTracker.UnregisterTicker(ticker)
+		}
+		a.debugPrint(fmt.Sprintf("Drain: %s drained", ticker), "app")
+	}
+
+	emitEvent("tickers:drained", event)
+	a.debugPrint(fmt.Sprintf("Drain: Transition complete for %d disabled ticker(s)", len(tickers)), "app")
+}
+
+// ExportConfig writes a portable config bundle to path (API keys and sync credentials are excluded)
+// Includes ticker configs and groups, themes/chart colors, alerts and scheduling settings
+func (a *App) ExportConfig(path string) error {
+	if path == "" {
+		return fmt.Errorf("export path is required")
+	}
+
+	bundle, err := config.NewConfigBundle(a.settingsManager.GetSettings(), a.GetVersion())
 	if err != nil {
-		// Try current market date if parsing fails
-		date = utils.GetMarketDate()
-		// Extract just the date part at midnight ET
-		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, utils.GetMarketTimezone())
+		return err
 	}
-	
-	// Load data using lightweight LoadTickerData (skips profiles_blob)
-	// This prevents massive memory usage from decompressing profiles
-	data, err := a.dataLoader.LoadTickerData(ticker, date)
+	data, err := yaml.Marshal(bundle)
 	if err != nil {
-		// If there's an actual error (not just missing file), return it
-		a.debugPrint(fmt.Sprintf("GetTickerData: Error loading data for %s: %v", ticker, err), "error")
-		return nil, err
+		return fmt.Errorf("failed to marshal config bundle: %w", err)
 	}
-	
-	// Convert to map[string]interface{} for JSON serialization
-	result := make(map[string]interface{})
-	for k, v := range data {
-		result[k] = v
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config bundle: %w", err)
 	}
-	
-	// If no data, return empty structure (not nil)
-	if len(result) == 0 {
-		// Return structure with empty arrays for expected fields
-		result["timestamp"] = []interface{}{}
-		result["spot"] = []interface{}{}
-		result["zero_gamma"] = []interface{}{}
-		result["major_pos_vol"] = []interface{}{}
-		result["major_neg_vol"] = []interface{}{}
+
+	a.debugPrint(fmt.Sprintf("ExportConfig: Exported config to %s", path), "app")
+	return nil
+}
+
+// ImportConfig loads a config bundle from path and applies it
+// Local API keys, sync credentials, data directory and window size are kept
+func (a *App) ImportConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config bundle: %w", err)
 	}
-	
-	// Log memory usage after loading data
-	var mAfter runtime.MemStats
-	runtime.ReadMemStats(&mAfter)
-	memDelta := int64(mAfter.Alloc) - int64(mBefore.Alloc)
-	a.debugPrint(fmt.Sprintf("GetTickerData: Memory after loading %s: Alloc=%d MB, Sys=%d MB, HeapAlloc=%d MB, Delta=+%d MB", 
-		ticker, mAfter.Alloc/1024/1024, mAfter.Sys/1024/1024, mAfter.HeapAlloc/1024/1024, memDelta/1024/1024), "memory")
-	
-	return result, nil
+	bundle, err := config.ParseConfigBundle(data)
+	if err != nil {
+		return err
+	}
+
+	imported, err := bundle.ApplyTo(a.settingsManager.GetSettings())
+	if err != nil {
+		return err
+	}
+	if err := a.SaveSettings(imported); err != nil {
+		return err
+	}
+
+	a.debugPrint(fmt.Sprintf("ImportConfig: Imported config from %s (exported %s by %s)", path, bundle.ExportedAt, bundle.AppVersion), "app")
+	return nil
 }
 
-// GetTickerDataRange loads ticker data within a time range
-// dateStr is in format "2006-01-02" (YYYY-MM-DD)
-// Returns map[string][]interface{} where each key is a field name and value is an array of values
-func (a *App) GetTickerDataRange(ticker string, dateStr string, startTime, endTime float64) (map[string]interface{}, error) {
-	// Parse date string in ET (not UTC)
-	date, err := utils.ParseDateInET(dateStr)
+// GetTheme returns the active chart theme with per-series overrides applied
+func (a *App) GetTheme() config.Theme {
+	return a.settingsManager.GetSettings().GetTheme()
+}
+
+// GetAvailableThemes returns the names of the built-in themes
+func (a *App) GetAvailableThemes() []string {
+	return config.ThemeNames()
+}
+
+// SetTheme saves the theme and per-series color overrides (nil clears overrides)
+// SaveSettings emits "theme:changed" with the resolved theme so open chart windows recolor without reopening
+func (a *App) SetTheme(name string, overrides map[string]string) error {
+	known := false
+	for _, themeName := range config.ThemeNames() {
+		if themeName == name {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return fmt.Errorf("unknown theme: %q", name)
+	}
+	if err := config.ValidateThemeOverrides(overrides); err != nil {
+		return err
+	}
+
+	updated, err := a.settingsManager.GetSettings().Clone()
 	if err != nil {
-		// Try current market date if parsing fails
-		date = utils.GetMarketDate()
-		// Extract just the date part at midnight ET
-		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, utils.GetMarketTimezone())
+		return err
+	}
+	updated.Theme = name
+	updated.ThemeSeriesOverrides = overrides
+	if err := a.SaveSettings(updated); err != nil {
+		return err
+	}
+	a.debugPrint(fmt.Sprintf("Theme changed to %s (%d series overrides)", name, len(overrides)), "app")
+	return nil
+}
+
+// GetAPIKeyStats returns per-key request counts and rate limit state (keys are masked)
+func (a *App) GetAPIKeyStats() []api.KeyStats {
+	if a.apiClient == nil {
+		return []api.KeyStats{}
+	}
+	return a.apiClient.GetKeyStats()
+}
+
+// GetRateLimitStatus returns the API rate limit state (quota remaining, reset time, recent 429s, throttling)
+func (a *App) GetRateLimitStatus() scheduler.RateLimitStatus {
+	return a.scheduler.GetRateLimitTracker().GetStatus()
+}
+
+// FetchNow fetches a ticker immediately instead of waiting for its next scheduled cycle
+// Still subject to API rate limits; returns the timestamp (Unix seconds) of the fetched row
+func (a *App) FetchNow(ticker string) (float64, error) {
+	if a.readOnly {
+		return 0, errReadOnly
+	}
+	if a.coordinator == nil {
+		return 0, fmt.Errorf("data collection is not running")
+	}
+	ticker = strings.TrimSpace(ticker)
+	if ticker == "" {
+		return 0, fmt.Errorf("ticker is required")
+	}
+
+	a.debugPrint(fmt.Sprintf("FetchNow: Fetching %s outside its schedule", ticker), "app")
+	timestamp, err := a.coordinator.FetchNow(ticker)
+	if err != nil {
+		a.debugPrint(fmt.Sprintf("FetchNow: %s failed: %v", ticker, err), "app")
+		return 0, err
+	}
+	return timestamp, nil
+}
+
+// GetTimeSeriesSinkStatus returns the InfluxDB/TimescaleDB mirror's write counters and last error
+func (a *App) GetTimeSeriesSinkStatus() tsdb.MirrorStatus {
+	if a.dataWriter == nil {
+		return tsdb.MirrorStatus{}
+	}
+	return a.dataWriter.GetTimeSeriesSinkStatus()
+}
+
+// onClockSkewWarning notifies the UI that the system clock is off
+func (a *App) onClockSkewWarning(status coordinator.ClockSkewStatus) {
+	emitEvent("clock:skew", status)
+}
+
+// onQuotaSaverActivated tells the UI that tickers not open in a chart now fetch only chart endpoints for the day
+func (a *App) onQuotaSaverActivated(status coordinator.QuotaSaverStatus) {
+	emitEvent("quota:saver", status)
+}
+
+// onSpotDivergence warns the UI that a ticker's spot disagrees with the secondary quote source
+func (a *App) onSpotDivergence(ticker string, status coordinator.SpotCheckTickerStatus) {
+	emitEvent("spot:divergence", map[string]interface{}{"ticker": ticker, "status": status})
+}
+
+// onProcessorEvent passes an event reported by a data processor to the UI
+func (a *App) onProcessorEvent(processor, ticker string, event processors.Event) {
+	a.debugPrint(fmt.Sprintf("Processor %s: %s %s %s", processor, ticker, event.Name, event.Message), "app")
+	emitEvent("processor:event", map[string]interface{}{"processor": processor, "ticker": ticker, "event": event})
+}
+
+// GetProcessorStatus returns every registered data processor, whether it is enabled and its counters
+func (a *App) GetProcessorStatus() []processors.Status {
+	if a.coordinator == nil {
+		return []processors.Status{}
+	}
+	return a.coordinator.GetProcessorRunner().Status()
+}
+
+// applyScriptSettings loads the scripts and hooks them into the alert engine and the writer, or unhooks them
+// when scripts are disabled (alert rules with condition: script then never fire)
+func (a *App) applyScriptSettings(settings config.ScriptSettings) {
+	if a.scripts == nil {
+		return
+	}
+	if !settings.Enabled {
+		alerts.SetScriptRunner(nil)
+		if a.dataWriter != nil {
+			a.dataWriter.SetDerivedScripts(nil)
+		}
+		a.scripts.Close()
+		return
+	}
+	a.scripts.SetTimeoutMs(settings.GetTimeoutMs())
+	if err := a.scripts.Load(); err != nil {
+		a.debugPrint(fmt.Sprintf("WARNING: %v", err), "error")
+	}
+	alerts.SetScriptRunner(a.scripts)
+	if a.dataWriter != nil {
+		a.dataWriter.SetDerivedScripts(a.scripts.Columns)
+	}
+}
+
+// GetScriptStatus returns the loaded scripts, what they define and their call counters
+func (a *App) GetScriptStatus() map[string]interface{} {
+	return map[string]interface{}{
+		"enabled": a.settingsManager.GetSettings().Scripts.Enabled,
+		"dir":     a.scripts.Dir(),
+		"scripts": a.scripts.Status(),
+	}
+}
+
+// ReloadScripts re-reads the scripts directory (after editing a script), resetting the scripts' state and counters
+func (a *App) ReloadScripts() (map[string]interface{}, error) {
+	if !a.settingsManager.GetSettings().Scripts.Enabled {
+		return nil, fmt.Errorf("scripts are disabled (set scripts.enabled in settings)")
+	}
+	if err := a.scripts.Load(); err != nil {
+		return nil, err
+	}
+	return a.GetScriptStatus(), nil
+}
+
+// onLevelCross notifies the UI when spot crosses zero_gamma or a major level
+func (a *App) onLevelCross(ticker string, event database.LevelCrossEvent) {
+	emitEvent("level:cross", map[string]interface{}{"ticker": ticker, "event": event})
+}
+
+// GetSpotCheckStatus returns the last comparison of each ticker's spot with the secondary quote source
+func (a *App) GetSpotCheckStatus() coordinator.SpotCheckStatus {
+	if a.coordinator == nil {
+		return coordinator.SpotCheckStatus{Divergent: []string{}, Tickers: map[string]coordinator.SpotCheckTickerStatus{}}
+	}
+	return a.coordinator.GetSpotChecker().GetStatus()
+}
+
+// AlertNotice is a triggered alert as the UI sees it
+type AlertNotice struct {
+	alerts.Alert
+	Sound string `json:"sound"` // Sound the main window should play ("" = none: muted, silenced or played by the backend)
+}
+
+// onAlertTriggered announces a live alert: the backend plays its sound (sound_output: backend) and the UI is notified
+func (a *App) onAlertTriggered(alert alerts.Alert) {
+	liveAlerts := a.settingsManager.GetSettings().LiveAlerts
+	a.debugPrint(fmt.Sprintf("🔔 Alert %q: %s %s %s %.2f (%.2f)", alert.Rule.Name, alert.Ticker, alert.Rule.Field,
+		alert.Rule.Condition, alert.Threshold, alert.Value), "app")
+	soundName := liveAlerts.SoundFor(alert.Rule)
+	if soundName != "" && liveAlerts.GetSoundOutput() == config.AlertSoundOutputBackend {
+		if err := sound.Play(soundName); err != nil {
+			a.debugPrint(fmt.Sprintf("Alert sound %q failed: %v", soundName, err), "error")
+		}
+	}
+	emitEvent("alert:triggered", a.alertNotice(alert, liveAlerts))
+	if id := alerts.PriceAlertID(alert.Rule); id != "" {
+		// Price alerts are one-shot: the line comes off the chart once spot reaches it
+		go func() {
+			if err := a.DeletePriceAlert(id); err != nil {
+				a.debugPrint(fmt.Sprintf("Failed to remove fired price alert %s: %v", id, err), "error")
+			}
+		}()
+	}
+}
+
+// alertNotice adds the sound the main window should play
+func (a *App) alertNotice(alert alerts.Alert, liveAlerts config.AlertSettings) AlertNotice {
+	notice := AlertNotice{Alert: alert}
+	if liveAlerts.GetSoundOutput() == config.AlertSoundOutputFrontend {
+		notice.Sound = liveAlerts.SoundFor(alert.Rule)
+	}
+	return notice
+}
+
+// GetRecentAlerts returns the alerts triggered after afterSeq (0 = all remembered), oldest first
+// The main window polls this and plays each notice's sound
+func (a *App) GetRecentAlerts(afterSeq int64) []AlertNotice {
+	notices := make([]AlertNotice, 0)
+	if a.coordinator == nil {
+		return notices
+	}
+	liveAlerts := a.settingsManager.GetSettings().LiveAlerts
+	for _, alert := range a.coordinator.GetAlertEngine().Recent(afterSeq) {
+		notices = append(notices, a.alertNotice(alert, liveAlerts))
+	}
+	return notices
+}
+
+// SetAlertsMuted turns the global alert mute on or off (saved in settings; alerts still fire and are listed)
+func (a *App) SetAlertsMuted(muted bool) error {
+	updated, err := a.settingsManager.GetSettings().Clone()
+	if err != nil {
+		return err
+	}
+	updated.LiveAlerts.Muted = muted
+	return a.SaveSettings(updated)
+}
+
+// GetAlertSounds returns the bundled alert sound names (rules may also name a .wav file)
+func (a *App) GetAlertSounds() []string {
+	return append([]string(nil), config.BundledAlertSounds...)
+}
+
+// PlayAlertSound plays a sound through the OS now (preview in the alert editor)
+func (a *App) PlayAlertSound(name string) error {
+	return sound.Play(name)
+}
+
+// GetAlertSoundWAV returns a sound's WAV data for the main window to play
+// Only bundled sounds and files configured in live_alerts are served
+func (a *App) GetAlertSoundWAV(name string) ([]byte, error) {
+	if !a.settingsManager.GetSettings().LiveAlerts.UsesSound(name) {
+		return nil, fmt.Errorf("unknown alert sound %q", name)
+	}
+	return sound.WAV(name)
+}
+
+// CreatePriceAlert adds a one-shot alarm line at a price on a ticker's chart (saved in live_alerts.price_alerts)
+// direction is "above", "below" or "cross"; "" picks the side the level is on from the latest spot
+func (a *App) CreatePriceAlert(ticker string, level float64, direction string) (alerts.PriceAlert, error) {
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+	if direction == "" {
+		direction = alerts.DirectionCross
+		if spot, ok := a.latestSpot(ticker); ok {
+			direction = alerts.DirectionAbove
+			if level < spot {
+				direction = alerts.DirectionBelow
+			}
+		}
+	}
+	now := time.Now()
+	priceAlert := alerts.PriceAlert{
+		ID:        fmt.Sprintf("%s-%d", strings.ToLower(ticker), now.UnixNano()),
+		Ticker:    ticker,
+		Level:     level,
+		Direction: direction,
+		CreatedAt: float64(now.UnixMilli()) / 1000,
+	}
+	if err := priceAlert.Validate(); err != nil {
+		return alerts.PriceAlert{}, err
+	}
+
+	updated, err := a.settingsManager.GetSettings().Clone()
+	if err != nil {
+		return alerts.PriceAlert{}, err
+	}
+	updated.LiveAlerts.PriceAlerts = append(updated.LiveAlerts.PriceAlerts, priceAlert)
+	if err := a.SaveSettings(updated); err != nil {
+		return alerts.PriceAlert{}, err
+	}
+	a.debugPrint(fmt.Sprintf("Price alert %s: %s %s %.2f", priceAlert.ID, ticker, direction, level), "app")
+	return priceAlert, nil
+}
+
+// ListPriceAlerts returns the price alerts on a ticker ("" = every ticker)
+func (a *App) ListPriceAlerts(ticker string) []alerts.PriceAlert {
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+	result := make([]alerts.PriceAlert, 0)
+	for _, priceAlert := range a.settingsManager.GetSettings().LiveAlerts.PriceAlerts {
+		if ticker == "" || priceAlert.Ticker == ticker {
+			result = append(result, priceAlert)
+		}
+	}
+	return result
+}
+
+// MovePriceAlert moves an alert line to a new level (dragged on the chart); the direction is kept
+func (a *App) MovePriceAlert(id string, level float64) (alerts.PriceAlert, error) {
+	updated, err := a.settingsManager.GetSettings().Clone()
+	if err != nil {
+		return alerts.PriceAlert{}, err
+	}
+	i := updated.LiveAlerts.FindPriceAlert(id)
+	if i < 0 {
+		return alerts.PriceAlert{}, fmt.Errorf("price alert not found: %s", id)
+	}
+	updated.LiveAlerts.PriceAlerts[i].Level = level
+	if err := updated.LiveAlerts.PriceAlerts[i].Validate(); err != nil {
+		return alerts.PriceAlert{}, err
+	}
+	if err := a.SaveSettings(updated); err != nil {
+		return alerts.PriceAlert{}, err
+	}
+	return updated.LiveAlerts.PriceAlerts[i], nil
+}
+
+// DeletePriceAlert removes an alert line
+func (a *App) DeletePriceAlert(id string) error {
+	updated, err := a.settingsManager.GetSettings().Clone()
+	if err != nil {
+		return err
+	}
+	i := updated.LiveAlerts.FindPriceAlert(id)
+	if i < 0 {
+		return fmt.Errorf("price alert not found: %s", id)
+	}
+	updated.LiveAlerts.PriceAlerts = append(updated.LiveAlerts.PriceAlerts[:i], updated.LiveAlerts.PriceAlerts[i+1:]...)
+	return a.SaveSettings(updated)
+}
+
+// latestSpot returns a ticker's latest spot from memory for the current market date
+func (a *App) latestSpot(ticker string) (float64, bool) {
+	if a.coordinator == nil {
+		return 0, false
+	}
+	snapshot, ok := a.coordinator.GetLatestStore().Current(ticker)
+	if !ok {
+		return 0, false
+	}
+	spot, ok := snapshot.Fields["spot"].(float64)
+	return spot, ok && spot > 0
+}
+
+// GetFetchFallbackStatus returns how long cached responses may fill in for failed fetches and how often they did
+func (a *App) GetFetchFallbackStatus() coordinator.FetchFallbackStatus {
+	if a.coordinator == nil {
+		return coordinator.FetchFallbackStatus{}
+	}
+	return a.coordinator.GetFetchFallback().Status()
+}
+
+// GetPoolStats returns the SQLite connection pools' sizes and counters ("reader" = chart/table loads,
+// "writer" = collection writes; the writer is missing in read-only mode)
+func (a *App) GetPoolStats() map[string]database.PoolStats {
+	stats := map[string]database.PoolStats{"reader": a.dataLoader.PoolStats()}
+	if a.dataWriter != nil {
+		stats["writer"] = a.dataWriter.PoolStats()
+	}
+	return stats
+}
+
+// GetFieldSources returns the endpoint each field of a ticker's last collected row came from, and the
+// endpoints whose value for it was dropped (older response or lower source priority)
+func (a *App) GetFieldSources(ticker string) map[string]coordinator.FieldSource {
+	if a.coordinator == nil {
+		return map[string]coordinator.FieldSource{}
+	}
+	return a.coordinator.GetFieldSources(ticker)
+}
+
+// GetQueryPlanPreview returns the queries the planner would issue if the given tickers were enabled alongside
+// the current ones (endpoints, polling interval and requests per minute per ticker, and the share of the API
+// rate limit); nothing is fetched. With no tickers it previews the current configuration
+func (a *App) GetQueryPlanPreview(tickers []string) coordinator.QueryPlanPreview {
+	if a.coordinator == nil {
+		return coordinator.QueryPlanPreview{Tickers: []coordinator.TickerPlanPreview{}}
+	}
+	normalized := make([]string, 0, len(tickers))
+	for _, ticker := range tickers {
+		if ticker = strings.ToUpper(strings.TrimSpace(ticker)); ticker != "" {
+			normalized = append(normalized, ticker)
+		}
+	}
+	return a.coordinator.PreviewQueryPlan(normalized)
+}
+
+// GetClockSkewStatus returns the measured skew between the local clock and API timestamps
+func (a *App) GetClockSkewStatus() coordinator.ClockSkewStatus {
+	if a.coordinator == nil {
+		return coordinator.ClockSkewStatus{}
+	}
+	return a.coordinator.GetClockSkewMonitor().GetStatus()
+}
+
+// GetQuotaSaverStatus returns whether background tickers were switched to chart endpoints to save API quota,
+// with the quota projection behind it
+func (a *App) GetQuotaSaverStatus() coordinator.QuotaSaverStatus {
+	if a.coordinator == nil {
+		return coordinator.QuotaSaverStatus{}
+	}
+	return a.coordinator.GetQuotaSaver().GetStatus()
+}
+
+// GetProfilerAddress returns the address the pprof server is listening on
+// ("" when it is disabled or failed to start); with port 0 this is the port that was picked
+func (a *App) GetProfilerAddress() string {
+	profilerAddrLock.RLock()
+	defer profilerAddrLock.RUnlock()
+	return profilerAddr
+}
+
+// GetCrashReports lists crash dumps written after recovered panics, newest first
+func (a *App) GetCrashReports() ([]crash.ReportInfo, error) {
+	return crash.ListReports()
+}
+
+// OpenCrashReport opens a crash dump in the default viewer, or shows it in its folder when reveal is set
+// (for attaching to a bug report)
+func (a *App) OpenCrashReport(path string, reveal bool) error {
+	return crash.Open(path, reveal)
+}
+
+// ReadCrashReport returns a crash dump's contents (for copying into a bug report)
+func (a *App) ReadCrashReport(path string) (string, error) {
+	path, err := crash.ResolveReport(path)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read crash report: %w", err)
+	}
+	return string(data), nil
+}
+
+// ExportDiagnostics writes a support zip for attaching to issues: recent logs, settings without credentials,
+// health/status snapshots, recent crash dumps and the latest rows of each enabled ticker
+// With obfuscate set the sampled values are scaled by a random factor per ticker (timestamps are kept)
+// path "" = diagnostics_<time>.zip in the exports folder; returns the written path
+func (a *App) ExportDiagnostics(path string, obfuscate bool) (string, error) {
+	settings := a.settingsManager.GetSettings()
+	shared, err := settings.WithoutSecrets()
+	if err != nil {
+		return "", err
+	}
+	settingsYAML, err := yaml.Marshal(shared)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal settings: %w", err)
+	}
+
+	status := map[string]interface{}{
+		"rate_limit":       a.GetRateLimitStatus(),
+		"circuit_breakers": a.GetCircuitBreakerStatus(),
+		"batch_overlaps":   a.GetBatchOverlapStatus(),
+		"batch_coalescing": a.GetBatchCoalesceStatus(),
+		"adaptive_batching": a.GetAdaptiveBatchStatus(),
+		"rejected_values":  a.GetRejectedValueStatus(),
+		"clock_skew":       a.GetClockSkewStatus(),
+		"quota_saver":      a.GetQuotaSaverStatus(),
+		"pre_open":         a.GetPreOpenStatus(),
+		"spot_check":       a.GetSpotCheckStatus(),
+		"fetch_fallback":   a.GetFetchFallbackStatus(),
+		"timeseries_sink":  a.GetTimeSeriesSinkStatus(),
+		"trace_export":     a.GetTraceExportStatus(),
+		"recent_traces":    a.GetRecentTraces("", 50),
+		"profiler_address": a.GetProfilerAddress(),
+		"eco":              a.GetEcoStatus(),
+		"read_only":        a.readOnly,
+	}
+	if a.healthCheck != nil {
+		status["health"] = a.healthCheck.GetStatus()
+	}
+	if a.memoryMonitor != nil {
+		status["memory"] = a.memoryMonitor.Check()
+	}
+	if a.diskMonitor != nil {
+		status["disk_space"] = a.GetDiskSpaceStatus()
+	}
+
+	marketDate := utils.GetMarketDate()
+	samples := make(map[string][]map[string]interface{})
+	for _, ticker := range config.GetEnabledTickers(settings.TickerConfigs) {
+		rows, err := a.dataLoader.LoadLatestRows(ticker, marketDate, config.DiagnosticsSampleRows)
+		if err != nil {
+			a.debugPrint(fmt.Sprintf("ExportDiagnostics: Skipping sample for %s: %v", ticker, err), "app")
+			continue
+		}
+		samples[ticker] = rows
+	}
+	if obfuscate {
+		reports.ObfuscateSamples(samples)
+	}
+
+	var crashReports []string
+	if reportsList, err := crash.ListReports(); err == nil {
+		for i, report := range reportsList {
+			if i >= config.DiagnosticsCrashReports {
+				break
+			}
+			crashReports = append(crashReports, report.Path)
+		}
+	}
+
+	bundle := reports.DiagnosticsBundle{
+		Manifest: map[string]interface{}{
+			"app_version": appVersion,
+			"exported_at": time.Now().Format(time.RFC3339),
+			"market_date": marketDate.Format("2006-01-02"),
+			"os":          runtime.GOOS,
+			"arch":        runtime.GOARCH,
+			"go_version":  runtime.Version(),
+			"obfuscated":  obfuscate,
+		},
+		Settings:     settingsYAML,
+		Status:       status,
+		RecentLogs:   utils.RecentLogLines(config.RecentLogBufferLines),
+		LogFile:      utils.GetLogger().Path(),
+		LogTailBytes: config.DiagnosticsLogTailBytes,
+		Samples:      samples,
+		CrashReports: crashReports,
+	}
+
+	if path == "" {
+		configDir, err := config.GetConfigDir()
+		if err != nil {
+			return "", err
+		}
+		exportDir := filepath.Join(configDir, config.ChartExportDirName)
+		if err := os.MkdirAll(exportDir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create export directory: %w", err)
+		}
+		path = filepath.Join(exportDir, fmt.Sprintf("diagnostics_%s.zip", time.Now().Format("2006-01-02_15-04-05")))
+	}
+	if err := reports.WriteDiagnosticsBundle(path, bundle); err != nil {
+		return "", err
+	}
+
+	a.debugPrint(fmt.Sprintf("ExportDiagnostics: Wrote diagnostics bundle to %s (%d ticker samples, obfuscated=%v)", path, len(samples), obfuscate), "app")
+	return path, nil
+}
+
+// ReplayRecordedDay re-extracts a ticker's day from its recorded raw API responses and rewrites the rows
+// Use after a parsing fix to correct data collected with the bug (needs record_raw_responses on that day)
+func (a *App) ReplayRecordedDay(ticker string, dateStr string) (coordinator.ReplayResult, error) {
+	if a.coordinator == nil {
+		return coordinator.ReplayResult{}, fmt.Errorf("replay is not available in read-only mode")
+	}
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		return coordinator.ReplayResult{}, fmt.Errorf("invalid date %q: %w", dateStr, err)
+	}
+	result, err := a.coordinator.ReplayDay(a.shutdownCtx, strings.ToUpper(strings.TrimSpace(ticker)), date)
+	if err != nil {
+		return result, err
+	}
+	// Cached results for the day are stale now
+	a.dataLoader.ClearCaches()
+	return result, nil
+}
+
+// ReprocessDay re-extracts scalar columns from a ticker's stored profiles for one day and fills the
+// columns that are NULL (see DataWriter.ReprocessDay) - for days where a field-mapping bug lost a column
+func (a *App) ReprocessDay(ticker string, dateStr string) (database.ReprocessResult, error) {
+	if a.dataWriter == nil {
+		return database.ReprocessResult{}, fmt.Errorf("reprocessing is not available in read-only mode")
+	}
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		return database.ReprocessResult{}, fmt.Errorf("invalid date %q: %w", dateStr, err)
+	}
+	result, err := a.dataWriter.ReprocessDay(a.shutdownCtx, strings.ToUpper(strings.TrimSpace(ticker)), date)
+	if err != nil {
+		return result, err
+	}
+	// Cached results for the day are stale now
+	a.dataLoader.ClearCaches()
+	return result, nil
+}
+
+// MergeDatabases merges another machine's database file for a ticker and market date into the local day
+// (rows the local file already has are skipped), e.g. to unify a day collected at home and at the office
+func (a *App) MergeDatabases(sourcePath string, ticker string, dateStr string) (*database.MergeResult, error) {
+	if a.dataWriter == nil || a.readOnly {
+		return nil, errReadOnly
+	}
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q: %w", dateStr, err)
+	}
+	result, err := a.dataWriter.MergeDatabases(a.shutdownCtx, strings.TrimSpace(sourcePath), strings.ToUpper(strings.TrimSpace(ticker)), date)
+	if err != nil {
+		return nil, err
+	}
+	// Cached results for the day are stale now
+	a.dataLoader.ClearCaches()
+	return result, nil
+}
+
+// GetRecentTraces returns recent collection traces, newest first: one per scheduler wakeup (or Fetch now),
+// with plan, fetch, aggregate, write and flush spans timed from the wakeup - e.g. to see why a row was late
+// ticker "" = all tickers; limit <= 0 = all kept traces
+func (a *App) GetRecentTraces(ticker string, limit int) []tracing.TraceSummary {
+	if a.coordinator == nil {
+		return []tracing.TraceSummary{}
+	}
+	return a.coordinator.GetTracer().Recent(strings.ToUpper(strings.TrimSpace(ticker)), limit)
+}
+
+// GetTrace returns one recent trace by ID (the full ID or the 8-character prefix shown in log lines)
+func (a *App) GetTrace(id string) (tracing.TraceSummary, error) {
+	if a.coordinator != nil {
+		if trace, ok := a.coordinator.GetTracer().Find(strings.TrimSpace(id)); ok {
+			return trace, nil
+		}
+	}
+	return tracing.TraceSummary{}, fmt.Errorf("trace %q not found (only the last %d traces are kept)", id, config.RecentTraceCount)
+}
+
+// GetTraceExportStatus returns the OpenTelemetry trace exporter's counters and last error
+func (a *App) GetTraceExportStatus() tracing.ExportStatus {
+	if a.coordinator == nil {
+		return tracing.ExportStatus{}
+	}
+	return a.coordinator.GetTracer().ExportStatus()
+}
+
+// GetBatchOverlapStatus returns how many overlapping ticker batches were avoided (skipped or queued) and the
+// tickers being fetched right now
+func (a *App) GetBatchOverlapStatus() coordinator.BatchOverlapStatus {
+	if a.coordinator == nil {
+		return coordinator.BatchOverlapStatus{Policy: config.BatchOverlapSkip, PerTicker: map[string]int64{}, InFlight: []string{}, Pending: []string{}}
+	}
+	return a.coordinator.GetBatchOverlapStatus()
+}
+
+// GetAdaptiveBatchStatus returns how the writer currently sizes collection flush batches
+func (a *App) GetAdaptiveBatchStatus() database.AdaptiveBatchStatus {
+	if a.dataWriter == nil {
+		return database.AdaptiveBatchStatus{}
+	}
+	return a.dataWriter.GetAdaptiveBatchStatus()
+}
+
+// GetRejectedValueStatus returns the values the writer stored as NULL (and rows it dropped) because the schema's
+// type or range checks refused them
+func (a *App) GetRejectedValueStatus() database.RejectedValueStatus {
+	if a.dataWriter == nil {
+		return database.RejectedValueStatus{PerColumn: map[string]int64{}}
+	}
+	return a.dataWriter.GetRejectedValueStatus()
+}
+
+// GetBatchCoalesceStatus returns how many ticker timers were merged into shared batches
+func (a *App) GetBatchCoalesceStatus() coordinator.BatchCoalesceStatus {
+	if a.coordinator == nil {
+		return coordinator.BatchCoalesceStatus{}
+	}
+	return a.coordinator.GetBatchCoalescer().Status()
+}
+
+// GetCircuitBreakerStatus returns the circuit breaker state per API endpoint family (classic, state, orderflow)
+func (a *App) GetCircuitBreakerStatus() []coordinator.CircuitStatus {
+	if a.coordinator == nil {
+		return []coordinator.CircuitStatus{}
+	}
+	return a.coordinator.GetCircuitBreakerStatus()
+}
+
+// emitRateLimitStatus pushes the rate limit state to the frontend periodically ("ratelimit:status")
+// so throttling is visible without polling
+func (a *App) emitRateLimitStatus() {
+	ticker := time.NewTicker(time.Duration(config.RateLimitStatusEventSec) * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		a.shutdownLock.RLock()
+		shuttingDown := a.shuttingDown
+		a.shutdownLock.RUnlock()
+		if shuttingDown {
+			return
+		}
+		emitEvent("ratelimit:status", a.GetRateLimitStatus())
+	}
+}
+
+// GetPollingIntervals returns the effective polling interval matrix (priority × ticker count)
+func (a *App) GetPollingIntervals() config.PollingIntervals {
+	return a.settingsManager.GetSettings().GetPollingIntervals()
+}
+
+// SetPollingIntervals validates, saves and applies a new polling interval matrix
+// Takes effect on each ticker's next scheduling cycle - no restart required
+func (a *App) SetPollingIntervals(intervals config.PollingIntervals) error {
+	if err := intervals.Validate(); err != nil {
+		return fmt.Errorf("invalid polling intervals: %w", err)
+	}
+
+	updated, err := a.settingsManager.GetSettings().Clone()
+	if err != nil {
+		return err
+	}
+	updated.PollingIntervals = &intervals
+	return a.SaveSettings(updated)
+}
+
+// ResetPollingIntervals restores the built-in polling interval matrix
+func (a *App) ResetPollingIntervals() error {
+	updated, err := a.settingsManager.GetSettings().Clone()
+	if err != nil {
+		return err
+	}
+	updated.PollingIntervals = nil
+	return a.SaveSettings(updated)
+}
+
+// GetEnabledTickers returns the list of enabled tickers
+// Returns empty array if no tickers are enabled (doesn't crash)
+// Always reads from current settings to ensure it reflects the latest state
+func (a *App) GetEnabledTickers() []string {
+	// Always read from current settings, not cached value
+	// This ensures it reflects the latest state even if a.enabledTickers is stale
+	settings := a.settingsManager.GetSettings()
+	
+	// Debug logging
+	a.debugPrint(fmt.Sprintf("GetEnabledTickers: Settings has %d ticker configs", len(settings.TickerConfigs)), "app")
+	for ticker, config := range settings.TickerConfigs {
+		a.debugPrint(fmt.Sprintf("GetEnabledTickers: Ticker %s - CollectionEnabled=%v, Display=%v", 
+			ticker, config.CollectionEnabled, config.Display), "app")
+	}
+	
+	enabled := getEnabledTickers(settings)
+	a.debugPrint(fmt.Sprintf("GetEnabledTickers: Returning %d enabled tickers: %v", len(enabled), enabled), "app")
+	
+	return enabled
+}
+
+// GetSymbolInfo returns symbol metadata (asset class, underlying, tick size, decimals) for chart formatting
+func (a *App) GetSymbolInfo(ticker string) config.SymbolInfo {
+	return a.settingsManager.GetSettings().GetSymbolInfo(ticker)
+}
+
+// GetTickerGroups returns the configured ticker groups in display order
+func (a *App) GetTickerGroups() []config.TickerGroup {
+	groups := a.settingsManager.GetSettings().TickerGroups
+	if groups == nil {
+		return []config.TickerGroup{}
+	}
+	return groups
+}
+
+// SaveTickerGroup creates a group or replaces the existing group with the same name
+// The order of group.Tickers is the order charts are opened in
+func (a *App) SaveTickerGroup(group config.TickerGroup) error {
+	group.Name = strings.TrimSpace(group.Name)
+	if err := group.Validate(); err != nil {
+		return err
+	}
+
+	updated, err := a.settingsManager.GetSettings().Clone()
+	if err != nil {
+		return err
+	}
+	if i := updated.FindTickerGroup(group.Name); i >= 0 {
+		updated.TickerGroups[i] = group
+	} else {
+		updated.TickerGroups = append(updated.TickerGroups, group)
+	}
+	return a.SaveSettings(updated)
+}
+
+// DeleteTickerGroup removes a group (ticker configs are left unchanged)
+func (a *App) DeleteTickerGroup(name string) error {
+	updated, err := a.settingsManager.GetSettings().Clone()
+	if err != nil {
+		return err
+	}
+	i := updated.FindTickerGroup(name)
+	if i < 0 {
+		return fmt.Errorf("ticker group not found: %s", name)
+	}
+	updated.TickerGroups = append(updated.TickerGroups[:i], updated.TickerGroups[i+1:]...)
+	return a.SaveSettings(updated)
+}
+
+// SetTickerGroupOrder reorders the tickers within a group
+// tickers must contain exactly the group's current tickers
+func (a *App) SetTickerGroupOrder(name string, tickers []string) error {
+	updated, err := a.settingsManager.GetSettings().Clone()
+	if err != nil {
+		return err
+	}
+	i := updated.FindTickerGroup(name)
+	if i < 0 {
+		return fmt.Errorf("ticker group not found: %s", name)
+	}
+
+	current := make(map[string]bool, len(updated.TickerGroups[i].Tickers))
+	for _, ticker := range updated.TickerGroups[i].Tickers {
+		current[ticker] = true
+	}
+	reordered := config.TickerGroup{Name: updated.TickerGroups[i].Name, Tickers: tickers}
+	if err := reordered.Validate(); err != nil {
+		return err
+	}
+	if len(tickers) != len(current) {
+		return fmt.Errorf("order must contain exactly the %d tickers in group %s", len(current), name)
+	}
+	for _, ticker := range tickers {
+		if !current[ticker] {
+			return fmt.Errorf("%s is not in group %s", ticker, name)
+		}
+	}
+
+	updated.TickerGroups[i] = reordered
+	return a.SaveSettings(updated)
+}
+
+// SetTickerGroupCollection enables or disables collection for every ticker in a group
+// Goes through SaveSettings so schedulers pick up the change (disabled tickers are drained)
+func (a *App) SetTickerGroupCollection(name string, enabled bool) error {
+	updated, err := a.settingsManager.GetSettings().Clone()
+	if err != nil {
+		return err
+	}
+	i := updated.FindTickerGroup(name)
+	if i < 0 {
+		return fmt.Errorf("ticker group not found: %s", name)
+	}
+	updated.SetGroupCollectionEnabled(updated.TickerGroups[i], enabled)
+	return a.SaveSettings(updated)
+}
+
+// OpenTickerGroupCharts opens a chart window for every ticker in a group, in group order
+// dateStr is optional - if empty, charts use the current market date
+func (a *App) OpenTickerGroupCharts(name string, dateStr string) error {
+	settings := a.settingsManager.GetSettings()
+	i := settings.FindTickerGroup(name)
+	if i < 0 {
+		return fmt.Errorf("ticker group not found: %s", name)
+	}
+
+	failed := make([]string, 0)
+	for _, ticker := range settings.TickerGroups[i].Tickers {
+		if err := a.OpenChartWindow(ticker, dateStr); err != nil {
+			a.debugPrint(fmt.Sprintf("OpenTickerGroupCharts: Failed to open %s: %v", ticker, err), "error")
+			failed = append(failed, ticker)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to open charts for: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// chartWindowState is what a workspace needs to reopen a chart window besides its geometry
+type chartWindowState struct {
+	date        string   // Date the window was opened with ("" = current market date)
+	live        bool     // Opened with the then-current market date (moved to the new one by RetargetChartsToMarketDate)
+	hiddenPlots []string // Plots hidden in this chart (reported by the window); nil = hidden_plots setting
+}
+
+// MarketDateChange is the market-date-changed event: the rollover and the charts still on the previous date
+type MarketDateChange struct {
+	Previous string   `json:"previous"`
+	Current  string   `json:"current"`
+	Charts   []string `json:"charts"` // Tickers whose chart window shows the previous date (see RetargetChartsToMarketDate)
+}
+
+// onMarketDateChanged emits market-date-changed when the market date rolls over (8:30 AM ET)
+// Windows opened without a date already follow the market date; the event lists the ones pinned to the old one
+func (a *App) onMarketDateChanged(previous, current time.Time) {
+	change := MarketDateChange{
+		Previous: previous.Format("2006-01-02"),
+		Current:  current.Format("2006-01-02"),
+		Charts:   a.liveChartsBefore(current.Format("2006-01-02")),
+	}
+	a.debugPrint(fmt.Sprintf("Market date changed to %s; %d chart(s) still show %s", change.Current, len(change.Charts), change.Previous), "app")
+	emitEvent("market-date-changed", change)
+}
+
+// GetMarketDateChange returns the current (trading) market date and the chart windows still on the previous one,
+// the state market-date-changed announces (polled by the main window in case it missed the event)
+func (a *App) GetMarketDateChange() MarketDateChange {
+	current := a.marketDateWatcher.Current().Format("2006-01-02")
+	return MarketDateChange{Current: current, Charts: a.liveChartsBefore(current)}
+}
+
+// liveChartsBefore returns the tickers of chart windows opened on the then-current market date that is now older
+// than today (sorted)
+func (a *App) liveChartsBefore(today string) []string {
+	a.chartWindowsLock.RLock()
+	defer a.chartWindowsLock.RUnlock()
+	tickers := make([]string, 0)
+	for ticker, state := range a.chartWindowStates {
+		if a.chartWindows[ticker] != nil && state.live && state.date != "" && state.date < today {
+			tickers = append(tickers, ticker)
+		}
+	}
+	sort.Strings(tickers)
+	return tickers
+}
+
+// RetargetChartsToMarketDate moves chart windows still showing the previous market date (opened on it while it
+// was current) to the current one. With keepOldDate they stay on their date with a "previous session" banner
+// instead, and are left alone on later rollovers. Returns the tickers changed
+func (a *App) RetargetChartsToMarketDate(keepOldDate bool) []string {
+	today := a.marketDateWatcher.Current().Format("2006-01-02")
+	tickers := a.liveChartsBefore(today)
+
+	urls := make(map[*application.WebviewWindow]string, len(tickers))
+	a.chartWindowsLock.Lock()
+	for _, ticker := range tickers {
+		window, state := a.chartWindows[ticker], a.chartWindowStates[ticker]
+		if window == nil || state == nil {
+			continue
+		}
+		if keepOldDate {
+			urls[window] = chartWindowURL(ticker, state.date) + "&previous_session=1"
+			state.live = false
+		} else {
+			urls[window] = chartWindowURL(ticker, today)
+			state.date = today
+		}
+	}
+	a.chartWindowsLock.Unlock()
+
+	// Navigated outside the lock (window calls may dispatch events that take it)
+	for window, url := range urls {
+		window.SetURL(url)
+	}
+	target := "moved to " + today
+	if keepOldDate {
+		target = "kept on the previous session"
+	}
+	a.debugPrint(fmt.Sprintf("RetargetChartsToMarketDate: %d chart(s) %s: %v", len(tickers), target, tickers), "app")
+	return tickers
+}
+
+// GetWorkspaces returns the saved chart layouts
+func (a *App) GetWorkspaces() []config.Workspace {
+	workspaces := a.settingsManager.GetSettings().Workspaces
+	if workspaces == nil {
+		return []config.Workspace{}
+	}
+	return workspaces
+}
+
+// SaveWorkspace stores the open chart windows (geometry, date and per-chart options) as a named workspace,
+// replacing a workspace with the same name
+func (a *App) SaveWorkspace(name string) (config.Workspace, error) {
+	workspace := config.Workspace{Name: strings.TrimSpace(name), Charts: make([]config.ChartLayout, 0)}
+	today := a.GetCurrentMarketDate()
+
+	a.chartWindowsLock.RLock()
+	tickers := make([]string, 0, len(a.chartWindows))
+	for ticker := range a.chartWindows {
+		tickers = append(tickers, ticker)
+	}
+	sort.Strings(tickers)
+	for _, ticker := range tickers {
+		layout := config.ChartLayout{Ticker: ticker}
+		layout.X, layout.Y = a.chartWindows[ticker].Position()
+		layout.Width, layout.Height = a.chartWindows[ticker].Size()
+		if state := a.chartWindowStates[ticker]; state != nil {
+			// Charts on the current day follow the market date when the workspace is loaded later
+			if state.date != today {
+				layout.Date = state.date
+			}
+			layout.HiddenPlots = state.hiddenPlots
+		}
+		workspace.Charts = append(workspace.Charts, layout)
+	}
+	a.chartWindowsLock.RUnlock()
+
+	if len(workspace.Charts) == 0 {
+		return workspace, fmt.Errorf("no chart windows are open")
+	}
+	if err := workspace.Validate(); err != nil {
+		return workspace, err
+	}
+
+	updated, err := a.settingsManager.GetSettings().Clone()
+	if err != nil {
+		return workspace, err
+	}
+	if i := updated.FindWorkspace(workspace.Name); i >= 0 {
+		updated.Workspaces[i] = workspace
+	} else {
+		updated.Workspaces = append(updated.Workspaces, workspace)
+	}
+	updated.LastWorkspace = workspace.Name
+	if err := a.SaveSettings(updated); err != nil {
+		return workspace, err
+	}
+	a.debugPrint(fmt.Sprintf("Workspace: Saved %q with %d chart(s)", workspace.Name, len(workspace.Charts)), "app")
+	return workspace, nil
+}
+
+// LoadWorkspace closes chart windows that aren't part of a workspace and opens its charts at their saved
+// positions; the workspace is reopened at the next startup
+func (a *App) LoadWorkspace(name string) error {
+	return a.loadWorkspace(name, true)
+}
+
+// loadWorkspace is LoadWorkspace; remember saves it as last_workspace (false when restoring at startup)
+func (a *App) loadWorkspace(name string, remember bool) error {
+	settings := a.settingsManager.GetSettings()
+	i := settings.FindWorkspace(name)
+	if i < 0 {
+		return fmt.Errorf("workspace not found: %s", name)
+	}
+	workspace := settings.Workspaces[i]
+
+	keep := make(map[string]bool, len(workspace.Charts))
+	for _, chart := range workspace.Charts {
+		keep[chart.Ticker] = true
+	}
+	a.chartWindowsLock.RLock()
+	others := make([]*application.WebviewWindow, 0)
+	for ticker, window := range a.chartWindows {
+		if !keep[ticker] {
+			others = append(others, window)
+		}
+	}
+	a.chartWindowsLock.RUnlock()
+	for _, window := range others {
+		window.Close() // WindowClosing unregisters the ticker
+	}
+
+	failed := make([]string, 0)
+	for _, chart := range workspace.Charts {
+		layout := chart
+		if err := a.openChartWindow(chart.Ticker, chart.Date, &layout); err != nil {
+			a.debugPrint(fmt.Sprintf("Workspace: Failed to open %s: %v", chart.Ticker, err), "error")
+			failed = append(failed, chart.Ticker)
+		}
+	}
+	a.debugPrint(fmt.Sprintf("Workspace: Loaded %q (%d chart(s))", workspace.Name, len(workspace.Charts)-len(failed)), "app")
+
+	if remember && settings.LastWorkspace != workspace.Name {
+		updated, err := settings.Clone()
+		if err != nil {
+			return err
+		}
+		updated.LastWorkspace = workspace.Name
+		if err := a.SaveSettings(updated); err != nil {
+			return err
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to open charts for: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// DeleteWorkspace removes a saved workspace (open chart windows are left as they are)
+func (a *App) DeleteWorkspace(name string) error {
+	updated, err := a.settingsManager.GetSettings().Clone()
+	if err != nil {
+		return err
+	}
+	i := updated.FindWorkspace(name)
+	if i < 0 {
+		return fmt.Errorf("workspace not found: %s", name)
+	}
+	if strings.EqualFold(updated.LastWorkspace, updated.Workspaces[i].Name) {
+		updated.LastWorkspace = ""
+	}
+	updated.Workspaces = append(updated.Workspaces[:i], updated.Workspaces[i+1:]...)
+	return a.SaveSettings(updated)
+}
+
+// GetChartHiddenPlots returns the plots hidden in a chart window (nil = use the hidden_plots setting)
+// Chart windows read it on load so a workspace restores their legend state
+func (a *App) GetChartHiddenPlots(ticker string) []string {
+	a.chartWindowsLock.RLock()
+	defer a.chartWindowsLock.RUnlock()
+	if state := a.chartWindowStates[ticker]; state != nil {
+		return state.hiddenPlots
+	}
+	return nil
+}
+
+// SetChartHiddenPlots records the plots hidden in a chart window (called when its legend is toggled)
+func (a *App) SetChartHiddenPlots(ticker string, plots []string) {
+	a.chartWindowsLock.Lock()
+	defer a.chartWindowsLock.Unlock()
+	if state := a.chartWindowStates[ticker]; state != nil {
+		state.hiddenPlots = plots
+	}
+}
+
+// GetTickerData loads ticker data from the database
+// dateStr is in format "2006-01-02" (YYYY-MM-DD)
+// Returns map[string][]interface{} where each key is a field name and value is an array of values
+// Returns empty data if database doesn't exist yet (data collection hasn't started)
+// The current market date is served from the coordinator's LatestStore (one-element arrays, no database read)
+// CRITICAL: Uses LoadTickerData instead of LoadFromFile to skip profiles_blob and prevent memory issues
+func (a *App) GetTickerData(ticker string, dateStr string) (map[string]interface{}, error) {
+	if latest := a.latestTickerData(ticker, dateStr); latest != nil {
+		return latest, nil
+	}
+
+	// Log memory usage before loading data
+	var mBefore runtime.MemStats
+	runtime.ReadMemStats(&mBefore)
+	a.debugPrint(fmt.Sprintf("GetTickerData: Memory before loading %s: Alloc=%d MB, Sys=%d MB, HeapAlloc=%d MB", 
+		ticker, mBefore.Alloc/1024/1024, mBefore.Sys/1024/1024, mBefore.HeapAlloc/1024/1024), "memory")
+	
+	// Parse date string in ET (not UTC)
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		// Try current market date if parsing fails
+		date = utils.GetMarketDate()
+		// Extract just the date part at midnight ET
+		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, utils.GetMarketTimezone())
+	}
+	
+	// Load data using lightweight LoadTickerData (skips profiles_blob)
+	// This prevents massive memory usage from decompressing profiles
+	data, err := a.dataLoader.LoadTickerData(ticker, date)
+	if err != nil {
+		// If there's an actual error (not just missing file), return it
+		a.debugPrint(fmt.Sprintf("GetTickerData: Error loading data for %s: %v", ticker, err), "error")
+		return nil, err
+	}
+	
+	// Convert to map[string]interface{} for JSON serialization
+	result := make(map[string]interface{})
+	for k, v := range data {
+		result[k] = v
+	}
+	
+	// If no data, return empty structure (not nil)
+	if len(result) == 0 {
+		// Return structure with empty arrays for expected fields
+		result["timestamp"] = []interface{}{}
+		result["spot"] = []interface{}{}
+		result["zero_gamma"] = []interface{}{}
+		result["major_pos_vol"] = []interface{}{}
+		result["major_neg_vol"] = []interface{}{}
+	}
+	a.seedLatestTickerData(ticker, date.Format("2006-01-02"), result)
+	
+	// Log memory usage after loading data
+	var mAfter runtime.MemStats
+	runtime.ReadMemStats(&mAfter)
+	memDelta := int64(mAfter.Alloc) - int64(mBefore.Alloc)
+	a.debugPrint(fmt.Sprintf("GetTickerData: Memory after loading %s: Alloc=%d MB, Sys=%d MB, HeapAlloc=%d MB, Delta=+%d MB", 
+		ticker, mAfter.Alloc/1024/1024, mAfter.Sys/1024/1024, mAfter.HeapAlloc/1024/1024, memDelta/1024/1024), "memory")
+	
+	return result, nil
+}
+
+// latestTickerData returns a ticker's latest row from memory as one-element arrays (nil if not in the store)
+func (a *App) latestTickerData(ticker, dateStr string) map[string]interface{} {
+	if a.coordinator == nil {
+		return nil
+	}
+	snapshot, ok := a.coordinator.GetLatestStore().Get(ticker, dateStr)
+	if !ok {
+		return nil
+	}
+	result := make(map[string]interface{}, len(snapshot.Fields)+1)
+	for field, value := range snapshot.Fields {
+		result[field] = []interface{}{value}
+	}
+	result["timestamp"] = []interface{}{snapshot.Timestamp}
+	return result
+}
+
+// seedLatestTickerData fills the LatestStore from a database load of the current market date (e.g. after a restart),
+// so later calls for that ticker stay in memory even before its next fetch
+func (a *App) seedLatestTickerData(ticker, dateStr string, data map[string]interface{}) {
+	if a.coordinator == nil {
+		return
+	}
+	timestamps, _ := data["timestamp"].([]interface{})
+	if len(timestamps) == 0 {
+		return
+	}
+	timestamp, ok := timestamps[len(timestamps)-1].(float64)
+	if !ok {
+		return
+	}
+	fields := make(map[string]interface{}, len(data))
+	for field, values := range data {
+		column, _ := values.([]interface{})
+		if field == "timestamp" || len(column) == 0 || column[len(column)-1] == nil {
+			continue
+		}
+		fields[field] = column[len(column)-1]
+	}
+	a.coordinator.GetLatestStore().Seed(ticker, dateStr, timestamp, fields)
+}
+
+// GetTickerDataRange loads ticker data within a time range
+// dateStr is in format "2006-01-02" (YYYY-MM-DD)
+// Returns map[string][]interface{} where each key is a field name and value is an array of values
+func (a *App) GetTickerDataRange(ticker string, dateStr string, startTime, endTime float64) (map[string]interface{}, error) {
+	// Parse date string in ET (not UTC)
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		// Try current market date if parsing fails
+		date = utils.GetMarketDate()
+		// Extract just the date part at midnight ET
+		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, utils.GetMarketTimezone())
+	}
+	
+	// Load data (returns map[string][]interface{})
+	data, err := a.dataLoader.LoadTimeRange(ticker, date, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Convert to map[string]interface{} for JSON serialization
+	result := make(map[string]interface{})
+	for k, v := range data {
+		result[k] = v
+	}
+	
+	return result, nil
+}
+
+// GetTickerDataRangePage loads one page of a time range: up to limit rows with timestamp > after (0 = from the start)
+// dateStr is in format "2006-01-02" (YYYY-MM-DD); pass the returned next_after as after while has_more is set
+// limit <= 0 uses the default page size
+func (a *App) GetTickerDataRangePage(ticker string, dateStr string, startTime, endTime, after float64, limit int) (*database.RangePage, error) {
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		// Try current market date if parsing fails
+		date = utils.GetMarketDate()
+		// Extract just the date part at midnight ET
+		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, utils.GetMarketTimezone())
+	}
+	return a.dataLoader.LoadTimeRangePage(ticker, date, startTime, endTime, after, limit)
+}
+
+// GetProfile returns the full profiles for a ticker at (or just before) a timestamp
+// dateStr is in format "2006-01-02" (YYYY-MM-DD); returns nil if there is no data yet
+func (a *App) GetProfile(ticker string, dateStr string, timestamp float64) (*model.ProfileLadder, error) {
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		// Try current market date if parsing fails
+		date = utils.GetMarketDate()
+		// Extract just the date part at midnight ET
+		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, utils.GetMarketTimezone())
+	}
+
+	return a.dataLoader.LoadProfile(ticker, date, timestamp)
+}
+
+// filterChartData replaces NaN, Inf and 0 values with NaN (no value) per field while maintaining timestamp alignment
+// This prevents vertical lines in charts and reduces memory usage
+// Each field is filtered independently - invalid values are sent as null (Chart.js will skip them)
+func filterChartData(series *model.ChartSeries) {
+	if series.Len() == 0 {
+		return
+	}
+	for name, values := range series.Columns {
+		filtered := make([]float64, series.Len())
+		for i := range filtered {
+			// Note: For spot price, 0 might be valid in rare cases, but we'll filter it for consistency
+			// For all other fields, 0 is invalid
+			if i < len(values) && !math.IsInf(values[i], 0) && values[i] != 0 {
+				filtered[i] = values[i]
+			} else {
+				filtered[i] = math.NaN() // spanGaps: false prevents connecting across it
+			}
+		}
+		series.SetColumn(name, filtered)
+	}
+	// Note: Filtering stats are logged in GetChartData, not here (no access to debugPrint)
+}
+
+// priceLevelFields are the chart fields quoted in price terms (compared with spot by filterPriceOutliers)
+// Other requested fields (OI, flow, custom columns) aren't prices and are left alone
+var priceLevelFields = func() map[string]bool {
+	fields := map[string]bool{"spot_open": true, "spot_high": true, "spot_low": true}
+	for _, col := range database.ChartColumns() {
+		fields[col] = true
+	}
+	return fields
+}()
+
+// filterPriceOutliers nulls values that deviate from spot by more than thresholdPercent
+// A spot is a bad tick when it deviates from both the previous and next valid spot (a real move
+// persists, a spike doesn't); other fields are compared with spot at the same row
+// Returns the number of values removed (thresholdPercent <= 0 disables filtering)
+func filterPriceOutliers(series *model.ChartSeries, thresholdPercent float64) int {
+	spots := series.Column("spot")
+	if spots == nil || thresholdPercent <= 0 {
+		return 0
+	}
+	deviates := func(value, reference float64) bool {
+		return math.Abs(value-reference)/reference*100 > thresholdPercent
+	}
+	nextSpot := func(from int) float64 {
+		for j := from; j < len(spots); j++ {
+			if !math.IsNaN(spots[j]) {
+				return spots[j]
+			}
+		}
+		return 0
+	}
+
+	removed := 0
+	lastSpot := 0.0
+	for i, spot := range spots {
+		if math.IsNaN(spot) {
+			continue
+		}
+		next := nextSpot(i + 1)
+		if lastSpot > 0 && deviates(spot, lastSpot) && (next <= 0 || deviates(spot, next)) {
+			spots[i] = math.NaN()
+			removed++
+			continue
+		}
+		lastSpot = spot
+	}
+
+	for name, values := range series.Columns {
+		if name == "spot" || !priceLevelFields[name] {
+			continue
+		}
+		for i := 0; i < len(values) && i < len(spots); i++ {
+			spot, value := spots[i], values[i]
+			if !math.IsNaN(spot) && !math.IsNaN(value) && spot > 0 && deviates(value, spot) {
+				values[i] = math.NaN()
+				removed++
+			}
+		}
+	}
+	return removed
+}
+
+// GetChartData serves chart data for chart windows
+// Loads data with limits and filters to reduce memory usage
+// Full-day views are served from 1-minute bars when the day has them
+// ticker: Ticker symbol
+// dateStr: Date in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) GetChartData(ticker string, dateStr string) (*model.ChartSeries, error) {
+	return a.loadChartData(a.shutdownCtx, ticker, dateStr, 0, 0, nil)
+}
+
+// GetChartDataInTimezone is GetChartData plus a timezone describing how to label timestamps
+// in tz ("market", "local", "UTC" or an IANA name; empty = the chart_timezone setting): the UTC offset
+// at the start of the day and any DST transitions within it. Timestamps themselves stay Unix seconds
+func (a *App) GetChartDataInTimezone(ticker string, dateStr string, tz string) (*model.ChartSeries, error) {
+	return a.chartDataInTimezone(a.shutdownCtx, ticker, dateStr, tz)
+}
+
+// chartDataInTimezone is GetChartDataInTimezone, cancelled with ctx
+func (a *App) chartDataInTimezone(ctx context.Context, ticker string, dateStr string, tz string) (*model.ChartSeries, error) {
+	info, err := a.chartTimezoneInfo(dateStr, tz, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	data, err := a.loadChartData(ctx, ticker, dateStr, 0, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	data.Timezone = &info
+	return data, nil
+}
+
+// chartTimezoneInfo resolves tz and describes its offsets over a market date (or [startTime, endTime] when endTime > 0)
+func (a *App) chartTimezoneInfo(dateStr string, tz string, startTime, endTime float64) (utils.ChartTimezoneInfo, error) {
+	if tz == "" {
+		tz = a.settingsManager.GetSettings().ChartTimezone
+	}
+	loc, err := utils.ResolveChartTimezone(tz)
+	if err != nil {
+		return utils.ChartTimezoneInfo{}, err
+	}
+
+	var start, end time.Time
+	if endTime > 0 {
+		start, end = time.Unix(int64(startTime), 0), time.Unix(int64(endTime), 0)
+	} else {
+		date, err := utils.ParseDateInET(dateStr)
+		if err != nil {
+			date = utils.GetMarketDate()
+		}
+		start = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, utils.GetMarketTimezone())
+		end = start.AddDate(0, 0, 1)
+	}
+	return utils.GetChartTimezoneInfo(loc, start, end), nil
+}
+
+// GetChartDataRange serves raw (full resolution) chart data between startTime and endTime
+// (Unix seconds) for zoomed chart views
+func (a *App) GetChartDataRange(ticker string, dateStr string, startTime, endTime float64) (*model.ChartSeries, error) {
+	return a.chartDataRange(a.shutdownCtx, ticker, dateStr, startTime, endTime)
+}
+
+// chartDataRange is GetChartDataRange, cancelled with ctx
+func (a *App) chartDataRange(ctx context.Context, ticker string, dateStr string, startTime, endTime float64) (*model.ChartSeries, error) {
+	if endTime <= startTime {
+		return nil, fmt.Errorf("invalid chart range: end (%.0f) must be after start (%.0f)", endTime, startTime)
+	}
+	return a.loadChartData(ctx, ticker, dateStr, startTime, endTime, nil)
+}
+
+// ExportChartImage renders a ticker's chart for a market date to a PNG or SVG file and returns its path
+// The image uses the active theme and chart timezone, at the requested resolution, with optional
+// watermark and render timestamp (for sharing setups, e.g. in Discord)
+func (a *App) ExportChartImage(ticker string, dateStr string, options charts.ImageOptions) (string, error) {
+	if err := options.Normalize(); err != nil {
+		return "", err
+	}
+	data, err := a.loadChartData(a.shutdownCtx, ticker, dateStr, 0, 0, nil)
+	if err != nil {
+		return "", err
+	}
+
+	settings := a.settingsManager.GetSettings()
+	loc, err := utils.ResolveChartTimezone(settings.ChartTimezone)
+	if err != nil {
+		loc = utils.GetMarketTimezone()
+	}
+	chartImage := charts.ChartImageData{
+		Title:    fmt.Sprintf("%s %s", ticker, dateStr),
+		Series:   make(map[string][]float64),
+		Location: loc,
+	}
+	chartImage.Timestamps = data.Timestamps
+	for _, name := range database.ChartColumns() {
+		for _, value := range data.Column(name) {
+			if !math.IsNaN(value) {
+				chartImage.Series[name] = data.Column(name)
+				break
+			}
+		}
+	}
+
+	encoded, err := charts.RenderChartImage(chartImage, options, settings.GetTheme())
+	if err != nil {
+		return "", err
+	}
+
+	path := options.Path
+	if path == "" {
+		configDir, err := config.GetConfigDir()
+		if err != nil {
+			return "", err
+		}
+		exportDir := filepath.Join(configDir, config.ChartExportDirName)
+		if err := os.MkdirAll(exportDir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create export directory: %w", err)
+		}
+		name := fmt.Sprintf("%s_%s_%s.%s", ticker, dateStr, time.Now().Format("150405"), options.Format)
+		path = filepath.Join(exportDir, name)
+	}
+	if err := os.WriteFile(path, encoded, 0644); err != nil {
+		return "", fmt.Errorf("failed to write chart image: %w", err)
 	}
-	
-	// Load data (returns map[string][]interface{})
-	data, err := a.dataLoader.LoadTimeRange(ticker, date, startTime, endTime)
-	if err != nil {
-		return nil, err
+
+	a.debugPrint(fmt.Sprintf("ExportChartImage: Exported %s %s (%dx%d %s) to %s", ticker, dateStr, options.Width, options.Height, options.Format, path), "app")
+	return path, nil
+}
+
+// captureChartSnapshots renders the configured tickers' charts into <snapshot dir>/<date>/TICKER_HHMM.png
+// Called by the snapshot scheduler at each capture time; emits "snapshots:captured" with the written paths
+func (a *App) captureChartSnapshots(marketDate time.Time, slot string) {
+	tickers := a.settingsManager.GetSettings().ChartSnapshots.Tickers
+	if len(tickers) == 0 && a.chartTracker != nil {
+		tickers = a.chartTracker.GetDisplayedTickers()
 	}
-	
-	// Convert to map[string]interface{} for JSON serialization
-	result := make(map[string]interface{})
-	for k, v := range data {
-		result[k] = v
+	a.captureSnapshots(tickers, marketDate, slot)
+}
+
+// SnapshotAllCharts captures every open chart now (the snapshot_all hotkey), using the chart_snapshots
+// directory, format and size
+func (a *App) SnapshotAllCharts() error {
+	if a.chartTracker == nil || len(a.chartTracker.GetDisplayedTickers()) == 0 {
+		return fmt.Errorf("no chart windows are open")
 	}
-	
-	return result, nil
+	a.captureSnapshots(a.chartTracker.GetDisplayedTickers(), utils.GetMarketDate(), utils.NowMarketTime().Format("15:04:05"))
+	return nil
 }
 
-// filterChartData filters out NaN and 0 values per-field while maintaining timestamp alignment
-// This prevents vertical lines in charts and reduces memory usage
-// Each field is filtered independently - invalid values are replaced with nil (Chart.js will skip them)
-func filterChartData(data map[string][]interface{}) map[string][]interface{} {
-	timestamps, hasTimestamps := data["timestamp"]
-	if !hasTimestamps || len(timestamps) == 0 {
-		return data
+// captureSnapshots renders tickers' charts for a capture slot ("HH:MM", or "HH:MM:SS" for manual captures)
+func (a *App) captureSnapshots(tickers []string, marketDate time.Time, slot string) {
+	snapshots := a.settingsManager.GetSettings().ChartSnapshots
+	if len(tickers) == 0 {
+		a.debugPrint(fmt.Sprintf("Chart snapshots: nothing to capture at %s (no tickers configured and no chart windows open)", slot), "app")
+		return
 	}
-	
-	filtered := make(map[string][]interface{})
-	
-	// Always include timestamps (no filtering)
-	filtered["timestamp"] = timestamps
-	
-	// Filter each field independently (replace NaN/zero with nil)
-	for key, values := range data {
-		if key == "timestamp" {
+
+	baseDir := snapshots.Directory
+	if baseDir == "" {
+		configDir, err := config.GetConfigDir()
+		if err != nil {
+			a.debugPrint(fmt.Sprintf("Chart snapshots: %v", err), "error")
+			return
+		}
+		baseDir = filepath.Join(configDir, config.SnapshotDirName)
+	}
+	dateStr := marketDate.Format("2006-01-02")
+	dayDir := filepath.Join(baseDir, dateStr)
+	if err := os.MkdirAll(dayDir, 0755); err != nil {
+		a.debugPrint(fmt.Sprintf("Chart snapshots: failed to create %s: %v", dayDir, err), "error")
+		return
+	}
+
+	options := charts.ImageOptions{
+		Format:    snapshots.Format,
+		Width:     snapshots.Width,
+		Height:    snapshots.Height,
+		Watermark: snapshots.Watermark,
+		Timestamp: true,
+	}
+	if err := options.Normalize(); err != nil {
+		a.debugPrint(fmt.Sprintf("Chart snapshots: %v", err), "error")
+		return
+	}
+
+	paths := make([]string, 0, len(tickers))
+	for _, ticker := range tickers {
+		options.Path = filepath.Join(dayDir, fmt.Sprintf("%s_%s.%s", ticker, strings.ReplaceAll(slot, ":", ""), options.Format))
+		path, err := a.ExportChartImage(ticker, dateStr, options)
+		if err != nil {
+			a.debugPrint(fmt.Sprintf("Chart snapshots: %s at %s failed: %v", ticker, slot, err), "error")
 			continue
 		}
-		
-		filtered[key] = make([]interface{}, len(timestamps))
-		for i := 0; i < len(timestamps) && i < len(values); i++ {
-			val := values[i]
-			
-			// Check for nil
-			if val == nil {
-				filtered[key][i] = nil
-				continue
-			}
-			
-			// Check for NaN, Inf, or 0
-			// Note: For spot price, 0 might be valid in rare cases, but we'll filter it for consistency
-			// For all other fields, 0 is invalid
-			if f, ok := val.(float64); ok {
-				if math.IsNaN(f) || math.IsInf(f, 0) || f == 0 {
-					filtered[key][i] = nil // Chart.js will skip nil values (spanGaps: false prevents connections)
-				} else {
-					filtered[key][i] = val
-				}
-			} else {
-				// Non-float value - keep as-is
-				filtered[key][i] = val
-			}
+		paths = append(paths, path)
+	}
+	a.debugPrint(fmt.Sprintf("Chart snapshots: captured %d/%d chart(s) for %s %s", len(paths), len(tickers), dateStr, slot), "app")
+	emitEvent("snapshots:captured", map[string]interface{}{
+		"date":  dateStr,
+		"time":  slot,
+		"paths": paths,
+	})
+}
+
+// GetChartDataFields serves only the requested columns (timestamp is always included), e.g.
+// ["spot"] for sparklines or extra OI/flow columns for heavier views
+// endTime = 0 loads the full day; otherwise raw rows between startTime and endTime (Unix seconds)
+// Unknown fields return an error wrapping database.ErrUnknownChartField
+func (a *App) GetChartDataFields(ticker string, dateStr string, startTime, endTime float64, fields []string) (*model.ChartSeries, error) {
+	return a.chartDataFields(a.shutdownCtx, ticker, dateStr, startTime, endTime, fields)
+}
+
+// chartDataFields is GetChartDataFields, cancelled with ctx
+func (a *App) chartDataFields(ctx context.Context, ticker string, dateStr string, startTime, endTime float64, fields []string) (*model.ChartSeries, error) {
+	if endTime > 0 && endTime <= startTime {
+		return nil, fmt.Errorf("invalid chart range: end (%.0f) must be after start (%.0f)", endTime, startTime)
+	}
+	cleaned := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			cleaned = append(cleaned, field)
 		}
 	}
-	
-	// Note: Filtering stats are logged in GetChartData, not here (no access to debugPrint)
-	return filtered
+	if len(cleaned) == 0 {
+		return nil, fmt.Errorf("%w: no fields requested", database.ErrUnknownChartField)
+	}
+	return a.loadChartData(ctx, ticker, dateStr, startTime, endTime, cleaned)
 }
 
-// GetChartData serves chart data for chart windows
-// Loads data with limits and filters to reduce memory usage
-// ticker: Ticker symbol
-// dateStr: Date in format "2006-01-02" (YYYY-MM-DD)
-func (a *App) GetChartData(ticker string, dateStr string) (map[string]interface{}, error) {
+// requestContext returns a context cancelled when either the HTTP request goes away (chart window
+// closed or navigated) or the app shuts down
+func (a *App) requestContext(requestCtx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(requestCtx)
+	stop := context.AfterFunc(a.shutdownCtx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// loadChartData loads, filters and shapes chart data for a day, or for [startTime, endTime] when endTime > 0
+// fields selects the columns to return (nil = the default chart fields); cancelling ctx abandons the load
+func (a *App) loadChartData(ctx context.Context, ticker string, dateStr string, startTime, endTime float64, fields []string) (*model.ChartSeries, error) {
 	// Log memory usage before loading data
 	var mBefore runtime.MemStats
 	runtime.ReadMemStats(&mBefore)
@@ -907,38 +3419,58 @@ func (a *App) GetChartData(ticker string, dateStr string) (map[string]interface{
 	a.debugPrint(fmt.Sprintf("GetChartData: Parsed date for %s: %s (original: %s, ET: %s)", 
 		ticker, date.Format("2006-01-02"), dateStr, date.Format("2006-01-02 15:04:05 MST")), "app")
 	
-	const maxRows = 30000 // Maximum rows to load (full trading day at 1s = ~23,400)
+	// Maximum rows to load (full trading day at 1s = ~23,400), reduced under memory pressure
+	maxRows := a.memoryMonitor.ChartRowLimit()
 	
 	a.debugPrint(fmt.Sprintf("GetChartData: Loading chart data for %s on %s (max %d rows, skipping profiles)", ticker, dateStr, maxRows), "app")
 	
 	// Load chart data (only required columns, no profiles_blob)
 	// This prevents massive memory usage from decompressing profiles
-	data, err := a.dataLoader.LoadChartData(ticker, date, maxRows)
+	var data map[string][]interface{}
+	if fields != nil {
+		// spot is always loaded - the price filter compares every level with it
+		data, err = a.dataLoader.LoadChartFieldsContext(ctx, ticker, date, startTime, endTime, maxRows, append([]string{"spot"}, fields...))
+	} else {
+		data, err = a.dataLoader.LoadChartFieldsContext(ctx, ticker, date, startTime, endTime, maxRows, nil)
+	}
+	if ctx.Err() != nil {
+		a.debugPrint(fmt.Sprintf("GetChartData: Load of %s on %s cancelled", ticker, dateStr), "app")
+		return nil, ctx.Err()
+	}
 	if err != nil {
 		a.debugPrint(fmt.Sprintf("GetChartData: Error loading data for %s: %v", ticker, err), "error")
 		return nil, err
 	}
+
+	// Days recorded before 1-minute bars existed are served raw - backfill their bars for next time
+	if endTime <= 0 && a.dataWriter != nil && len(data["timestamp"]) > 0 && !a.dataLoader.HasChartBars(ticker, date) {
+		go func() {
+			if err := a.dataWriter.RebuildChartBars(ticker, date); err != nil {
+				a.debugPrint(fmt.Sprintf("GetChartData: 1m bar backfill skipped for %s on %s: %v", ticker, dateStr, err), "app")
+			}
+		}()
+	}
 	
 	// Log data before filtering
-	beforeFilterCount := 0
-	if timestamps, ok := data["timestamp"]; ok {
-		beforeFilterCount = len(timestamps)
-	}
+	series := model.ChartSeriesFromColumns(data)
+	beforeFilterCount := series.Len()
 	a.debugPrint(fmt.Sprintf("GetChartData: Data loaded for %s: %d timestamps before filtering", ticker, beforeFilterCount), "app")
 	
 	// Filter out NaN and 0 values to prevent vertical lines and reduce memory
-	filteredData := filterChartData(data)
+	filterChartData(series)
 	
-	// Log data after filtering
-	afterFilterCount := 0
-	if timestamps, ok := filteredData["timestamp"]; ok {
-		afterFilterCount = len(timestamps)
+	// Filter bad ticks / stray levels using the symbol's price filter threshold (futures vs stocks)
+	threshold := a.settingsManager.GetSettings().PriceFilterThresholdPercent(ticker)
+	if removed := filterPriceOutliers(series, threshold); removed > 0 {
+		a.debugPrint(fmt.Sprintf("GetChartData: Removed %d values beyond %.1f%% price filter for %s", removed, threshold, ticker), "app")
 	}
+	
+	// Log data after filtering
+	afterFilterCount := series.Len()
 	a.debugPrint(fmt.Sprintf("GetChartData: Data filtered for %s: %d timestamps after filtering (removed %d)", ticker, afterFilterCount, beforeFilterCount-afterFilterCount), "app")
 	
 	// Only send required fields to frontend (reduces JSON size and memory)
 	requiredFields := []string{
-		"timestamp",
 		"spot",
 		"zero_gamma",
 		"major_pos_vol",    // Positive gamma
@@ -950,24 +3482,27 @@ func (a *App) GetChartData(ticker string, dateStr string) (map[string]interface{
 		"major_pos_oi",     // Major positive OI
 		"major_neg_oi",     // Major negative OI
 	}
-	result := make(map[string]interface{})
+	if fields != nil {
+		requiredFields = fields
+	}
+	result := model.NewChartSeries(series.Timestamps)
 	for _, field := range requiredFields {
-		if values, ok := filteredData[field]; ok {
-			result[field] = values
+		if values := series.Column(field); values != nil {
+			result.SetColumn(field, values)
 		} else {
-			result[field] = []interface{}{}
+			result.SetColumn(field, []float64{})
+		}
+	}
+	// 1-minute bars also carry each bar's spot range
+	for _, field := range []string{"spot_open", "spot_high", "spot_low"} {
+		if values := series.Column(field); values != nil && fields == nil {
+			result.SetColumn(field, values)
 		}
 	}
 	
 	// Log filtering results
-	originalCount := 0
-	if timestamps, ok := data["timestamp"]; ok {
-		originalCount = len(timestamps)
-	}
-	filteredCount := 0
-	if timestamps, ok := result["timestamp"].([]interface{}); ok {
-		filteredCount = len(timestamps)
-	}
+	originalCount := beforeFilterCount
+	filteredCount := result.Len()
 	
 	if originalCount > 0 {
 		a.debugPrint(fmt.Sprintf("GetChartData: Filtered %s: %d -> %d points (removed %d invalid)", 
@@ -977,12 +3512,11 @@ func (a *App) GetChartData(ticker string, dateStr string) (map[string]interface{
 	}
 	
 	// Return empty structure if no valid data
-	if len(result) == 0 || filteredCount == 0 {
-		result["timestamp"] = []interface{}{}
-		result["spot"] = []interface{}{}
-		result["zero_gamma"] = []interface{}{}
-		result["major_pos_vol"] = []interface{}{}
-		result["major_neg_vol"] = []interface{}{}
+	if fields == nil && filteredCount == 0 {
+		result.Timestamps = []float64{}
+		for _, field := range []string{"spot", "zero_gamma", "major_pos_vol", "major_neg_vol"} {
+			result.SetColumn(field, []float64{})
+		}
 	}
 	
 	// Log memory usage after loading data
@@ -995,6 +3529,349 @@ func (a *App) GetChartData(ticker string, dateStr string) (map[string]interface{
 	return result, nil
 }
 
+// GetMemoryStatus returns process memory usage against the memory budget
+func (a *App) GetMemoryStatus() metrics.MemoryStatus {
+	return a.memoryMonitor.Check()
+}
+
+// DiskSpaceStatus is free space on the data volume plus whether the writer dropped to essential columns
+type DiskSpaceStatus struct {
+	metrics.DiskStatus
+	EssentialOnly bool `json:"essential_only"` // New rows keep chart columns only, no profiles
+}
+
+// GetDiskSpaceStatus returns free space on the data directory's volume against the disk_space thresholds
+func (a *App) GetDiskSpaceStatus() DiskSpaceStatus {
+	status := DiskSpaceStatus{DiskStatus: a.diskMonitor.Check()}
+	if a.dataWriter != nil {
+		status.EssentialOnly = a.dataWriter.EssentialOnly()
+	}
+	return status
+}
+
+// GetDailyStats returns the session summary for a ticker (main window summary card)
+// dateStr is in format "2006-01-02" (YYYY-MM-DD)
+// Uses stats persisted at end of day when available, otherwise computes them from the database
+// Returns nil if there is no data for that day
+func (a *App) GetDailyStats(ticker string, dateStr string) (*database.DailyStats, error) {
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		// Try current market date if parsing fails
+		date = utils.GetMarketDate()
+		// Extract just the date part at midnight ET
+		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, utils.GetMarketTimezone())
+	}
+
+	stats, err := a.dataLoader.LoadDailyStats(ticker, date)
+	if err != nil {
+		a.debugPrint(fmt.Sprintf("GetDailyStats: Failed to load persisted stats for %s: %v", ticker, err), "error")
+	}
+	if stats != nil {
+		return stats, nil
+	}
+
+	return a.dataLoader.ComputeDailyStats(ticker, date)
+}
+
+// AddAnnotation pins a note to a ticker's chart for a market date (e.g. "FOMC 14:00", "entered short")
+// timestamp is the chart time in Unix seconds (0 = now); dateStr is "2006-01-02" (empty = current market date)
+func (a *App) AddAnnotation(ticker string, dateStr string, timestamp float64, text string) (*database.Annotation, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("annotation text is required")
+	}
+
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		// Try current market date if parsing fails
+		date = utils.GetMarketDate()
+		// Extract just the date part at midnight ET
+		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, utils.GetMarketTimezone())
+	}
+	if timestamp <= 0 {
+		timestamp = float64(time.Now().Unix())
+	}
+
+	if a.readOnly {
+		return nil, errReadOnly
+	}
+	return a.dataWriter.AddAnnotation(ticker, date, timestamp, text)
+}
+
+// GetAnnotations returns a ticker's annotations for a market date, ordered by chart time
+func (a *App) GetAnnotations(ticker string, dateStr string) ([]database.Annotation, error) {
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		// Try current market date if parsing fails
+		date = utils.GetMarketDate()
+		// Extract just the date part at midnight ET
+		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, utils.GetMarketTimezone())
+	}
+
+	return a.dataLoader.LoadAnnotations(ticker, date)
+}
+
+// GetMaxChange returns a ticker's max-change history for a market date (the strike with the largest change per
+// *_maxchange endpoint and lookback period, per collection) with the day's top gaining and losing strikes
+func (a *App) GetMaxChange(ticker string, dateStr string) (*database.MaxChangeDay, error) {
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q: %w", dateStr, err)
+	}
+
+	return a.dataLoader.LoadMaxChange(ticker, date)
+}
+
+// GetLevelCrossEvents returns the times spot crossed zero_gamma or a major level for a market date, oldest first
+// (the session event timeline); events are recorded live by the coordinator
+func (a *App) GetLevelCrossEvents(ticker string, dateStr string) ([]database.LevelCrossEvent, error) {
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q: %w", dateStr, err)
+	}
+
+	return a.dataLoader.LoadLevelCrossEvents(ticker, date)
+}
+
+// GetQualityMarkers returns a ticker's rows with data quality flags (back-filled, dedup merged, delayed fetch)
+// for a market date; charts draw them as markers so patched data can be told apart from clean data
+func (a *App) GetQualityMarkers(ticker string, dateStr string) ([]database.QualityMarker, error) {
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		// Try current market date if parsing fails
+		date = utils.GetMarketDate()
+		// Extract just the date part at midnight ET
+		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, utils.GetMarketTimezone())
+	}
+
+	return a.dataLoader.LoadQualityMarkers(ticker, date)
+}
+
+// DeleteAnnotation removes an annotation by ID
+func (a *App) DeleteAnnotation(ticker string, dateStr string, id int64) error {
+	if a.readOnly {
+		return errReadOnly
+	}
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		return fmt.Errorf("invalid date %q: %w", dateStr, err)
+	}
+
+	return a.dataWriter.DeleteAnnotation(ticker, date, id)
+}
+
+// BacktestAlert replays a past date through an alert rule and returns when it would have fired
+// e.g. "would spot crossing zero_gamma have alerted on 2026-01-12?"
+// dateStr is in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) BacktestAlert(rule alerts.Rule, dateStr string) (*alerts.BacktestResult, error) {
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q: %w", dateStr, err)
+	}
+	if rule.Ticker == "" {
+		return nil, fmt.Errorf("alert rule %q has no ticker", rule.Name)
+	}
+
+	result, err := alerts.Backtest(rule, dateStr, func(columns []string, fn func(float64, map[string]float64) error) error {
+		return a.dataLoader.StreamRows(rule.Ticker, date, columns, fn)
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.debugPrint(fmt.Sprintf("BacktestAlert: %s on %s - %d triggers in %d rows", rule.Ticker, dateStr, len(result.Triggers), result.RowsEvaluated), "app")
+	return result, nil
+}
+
+// verifyAPIBeforeOpen makes one request (the first enabled ticker's first chart endpoint) to check the key
+// and connectivity in the pre-open phase
+func (a *App) verifyAPIBeforeOpen() error {
+	tickers := getEnabledTickers(a.settingsManager.GetSettings())
+	if len(tickers) == 0 {
+		return fmt.Errorf("no tickers enabled")
+	}
+	endpoints := a.queryPlanner.ChartEndpoints()
+	if len(endpoints) == 0 {
+		return fmt.Errorf("no chart endpoints for the subscription tiers")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(config.PreOpenVerifyTimeoutSec)*time.Second)
+	defer cancel()
+	_, err := a.querySystem.GetClient().FetchEndpointContext(ctx, endpoints[0], tickers[0])
+	return err
+}
+
+// prepareMarketDate opens every enabled ticker's database for the market date and creates its schema
+// Called by the pre-open watcher before the open
+func (a *App) prepareMarketDate(marketDate time.Time) (int, error) {
+	return a.dataWriter.PrepareDate(getEnabledTickers(a.settingsManager.GetSettings()), marketDate)
+}
+
+// warmMarketOpen re-opens today's databases at the open (the pool closes idle write connections within
+// seconds) while the ticker goroutines' first fetches are in flight
+func (a *App) warmMarketOpen(marketDate time.Time) {
+	if _, err := a.prepareMarketDate(marketDate); err != nil {
+		a.debugPrint(fmt.Sprintf("Market open: %v", err), "error")
+	}
+}
+
+// GetPreOpenStatus returns today's pre-open phase: the countdown to the open, the API check and how many
+// databases were prepared
+func (a *App) GetPreOpenStatus() scheduler.PreOpenStatus {
+	if a.preOpenWatcher == nil {
+		return scheduler.PreOpenStatus{}
+	}
+	return a.preOpenWatcher.Status()
+}
+
+// persistDailyStats computes and stores daily stats for every enabled ticker
+// Called by the market close watcher once the session has closed
+func (a *App) persistDailyStats(marketDate time.Time) {
+	tickers := getEnabledTickers(a.settingsManager.GetSettings())
+	a.debugPrint(fmt.Sprintf("persistDailyStats: Computing daily stats for %d ticker(s) on %s", len(tickers), marketDate.Format("2006-01-02")), "app")
+
+	for _, ticker := range tickers {
+		// Flush pending writes so the stats include the final rows of the session
+		if err := a.dataWriter.FlushTicker(ticker); err != nil {
+			a.debugPrint(fmt.Sprintf("persistDailyStats: Failed to flush %s: %v", ticker, err), "error")
+		}
+
+		stats, err := a.dataLoader.ComputeDailyStats(ticker, marketDate)
+		if err != nil {
+			a.debugPrint(fmt.Sprintf("persistDailyStats: Failed to compute stats for %s: %v", ticker, err), "error")
+			continue
+		}
+		if stats == nil {
+			continue
+		}
+
+		if err := a.dataWriter.SaveDailyStats(ticker, marketDate, stats); err != nil {
+			a.debugPrint(fmt.Sprintf("persistDailyStats: Failed to save stats for %s: %v", ticker, err), "error")
+		}
+	}
+}
+
+// GetEndOfDayReport returns the collection report for a date
+// dateStr is in format "2006-01-02" (YYYY-MM-DD)
+// Returns the saved report if one was written at market close, otherwise generates it on demand
+func (a *App) GetEndOfDayReport(dateStr string) (*reports.DayReport, error) {
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q: %w", dateStr, err)
+	}
+
+	if data, err := os.ReadFile(a.endOfDayReporter.ReportPath(date)); err == nil {
+		var report reports.DayReport
+		if err := json.Unmarshal(data, &report); err == nil {
+			return &report, nil
+		}
+		a.debugPrint(fmt.Sprintf("GetEndOfDayReport: Saved report for %s is invalid, regenerating", dateStr), "error")
+	}
+
+	return a.endOfDayReporter.Generate(date, getEnabledTickers(a.settingsManager.GetSettings())), nil
+}
+
+// encryptCompletedDay encrypts a closed market date's databases if encryption is enabled
+// Called at the market date rollover (the writer no longer files rows under the day) and at startup for a
+// rollover missed while the app wasn't running; a day without plaintext databases is left alone
+func (a *App) encryptCompletedDay(marketDate time.Time) {
+	if a.dataWriter == nil || !a.settingsManager.GetSettings().EncryptCompletedDays {
+		return
+	}
+	if !a.dataWriter.HasPlaintextDay(marketDate) {
+		return
+	}
+
+	key, err := keychain.GetOrCreateKey(config.DatabaseEncryptionKeyAccount, config.DatabaseEncryptionKeySize)
+	if err != nil {
+		a.debugPrint(fmt.Sprintf("encryptCompletedDay: Encryption key unavailable, leaving databases unencrypted: %v", err), "error")
+		return
+	}
+	a.dataLoader.SetEncryptionKey(key)
+
+	// Release read connections before the files are replaced
+	a.dataLoader.ReleaseDay(marketDate)
+	count, err := a.dataWriter.EncryptDay(marketDate, key)
+	if err != nil {
+		a.debugPrint(fmt.Sprintf("encryptCompletedDay: Encrypted %d database(s) for %s, the rest stay in plaintext: %v", count, marketDate.Format("2006-01-02"), err), "error")
+		return
+	}
+	a.debugPrint(fmt.Sprintf("encryptCompletedDay: Encrypted %d database(s) for %s", count, marketDate.Format("2006-01-02")), "system")
+}
+
+// SyncPushDay uploads a completed day's databases to the sync destination
+// dateStr is in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) SyncPushDay(dateStr string) (*datasync.Result, error) {
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q: %w", dateStr, err)
+	}
+	if dateStr == utils.GetMarketDate().Format("2006-01-02") && utils.IsMarketOpen() {
+		return nil, fmt.Errorf("cannot push %s while the market is open", dateStr)
+	}
+	return a.syncer.PushDay(date)
+}
+
+// SyncPullMissing downloads days from the sync destination that don't exist locally
+func (a *App) SyncPullMissing() (*datasync.Result, error) {
+	return a.syncer.PullMissing()
+}
+
+// GetLastSyncResult returns the result of the most recent sync run (nil if none yet)
+func (a *App) GetLastSyncResult() *datasync.Result {
+	return a.syncer.GetLastResult()
+}
+
+// CompactDatabases reclaims free space in all closed (previous-day) databases now
+// The scheduled off-hours pass only covers the last CompactionLookbackDays days
+func (a *App) CompactDatabases() (*database.CompactionResult, error) {
+	if a.readOnly {
+		return nil, errReadOnly
+	}
+	result, err := a.dataWriter.CompactDatabases(0)
+	if err != nil {
+		return nil, err
+	}
+	emitEvent("database:compacted", result)
+	return result, nil
+}
+
+// PruneColumns rewrites previous days' databases keeping only the given scalar columns
+// An empty list keeps the chart columns (what chart-only collection stores); dryRun only reports
+func (a *App) PruneColumns(columns []string, dryRun bool) (*database.PruneResult, error) {
+	if a.readOnly {
+		return nil, errReadOnly
+	}
+	return a.dataWriter.PruneColumns(columns, dryRun)
+}
+
+// runDatabaseMaintenance compacts recent closed databases once per market date while the market is closed
+func (a *App) runDatabaseMaintenance() {
+	var lastRun time.Time
+	ticker := time.NewTicker(time.Duration(config.CompactionCheckIntervalMin) * time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		a.shutdownLock.RLock()
+		shuttingDown := a.shuttingDown
+		a.shutdownLock.RUnlock()
+		if shuttingDown {
+			return
+		}
+
+		marketDate := utils.GetMarketDate()
+		if utils.IsMarketOpen() || marketDate.Equal(lastRun) {
+			continue
+		}
+		result, err := a.dataWriter.CompactDatabases(config.CompactionLookbackDays)
+		if err != nil {
+			a.debugPrint(fmt.Sprintf("Scheduled compaction skipped: %v", err), "writer")
+			continue
+		}
+		lastRun = marketDate
+		if result.Databases > 0 {
+			emitEvent("database:compacted", result)
+		}
+	}
+}
+
 // GetCurrentMarketDate returns the current market date in Eastern Time as "YYYY-MM-DD"
 // Date rolls over at 8:30 AM ET (1 hour before market open)
 func (a *App) GetCurrentMarketDate() string {
@@ -1003,66 +3880,54 @@ func (a *App) GetCurrentMarketDate() string {
 }
 
 // GetAvailableDates returns a list of available dates (newest first) from data directories
-// Scans for directories matching "Tickers MM.DD.YYYY" pattern
+// Scans the day directories of the configured layout ("Tickers MM.DD.YYYY" or "Tickers/YYYY/MM/DD")
 // Returns dates in "YYYY-MM-DD" format, sorted newest first
 func (a *App) GetAvailableDates() []string {
-	settings := a.settingsManager.GetSettings()
-	dataDir := settings.DataDirectory
-	if dataDir == "" {
-		dataDir = "Tickers"
-	}
-	
-	prefix := dataDir + " "
-	currentDir, err := os.Getwd()
-	if err != nil {
-		a.debugPrint(fmt.Sprintf("GetAvailableDates: Failed to get current directory: %v", err), "error")
-		return []string{}
+	return a.availableDates(func(name string) bool {
+		return strings.HasSuffix(name, ".db") || strings.HasSuffix(name, ".db.enc")
+	})
+}
+
+// GetAvailableDatesForTicker returns the dates (newest first) with a database for a ticker,
+// including days recorded under an older name of the symbol (ticker_aliases)
+func (a *App) GetAvailableDatesForTicker(ticker string) []string {
+	files := make(map[string]bool)
+	for _, name := range a.settingsManager.GetSettings().TickerFileNames(ticker) {
+		files[name+".db"] = true
+		files[name+".db.enc"] = true
 	}
-	
-	entries, err := os.ReadDir(currentDir)
+	return a.availableDates(func(name string) bool {
+		return files[name]
+	})
+}
+
+// availableDates returns the dates (newest first) whose day directory has a file matching hasFile
+func (a *App) availableDates(hasFile func(name string) bool) []string {
+	settings := a.settingsManager.GetSettings()
+	days, err := settings.ListDayDirectories(utils.GetMarketTimezone())
 	if err != nil {
-		a.debugPrint(fmt.Sprintf("GetAvailableDates: Failed to read directory: %v", err), "error")
+		a.debugPrint(fmt.Sprintf("GetAvailableDates: Failed to list data directories: %v", err), "error")
 		return []string{}
 	}
 	
 	var availableDates []time.Time
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-		
-		name := entry.Name()
-		if !strings.HasPrefix(name, prefix) {
-			continue
-		}
-		
-		// Extract date string (format: "MM.DD.YYYY")
-		dateStr := strings.TrimPrefix(name, prefix)
-		
-		// Parse date
-		date, err := time.Parse("01.02.2006", dateStr)
-		if err != nil {
-			// Skip invalid date formats
-			continue
-		}
-		
+	for _, day := range days {
 		// Check if directory has any database files
-		dirPath := filepath.Join(currentDir, name)
-		files, err := os.ReadDir(dirPath)
+		files, err := os.ReadDir(day.Path)
 		if err != nil {
 			continue
 		}
 		
 		hasData := false
 		for _, file := range files {
-			if !file.IsDir() && strings.HasSuffix(file.Name(), ".db") {
+			if !file.IsDir() && hasFile(file.Name()) {
 				hasData = true
 				break
 			}
 		}
 		
 		if hasData {
-			availableDates = append(availableDates, date)
+			availableDates = append(availableDates, day.Date)
 		}
 	}
 	
@@ -1080,6 +3945,39 @@ func (a *App) GetAvailableDates() []string {
 	return result
 }
 
+// PreloadDates warms the latest-row cache for the given tickers and dates (call when the date picker opens)
+// tickers defaults to the enabled tickers; dates are "YYYY-MM-DD" (invalid entries are skipped)
+// Returns the number of ticker/date pairs warmed
+func (a *App) PreloadDates(tickers []string, dates []string) int {
+	if len(tickers) == 0 {
+		tickers = getEnabledTickers(a.settingsManager.GetSettings())
+	}
+
+	parsed := make([]time.Time, 0, len(dates))
+	for _, dateStr := range dates {
+		date, err := utils.ParseDateInET(dateStr)
+		if err != nil {
+			a.debugPrint(fmt.Sprintf("PreloadDates: Skipping invalid date '%s': %v", dateStr, err), "app")
+			continue
+		}
+		parsed = append(parsed, date)
+	}
+
+	return a.dataLoader.PreloadDates(tickers, parsed)
+}
+
+// warmTodayDatabases reads the latest row of each enabled ticker's current-day database (opening its
+// connection and introspecting its columns) and seeds the LatestStore with it
+func (a *App) warmTodayDatabases() {
+	defer crash.Recover("warmup")
+	marketDate := utils.GetMarketDate()
+	date := time.Date(marketDate.Year(), marketDate.Month(), marketDate.Day(), 0, 0, 0, 0, utils.GetMarketTimezone())
+	dateStr := date.Format("2006-01-02")
+	a.dataLoader.WarmDate(getEnabledTickers(a.settingsManager.GetSettings()), date, func(ticker string, data map[string]interface{}) {
+		a.seedLatestTickerData(ticker, dateStr, data)
+	})
+}
+
 // GetMarketHoursLocal returns market open and close times in user's local timezone
 // Returns (openTime, closeTime) as "HH:MM" format strings
 // Note: Since we can't determine the user's browser timezone from Go, we return ET times
@@ -1099,6 +3997,17 @@ func (a *App) GetMarketHoursLocal() (string, string) {
 	return openStr, closeStr
 }
 
+// GetMarketUTCOffset returns the market timezone's UTC offset on a market date ("-05:00" EST, "-04:00" EDT)
+// so the frontend can convert GetMarketHoursLocal's ET times on DST dates; empty dateStr = current market date
+func (a *App) GetMarketUTCOffset(dateStr string) string {
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		date = utils.GetMarketDate()
+	}
+	marketOpenET, _ := utils.MarketOpenCloseTimes(date)
+	return marketOpenET.Format("-07:00")
+}
+
 // IsMarketOpen checks if the market is currently open
 func (a *App) IsMarketOpen() bool {
 	// MISSION CRITICAL: Log immediately when function is called
@@ -1293,6 +4202,54 @@ func createWindowFromApp(appRef interface{}, options application.WebviewWindowOp
 	return nil
 }
 
+// screensFunc is set from main.go to list the attached screens
+var screensFunc func() []*application.Screen
+
+// SetScreensFunc sets the function to list the attached screens (called from main.go)
+func SetScreensFunc(fn func() []*application.Screen) {
+	screensFunc = fn
+}
+
+// currentMonitors lists the attached screens for window placement (nil before the app is running)
+func currentMonitors() []placement.Monitor {
+	if screensFunc == nil {
+		return nil
+	}
+	screens := screensFunc()
+	monitors := make([]placement.Monitor, 0, len(screens))
+	for _, screen := range screens {
+		if screen == nil {
+			continue
+		}
+		monitors = append(monitors, placement.Monitor{
+			ID:          screen.ID,
+			Name:        screen.Name,
+			Bounds:      placement.Rect{X: screen.Bounds.X, Y: screen.Bounds.Y, Width: screen.Bounds.Width, Height: screen.Bounds.Height},
+			WorkArea:    placement.Rect{X: screen.WorkArea.X, Y: screen.WorkArea.Y, Width: screen.WorkArea.Width, Height: screen.WorkArea.Height},
+			ScaleFactor: screen.ScaleFactor,
+			Primary:     screen.IsPrimary,
+		})
+	}
+	if len(monitors) == 0 {
+		return nil
+	}
+	return monitors
+}
+
+// emitEventFunc is set from main.go to emit events to the frontend
+var emitEventFunc func(name string, data interface{})
+
+// SetEmitEventFunc sets the function to emit frontend events (called from main.go)
+func SetEmitEventFunc(fn func(name string, data interface{})) {
+	emitEventFunc = fn
+}
+
+func emitEvent(name string, data interface{}) {
+	if emitEventFunc != nil {
+		emitEventFunc(name, data)
+	}
+}
+
 // LogFrontend logs a message from the frontend to the backend console and log file
 // This allows frontend errors to appear in the terminal window
 func (a *App) LogFrontend(level string, message string) {
@@ -1325,6 +4282,46 @@ func (a *App) UnregisterTickerDisplay(ticker string) {
 		a.chartTracker.UnregisterTicker(ticker)
 		a.debugPrint(fmt.Sprintf("Unregistered ticker display: %s", ticker), "system")
 	}
+	// A closed chart's refresh rate no longer applies
+	if a.scheduler != nil && a.scheduler.GetChartRefreshRate(ticker) > 0 {
+		a.scheduler.SetChartRefreshRate(ticker, 0)
+		if a.perTickerScheduler != nil {
+			a.perTickerScheduler.Reschedule()
+		}
+	}
+}
+
+// SetChartRefreshRate lets an open chart window request its own refresh rate for its ticker (ms; 0 = back to
+// the normal rules), e.g. 500ms around the open or 10s for a background chart
+// It applies ahead of the priority matrix and refresh_rate_ms until the chart closes; the rate limit floor still applies
+func (a *App) SetChartRefreshRate(ticker string, ms int) error {
+	if a.scheduler == nil {
+		return fmt.Errorf("data collection is not running")
+	}
+	if ms != 0 && (ms < config.ChartRefreshRateMinMs || ms > config.ChartRefreshRateMaxMs) {
+		return fmt.Errorf("chart refresh rate must be between %dms and %dms (0 = default)", config.ChartRefreshRateMinMs, config.ChartRefreshRateMaxMs)
+	}
+	a.chartWindowsLock.RLock()
+	_, open := a.chartWindows[ticker]
+	a.chartWindowsLock.RUnlock()
+	if !open && ms != 0 {
+		return fmt.Errorf("no chart window is open for %s", ticker)
+	}
+
+	a.scheduler.SetChartRefreshRate(ticker, ms)
+	a.debugPrint(fmt.Sprintf("SetChartRefreshRate: %s chart refresh rate set to %dms", ticker, ms), "app")
+	if a.perTickerScheduler != nil {
+		a.perTickerScheduler.Reschedule()
+	}
+	return nil
+}
+
+// GetChartRefreshRate returns the refresh rate a ticker's chart window requested (ms, 0 = none)
+func (a *App) GetChartRefreshRate(ticker string) int {
+	if a.scheduler == nil {
+		return 0
+	}
+	return a.scheduler.GetChartRefreshRate(ticker)
 }
 
 // SetApp sets the Wails application reference (called from main.go)
@@ -1335,6 +4332,11 @@ func (a *App) SetApp(app interface{}) {
 // OpenChartWindow creates and opens a new chart window for a ticker
 // dateStr is optional - if empty, chart will use current market date
 func (a *App) OpenChartWindow(ticker string, dateStr string) error {
+	return a.openChartWindow(ticker, dateStr, nil)
+}
+
+// openChartWindow opens a chart window, at the layout's position and size when one is given (workspaces)
+func (a *App) openChartWindow(ticker string, dateStr string, layout *config.ChartLayout) error {
 	if a.appRef == nil {
 		return fmt.Errorf("application not initialized")
 	}
@@ -1350,41 +4352,499 @@ func (a *App) OpenChartWindow(ticker string, dateStr string) error {
 	a.chartWindowsLock.Unlock()
 	
 	// Build URL with ticker and optional date parameter
-	url := fmt.Sprintf("/chart.html?ticker=%s", ticker)
-	if dateStr != "" {
-		url += fmt.Sprintf("&date=%s", dateStr)
-	}
+	url := chartWindowURL(ticker, dateStr)
 	
 	// Create new window using chart.html file with ticker and date parameters
 	// The chart.html file will be served by the asset server
-	window := createWindowFromApp(a.appRef, application.WebviewWindowOptions{
+	options := application.WebviewWindowOptions{
 		Title:    fmt.Sprintf("%s Chart", ticker),
-		Width:    1200,
-		Height:   800,
-		MinWidth: 600,
-		MinHeight: 400,
+		Width:    config.ChartWindowDefaultWidth,
+		Height:   config.ChartWindowDefaultHeight,
+		MinWidth: config.ChartWindowMinWidth,
+		MinHeight: config.ChartWindowMinHeight,
 		URL:      url,
 		BackgroundColour: application.NewRGB(30, 30, 30),
-	})
+	}
+	if layout != nil {
+		// Keep workspace windows on a screen when it was saved on a monitor that is no longer attached
+		rect := a.placement.Clamp(placement.Rect{X: layout.X, Y: layout.Y, Width: layout.Width, Height: layout.Height})
+		options.Width, options.Height = rect.Width, rect.Height
+		options.X, options.Y = rect.X, rect.Y
+		options.InitialPosition = application.WindowXY
+	}
+	window := createWindowFromApp(a.appRef, options)
 	
 	if window == nil {
 		return fmt.Errorf("failed to create chart window")
 	}
 	
-	// Store window reference
+	// Store window reference (per-chart options survive reopening the same ticker)
 	a.chartWindowsLock.Lock()
 	a.chartWindows[ticker] = window
+	state := &chartWindowState{date: dateStr, live: dateStr != "" && dateStr == a.marketDateWatcher.Current().Format("2006-01-02")}
+	if previous := a.chartWindowStates[ticker]; previous != nil {
+		state.hiddenPlots = previous.hiddenPlots
+	}
+	if layout != nil {
+		state.hiddenPlots = layout.HiddenPlots
+	}
+	a.chartWindowStates[ticker] = state
 	a.chartWindowsLock.Unlock()
 	
 	// Register ticker as displayed
 	a.RegisterTickerDisplay(ticker)
 	
-	// Note: Window close handling will be done when window is actually closed
-	// We track windows in chartWindows map and clean up on next open if needed
+	// Drop the ticker's display priority when the user closes the window
+	// (handled in a goroutine: Close() may dispatch the event while chartWindowsLock is held)
+	window.OnWindowEvent(events.Common.WindowClosing, func(e *application.WindowEvent) {
+		go a.chartWindowClosed(ticker, window)
+	})
+	window.OnWindowEvent(events.Common.WindowFocus, func(e *application.WindowEvent) {
+		a.recordWindowActivity()
+	})
 	
 	return nil
 }
 
+// chartWindowURL returns the chart page URL for a ticker and optional date ("" = current market date)
+func chartWindowURL(ticker, dateStr string) string {
+	url := fmt.Sprintf("/chart.html?ticker=%s", ticker)
+	if dateStr != "" {
+		url += fmt.Sprintf("&date=%s", dateStr)
+	}
+	return url
+}
+
+// GetRecentLogs returns recent log lines from the in-memory buffer, oldest first
+// category filters by log category ("" = all), level is the minimum level ("info", "warn", "error";
+// "" = all) and limit caps the number of lines (0 = DefaultLogViewerLimit)
+func (a *App) GetRecentLogs(category string, level string, limit int) []utils.LogEntry {
+	if limit <= 0 {
+		limit = config.DefaultLogViewerLimit
+	}
+	return utils.RecentLogs(category, level, limit)
+}
+
+// GetLogCategories returns the log categories currently in the buffer (for the log viewer filter)
+func (a *App) GetLogCategories() []string {
+	return utils.RecentLogCategories()
+}
+
+// OpenLogViewer opens the log viewer window, or focuses it if it is already open
+func (a *App) OpenLogViewer() error {
+	if a.appRef == nil {
+		return fmt.Errorf("application not initialized")
+	}
+
+	a.logWindowLock.Lock()
+	defer a.logWindowLock.Unlock()
+	if a.logWindow != nil {
+		a.logWindow.Show()
+		a.logWindow.Focus()
+		return nil
+	}
+
+	window := createWindowFromApp(a.appRef, application.WebviewWindowOptions{
+		Title:            "Logs",
+		Width:            1000,
+		Height:           600,
+		MinWidth:         500,
+		MinHeight:        300,
+		URL:              "/logs.html",
+		BackgroundColour: application.NewRGB(30, 30, 30),
+	})
+	if window == nil {
+		return fmt.Errorf("failed to create log viewer window")
+	}
+	a.logWindow = window
+	window.OnWindowEvent(events.Common.WindowClosing, func(e *application.WindowEvent) {
+		go func() {
+			a.logWindowLock.Lock()
+			if a.logWindow == window {
+				a.logWindow = nil
+			}
+			a.logWindowLock.Unlock()
+		}()
+	})
+	return nil
+}
+
+// anyWindowFocused reports whether the main window or any chart window has focus
+func (a *App) anyWindowFocused() bool {
+	if a.mainWindow != nil && a.mainWindow.IsFocused() {
+		return true
+	}
+	a.chartWindowsLock.RLock()
+	defer a.chartWindowsLock.RUnlock()
+	for _, window := range a.chartWindows {
+		if window != nil && window.IsFocused() {
+			return true
+		}
+	}
+	return false
+}
+
+// recordWindowActivity is called when any app window gains focus
+func (a *App) recordWindowActivity() {
+	if a.activityMonitor != nil {
+		a.activityMonitor.RecordActivity()
+	}
+}
+
+// setIdle applies idle mode to the scheduler and wakes ticker goroutines so intervals change right away
+func (a *App) setIdle(idle bool) {
+	if a.scheduler != nil {
+		a.scheduler.SetIdle(idle)
+	}
+	if a.perTickerScheduler != nil {
+		a.perTickerScheduler.Reschedule()
+	}
+	emitEvent("collection:idle", idle)
+}
+
+// setEco applies eco mode: stretches polling intervals, batches more rows per flush and suspends the profiler
+func (a *App) setEco(status scheduler.EcoStatus) {
+	multiplier := 0.0
+	if status.Active {
+		multiplier = a.settingsManager.GetSettings().GetEcoIntervalMultiplier()
+	}
+	if a.scheduler != nil {
+		a.scheduler.SetEco(multiplier)
+	}
+	if a.perTickerScheduler != nil {
+		a.perTickerScheduler.Reschedule()
+	}
+	if a.dataWriter != nil {
+		a.dataWriter.SetEcoMode(status.Active)
+	}
+	if a.healthCheck != nil {
+		a.healthCheck.SetEcoMode(status.Active, status.Reason)
+	}
+
+	a.ecoLock.Lock()
+	if status.Active && a.profilerSuspended == "" {
+		a.profilerSuspended = stopProfiler()
+		if a.profilerSuspended != "" {
+			a.debugPrint(fmt.Sprintf("Eco: Profiler suspended (was on %s)", a.profilerSuspended), "system")
+		}
+	} else if !status.Active && a.profilerSuspended != "" {
+		go startProfiler(a.profilerSuspended)
+		a.profilerSuspended = ""
+	}
+	a.ecoLock.Unlock()
+
+	emitEvent("collection:eco", status)
+}
+
+// GetEcoStatus returns whether eco (battery saving) mode is on and why
+func (a *App) GetEcoStatus() scheduler.EcoStatus {
+	if a.ecoMonitor == nil {
+		return scheduler.EcoStatus{Mode: a.settingsManager.GetSettings().GetEcoMode()}
+	}
+	return a.ecoMonitor.Status()
+}
+
+// SetEcoMode sets eco_mode ("auto" = on battery, "on", "off") and applies it immediately
+func (a *App) SetEcoMode(mode string) error {
+	updated, err := a.settingsManager.GetSettings().Clone()
+	if err != nil {
+		return err
+	}
+	updated.EcoMode = mode
+	return a.SaveSettings(updated)
+}
+
+// GetHealthStatus returns the collection health check state (scheduler, stalls, recoveries, eco mode)
+func (a *App) GetHealthStatus() map[string]interface{} {
+	if a.healthCheck == nil {
+		return map[string]interface{}{"is_running": false, "eco_mode": a.GetEcoStatus().Active}
+	}
+	return a.healthCheck.GetStatus()
+}
+
+// chartWindowClosed removes a closed chart window and unregisters its ticker
+// Ignored if the window was already replaced by a newer chart for the same ticker
+func (a *App) chartWindowClosed(ticker string, window *application.WebviewWindow) {
+	a.chartWindowsLock.Lock()
+	current, exists := a.chartWindows[ticker]
+	if !exists || current != window {
+		a.chartWindowsLock.Unlock()
+		return
+	}
+	delete(a.chartWindows, ticker)
+	delete(a.chartWindowStates, ticker)
+	a.chartWindowsLock.Unlock()
+
+	a.debugPrint(fmt.Sprintf("Chart window closed for %s", ticker), "app")
+	a.UnregisterTickerDisplay(ticker)
+}
+
+// reconcileChartWindows periodically unregisters displayed tickers that no longer have a chart window
+// Safety net for close events that never arrive, so a ticker can't keep display priority forever
+func (a *App) reconcileChartWindows() {
+	ticker := time.NewTicker(time.Duration(config.ChartWindowReconcileIntervalSec) * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		a.shutdownLock.RLock()
+		shuttingDown := a.shuttingDown
+		a.shutdownLock.RUnlock()
+		if shuttingDown {
+			return
+		}
+		if a.chartTracker == nil {
+			continue
+		}
+
+		a.chartWindowsLock.RLock()
+		stale := make([]string, 0)
+		for _, displayed := range a.chartTracker.GetDisplayedTickers() {
+			if window, exists := a.chartWindows[displayed]; !exists || window == nil {
+				stale = append(stale, displayed)
+			}
+		}
+		a.chartWindowsLock.RUnlock()
+
+		for _, displayed := range stale {
+			a.debugPrint(fmt.Sprintf("Chart reconciliation: %s has no open chart window", displayed), "app")
+			a.UnregisterTickerDisplay(displayed)
+		}
+	}
+}
+
+// ShowMainWindow shows and focuses the main window (e.g. from the system tray)
+func (a *App) ShowMainWindow() {
+	if a.mainWindow == nil {
+		return
+	}
+	a.mainWindow.Show()
+	a.mainWindow.Restore()
+	a.mainWindow.Focus()
+}
+
+// onSecondInstanceLaunch runs in this instance when the app is launched again (same profile): instead of a
+// second scheduler on the same API quota, the main window comes to the front and --open-chart is honoured here
+func (a *App) onSecondInstanceLaunch(data application.SecondInstanceData) {
+	a.debugPrint(fmt.Sprintf("Second launch detected (args: %v) - activating this instance", data.Args), "app")
+	a.ShowMainWindow()
+	a.handleLaunchArgs(data.Args)
+}
+
+// handleLaunchArgs acts on window-opening launch arguments: --open-chart=TICKER opens that chart (or
+// focuses it when it is already open), and an mgt:// link is opened like OpenDeepLink
+func (a *App) handleLaunchArgs(args []string) {
+	for _, arg := range args {
+		if deeplink.IsLink(arg) {
+			if err := a.OpenDeepLink(arg); err != nil {
+				a.debugPrint(fmt.Sprintf("Launch: %v", err), "error")
+			}
+			continue
+		}
+		if !strings.HasPrefix(arg, config.OpenChartLaunchFlag) {
+			continue
+		}
+		ticker := strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(arg, config.OpenChartLaunchFlag)))
+		if ticker == "" {
+			continue
+		}
+		if err := a.showChart(ticker, ""); err != nil {
+			a.debugPrint(fmt.Sprintf("Launch: Failed to open the %s chart: %v", ticker, err), "error")
+		}
+	}
+}
+
+// OpenDeepLink opens an mgt:// link, e.g. mgt://chart/SPX?date=2026-01-14 opens that day's SPX chart
+func (a *App) OpenDeepLink(raw string) error {
+	link, err := deeplink.Parse(raw)
+	if err != nil {
+		return err
+	}
+	a.debugPrint(fmt.Sprintf("Deep link: %s", raw), "app")
+	if err := a.showChart(link.Ticker, link.Date); err != nil {
+		return fmt.Errorf("failed to open the %s chart: %w", link.Ticker, err)
+	}
+	return nil
+}
+
+// showChart brings a ticker's chart window to the front, opening it when it isn't open
+// A date reopens the window on that day (an open window may show another one)
+func (a *App) showChart(ticker, dateStr string) error {
+	a.chartWindowsLock.RLock()
+	window := a.chartWindows[ticker]
+	a.chartWindowsLock.RUnlock()
+	if window != nil && dateStr == "" {
+		window.Show()
+		window.Focus()
+		return nil
+	}
+	return a.OpenChartWindow(ticker, dateStr)
+}
+
+// registerURLScheme registers mgt:// links with the OS (register_url_scheme, on by default)
+func (a *App) registerURLScheme() {
+	if !a.settingsManager.GetSettings().URLSchemeEnabled() {
+		return
+	}
+	if err := deeplink.Register(); err != nil {
+		a.debugPrint(fmt.Sprintf("Deep links: %s:// not registered: %v", config.URLScheme, err), "app")
+		return
+	}
+	a.debugPrint(fmt.Sprintf("Deep links: %s:// registered", config.URLScheme), "app")
+}
+
+// PauseCollection stops scheduling fetches until ResumeCollection
+// In-flight fetches finish and pending writes stay queued
+func (a *App) PauseCollection() error {
+	if a.readOnly {
+		return errReadOnly
+	}
+	a.shutdownLock.Lock()
+	defer a.shutdownLock.Unlock()
+	if a.collectionPaused {
+		return nil
+	}
+	a.collectionPaused = true
+
+	if a.healthCheck != nil {
+		a.healthCheck.SetPaused(true)
+	}
+	if a.perTickerScheduler != nil {
+		a.perTickerScheduler.Stop()
+	}
+	a.debugPrint("Data collection paused", "system")
+	emitEvent("collection:paused", true)
+	return nil
+}
+
+// ResumeCollection restarts scheduling after PauseCollection
+func (a *App) ResumeCollection() error {
+	if a.readOnly {
+		return errReadOnly
+	}
+	a.shutdownLock.Lock()
+	defer a.shutdownLock.Unlock()
+	if a.collectorLockLost != nil {
+		return errCollectorLockLost
+	}
+	if !a.collectionPaused || a.shuttingDown {
+		return nil
+	}
+	a.collectionPaused = false
+
+	if a.perTickerScheduler != nil {
+		a.perTickerScheduler.Start()
+	}
+	if a.healthCheck != nil {
+		a.healthCheck.SetPaused(false)
+	}
+	a.debugPrint("Data collection resumed", "system")
+	emitEvent("collection:paused", false)
+	return nil
+}
+
+// errCollectorLockLost is returned by ResumeCollection once another instance has taken the data directory over
+var errCollectorLockLost = errors.New("another instance took over the data directory - restart to collect here")
+
+// onCollectorLockLost stops collecting once another instance has taken the data directory lock over (this one's
+// heartbeat lapsed, e.g. the machine slept): the scheduler and fetches stop and the writer drops what it had
+// pending, so the two never write the same day files. Browsing keeps working
+func (a *App) onCollectorLockLost(holder database.CollectorLockInfo) {
+	a.shutdownLock.Lock()
+	a.collectorLockLost = &holder
+	a.collectionPaused = true
+	a.shutdownLock.Unlock()
+
+	if a.healthCheck != nil {
+		a.healthCheck.SetPaused(true)
+	}
+	if a.perTickerScheduler != nil {
+		a.perTickerScheduler.Stop()
+	}
+	if a.marketCloseWatcher != nil {
+		a.marketCloseWatcher.Stop()
+	}
+	if a.preOpenWatcher != nil {
+		a.preOpenWatcher.Stop()
+	}
+	if a.coordinator != nil {
+		a.coordinator.Stop()
+	}
+	dropped := 0
+	if a.dataWriter != nil {
+		dropped = a.dataWriter.Halt()
+	}
+	a.debugPrint(fmt.Sprintf("⚠️ Data directory taken over by PID %d on %s - collection stopped, %d pending row(s) dropped",
+		holder.PID, holder.Host, dropped), "error")
+	emitEvent("collection:paused", true)
+}
+
+// RequestQuit marks the app as quitting so close-to-tray doesn't cancel the main window close
+func (a *App) RequestQuit() {
+	a.shutdownLock.Lock()
+	defer a.shutdownLock.Unlock()
+	a.quitRequested = true
+}
+
+// IsCollectionPaused reports whether collection is paused
+func (a *App) IsCollectionPaused() bool {
+	a.shutdownLock.RLock()
+	defer a.shutdownLock.RUnlock()
+	return a.collectionPaused
+}
+
+// SetHoveredTicker records the ticker row under the cursor in the main window ("" when the cursor leaves)
+// Used by the open_chart hotkey
+func (a *App) SetHoveredTicker(ticker string) {
+	a.hoveredTickerLock.Lock()
+	defer a.hoveredTickerLock.Unlock()
+	a.hoveredTicker = ticker
+}
+
+// GetHotkeyStatus returns each configured shortcut and whether it is registered system-wide
+func (a *App) GetHotkeyStatus() []hotkeys.Status {
+	if a.hotkeys == nil {
+		return []hotkeys.Status{}
+	}
+	return a.hotkeys.Status()
+}
+
+// TriggerHotkeyAction runs a hotkey action (open_chart, pause_collection, snapshot_all)
+// Called for system-wide shortcuts and by the main window when it sees the shortcut itself
+func (a *App) TriggerHotkeyAction(action string) error {
+	var err error
+	switch action {
+	case config.HotkeyOpenChart:
+		a.hoveredTickerLock.Lock()
+		ticker := a.hoveredTicker
+		a.hoveredTickerLock.Unlock()
+		if ticker == "" {
+			return fmt.Errorf("no ticker under the cursor")
+		}
+		err = a.OpenChartWindow(ticker, "")
+	case config.HotkeyPauseCollection:
+		if a.IsCollectionPaused() {
+			err = a.ResumeCollection()
+		} else {
+			err = a.PauseCollection()
+		}
+	case config.HotkeySnapshotAll:
+		err = a.SnapshotAllCharts()
+	default:
+		return fmt.Errorf("unknown hotkey action %q", action)
+	}
+	if err == nil {
+		emitEvent("hotkey:triggered", action)
+	}
+	return err
+}
+
+// runHotkeyAction is the hotkey manager's handler (errors are only logged - there is no caller to return them to)
+func (a *App) runHotkeyAction(action string) {
+	a.debugPrint(fmt.Sprintf("Hotkeys: %s pressed", action), "app")
+	if err := a.TriggerHotkeyAction(action); err != nil {
+		a.debugPrint(fmt.Sprintf("Hotkeys: %s failed: %v", action, err), "app")
+	}
+}
+
 // VerifyDataCollection verifies that data collection is working
 // Returns a map with verification results
 func (a *App) VerifyDataCollection() map[string]interface{} {
@@ -1418,10 +4878,6 @@ func (a *App) VerifyDataCollection() map[string]interface{} {
 	}
 	
 	// Check data directory
-	dataDir := settings.DataDirectory
-	if dataDir == "" {
-		dataDir = "Tickers"
-	}
 	today := time.Now()
 	weekday := today.Weekday()
 	if weekday == time.Saturday {
@@ -1429,8 +4885,7 @@ func (a *App) VerifyDataCollection() map[string]interface{} {
 	} else if weekday == time.Sunday {
 		today = today.AddDate(0, 0, -2)
 	}
-	dateStr := today.Format("01.02.2006")
-	dataDirPath := fmt.Sprintf("%s %s", dataDir, dateStr)
+	dataDirPath := settings.DayDirectory(today)
 	
 	// Check if data directory exists
 	if _, err := os.Stat(dataDirPath); err == nil {
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/database"
+	"market-terminal/internal/metrics"
+	"market-terminal/internal/scheduler"
+	"market-terminal/internal/utils"
+)
+
+// The bindings tests call the methods the frontend calls and compare what it receives (the JSON Wails sends)
+// with the golden files in testdata/bindings, so a renamed field or changed shape fails here instead of in a
+// window. Run with -update after an intended change and review the golden diff
+var updateGolden = flag.Bool("update", false, "rewrite the bindings golden files")
+
+// bindingsTestDate is the market date the chart fixtures are written to
+const bindingsTestDate = "2026-03-10"
+
+// newBindingsTestApp builds an App around settings, a loader and a writer in a temporary directory, with the
+// clock frozen at bindingsTestDate's session (the writer files rows under the current market date)
+// Only the components the bindings under test use are created; the rest stay nil like in read-only mode
+func newBindingsTestApp(t *testing.T) (*App, string) {
+	t.Helper()
+	t.Setenv(config.APIKeyEnvVar, "")
+	dir := t.TempDir()
+
+	now, err := time.ParseInLocation("2006-01-02 15:04", bindingsTestDate+" 10:30", utils.MARKET_TIMEZONE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	utils.SetClock(utils.NewSimulatedClock(now, 0))
+	t.Cleanup(func() { utils.SetClock(nil) })
+
+	settingsManager := config.NewSettingsManager(filepath.Join(dir, "config", "settings.yaml"))
+	settings := config.GetDefaultSettings()
+	settings.DataDirectory = filepath.Join(dir, "data")
+	settingsManager.SetSettings(settings)
+
+	debugPrint := func(string, string) {}
+	dataWriter := database.NewDataWriter(settings, debugPrint)
+	dataLoader := database.NewDataLoader(settings, debugPrint)
+	t.Cleanup(func() {
+		dataWriter.Close()
+		dataLoader.Close()
+	})
+
+	a := &App{
+		ctx:               context.Background(),
+		shutdownCtx:       context.Background(),
+		settingsManager:   settingsManager,
+		dataWriter:        dataWriter,
+		dataLoader:        dataLoader,
+		memoryMonitor:     metrics.NewMemoryMonitor(0, debugPrint),
+		marketDateWatcher: scheduler.NewMarketDateWatcher(debugPrint),
+		enabledTickers:    getEnabledTickers(settings),
+		debugPrint:        debugPrint,
+		chartWindows:      make(map[string]*application.WebviewWindow),
+		chartWindowStates: make(map[string]*chartWindowState),
+	}
+	return a, dir
+}
+
+// assertGolden compares v's JSON with testdata/bindings/<name>.json (paths under dir are written as $TMP)
+func assertGolden(t *testing.T, name, dir string, v interface{}) {
+	t.Helper()
+	got, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatalf("marshal %s: %v", name, err)
+	}
+	escapedDir, _ := json.Marshal(dir)
+	got = bytes.ReplaceAll(got, bytes.Trim(escapedDir, `"`), []byte("$TMP"))
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", "bindings", name+".json")
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run go test -run %s -update to create it): %v", t.Name(), err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s does not match %s (run go test -run %s -update if the change is intended)\ngot:\n%s", name, path, t.Name(), got)
+	}
+}
+
+// writeChartFixture writes a few rows for a ticker on bindingsTestDate, including a bad tick the chart filters drop
+func writeChartFixture(t *testing.T, a *App, ticker string) {
+	t.Helper()
+	date, err := utils.ParseDateInET(bindingsTestDate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	open := date.Add(10 * time.Hour)
+	rows := []map[string]interface{}{
+		{"spot": 5000.0, "zero_gamma": 4980.0, "major_pos_vol": 5050.0, "major_neg_vol": 4950.0},
+		{"spot": 5002.5, "zero_gamma": 4981.0, "major_pos_vol": 5050.0, "major_neg_vol": 4950.0},
+		{"spot": 5001.0, "zero_gamma": 4982.0, "major_pos_vol": 5050.0, "major_neg_vol": 4900.0},
+		{"spot": 9999.0, "zero_gamma": 4982.0}, // Bad tick
+	}
+	for i, row := range rows {
+		timestamp := float64(open.Add(time.Duration(i) * time.Minute).Unix())
+		if err := a.dataWriter.WriteDataEntry(ticker, timestamp, row, false); err != nil {
+			t.Fatalf("write fixture row %d: %v", i, err)
+		}
+	}
+	if err := a.dataWriter.FlushTicker(ticker); err != nil {
+		t.Fatalf("flush fixture: %v", err)
+	}
+}
+
+func TestBindingGetChartData(t *testing.T) {
+	a, dir := newBindingsTestApp(t)
+	writeChartFixture(t, a, "SPX")
+
+	data, err := a.GetChartData("SPX", bindingsTestDate)
+	if err != nil {
+		t.Fatalf("GetChartData: %v", err)
+	}
+	assertGolden(t, "get_chart_data", dir, data)
+
+	// A day without data is an empty series with the base columns, not an error
+	empty, err := a.GetChartData("QQQ", bindingsTestDate)
+	if err != nil {
+		t.Fatalf("GetChartData without data: %v", err)
+	}
+	assertGolden(t, "get_chart_data_empty", dir, empty)
+}
+
+func TestBindingSaveSettings(t *testing.T) {
+	a, dir := newBindingsTestApp(t)
+
+	settings := config.GetDefaultSettings()
+	settings.DataDirectory = a.settingsManager.GetSettings().DataDirectory
+	refreshRate := 2000
+	settings.TickerConfigs = map[string]config.TickerConfig{
+		"SPX": {Display: true, CollectionEnabled: true, Priority: "1", RefreshRateMs: &refreshRate},
+		"QQQ": {Display: true, CollectionEnabled: false, Priority: "2"},
+	}
+	if err := a.SaveSettings(settings); err != nil {
+		t.Fatalf("SaveSettings: %v", err)
+	}
+	assertGolden(t, "save_settings", dir, a.GetSettings())
+	if got := strings.Join(a.enabledTickers, ","); got != "SPX" {
+		t.Errorf("enabled tickers after SaveSettings = %q, want SPX", got)
+	}
+
+	// A rejected save returns the error the settings page shows and leaves the file alone
+	saved, err := os.ReadFile(a.settingsManager.GetConfigPath())
+	if err != nil {
+		t.Fatalf("read saved settings: %v", err)
+	}
+	invalid := config.GetDefaultSettings()
+	invalid.BatchOverlapPolicy = "bogus"
+	err = a.SaveSettings(invalid)
+	if err == nil {
+		t.Fatal("SaveSettings accepted an invalid batch overlap policy")
+	}
+	assertGolden(t, "save_settings_rejected", dir, map[string]string{"error": err.Error()})
+	if after, _ := os.ReadFile(a.settingsManager.GetConfigPath()); !bytes.Equal(after, saved) {
+		t.Error("rejected SaveSettings changed the settings file")
+	}
+}
+
+func TestBindingCompleteSetup(t *testing.T) {
+	a, dir := newBindingsTestApp(t)
+
+	if err := a.CompleteSetup("", nil, nil); err == nil {
+		t.Fatal("CompleteSetup accepted an empty API key")
+	}
+	if err := a.CompleteSetup("test-key", []string{"classic", "state"}, []string{"SPX", "NDX"}); err != nil {
+		t.Fatalf("CompleteSetup: %v", err)
+	}
+	assertGolden(t, "complete_setup", dir, a.GetSettings())
+
+	// The key is written to the file so the next launch doesn't show the setup again
+	reloaded, err := config.NewSettingsManager(a.settingsManager.GetConfigPath()).LoadSettings()
+	if err != nil {
+		t.Fatalf("reload settings: %v", err)
+	}
+	if reloaded.APITKey != "test-key" {
+		t.Errorf("saved API key = %q, want test-key", reloaded.APITKey)
+	}
+}
+
+func TestBindingOpenChartWindow(t *testing.T) {
+	a, dir := newBindingsTestApp(t)
+
+	// chart.html reads the ticker and date from the URL
+	assertGolden(t, "open_chart_window_urls", dir, map[string]string{
+		"live":     chartWindowURL("SPX", ""),
+		"historic": chartWindowURL("ES_SPX", bindingsTestDate),
+	})
+
+	if err := a.OpenChartWindow("SPX", ""); err == nil || err.Error() != "application not initialized" {
+		t.Errorf("OpenChartWindow before the app runs = %v, want application not initialized", err)
+	}
+
+	previous := createWindowFunc
+	t.Cleanup(func() { createWindowFunc = previous })
+	var requested application.WebviewWindowOptions
+	createWindowFunc = func(options application.WebviewWindowOptions) *application.WebviewWindow {
+		requested = options
+		return nil
+	}
+	a.SetApp(struct{}{})
+	if err := a.OpenChartWindow("SPX", bindingsTestDate); err == nil || err.Error() != "failed to create chart window" {
+		t.Errorf("OpenChartWindow without a window = %v, want failed to create chart window", err)
+	}
+	if requested.URL != chartWindowURL("SPX", bindingsTestDate) || requested.Title != "SPX Chart" {
+		t.Errorf("OpenChartWindow requested %q titled %q", requested.URL, requested.Title)
+	}
+	if len(a.chartWindows) != 0 || len(a.chartWindowStates) != 0 {
+		t.Error("a chart window that wasn't created was registered")
+	}
+}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/database"
+	"market-terminal/internal/keychain"
+	"market-terminal/internal/utils"
+)
+
+// cliCommand is a headless subcommand (market-terminal COMMAND [args]) for servers and scripts
+type cliCommand struct {
+	usage string
+	run   func(settings *config.Settings, args []string, options map[string]string) int
+}
+
+var cliCommands = map[string]cliCommand{
+	"list-dates": {"list-dates [TICKER]  - market dates with data (newest first)", runListDates},
+	"export":     {"export TICKER DATE [--columns=a,b] [--out=PATH]  - write a day as CSV (default TICKER_DATE.csv)", runExport},
+	"verify":     {"verify [DATE] [--ticker=TICKER]  - integrity-check databases (every day if no date)", runVerify},
+	"backfill":   {"backfill TICKER DATE  - rebuild the 1m bars and fill NULL columns from the stored profiles", runBackfill},
+	"compact":    {"compact  - reclaim free space in every previous day's database", runCompact},
+}
+
+// findCLICommand returns the subcommand on the command line (the first argument that isn't a --flag)
+func findCLICommand(args []string) (string, []string, bool) {
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if arg == "help" {
+			return arg, nil, true
+		}
+		if _, ok := cliCommands[arg]; ok {
+			return arg, append(append([]string{}, args[:i]...), args[i+1:]...), true
+		}
+		return "", nil, false
+	}
+	return "", nil, false
+}
+
+// runCLI runs a subcommand without a window and returns the process exit code
+func runCLI(settings *config.Settings, name string, args []string) int {
+	command, ok := cliCommands[name]
+	if !ok {
+		printCLIUsage()
+		return 0
+	}
+	if settings == nil {
+		settings = config.GetDefaultSettings()
+	}
+	positional := make([]string, 0, len(args))
+	options := make(map[string]string)
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "--") {
+			positional = append(positional, arg)
+		} else if key, value, _ := strings.Cut(strings.TrimPrefix(arg, "--"), "="); key != "profile" {
+			options[key] = value
+		}
+	}
+	return command.run(settings, positional, options)
+}
+
+// printCLIUsage lists the subcommands
+func printCLIUsage() {
+	names := make([]string, 0, len(cliCommands))
+	for name := range cliCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Println("Usage: market-terminal COMMAND [args] [--profile=NAME]")
+	for _, name := range names {
+		fmt.Printf("  %s\n", cliCommands[name].usage)
+	}
+}
+
+// cliLog sends the database packages' debug output to the log file
+func cliLog(msg, category string) {
+	utils.Logf("[%s] %s", category, msg)
+}
+
+// newCLILoader creates a data loader that can read encrypted days (if the key is in the keychain)
+func newCLILoader(settings *config.Settings) *database.DataLoader {
+	dataLoader := database.NewDataLoader(settings, cliLog)
+	if key, err := keychain.Get(config.DatabaseEncryptionKeyAccount); err == nil {
+		dataLoader.SetEncryptionKey(key)
+	}
+	return dataLoader
+}
+
+// lockForCLI takes the collector lock for a command that writes, so it can't run while the app collects
+func lockForCLI(settings *config.Settings) (*database.CollectorLock, bool) {
+	lock, err := database.AcquireCollectorLock(settings.GetDataDirectory(), cliLog)
+	var inUse *database.DataDirectoryInUseError
+	if errors.As(err, &inUse) {
+		fmt.Fprintf(os.Stderr, "%v - close the app first\n", err)
+		return nil, false
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: collector lock not taken: %v\n", err)
+	}
+	return lock, true
+}
+
+// cliTickerDate reads the TICKER DATE arguments
+func cliTickerDate(command string, args []string) (string, string, bool) {
+	if len(args) != 2 {
+		fmt.Fprintf(os.Stderr, "Usage: market-terminal %s TICKER DATE\n", command)
+		return "", "", false
+	}
+	return strings.ToUpper(strings.TrimSpace(args[0])), strings.TrimSpace(args[1]), true
+}
+
+// dayTickers returns the tickers with a database (plain or encrypted) in a day directory
+func dayTickers(dayPath string) []string {
+	entries, err := os.ReadDir(dayPath)
+	if err != nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	tickers := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".enc")
+		if entry.IsDir() || !strings.HasSuffix(name, ".db") {
+			continue
+		}
+		ticker := strings.TrimSuffix(name, ".db")
+		if !seen[ticker] {
+			seen[ticker] = true
+			tickers = append(tickers, ticker)
+		}
+	}
+	sort.Strings(tickers)
+	return tickers
+}
+
+func runListDates(settings *config.Settings, args []string, options map[string]string) int {
+	days, err := settings.ListDayDirectories(utils.GetMarketTimezone())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list data directories: %v\n", err)
+		return 1
+	}
+	files := make(map[string]bool)
+	if len(args) > 0 {
+		for _, name := range settings.TickerFileNames(strings.ToUpper(args[0])) {
+			files[name] = true
+		}
+	}
+	for i := len(days) - 1; i >= 0; i-- {
+		tickers := dayTickers(days[i].Path)
+		if len(files) > 0 {
+			matching := tickers[:0]
+			for _, ticker := range tickers {
+				if files[ticker] {
+					matching = append(matching, ticker)
+				}
+			}
+			tickers = matching
+		}
+		if len(tickers) > 0 {
+			fmt.Printf("%s  %s\n", days[i].Date.Format("2006-01-02"), strings.Join(tickers, " "))
+		}
+	}
+	return 0
+}
+
+func runExport(settings *config.Settings, args []string, options map[string]string) int {
+	ticker, dateStr, ok := cliTickerDate("export", args)
+	if !ok {
+		return 1
+	}
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid date: %v\n", err)
+		return 1
+	}
+	var columns []string
+	if options["columns"] != "" {
+		columns = strings.Split(options["columns"], ",")
+	}
+	path := options["out"]
+	if path == "" {
+		path = fmt.Sprintf("%s_%s.csv", ticker, date.Format("2006-01-02"))
+	}
+
+	dataLoader := newCLILoader(settings)
+	defer dataLoader.Close()
+	file, err := os.Create(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		return 1
+	}
+	rows, err := dataLoader.ExportCSV(ticker, date, columns, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		return 1
+	}
+	fmt.Printf("%s on %s: %d row(s) written to %s\n", ticker, date.Format("2006-01-02"), rows, path)
+	return 0
+}
+
+func runVerify(settings *config.Settings, args []string, options map[string]string) int {
+	days, err := settings.ListDayDirectories(utils.GetMarketTimezone())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list data directories: %v\n", err)
+		return 1
+	}
+	if len(args) > 0 {
+		date, err := utils.ParseDateInET(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid date: %v\n", err)
+			return 1
+		}
+		selected := days[:0]
+		for _, day := range days {
+			if day.Date.Format("2006-01-02") == date.Format("2006-01-02") {
+				selected = append(selected, day)
+			}
+		}
+		days = selected
+	}
+	ticker := strings.ToUpper(options["ticker"])
+
+	dataLoader := newCLILoader(settings)
+	defer dataLoader.Close()
+	checked, failed := 0, 0
+	for _, day := range days {
+		for _, name := range dayTickers(day.Path) {
+			if ticker != "" && name != ticker {
+				continue
+			}
+			checked++
+			result, err := dataLoader.VerifyDay(name, day.Date)
+			if err != nil {
+				failed++
+				fmt.Printf("%s %s: ERROR %v\n", name, day.Date.Format("2006-01-02"), err)
+				continue
+			}
+			if !result.OK() {
+				failed++
+			}
+			fmt.Println(result.String())
+		}
+	}
+	fmt.Printf("%d database(s) checked, %d with problems\n", checked, failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func runBackfill(settings *config.Settings, args []string, options map[string]string) int {
+	ticker, dateStr, ok := cliTickerDate("backfill", args)
+	if !ok {
+		return 1
+	}
+	date, err := utils.ParseDateInET(dateStr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid date: %v\n", err)
+		return 1
+	}
+	lock, ok := lockForCLI(settings)
+	if !ok {
+		return 1
+	}
+	defer lock.Release()
+	dataWriter := database.NewDataWriter(settings, cliLog)
+	defer dataWriter.Close()
+
+	result, err := dataWriter.ReprocessDay(context.Background(), ticker, date)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Backfill failed: %v\n", err)
+		return 1
+	}
+	fmt.Printf("%s on %s: %d of %d row(s) got missing values\n", ticker, result.Date, result.UpdatedRows, result.Rows)
+	columns := make([]string, 0, len(result.Filled))
+	for column := range result.Filled {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	for _, column := range columns {
+		fmt.Printf("  %s: %d value(s) filled\n", column, result.Filled[column])
+	}
+	if err := dataWriter.RebuildChartBars(ticker, date); err != nil {
+		fmt.Fprintf(os.Stderr, "1m bar rebuild failed: %v\n", err)
+		return 1
+	}
+	fmt.Println("1m bars rebuilt")
+	return 0
+}
+
+func runCompact(settings *config.Settings, args []string, options map[string]string) int {
+	lock, ok := lockForCLI(settings)
+	if !ok {
+		return 1
+	}
+	defer lock.Release()
+	dataWriter := database.NewDataWriter(settings, cliLog)
+	defer dataWriter.Close()
+
+	result, err := dataWriter.CompactDatabases(0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Compaction failed: %v\n", err)
+		return 1
+	}
+	fmt.Printf("%d database(s) compacted, %d skipped, %.1f MB reclaimed\n",
+		result.Databases, result.Skipped, float64(result.ReclaimedBytes)/1024/1024)
+	for _, message := range result.Errors {
+		fmt.Fprintf(os.Stderr, "  error: %s\n", message)
+	}
+	if len(result.Errors) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,65 @@
+// Decoder for the binary chart data format (/api/chart-data/...?format=binary)
+// Layout (little-endian), see internal/charts/binary.go:
+//   "MGTC" | version u16 | column count u16 | row count u32 | metadata length u32 | metadata JSON (padded to 8)
+//   per column: name length u16 | name (padded to 8) | row count f64 values (NaN = null)
+
+const CHART_BINARY_MAGIC = 'MGTC';
+const CHART_BINARY_VERSION = 1;
+
+function alignTo8(offset) {
+    return (offset + 7) & ~7;
+}
+
+// decodeChartBinary turns an ArrayBuffer into { column: Float64Array, ...metadata }
+// With plainArrays set, columns become regular arrays with null for gaps (what the chart code expects)
+function decodeChartBinary(buffer, { plainArrays = false } = {}) {
+    const view = new DataView(buffer);
+    const decoder = new TextDecoder();
+    const magic = decoder.decode(new Uint8Array(buffer, 0, 4));
+    if (magic !== CHART_BINARY_MAGIC) {
+        throw new Error(`Not binary chart data (magic ${JSON.stringify(magic)})`);
+    }
+    const version = view.getUint16(4, true);
+    if (version > CHART_BINARY_VERSION) {
+        throw new Error(`Unsupported binary chart data version ${version}`);
+    }
+    const columnCount = view.getUint16(6, true);
+    const rowCount = view.getUint32(8, true);
+    const metadataLength = view.getUint32(12, true);
+
+    let offset = 16;
+    const result = JSON.parse(decoder.decode(new Uint8Array(buffer, offset, metadataLength)) || '{}');
+    offset = alignTo8(offset + metadataLength);
+
+    for (let i = 0; i < columnCount; i++) {
+        const nameLength = view.getUint16(offset, true);
+        const name = decoder.decode(new Uint8Array(buffer, offset + 2, nameLength));
+        offset = alignTo8(offset + 2 + nameLength);
+        const values = new Float64Array(buffer, offset, rowCount);
+        offset += rowCount * 8;
+        result[name] = plainArrays ? Array.from(values, v => (Number.isNaN(v) ? null : v)) : values;
+    }
+    return result;
+}
+
+// fetchChartData requests binary chart data, falling back to JSON if the binary request fails
+async function fetchChartData(url, options = {}) {
+    const separator = url.includes('?') ? '&' : '?';
+    try {
+        const response = await fetch(`${url}${separator}format=binary`);
+        if (response.ok) {
+            return decodeChartBinary(await response.arrayBuffer(), options);
+        }
+    } catch (error) {
+        console.warn('[Chart] Binary chart data failed, falling back to JSON:', error);
+    }
+    const response = await fetch(url);
+    if (!response.ok) {
+        const errorText = await response.text();
+        const error = new Error(`HTTP ${response.status}: ${response.statusText}`);
+        error.status = response.status;
+        error.detail = errorText;
+        throw error;
+    }
+    return response.json();
+}
@@ -21,6 +21,34 @@ async function logToBackend(level, message) {
     }
 }
 
+// Subscribe to a backend event (app.Event.Emit) by hooking the Wails runtime's event dispatcher
+// The runtime can load after this script, so retry for a few seconds before giving up
+const backendEventListeners = {};
+function onBackendEvent(name, callback, attempt = 0) {
+    const wails = window._wails;
+    if (!wails || typeof wails.dispatchWailsEvent !== 'function') {
+        if (attempt < 50) {
+            setTimeout(() => onBackendEvent(name, callback, attempt + 1), 200);
+        }
+        return;
+    }
+    if (!wails.__backendEventsHooked) {
+        const dispatch = wails.dispatchWailsEvent;
+        wails.dispatchWailsEvent = function(event) {
+            (backendEventListeners[event && event.name] || []).forEach(listener => {
+                try {
+                    listener(event.data);
+                } catch (err) {
+                    console.error(`[Events] ${event.name} listener failed:`, err);
+                }
+            });
+            return dispatch.apply(this, arguments);
+        };
+        wails.__backendEventsHooked = true;
+    }
+    (backendEventListeners[name] = backendEventListeners[name] || []).push(callback);
+}
+
 // Ticker list constants (matching Python version)
 const FUTURES = ["ES_SPX", "NQ_NDX"];
 const INDEXES = ["SPX", "VIX", "NDX", "RUT", "IWM", "QQQ", "SPY"];
@@ -280,6 +308,25 @@ function formatDateTimeForDisplay(date) {
     return date.toLocaleString('en-US', options);
 }
 
+// Open the log viewer window (served over HTTP so it works before the Wails runtime is ready)
+function initializeLogsButton() {
+    const logsBtn = document.getElementById('logs-btn');
+    if (!logsBtn) {
+        return;
+    }
+    logsBtn.addEventListener('click', async () => {
+        try {
+            const response = await fetch('/api/log-viewer', { method: 'POST' });
+            if (!response.ok) {
+                throw new Error(await response.text());
+            }
+        } catch (error) {
+            console.error('[Logs] Failed to open log viewer:', error);
+            alert(`Error opening log viewer: ${error.message}`);
+        }
+    });
+}
+
 // Initialize settings immediately (doesn't require backend)
 function initializeSettingsImmediate() {
     const settingsBtn = document.getElementById('settings-btn');
@@ -305,6 +352,10 @@ function initializeSettingsImmediate() {
         console.log('[Settings] Loading from cache/defaults first');
         loadSettingsUI(initialSettings);
         
+        // Profiles are listed separately from the settings of the running profile
+        loadProfilesUI();
+        loadSettingsHistoryUI();
+        
         // Then try to load from backend in background
         try {
             await loadSettingsFromBackend();
@@ -725,6 +776,7 @@ window.addEventListener('DOMContentLoaded', async () => {
     
     // Initialize settings button immediately (doesn't need backend)
     initializeSettingsImmediate();
+    initializeLogsButton();
     
     // Since window is created AFTER backend is ready, we can initialize directly
     // Window creation happens in ServiceStartup() after all backend initialization
@@ -764,8 +816,47 @@ window.addEventListener('DOMContentLoaded', async () => {
     // Start first attempt immediately (window is already created after backend is ready)
     console.log('[Init] Starting initialization immediately...');
     tryInitialize();
+    checkDataDirectoryLock();
 });
 
+// Explain why this instance is read-only when another collector already held the data directory at startup,
+// and keep checking while collecting: another instance can take the lock over (e.g. after this machine slept)
+let dataDirectoryLockTimer = null;
+async function checkDataDirectoryLock() {
+    try {
+        const response = await fetch('/api/data-directory-lock');
+        if (!response.ok) {
+            return;
+        }
+        const status = await response.json();
+        if (!status.holder) {
+            if (!status.read_only && !dataDirectoryLockTimer) {
+                dataDirectoryLockTimer = setInterval(checkDataDirectoryLock, 30000);
+            }
+            return;
+        }
+        if (dataDirectoryLockTimer) {
+            clearInterval(dataDirectoryLockTimer);
+            dataDirectoryLockTimer = null;
+        }
+        const holder = status.holder.pid
+            ? `(PID ${status.holder.pid} on ${status.holder.host || 'unknown host'}, running since ${new Date(status.holder.started_at).toLocaleString()})`
+            : '(its lock file was updated moments ago)';
+        if (status.lost) {
+            alert(`Another Market Terminal took over this data directory while this one was collecting:\n\n${status.directory}\n\n` +
+                `${holder}\n\nCollection has stopped here so the two don't write the same files; rows not yet ` +
+                'written were dropped. Charts can still be browsed. Restart this instance to collect here again.');
+            return;
+        }
+        alert(`Another Market Terminal is already collecting into this data directory:\n\n${status.directory}\n\n` +
+            `${holder}\n\n` +
+            'Two collectors writing the same files would corrupt them, so this window is read-only: you can browse ' +
+            'charts but nothing is collected. Close the other instance and restart this one to collect here.');
+    } catch (error) {
+        console.warn('[Init] Could not check the data directory lock:', error);
+    }
+}
+
 // Clean up on page unload
 window.addEventListener('beforeunload', () => {
     stopMarketCountdown();
@@ -918,6 +1009,9 @@ async function connectToBackend() {
             console.log('[Connect] Not first run, initializing UI...');
             // Initialize UI
             await initializeUI();
+            
+            // Report problems found in a hand-edited config.yaml
+            await showSettingsValidation();
         }
         
         console.log('[Connect] ===== Backend connection successful =====');
@@ -1000,6 +1094,7 @@ async function initializeUI() {
         if (tickers && tickers.length > 0) {
             try {
                 const settings = await App.GetSettings();
+                hotkeySettings = settings?.Hotkeys || {};
                 if (settings && settings.TickerOrder && settings.TickerOrder.length > 0) {
                     const tickerOrder = settings.TickerOrder;
                     tickers = sortTickersByOrder(tickers, tickerOrder);
@@ -1033,6 +1128,9 @@ async function initializeUI() {
         await initializeDateSelector();
         await logToBackend('INFO', '[InitializeUI] Date selector initialized');
         
+        // Workspace selector (chart layouts)
+        loadWorkspacesUI();
+        
         console.log('[InitializeUI] Starting periodic updates...');
         // Settings already initialized in initializeSettingsImmediate()
         
@@ -1097,6 +1195,15 @@ function initializeTickerTable(tickers) {
             <td><button class="chart-btn" data-ticker="${ticker}">📊 Chart</button></td>
         `;
         
+        // Track the row under the cursor for the open-chart hotkey and the session summary card
+        row.addEventListener('mouseenter', () => {
+            fetch(`/api/hovered-ticker?ticker=${encodeURIComponent(ticker)}`, { method: 'POST' }).catch(() => {});
+            showDailyStats(ticker);
+        });
+        row.addEventListener('mouseleave', () => {
+            fetch('/api/hovered-ticker?ticker=', { method: 'POST' }).catch(() => {});
+        });
+        
         // Add click handler to open chart
         row.addEventListener('click', (e) => {
             // Don't trigger if clicking the button or drag handle
@@ -1255,11 +1362,13 @@ function loadSettingsUI(settings) {
         // Load ticker selection (organized by tier)
         loadTickerSelection(settings);
         
-        // Load chart colors
+        // Load chart colors and the theme picker
         loadChartColors(settings);
+        loadChartThemeSelect(settings);
         
         // Load general settings
         loadGeneralSettings(settings);
+        loadHotkeySettings(settings);
         
         // Load hidden plots settings
         loadHiddenPlots(settings);
@@ -1302,6 +1411,99 @@ function loadGeneralSettings(settings) {
     }
 }
 
+// Load hotkey settings into UI and show which shortcuts are registered
+async function loadHotkeySettings(settings) {
+    const hotkeys = settings.Hotkeys || {};
+    hotkeySettings = hotkeys;
+    const fields = { 'hotkeys-enabled': null, 'hotkey-open-chart': 'OpenChart', 'hotkey-pause-collection': 'PauseCollection', 'hotkey-snapshot-all': 'SnapshotAll' };
+    for (const [id, key] of Object.entries(fields)) {
+        const input = document.getElementById(id);
+        if (!input) {
+            continue;
+        }
+        if (key) {
+            input.value = hotkeys[key] || '';
+        } else {
+            input.checked = hotkeys.Enabled || false;
+        }
+    }
+    
+    const status = document.getElementById('hotkeys-status');
+    if (!status) {
+        return;
+    }
+    try {
+        const response = await fetch('/api/hotkeys');
+        if (!response.ok) {
+            return;
+        }
+        const registered = await response.json();
+        const failed = registered.filter(entry => !entry.registered);
+        if (registered.length === 0) {
+            status.textContent = '';
+        } else if (failed.length === 0) {
+            status.textContent = `${registered.length} shortcut(s) registered system-wide.`;
+        } else {
+            status.textContent = failed.map(entry => `${entry.shortcut}: ${entry.error}`).join(' | ');
+        }
+    } catch (error) {
+        console.warn('[Hotkeys] Failed to load hotkey status:', error);
+    }
+}
+
+// Save hotkey settings from UI to settings object
+function saveHotkeySettings(settings) {
+    const enabled = document.getElementById('hotkeys-enabled');
+    settings.Hotkeys = {
+        Enabled: enabled ? enabled.checked : false,
+        OpenChart: document.getElementById('hotkey-open-chart')?.value.trim() || '',
+        PauseCollection: document.getElementById('hotkey-pause-collection')?.value.trim() || '',
+        SnapshotAll: document.getElementById('hotkey-snapshot-all')?.value.trim() || ''
+    };
+    hotkeySettings = settings.Hotkeys;
+}
+
+// Hotkey settings in use (for shortcuts pressed while the main window has focus)
+let hotkeySettings = {};
+
+// Default shortcuts (match DefaultHotkey* in internal/config/constants.go)
+const DEFAULT_HOTKEYS = { open_chart: 'Ctrl+Alt+C', pause_collection: 'Ctrl+Alt+P', snapshot_all: 'Ctrl+Alt+S' };
+
+// Does a keydown event match a shortcut like "Ctrl+Shift+F5"?
+function matchesShortcut(event, shortcut) {
+    const parts = shortcut.toUpperCase().replace(/\s/g, '').split('+');
+    const key = parts.pop();
+    const wants = mod => parts.some(part => mod.includes(part));
+    if (event.ctrlKey !== wants(['CTRL', 'CONTROL']) || event.altKey !== wants(['ALT', 'OPTION']) ||
+        event.shiftKey !== wants(['SHIFT']) || event.metaKey !== wants(['SUPER', 'WIN', 'CMD', 'META'])) {
+        return false;
+    }
+    // event.code is layout-independent: "KeyC", "Digit5", "F5"
+    return event.code === key || event.code === `Key${key}` || event.code === `Digit${key}`;
+}
+
+// Handle hotkeys while the main window is focused (platforms without system-wide registration)
+document.addEventListener('keydown', async (event) => {
+    if (!hotkeySettings.Enabled) {
+        return;
+    }
+    const shortcuts = {
+        open_chart: hotkeySettings.OpenChart || DEFAULT_HOTKEYS.open_chart,
+        pause_collection: hotkeySettings.PauseCollection || DEFAULT_HOTKEYS.pause_collection,
+        snapshot_all: hotkeySettings.SnapshotAll || DEFAULT_HOTKEYS.snapshot_all
+    };
+    for (const [action, shortcut] of Object.entries(shortcuts)) {
+        if (shortcut.toLowerCase() !== 'off' && matchesShortcut(event, shortcut)) {
+            event.preventDefault();
+            const response = await fetch(`/api/hotkeys/${action}`, { method: 'POST' });
+            if (!response.ok) {
+                console.warn(`[Hotkeys] ${action} failed:`, await response.text());
+            }
+            return;
+        }
+    }
+});
+
 // Load hidden plots settings into UI
 function loadHiddenPlots(settings) {
     try {
@@ -1715,6 +1917,49 @@ function loadChartColors(settings) {
     }
 }
 
+// Fill the theme picker with the built-in themes and select the saved one
+async function loadChartThemeSelect(settings) {
+    const select = document.getElementById('chart-theme-select');
+    if (!select) {
+        return;
+    }
+    try {
+        const response = await fetch('/api/theme');
+        if (!response.ok) {
+            throw new Error(`HTTP ${response.status}`);
+        }
+        const data = await response.json();
+        select.innerHTML = '';
+        (data.available || []).forEach(name => {
+            const option = document.createElement('option');
+            option.value = name;
+            option.textContent = name.replace(/-/g, ' ').replace(/\b\w/g, l => l.toUpperCase());
+            select.appendChild(option);
+        });
+        select.value = (settings && settings.Theme) || (data.theme && data.theme.Name) || '';
+    } catch (error) {
+        console.warn('[Chart Colors] Could not load themes:', error);
+    }
+}
+
+// Keep the theme picker and chart windows in step with theme changes made elsewhere (SetTheme, config import)
+onBackendEvent('theme:changed', (theme) => {
+    if (!theme || !theme.Name) {
+        return;
+    }
+    const select = document.getElementById('chart-theme-select');
+    if (select) {
+        select.value = theme.Name;
+    }
+    try {
+        const settingsChannel = new BroadcastChannel('market-terminal-settings');
+        settingsChannel.postMessage({ type: 'theme-changed', theme: theme });
+        settingsChannel.close();
+    } catch (e) {
+        console.warn('[Theme] Failed to broadcast theme change:', e);
+    }
+});
+
 // Save chart colors from UI to settings
 function saveChartColors(settings) {
     console.log('[Chart Colors] Saving chart colors...');
@@ -1743,6 +1988,50 @@ function saveChartColors(settings) {
 }
 
 // Save settings
+// Ask the backend what saving would change and confirm it with the user
+// Returns true to go ahead (also when the diff can't be fetched)
+async function confirmSettingsDiff(settings) {
+    let diff;
+    try {
+        const response = await fetch('/api/settings-diff', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify(settings)
+        });
+        if (!response.ok) {
+            return true;
+        }
+        diff = await response.json();
+    } catch (error) {
+        console.warn('[Save Settings] Could not preview changes:', error);
+        return true;
+    }
+    if (!diff || diff.empty) {
+        return true;
+    }
+
+    const lines = [];
+    if (diff.tickers_enabled.length > 0) {
+        lines.push(`Enable collection: ${diff.tickers_enabled.join(', ')}`);
+    }
+    if (diff.tickers_disabled.length > 0) {
+        lines.push(`Disable collection: ${diff.tickers_disabled.join(', ')}`);
+    }
+    diff.ticker_changes.forEach(change => {
+        lines.push(`${change.ticker} ${change.field}: ${change.old || '(none)'} → ${change.new || '(none)'}`);
+    });
+    if (diff.endpoints_added.length > 0) {
+        lines.push(`Endpoints added: ${diff.endpoints_added.join(', ')}`);
+    }
+    if (diff.endpoints_removed.length > 0) {
+        lines.push(`Endpoints removed: ${diff.endpoints_removed.join(', ')}`);
+    }
+    if (diff.changed_settings.length > 0) {
+        lines.push(`Other settings: ${diff.changed_settings.join(', ')}`);
+    }
+    return confirm(`Save these changes?\n\n${lines.join('\n')}`);
+}
+
 async function saveSettings() {
     try {
         console.log('[Save Settings] Starting save...');
@@ -1855,11 +2144,16 @@ async function saveSettings() {
             }
         });
         
-        // Save chart colors
+        // Save chart colors and the theme
         saveChartColors(settings);
+        const themeSelect = document.getElementById('chart-theme-select');
+        if (themeSelect && themeSelect.value) {
+            settings.Theme = themeSelect.value;
+        }
         
         // Save general settings (UseMarketTime, EnableLogging, HideConsole)
         saveGeneralSettings(settings);
+        saveHotkeySettings(settings);
         
         // Save hidden plots settings
         saveHiddenPlots(settings);
@@ -1876,6 +2170,12 @@ async function saveSettings() {
         
         console.log('[Save Settings] Saving settings with ticker configs:', Object.keys(settings.TickerConfigs));
         
+        // Show what the save changes and let the user back out (skipped if the backend can't diff)
+        if (!(await confirmSettingsDiff(settings))) {
+            console.log('[Save Settings] Cancelled at the change preview');
+            return;
+        }
+        
         // Save to cache immediately (works even without backend)
         saveSettingsToCache(settings);
         console.log('[Save Settings] Settings saved to cache');
@@ -1949,6 +2249,7 @@ async function saveSettings() {
 
 // Periodic updates interval
 let periodicUpdateInterval = null;
+let rateLimitGaugeInterval = null;
 
 // Start periodic updates
 // Monitor window size and save periodically (backup for resize events)
@@ -2003,14 +2304,445 @@ function startPeriodicUpdates() {
         clearInterval(periodicUpdateInterval);
         periodicUpdateInterval = null;
     }
+    if (rateLimitGaugeInterval) {
+        clearInterval(rateLimitGaugeInterval);
+        rateLimitGaugeInterval = null;
+    }
     
     // Update every 1 second to reflect high-priority ticker updates
     periodicUpdateInterval = setInterval(async () => {
         await updateTickerData();
+        pollAlerts();
     }, 1000);
     
+    // Rate limit gauge changes slowly - 5 seconds matches the backend's ratelimit:status event
+    rateLimitGaugeInterval = setInterval(() => {
+        updateRateLimitGauge();
+        updateEcoBadge();
+        updateSpotCheckBadge();
+        updateDiskSpaceBadge();
+        updateCrashBadge();
+        updateClockSkewBadge();
+        updateMarketDateBanner();
+        showDailyStats(dailyStatsTicker);
+    }, 5000);
+    
     // Initial update
     updateTickerData();
+    updateRateLimitGauge();
+    updateEcoBadge();
+    updateSpotCheckBadge();
+    updateDiskSpaceBadge();
+    updateCrashBadge();
+    updateClockSkewBadge();
+    updateMarketDateBanner();
+    const firstRow = document.querySelector('#ticker-table-body tr');
+    showDailyStats(firstRow ? firstRow.dataset.ticker : null);
+}
+
+// Live alerts: the header badge shows the global mute (click toggles it) and each new alert plays its sound
+let lastAlertSeq = null; // null until the first poll, so alerts from before the window opened don't replay
+async function pollAlerts() {
+    const badge = document.getElementById('alerts-badge');
+    try {
+        const response = await fetch(`/api/alerts/recent?after=${lastAlertSeq || 0}`);
+        if (!response.ok) {
+            return;
+        }
+        const status = await response.json();
+        const alerts = status.alerts || [];
+        if (badge) {
+            badge.style.display = 'inline-block';
+            badge.textContent = status.muted ? '🔕 Alerts' : '🔔 Alerts';
+            badge.title = `Alert sounds are ${status.muted ? 'muted' : 'on'} - click to ${status.muted ? 'unmute' : 'mute'}`;
+            badge.onclick = async () => {
+                await fetch(`/api/alerts/mute?muted=${!status.muted}`, { method: 'POST' });
+                pollAlerts();
+            };
+        }
+        if (lastAlertSeq !== null) {
+            for (const alert of alerts) {
+                console.log(`[Alerts] ${alert.rule.name}: ${alert.ticker} ${alert.rule.field} ${alert.rule.condition} ${alert.threshold.toFixed(2)} (${alert.value.toFixed(2)})`);
+                if (alert.sound) {
+                    new Audio(`/api/alert-sound?name=${encodeURIComponent(alert.sound)}`).play()
+                        .catch(error => console.warn('[Alerts] Failed to play sound:', error));
+                }
+            }
+        }
+        lastAlertSeq = alerts.length > 0 ? alerts[alerts.length - 1].seq : (lastAlertSeq || 0);
+    } catch (error) {
+        console.warn('[Alerts] Failed to poll alerts:', error);
+    }
+}
+
+// Warn in the header while the data volume is below the disk_space thresholds (critical: essential columns only)
+async function updateDiskSpaceBadge() {
+    const badge = document.getElementById('disk-space-badge');
+    if (!badge) {
+        return;
+    }
+    try {
+        const response = await fetch('/api/disk-space');
+        if (!response.ok) {
+            return;
+        }
+        const status = await response.json();
+        if (status.level !== 'low' && status.level !== 'critical') {
+            badge.style.display = 'none';
+            return;
+        }
+        const freeGB = (status.free_mb / 1024).toFixed(1);
+        badge.style.display = 'inline-block';
+        badge.style.color = status.level === 'critical' ? '#ff1744' : '#ff9800';
+        badge.textContent = `💾 ${freeGB} GB free`;
+        badge.title = `Low disk space on ${status.path}: ${status.free_mb} MB free of ${status.total_mb} MB ` +
+            `(warning below ${status.warn_mb} MB, critical below ${status.critical_mb} MB)` +
+            (status.essential_only ? '\nNew rows keep the chart columns only (no profiles) until space is freed.' : '');
+    } catch (error) {
+        console.warn('[Disk Space] Failed to update badge:', error);
+    }
+}
+
+// Warn in the header while the local clock is off from API time (rollover and market hours follow the clock)
+async function updateClockSkewBadge() {
+    const badge = document.getElementById('clock-skew-badge');
+    if (!badge) {
+        return;
+    }
+    try {
+        const response = await fetch('/api/clock-skew');
+        if (!response.ok) {
+            return;
+        }
+        const status = await response.json();
+        if (!status.warning) {
+            badge.style.display = 'none';
+            return;
+        }
+        const skew = Math.abs(status.skew_sec).toFixed(0);
+        badge.style.display = 'inline-block';
+        badge.textContent = `🕒 Clock ${status.skew_sec > 0 ? '+' : '-'}${skew}s`;
+        badge.title = `The local clock is ${skew}s ${status.skew_sec > 0 ? 'ahead of' : 'behind'} API time ` +
+            `(${status.samples} samples). Market date rollover and market hours follow the local clock.\n` +
+            (status.correcting
+                ? `Market time is corrected by ${status.offset_sec.toFixed(1)}s.`
+                : 'Sync the system clock, or set correct_clock_skew: true in config.yaml.');
+    } catch (error) {
+        console.warn('[Clock Skew] Failed to update badge:', error);
+    }
+}
+
+// Crash reports: the header badge counts the dumps written after recovered panics and opens the list
+async function updateCrashBadge() {
+    const badge = document.getElementById('crash-badge');
+    if (!badge) {
+        return;
+    }
+    try {
+        const response = await fetch('/api/crash-reports');
+        if (!response.ok) {
+            return;
+        }
+        const reports = await response.json();
+        if (!reports || reports.length === 0) {
+            badge.style.display = 'none';
+            return;
+        }
+        badge.style.display = 'inline-block';
+        badge.textContent = `💥 ${reports.length} crash${reports.length !== 1 ? 'es' : ''}`;
+        badge.title = `Latest: ${reports[0].component} at ${reports[0].time} - click to view`;
+        badge.onclick = () => showCrashReports(reports);
+    } catch (error) {
+        console.warn('[Crash Reports] Failed to update badge:', error);
+    }
+}
+
+function showCrashReports(reports) {
+    const modal = document.getElementById('crash-reports-modal');
+    const list = document.getElementById('crash-reports-list');
+    if (!modal || !list) {
+        return;
+    }
+    const action = (label, onClick) => {
+        const button = document.createElement('button');
+        button.textContent = label;
+        button.style.cssText = 'margin-right: 0.5rem; padding: 0.25rem 0.6rem; background: #3a3a3a; border: 1px solid #4a4a4a; border-radius: 4px; color: #e0e0e0; cursor: pointer;';
+        button.onclick = async () => {
+            try {
+                await onClick();
+            } catch (error) {
+                alert(`${label} failed: ${error.message}`);
+            }
+        };
+        return button;
+    };
+    const open = async (path, reveal) => {
+        const response = await fetch(`/api/crash-reports/open?path=${encodeURIComponent(path)}&reveal=${reveal ? 1 : 0}`, { method: 'POST' });
+        if (!response.ok) {
+            throw new Error(await response.text());
+        }
+    };
+    
+    list.innerHTML = '';
+    for (const report of reports) {
+        const item = document.createElement('li');
+        item.style.padding = '0.5rem 0';
+        item.style.borderBottom = '1px solid #3a3a3a';
+        const summary = document.createElement('div');
+        summary.textContent = `${report.time} - ${report.component}: ${report.panic}`;
+        summary.style.marginBottom = '0.4rem';
+        item.appendChild(summary);
+        item.appendChild(action('Open', () => open(report.path, false)));
+        item.appendChild(action('Show in folder', () => open(report.path, true)));
+        item.appendChild(action('Copy', async () => {
+            const response = await fetch(`/api/crash-reports/read?path=${encodeURIComponent(report.path)}`);
+            if (!response.ok) {
+                throw new Error(await response.text());
+            }
+            await navigator.clipboard.writeText((await response.json()).contents);
+        }));
+        list.appendChild(item);
+    }
+    document.getElementById('crash-reports-close').onclick = () => { modal.style.display = 'none'; };
+    modal.style.display = 'block';
+}
+
+// A recovered panic just wrote a dump: refresh the badge right away
+onBackendEvent('crash:reported', (report) => {
+    console.warn('[Crash Reports] Crash reported:', report && report.component, report && report.panic);
+    updateCrashBadge();
+});
+
+// Session summary card (GetDailyStats) for the ticker last hovered in the table on the selected date
+// Today's stats are recomputed from the database, so they are refetched at most every 30 seconds
+const DAILY_STATS_MAX_AGE_MS = 30000;
+let dailyStatsTicker = null;
+let dailyStatsFetched = { key: null, at: 0 };
+async function showDailyStats(ticker, force = false) {
+    const card = document.getElementById('daily-stats-card');
+    if (!card || !ticker) {
+        return;
+    }
+    dailyStatsTicker = ticker;
+    const key = `${ticker}|${selectedDate || ''}`;
+    if (!force && dailyStatsFetched.key === key && Date.now() - dailyStatsFetched.at < DAILY_STATS_MAX_AGE_MS) {
+        return;
+    }
+    dailyStatsFetched = { key, at: Date.now() };
+    
+    try {
+        const response = await fetch(`/api/daily-stats?ticker=${encodeURIComponent(ticker)}&date=${encodeURIComponent(selectedDate || '')}`);
+        if (!response.ok) {
+            throw new Error(await response.text());
+        }
+        const stats = await response.json();
+        if (dailyStatsTicker !== ticker) {
+            return; // Another ticker was hovered while this one loaded
+        }
+        card.style.display = 'block';
+        const body = document.getElementById('daily-stats-body');
+        if (!stats || !stats.row_count) {
+            document.getElementById('daily-stats-title').textContent = `${ticker} session`;
+            body.innerHTML = '<div>No data for this day</div>';
+            return;
+        }
+        document.getElementById('daily-stats-title').textContent = `${ticker} session ${stats.date}`;
+        const time = (seconds) => seconds ? formatTimeForDisplay(new Date(seconds * 1000), false) : '-';
+        const duration = (seconds) => `${Math.floor(seconds / 3600)}h ${Math.round((seconds % 3600) / 60)}m`;
+        const change = stats.spot_close - stats.spot_open;
+        const flip = (stats.largest_gamma_flips || [])[0];
+        const items = [
+            ['Open / Close', `${stats.spot_open.toFixed(2)} / ${stats.spot_close.toFixed(2)} (${change >= 0 ? '+' : ''}${change.toFixed(2)})`],
+            ['High', `${stats.spot_high.toFixed(2)} at ${time(stats.spot_high_time)}`],
+            ['Low', `${stats.spot_low.toFixed(2)} at ${time(stats.spot_low_time)}`],
+            ['Above / below zero gamma', `${duration(stats.seconds_above_zero_gamma)} / ${duration(stats.seconds_below_zero_gamma)}`],
+            ['Largest zero gamma flip', flip ? `${flip.from.toFixed(2)} → ${flip.to.toFixed(2)} at ${time(flip.timestamp)}` : '-'],
+            ['Volume-weighted major pos / neg vol', `${stats.avg_major_pos_vol.toFixed(2)} / ${stats.avg_major_neg_vol.toFixed(2)}`],
+            ['Rows', String(stats.row_count)]
+        ];
+        body.innerHTML = '';
+        items.forEach(([label, value]) => {
+            const item = document.createElement('div');
+            const labelEl = document.createElement('span');
+            labelEl.className = 'label';
+            labelEl.textContent = label;
+            item.appendChild(labelEl);
+            item.appendChild(document.createTextNode(value));
+            body.appendChild(item);
+        });
+    } catch (error) {
+        console.warn('[Daily Stats] Failed to load session summary:', error);
+    }
+}
+
+// After the 8:30 AM ET rollover, offer to move chart windows opened on the previous market date to the new one
+// ("Keep" leaves them on their session with a banner); the date selector is reloaded when the date changes
+let lastMarketDate = null;
+function showMarketDateBanner(change) {
+    const banner = document.getElementById('market-date-banner');
+    if (!banner || !change) {
+        return;
+    }
+    if (lastMarketDate !== null && change.current && change.current !== lastMarketDate) {
+        loadAvailableDates();
+    }
+    if (change.current) {
+        lastMarketDate = change.current;
+    }
+    const charts = change.charts || [];
+    if (charts.length === 0) {
+        banner.style.display = 'none';
+        return;
+    }
+    banner.style.display = 'inline-flex';
+    document.getElementById('market-date-banner-text').textContent =
+        `📅 ${charts.length} chart${charts.length !== 1 ? 's' : ''} on the previous session`;
+    banner.title = `New market date ${change.current}: ${charts.join(', ')} still show${charts.length === 1 ? 's' : ''} ` +
+        `${change.previous || 'the previous date'}.\nMove: switch them to ${change.current}\nKeep: stay on the previous session`;
+    const retarget = async (keep) => {
+        try {
+            await fetch(`/api/market-date/charts?keep=${keep}`, { method: 'POST' });
+        } catch (error) {
+            console.warn('[Market Date] Failed to retarget charts:', error);
+        }
+        banner.style.display = 'none';
+    };
+    document.getElementById('market-date-move').onclick = () => retarget(false);
+    document.getElementById('market-date-keep').onclick = () => retarget(true);
+}
+
+async function updateMarketDateBanner() {
+    try {
+        const response = await fetch('/api/market-date/charts');
+        if (response.ok) {
+            showMarketDateBanner(await response.json());
+        }
+    } catch (error) {
+        console.warn('[Market Date] Failed to check the market date:', error);
+    }
+}
+
+onBackendEvent('market-date-changed', showMarketDateBanner);
+
+// Warn in the header while a ticker's GEXBot spot disagrees with the secondary quote source (spot_check)
+async function updateSpotCheckBadge() {
+    const badge = document.getElementById('spot-check-badge');
+    if (!badge) {
+        return;
+    }
+    try {
+        const response = await fetch('/api/spot-check');
+        if (!response.ok) {
+            return;
+        }
+        const status = await response.json();
+        if (!status.enabled || !status.divergent || status.divergent.length === 0) {
+            badge.style.display = 'none';
+            return;
+        }
+        badge.style.display = 'inline-block';
+        badge.textContent = `⚠️ Spot: ${status.divergent.join(', ')}`;
+        badge.title = 'GEXBot spot differs from the secondary quote source by more than ' + status.threshold_pct + '%:\n' +
+            status.divergent.map(ticker => {
+                const t = status.tickers[ticker];
+                return `${ticker}: ${t.spot.toFixed(2)} vs ${t.symbol} ${t.secondary.toFixed(2)} (${t.diff_pct.toFixed(2)}%)`;
+            }).join('\n') + '\nAffected rows are flagged on the charts.';
+    } catch (error) {
+        console.warn('[Spot Check] Failed to update badge:', error);
+    }
+}
+
+// Show eco (battery saving) mode in the header; clicking cycles eco_mode auto -> on -> off
+async function updateEcoBadge() {
+    const badge = document.getElementById('eco-badge');
+    if (!badge) {
+        return;
+    }
+    try {
+        const response = await fetch('/api/health');
+        if (!response.ok) {
+            return;
+        }
+        const eco = (await response.json()).eco || {};
+        badge.style.display = 'inline-block';
+        badge.textContent = `${eco.active ? '🔋' : '⚡'} Eco: ${eco.mode || 'auto'}`;
+        badge.style.color = eco.active ? '#00c853' : '#888';
+        badge.title = `Eco mode is ${eco.active ? 'on' : 'off'} (${eco.reason || 'unknown'})\n` +
+            'On: longer polling intervals, fewer disk flushes, profiler suspended\nClick to switch auto/on/off';
+        badge.onclick = async () => {
+            const next = { auto: 'on', on: 'off', off: 'auto' }[eco.mode || 'auto'];
+            await fetch(`/api/eco-mode?mode=${next}`, { method: 'POST' });
+            updateEcoBadge();
+        };
+    } catch (error) {
+        console.warn('[Eco] Failed to update badge:', error);
+    }
+}
+
+// Update the API quota gauge in the header (remaining quota, 429s, throttling)
+async function updateRateLimitGauge() {
+    const gauge = document.getElementById('rate-limit-gauge');
+    const bar = document.getElementById('rate-limit-bar');
+    const text = document.getElementById('rate-limit-text');
+    if (!gauge || !bar || !text) {
+        return;
+    }
+    try {
+        const [response, saverResponse] = await Promise.all([
+            fetch('/api/rate-limit'),
+            fetch('/api/quota-saver').catch(() => null)
+        ]);
+        if (!response.ok) {
+            return;
+        }
+        const status = await response.json();
+        const saver = saverResponse && saverResponse.ok ? await saverResponse.json() : null;
+        
+        // Hide until the API has reported a limit (or we've been throttled or switched to the quota saver)
+        if (!status.limit && !status.is_rate_limited && !status.recent_429s && !(saver && saver.active)) {
+            gauge.style.display = 'none';
+            return;
+        }
+        gauge.style.display = 'flex';
+        
+        const remaining = status.limit ? Math.max(0, status.remaining) : 0;
+        const percent = status.limit ? Math.min(100, (remaining / status.limit) * 100) : 0;
+        bar.style.width = `${percent}%`;
+        bar.style.background = status.is_rate_limited || percent < 10 ? '#ff1744' : (status.light_throttle || percent < 30 ? '#ffc107' : '#00c853');
+        
+        let label = status.limit ? `${remaining}/${status.limit}` : '';
+        if (status.is_rate_limited) {
+            label = 'Rate limited';
+        } else if (status.light_throttle) {
+            label += ' (throttled)';
+        }
+        if (saver && saver.active) {
+            label += ' 🪫 saver';
+        }
+        text.textContent = label;
+        
+        const details = [];
+        if (status.reset_time) {
+            details.push(`Resets at ${new Date(status.reset_time * 1000).toLocaleTimeString()}`);
+        }
+        details.push(`${status.requests_in_window} requests in the last minute`);
+        details.push(`${status.recent_429s} rate limit errors (429) in the last minute`);
+        if (status.is_rate_limited && status.retry_after) {
+            details.push(`Retrying at ${new Date(status.retry_after * 1000).toLocaleTimeString()}`);
+        }
+        if (saver && saver.active) {
+            // Background tickers fetch the chart endpoints only for the rest of the market date
+            details.push(`Quota saver on since ${new Date(saver.activated_at * 1000).toLocaleTimeString()}: ` +
+                `tickers without an open chart fetch chart endpoints only (${saver.skipped} requests saved today)`);
+            if (saver.projection && saver.projection.exhausts_at) {
+                details.push(`At ${saver.projection.rate_per_minute.toFixed(1)} requests/min the quota would run out at ` +
+                    `${new Date(saver.projection.exhausts_at * 1000).toLocaleTimeString()}`);
+            }
+        }
+        gauge.title = `API quota\n${details.join('\n')}`;
+    } catch (error) {
+        console.warn('[RateLimit] Failed to update gauge:', error);
+    }
 }
 
 // Update ticker data (parallelized for performance)
@@ -2291,8 +3023,9 @@ async function onDateChanged(dateStr) {
     console.log('[Date Selector] Date changed to:', dateStr);
     selectedDate = dateStr;
     
-    // Refresh ticker table with new date
+    // Refresh ticker table and the summary card with new date
     await updateTickerData();
+    showDailyStats(dailyStatsTicker, true);
 }
 
 // Set date selector to today
@@ -2328,6 +3061,234 @@ async function setDateToToday() {
     }
 }
 
+// Fill the profile selector in the settings modal and wire its buttons
+async function loadProfilesUI() {
+    const select = document.getElementById('profile-select');
+    const status = document.getElementById('profile-status');
+    if (!select || !status) {
+        return;
+    }
+    try {
+        const response = await fetch('/api/profiles');
+        if (!response.ok) {
+            throw new Error(await response.text());
+        }
+        const data = await response.json();
+        select.innerHTML = '';
+        for (const profile of data.profiles) {
+            const option = document.createElement('option');
+            option.value = profile.name;
+            option.textContent = profile.name + (profile.running ? ' (running)' : '') + (profile.active && !profile.running ? ' (next start)' : '');
+            option.selected = profile.name === data.running;
+            select.appendChild(option);
+        }
+    } catch (error) {
+        console.warn('[Profiles] Failed to load profiles:', error);
+        return;
+    }
+    
+    const profileAction = async (name, action, query = '') => {
+        const response = await fetch(`/api/profiles/${encodeURIComponent(name)}/${action}${query}`, { method: 'POST' });
+        if (!response.ok) {
+            throw new Error(await response.text());
+        }
+        return response.json();
+    };
+    
+    document.getElementById('profile-switch').onclick = async () => {
+        try {
+            const result = await profileAction(select.value, 'switch');
+            status.textContent = result.restart_required
+                ? `Profile "${result.profile}" will be loaded the next time Market Terminal starts.`
+                : `Profile "${result.profile}" is already running.`;
+            await loadProfilesUI();
+        } catch (error) {
+            alert('Failed to switch profile: ' + error.message);
+        }
+    };
+    document.getElementById('profile-new').onclick = async () => {
+        const name = prompt('Name of the new profile (letters, digits, - and _):');
+        if (!name) {
+            return;
+        }
+        try {
+            const copy = confirm('Copy the current profile\'s settings? (Cancel starts from the defaults)');
+            await profileAction(name.trim(), 'create', copy ? '?copy=1' : '');
+            status.textContent = `Profile "${name.trim()}" created. Switch to it and restart to edit its settings.`;
+            await loadProfilesUI();
+        } catch (error) {
+            alert('Failed to create profile: ' + error.message);
+        }
+    };
+    document.getElementById('profile-delete').onclick = async () => {
+        if (!confirm(`Delete profile "${select.value}"? Its data directory is kept.`)) {
+            return;
+        }
+        try {
+            await profileAction(select.value, 'delete');
+            await loadProfilesUI();
+        } catch (error) {
+            alert('Failed to delete profile: ' + error.message);
+        }
+    };
+}
+
+// Fill the previous settings versions selector in the settings modal and wire its restore button
+async function loadSettingsHistoryUI() {
+    const select = document.getElementById('settings-history-select');
+    const status = document.getElementById('settings-history-status');
+    if (!select || !status) {
+        return;
+    }
+    try {
+        const response = await fetch('/api/settings-history');
+        if (!response.ok) {
+            throw new Error(await response.text());
+        }
+        const versions = await response.json();
+        select.innerHTML = '';
+        for (const version of versions) {
+            const option = document.createElement('option');
+            option.value = version.version;
+            option.textContent = 'In use until ' + new Date(version.saved_at).toLocaleString();
+            select.appendChild(option);
+        }
+        select.disabled = versions.length === 0;
+        document.getElementById('settings-history-restore').disabled = versions.length === 0;
+    } catch (error) {
+        console.warn('[Settings History] Failed to load settings history:', error);
+        return;
+    }
+    
+    document.getElementById('settings-history-restore').onclick = async () => {
+        if (!select.value || !confirm(`Restore the settings ${select.options[select.selectedIndex].textContent.toLowerCase()}? The current settings are kept in the history.`)) {
+            return;
+        }
+        try {
+            const response = await fetch(`/api/settings-history/${encodeURIComponent(select.value)}/rollback`, { method: 'POST' });
+            if (!response.ok) {
+                throw new Error(await response.text());
+            }
+            const diff = await response.json();
+            status.textContent = diff.empty ? 'Restored (nothing changed).' : 'Restored the previous settings.';
+            await loadSettingsFromBackend();
+            await loadSettingsHistoryUI();
+        } catch (error) {
+            alert('Failed to restore settings: ' + error.message);
+        }
+    };
+}
+
+// Fill the workspace selector in the header and wire its buttons
+async function loadWorkspacesUI() {
+    const select = document.getElementById('workspace-select');
+    if (!select) {
+        return;
+    }
+    try {
+        const response = await fetch('/api/workspaces');
+        if (!response.ok) {
+            throw new Error(await response.text());
+        }
+        const data = await response.json();
+        select.innerHTML = '';
+        for (const workspace of data.workspaces) {
+            const option = document.createElement('option');
+            option.value = workspace.Name;
+            option.textContent = `${workspace.Name} (${workspace.Charts.length})`;
+            option.selected = workspace.Name.toLowerCase() === (data.last || '').toLowerCase();
+            select.appendChild(option);
+        }
+    } catch (error) {
+        console.warn('[Workspaces] Failed to load workspaces:', error);
+        return;
+    }
+    
+    const workspaceAction = async (name, action) => {
+        const response = await fetch(`/api/workspaces/${encodeURIComponent(name)}/${action}`, { method: 'POST' });
+        if (!response.ok) {
+            throw new Error(await response.text());
+        }
+        return response.json();
+    };
+    
+    document.getElementById('workspace-load').onclick = async () => {
+        if (!select.value) {
+            return;
+        }
+        try {
+            await workspaceAction(select.value, 'load');
+        } catch (error) {
+            alert('Failed to load workspace: ' + error.message);
+        }
+    };
+    document.getElementById('workspace-save').onclick = async () => {
+        const name = prompt('Save the open chart windows as workspace:', select.value || '');
+        if (!name || !name.trim()) {
+            return;
+        }
+        try {
+            await workspaceAction(name.trim(), 'save');
+            await loadWorkspacesUI();
+        } catch (error) {
+            alert('Failed to save workspace: ' + error.message);
+        }
+    };
+    document.getElementById('workspace-delete').onclick = async () => {
+        if (!select.value || !confirm(`Delete workspace "${select.value}"? Open charts are left as they are.`)) {
+            return;
+        }
+        try {
+            await workspaceAction(select.value, 'delete');
+            await loadWorkspacesUI();
+        } catch (error) {
+            alert('Failed to delete workspace: ' + error.message);
+        }
+    };
+}
+
+// Show a dialog listing problems found in config.yaml when it was loaded (nothing if the file is clean)
+async function showSettingsValidation() {
+    const modal = document.getElementById('settings-validation-modal');
+    const list = document.getElementById('settings-validation-list');
+    const summary = document.getElementById('settings-validation-summary');
+    if (!modal || !list || !summary) {
+        return;
+    }
+    try {
+        const response = await fetch('/api/settings-validation');
+        if (!response.ok) {
+            return;
+        }
+        const validation = await response.json();
+        const issues = validation.issues || [];
+        if (issues.length === 0) {
+            return;
+        }
+        
+        const errors = issues.filter(issue => issue.severity === 'error').length;
+        summary.textContent = `${validation.config_file}: ${errors} error(s), ${issues.length - errors} warning(s).`;
+        list.innerHTML = '';
+        for (const issue of issues) {
+            const item = document.createElement('li');
+            item.style.padding = '0.4rem 0';
+            item.style.borderBottom = '1px solid #3a3a3a';
+            const location = [issue.path, issue.line ? `line ${issue.line}` : ''].filter(Boolean).join(', ');
+            item.textContent = `${issue.severity === 'error' ? '❌' : '⚠️'} ${location ? location + ': ' : ''}${issue.message}`;
+            list.appendChild(item);
+        }
+        
+        const closeBtn = document.getElementById('settings-validation-close');
+        if (closeBtn) {
+            closeBtn.onclick = () => { modal.style.display = 'none'; };
+        }
+        modal.style.display = 'block';
+        await logToBackend('WARN', `[Settings] config.yaml has ${issues.length} validation issue(s)`);
+    } catch (error) {
+        console.warn('[Settings] Failed to load settings validation:', error);
+    }
+}
+
 // Show startup wizard
 function showStartupWizard() {
     // Hide loading overlay
@@ -0,0 +1,47 @@
+package alerts
+
+import "fmt"
+
+// Trigger is one time a rule fired
+type Trigger struct {
+	Timestamp float64 `json:"timestamp"`
+	Value     float64 `json:"value"`     // Field value at the trigger
+	Threshold float64 `json:"threshold"` // Threshold (fixed value or target series) at the trigger
+}
+
+// BacktestResult lists when a rule would have fired on a past date
+type BacktestResult struct {
+	Rule          Rule      `json:"rule"`
+	Date          string    `json:"date"`
+	RowsEvaluated int       `json:"rows_evaluated"`
+	Triggers      []Trigger `json:"triggers"`
+}
+
+// RowStream replays a day's rows in timestamp order, calling fn with the requested columns
+type RowStream func(columns []string, fn func(timestamp float64, row map[string]float64) error) error
+
+// Backtest streams a day's rows through the rule engine (off the live path) and returns the trigger timestamps
+func Backtest(rule Rule, date string, stream RowStream) (*BacktestResult, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := &BacktestResult{Rule: rule, Date: date, Triggers: make([]Trigger, 0)}
+	evaluator := NewEvaluator(rule)
+	err := stream(rule.Columns(), func(timestamp float64, row map[string]float64) error {
+		result.RowsEvaluated++
+		if evaluator.Evaluate(timestamp, row) {
+			value, threshold := rule.TriggerValues(row)
+			result.Triggers = append(result.Triggers, Trigger{
+				Timestamp: timestamp,
+				Value:     value,
+				Threshold: threshold,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay %s on %s: %w", rule.Ticker, date, err)
+	}
+	return result, nil
+}
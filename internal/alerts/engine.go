@@ -0,0 +1,131 @@
+package alerts
+
+import (
+	"math"
+	"sync"
+)
+
+// Alert is one live trigger of a rule
+type Alert struct {
+	Seq    int64  `json:"seq"` // Increases with every trigger (pollers ask for alerts after the last seq they saw)
+	Ticker string `json:"ticker"`
+	Rule   Rule   `json:"rule"`
+	Trigger
+}
+
+// Engine evaluates the configured rules against rows as they are collected
+// Each rule keeps its Evaluator across rows, so live triggers match what Backtest reports for the same day
+type Engine struct {
+	mu         sync.Mutex
+	rules      []Rule
+	evaluators map[string]*Evaluator // Rule ID -> evaluator
+	recent     []Alert               // Last recentLimit triggers, oldest first
+	recentMax  int
+	nextSeq    int64
+	onTrigger  func(alert Alert)
+}
+
+// NewEngine creates an engine without rules that remembers the last recentLimit triggers
+func NewEngine(recentLimit int) *Engine {
+	return &Engine{evaluators: make(map[string]*Evaluator), recentMax: recentLimit, nextSeq: 1}
+}
+
+// SetRules replaces the rules; an unchanged rule keeps its state (a settings save doesn't re-fire "above" rules)
+func (e *Engine) SetRules(rules []Rule) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	evaluators := make(map[string]*Evaluator, len(rules))
+	for _, rule := range rules {
+		if existing, ok := e.evaluators[rule.ID]; ok && existing.rule == rule {
+			evaluators[rule.ID] = existing
+		} else {
+			evaluators[rule.ID] = NewEvaluator(rule)
+		}
+	}
+	e.rules = append([]Rule(nil), rules...)
+	e.evaluators = evaluators
+}
+
+// Rules returns the current rules
+func (e *Engine) Rules() []Rule {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Rule(nil), e.rules...)
+}
+
+// SetOnTrigger sets the callback for a rule firing (e.g. to play its sound and notify the UI)
+func (e *Engine) SetOnTrigger(onTrigger func(alert Alert)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.onTrigger = onTrigger
+}
+
+// Evaluate feeds a collected row of a ticker to that ticker's rules
+func (e *Engine) Evaluate(ticker string, timestamp float64, data map[string]interface{}) {
+	e.mu.Lock()
+	fired := make([]Alert, 0)
+	for _, rule := range e.rules {
+		if rule.Ticker != ticker {
+			continue
+		}
+		row := make(map[string]float64, 2)
+		if rule.Condition == ConditionScript {
+			// Live scripts see every numeric field of the row, not only the fields they list for back-tests
+			for column, value := range data {
+				if v := rowValue(value); !math.IsNaN(v) {
+					row[column] = v
+				}
+			}
+		} else {
+			for _, column := range rule.Columns() {
+				row[column] = rowValue(data[column])
+			}
+		}
+		if !e.evaluators[rule.ID].Evaluate(timestamp, row) {
+			continue
+		}
+		value, threshold := rule.TriggerValues(row)
+		alert := Alert{Seq: e.nextSeq, Ticker: ticker, Rule: rule, Trigger: Trigger{Timestamp: timestamp, Value: value, Threshold: threshold}}
+		e.nextSeq++
+		e.recent = append(e.recent, alert)
+		fired = append(fired, alert)
+	}
+	if len(e.recent) > e.recentMax {
+		e.recent = append([]Alert(nil), e.recent[len(e.recent)-e.recentMax:]...)
+	}
+	onTrigger := e.onTrigger
+	e.mu.Unlock()
+
+	if onTrigger != nil {
+		for _, alert := range fired {
+			onTrigger(alert)
+		}
+	}
+}
+
+// Recent returns the remembered triggers with a seq after afterSeq, oldest first
+func (e *Engine) Recent(afterSeq int64) []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	result := make([]Alert, 0)
+	for _, alert := range e.recent {
+		if alert.Seq > afterSeq {
+			result = append(result, alert)
+		}
+	}
+	return result
+}
+
+// rowValue converts a collected value to float64 (NaN when missing or not numeric)
+func rowValue(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	default:
+		return math.NaN()
+	}
+}
@@ -0,0 +1,71 @@
+package alerts
+
+import (
+	"fmt"
+	"math"
+)
+
+// Price alert directions
+const (
+	DirectionAbove = "above" // Spot crosses the level upwards
+	DirectionBelow = "below" // Spot crosses the level downwards
+	DirectionCross = "cross" // Either way
+)
+
+// priceAlertIDPrefix marks the engine rules made from price alerts
+const priceAlertIDPrefix = "price:"
+
+// PriceAlert is a horizontal alarm line on a ticker's chart: it fires once when spot crosses the level
+// (the line is removed then) and is evaluated as a crosses_* rule on spot
+type PriceAlert struct {
+	ID        string  `json:"id" yaml:"id"`
+	Ticker    string  `json:"ticker" yaml:"ticker"`
+	Level     float64 `json:"level" yaml:"level"`
+	Direction string  `json:"direction" yaml:"direction"` // above, below, cross
+	Sound     string  `json:"sound,omitempty" yaml:"sound"`
+	CreatedAt float64 `json:"created_at" yaml:"created_at"`
+}
+
+// Validate checks the level and direction
+func (p PriceAlert) Validate() error {
+	if p.Ticker == "" {
+		return fmt.Errorf("price alert %s has no ticker", p.ID)
+	}
+	if p.Level <= 0 || math.IsNaN(p.Level) || math.IsInf(p.Level, 0) {
+		return fmt.Errorf("price alert %s has an invalid level %v", p.ID, p.Level)
+	}
+	switch p.Direction {
+	case DirectionAbove, DirectionBelow, DirectionCross:
+	default:
+		return fmt.Errorf("price alert %s has unknown direction %q (expected above, below or cross)", p.ID, p.Direction)
+	}
+	return nil
+}
+
+// Rule returns the engine rule that watches the level
+func (p PriceAlert) Rule() Rule {
+	condition := ConditionCrosses
+	switch p.Direction {
+	case DirectionAbove:
+		condition = ConditionCrossesAbove
+	case DirectionBelow:
+		condition = ConditionCrossesBelow
+	}
+	return Rule{
+		ID:        priceAlertIDPrefix + p.ID,
+		Name:      fmt.Sprintf("%s %s %.2f", p.Ticker, p.Direction, p.Level),
+		Ticker:    p.Ticker,
+		Field:     "spot",
+		Condition: condition,
+		Value:     p.Level,
+		Sound:     p.Sound,
+	}
+}
+
+// PriceAlertID returns the price alert a rule was made from ("" if it is an ordinary rule)
+func PriceAlertID(rule Rule) string {
+	if len(rule.ID) > len(priceAlertIDPrefix) && rule.ID[:len(priceAlertIDPrefix)] == priceAlertIDPrefix {
+		return rule.ID[len(priceAlertIDPrefix):]
+	}
+	return ""
+}
@@ -0,0 +1,219 @@
+package alerts
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// Alert rule conditions
+const (
+	ConditionAbove        = "above"         // Field moves above the threshold
+	ConditionBelow        = "below"         // Field moves below the threshold
+	ConditionCrossesAbove = "crosses_above" // Field crosses the threshold from below
+	ConditionCrossesBelow = "crosses_below" // Field crosses the threshold from above
+	ConditionCrosses      = "crosses"       // Either direction
+	ConditionScript       = "script"        // The Script's alert() turns true
+)
+
+// Rule is an alert rule evaluated against a ticker's rows
+// The threshold is either a fixed Value or another series (Target), e.g. spot crosses zero_gamma
+type Rule struct {
+	ID          string  `json:"id" yaml:"id"`
+	Name        string  `json:"name" yaml:"name"`
+	Ticker      string  `json:"ticker" yaml:"ticker"`
+	Field       string  `json:"field" yaml:"field"`               // Watched series (e.g. "spot")
+	Condition   string  `json:"condition" yaml:"condition"`       // above, below, crosses_above, crosses_below, crosses
+	Value       float64 `json:"value" yaml:"value"`               // Fixed threshold (used when Target is empty)
+	Target      string  `json:"target,omitempty" yaml:"target"`   // Threshold series (e.g. "zero_gamma")
+	CooldownSec float64 `json:"cooldown_sec" yaml:"cooldown_sec"` // Minimum time between triggers
+	Sound       string  `json:"sound,omitempty" yaml:"sound"`     // Bundled sound name or .wav path; "" = live_alerts.default_sound, "none" = silent
+	Script      string  `json:"script,omitempty" yaml:"script"`   // Script whose alert(ticker, row) is the condition (condition: script)
+}
+
+// ScriptRunner evaluates the alert() function of user scripts (internal/scripting)
+type ScriptRunner interface {
+	Condition(script, ticker string, timestamp float64, row map[string]float64) (bool, error)
+	Fields(script string) []string // Columns the script reads (its fields list)
+}
+
+var (
+	scriptRunnerMu sync.RWMutex
+	scriptRunner   ScriptRunner
+)
+
+// SetScriptRunner sets what evaluates "script" rules (nil = they never fire)
+func SetScriptRunner(runner ScriptRunner) {
+	scriptRunnerMu.Lock()
+	defer scriptRunnerMu.Unlock()
+	scriptRunner = runner
+}
+
+func getScriptRunner() ScriptRunner {
+	scriptRunnerMu.RLock()
+	defer scriptRunnerMu.RUnlock()
+	return scriptRunner
+}
+
+// Validate checks the rule has a field (or a script) and a known condition
+func (r Rule) Validate() error {
+	if r.Condition == ConditionScript {
+		if r.Script == "" {
+			return fmt.Errorf("alert rule %q has condition script but no script", r.Name)
+		}
+		if r.CooldownSec < 0 {
+			return fmt.Errorf("alert rule %q has a negative cooldown", r.Name)
+		}
+		return nil
+	}
+	if r.Field == "" {
+		return fmt.Errorf("alert rule %q has no field", r.Name)
+	}
+	switch r.Condition {
+	case ConditionAbove, ConditionBelow, ConditionCrossesAbove, ConditionCrossesBelow, ConditionCrosses:
+	default:
+		return fmt.Errorf("alert rule %q has unknown condition %q", r.Name, r.Condition)
+	}
+	if r.Target == r.Field {
+		return fmt.Errorf("alert rule %q compares %s with itself", r.Name, r.Field)
+	}
+	if r.CooldownSec < 0 {
+		return fmt.Errorf("alert rule %q has a negative cooldown", r.Name)
+	}
+	return nil
+}
+
+// Columns returns the series the rule reads (a script's fields plus the rule's field, or spot if it lists none)
+func (r Rule) Columns() []string {
+	if r.Condition == ConditionScript {
+		var columns []string
+		if runner := getScriptRunner(); runner != nil {
+			columns = runner.Fields(r.Script)
+		}
+		if r.Field != "" {
+			columns = append(columns, r.Field)
+		}
+		if len(columns) == 0 {
+			columns = []string{"spot"}
+		}
+		return columns
+	}
+	if r.Target != "" {
+		return []string{r.Field, r.Target}
+	}
+	return []string{r.Field}
+}
+
+// Evaluator tracks one rule's state across a stream of rows
+// The same evaluator serves live data and historical replays, so back-tests match live behaviour
+type Evaluator struct {
+	rule      Rule
+	lastSide  int // -1 below, +1 above, 0 unknown (no valid row yet)
+	lastFired float64
+	fired     bool
+}
+
+// NewEvaluator creates an evaluator for a rule
+func NewEvaluator(rule Rule) *Evaluator {
+	return &Evaluator{rule: rule}
+}
+
+// Evaluate feeds one row (values keyed by column) and reports whether the rule fires at timestamp
+// Rows missing the field or threshold (NULL/NaN, or a zero threshold series) are skipped
+func (e *Evaluator) Evaluate(timestamp float64, row map[string]float64) bool {
+	if e.rule.Condition == ConditionScript {
+		return e.evaluateScript(timestamp, row)
+	}
+	value, ok := row[e.rule.Field]
+	if !ok || math.IsNaN(value) {
+		return false
+	}
+	threshold := e.rule.Value
+	if e.rule.Target != "" {
+		threshold, ok = row[e.rule.Target]
+		if !ok || math.IsNaN(threshold) || threshold == 0 {
+			return false
+		}
+	}
+
+	// Which side of the threshold the value is on (equal keeps the previous side)
+	side := e.lastSide
+	if value > threshold {
+		side = 1
+	} else if value < threshold {
+		side = -1
+	}
+	previous := e.lastSide
+	e.lastSide = side
+	if side == 0 || side == previous {
+		return false
+	}
+
+	triggered := false
+	switch e.rule.Condition {
+	case ConditionAbove:
+		triggered = side > 0 // Includes the first row if it's already above
+	case ConditionBelow:
+		triggered = side < 0
+	case ConditionCrossesAbove:
+		triggered = previous < 0 && side > 0
+	case ConditionCrossesBelow:
+		triggered = previous > 0 && side < 0
+	case ConditionCrosses:
+		triggered = previous != 0
+	}
+	if !triggered {
+		return false
+	}
+
+	if e.fired && e.rule.CooldownSec > 0 && timestamp-e.lastFired < e.rule.CooldownSec {
+		return false
+	}
+	e.fired = true
+	e.lastFired = timestamp
+	return true
+}
+
+// evaluateScript fires when the script's condition turns true (including a first row that is already true)
+// A script error skips the row, like a missing value
+func (e *Evaluator) evaluateScript(timestamp float64, row map[string]float64) bool {
+	runner := getScriptRunner()
+	if runner == nil {
+		return false
+	}
+	condition, err := runner.Condition(e.rule.Script, e.rule.Ticker, timestamp, row)
+	if err != nil {
+		return false
+	}
+	side := -1
+	if condition {
+		side = 1
+	}
+	previous := e.lastSide
+	e.lastSide = side
+	if side < 0 || previous > 0 {
+		return false
+	}
+	if e.fired && e.rule.CooldownSec > 0 && timestamp-e.lastFired < e.rule.CooldownSec {
+		return false
+	}
+	e.fired = true
+	e.lastFired = timestamp
+	return true
+}
+
+// TriggerValues returns the value and threshold reported with a trigger: the field and the fixed value or target
+// series, or for a script rule its field (0 without one) and no threshold
+func (r Rule) TriggerValues(row map[string]float64) (value, threshold float64) {
+	if r.Condition == ConditionScript {
+		if v, ok := row[r.Field]; ok && r.Field != "" && !math.IsNaN(v) {
+			value = v
+		}
+		return value, 0
+	}
+	threshold = r.Value
+	if r.Target != "" {
+		threshold = row[r.Target]
+	}
+	return row[r.Field], threshold
+}
@@ -1,10 +1,12 @@
 package api
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -13,11 +15,15 @@ import (
 
 // Client handles HTTP requests to the GEXBot API
 type Client struct {
-	apiKey     string
-	baseURL    string
-	httpClient *http.Client
-	mu         sync.RWMutex
-	debugPrint func(string, string)
+	apiKey         string
+	additionalKeys []config.APIKeyConfig
+	keyPool        *KeyPool // Rotates requests among the primary and additional keys
+	baseURL        string
+	httpClient     *http.Client
+	policies       config.RequestPolicies // Timeout/retry policy (per-endpoint overrides)
+	recorder       ResponseRecorder       // Optional raw response recording (record mode)
+	mu             sync.RWMutex
+	debugPrint     func(string, string)
 }
 
 // NewClient creates a new API client with connection pooling
@@ -29,48 +35,142 @@ func NewClient(apiKey string, debugPrint func(string, string)) *Client {
 		IdleConnTimeout:     90 * time.Second,
 	}
 
+	// Timeouts are applied per attempt from the request policy (see FetchEndpoint)
 	httpClient := &http.Client{
 		Transport: transport,
-		Timeout:   30 * time.Second,
 	}
 
 	return &Client{
 		apiKey:     apiKey,
+		keyPool:    NewKeyPool(apiKey, nil),
 		baseURL:    config.APIBaseURL,
 		httpClient: httpClient,
+		policies:   config.DefaultRequestPolicies(),
 		debugPrint: debugPrint,
 	}
 }
 
+// SetRequestPolicies updates the timeout/retry policies (applies to the next request)
+func (c *Client) SetRequestPolicies(policies config.RequestPolicies) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.policies = policies
+}
+
+// retryDelay returns the exponential backoff delay before retry number attempt (0-based), with jitter
+func retryDelay(policy config.RequestPolicy, attempt int) time.Duration {
+	delayMs := float64(policy.BackoffBaseMs)
+	for i := 0; i < attempt && delayMs < float64(policy.BackoffMaxMs); i++ {
+		delayMs *= 2
+	}
+	if delayMs > float64(policy.BackoffMaxMs) {
+		delayMs = float64(policy.BackoffMaxMs)
+	}
+	if policy.JitterPercent > 0 {
+		delayMs -= delayMs * float64(policy.JitterPercent) / 100 * rand.Float64()
+	}
+	return time.Duration(delayMs * float64(time.Millisecond))
+}
+
+// parseRetryAfter parses a Retry-After header (seconds or HTTP date); returns 0 if absent or invalid
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(when); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+// isTransientStatus reports whether an HTTP status is worth retrying
+func isTransientStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// sleepContext waits for d, returning early with ctx's error if it is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // FetchEndpoint fetches data from a specific API endpoint
 func (c *Client) FetchEndpoint(endpoint, ticker string) (map[string]interface{}, error) {
-	// Get endpoint URL template
+	return c.FetchEndpointContext(context.Background(), endpoint, ticker)
+}
+
+// FetchEndpointContext is FetchEndpoint with cancellation: cancelling ctx aborts the in-flight
+// request and any retry backoff (e.g. on shutdown) and returns ctx's error
+func (c *Client) FetchEndpointContext(parent context.Context, endpoint, ticker string) (map[string]interface{}, error) {
+	// Get endpoint URL template (built-in, or user-defined in custom_endpoints)
 	urlTemplate, ok := Endpoints[endpoint]
-	if !ok {
+	custom, isCustom := getCustomEndpoint(endpoint)
+	if !ok && !isCustom {
 		return nil, fmt.Errorf("unknown endpoint: %s", endpoint)
 	}
 
+	// Pick the API key for this request (rotates among configured keys)
+	apiKey := c.keyPool.Acquire(ticker)
+
 	// Build URL
-	url := fmt.Sprintf(urlTemplate, c.baseURL, ticker, c.apiKey)
+	var url string
+	if isCustom {
+		url = customEndpointURL(custom, c.baseURL, ticker, apiKey)
+	} else {
+		url = fmt.Sprintf(urlTemplate, c.baseURL, ticker, apiKey)
+	}
 
-	// Retry logic for transient errors
-	maxRetries := 3
-	retryDelays := []time.Duration{100 * time.Millisecond, 500 * time.Millisecond, 1 * time.Second}
+	// Retry logic for transient errors (timeouts and backoff from the endpoint's request policy)
+	c.mu.RLock()
+	policy := c.policies.ForEndpoint(endpoint)
+	c.mu.RUnlock()
+	maxRetries := policy.MaxAttempts
+	timeout := time.Duration(policy.TimeoutSec * float64(time.Second))
 
 	var lastErr error
 	for attempt := 0; attempt < maxRetries; attempt++ {
+		if err := parent.Err(); err != nil {
+			return nil, err
+		}
 		requestStartTime := time.Now()
 		
 		c.debugPrint(fmt.Sprintf("API: Fetching %s for %s (attempt %d/%d)", endpoint, ticker, attempt+1, maxRetries), "api")
 
-		// Make HTTP request
-		resp, err := c.httpClient.Get(url)
+		// Make HTTP request (the timeout covers reading the body too)
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to build request: %w", err)
+		}
+		resp, err := c.httpClient.Do(req)
 		if err != nil {
+			cancel()
+			if parent.Err() != nil {
+				return nil, parent.Err() // Cancelled, not a network error - don't retry
+			}
 			lastErr = err
 			if attempt < maxRetries-1 {
-				delay := retryDelays[attempt]
+				delay := retryDelay(policy, attempt)
 				c.debugPrint(fmt.Sprintf("⏳ Request error fetching %s for %s (attempt %d/%d) - retrying in %v", endpoint, ticker, attempt+1, maxRetries, delay), "api")
-				time.Sleep(delay)
+				if err := sleepContext(parent, delay); err != nil {
+					return nil, err
+				}
 				continue
 			}
 			return nil, fmt.Errorf("request error after %d attempts: %w", maxRetries, err)
@@ -82,12 +182,14 @@ func (c *Client) FetchEndpoint(endpoint, ticker string) (map[string]interface{},
 		// Check status code
 		if resp.StatusCode == 401 {
 			resp.Body.Close()
+			cancel()
 			return nil, &SubscriptionError{
 				Endpoint: endpoint,
 				Message:  fmt.Sprintf("Unauthorized access to %s for %s. Check API key and subscription tier.", endpoint, ticker),
 			}
 		} else if resp.StatusCode == 403 {
 			resp.Body.Close()
+			cancel()
 			return nil, &SubscriptionError{
 				Endpoint: endpoint,
 				Message:  fmt.Sprintf("Access forbidden to %s for %s. This endpoint requires a subscription tier you don't have.", endpoint, ticker),
@@ -96,49 +198,82 @@ func (c *Client) FetchEndpoint(endpoint, ticker string) (map[string]interface{},
 			// Rate limit exceeded
 			retryAfter := resp.Header.Get("Retry-After")
 			resp.Body.Close()
+			cancel()
+			c.keyPool.RecordRateLimit(apiKey, retryAfter)
 			return nil, &RateLimitError{
 				Endpoint:  endpoint,
 				Message:   fmt.Sprintf("Rate limit exceeded for %s on %s", endpoint, ticker),
 				RetryAfter: retryAfter,
+				KeyName:   c.keyPool.NameOf(apiKey),
 			}
 		} else if resp.StatusCode != 200 {
 			// Read error body
 			body, _ := io.ReadAll(resp.Body)
 			resp.Body.Close()
+			cancel()
 			bodyStr := string(body)
 			if len(bodyStr) > 200 {
 				bodyStr = bodyStr[:200]
 			}
-			return nil, &RequestError{
+			requestErr := &RequestError{
 				Endpoint:   endpoint,
 				StatusCode: resp.StatusCode,
 				Message:    fmt.Sprintf("HTTP %d error fetching %s for %s: %s", resp.StatusCode, endpoint, ticker, bodyStr),
 			}
+
+			// Transient server errors are retried, honoring Retry-After when the server sends one
+			if isTransientStatus(resp.StatusCode) && attempt < maxRetries-1 {
+				delay := retryDelay(policy, attempt)
+				if retryAfter := parseRetryAfter(resp.Header.Get("Retry-After")); retryAfter > 0 {
+					if retryAfter > time.Duration(config.MaxRetryAfterWaitSec)*time.Second {
+						return nil, requestErr // Server asked for a long pause - give up until the next poll
+					}
+					delay = retryAfter
+				}
+				lastErr = requestErr
+				c.debugPrint(fmt.Sprintf("⏳ HTTP %d fetching %s for %s (attempt %d/%d) - retrying in %v", resp.StatusCode, endpoint, ticker, attempt+1, maxRetries, delay), "api")
+				if err := sleepContext(parent, delay); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			return nil, requestErr
 		}
 
 		// Read response body
 		body, err := io.ReadAll(resp.Body)
 		resp.Body.Close()
+		cancel()
 		if err != nil {
 			lastErr = err
 			if attempt < maxRetries-1 {
-				delay := retryDelays[attempt]
-				time.Sleep(delay)
+				delay := retryDelay(policy, attempt)
+				if err := sleepContext(parent, delay); err != nil {
+					return nil, err
+				}
 				continue
 			}
 			return nil, fmt.Errorf("failed to read response body: %w", err)
 		}
 
-		// Parse JSON
-		var data map[string]interface{}
-		if err := json.Unmarshal(body, &data); err != nil {
-			return nil, &RequestError{
-				Endpoint:      endpoint,
-				Message:       fmt.Sprintf("Invalid JSON response from %s for %s: %v", endpoint, ticker, err),
-				OriginalError: err,
-			}
+		// Record the raw body before parsing, so responses the parser rejects can be replayed after a fix
+		c.mu.RLock()
+		recorder := c.recorder
+		c.mu.RUnlock()
+		if recorder != nil {
+			recorder.RecordResponse(parent, endpoint, ticker, body, time.Now())
 		}
 
+		// Parse JSON into canonical fields (seconds timestamps, float64 scalars)
+		snapshot, err := ParseSnapshot(endpoint, ticker, body)
+		if err != nil {
+			return nil, err
+		}
+		if len(snapshot.Dropped) > 0 {
+			c.debugPrint(fmt.Sprintf("API: Dropped unconvertible fields from %s for %s: %v", endpoint, ticker, snapshot.Dropped), "api")
+		}
+		data := snapshot.Row()
+
 		// Extract rate limit headers
 		rateLimitHeaders := make(map[string]string)
 		for _, headerName := range []string{"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After"} {
@@ -150,8 +285,12 @@ func (c *Client) FetchEndpoint(endpoint, ticker string) (map[string]interface{},
 		// Add headers to response data
 		if len(rateLimitHeaders) > 0 {
 			data["_response_headers"] = rateLimitHeaders
+			c.keyPool.RecordHeaders(apiKey, rateLimitHeaders)
 		}
 
+		// Name the key that made the request, for per-key rate limit tracking
+		data["_api_key_name"] = c.keyPool.NameOf(apiKey)
+
 		// Add response time
 		data["_response_time"] = responseTime.Seconds()
 		
@@ -169,6 +308,21 @@ func (c *Client) SetAPIKey(apiKey string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.apiKey = apiKey
+	c.keyPool.SetKeys(c.apiKey, c.additionalKeys)
+}
+
+// SetAdditionalAPIKeys updates the additional API keys rotated alongside the primary key
+func (c *Client) SetAdditionalAPIKeys(keys []config.APIKeyConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.additionalKeys = keys
+	c.keyPool.SetKeys(c.apiKey, c.additionalKeys)
+	c.debugPrint(fmt.Sprintf("API: Key pool updated (%d key(s))", c.keyPool.Size()), "api")
+}
+
+// GetKeyStats returns per-key usage and rate limit statistics (keys are masked)
+func (c *Client) GetKeyStats() []KeyStats {
+	return c.keyPool.GetStats()
 }
 
 // Close closes the HTTP client (releases connections)
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"market-terminal/internal/config"
+)
+
+// Custom endpoints from settings (custom_endpoints), treated like built-ins by planning, validation and fetching
+var (
+	customEndpoints     = make(map[string]config.CustomEndpoint)
+	customEndpointsLock sync.RWMutex
+)
+
+// IsBuiltInEndpoint reports whether name is one of the built-in endpoints
+func IsBuiltInEndpoint(name string) bool {
+	_, ok := Endpoints[name]
+	return ok
+}
+
+// SetCustomEndpoints replaces the registered custom endpoints
+// Invalid definitions (or names clashing with built-ins) are skipped and returned as an error
+func SetCustomEndpoints(endpoints []config.CustomEndpoint) error {
+	registered := make(map[string]config.CustomEndpoint, len(endpoints))
+	var problems []string
+	for _, endpoint := range endpoints {
+		if err := endpoint.Validate(); err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		if IsBuiltInEndpoint(endpoint.Name) {
+			problems = append(problems, fmt.Sprintf("custom endpoint %s: name is already a built-in endpoint", endpoint.Name))
+			continue
+		}
+		registered[endpoint.Name] = endpoint
+	}
+
+	customEndpointsLock.Lock()
+	customEndpoints = registered
+	customEndpointsLock.Unlock()
+
+	if len(problems) > 0 {
+		return fmt.Errorf("skipped invalid custom endpoints: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// getCustomEndpoint returns a registered custom endpoint
+func getCustomEndpoint(name string) (config.CustomEndpoint, bool) {
+	customEndpointsLock.RLock()
+	defer customEndpointsLock.RUnlock()
+	endpoint, ok := customEndpoints[name]
+	return endpoint, ok
+}
+
+// EndpointExists reports whether name is a built-in or registered custom endpoint
+func EndpointExists(name string) bool {
+	if IsBuiltInEndpoint(name) {
+		return true
+	}
+	_, ok := getCustomEndpoint(name)
+	return ok
+}
+
+// customEndpointsFor returns custom endpoint names in the given tiers (chartOnly: only those marked chart), sorted
+func customEndpointsFor(tiers []string, chartOnly bool) []string {
+	customEndpointsLock.RLock()
+	defer customEndpointsLock.RUnlock()
+
+	names := make([]string, 0)
+	for name, endpoint := range customEndpoints {
+		if chartOnly && !endpoint.Chart {
+			continue
+		}
+		for _, tier := range tiers {
+			if endpoint.Tier == tier {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// customEndpointURL expands a custom endpoint's URL template
+func customEndpointURL(endpoint config.CustomEndpoint, baseURL, ticker, apiKey string) string {
+	return strings.NewReplacer(
+		"{base}", baseURL,
+		"{ticker}", url.PathEscape(ticker),
+		"{key}", url.QueryEscape(apiKey),
+	).Replace(endpoint.URLTemplate)
+}
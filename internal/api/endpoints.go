@@ -87,6 +87,9 @@ func GetEndpointsForTiers(tiers []string) []string {
 		}
 	}
 
+	// User-defined endpoints (custom_endpoints setting) in the same tiers
+	result = append(result, customEndpointsFor(tiers, false)...)
+
 	return result
 }
 
@@ -104,7 +107,7 @@ func GetChartEndpointsForTiers(tiers []string) []string {
 			"gamma_zero", // State tier gamma data
 		},
 		"orderflow": {
-			// Orderflow doesn't have specific chart endpoints yet
+			"orderflow", // delta, volume (order flow chart view)
 		},
 	}
 
@@ -122,6 +125,9 @@ func GetChartEndpointsForTiers(tiers []string) []string {
 		}
 	}
 
+	// User-defined endpoints marked for chart-only collection
+	result = append(result, customEndpointsFor(tiers, true)...)
+
 	return result
 }
 
@@ -156,5 +162,8 @@ func GetEndpointTier(endpoint string) string {
 	if orderflowEndpoints[endpoint] {
 		return "orderflow"
 	}
+	if custom, ok := getCustomEndpoint(endpoint); ok {
+		return custom.Tier
+	}
 	return "" // Unknown endpoint
 }
@@ -32,6 +32,7 @@ type RateLimitError struct {
 	Endpoint  string
 	Message   string
 	RetryAfter string
+	KeyName   string // Name of the API key that was limited (see KeyPool.NameOf)
 }
 
 func (e *RateLimitError) Error() string {
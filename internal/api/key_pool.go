@@ -0,0 +1,255 @@
+package api
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// apiKeyState tracks usage and rate limit state for a single API key
+type apiKeyState struct {
+	name             string
+	key              string
+	tickers          map[string]bool // Empty = shared key (any ticker)
+	requests         int64
+	rateLimitHits    int64
+	remaining        int       // From X-RateLimit-Remaining (-1 = unknown)
+	rateLimitedUntil time.Time // Skip this key until then (after a 429)
+}
+
+// KeyStats is a snapshot of a single API key's usage (key is masked)
+type KeyStats struct {
+	Name             string   `json:"name"`
+	MaskedKey        string   `json:"masked_key"`
+	Tickers          []string `json:"tickers"`
+	Requests         int64    `json:"requests"`
+	RateLimitHits    int64    `json:"rate_limit_hits"`
+	Remaining        int      `json:"remaining"`
+	RateLimited      bool     `json:"rate_limited"`
+	RateLimitedUntil float64  `json:"rate_limited_until"`
+}
+
+// KeyPool rotates requests among several API keys, tracking rate limits per key
+// Tickers assigned to dedicated keys use only those keys; all others share the unassigned keys
+type KeyPool struct {
+	mu   sync.Mutex
+	keys []*apiKeyState
+	next map[string]int // rotation group -> next index
+}
+
+// NewKeyPool creates a key pool from the primary key and any additional keys
+func NewKeyPool(primaryKey string, additional []config.APIKeyConfig) *KeyPool {
+	kp := &KeyPool{next: make(map[string]int)}
+	kp.SetKeys(primaryKey, additional)
+	return kp
+}
+
+// SetKeys replaces the keys in the pool
+// Usage stats are kept for keys that are still present
+func (kp *KeyPool) SetKeys(primaryKey string, additional []config.APIKeyConfig) {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+
+	existing := make(map[string]*apiKeyState)
+	for _, state := range kp.keys {
+		existing[state.key] = state
+	}
+
+	keys := make([]*apiKeyState, 0, len(additional)+1)
+	seen := make(map[string]bool)
+	add := func(name, key string, tickers []string) {
+		if key == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+
+		state, ok := existing[key]
+		if !ok {
+			state = &apiKeyState{key: key, remaining: -1}
+		}
+		state.name = name
+		state.tickers = make(map[string]bool)
+		for _, ticker := range tickers {
+			state.tickers[ticker] = true
+		}
+		keys = append(keys, state)
+	}
+
+	add(config.PrimaryAPIKeyName, primaryKey, nil)
+	for i, keyConfig := range additional {
+		if !keyConfig.Enabled {
+			continue
+		}
+		name := keyConfig.Name
+		if name == "" {
+			name = "key-" + strconv.Itoa(i+1)
+		}
+		add(name, keyConfig.Key, keyConfig.Tickers)
+	}
+
+	kp.keys = keys
+	kp.next = make(map[string]int)
+}
+
+// Acquire picks the key to use for a ticker and counts the request against it
+// Rotates round-robin, skipping rate-limited keys; if every candidate is limited,
+// returns the one whose limit expires soonest. Returns "" if no keys are configured.
+func (kp *KeyPool) Acquire(ticker string) string {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+
+	group := ticker
+	candidates := kp.candidatesLocked(ticker)
+	if len(candidates) == 0 {
+		group = ""
+		candidates = kp.sharedLocked()
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	now := time.Now()
+	start := kp.next[group] % len(candidates)
+	var chosen *apiKeyState
+	for i := 0; i < len(candidates); i++ {
+		idx := (start + i) % len(candidates)
+		if !now.Before(candidates[idx].rateLimitedUntil) {
+			chosen = candidates[idx]
+			kp.next[group] = idx + 1
+			break
+		}
+	}
+	if chosen == nil {
+		for _, state := range candidates {
+			if chosen == nil || state.rateLimitedUntil.Before(chosen.rateLimitedUntil) {
+				chosen = state
+			}
+		}
+	}
+
+	chosen.requests++
+	return chosen.key
+}
+
+// candidatesLocked returns keys dedicated to a ticker (caller holds mu)
+func (kp *KeyPool) candidatesLocked(ticker string) []*apiKeyState {
+	candidates := make([]*apiKeyState, 0)
+	for _, state := range kp.keys {
+		if state.tickers[ticker] {
+			candidates = append(candidates, state)
+		}
+	}
+	return candidates
+}
+
+// sharedLocked returns keys not dedicated to any ticker (caller holds mu)
+func (kp *KeyPool) sharedLocked() []*apiKeyState {
+	shared := make([]*apiKeyState, 0)
+	for _, state := range kp.keys {
+		if len(state.tickers) == 0 {
+			shared = append(shared, state)
+		}
+	}
+	return shared
+}
+
+// findLocked returns the state for a key (caller holds mu)
+func (kp *KeyPool) findLocked(key string) *apiKeyState {
+	for _, state := range kp.keys {
+		if state.key == key {
+			return state
+		}
+	}
+	return nil
+}
+
+// RecordHeaders updates a key's remaining quota from response headers
+// If the quota is exhausted, the key is skipped until the reset time
+func (kp *KeyPool) RecordHeaders(key string, headers map[string]string) {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+
+	state := kp.findLocked(key)
+	if state == nil {
+		return
+	}
+
+	if remaining, err := strconv.Atoi(headers["X-RateLimit-Remaining"]); err == nil {
+		state.remaining = remaining
+		if remaining <= 0 {
+			if reset, err := strconv.ParseFloat(headers["X-RateLimit-Reset"], 64); err == nil && reset > 0 {
+				state.rateLimitedUntil = time.Unix(int64(reset), 0)
+			}
+		}
+	}
+}
+
+// RecordRateLimit marks a key as rate limited after a 429
+// retryAfter is the Retry-After header value in seconds (defaults to 60s if missing)
+func (kp *KeyPool) RecordRateLimit(key string, retryAfter string) {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+
+	state := kp.findLocked(key)
+	if state == nil {
+		return
+	}
+
+	wait := 60 * time.Second
+	if seconds, err := strconv.ParseFloat(retryAfter, 64); err == nil && seconds > 0 {
+		wait = time.Duration(seconds * float64(time.Second))
+	}
+	state.rateLimitHits++
+	state.remaining = 0
+	state.rateLimitedUntil = time.Now().Add(wait)
+}
+
+// NameOf returns the name of a key in the pool ("primary" or the configured name), or "" if it isn't in the pool
+func (kp *KeyPool) NameOf(key string) string {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+
+	if state := kp.findLocked(key); state != nil {
+		return state.name
+	}
+	return ""
+}
+
+// Size returns the number of keys in the pool
+func (kp *KeyPool) Size() int {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+	return len(kp.keys)
+}
+
+// GetStats returns usage statistics for every key (keys are masked)
+func (kp *KeyPool) GetStats() []KeyStats {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+
+	now := time.Now()
+	stats := make([]KeyStats, 0, len(kp.keys))
+	for _, state := range kp.keys {
+		tickers := make([]string, 0, len(state.tickers))
+		for ticker := range state.tickers {
+			tickers = append(tickers, ticker)
+		}
+		limitedUntil := 0.0
+		if now.Before(state.rateLimitedUntil) {
+			limitedUntil = float64(state.rateLimitedUntil.UnixNano()) / 1e9
+		}
+		stats = append(stats, KeyStats{
+			Name:             state.name,
+			MaskedKey:        config.MaskAPIKey(state.key),
+			Tickers:          tickers,
+			Requests:         state.requests,
+			RateLimitHits:    state.rateLimitHits,
+			Remaining:        state.remaining,
+			RateLimited:      limitedUntil > 0,
+			RateLimitedUntil: limitedUntil,
+		})
+	}
+	return stats
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"market-terminal/internal/model"
+)
+
+// FieldKind is how a response field is converted to its canonical form
+type FieldKind int
+
+const (
+	FieldAuto      FieldKind = iota // Inferred: numbers, numeric strings and booleans are scalars, arrays/objects profiles
+	FieldNumber                     // Scalar: numeric strings and booleans are converted, anything else is dropped
+	FieldTimestamp                  // Unix time in seconds (millisecond values are converted)
+	FieldProfile                    // Array or object, stored in the profiles blob
+	FieldText                       // Kept as a string (never a column)
+)
+
+// FieldRule says what a response field becomes
+type FieldRule struct {
+	Column string    // Canonical name ("" = the response field's own name)
+	Kind   FieldKind // How the value is converted
+}
+
+// FieldMap declares how an endpoint's response fields are normalized
+type FieldMap struct {
+	Fields map[string]FieldRule // Response field -> rule (fields not listed are FieldAuto)
+	Strict bool                 // Drop fields not listed (custom endpoints with a column mapping)
+}
+
+// baseFields apply to every endpoint
+var baseFields = map[string]FieldRule{
+	"timestamp": {Kind: FieldTimestamp},
+	"ticker":    {Kind: FieldText},
+}
+
+// builtinFieldMaps declare the fields the charts depend on per built-in endpoint (path.Match patterns; every
+// matching pattern applies). Declaring them as numbers keeps a malformed value from becoming a profile
+var builtinFieldMaps = []struct {
+	pattern string
+	fields  map[string]FieldRule
+}{
+	{"classic_*", map[string]FieldRule{"spot": {Kind: FieldNumber}, "zero_gamma": {Kind: FieldNumber}}},
+	{"state_*", map[string]FieldRule{"spot": {Kind: FieldNumber}, "zero_gamma": {Kind: FieldNumber}}},
+	{"*_majors", map[string]FieldRule{
+		"major_pos_vol": {Kind: FieldNumber}, "major_neg_vol": {Kind: FieldNumber},
+		"major_pos_oi": {Kind: FieldNumber}, "major_neg_oi": {Kind: FieldNumber},
+		"major_positive": {Kind: FieldNumber}, "major_negative": {Kind: FieldNumber},
+		"major_long_gamma": {Kind: FieldNumber}, "major_short_gamma": {Kind: FieldNumber},
+	}},
+	{"gamma_*", map[string]FieldRule{
+		"spot": {Kind: FieldNumber}, "zero_gamma": {Kind: FieldNumber},
+		"major_long_gamma": {Kind: FieldNumber}, "major_short_gamma": {Kind: FieldNumber},
+	}},
+	{"orderflow", map[string]FieldRule{"spot": {Kind: FieldNumber}, "delta": {Kind: FieldNumber}, "volume": {Kind: FieldNumber}}},
+}
+
+// FieldMapFor returns the field map an endpoint's responses are normalized with
+func FieldMapFor(endpoint string) FieldMap {
+	fields := make(map[string]FieldRule, len(baseFields))
+	for field, rule := range baseFields {
+		fields[field] = rule
+	}
+
+	// Custom endpoints: the configured column mapping (only mapped fields are kept)
+	if custom, isCustom := getCustomEndpoint(endpoint); isCustom {
+		for field, column := range custom.Columns {
+			fields[field] = FieldRule{Column: column}
+		}
+		return FieldMap{Fields: fields, Strict: len(custom.Columns) > 0}
+	}
+
+	for _, builtin := range builtinFieldMaps {
+		if matched, _ := path.Match(builtin.pattern, endpoint); matched {
+			for field, rule := range builtin.fields {
+				fields[field] = rule
+			}
+		}
+	}
+	return FieldMap{Fields: fields}
+}
+
+// Normalize converts a decoded endpoint response into a snapshot using the endpoint's field map
+func Normalize(endpoint, ticker string, data map[string]interface{}) *model.TickerSnapshot {
+	fieldMap := FieldMapFor(endpoint)
+	snapshot := model.NewTickerSnapshot(endpoint, ticker)
+
+	for field, value := range data {
+		rule, declared := fieldMap.Fields[field]
+		if !declared && fieldMap.Strict {
+			continue
+		}
+		if value == nil {
+			continue
+		}
+		column := rule.Column
+		if column == "" {
+			column = field
+		}
+
+		ok := true
+		switch rule.Kind {
+		case FieldTimestamp:
+			var seconds float64
+			if seconds, ok = toNumber(value); ok && seconds > 0 {
+				if seconds > 1e10 {
+					seconds /= 1000 // Milliseconds
+				}
+				snapshot.Timestamp = seconds
+			} else {
+				ok = false
+			}
+		case FieldNumber:
+			var number float64
+			if number, ok = toNumber(value); ok {
+				snapshot.Scalars[column] = number
+			}
+		case FieldProfile:
+			if ok = isProfile(value); ok {
+				snapshot.Profiles[column] = value
+			}
+		case FieldText:
+			var text string
+			if text, ok = value.(string); ok {
+				snapshot.Text[column] = text
+			}
+		default:
+			if isProfile(value) {
+				snapshot.Profiles[column] = value
+			} else {
+				var number float64
+				if number, ok = toNumber(value); ok {
+					snapshot.Scalars[column] = number
+				}
+			}
+		}
+		if !ok {
+			snapshot.Dropped = append(snapshot.Dropped, field)
+		}
+	}
+	sort.Strings(snapshot.Dropped)
+	return snapshot
+}
+
+// toNumber converts a scalar response value: JSON numbers, numeric strings and booleans (1/0)
+func toNumber(value interface{}) (float64, bool) {
+	if text, ok := value.(string); ok {
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return 0, false
+		}
+		value = parsed
+	}
+	return model.Number(value)
+}
+
+func isProfile(value interface{}) bool {
+	switch value.(type) {
+	case []interface{}, map[string]interface{}:
+		return true
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
@@ -100,7 +101,7 @@ func (qs *QuerySystem) ValidateAndFilterQueries(queryPlan interface{}) []Query {
 		validEndpoints := make([]string, 0)
 		for _, endpoint := range item.Endpoints {
 			// Check if endpoint exists
-			if _, exists := Endpoints[endpoint]; !exists {
+			if !EndpointExists(endpoint) {
 				continue
 			}
 
@@ -138,6 +139,12 @@ func (qs *QuerySystem) ValidateAndFilterQueries(queryPlan interface{}) []Query {
 
 // ExecuteQueryPlan executes queries in parallel using goroutines
 func (qs *QuerySystem) ExecuteQueryPlan(queries []Query, maxWorkers int, resultCallback func(Query, map[string]interface{}, error)) {
+	qs.ExecuteQueryPlanContext(context.Background(), queries, maxWorkers, resultCallback)
+}
+
+// ExecuteQueryPlanContext is ExecuteQueryPlan with cancellation: once ctx is cancelled, in-flight
+// fetches abort and queries still waiting for a worker complete with ctx's error without fetching
+func (qs *QuerySystem) ExecuteQueryPlanContext(ctx context.Context, queries []Query, maxWorkers int, resultCallback func(Query, map[string]interface{}, error)) {
 	if len(queries) == 0 {
 		return
 	}
@@ -151,12 +158,19 @@ func (qs *QuerySystem) ExecuteQueryPlan(queries []Query, maxWorkers int, resultC
 		go func(q Query) {
 			defer wg.Done()
 
-			// Acquire semaphore
-			semaphore <- struct{}{}
+			// Acquire semaphore (or give up if cancelled while waiting)
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				if resultCallback != nil {
+					resultCallback(q, nil, ctx.Err())
+				}
+				return
+			}
 			defer func() { <-semaphore }()
 
 			// Fetch endpoint
-			result, err := qs.client.FetchEndpoint(q.Endpoint, q.Ticker)
+			result, err := qs.client.FetchEndpointContext(ctx, q.Endpoint, q.Ticker)
 			if err != nil {
 				qs.debugPrint(fmt.Sprintf("Error fetching %s for %s: %v", q.Endpoint, q.Ticker, err), "api")
 			}
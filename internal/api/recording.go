@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"market-terminal/internal/model"
+)
+
+// ResponseRecorder receives the raw body of every successful API response (record mode)
+// Implementations must not block: it's called on the fetch worker before the response is parsed
+type ResponseRecorder interface {
+	RecordResponse(ctx context.Context, endpoint, ticker string, body []byte, receivedAt time.Time)
+}
+
+// SetResponseRecorder sets the recorder raw responses are passed to (nil = none)
+func (c *Client) SetResponseRecorder(recorder ResponseRecorder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recorder = recorder
+}
+
+// ParseResponse extracts the fields of a raw endpoint response as a row (see model.TickerSnapshot.Row)
+// Used for live fetches and for replaying recorded responses, so a parsing fix applies to both
+func ParseResponse(endpoint, ticker string, body []byte) (map[string]interface{}, error) {
+	snapshot, err := ParseSnapshot(endpoint, ticker, body)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.Row(), nil
+}
+
+// ParseSnapshot decodes a raw endpoint response and normalizes it with the endpoint's field map
+// (custom endpoints store fields under their configured column names)
+func ParseSnapshot(endpoint, ticker string, body []byte) (*model.TickerSnapshot, error) {
+	var data map[string]interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, &RequestError{
+			Endpoint:      endpoint,
+			Message:       fmt.Sprintf("Invalid JSON response from %s for %s: %v", endpoint, ticker, err),
+			OriginalError: err,
+		}
+	}
+	return Normalize(endpoint, ticker, data), nil
+}
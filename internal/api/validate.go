@@ -0,0 +1,88 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// tierProbeEndpoints is the cheapest endpoint that proves access to each subscription tier
+var tierProbeEndpoints = map[string]string{
+	"classic":   "classic_zero",
+	"state":     "gamma_zero",
+	"orderflow": "orderflow",
+}
+
+// tierProbeTicker is queried by the probes (available on every tier)
+const tierProbeTicker = "SPX"
+
+// TierProbe is the outcome of probing one subscription tier
+type TierProbe struct {
+	Tier      string `json:"tier"`
+	Endpoint  string `json:"endpoint"`
+	Available bool   `json:"available"`
+	Error     string `json:"error,omitempty"`
+}
+
+// KeyValidation reports which subscription tiers an API key has
+type KeyValidation struct {
+	Valid  bool        `json:"valid"` // At least one tier answered
+	Tiers  []string    `json:"tiers"` // Tiers the key has access to
+	Probes []TierProbe `json:"probes"`
+}
+
+// ValidateAPIKey probes one endpoint per subscription tier (one request each, in parallel)
+// 401/403 means the tier isn't included; other failures (network, rate limit, 5xx) are returned
+// as an error when no tier could be confirmed, since they say nothing about the key
+func ValidateAPIKey(apiKey string, debugPrint func(string, string)) (*KeyValidation, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key cannot be empty")
+	}
+	client := NewClient(apiKey, debugPrint)
+	defer client.Close()
+
+	tiers := make([]string, 0, len(tierProbeEndpoints))
+	for tier := range tierProbeEndpoints {
+		tiers = append(tiers, tier)
+	}
+	sort.Strings(tiers)
+
+	probes := make([]TierProbe, len(tiers))
+	probeErrs := make([]error, len(tiers))
+	var wg sync.WaitGroup
+	for i, tier := range tiers {
+		wg.Add(1)
+		go func(i int, tier string) {
+			defer wg.Done()
+			endpoint := tierProbeEndpoints[tier]
+			probe := TierProbe{Tier: tier, Endpoint: endpoint}
+			if _, err := client.FetchEndpoint(endpoint, tierProbeTicker); err != nil {
+				probe.Error = err.Error()
+				probeErrs[i] = err
+			} else {
+				probe.Available = true
+			}
+			probes[i] = probe
+		}(i, tier)
+	}
+	wg.Wait()
+
+	result := &KeyValidation{Tiers: make([]string, 0), Probes: probes}
+	var inconclusive error
+	for i, probe := range probes {
+		if probe.Available {
+			result.Tiers = append(result.Tiers, probe.Tier)
+			continue
+		}
+		var subErr *SubscriptionError
+		if !errors.As(probeErrs[i], &subErr) && inconclusive == nil {
+			inconclusive = fmt.Errorf("could not verify the %s tier: %s", probe.Tier, probe.Error)
+		}
+	}
+	result.Valid = len(result.Tiers) > 0
+	if !result.Valid && inconclusive != nil {
+		return result, inconclusive
+	}
+	return result, nil
+}
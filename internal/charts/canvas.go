@@ -0,0 +1,191 @@
+package charts
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strings"
+)
+
+// Text anchors for canvas.text
+const (
+	anchorStart  = "start"
+	anchorMiddle = "middle"
+	anchorEnd    = "end"
+)
+
+// point is a canvas coordinate in pixels
+type point struct {
+	x, y float64
+}
+
+// canvas is the drawing surface shared by the PNG and SVG renderers
+type canvas interface {
+	fill(x, y, w, h float64, c color.RGBA)
+	polyline(points []point, c color.RGBA, width float64)
+	text(x, y float64, s string, c color.RGBA, size float64, anchor string) // y is the text baseline
+	encode() ([]byte, error)
+}
+
+// parseHexColor parses "#RRGGBB" (falls back to gray)
+func parseHexColor(hex string) color.RGBA {
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(strings.TrimPrefix(hex, "#"), "%02x%02x%02x", &r, &g, &b); err != nil {
+		return color.RGBA{128, 128, 128, 255}
+	}
+	return color.RGBA{r, g, b, 255}
+}
+
+// withAlpha returns c with the given opacity (0-1)
+func withAlpha(c color.RGBA, alpha float64) color.RGBA {
+	c.A = uint8(math.Round(alpha * 255))
+	return c
+}
+
+// svgCanvas builds an SVG document
+type svgCanvas struct {
+	width, height int
+	body          strings.Builder
+}
+
+func newSVGCanvas(width, height int) *svgCanvas {
+	return &svgCanvas{width: width, height: height}
+}
+
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+}
+
+func svgOpacity(c color.RGBA) string {
+	if c.A == 255 {
+		return ""
+	}
+	return fmt.Sprintf(` opacity="%.2f"`, float64(c.A)/255)
+}
+
+func (s *svgCanvas) fill(x, y, w, h float64, c color.RGBA) {
+	fmt.Fprintf(&s.body, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"%s/>`+"\n", x, y, w, h, svgColor(c), svgOpacity(c))
+}
+
+func (s *svgCanvas) polyline(points []point, c color.RGBA, width float64) {
+	if len(points) < 2 {
+		return
+	}
+	coords := make([]string, len(points))
+	for i, p := range points {
+		coords[i] = fmt.Sprintf("%.1f,%.1f", p.x, p.y)
+	}
+	fmt.Fprintf(&s.body, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%.1f" stroke-linejoin="round"%s/>`+"\n",
+		strings.Join(coords, " "), svgColor(c), width, svgOpacity(c))
+}
+
+func (s *svgCanvas) text(x, y float64, str string, c color.RGBA, size float64, anchor string) {
+	fmt.Fprintf(&s.body, `<text x="%.1f" y="%.1f" fill="%s" font-size="%.1f" text-anchor="%s" font-family="Menlo, Consolas, monospace"%s>%s</text>`+"\n",
+		x, y, svgColor(c), size, anchor, svgOpacity(c), html.EscapeString(str))
+}
+
+func (s *svgCanvas) encode() ([]byte, error) {
+	var out bytes.Buffer
+	fmt.Fprintf(&out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", s.width, s.height, s.width, s.height)
+	out.WriteString(s.body.String())
+	out.WriteString("</svg>\n")
+	return out.Bytes(), nil
+}
+
+// pngCanvas rasterizes onto an RGBA image
+type pngCanvas struct {
+	img *image.RGBA
+}
+
+func newPNGCanvas(width, height int) *pngCanvas {
+	return &pngCanvas{img: image.NewRGBA(image.Rect(0, 0, width, height))}
+}
+
+// blend draws one pixel with alpha blending
+func (p *pngCanvas) blend(x, y int, c color.RGBA) {
+	if !(image.Point{x, y}.In(p.img.Rect)) {
+		return
+	}
+	if c.A == 255 {
+		p.img.SetRGBA(x, y, c)
+		return
+	}
+	dst := p.img.RGBAAt(x, y)
+	a := float64(c.A) / 255
+	mix := func(src, dst uint8) uint8 { return uint8(math.Round(float64(src)*a + float64(dst)*(1-a))) }
+	p.img.SetRGBA(x, y, color.RGBA{mix(c.R, dst.R), mix(c.G, dst.G), mix(c.B, dst.B), 255})
+}
+
+func (p *pngCanvas) fill(x, y, w, h float64, c color.RGBA) {
+	for py := int(math.Round(y)); py < int(math.Round(y+h)); py++ {
+		for px := int(math.Round(x)); px < int(math.Round(x+w)); px++ {
+			p.blend(px, py, c)
+		}
+	}
+}
+
+// polyline draws each segment with a square brush (width pixels wide)
+func (p *pngCanvas) polyline(points []point, c color.RGBA, width float64) {
+	brush := int(math.Max(1, math.Round(width)))
+	offset := brush / 2
+	drawn := make(map[image.Point]bool) // Avoid double-blending overlapping brush pixels
+	for i := 1; i < len(points); i++ {
+		x0, y0 := points[i-1].x, points[i-1].y
+		x1, y1 := points[i].x, points[i].y
+		steps := int(math.Max(math.Abs(x1-x0), math.Abs(y1-y0)))
+		if steps == 0 {
+			steps = 1
+		}
+		for s := 0; s <= steps; s++ {
+			t := float64(s) / float64(steps)
+			cx := int(math.Round(x0 + (x1-x0)*t))
+			cy := int(math.Round(y0 + (y1-y0)*t))
+			for dy := 0; dy < brush; dy++ {
+				for dx := 0; dx < brush; dx++ {
+					pt := image.Point{cx + dx - offset, cy + dy - offset}
+					if !drawn[pt] {
+						drawn[pt] = true
+						p.blend(pt.X, pt.Y, c)
+					}
+				}
+			}
+		}
+	}
+}
+
+// text draws with the bitmap font, scaled to the nearest whole multiple of the glyph height
+func (p *pngCanvas) text(x, y float64, str string, c color.RGBA, size float64, anchor string) {
+	scale := int(math.Max(1, math.Round(size/float64(glyphHeight+2))))
+	width := float64(len([]rune(str))*glyphAdvance*scale - scale)
+	switch anchor {
+	case anchorMiddle:
+		x -= width / 2
+	case anchorEnd:
+		x -= width
+	}
+	left := int(math.Round(x))
+	top := int(math.Round(y)) - glyphHeight*scale
+	for i, r := range []rune(str) {
+		g := glyph(r)
+		for row := 0; row < glyphHeight; row++ {
+			for col := 0; col < glyphWidth; col++ {
+				if g[row]&(0x10>>col) == 0 {
+					continue
+				}
+				p.fill(float64(left+(i*glyphAdvance+col)*scale), float64(top+row*scale), float64(scale), float64(scale), c)
+			}
+		}
+	}
+}
+
+func (p *pngCanvas) encode() ([]byte, error) {
+	var out bytes.Buffer
+	if err := png.Encode(&out, p.img); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	return out.Bytes(), nil
+}
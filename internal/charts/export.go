@@ -0,0 +1,232 @@
+package charts
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// Image formats supported by RenderChartImage
+const (
+	ImageFormatPNG = "png"
+	ImageFormatSVG = "svg"
+)
+
+// ImageOptions controls a chart image export
+type ImageOptions struct {
+	Format    string   `json:"format"`    // "png" (default) or "svg"
+	Width     int      `json:"width"`     // Pixels; 0 = DefaultChartImageWidth
+	Height    int      `json:"height"`    // Pixels; 0 = DefaultChartImageHeight
+	Series    []string `json:"series"`    // Series to draw; empty = spot plus every level with data
+	Watermark string   `json:"watermark"` // Text stamped in the bottom-right corner (e.g. a Discord handle); "" = none
+	Timestamp bool     `json:"timestamp"` // Stamp the render time in the bottom-left corner
+	Path      string   `json:"path"`      // Output file; "" = the exports folder in the config dir
+}
+
+// Normalize fills defaults and checks the options
+func (o *ImageOptions) Normalize() error {
+	o.Format = strings.ToLower(strings.TrimSpace(o.Format))
+	if o.Format == "" {
+		o.Format = ImageFormatPNG
+	}
+	if o.Format != ImageFormatPNG && o.Format != ImageFormatSVG {
+		return fmt.Errorf("unsupported image format %q (expected png or svg)", o.Format)
+	}
+	if o.Width == 0 {
+		o.Width = config.DefaultChartImageWidth
+	}
+	if o.Height == 0 {
+		o.Height = config.DefaultChartImageHeight
+	}
+	if o.Width < config.MinChartImageSize || o.Height < config.MinChartImageSize ||
+		o.Width > config.MaxChartImageSize || o.Height > config.MaxChartImageSize {
+		return fmt.Errorf("image size %dx%d out of range (%d-%d pixels per side)", o.Width, o.Height, config.MinChartImageSize, config.MaxChartImageSize)
+	}
+	return nil
+}
+
+// ChartImageData is the chart content to render
+type ChartImageData struct {
+	Title      string               // e.g. "SPX 2026-10-16"
+	Timestamps []float64            // Unix seconds, ascending
+	Series     map[string][]float64 // Series name -> values aligned with Timestamps (NaN = gap)
+	Location   *time.Location       // Time axis labels are shown in this zone
+}
+
+// chartLayout maps data coordinates to pixels
+type chartLayout struct {
+	left, top, right, bottom float64
+	minX, maxX, minY, maxY   float64
+}
+
+func (l chartLayout) x(ts float64) float64 {
+	if l.maxX == l.minX {
+		return l.left
+	}
+	return l.left + (ts-l.minX)/(l.maxX-l.minX)*(l.right-l.left)
+}
+
+func (l chartLayout) y(value float64) float64 {
+	return l.bottom - (value-l.minY)/(l.maxY-l.minY)*(l.bottom-l.top)
+}
+
+// seriesOrder lists the series to draw: spot first, then levels in the chart's usual order
+func seriesOrder(data ChartImageData, requested []string) []string {
+	if len(requested) > 0 {
+		names := make([]string, 0, len(requested))
+		for _, name := range requested {
+			if _, ok := data.Series[name]; ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	names := make([]string, 0, len(data.Series))
+	for name := range data.Series {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == "spot") != (names[j] == "spot") {
+			return names[i] == "spot"
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// niceStep returns a round grid step giving about count divisions over span
+func niceStep(span float64, count int) float64 {
+	raw := span / float64(count)
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, factor := range []float64{1, 2, 2.5, 5, 10} {
+		if raw <= factor*magnitude {
+			return factor * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// formatPrice formats a price axis label with as many decimals as the grid step needs
+func formatPrice(value, step float64) string {
+	decimals := 0
+	if step < 1 {
+		decimals = int(math.Ceil(-math.Log10(step)))
+	}
+	return fmt.Sprintf("%.*f", decimals, value)
+}
+
+// RenderChartImage draws the chart to PNG or SVG bytes using the theme's colors
+// opts must have been normalized
+func RenderChartImage(data ChartImageData, opts ImageOptions, theme config.Theme) ([]byte, error) {
+	var c canvas
+	if opts.Format == ImageFormatSVG {
+		c = newSVGCanvas(opts.Width, opts.Height)
+	} else {
+		c = newPNGCanvas(opts.Width, opts.Height)
+	}
+	if data.Location == nil {
+		data.Location = time.UTC
+	}
+
+	width, height := float64(opts.Width), float64(opts.Height)
+	unit := math.Max(1, math.Min(width/1600, height/900)) // Scales fonts and lines with the resolution
+	fontSize := 14 * unit
+	background := parseHexColor(theme.Background)
+	grid := parseHexColor(theme.Grid)
+	textColor := parseHexColor(theme.Text)
+
+	c.fill(0, 0, width, height, background)
+	c.text(16*unit, 28*unit, data.Title, textColor, 20*unit, anchorStart)
+
+	names := seriesOrder(data, opts.Series)
+
+	// Data range over every drawn series
+	layout := chartLayout{
+		left: 16 * unit, top: 64 * unit, right: width - 90*unit, bottom: height - 64*unit,
+		minY: math.Inf(1), maxY: math.Inf(-1),
+	}
+	if len(data.Timestamps) > 0 {
+		layout.minX, layout.maxX = data.Timestamps[0], data.Timestamps[len(data.Timestamps)-1]
+	}
+	for _, name := range names {
+		for _, value := range data.Series[name] {
+			if !math.IsNaN(value) {
+				layout.minY = math.Min(layout.minY, value)
+				layout.maxY = math.Max(layout.maxY, value)
+			}
+		}
+	}
+	if math.IsInf(layout.minY, 0) {
+		c.text(width/2, height/2, "No data", textColor, 20*unit, anchorMiddle)
+		return c.encode()
+	}
+	padding := (layout.maxY - layout.minY) * 0.05
+	if padding == 0 {
+		padding = math.Max(math.Abs(layout.maxY)*0.001, 1)
+	}
+	layout.minY -= padding
+	layout.maxY += padding
+
+	// Price grid (labels on the right, like the chart windows)
+	step := niceStep(layout.maxY-layout.minY, 8)
+	for value := math.Ceil(layout.minY/step) * step; value <= layout.maxY; value += step {
+		y := layout.y(value)
+		c.fill(layout.left, y, layout.right-layout.left, unit, grid)
+		c.text(layout.right+8*unit, y+fontSize/3, formatPrice(value, step), textColor, fontSize, anchorStart)
+	}
+
+	// Time grid: hourly lines (every 30 minutes on short spans)
+	interval := 3600.0
+	if layout.maxX-layout.minX < 3*3600 {
+		interval = 1800
+	}
+	for ts := math.Ceil(layout.minX/interval) * interval; ts <= layout.maxX; ts += interval {
+		x := layout.x(ts)
+		c.fill(x, layout.top, unit, layout.bottom-layout.top, grid)
+		if x-layout.left < 3*fontSize {
+			continue // Label would be cut off at the image edge
+		}
+		label := time.Unix(int64(ts), 0).In(data.Location).Format("15:04")
+		c.text(x, layout.bottom+20*unit, label, textColor, fontSize, anchorMiddle)
+	}
+
+	// Series (gaps split the line)
+	legendX := 16 * unit
+	for _, name := range names {
+		color := parseHexColor(theme.SeriesColors[name])
+		lineWidth := 1.5 * unit
+		if name == "spot" {
+			lineWidth = 2.5 * unit
+		}
+		values := data.Series[name]
+		segment := make([]point, 0, len(values))
+		for i, value := range values {
+			if i >= len(data.Timestamps) || math.IsNaN(value) {
+				c.polyline(segment, color, lineWidth)
+				segment = segment[:0]
+				continue
+			}
+			segment = append(segment, point{layout.x(data.Timestamps[i]), layout.y(value)})
+		}
+		c.polyline(segment, color, lineWidth)
+
+		// Legend entry under the title
+		c.fill(legendX, 42*unit, 10*unit, 10*unit, color)
+		c.text(legendX+14*unit, 52*unit, name, textColor, fontSize, anchorStart)
+		legendX += 14*unit + float64(len(name)+3)*fontSize*0.62
+	}
+
+	// Footer stamps
+	if opts.Timestamp {
+		stamp := "Generated " + time.Now().In(data.Location).Format("2006-01-02 15:04:05 MST")
+		c.text(16*unit, height-12*unit, stamp, withAlpha(textColor, 0.7), fontSize, anchorStart)
+	}
+	if opts.Watermark != "" {
+		c.text(width-16*unit, height-12*unit, opts.Watermark, withAlpha(textColor, 0.5), 18*unit, anchorEnd)
+	}
+	return c.encode()
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"market-terminal/internal/alerts"
+)
+
+// Alert sound outputs
+const (
+	AlertSoundOutputFrontend = "frontend" // The main window plays the sound (default)
+	AlertSoundOutputBackend  = "backend"  // The app plays it through the OS (works with every window closed to the tray)
+)
+
+// AlertSoundNone silences a rule
+const AlertSoundNone = "none"
+
+// BundledAlertSounds are the sounds built into the app (synthesized, no files needed)
+var BundledAlertSounds = []string{"chime", "beep", "ding", "alarm"}
+
+// AlertSettings holds the live alert rules and how triggered alerts are announced
+type AlertSettings struct {
+	Rules        []alerts.Rule `yaml:"rules,omitempty" json:"Rules"`
+	Muted        bool          `yaml:"muted" json:"Muted"`                          // Global mute (alerts still fire and are listed)
+	DefaultSound string        `yaml:"default_sound,omitempty" json:"DefaultSound"` // Sound of rules without one ("" = DefaultAlertSound)
+	SoundOutput  string        `yaml:"sound_output,omitempty" json:"SoundOutput"`   // "frontend" (default) or "backend"
+
+	PriceAlerts []alerts.PriceAlert `yaml:"price_alerts,omitempty" json:"PriceAlerts"` // One-shot level lines placed on the chart
+}
+
+// AllRules returns the rules the engine evaluates: the configured rules plus one per price alert
+func (s AlertSettings) AllRules() []alerts.Rule {
+	rules := make([]alerts.Rule, 0, len(s.Rules)+len(s.PriceAlerts))
+	rules = append(rules, s.Rules...)
+	for _, priceAlert := range s.PriceAlerts {
+		rules = append(rules, priceAlert.Rule())
+	}
+	return rules
+}
+
+// FindPriceAlert returns the index of a price alert (-1 if there is none with that ID)
+func (s AlertSettings) FindPriceAlert(id string) int {
+	for i, priceAlert := range s.PriceAlerts {
+		if priceAlert.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsBundledAlertSound reports whether a sound name is built into the app
+func IsBundledAlertSound(name string) bool {
+	for _, bundled := range BundledAlertSounds {
+		if name == bundled {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateAlertSound checks a sound is "none", a bundled sound or an existing .wav file
+func ValidateAlertSound(sound string) error {
+	if sound == "" || sound == AlertSoundNone || IsBundledAlertSound(sound) {
+		return nil
+	}
+	if !strings.EqualFold(filepath.Ext(sound), ".wav") {
+		return fmt.Errorf("alert sound %q is neither a bundled sound (%s) nor a .wav file", sound, strings.Join(BundledAlertSounds, ", "))
+	}
+	if _, err := os.Stat(sound); err != nil {
+		return fmt.Errorf("alert sound file %s: %w", sound, err)
+	}
+	return nil
+}
+
+// Validate checks the rules and price alerts (each needs a unique ID and a ticker), sounds and output
+func (s AlertSettings) Validate() error {
+	ids := make(map[string]bool, len(s.Rules))
+	for _, rule := range s.Rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+		if rule.ID == "" || ids[rule.ID] {
+			return fmt.Errorf("alert rule %q needs a unique id", rule.Name)
+		}
+		ids[rule.ID] = true
+		if rule.Ticker == "" {
+			return fmt.Errorf("alert rule %q has no ticker", rule.Name)
+		}
+		if err := ValidateAlertSound(rule.Sound); err != nil {
+			return err
+		}
+	}
+	priceIDs := make(map[string]bool, len(s.PriceAlerts))
+	for _, priceAlert := range s.PriceAlerts {
+		if priceAlert.ID == "" || priceIDs[priceAlert.ID] {
+			return fmt.Errorf("price alert on %s at %.2f needs a unique id", priceAlert.Ticker, priceAlert.Level)
+		}
+		priceIDs[priceAlert.ID] = true
+		if err := priceAlert.Validate(); err != nil {
+			return err
+		}
+		if err := ValidateAlertSound(priceAlert.Sound); err != nil {
+			return err
+		}
+	}
+	if err := ValidateAlertSound(s.DefaultSound); err != nil {
+		return err
+	}
+	switch s.SoundOutput {
+	case "", AlertSoundOutputFrontend, AlertSoundOutputBackend:
+	default:
+		return fmt.Errorf("alert sound_output must be %q or %q (got %q)", AlertSoundOutputFrontend, AlertSoundOutputBackend, s.SoundOutput)
+	}
+	return nil
+}
+
+// GetSoundOutput returns where alert sounds are played
+func (s AlertSettings) GetSoundOutput() string {
+	if s.SoundOutput == "" {
+		return AlertSoundOutputFrontend
+	}
+	return s.SoundOutput
+}
+
+// SoundFor returns the sound a rule plays ("" when muted or silenced)
+func (s AlertSettings) SoundFor(rule alerts.Rule) string {
+	sound := rule.Sound
+	if sound == "" {
+		sound = s.DefaultSound
+	}
+	if sound == "" {
+		sound = DefaultAlertSound
+	}
+	if s.Muted || sound == AlertSoundNone {
+		return ""
+	}
+	return sound
+}
+
+// UsesSound reports whether a sound is the default or some rule's or price alert's (files are only served when configured)
+func (s AlertSettings) UsesSound(sound string) bool {
+	if sound == "" || sound == AlertSoundNone {
+		return false
+	}
+	if IsBundledAlertSound(sound) || sound == s.DefaultSound {
+		return true
+	}
+	for _, rule := range s.Rules {
+		if rule.Sound == sound {
+			return true
+		}
+	}
+	for _, priceAlert := range s.PriceAlerts {
+		if priceAlert.Sound == sound {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package config
+
+// APIKeyConfig represents an additional GEXBot API key
+// Keys with Tickers set are only used for those tickers (e.g. a group subscription for SPX/ES_SPX)
+// Keys without Tickers join the primary key in the shared rotation
+type APIKeyConfig struct {
+	Name    string   `yaml:"name" json:"Name"`
+	Key     string   `yaml:"key" json:"Key"`
+	Tickers []string `yaml:"tickers,omitempty" json:"Tickers"`
+	Enabled bool     `yaml:"enabled" json:"Enabled"`
+}
+
+// PrimaryAPIKeyName is the name reported for the main api_key in key statistics
+const PrimaryAPIKeyName = "primary"
+
+// MaskAPIKey returns a key with all but the last 4 characters hidden (safe for UI/logs)
+func MaskAPIKey(key string) string {
+	if len(key) <= 4 {
+		return "****"
+	}
+	return "****" + key[len(key)-4:]
+}
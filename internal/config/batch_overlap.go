@@ -0,0 +1,26 @@
+package config
+
+import "fmt"
+
+// What the coordinator does when a ticker is due while its previous batch is still fetching (batch_overlap_policy)
+const (
+	BatchOverlapSkip  = "skip"  // Drop the ticker from the new batch; its next interval fetches it (default)
+	BatchOverlapQueue = "queue" // Fetch it once more right after the running batch (repeated requests coalesce)
+)
+
+// GetBatchOverlapPolicy returns the overlap policy (skip unless set to queue)
+func (s *Settings) GetBatchOverlapPolicy() string {
+	if s.BatchOverlapPolicy == "" {
+		return BatchOverlapSkip
+	}
+	return s.BatchOverlapPolicy
+}
+
+// ValidateBatchOverlapPolicy checks batch_overlap_policy is skip or queue
+func (s *Settings) ValidateBatchOverlapPolicy() error {
+	switch s.BatchOverlapPolicy {
+	case "", BatchOverlapSkip, BatchOverlapQueue:
+		return nil
+	}
+	return fmt.Errorf("batch_overlap_policy %q must be %s or %s", s.BatchOverlapPolicy, BatchOverlapSkip, BatchOverlapQueue)
+}
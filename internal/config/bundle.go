@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config bundle format (portable export of settings for migrating machines or sharing setups)
+const (
+	ConfigBundleFormat  = "market-terminal-config"
+	ConfigBundleVersion = 1 // Bump when the bundle layout changes incompatibly
+)
+
+// ConfigBundle is the file written by ExportConfig
+// Settings carries everything shareable: ticker configs and groups, themes/chart colors, alerts, intervals...
+type ConfigBundle struct {
+	Format        string    `yaml:"format"`
+	FormatVersion int       `yaml:"format_version"`
+	AppVersion    string    `yaml:"app_version"`
+	ExportedAt    string    `yaml:"exported_at"`
+	Settings      *Settings `yaml:"settings"`
+}
+
+// WithoutSecrets returns a copy of the settings with API keys and other credentials cleared
+// Used wherever settings leave the machine (config bundles, crash dumps)
+func (s *Settings) WithoutSecrets() (*Settings, error) {
+	shared, err := s.Clone()
+	if err != nil {
+		return nil, err
+	}
+	shared.APITKey = ""
+	shared.AdditionalAPIKeys = nil
+	shared.Sync.AccessKeyID = ""
+	shared.Sync.SecretAccessKey = ""
+	shared.TimeSeriesSink.Token = ""
+	shared.TimeSeriesSink.DSN = ""
+	shared.Tracing.Headers = nil
+	shared.SpotCheck.URLTemplate = "" // May carry a quote API token
+	shared.SpotCheck.Headers = nil
+	return shared, nil
+}
+
+// NewConfigBundle builds an export bundle with secrets and machine-specific values removed
+func NewConfigBundle(settings *Settings, appVersion string) (*ConfigBundle, error) {
+	// Never export credentials
+	shared, err := settings.WithoutSecrets()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConfigBundle{
+		Format:        ConfigBundleFormat,
+		FormatVersion: ConfigBundleVersion,
+		AppVersion:    appVersion,
+		ExportedAt:    time.Now().Format(time.RFC3339),
+		Settings:      shared,
+	}, nil
+}
+
+// ParseConfigBundle parses and version-checks a bundle file
+func ParseConfigBundle(data []byte) (*ConfigBundle, error) {
+	var bundle ConfigBundle
+	if err := yaml.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse config bundle: %w", err)
+	}
+	if bundle.Format != ConfigBundleFormat {
+		return nil, fmt.Errorf("not a market terminal config bundle (format %q)", bundle.Format)
+	}
+	if bundle.FormatVersion < 1 || bundle.FormatVersion > ConfigBundleVersion {
+		return nil, fmt.Errorf("unsupported config bundle version %d (this version supports up to %d) - exported by %s",
+			bundle.FormatVersion, ConfigBundleVersion, bundle.AppVersion)
+	}
+	if bundle.Settings == nil {
+		return nil, fmt.Errorf("config bundle has no settings")
+	}
+	return &bundle, nil
+}
+
+// ApplyTo returns the bundle's settings merged onto the local settings
+// Credentials, the data directory and window size/placement stay local
+func (b *ConfigBundle) ApplyTo(local *Settings) (*Settings, error) {
+	imported, err := b.Settings.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	imported.APITKey = local.APITKey
+	imported.AdditionalAPIKeys = local.AdditionalAPIKeys
+	imported.Sync.AccessKeyID = local.Sync.AccessKeyID
+	imported.Sync.SecretAccessKey = local.Sync.SecretAccessKey
+	imported.TimeSeriesSink.Token = local.TimeSeriesSink.Token
+	imported.TimeSeriesSink.DSN = local.TimeSeriesSink.DSN
+	imported.Tracing.Headers = local.Tracing.Headers
+	imported.SpotCheck.URLTemplate = local.SpotCheck.URLTemplate
+	imported.SpotCheck.Headers = local.SpotCheck.Headers
+	imported.DataDirectory = local.DataDirectory
+	imported.WindowWidth = local.WindowWidth
+	imported.WindowHeight = local.WindowHeight
+	imported.WindowPlacements = local.WindowPlacements
+
+	return imported, nil
+}
@@ -0,0 +1,34 @@
+package config
+
+import "testing"
+
+func TestConfigBundleApplyToKeepsLocalSecrets(t *testing.T) {
+	exporter := GetDefaultSettings()
+	exporter.Tracing.Headers = map[string]string{"x-api-key": "exporter-secret"}
+	bundle, err := NewConfigBundle(exporter, "test")
+	if err != nil {
+		t.Fatalf("NewConfigBundle: %v", err)
+	}
+	if bundle.Settings.Tracing.Headers != nil {
+		t.Fatalf("exported bundle carries tracing headers: %v", bundle.Settings.Tracing.Headers)
+	}
+
+	local := GetDefaultSettings()
+	local.APITKey = "local-key"
+	local.Tracing.Headers = map[string]string{"x-api-key": "local-secret"}
+	local.SpotCheck.Headers = map[string]string{"Authorization": "Bearer local"}
+
+	imported, err := bundle.ApplyTo(local)
+	if err != nil {
+		t.Fatalf("ApplyTo: %v", err)
+	}
+	if got := imported.Tracing.Headers["x-api-key"]; got != "local-secret" {
+		t.Errorf("Tracing.Headers[x-api-key] = %q after import, want the local %q", got, "local-secret")
+	}
+	if got := imported.SpotCheck.Headers["Authorization"]; got != "Bearer local" {
+		t.Errorf("SpotCheck.Headers[Authorization] = %q after import, want the local value", got)
+	}
+	if imported.APITKey != "local-key" {
+		t.Errorf("APITKey = %q after import, want the local key", imported.APITKey)
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import "fmt"
+
+// ConnectionPoolSettings sizes the SQLite connection pools (the loader's read pool and the writer's pool each get these limits)
+type ConnectionPoolSettings struct {
+	MaxSize             int `yaml:"max_size" json:"MaxSize"`                           // Open connections per pool; beyond it the least recently used idle one is closed
+	ReadIdleTimeoutSec  int `yaml:"read_idle_timeout_sec" json:"ReadIdleTimeoutSec"`   // Seconds before an unused read connection is closed
+	WriteIdleTimeoutSec int `yaml:"write_idle_timeout_sec" json:"WriteIdleTimeoutSec"` // Seconds before an unused write connection is closed
+	CleanupIntervalSec  int `yaml:"cleanup_interval_sec" json:"CleanupIntervalSec"`    // How often idle connections are looked for
+}
+
+// DefaultConnectionPoolSettings returns the built-in pool settings
+func DefaultConnectionPoolSettings() ConnectionPoolSettings {
+	return ConnectionPoolSettings{
+		MaxSize:             DBConnectionPoolMaxSize,
+		ReadIdleTimeoutSec:  int(DBConnectionIdleTimeoutSec),
+		WriteIdleTimeoutSec: int(SQLiteConnectionIdleTimeoutSeconds),
+		CleanupIntervalSec:  int(SQLiteConnectionCleanupIntervalSeconds),
+	}
+}
+
+// Validate checks that the limits are positive
+func (c ConnectionPoolSettings) Validate() error {
+	if c.MaxSize < 1 {
+		return fmt.Errorf("connection pool size must be at least 1 (got %d)", c.MaxSize)
+	}
+	if c.ReadIdleTimeoutSec < 1 || c.WriteIdleTimeoutSec < 1 {
+		return fmt.Errorf("connection idle timeouts must be at least 1 second (got read %d, write %d)", c.ReadIdleTimeoutSec, c.WriteIdleTimeoutSec)
+	}
+	if c.CleanupIntervalSec < 1 {
+		return fmt.Errorf("connection cleanup interval must be at least 1 second (got %d)", c.CleanupIntervalSec)
+	}
+	return nil
+}
+
+// GetConnectionPoolSettings returns the configured pool settings, or the defaults if unset
+func (s *Settings) GetConnectionPoolSettings() ConnectionPoolSettings {
+	if s.ConnectionPool == nil {
+		return DefaultConnectionPoolSettings()
+	}
+	return *s.ConnectionPool
+}
@@ -49,11 +49,17 @@ const (
 	HealthCheckStartDelayMs    = 1000  // Initial delay before starting health check
 )
 
+// Polling Interval Bounds (for the configurable interval matrix)
+const (
+	MinPollingIntervalSec = 1.0   // Never poll a ticker faster than once per second
+	MaxPollingIntervalSec = 300.0 // Never poll an enabled ticker slower than every 5 minutes
+)
+
 // Circuit Breaker Configuration
 const (
-	BatchTimeoutCircuitBreakerThreshold    = 3   // Consecutive timeouts before pausing batch creation
-	BatchTimeoutCircuitBreakerBackoffSec   = 30  // Seconds to pause before retry after circuit breaker trips
-	BatchTimeoutCircuitBreakerSuccessReset = 2   // Successful batches to reset timeout counter
+	BatchTimeoutCircuitBreakerThreshold    = 3   // Consecutive failures before an endpoint family's circuit opens
+	BatchTimeoutCircuitBreakerBackoffSec   = 30  // Seconds an open circuit skips its family before a probe request
+	BatchTimeoutCircuitBreakerSuccessReset = 2   // Successful probes needed to close a half-open circuit
 )
 
 // File Write Batching Configuration
@@ -93,14 +99,13 @@ const (
 	ShutdownWriteExecutorTimeout  = 30.0 // Seconds to wait for write executor shutdown
 	ShutdownDatabaseCloseTimeout = 10.0 // Seconds to wait for database close
 	ShutdownFlushThreadTimeout    = 30.0 // Seconds to wait for flush thread
-	ShutdownFinalFlushTimeout    = 60.0 // Seconds for final flush
+	ShutdownFinalFlushTimeout    = 60.0 // Seconds for the whole soft shutdown (final flush included) before it is aborted
 	ShutdownLockAcquisitionTimeout = 5.0 // Seconds for lock acquisition during shutdown
 )
 
 // SQLite Optimization Intervals
 const (
 	SQLiteWalCheckpointInterval = 20 // Checkpoint WAL file every N flushes
-	SQLiteVacuumInterval        = 50 // VACUUM database every N flushes
 )
 
 // Chart Rendering Performance Configuration
@@ -152,6 +157,11 @@ const (
 	QueueSizeEmergencyThresholdRatio = 0.9 // Emergency mode at 90%
 	AdaptiveBatchingEnabled         = true // Enable adaptive batching based on queue size
 	AdaptiveBatchReductionRatio     = 0.5 // Reduce batch size by 50% when approaching limit
+	AdaptiveBatchGrowthRatio        = 1.25 // Grow batch size by 25% per flush while idle
+	AdaptiveBatchMinScale           = 0.1 // Smallest multiplier on the base flush thresholds
+	AdaptiveBatchMaxScale           = 4.0 // Largest multiplier on the base flush thresholds
+	AdaptiveBatchIdleDepthRatio     = 0.1 // Idle while fewer rows than this share of MaxWriteQueueSize are pending
+	AdaptiveBatchLatencySmoothing   = 0.2 // Weight of the latest flush in the flush latency moving average
 	MinBatchCount                   = 1   // Minimum batch count (never flush more frequently than this)
 )
 
@@ -202,6 +212,29 @@ const (
 	SQLiteCacheSizeMB                     = 5    // 5MB cache per connection
 )
 
+// Profile Delta Compression Configuration
+const (
+	ProfileDeltaKeyframeInterval = 60 // Rows per window: one full profile snapshot, then diffs against it
+)
+
+// Database Encryption Configuration
+const (
+	DatabaseEncryptionKeyAccount = "database-encryption-key" // Keychain account the AES-256 key is stored under
+	DatabaseEncryptionKeySize    = 32                        // AES-256 key size in bytes
+)
+
+// Sync Configuration
+const (
+	SyncTransferTimeoutSec = 600 // Per-request timeout for S3 transfers (daily databases can be large)
+	SyncStartupDelaySec    = 10  // Wait after startup before pulling missing days
+)
+
+// Ticker Drain Configuration
+const (
+	TickerDrainTimeoutSec = 30  // Max wait for a disabled ticker's in-flight fetch before flushing anyway
+	TickerDrainPollMs     = 100 // How often to check whether a draining ticker's fetch has finished
+)
+
 // Config Directory and Environment Variables
 const (
 	// ConfigDirName is the name of the config directory in user's home/config directory
@@ -213,3 +246,333 @@ const (
 	// OldSettingsFileName is the old JSON settings file name (for migration)
 	OldSettingsFileName = "market_terminal_settings.json"
 )
+
+// End-of-Day Processing Configuration
+const (
+	EndOfDayProcessingDelaySec = 120  // Wait 2 minutes after the close so final flushes land before processing
+	EndOfDayCheckIntervalSec   = 30   // How often the market close watcher checks the clock
+	DailyStatsMaxGapSec        = 60.0 // Gaps longer than this are not counted toward time above/below zero gamma
+	DailyStatsTopGammaFlips    = 5    // Number of largest zero gamma shifts to report
+
+	EndOfDayReportGapThresholdSec   = 30.0 // Gaps between rows longer than this are counted in the report
+	EndOfDayReportWebhookTimeoutSec = 10   // Timeout for posting the report to the webhook
+)
+
+// Memory Budget Configuration
+const (
+	DefaultMemoryBudgetMB  = 1024  // Process memory budget when memory_budget_mb is unset
+	MemoryCheckIntervalSec = 5     // How often the memory monitor samples usage
+	MemoryHighPercent      = 80.0  // Shrink caches, halve chart loads and GC at 80% of the budget
+	MemoryCriticalPercent  = 95.0  // Quarter chart loads and return memory to the OS at 95%
+	ChartMaxRows           = 30000 // Maximum rows per chart load (full trading day at 1s = ~23,400)
+	MemoryRelieveCacheSize = 10    // Query cache entries kept when shrinking caches under pressure
+)
+
+// Disk Space Monitoring
+const (
+	DefaultDiskWarnMB           = 2048 // Warn the UI below 2 GB free on the data directory's volume
+	DefaultDiskCriticalMB       = 512  // Write essential columns only (no profiles) below 512 MB free
+	DefaultDiskCheckIntervalSec = 60   // How often free space is checked
+	MinDiskCheckIntervalSec     = 5
+)
+
+// Frontend Dev Server Configuration
+const (
+	DevServerDefaultURL = "http://localhost:5173" // Vite's default dev server address (used by --dev-server without a URL)
+)
+
+// Date Preload Configuration
+const (
+	PreloadWorkers         = 4     // Concurrent ticker/date loads when warming the date picker
+	PreloadCacheMaxEntries = 500   // Latest-row results kept for past dates
+	PreloadCacheTTLSec     = 900.0 // Past-date results expire after 15 minutes (bounds memory, files rarely change)
+)
+
+// Rate Limit Telemetry Configuration
+const (
+	RateLimitStatusEventSec = 5 // How often "ratelimit:status" is emitted to the frontend
+)
+
+// API Request Policy Limits
+const (
+	MaxRequestTimeoutSec = 300 // Upper bound for a configured per-attempt timeout
+	MaxRequestAttempts   = 10  // Upper bound for configured attempts per request
+	MaxRetryAfterWaitSec = 10  // Longest Retry-After honored on a 5xx before retrying (longer waits give up instead)
+)
+
+// Chart Bar Aggregation Configuration
+const (
+	ChartBarIntervalSec = 60.0 // Bucket size of the ticker_data_1m table served for full-day chart views
+)
+
+// Time-Series Sink Configuration
+const (
+	TimeSeriesQueueBatches    = 256 // Flushed batches queued for the sink before new ones are dropped
+	TimeSeriesWriteTimeoutSec = 10  // Per-request timeout for sink writes
+)
+
+// Chart Window Tracking Configuration
+const (
+	ChartWindowReconcileIntervalSec = 30 // How often displayed tickers without a chart window are unregistered
+)
+
+// Idle-Aware Scheduling Configuration
+const (
+	DefaultIdleAfterMinutes  = 15 // Minutes without a focused app window before all tickers drop to collection intervals
+	ActivityCheckIntervalSec = 30 // How often window focus is polled for idle detection
+)
+
+// Fetch History Configuration
+const (
+	FetchHistoryFileName        = "fetch_history.json" // Per-ticker last-fetch timestamps kept in the config dir for warm starts
+	FetchHistorySaveIntervalSec = 60                   // Minimum time between fetch history writes while collecting
+)
+
+// Startup Stagger Configuration
+const (
+	DefaultStartupStaggerSec = 3.0  // Initial fetches are spread over this many seconds, highest priority first
+	MaxStartupStaggerSec     = 30.0 // Upper bound for startup_stagger_sec
+)
+
+// Batch Coalescing Configuration
+const (
+	DefaultBatchCoalesceWindowMs = 100  // Ticker timers firing within this window are fetched as one batch
+	MaxBatchCoalesceWindowMs     = 1000 // Upper bound for batch_coalesce_window_ms (every ticker in the batch waits for it)
+)
+
+// Database Compaction Configuration
+const (
+	CompactionCheckIntervalMin = 30 // How often off-hours compaction of closed databases is considered
+	CompactionLookbackDays     = 7  // Scheduled passes only look at this many previous days (manual passes look at all)
+)
+
+// Clock Skew Configuration
+const (
+	ClockSkewWarnSec      = 10.0   // Warn (and correct, if enabled) when the local clock is off from API time by more than this
+	ClockSkewMaxSampleSec = 1800.0 // Differences beyond this are stale snapshots, not clock error, and are ignored
+	ClockSkewSampleWindow = 60     // Recent samples the skew estimate is taken from
+	ClockSkewMinSamples   = 5      // Samples needed before the estimate is reported or acted on
+)
+
+// Pre-Open Configuration
+const (
+	PreOpenLeadMinutes      = 5   // The pre-open phase (API check, databases and schemas) runs this long before the open (9:25 ET)
+	PreOpenCheckIntervalSec = 60  // How often the pre-open watcher checks the clock outside the pre-open phase
+	PreOpenRampGraceSec     = 300 // The open callback only runs this soon after the open (not when the app starts mid-session)
+	PreOpenVerifyTimeoutSec = 15  // Timeout of the pre-open API check request
+)
+
+// Market Date Rollover Configuration
+const (
+	MarketDateCheckIntervalSec = 15 // How often the market date watcher checks for the 8:30 AM ET rollover
+)
+
+// Quota Saver Configuration
+const (
+	QuotaProjectionMinWindowSec = 900.0 // Only quotas resetting at least this far ahead are projected (per-minute limits are throttled instead)
+	QuotaProjectionSafetyRatio  = 1.2   // Projected usage is padded by this much for bursts, retries and on-demand fetches
+)
+
+// Crash Reporting Configuration
+const (
+	CrashDirName              = "crashes" // Crash dumps are written to this folder inside the config dir
+	CrashReportMaxFiles       = 20        // Oldest crash dumps beyond this are deleted
+	CrashReportMinIntervalSec = 60        // Minimum time between dumps for the same component (a panicking loop writes one dump)
+	RecentLogBufferLines      = 2000      // Log lines kept in memory for crash dumps and the log viewer
+	CrashReportLogLines       = 200       // Most recent log lines included in a crash dump
+)
+
+// Log Viewer Configuration
+const (
+	DefaultLogViewerLimit = 500 // Lines returned by GetRecentLogs when no limit is given
+)
+
+// Profiler Configuration
+const (
+	DefaultProfilerAddress = "localhost:6060" // pprof listen address when profiler_address is not set
+)
+
+// Chart Image Export Configuration
+const (
+	ChartExportDirName      = "exports" // Exported chart images go to this folder inside the config dir by default
+	DefaultChartImageWidth  = 1600
+	DefaultChartImageHeight = 900
+	MinChartImageSize       = 320  // Smallest width/height accepted (pixels)
+	MaxChartImageSize       = 7680 // Largest width/height accepted (pixels, 8K)
+)
+
+// Chart Snapshot Configuration
+const (
+	SnapshotDirName          = "snapshots" // Scheduled chart images go here (inside the config dir) unless a directory is set
+	SnapshotCheckIntervalSec = 20          // How often capture times are checked
+	SnapshotGraceMinutes     = 5           // A capture time still fires this long after it passes (e.g. app started late)
+)
+
+// Diagnostics Export Configuration
+const (
+	DiagnosticsSampleRows    = 20              // Latest rows per ticker included in a diagnostics bundle
+	DiagnosticsLogTailBytes  = 2 * 1024 * 1024 // Tail of the current log file included (bytes)
+	DiagnosticsCrashReports  = 5               // Most recent crash dumps included
+)
+
+// Chart Loader Configuration
+const (
+	ChartScanInitialCapacity  = 4096  // Rows preallocated per column buffer when scanning chart data (grows as needed)
+	ParallelColumnConvertRows = 10000 // Results with at least this many rows convert their columns concurrently
+)
+
+// Shutdown Configuration
+const (
+	ShutdownAbortGraceSec = 5 // A shutdown step still running this long after the ShutdownFinalFlushTimeout budget is abandoned
+)
+
+// Tracing Configuration
+const (
+	RecentTraceCount       = 500  // Collection traces kept in memory for GetRecentTraces
+	TraceExportIntervalSec = 5    // How often finished spans are sent to the OTLP collector
+	TraceExportBatchSize   = 512  // Most spans sent in one OTLP request
+	TraceExportQueueSize   = 8192 // Spans buffered for export; the oldest are dropped when the collector can't keep up
+	TraceExportTimeoutSec  = 10   // OTLP request timeout
+)
+
+// Raw Response Recording Configuration
+const (
+	RawRecordFlushSec       = 10               // Recorded responses are appended to the day's raw file this often
+	RawResponseMaxLineBytes = 64 * 1024 * 1024 // Largest recorded response read back when replaying
+	ReplayBatchRows         = 500              // Replayed rows written per transaction
+)
+
+// Settings Profiles Configuration
+const (
+	DefaultProfileName    = "default"        // Profile stored in config.yaml itself
+	ProfilesDirName       = "profiles"       // Other profiles: <config dir>/profiles/<name>.yaml
+	ActiveProfileFileName = "active_profile" // Name of the profile loaded at startup (absent = default)
+)
+
+// Chart Refresh Rate Configuration
+const (
+	ChartRefreshRateMinMs = 500    // Fastest refresh a chart window may request (the rate limit floor still applies)
+	ChartRefreshRateMaxMs = 600000 // Slowest (10 minutes)
+)
+
+// Settings History Configuration
+const (
+	SettingsHistoryDirName = "history" // Previous versions: <config dir>/history/<config file name>/<version>.yaml
+	SettingsHistoryMax     = 10        // Versions kept per settings file (oldest are removed)
+)
+
+// Hotkey Configuration
+const (
+	DefaultHotkeyOpenChart       = "Ctrl+Alt+C" // Open the chart of the ticker under the cursor
+	DefaultHotkeyPauseCollection = "Ctrl+Alt+P" // Pause/resume collection
+	DefaultHotkeySnapshotAll     = "Ctrl+Alt+S" // Snapshot every open chart
+)
+
+// Chart Window Configuration
+const (
+	ChartWindowDefaultWidth  = 1200 // Size of a newly opened chart window
+	ChartWindowDefaultHeight = 800
+	ChartWindowMinWidth      = 600 // Smallest chart window (also the smallest size a workspace may restore)
+	ChartWindowMinHeight     = 400
+)
+
+// Window Placement Configuration
+const (
+	MaxWindowPlacements    = 8  // Monitor configurations remembered for the main window (least recently used dropped)
+	WindowTitleBarHeight   = 32 // Height of the strip that must stay on a screen so a window can be dragged
+	WindowMinVisibleWidth  = 120
+	WindowPlacementDelayMs = 1000 // Wait after the main window stops moving before saving its placement
+)
+
+// Eco Mode Configuration
+const (
+	DefaultEcoIntervalMultiplier = 3  // Polling intervals are this many times longer in eco mode
+	EcoFlushIntervalSec          = 10 // Background (collection) tickers batch this long before flushing in eco mode
+	EcoFlushCountThreshold       = 20 // ...or this many rows
+	PowerCheckIntervalSec        = 30 // How often the power source is checked (eco_mode: auto)
+)
+
+// Endpoint Merge Configuration
+const (
+	MergeTimestampToleranceSec = 1.0 // Responses this close in API time are the same snapshot; source priority picks the value
+)
+
+// Fetch Fallback Configuration
+const (
+	DefaultFetchFallbackMaxAgeSec = 120 // A failed endpoint's fields are filled from its last successful response up to this old
+)
+
+// Schema Configuration
+const (
+	ScalarColumnMaxMagnitude = 1e15 // ticker_data CHECK: scalar values at or beyond this magnitude (or non-finite) are rejected as writer bugs
+	RejectedValuesLogged     = 5    // Refused values listed in a flush's log line (all are counted in the rejected values status)
+)
+
+// Data Quality Configuration
+const (
+	QualityDelayedFetchSec = 5.0 // Rows whose slowest endpoint took longer than this are flagged as delayed fetches
+)
+
+// Spot Cross-Check Configuration
+const (
+	DefaultSpotCheckThresholdPct = 0.25 // GEXBot spot this far (percent) from the secondary quote is flagged
+	DefaultSpotCheckIntervalSec  = 30   // Secondary quotes are requested at most this often per ticker
+	SpotCheckRequestTimeoutSec   = 5    // Quote request timeout
+)
+
+// Max-Change Configuration
+const (
+	MaxChangeTopStrikes = 10 // Gaining/losing strikes returned with a day's max-change history
+)
+
+// Database Merge Configuration
+const (
+	MergeDedupToleranceSec = 0.1 // MergeDatabases: a source row this close to a local row is the same snapshot (matches the writer's dedup)
+)
+
+// Collector Lock Configuration
+const (
+	CollectorLockHeartbeatSec = 10 // The collecting instance refreshes its data directory lock this often
+	CollectorLockStaleSec     = 45 // A lock not refreshed for this long belongs to a crashed instance and is taken over
+	CollectorLockGuardWaitMs  = 2000 // How long to wait for another instance's takeover or heartbeat to finish
+	CollectorLockGuardRetryMs = 20   // Retry interval while waiting for it
+)
+
+// Alert Configuration
+const (
+	DefaultAlertSound = "chime" // Sound of rules without one (live_alerts.default_sound)
+	AlertRecentLimit  = 100     // Triggers kept for the UI's alert poll (GetRecentAlerts)
+)
+
+// Single Instance Configuration
+const (
+	SingleInstanceID    = "com.market-terminal.gexbot" // Per-profile suffix is added: each profile runs at most once
+	OpenChartLaunchFlag = "--open-chart="              // --open-chart=TICKER opens that ticker's chart (forwarded to a running instance)
+	URLScheme           = "mgt"                        // Deep links: mgt://chart/SPX?date=2026-01-14
+)
+
+// Range Pagination Configuration
+const (
+	RangePageDefaultLimit = 5000  // Rows per GetTickerDataRangePage page when no limit is given
+	RangePageMaxLimit     = 50000 // Largest page a caller may ask for
+)
+
+// Derived Series Configuration
+const (
+	DerivedRollingWindowSec = 300 // Window of the rolling level distance averages (*_dist_avg), in seconds
+)
+
+// Scripting Configuration
+const (
+	ScriptsDirName         = "scripts"  // User scripts: <config dir>/scripts/<name>.lua
+	DefaultScriptTimeoutMs = 25         // CPU time one script call may take before it is stopped
+	ScriptMaxTimeoutMs     = 1000       // Largest scripts.timeout_ms
+	ScriptMaxFileBytes     = 256 * 1024 // Larger script files are not loaded
+	ScriptCallStackSize    = 64         // Lua call depth (deep recursion fails instead of exhausting memory)
+	ScriptRegistrySize     = 16 * 1024  // Lua value stack slots (fixed; a runaway stack fails the call)
+	ScriptMaxStringBytes   = 1 << 20    // string.rep, string.format and table.concat results are capped at this size
+	ScriptMaxCallAllocMB   = 64         // A call allocating more than this (e.g. a .. doubling loop) is stopped
+	ScriptAllocCheckMs     = 1          // How often a running call's allocations are checked
+	ScriptMaxColumns       = 32         // Columns one script may return per row
+	ScriptMaxFailures      = 10         // Consecutive failed calls before a script is disabled until reloaded
+)
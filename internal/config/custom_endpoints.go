@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CustomEndpoint is a user-defined API endpoint collected like the built-in ones
+// Lets new GEXBot API additions be collected without waiting for an app release
+type CustomEndpoint struct {
+	Name        string            `yaml:"name" json:"Name"`                 // Unique endpoint name (letters, digits, underscores)
+	URLTemplate string            `yaml:"url_template" json:"URLTemplate"`  // e.g. "{base}/{ticker}/classic/zero/new?key={key}"
+	Tier        string            `yaml:"tier" json:"Tier"`                 // Subscription tier: classic, state or orderflow
+	Columns     map[string]string `yaml:"columns,omitempty" json:"Columns"` // Response field -> column name; empty = store all fields as-is
+	Chart       bool              `yaml:"chart,omitempty" json:"Chart"`     // Also collect in chart-only mode (collect_all_endpoints: false)
+}
+
+// CustomEndpointTiers are the tiers a custom endpoint can belong to
+var CustomEndpointTiers = []string{"classic", "state", "orderflow"}
+
+// isIdentifier reports whether s only contains letters, digits and underscores
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks a custom endpoint definition
+func (e CustomEndpoint) Validate() error {
+	if !isIdentifier(e.Name) {
+		return fmt.Errorf("custom endpoint name %q may only contain letters, digits and underscores", e.Name)
+	}
+	if !strings.Contains(e.URLTemplate, "{ticker}") || !strings.Contains(e.URLTemplate, "{key}") {
+		return fmt.Errorf("custom endpoint %s: url_template must contain {ticker} and {key}", e.Name)
+	}
+	if !strings.HasPrefix(e.URLTemplate, "{base}") && !strings.HasPrefix(e.URLTemplate, "https://") && !strings.HasPrefix(e.URLTemplate, "http://") {
+		return fmt.Errorf("custom endpoint %s: url_template must start with {base} or an http(s):// URL", e.Name)
+	}
+	validTier := false
+	for _, tier := range CustomEndpointTiers {
+		if e.Tier == tier {
+			validTier = true
+			break
+		}
+	}
+	if !validTier {
+		return fmt.Errorf("custom endpoint %s: unknown tier %q (expected classic, state or orderflow)", e.Name, e.Tier)
+	}
+	for field, column := range e.Columns {
+		if !isIdentifier(column) {
+			return fmt.Errorf("custom endpoint %s: column name %q for field %q may only contain letters, digits and underscores", e.Name, column, field)
+		}
+		if column == "timestamp" || column == "profiles_blob" {
+			return fmt.Errorf("custom endpoint %s: column name %q is reserved", e.Name, column)
+		}
+	}
+	return nil
+}
+
+// ValidateCustomEndpoints checks every definition and rejects duplicate names
+// builtIn reports whether a name is already a built-in endpoint
+func ValidateCustomEndpoints(endpoints []CustomEndpoint, builtIn func(string) bool) error {
+	seen := make(map[string]bool, len(endpoints))
+	for _, endpoint := range endpoints {
+		if err := endpoint.Validate(); err != nil {
+			return err
+		}
+		if builtIn != nil && builtIn(endpoint.Name) {
+			return fmt.Errorf("custom endpoint %s: name is already a built-in endpoint", endpoint.Name)
+		}
+		if seen[endpoint.Name] {
+			return fmt.Errorf("custom endpoint %s is defined more than once", endpoint.Name)
+		}
+		seen[endpoint.Name] = true
+	}
+	return nil
+}
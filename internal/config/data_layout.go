@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Day directory layouts (data_layout setting)
+const (
+	DataLayoutFlat = "flat" // "Tickers 01.14.2026/SPX.db" (default)
+	DataLayoutISO  = "iso"  // "Tickers/2026/01/14/SPX.db" - unambiguous and sorts by date
+)
+
+// DefaultDataDirectory is used when data_directory is empty
+const DefaultDataDirectory = "Tickers"
+
+// SimulatedDataDirectorySuffix is appended to the data directory of a --simulate-clock launch
+// ("Tickers-simulated 03.06.2026/SPX.db"), so rows filed under simulated dates never mix with collected days
+const SimulatedDataDirectorySuffix = "-simulated"
+
+// DayDirectory is a market date's data directory found on disk
+type DayDirectory struct {
+	Date time.Time // Midnight of the market date in the location passed to the listing
+	Path string
+}
+
+// dataDirectoryOverride replaces data_directory for this launch without being saved ("" = none)
+var dataDirectoryOverride string
+
+// SetDataDirectoryOverride points GetDataDirectory at dir for this launch (--simulate-clock); settings saved
+// meanwhile keep their own data_directory. Called from main.go before anything reads the data directory
+func SetDataDirectoryOverride(dir string) {
+	dataDirectoryOverride = dir
+}
+
+// GetDataDirectory returns the data directory root, or the default if unset
+func (s *Settings) GetDataDirectory() string {
+	if dataDirectoryOverride != "" {
+		return dataDirectoryOverride
+	}
+	if s.DataDirectory == "" {
+		return DefaultDataDirectory
+	}
+	return s.DataDirectory
+}
+
+// GetDataLayout returns the day directory layout (flat unless set to iso)
+func (s *Settings) GetDataLayout() string {
+	if s.DataLayout == "" {
+		return DataLayoutFlat
+	}
+	return s.DataLayout
+}
+
+// ValidateDataLayout checks data_layout is flat or iso
+func (s *Settings) ValidateDataLayout() error {
+	return ValidateDataLayout(s.DataLayout)
+}
+
+// ValidateDataLayout checks a layout name (empty = flat)
+func ValidateDataLayout(layout string) error {
+	switch layout {
+	case "", DataLayoutFlat, DataLayoutISO:
+		return nil
+	}
+	return fmt.Errorf("data_layout %q must be %s or %s", layout, DataLayoutFlat, DataLayoutISO)
+}
+
+// DayDirectory returns the data directory for a market date (the date's calendar day, in its own location)
+func (s *Settings) DayDirectory(date time.Time) string {
+	return DayDirectoryFor(s.GetDataDirectory(), s.GetDataLayout(), date)
+}
+
+// DayDirectoryFor returns a market date's directory under dataDir in a layout
+func DayDirectoryFor(dataDir, layout string, date time.Time) string {
+	if layout == DataLayoutISO {
+		return filepath.Join(dataDir, date.Format("2006"), date.Format("01"), date.Format("02"))
+	}
+	return fmt.Sprintf("%s %s", dataDir, date.Format("01.02.2006"))
+}
+
+// ListDayDirectories returns the existing day directories of the configured layout, oldest first
+// Dates are parsed in loc (the market timezone)
+func (s *Settings) ListDayDirectories(loc *time.Location) ([]DayDirectory, error) {
+	return ListDayDirectoriesFor(s.GetDataDirectory(), s.GetDataLayout(), loc)
+}
+
+// ListDayDirectoriesFor returns the existing day directories under dataDir in a layout, oldest first
+func ListDayDirectoriesFor(dataDir, layout string, loc *time.Location) ([]DayDirectory, error) {
+	pattern, dateLayout := dataDir+" ??.??.????", "01.02.2006"
+	if layout == DataLayoutISO {
+		pattern, dateLayout = filepath.Join(dataDir, "????", "??", "??"), "2006/01/02"
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]DayDirectory, 0, len(matches))
+	for _, match := range matches {
+		var dateStr string
+		if layout == DataLayoutISO {
+			rel, err := filepath.Rel(dataDir, match)
+			if err != nil {
+				continue
+			}
+			dateStr = filepath.ToSlash(rel)
+		} else {
+			dateStr = strings.TrimPrefix(filepath.Base(match), filepath.Base(dataDir)+" ")
+		}
+		date, err := time.ParseInLocation(dateLayout, dateStr, loc)
+		if err != nil {
+			continue // Not a day directory ("Tickers 13.45.2026", "Tickers/2026/ab/01", ...)
+		}
+		days = append(days, DayDirectory{Date: date, Path: match})
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date.Before(days[j].Date) })
+	return days, nil
+}
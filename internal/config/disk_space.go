@@ -0,0 +1,41 @@
+package config
+
+import "fmt"
+
+// DiskSpaceSettings controls the free space checks on the data directory's volume
+type DiskSpaceSettings struct {
+	WarnMB           int `yaml:"warn_mb" json:"WarnMB"`                      // Below this the UI is warned
+	CriticalMB       int `yaml:"critical_mb" json:"CriticalMB"`              // Below this only essential columns are written (no profiles)
+	CheckIntervalSec int `yaml:"check_interval_sec" json:"CheckIntervalSec"` // How often free space is checked
+}
+
+// DefaultDiskSpaceSettings returns the built-in disk space thresholds
+func DefaultDiskSpaceSettings() DiskSpaceSettings {
+	return DiskSpaceSettings{
+		WarnMB:           DefaultDiskWarnMB,
+		CriticalMB:       DefaultDiskCriticalMB,
+		CheckIntervalSec: DefaultDiskCheckIntervalSec,
+	}
+}
+
+// Validate checks that the critical threshold is below the warning threshold and the interval is sane
+func (d DiskSpaceSettings) Validate() error {
+	if d.CriticalMB < 1 {
+		return fmt.Errorf("critical disk space threshold must be at least 1 MB (got %d)", d.CriticalMB)
+	}
+	if d.WarnMB < d.CriticalMB {
+		return fmt.Errorf("disk space warning threshold (%d MB) must not be below the critical threshold (%d MB)", d.WarnMB, d.CriticalMB)
+	}
+	if d.CheckIntervalSec < MinDiskCheckIntervalSec {
+		return fmt.Errorf("disk space check interval must be at least %d seconds (got %d)", MinDiskCheckIntervalSec, d.CheckIntervalSec)
+	}
+	return nil
+}
+
+// GetDiskSpaceSettings returns the configured disk space thresholds, or the defaults if unset
+func (s *Settings) GetDiskSpaceSettings() DiskSpaceSettings {
+	if s.DiskSpace == nil {
+		return DefaultDiskSpaceSettings()
+	}
+	return *s.DiskSpace
+}
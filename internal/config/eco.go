@@ -0,0 +1,39 @@
+package config
+
+import "fmt"
+
+// Eco modes
+const (
+	EcoModeAuto = "auto" // Eco while the machine runs on battery
+	EcoModeOn   = "on"   // Always eco (manual toggle)
+	EcoModeOff  = "off"  // Never eco
+)
+
+// GetEcoMode returns eco_mode with the default applied ("" = auto)
+func (s *Settings) GetEcoMode() string {
+	if s.EcoMode == "" {
+		return EcoModeAuto
+	}
+	return s.EcoMode
+}
+
+// GetEcoIntervalMultiplier returns how much longer polling intervals get in eco mode
+func (s *Settings) GetEcoIntervalMultiplier() float64 {
+	if s.EcoIntervalMultiplier <= 0 {
+		return DefaultEcoIntervalMultiplier
+	}
+	return s.EcoIntervalMultiplier
+}
+
+// ValidateEcoMode checks eco_mode is auto/on/off and the multiplier doesn't shorten intervals
+func (s *Settings) ValidateEcoMode() error {
+	switch s.EcoMode {
+	case "", EcoModeAuto, EcoModeOn, EcoModeOff:
+	default:
+		return fmt.Errorf("eco_mode %q must be auto, on or off", s.EcoMode)
+	}
+	if s.EcoIntervalMultiplier != 0 && s.EcoIntervalMultiplier < 1 {
+		return fmt.Errorf("eco_interval_multiplier %g must be at least 1 (0 = default %g)", s.EcoIntervalMultiplier, float64(DefaultEcoIntervalMultiplier))
+	}
+	return nil
+}
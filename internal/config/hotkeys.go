@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Hotkey actions
+const (
+	HotkeyOpenChart       = "open_chart"       // Open the chart of the ticker under the cursor in the main window
+	HotkeyPauseCollection = "pause_collection" // Pause or resume collection
+	HotkeySnapshotAll     = "snapshot_all"     // Save an image of every open chart
+)
+
+// HotkeyActions lists the actions in display order
+var HotkeyActions = []string{HotkeyOpenChart, HotkeyPauseCollection, HotkeySnapshotAll}
+
+// HotkeySettings configures system-wide keyboard shortcuts ("" = the default shortcut, "off" = no shortcut)
+type HotkeySettings struct {
+	Enabled         bool   `yaml:"enabled" json:"Enabled"`
+	OpenChart       string `yaml:"open_chart,omitempty" json:"OpenChart"`             // Default Ctrl+Alt+C
+	PauseCollection string `yaml:"pause_collection,omitempty" json:"PauseCollection"` // Default Ctrl+Alt+P
+	SnapshotAll     string `yaml:"snapshot_all,omitempty" json:"SnapshotAll"`         // Default Ctrl+Alt+S
+}
+
+// Shortcut is a parsed key combination such as "Ctrl+Shift+F5"
+type Shortcut struct {
+	Ctrl  bool
+	Alt   bool
+	Shift bool
+	Super bool   // Windows/Command key
+	Key   string // "A"-"Z", "0"-"9" or "F1"-"F24"
+}
+
+// String formats the shortcut the way ParseShortcut reads it
+func (s Shortcut) String() string {
+	parts := make([]string, 0, 5)
+	if s.Ctrl {
+		parts = append(parts, "Ctrl")
+	}
+	if s.Alt {
+		parts = append(parts, "Alt")
+	}
+	if s.Shift {
+		parts = append(parts, "Shift")
+	}
+	if s.Super {
+		parts = append(parts, "Super")
+	}
+	return strings.Join(append(parts, s.Key), "+")
+}
+
+// ParseShortcut parses "Modifier+...+Key" (case-insensitive); at least one modifier is required so a
+// global shortcut can't swallow plain typing
+func ParseShortcut(text string) (Shortcut, error) {
+	var shortcut Shortcut
+	parts := strings.Split(strings.ReplaceAll(text, " ", ""), "+")
+	for i, part := range parts {
+		upper := strings.ToUpper(part)
+		if i == len(parts)-1 {
+			if !isShortcutKey(upper) {
+				return Shortcut{}, fmt.Errorf("shortcut %q: key must be A-Z, 0-9 or F1-F24", text)
+			}
+			shortcut.Key = upper
+			break
+		}
+		switch upper {
+		case "CTRL", "CONTROL":
+			shortcut.Ctrl = true
+		case "ALT", "OPTION":
+			shortcut.Alt = true
+		case "SHIFT":
+			shortcut.Shift = true
+		case "SUPER", "WIN", "CMD", "META":
+			shortcut.Super = true
+		default:
+			return Shortcut{}, fmt.Errorf("shortcut %q: unknown modifier %q", text, part)
+		}
+	}
+	if !shortcut.Ctrl && !shortcut.Alt && !shortcut.Super {
+		return Shortcut{}, fmt.Errorf("shortcut %q needs Ctrl, Alt or Super", text)
+	}
+	return shortcut, nil
+}
+
+func isShortcutKey(key string) bool {
+	if len(key) == 1 {
+		return (key[0] >= 'A' && key[0] <= 'Z') || (key[0] >= '0' && key[0] <= '9')
+	}
+	var n int
+	if _, err := fmt.Sscanf(key, "F%d", &n); err == nil && fmt.Sprintf("F%d", n) == key {
+		return n >= 1 && n <= 24
+	}
+	return false
+}
+
+// Bindings returns action -> shortcut with defaults applied (actions set to "off" are left out)
+func (h HotkeySettings) Bindings() map[string]string {
+	bindings := make(map[string]string, len(HotkeyActions))
+	for action, shortcut := range map[string]string{
+		HotkeyOpenChart:       orDefault(h.OpenChart, DefaultHotkeyOpenChart),
+		HotkeyPauseCollection: orDefault(h.PauseCollection, DefaultHotkeyPauseCollection),
+		HotkeySnapshotAll:     orDefault(h.SnapshotAll, DefaultHotkeySnapshotAll),
+	} {
+		if !strings.EqualFold(shortcut, "off") {
+			bindings[action] = shortcut
+		}
+	}
+	return bindings
+}
+
+// Validate checks every shortcut parses and no two actions share one
+func (h HotkeySettings) Validate() error {
+	bindings := h.Bindings()
+	seen := make(map[string]string)
+	for _, action := range HotkeyActions {
+		text, ok := bindings[action]
+		if !ok {
+			continue
+		}
+		shortcut, err := ParseShortcut(text)
+		if err != nil {
+			return fmt.Errorf("hotkey %s: %w", action, err)
+		}
+		if other, ok := seen[shortcut.String()]; ok {
+			return fmt.Errorf("hotkeys %s and %s both use %s", other, action, shortcut)
+		}
+		seen[shortcut.String()] = action
+	}
+	return nil
+}
+
+func orDefault(value, def string) string {
+	if strings.TrimSpace(value) == "" {
+		return def
+	}
+	return value
+}
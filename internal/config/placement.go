@@ -0,0 +1,13 @@
+package config
+
+// WindowPlacement is the main window's geometry on one monitor configuration, so undocking a laptop and
+// docking it again puts the window back where it was on each setup
+type WindowPlacement struct {
+	Monitors    string  `yaml:"monitors" json:"Monitors"`        // Monitor configuration key (screen IDs, bounds and DPI scale of every screen)
+	Screen      string  `yaml:"screen" json:"Screen"`            // ID of the screen the window was on
+	ScaleFactor float32 `yaml:"scale_factor" json:"ScaleFactor"` // DPI scale of that screen
+	X           int     `yaml:"x" json:"X"`
+	Y           int     `yaml:"y" json:"Y"`
+	Width       int     `yaml:"width" json:"Width"`
+	Height      int     `yaml:"height" json:"Height"`
+}
@@ -0,0 +1,91 @@
+package config
+
+import "fmt"
+
+// IntervalTier holds polling intervals (seconds) for one priority, by ticker count bucket
+type IntervalTier struct {
+	Small  float64 `yaml:"small" json:"Small"`   // ticker count <= SmallTickerCount
+	Medium float64 `yaml:"medium" json:"Medium"` // ticker count <= MediumTickerCount
+	Large  float64 `yaml:"large" json:"Large"`   // ticker count > MediumTickerCount
+}
+
+// PollingIntervals is the interval matrix (priority × ticker count) used by the scheduler
+type PollingIntervals struct {
+	SmallTickerCount  int          `yaml:"small_ticker_count" json:"SmallTickerCount"`
+	MediumTickerCount int          `yaml:"medium_ticker_count" json:"MediumTickerCount"`
+	High              IntervalTier `yaml:"high" json:"High"`     // Tickers open in a chart or configured "high"
+	Medium            IntervalTier `yaml:"medium" json:"Medium"` // Enabled tickers (default priority)
+	Low               IntervalTier `yaml:"low" json:"Low"`       // Tickers configured "low"
+}
+
+// DefaultPollingIntervals returns the built-in interval matrix
+func DefaultPollingIntervals() PollingIntervals {
+	return PollingIntervals{
+		SmallTickerCount:  5,
+		MediumTickerCount: 20,
+		High:              IntervalTier{Small: 1.0, Medium: 1.0, Large: 1.0},
+		Medium:            IntervalTier{Small: 6.0, Medium: 10.0, Large: 15.0},
+		Low:               IntervalTier{Small: 16.0, Medium: 22.0, Large: 30.0},
+	}
+}
+
+// Validate checks that thresholds and intervals are within allowed bounds
+func (p PollingIntervals) Validate() error {
+	if p.SmallTickerCount < 1 {
+		return fmt.Errorf("small ticker count must be at least 1 (got %d)", p.SmallTickerCount)
+	}
+	if p.MediumTickerCount <= p.SmallTickerCount {
+		return fmt.Errorf("medium ticker count (%d) must be greater than small ticker count (%d)", p.MediumTickerCount, p.SmallTickerCount)
+	}
+
+	tiers := []struct {
+		name string
+		tier IntervalTier
+	}{
+		{"high", p.High},
+		{"medium", p.Medium},
+		{"low", p.Low},
+	}
+	for _, t := range tiers {
+		for bucket, interval := range map[string]float64{"small": t.tier.Small, "medium": t.tier.Medium, "large": t.tier.Large} {
+			if interval < MinPollingIntervalSec || interval > MaxPollingIntervalSec {
+				return fmt.Errorf("%s priority %s interval must be between %.0fs and %.0fs (got %.2fs)",
+					t.name, bucket, MinPollingIntervalSec, MaxPollingIntervalSec, interval)
+			}
+		}
+	}
+
+	return nil
+}
+
+// IntervalFor returns the interval (seconds) for a priority (0=high, 1=medium, 2=low) and ticker count
+func (p PollingIntervals) IntervalFor(priority int, tickerCount int) float64 {
+	var tier IntervalTier
+	switch priority {
+	case 0:
+		tier = p.High
+	case 1:
+		tier = p.Medium
+	default:
+		tier = p.Low
+	}
+
+	if tickerCount <= p.SmallTickerCount {
+		return tier.Small
+	} else if tickerCount <= p.MediumTickerCount {
+		return tier.Medium
+	}
+	return tier.Large
+}
+
+// GetPollingIntervals returns the configured interval matrix
+// Falls back to the defaults if none is configured or the configured one is invalid
+func (s *Settings) GetPollingIntervals() PollingIntervals {
+	if s == nil || s.PollingIntervals == nil {
+		return DefaultPollingIntervals()
+	}
+	if err := s.PollingIntervals.Validate(); err != nil {
+		return DefaultPollingIntervals()
+	}
+	return *s.PollingIntervals
+}
@@ -0,0 +1,27 @@
+package config
+
+import "fmt"
+
+// ProcessorSettings enables data processors (internal/processors) that add columns and events to collected rows
+type ProcessorSettings struct {
+	Enabled []string            `yaml:"enabled,omitempty" json:"Enabled"` // Registered processor names, run in this order
+	Tickers map[string][]string `yaml:"tickers,omitempty" json:"Tickers"` // Processor -> tickers it runs on (missing = every ticker)
+}
+
+// Validate checks each processor is enabled once and ticker filters only name enabled processors
+// (whether a name is registered is checked when the settings are applied)
+func (s ProcessorSettings) Validate() error {
+	enabled := make(map[string]bool, len(s.Enabled))
+	for _, name := range s.Enabled {
+		if name == "" || enabled[name] {
+			return fmt.Errorf("processor %q is listed more than once or empty", name)
+		}
+		enabled[name] = true
+	}
+	for name := range s.Tickers {
+		if !enabled[name] {
+			return fmt.Errorf("processors.tickers names %q, which is not enabled", name)
+		}
+	}
+	return nil
+}
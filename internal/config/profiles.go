@@ -0,0 +1,213 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileInfo describes a named configuration profile (e.g. "live", "low-quota", "research")
+// Each profile is a complete settings file with its own tickers, intervals and data directory
+type ProfileInfo struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Active  bool   `json:"active"`  // Profile loaded at the next startup
+	Running bool   `json:"running"` // Profile this process is using
+}
+
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// launchProfile overrides the active profile for this launch (--profile=NAME)
+var launchProfile string
+
+// SetLaunchProfile selects a profile for this launch without changing the active one (called from main.go
+// before any SettingsManager is created)
+func SetLaunchProfile(name string) {
+	launchProfile = name
+}
+
+// ValidateProfileName checks a profile name is usable as a file name
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q (letters, digits, '-' and '_', up to 64 characters)", name)
+	}
+	return nil
+}
+
+// ProfilePath returns the settings file of a profile ("default" is config.yaml)
+func ProfilePath(name string) (string, error) {
+	if name == "" || name == DefaultProfileName {
+		return GetConfigPath()
+	}
+	if err := ValidateProfileName(name); err != nil {
+		return "", err
+	}
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, ProfilesDirName, name+".yaml"), nil
+}
+
+// GetActiveProfile returns the profile loaded at startup: the --profile override, else the one saved
+// by SetActiveProfile, else "default"
+func GetActiveProfile() string {
+	if launchProfile != "" {
+		return launchProfile
+	}
+	return savedActiveProfile()
+}
+
+// savedActiveProfile reads the active_profile file ("default" if missing or invalid)
+func savedActiveProfile() string {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return DefaultProfileName
+	}
+	data, err := os.ReadFile(filepath.Join(configDir, ActiveProfileFileName))
+	if err != nil {
+		return DefaultProfileName
+	}
+	name := strings.TrimSpace(string(data))
+	if name == "" || ValidateProfileName(name) != nil {
+		return DefaultProfileName
+	}
+	return name
+}
+
+// SetActiveProfile makes a profile the one loaded at startup
+func SetActiveProfile(name string) error {
+	if name == "" {
+		name = DefaultProfileName
+	}
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, ActiveProfileFileName), []byte(name+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to save active profile: %w", err)
+	}
+	return nil
+}
+
+// ListProfiles returns the default profile and every profile under the profiles directory, sorted by name
+// running is the profile of the current process
+func ListProfiles(running string) ([]ProfileInfo, error) {
+	active := savedActiveProfile() // A --profile override only applies to this launch
+
+	defaultPath, err := GetConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	profiles := []ProfileInfo{{
+		Name:    DefaultProfileName,
+		Path:    defaultPath,
+		Active:  active == DefaultProfileName,
+		Running: running == DefaultProfileName,
+	}}
+
+	dir := filepath.Join(filepath.Dir(defaultPath), ProfilesDirName)
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read profiles directory: %w", err)
+	}
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".yaml")
+		if entry.IsDir() || name == entry.Name() || name == DefaultProfileName || ValidateProfileName(name) != nil {
+			continue
+		}
+		profiles = append(profiles, ProfileInfo{
+			Name:    name,
+			Path:    filepath.Join(dir, entry.Name()),
+			Active:  active == name,
+			Running: running == name,
+		})
+	}
+	sort.SliceStable(profiles[1:], func(i, j int) bool { return profiles[i+1].Name < profiles[j+1].Name })
+	return profiles, nil
+}
+
+// CreateProfile creates a profile as a copy of another profile's settings file
+// (from "" or a missing source file gives a profile that starts from the defaults)
+func CreateProfile(name, from string) (string, error) {
+	if name == DefaultProfileName {
+		return "", fmt.Errorf("profile %q already exists", name)
+	}
+	path, err := ProfilePath(name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("profile %q already exists", name)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create profiles directory: %w", err)
+	}
+
+	var data []byte
+	if from != "" {
+		source, err := ProfilePath(from)
+		if err != nil {
+			return "", err
+		}
+		if data, err = os.ReadFile(source); err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to read profile %q: %w", from, err)
+		}
+	}
+	if len(data) == 0 {
+		defaults, err := yaml.Marshal(getDefaultSettings())
+		if err != nil {
+			return "", fmt.Errorf("failed to create profile %q: %w", name, err)
+		}
+		data = defaults
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to create profile %q: %w", name, err)
+	}
+	return path, nil
+}
+
+// DeleteProfile removes a profile's settings file (not "default" or the active profile)
+func DeleteProfile(name string) error {
+	if name == "" || name == DefaultProfileName {
+		return fmt.Errorf("the default profile can't be deleted")
+	}
+	if name == savedActiveProfile() {
+		return fmt.Errorf("profile %q is the active profile - switch to another profile first", name)
+	}
+	path, err := ProfilePath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("profile %q does not exist", name)
+		}
+		return fmt.Errorf("failed to delete profile %q: %w", name, err)
+	}
+	return nil
+}
+
+// ProfileExists reports whether a profile's settings file exists ("default" always exists)
+func ProfileExists(name string) bool {
+	if name == "" || name == DefaultProfileName {
+		return true
+	}
+	path, err := ProfilePath(name)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,96 @@
+package config
+
+import "fmt"
+
+// RequestPolicy controls timeouts and retries for API requests
+type RequestPolicy struct {
+	TimeoutSec    float64 `yaml:"timeout_sec" json:"TimeoutSec"`        // Per-attempt timeout
+	MaxAttempts   int     `yaml:"max_attempts" json:"MaxAttempts"`      // Total attempts, including the first
+	BackoffBaseMs int     `yaml:"backoff_base_ms" json:"BackoffBaseMs"` // Delay before the first retry, doubled on each retry
+	BackoffMaxMs  int     `yaml:"backoff_max_ms" json:"BackoffMaxMs"`   // Cap for the backoff delay
+	JitterPercent int     `yaml:"jitter_percent" json:"JitterPercent"`  // Each delay is randomly reduced by up to this much so retries don't align
+}
+
+// RequestPolicies holds the default policy and per-endpoint overrides
+type RequestPolicies struct {
+	Default   RequestPolicy            `yaml:"default" json:"Default"`
+	Endpoints map[string]RequestPolicy `yaml:"endpoints,omitempty" json:"Endpoints"` // By endpoint name; zero fields inherit Default
+}
+
+// DefaultRequestPolicies returns the built-in policies (orderflow responses are larger and slower)
+func DefaultRequestPolicies() RequestPolicies {
+	return RequestPolicies{
+		Default: RequestPolicy{
+			TimeoutSec:    30,
+			MaxAttempts:   3,
+			BackoffBaseMs: 100,
+			BackoffMaxMs:  2000,
+			JitterPercent: 50,
+		},
+		Endpoints: map[string]RequestPolicy{
+			"orderflow": {TimeoutSec: 60},
+		},
+	}
+}
+
+// ForEndpoint returns the effective policy for an endpoint
+func (p RequestPolicies) ForEndpoint(endpoint string) RequestPolicy {
+	policy := p.Default
+	override, ok := p.Endpoints[endpoint]
+	if !ok {
+		return policy
+	}
+	if override.TimeoutSec > 0 {
+		policy.TimeoutSec = override.TimeoutSec
+	}
+	if override.MaxAttempts > 0 {
+		policy.MaxAttempts = override.MaxAttempts
+	}
+	if override.BackoffBaseMs > 0 {
+		policy.BackoffBaseMs = override.BackoffBaseMs
+	}
+	if override.BackoffMaxMs > 0 {
+		policy.BackoffMaxMs = override.BackoffMaxMs
+	}
+	if override.JitterPercent > 0 {
+		policy.JitterPercent = override.JitterPercent
+	}
+	return policy
+}
+
+// Validate checks the default and every effective endpoint policy
+func (p RequestPolicies) Validate() error {
+	if err := p.Default.validate("default"); err != nil {
+		return err
+	}
+	for endpoint := range p.Endpoints {
+		if err := p.ForEndpoint(endpoint).validate(endpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r RequestPolicy) validate(name string) error {
+	if r.TimeoutSec < 1 || r.TimeoutSec > MaxRequestTimeoutSec {
+		return fmt.Errorf("%s request timeout must be between 1s and %ds (got %.1fs)", name, MaxRequestTimeoutSec, r.TimeoutSec)
+	}
+	if r.MaxAttempts < 1 || r.MaxAttempts > MaxRequestAttempts {
+		return fmt.Errorf("%s request attempts must be between 1 and %d (got %d)", name, MaxRequestAttempts, r.MaxAttempts)
+	}
+	if r.BackoffBaseMs < 0 || r.BackoffMaxMs < r.BackoffBaseMs {
+		return fmt.Errorf("%s backoff must have 0 <= base (%dms) <= max (%dms)", name, r.BackoffBaseMs, r.BackoffMaxMs)
+	}
+	if r.JitterPercent < 0 || r.JitterPercent > 100 {
+		return fmt.Errorf("%s jitter must be between 0 and 100%% (got %d)", name, r.JitterPercent)
+	}
+	return nil
+}
+
+// GetRequestPolicies returns the configured request policies, or the defaults if unset
+func (s *Settings) GetRequestPolicies() RequestPolicies {
+	if s.RequestPolicies == nil {
+		return DefaultRequestPolicies()
+	}
+	return *s.RequestPolicies
+}
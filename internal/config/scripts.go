@@ -0,0 +1,26 @@
+package config
+
+import "fmt"
+
+// ScriptSettings enables user Lua scripts (<config dir>/scripts/*.lua) that define alert conditions (alert rules
+// with condition: script) and derived columns computed on flush
+type ScriptSettings struct {
+	Enabled   bool `yaml:"enabled" json:"Enabled"`
+	TimeoutMs int  `yaml:"timeout_ms,omitempty" json:"TimeoutMs"` // CPU time per call (0 = DefaultScriptTimeoutMs)
+}
+
+// Validate checks the timeout
+func (s ScriptSettings) Validate() error {
+	if s.TimeoutMs < 0 || s.TimeoutMs > ScriptMaxTimeoutMs {
+		return fmt.Errorf("scripts timeout_ms must be between 0 and %d (got %d)", ScriptMaxTimeoutMs, s.TimeoutMs)
+	}
+	return nil
+}
+
+// GetTimeoutMs returns the CPU time one script call may take
+func (s ScriptSettings) GetTimeoutMs() int {
+	if s.TimeoutMs == 0 {
+		return DefaultScriptTimeoutMs
+	}
+	return s.TimeoutMs
+}
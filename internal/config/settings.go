@@ -3,8 +3,10 @@ package config
 import (
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -19,15 +21,19 @@ type Settings struct {
 	// Note: omitempty is removed so API key is always written when present
 	APITKey                        string                      `yaml:"api_key"`
 	APISubscriptionTiers           []string                    `yaml:"api_subscription_tiers"`
+	AdditionalAPIKeys              []APIKeyConfig              `yaml:"additional_api_keys,omitempty"` // Extra keys rotated with api_key (or dedicated to tickers)
 	CollectAllEndpoints            bool                        `yaml:"collect_all_endpoints"` // true = collect all available data, false = chart data only
 	ActiveTickerRefreshRateMs      int                         `yaml:"active_ticker_refresh_rate_ms"`
 	DataCollectionRefreshRateMs    int                         `yaml:"data_collection_refresh_rate_ms"`
+	BatchOverlapPolicy             string                      `yaml:"batch_overlap_policy,omitempty"` // Ticker due while its last batch is still running: "skip" (default) or "queue" one follow-up fetch
 	DataDirectory                  string                      `yaml:"data_directory"`
+	DataLayout                     string                      `yaml:"data_layout,omitempty"` // Day directories: "flat" ("Tickers 01.14.2026", default) or "iso" ("Tickers/2026/01/14"); switch with --migrate-data-layout
 	TrimDataStartTime              string                      `yaml:"trim_data_start_time"`
 	TrimDataEndTime                string                      `yaml:"trim_data_end_time"`
 	EnableDebug                    bool                        `yaml:"enable_debug"`
 	EnableLogging                  bool                        `yaml:"enable_logging"`
 	HideConsole                    bool                        `yaml:"hide_console"`
+	CloseToTray                    bool                        `yaml:"close_to_tray"` // Closing the main window hides it to the system tray
 	UseMarketTime                  bool                        `yaml:"use_market_time"` // Display times in ET instead of local time
 	HiddenPlots                    []string                    `yaml:"hidden_plots"`    // Plots hidden by default on charts
 	ShowCrosshair                  bool                        `yaml:"show_crosshair"`
@@ -42,6 +48,7 @@ type Settings struct {
 	PriceColor                     string                      `yaml:"price_color"`
 	PriceFilterThresholdFuturesPercent float64                 `yaml:"price_filter_threshold_futures_percent"`
 	PriceFilterThresholdStocksPercent   float64                `yaml:"price_filter_threshold_stocks_percent"`
+	SymbolOverrides                map[string]SymbolInfo       `yaml:"symbol_overrides,omitempty"` // Per-ticker metadata overriding the built-in symbol registry
 	LegendOpacity                 int                         `yaml:"legend_opacity"`
 	LegendFontColor                string                      `yaml:"legend_font_color"`
 	LegendFontSize                 int                         `yaml:"legend_font_size"`
@@ -58,15 +65,57 @@ type Settings struct {
 	Tickers                        []interface{}               `yaml:"tickers"`
 	TickerConfigs                  map[string]TickerConfig    `yaml:"ticker_configs"`
 	TickerOrder                    []string                    `yaml:"ticker_order,omitempty"` // User-defined ticker display order
+	TickerAliases                  map[string]string           `yaml:"ticker_aliases,omitempty"` // Renamed symbols: old name -> new name (old days stay readable, collection uses the new name)
+	TickerGroups                   []TickerGroup               `yaml:"ticker_groups,omitempty"` // Named ticker groups (e.g. "Indices", "Mag7")
+	Workspaces                     []Workspace                 `yaml:"workspaces,omitempty"` // Named chart layouts (chart windows with geometry and per-chart options)
+	LastWorkspace                  string                      `yaml:"last_workspace,omitempty"` // Workspace restored at startup ("" = none)
 	ChartColors                    map[string]string           `yaml:"chart_colors"` // Color preferences for chart data series
+	Theme                          string                      `yaml:"theme"`                            // Chart theme name (dark, light, high-contrast)
+	ThemeSeriesOverrides           map[string]string           `yaml:"theme_series_overrides,omitempty"` // Per-series "#RRGGBB" colors applied on top of the theme
 	WindowWidth                    int                         `yaml:"window_width,omitempty"`  // Last saved window width
 	WindowHeight                   int                         `yaml:"window_height,omitempty"` // Last saved window height
+	WindowPlacements               []WindowPlacement           `yaml:"window_placements,omitempty"` // Main window position per monitor configuration, most recent first
+	PollingIntervals               *PollingIntervals           `yaml:"polling_intervals,omitempty"`             // Interval matrix (priority × ticker count), nil = built-in defaults
+	WALCheckpoint                  *WALCheckpointSettings      `yaml:"wal_checkpoint,omitempty"`                // WAL checkpoint policy after flushes, nil = built-in defaults
+	ConnectionPool                 *ConnectionPoolSettings     `yaml:"connection_pool,omitempty"`               // SQLite connection pool size and idle timeouts, nil = built-in defaults
+	DiskSpace                      *DiskSpaceSettings          `yaml:"disk_space,omitempty"`                    // Free space thresholds for the data directory's volume, nil = built-in defaults
+	RequestPolicies                *RequestPolicies            `yaml:"request_policies,omitempty"`             // API timeout/retry policy with per-endpoint overrides, nil = built-in defaults
+	Sync                           SyncSettings                `yaml:"sync"`                                    // Cross-machine sync of completed days
+	TimeSeriesSink                 TimeSeriesSinkSettings      `yaml:"timeseries_sink"`                         // Mirror collected scalar fields to InfluxDB/TimescaleDB (e.g. for Grafana)
+	Tracing                        TracingSettings             `yaml:"tracing"`                                 // Export collection traces to an OpenTelemetry collector
+	Hotkeys                        HotkeySettings              `yaml:"hotkeys"`                                 // System-wide shortcuts (open chart under cursor, pause collection, snapshot all)
+	CustomEndpoints                []CustomEndpoint            `yaml:"custom_endpoints,omitempty"`              // User-defined endpoint templates collected like built-ins
+	ChartSnapshots                 ChartSnapshotSettings       `yaml:"chart_snapshots"`                         // Automatic chart images at fixed market times
+	SpotCheck                      SpotCheckSettings           `yaml:"spot_check"`                              // Cross-check GEXBot's spot against a secondary quote source
+	LiveAlerts                     AlertSettings               `yaml:"live_alerts"`                             // Live alert rules, their sounds and the global mute
+	Processors                     ProcessorSettings           `yaml:"processors"`                              // Data processors adding custom columns/events to collected rows
+	Scripts                        ScriptSettings              `yaml:"scripts"`                                 // User Lua scripts for alert conditions and derived columns (<config dir>/scripts)
+	EncryptCompletedDays           bool                        `yaml:"encrypt_completed_days"`                  // Encrypt each day's databases at the next market date rollover (key kept in OS keychain)
+	ProfileDeltaCompression        bool                        `yaml:"profile_delta_compression"`               // Store profiles as a full keyframe per window + diffs (much smaller databases)
+	RecordRawResponses             bool                        `yaml:"record_raw_responses"`                    // Keep raw API responses per ticker/day (.raw.jsonl.gz) so days can be replayed after a parsing fix
+	ReadOnlyMode                   bool                        `yaml:"read_only_mode"`                          // Browse existing data only: no scheduler, collection or writes (also --read-only)
+	MemoryBudgetMB                 int                         `yaml:"memory_budget_mb"`                        // Process memory budget; 0 = default (1024 MB), negative = no limit
+	IdleAfterMinutes               int                         `yaml:"idle_after_minutes"`                      // Minutes without a focused window before polling slows to collection intervals; 0 = default (15), negative = never
+	EcoMode                        string                      `yaml:"eco_mode,omitempty"`                      // Battery saving: "auto" (on battery, default), "on" or "off" - longer intervals, fewer flushes, profiler suspended
+	EcoIntervalMultiplier          float64                     `yaml:"eco_interval_multiplier,omitempty"`       // Polling interval multiplier in eco mode; 0 = default (3)
+	StartupStaggerSec              float64                     `yaml:"startup_stagger_sec"`                     // Spread of the initial fetches when collection starts; 0 = default (3s), negative = fire all at once
+	BatchCoalesceWindowMs          int                         `yaml:"batch_coalesce_window_ms"`                // Ticker timers firing this close together share one batch; 0 = default (100ms), negative = off
+	FetchFallbackMaxAgeSec         int                         `yaml:"fetch_fallback_max_age_sec"`              // Fill a failed endpoint's fields from its last successful response up to this old; 0 = default (120s), negative = never
+	CorrectClockSkew               bool                        `yaml:"correct_clock_skew"`                      // Offset market time by the clock skew measured against API timestamps (wrong system clock)
+	QuotaSaver                     *bool                       `yaml:"quota_saver,omitempty"`                   // Fetch only chart endpoints for tickers not open in a chart once the quota is projected to run out before the close; nil = enabled
+	EnableProfiler                 *bool                       `yaml:"enable_profiler,omitempty"`               // Serve pprof (heap/goroutine profiles); nil = enabled, takes effect on restart
+	ProfilerAddress                string                      `yaml:"profiler_address,omitempty"`              // pprof listen address; "" = localhost:6060, port 0 = any free port
+	RegisterURLScheme              *bool                       `yaml:"register_url_scheme,omitempty"`           // Register mgt:// links (mgt://chart/SPX?date=...) with the OS at startup; nil = enabled
+	EndOfDayReportEnabled          bool                        `yaml:"end_of_day_report_enabled"`               // Write a collection report after market close
+	EndOfDayReportWebhookURL       string                      `yaml:"end_of_day_report_webhook_url,omitempty"` // Optional URL the report is POSTed to as JSON
 }
 
 // SettingsManager manages loading and saving settings
 type SettingsManager struct {
 	configFile string
+	profile    string // Configuration profile the file belongs to ("" = explicit config file)
 	settings   *Settings
+	validation SettingsValidation // Issues found by the last LoadSettings
 	mu         sync.RWMutex
 }
 
@@ -89,11 +138,18 @@ func GetConfigPath() (string, error) {
 }
 
 // NewSettingsManager creates a new settings manager
-// If configFile is empty, uses default user config directory
+// If configFile is empty, uses the active profile's file in the user config directory
 func NewSettingsManager(configFile string) *SettingsManager {
+	profile := ""
 	if configFile == "" {
-		// Use default config path
-		if path, err := GetConfigPath(); err == nil {
+		profile = GetActiveProfile()
+		path, err := ProfilePath(profile)
+		if err != nil && profile != DefaultProfileName {
+			log.Printf("WARNING: Profile %q unavailable (%v), using the default profile", profile, err)
+			profile = DefaultProfileName
+			path, err = ProfilePath(profile)
+		}
+		if err == nil {
 			configFile = path
 		} else {
 			// Fallback to current directory
@@ -103,20 +159,27 @@ func NewSettingsManager(configFile string) *SettingsManager {
 
 	return &SettingsManager{
 		configFile: configFile,
+		profile:    profile,
 		settings:   getDefaultSettings(),
 	}
 }
 
 // LoadSettings loads settings from file
 // API key is loaded from environment variable GEXBOT_API_KEY first, then from config file
+// The file is validated as it loads; mistyped values fall back to their defaults and are reported by Validation
 func (sm *SettingsManager) LoadSettings() (*Settings, error) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
+	sm.validation = SettingsValidation{ConfigFile: sm.configFile, Issues: make([]SettingsIssue, 0)}
+
 	// Check if config file exists
 	if _, err := os.Stat(sm.configFile); os.IsNotExist(err) {
-		// File doesn't exist - check for old JSON file and migrate
+		// File doesn't exist - check for old JSON file and migrate (into the default profile only)
 		oldSettingsPath := filepath.Join(".", OldSettingsFileName)
+		if sm.profile != "" && sm.profile != DefaultProfileName {
+			oldSettingsPath = ""
+		}
 		if migrated, err := MigrateOldSettings(oldSettingsPath, sm.configFile); err != nil {
 			return nil, fmt.Errorf("migration failed: %w", err)
 		} else if !migrated {
@@ -135,11 +198,19 @@ func (sm *SettingsManager) LoadSettings() (*Settings, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	// Parse YAML
+	// Parse YAML (type errors still decode the rest of the file; the validation pass reports them)
 	var settings Settings
-	if err := yaml.Unmarshal(data, &settings); err != nil {
+	if err := yaml.Unmarshal(data, &settings); err != nil && !isTypeError(err) {
+		sm.validation.Issues = append(sm.validation.Issues, SettingsIssue{
+			Severity: "error",
+			Message:  fmt.Sprintf("config.yaml could not be parsed, defaults are used: %v", err),
+		})
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
+	sm.validation.Issues = validateSettingsYAML(data, &settings)
+	for _, issue := range sm.validation.Issues {
+		log.Printf("Settings %s: %s (line %d): %s", issue.Severity, issue.Path, issue.Line, issue.Message)
+	}
 
 	// Load API key from environment variable first, fallback to config file
 	apiKey := os.Getenv(APIKeyEnvVar)
@@ -165,6 +236,11 @@ func (sm *SettingsManager) LoadSettings() (*Settings, error) {
 		settings.TickerConfigs = make(map[string]TickerConfig)
 	}
 
+	// Renamed symbols are collected under their new name
+	if renamed := settings.ApplyTickerAliases(); len(renamed) > 0 {
+		log.Printf("Ticker aliases: collecting %v under their new names", renamed)
+	}
+
 	sm.settings = &settings
 	return sm.settings, nil
 }
@@ -271,6 +347,11 @@ func (sm *SettingsManager) SaveSettingsWithOptions(settings *Settings, saveAPIKe
 		}
 	}
 
+	// Keep the version being replaced so a bad change can be rolled back
+	if err := sm.recordHistory(data); err != nil {
+		log.Printf("WARNING: SaveSettingsWithOptions: Settings history not updated: %v", err)
+	}
+
 	// Write to file
 	if err := os.WriteFile(sm.configFile, data, 0644); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
@@ -292,6 +373,11 @@ func (sm *SettingsManager) GetConfigPath() string {
 	return sm.configFile
 }
 
+// GetProfile returns the configuration profile being used ("" when created with an explicit config file)
+func (sm *SettingsManager) GetProfile() string {
+	return sm.profile
+}
+
 // SetSettings updates the internal settings (thread-safe)
 // Note: This preserves the API key if the new settings don't have one
 func (sm *SettingsManager) SetSettings(settings *Settings) {
@@ -314,9 +400,9 @@ func (sm *SettingsManager) GetSettings() *Settings {
 	return sm.settings
 }
 
-// SaveWindowDimensions saves only window dimensions without full settings reload
-// This is a lightweight operation for use during window resize
-func (sm *SettingsManager) SaveWindowDimensions(width, height int) error {
+// SaveWindowDimensions saves only window dimensions and placements without full settings reload
+// This is a lightweight operation for use during window resize/move
+func (sm *SettingsManager) SaveWindowDimensions(width, height int, placements []WindowPlacement) error {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
@@ -324,6 +410,7 @@ func (sm *SettingsManager) SaveWindowDimensions(width, height int) error {
 	if sm.settings != nil {
 		sm.settings.WindowWidth = width
 		sm.settings.WindowHeight = height
+		sm.settings.WindowPlacements = placements
 	}
 
 	// Read existing file to preserve all other settings
@@ -341,6 +428,7 @@ func (sm *SettingsManager) SaveWindowDimensions(width, height int) error {
 	// Only update window dimensions
 	existingSettings.WindowWidth = width
 	existingSettings.WindowHeight = height
+	existingSettings.WindowPlacements = placements
 
 	// Write back
 	data, err := yaml.Marshal(&existingSettings)
@@ -356,6 +444,125 @@ func (sm *SettingsManager) SaveWindowDimensions(width, height int) error {
 	return nil
 }
 
+// Clone returns a deep copy of the settings (without copying the mutex)
+// Use this to modify a copy before passing it to SaveSettings
+func (s *Settings) Clone() (*Settings, error) {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal settings: %w", err)
+	}
+
+	var clone Settings
+	if err := yaml.Unmarshal(data, &clone); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+	if clone.TickerConfigs == nil {
+		clone.TickerConfigs = make(map[string]TickerConfig)
+	}
+	return &clone, nil
+}
+
+// GetMemoryBudgetMB returns the memory budget in MB (0 = no limit)
+func (s *Settings) GetMemoryBudgetMB() int {
+	if s.MemoryBudgetMB == 0 {
+		return DefaultMemoryBudgetMB
+	}
+	if s.MemoryBudgetMB < 0 {
+		return 0
+	}
+	return s.MemoryBudgetMB
+}
+
+// GetFetchFallbackMaxAgeSec returns how old a cached response may be to fill in for a failed fetch (0 = disabled)
+func (s *Settings) GetFetchFallbackMaxAgeSec() int {
+	if s.FetchFallbackMaxAgeSec == 0 {
+		return DefaultFetchFallbackMaxAgeSec
+	}
+	if s.FetchFallbackMaxAgeSec < 0 {
+		return 0
+	}
+	return s.FetchFallbackMaxAgeSec
+}
+
+// GetStartupStaggerSec returns the window initial fetches are spread over in seconds (0 = no staggering)
+func (s *Settings) GetStartupStaggerSec() float64 {
+	if s.StartupStaggerSec == 0 {
+		return DefaultStartupStaggerSec
+	}
+	if s.StartupStaggerSec < 0 {
+		return 0
+	}
+	if s.StartupStaggerSec > MaxStartupStaggerSec {
+		return MaxStartupStaggerSec
+	}
+	return s.StartupStaggerSec
+}
+
+// GetBatchCoalesceWindowMs returns how long a batch waits for other ticker timers to join it (0 = no coalescing)
+func (s *Settings) GetBatchCoalesceWindowMs() int {
+	ms := s.BatchCoalesceWindowMs
+	if ms == 0 {
+		ms = DefaultBatchCoalesceWindowMs
+	}
+	if ms < 0 {
+		ms = 0
+	}
+	if ms > MaxBatchCoalesceWindowMs {
+		ms = MaxBatchCoalesceWindowMs
+	}
+	return ms
+}
+
+// ProfilerEnabled reports whether the pprof server should be started (on unless disabled)
+func (s *Settings) ProfilerEnabled() bool {
+	return s.EnableProfiler == nil || *s.EnableProfiler
+}
+
+// QuotaSaverEnabled reports whether background tickers may be switched to the chart endpoints when the
+// API quota is projected to run out before market close (on unless disabled)
+func (s *Settings) QuotaSaverEnabled() bool {
+	return s.QuotaSaver == nil || *s.QuotaSaver
+}
+
+// URLSchemeEnabled reports whether the mgt:// URL scheme should be registered at startup (on unless disabled)
+func (s *Settings) URLSchemeEnabled() bool {
+	return s.RegisterURLScheme == nil || *s.RegisterURLScheme
+}
+
+// GetProfilerAddress returns the pprof listen address
+func (s *Settings) GetProfilerAddress() string {
+	if s.ProfilerAddress == "" {
+		return DefaultProfilerAddress
+	}
+	return s.ProfilerAddress
+}
+
+// ValidateProfilerAddress checks that profiler_address is a host:port pair
+func (s *Settings) ValidateProfilerAddress() error {
+	if s.ProfilerAddress == "" {
+		return nil
+	}
+	_, port, err := net.SplitHostPort(s.ProfilerAddress)
+	if err != nil {
+		return fmt.Errorf("profiler_address %q must be host:port (e.g. localhost:6060): %w", s.ProfilerAddress, err)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		return fmt.Errorf("profiler_address %q has an invalid port (use 0 for any free port)", s.ProfilerAddress)
+	}
+	return nil
+}
+
+// GetIdleAfterMinutes returns the idle threshold in minutes (0 = idle detection disabled)
+func (s *Settings) GetIdleAfterMinutes() int {
+	if s.IdleAfterMinutes == 0 {
+		return DefaultIdleAfterMinutes
+	}
+	if s.IdleAfterMinutes < 0 {
+		return 0
+	}
+	return s.IdleAfterMinutes
+}
+
 // GetDefaultSettings returns default settings (exported for use in app.go)
 func GetDefaultSettings() *Settings {
 	return getDefaultSettings()
@@ -460,5 +667,7 @@ func getDefaultSettings() *Settings {
 			"major_pos_oi":      "#3F51B5",
 			"major_neg_oi":      "#E91E63",
 		},
+		Theme:                 ThemeDark,
+		EndOfDayReportEnabled: true,
 	}
 }
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// TickerSettingChange is one changed field of a ticker config
+type TickerSettingChange struct {
+	Ticker string `json:"ticker"`
+	Field  string `json:"field"` // priority, refresh_rate_ms, active_windows, display
+	Old    string `json:"old"`
+	New    string `json:"new"`
+}
+
+// SettingsDiff describes what saving proposed settings would change (shown before SaveSettings, logged after)
+type SettingsDiff struct {
+	TickersEnabled   []string              `json:"tickers_enabled"`  // Collection switched on
+	TickersDisabled  []string              `json:"tickers_disabled"` // Collection switched off (or config removed)
+	TickerChanges    []TickerSettingChange `json:"ticker_changes"`   // Priority/interval/window/display changes
+	EndpointsAdded   []string              `json:"endpoints_added"`  // Filled in by the app (depends on the API layer)
+	EndpointsRemoved []string              `json:"endpoints_removed"`
+	ChangedSettings  []string              `json:"changed_settings"` // Other top-level settings (config.yaml keys)
+	Empty            bool                  `json:"empty"`
+}
+
+// diffIgnoredSettings are keys saved as a side effect of using the app, not by the settings dialog
+var diffIgnoredSettings = map[string]bool{
+	"ticker_configs": true, "window_width": true, "window_height": true, "window_placements": true, "api_key": true,
+}
+
+// DiffSettings compares current settings with proposed ones (the endpoint lists are left for the caller)
+func DiffSettings(current, proposed *Settings) SettingsDiff {
+	diff := SettingsDiff{
+		TickersEnabled:   make([]string, 0),
+		TickersDisabled:  make([]string, 0),
+		TickerChanges:    make([]TickerSettingChange, 0),
+		EndpointsAdded:   make([]string, 0),
+		EndpointsRemoved: make([]string, 0),
+		ChangedSettings:  make([]string, 0),
+	}
+
+	tickers := make(map[string]bool)
+	for ticker := range current.TickerConfigs {
+		tickers[ticker] = true
+	}
+	for ticker := range proposed.TickerConfigs {
+		tickers[ticker] = true
+	}
+	names := make([]string, 0, len(tickers))
+	for ticker := range tickers {
+		names = append(names, ticker)
+	}
+	sort.Strings(names)
+
+	for _, ticker := range names {
+		before, hadBefore := current.TickerConfigs[ticker]
+		after, hasAfter := proposed.TickerConfigs[ticker]
+		if before.CollectionEnabled != after.CollectionEnabled {
+			if after.CollectionEnabled {
+				diff.TickersEnabled = append(diff.TickersEnabled, ticker)
+			} else {
+				diff.TickersDisabled = append(diff.TickersDisabled, ticker)
+			}
+		}
+		if !hadBefore || !hasAfter {
+			continue // Added or removed: enabled/disabled says it all
+		}
+		change := func(field, old, new string) {
+			if old != new {
+				diff.TickerChanges = append(diff.TickerChanges, TickerSettingChange{Ticker: ticker, Field: field, Old: old, New: new})
+			}
+		}
+		change("priority", before.Priority, after.Priority)
+		change("refresh_rate_ms", formatRefreshRate(before.RefreshRateMs), formatRefreshRate(after.RefreshRateMs))
+		change("active_windows", strings.Join(before.ActiveWindows, ", "), strings.Join(after.ActiveWindows, ", "))
+		change("display", fmt.Sprint(before.Display), fmt.Sprint(after.Display))
+	}
+
+	if proposed.APITKey != "" && proposed.APITKey != current.APITKey {
+		diff.ChangedSettings = append(diff.ChangedSettings, "api_key")
+	}
+	currentValue, proposedValue := reflect.ValueOf(current).Elem(), reflect.ValueOf(proposed).Elem()
+	for key, field := range yamlFields(currentValue.Type()) {
+		if diffIgnoredSettings[key] {
+			continue
+		}
+		if !sameSettingValue(currentValue.FieldByIndex(field.Index).Interface(), proposedValue.FieldByIndex(field.Index).Interface()) {
+			diff.ChangedSettings = append(diff.ChangedSettings, key)
+		}
+	}
+	sort.Strings(diff.ChangedSettings)
+
+	diff.Empty = diff.IsEmpty()
+	return diff
+}
+
+// SetEndpoints records the endpoints collected before and after the change
+func (d *SettingsDiff) SetEndpoints(before, after []string) {
+	d.EndpointsAdded, d.EndpointsRemoved = listDifference(after, before), listDifference(before, after)
+	d.Empty = d.IsEmpty()
+}
+
+// IsEmpty reports whether nothing would change
+func (d SettingsDiff) IsEmpty() bool {
+	return len(d.TickersEnabled) == 0 && len(d.TickersDisabled) == 0 && len(d.TickerChanges) == 0 &&
+		len(d.EndpointsAdded) == 0 && len(d.EndpointsRemoved) == 0 && len(d.ChangedSettings) == 0
+}
+
+// Summary describes the diff in one line for the log
+func (d SettingsDiff) Summary() string {
+	if d.IsEmpty() {
+		return "no changes"
+	}
+	parts := make([]string, 0, 6)
+	if len(d.TickersEnabled) > 0 {
+		parts = append(parts, "enabled "+strings.Join(d.TickersEnabled, ","))
+	}
+	if len(d.TickersDisabled) > 0 {
+		parts = append(parts, "disabled "+strings.Join(d.TickersDisabled, ","))
+	}
+	for _, change := range d.TickerChanges {
+		parts = append(parts, fmt.Sprintf("%s %s %q -> %q", change.Ticker, change.Field, change.Old, change.New))
+	}
+	if len(d.EndpointsAdded) > 0 {
+		parts = append(parts, "endpoints added "+strings.Join(d.EndpointsAdded, ","))
+	}
+	if len(d.EndpointsRemoved) > 0 {
+		parts = append(parts, "endpoints removed "+strings.Join(d.EndpointsRemoved, ","))
+	}
+	if len(d.ChangedSettings) > 0 {
+		parts = append(parts, "changed "+strings.Join(d.ChangedSettings, ","))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// sameSettingValue compares two values of a setting by their JSON form, so a value decoded from the frontend's
+// JSON (float64 numbers) equals the one loaded from YAML (ints), and nil equals empty
+func sameSettingValue(a, b interface{}) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return emptyJSON(aJSON) && emptyJSON(bJSON) || bytes.Equal(aJSON, bJSON)
+}
+
+func emptyJSON(value []byte) bool {
+	switch string(value) {
+	case "null", "[]", "{}", `""`, "0", "false":
+		return true
+	}
+	return false
+}
+
+func formatRefreshRate(rate *int) string {
+	if rate == nil || *rate == 0 {
+		return "auto"
+	}
+	return fmt.Sprintf("%d", *rate)
+}
+
+// listDifference returns the entries of a missing from b, in a's order
+func listDifference(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, entry := range b {
+		inB[entry] = true
+	}
+	result := make([]string, 0)
+	for _, entry := range a {
+		if !inB[entry] {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// settingsVersionLayout names history files; it sorts by time and is the version ID passed to RollbackSettings
+const settingsVersionLayout = "20060102-150405.000"
+
+// SettingsVersion is a previous version of the settings file, saved before it was overwritten
+type SettingsVersion struct {
+	Version string    `json:"version"`  // e.g. "20261016-093512.250"
+	SavedAt time.Time `json:"saved_at"` // When it was replaced
+	Size    int64     `json:"size"`
+}
+
+// historyDir returns the directory holding previous versions of the settings file (one per profile)
+func (sm *SettingsManager) historyDir() string {
+	name := strings.TrimSuffix(filepath.Base(sm.configFile), filepath.Ext(sm.configFile))
+	return filepath.Join(filepath.Dir(sm.configFile), SettingsHistoryDirName, name)
+}
+
+// recordHistory copies the settings file about to be replaced by data into the history and removes the oldest
+// versions beyond SettingsHistoryMax; a save that changes nothing isn't recorded. Called with sm.mu held
+func (sm *SettingsManager) recordHistory(data []byte) error {
+	previous, err := os.ReadFile(sm.configFile)
+	if os.IsNotExist(err) || (err == nil && bytes.Equal(previous, data)) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read settings file: %w", err)
+	}
+
+	dir := sm.historyDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create settings history directory: %w", err)
+	}
+	version := time.Now().Format(settingsVersionLayout)
+	if err := os.WriteFile(filepath.Join(dir, version+".yaml"), previous, 0644); err != nil {
+		return fmt.Errorf("failed to write settings version %s: %w", version, err)
+	}
+
+	versions, err := sm.listHistory()
+	if err != nil {
+		return err
+	}
+	for _, old := range versions[min(len(versions), SettingsHistoryMax):] {
+		os.Remove(filepath.Join(dir, old.Version+".yaml"))
+	}
+	return nil
+}
+
+// ListSettingsHistory returns the saved previous versions of the settings file, newest first
+func (sm *SettingsManager) ListSettingsHistory() ([]SettingsVersion, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.listHistory()
+}
+
+func (sm *SettingsManager) listHistory() ([]SettingsVersion, error) {
+	entries, err := os.ReadDir(sm.historyDir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read settings history: %w", err)
+	}
+	versions := make([]SettingsVersion, 0, len(entries))
+	for _, entry := range entries {
+		version := strings.TrimSuffix(entry.Name(), ".yaml")
+		if entry.IsDir() || version == entry.Name() {
+			continue
+		}
+		savedAt, err := time.ParseInLocation(settingsVersionLayout, version, time.Local)
+		if err != nil {
+			continue // Not a history file
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		versions = append(versions, SettingsVersion{Version: version, SavedAt: savedAt, Size: info.Size()})
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version > versions[j].Version })
+	return versions, nil
+}
+
+// LoadSettingsVersion reads a previous version of the settings file (missing keys take their defaults)
+// Nothing is applied; the app saves the result like any other settings change
+func (sm *SettingsManager) LoadSettingsVersion(version string) (*Settings, error) {
+	if _, err := time.Parse(settingsVersionLayout, version); err != nil {
+		return nil, fmt.Errorf("invalid settings version %q", version)
+	}
+	sm.mu.RLock()
+	path := filepath.Join(sm.historyDir(), version+".yaml")
+	sm.mu.RUnlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("settings version %s does not exist", version)
+		}
+		return nil, fmt.Errorf("failed to read settings version %s: %w", version, err)
+	}
+	settings := getDefaultSettings()
+	if err := yaml.Unmarshal(data, settings); err != nil {
+		return nil, fmt.Errorf("failed to parse settings version %s: %w", version, err)
+	}
+	return settings, nil
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ChartSnapshotSettings schedules automatic chart images at fixed market times (a visual trading journal)
+// Images are rendered headlessly, so no chart window has to be open
+type ChartSnapshotSettings struct {
+	Enabled   bool     `yaml:"enabled" json:"Enabled"`
+	Times     []string `yaml:"times" json:"Times"`                   // Market-time (ET) "HH:MM" capture times, e.g. ["09:45", "12:00", "15:55"]
+	Tickers   []string `yaml:"tickers,omitempty" json:"Tickers"`     // Tickers to capture (empty = tickers with an open chart window)
+	Format    string   `yaml:"format,omitempty" json:"Format"`       // "png" (default) or "svg"
+	Width     int      `yaml:"width,omitempty" json:"Width"`         // Pixels (0 = export default)
+	Height    int      `yaml:"height,omitempty" json:"Height"`       // Pixels (0 = export default)
+	Watermark string   `yaml:"watermark,omitempty" json:"Watermark"` // Optional text stamped on each image
+	Directory string   `yaml:"directory,omitempty" json:"Directory"` // Base folder ("" = snapshots folder in the config dir); one subfolder per market date
+}
+
+// ParseSnapshotTime parses a "HH:MM" capture time into minutes after midnight
+func ParseSnapshotTime(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid snapshot time %q (expected HH:MM, e.g. 09:45)", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Validate checks the capture times and format
+func (s ChartSnapshotSettings) Validate() error {
+	if !s.Enabled {
+		return nil
+	}
+	if len(s.Times) == 0 {
+		return fmt.Errorf("chart snapshots need at least one capture time")
+	}
+	for _, value := range s.Times {
+		if _, err := ParseSnapshotTime(value); err != nil {
+			return err
+		}
+	}
+	if format := strings.ToLower(s.Format); format != "" && format != "png" && format != "svg" {
+		return fmt.Errorf("unsupported snapshot format %q (expected png or svg)", s.Format)
+	}
+	if s.Width < 0 || s.Height < 0 {
+		return fmt.Errorf("snapshot width/height cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SpotCheckSettings configures a secondary spot price source (e.g. the user's quote API) that GEXBot's spot is
+// compared with; rows where they diverge beyond the threshold are flagged, the other value is recorded and the UI warns
+type SpotCheckSettings struct {
+	Enabled      bool              `yaml:"enabled" json:"Enabled"`
+	URLTemplate  string            `yaml:"url_template,omitempty" json:"URLTemplate"`   // Quote URL with {symbol}, e.g. "https://quotes.example.com/v1/last/{symbol}?token=..."
+	PriceField   string            `yaml:"price_field,omitempty" json:"PriceField"`     // Dotted path to the price in the JSON response, e.g. "quote.last" (default "price")
+	Headers      map[string]string `yaml:"headers,omitempty" json:"Headers"`            // Extra request headers (e.g. an API key)
+	Symbols      map[string]string `yaml:"symbols,omitempty" json:"Symbols"`            // Ticker -> quote symbol where they differ, e.g. SPX -> "^GSPC"
+	Tickers      []string          `yaml:"tickers,omitempty" json:"Tickers"`            // Tickers to check (empty = every collected ticker)
+	ThresholdPct float64           `yaml:"threshold_pct,omitempty" json:"ThresholdPct"` // Divergence flagged, in percent of the quote (0 = DefaultSpotCheckThresholdPct)
+	IntervalSec  int               `yaml:"interval_sec,omitempty" json:"IntervalSec"`   // Quotes are requested at most this often per ticker (0 = DefaultSpotCheckIntervalSec)
+}
+
+// Validate checks the quote URL and limits
+func (s SpotCheckSettings) Validate() error {
+	if !s.Enabled {
+		return nil
+	}
+	if !strings.HasPrefix(s.URLTemplate, "http://") && !strings.HasPrefix(s.URLTemplate, "https://") {
+		return fmt.Errorf("spot check url_template must start with http:// or https:// (got %q)", s.URLTemplate)
+	}
+	if !strings.Contains(s.URLTemplate, "{symbol}") {
+		return fmt.Errorf("spot check url_template must contain {symbol}")
+	}
+	if s.ThresholdPct < 0 || s.IntervalSec < 0 {
+		return fmt.Errorf("spot check threshold_pct/interval_sec cannot be negative")
+	}
+	for _, part := range strings.Split(s.GetPriceField(), ".") {
+		if part == "" {
+			return fmt.Errorf("invalid spot check price_field %q", s.PriceField)
+		}
+	}
+	return nil
+}
+
+// GetPriceField returns the JSON path of the quote price (default "price")
+func (s SpotCheckSettings) GetPriceField() string {
+	if s.PriceField == "" {
+		return "price"
+	}
+	return s.PriceField
+}
+
+// GetThresholdPct returns the divergence threshold in percent
+func (s SpotCheckSettings) GetThresholdPct() float64 {
+	if s.ThresholdPct <= 0 {
+		return DefaultSpotCheckThresholdPct
+	}
+	return s.ThresholdPct
+}
+
+// GetIntervalSec returns the minimum time between quote requests per ticker
+func (s SpotCheckSettings) GetIntervalSec() int {
+	if s.IntervalSec <= 0 {
+		return DefaultSpotCheckIntervalSec
+	}
+	return s.IntervalSec
+}
+
+// Symbol returns the quote symbol of a ticker
+func (s SpotCheckSettings) Symbol(ticker string) string {
+	if symbol, ok := s.Symbols[ticker]; ok && symbol != "" {
+		return symbol
+	}
+	return ticker
+}
+
+// Checks reports whether a ticker's spot is cross-checked
+func (s SpotCheckSettings) Checks(ticker string) bool {
+	if !s.Enabled {
+		return false
+	}
+	if len(s.Tickers) == 0 {
+		return true
+	}
+	for _, t := range s.Tickers {
+		if t == ticker {
+			return true
+		}
+	}
+	return false
+}
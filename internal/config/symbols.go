@@ -0,0 +1,77 @@
+package config
+
+import "strings"
+
+// Asset classes used for symbol classification
+const (
+	AssetClassFuture = "future" // Futures proxy (e.g. ES_SPX)
+	AssetClassIndex  = "index"  // Cash index (e.g. SPX)
+	AssetClassETF    = "etf"    // Exchange-traded fund (e.g. SPY)
+	AssetClassStock  = "stock"  // Single stock
+)
+
+// SymbolInfo is metadata about a ticker used by filtering and chart formatting
+type SymbolInfo struct {
+	Symbol        string  `yaml:"symbol" json:"Symbol"`
+	AssetClass    string  `yaml:"asset_class" json:"AssetClass"`          // future, index, etf, stock
+	Underlying    string  `yaml:"underlying,omitempty" json:"Underlying"` // Index a futures proxy tracks (ES_SPX -> SPX)
+	TickSize      float64 `yaml:"tick_size" json:"TickSize"`              // Minimum price increment
+	PriceDecimals int     `yaml:"price_decimals" json:"PriceDecimals"`    // Decimals shown on chart axes/labels
+}
+
+// IsFuture reports whether the symbol is a futures proxy
+func (si SymbolInfo) IsFuture() bool {
+	return si.AssetClass == AssetClassFuture
+}
+
+// builtInSymbols is the registry of known symbols (matches the frontend ticker tiers)
+var builtInSymbols = map[string]SymbolInfo{
+	"ES_SPX": {AssetClass: AssetClassFuture, Underlying: "SPX", TickSize: 0.25, PriceDecimals: 2},
+	"NQ_NDX": {AssetClass: AssetClassFuture, Underlying: "NDX", TickSize: 0.25, PriceDecimals: 2},
+	"SPX":    {AssetClass: AssetClassIndex, TickSize: 0.01, PriceDecimals: 2},
+	"NDX":    {AssetClass: AssetClassIndex, TickSize: 0.01, PriceDecimals: 2},
+	"RUT":    {AssetClass: AssetClassIndex, TickSize: 0.01, PriceDecimals: 2},
+	"VIX":    {AssetClass: AssetClassIndex, TickSize: 0.01, PriceDecimals: 2},
+	"SPY":    {AssetClass: AssetClassETF, TickSize: 0.01, PriceDecimals: 2},
+	"QQQ":    {AssetClass: AssetClassETF, TickSize: 0.01, PriceDecimals: 2},
+	"IWM":    {AssetClass: AssetClassETF, TickSize: 0.01, PriceDecimals: 2},
+	"GLD":    {AssetClass: AssetClassETF, TickSize: 0.01, PriceDecimals: 2},
+	"SLV":    {AssetClass: AssetClassETF, TickSize: 0.01, PriceDecimals: 2},
+	"TLT":    {AssetClass: AssetClassETF, TickSize: 0.01, PriceDecimals: 2},
+	"HYG":    {AssetClass: AssetClassETF, TickSize: 0.01, PriceDecimals: 2},
+	"USO":    {AssetClass: AssetClassETF, TickSize: 0.01, PriceDecimals: 2},
+	"IBIT":   {AssetClass: AssetClassETF, TickSize: 0.01, PriceDecimals: 2},
+	"TQQQ":   {AssetClass: AssetClassETF, TickSize: 0.01, PriceDecimals: 2},
+	"UVXY":   {AssetClass: AssetClassETF, TickSize: 0.01, PriceDecimals: 2},
+}
+
+// LookupSymbol returns metadata for a ticker
+// Unknown symbols are classified by convention: "<ROOT>_<INDEX>" is a futures proxy, anything else a stock
+func LookupSymbol(ticker string, overrides map[string]SymbolInfo) SymbolInfo {
+	if info, ok := overrides[ticker]; ok {
+		info.Symbol = ticker
+		return info
+	}
+	if info, ok := builtInSymbols[ticker]; ok {
+		info.Symbol = ticker
+		return info
+	}
+	if parts := strings.SplitN(ticker, "_", 2); len(parts) == 2 && parts[1] != "" {
+		return SymbolInfo{Symbol: ticker, AssetClass: AssetClassFuture, Underlying: parts[1], TickSize: 0.25, PriceDecimals: 2}
+	}
+	return SymbolInfo{Symbol: ticker, AssetClass: AssetClassStock, TickSize: 0.01, PriceDecimals: 2}
+}
+
+// GetSymbolInfo returns metadata for a ticker, applying user overrides from settings
+func (s *Settings) GetSymbolInfo(ticker string) SymbolInfo {
+	return LookupSymbol(ticker, s.SymbolOverrides)
+}
+
+// PriceFilterThresholdPercent returns the price filter threshold for a ticker
+// Futures use the futures threshold; every other asset class uses the stocks threshold
+func (s *Settings) PriceFilterThresholdPercent(ticker string) float64 {
+	if s.GetSymbolInfo(ticker).IsFuture() {
+		return s.PriceFilterThresholdFuturesPercent
+	}
+	return s.PriceFilterThresholdStocksPercent
+}
@@ -0,0 +1,23 @@
+package config
+
+// Sync destination types
+const (
+	SyncTypeFolder = "folder" // Local folder or mounted network share
+	SyncTypeS3     = "s3"     // S3-compatible bucket
+	SyncTypeSFTP   = "sftp"   // Not supported yet (mount as a network share instead)
+)
+
+// SyncSettings configures cross-machine sync of completed daily databases
+type SyncSettings struct {
+	Enabled         bool   `yaml:"enabled" json:"Enabled"`
+	Type            string `yaml:"type" json:"Type"`                   // "folder" or "s3"
+	Path            string `yaml:"path,omitempty" json:"Path"`         // folder: destination directory
+	Endpoint        string `yaml:"endpoint,omitempty" json:"Endpoint"` // s3: e.g. https://s3.amazonaws.com or MinIO URL
+	Bucket          string `yaml:"bucket,omitempty" json:"Bucket"`     // s3: bucket name
+	Region          string `yaml:"region,omitempty" json:"Region"`     // s3: signing region (default us-east-1)
+	Prefix          string `yaml:"prefix,omitempty" json:"Prefix"`     // s3: key prefix inside the bucket
+	AccessKeyID     string `yaml:"access_key_id,omitempty" json:"AccessKeyID"`
+	SecretAccessKey string `yaml:"secret_access_key,omitempty" json:"SecretAccessKey"`
+	PullOnStartup   bool   `yaml:"pull_on_startup" json:"PullOnStartup"`   // Download days missing locally at startup
+	PushAfterClose  bool   `yaml:"push_after_close" json:"PushAfterClose"` // Upload the day's databases after market close
+}
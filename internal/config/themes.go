@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// Built-in theme names
+const (
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+)
+
+// Theme is a named set of chart colors (UI colors + one color per data series)
+type Theme struct {
+	Name         string            `json:"Name"`
+	Background   string            `json:"Background"`
+	Grid         string            `json:"Grid"`
+	Text         string            `json:"Text"`
+	Crosshair    string            `json:"Crosshair"`
+	SeriesColors map[string]string `json:"SeriesColors"` // Series name -> "#RRGGBB"
+}
+
+// builtInThemes returns the built-in themes keyed by name (fresh maps each call)
+func builtInThemes() map[string]Theme {
+	return map[string]Theme{
+		ThemeDark: {
+			Name:       ThemeDark,
+			Background: "#1E1E1E",
+			Grid:       "#2B2B2B",
+			Text:       "#D1D4DC",
+			Crosshair:  "#758696",
+			SeriesColors: map[string]string{
+				"spot":              "#4CAF50",
+				"zero_gamma":        "#FF9800",
+				"major_pos_vol":     "#2196F3",
+				"major_neg_vol":     "#F44336",
+				"major_long_gamma":  "#9C27B0",
+				"major_short_gamma": "#00BCD4",
+				"major_positive":    "#8BC34A",
+				"major_negative":    "#FF5722",
+				"major_pos_oi":      "#3F51B5",
+				"major_neg_oi":      "#E91E63",
+			},
+		},
+		ThemeLight: {
+			Name:       ThemeLight,
+			Background: "#FFFFFF",
+			Grid:       "#E6E6E6",
+			Text:       "#131722",
+			Crosshair:  "#9598A1",
+			SeriesColors: map[string]string{
+				"spot":              "#2E7D32",
+				"zero_gamma":        "#E65100",
+				"major_pos_vol":     "#1565C0",
+				"major_neg_vol":     "#C62828",
+				"major_long_gamma":  "#6A1B9A",
+				"major_short_gamma": "#00838F",
+				"major_positive":    "#558B2F",
+				"major_negative":    "#D84315",
+				"major_pos_oi":      "#283593",
+				"major_neg_oi":      "#AD1457",
+			},
+		},
+		ThemeHighContrast: {
+			Name:       ThemeHighContrast,
+			Background: "#000000",
+			Grid:       "#404040",
+			Text:       "#FFFFFF",
+			Crosshair:  "#FFFFFF",
+			SeriesColors: map[string]string{
+				"spot":              "#00FF00",
+				"zero_gamma":        "#FFFF00",
+				"major_pos_vol":     "#00BFFF",
+				"major_neg_vol":     "#FF0000",
+				"major_long_gamma":  "#FF00FF",
+				"major_short_gamma": "#00FFFF",
+				"major_positive":    "#7FFF00",
+				"major_negative":    "#FF8000",
+				"major_pos_oi":      "#8080FF",
+				"major_neg_oi":      "#FF69B4",
+			},
+		},
+	}
+}
+
+// ThemeNames returns the names of the built-in themes
+func ThemeNames() []string {
+	themes := builtInThemes()
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// ValidateThemeOverrides checks that every override is a "#RRGGBB" color
+func ValidateThemeOverrides(overrides map[string]string) error {
+	for series, color := range overrides {
+		if !hexColorPattern.MatchString(color) {
+			return fmt.Errorf("invalid color for %s: %q (expected #RRGGBB)", series, color)
+		}
+	}
+	return nil
+}
+
+// ResolveTheme returns a built-in theme with per-series overrides applied
+// Unknown names fall back to the dark theme
+func ResolveTheme(name string, overrides map[string]string) Theme {
+	themes := builtInThemes()
+	theme, ok := themes[name]
+	if !ok {
+		theme = themes[ThemeDark]
+	}
+	for series, color := range overrides {
+		theme.SeriesColors[series] = color
+	}
+	return theme
+}
+
+// GetTheme returns the active theme with the user's series overrides applied
+func (s *Settings) GetTheme() Theme {
+	return ResolveTheme(s.Theme, s.ThemeSeriesOverrides)
+}
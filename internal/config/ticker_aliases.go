@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CanonicalTicker returns the current name of a ticker, following ticker_aliases (old name -> new name)
+// Names without an alias are returned unchanged
+func (s *Settings) CanonicalTicker(ticker string) string {
+	for i := 0; i < len(s.TickerAliases); i++ {
+		next, ok := s.TickerAliases[ticker]
+		if !ok || next == ticker {
+			break
+		}
+		ticker = next
+	}
+	return ticker
+}
+
+// TickerFileNames returns the names a ticker's databases may be stored under: the canonical name first,
+// then every older name that resolves to it (sorted)
+func (s *Settings) TickerFileNames(ticker string) []string {
+	canonical := s.CanonicalTicker(ticker)
+	names := []string{canonical}
+	old := make([]string, 0)
+	for alias := range s.TickerAliases {
+		if alias != canonical && s.CanonicalTicker(alias) == canonical {
+			old = append(old, alias)
+		}
+	}
+	sort.Strings(old)
+	return append(names, old...)
+}
+
+// ValidateTickerAliases checks aliases are non-empty, don't point at themselves and don't loop
+func (s *Settings) ValidateTickerAliases() error {
+	for old, current := range s.TickerAliases {
+		if old == "" || current == "" {
+			return fmt.Errorf("ticker alias %q -> %q: both names are required", old, current)
+		}
+		if old == current {
+			return fmt.Errorf("ticker alias %s points at itself", old)
+		}
+		seen := map[string]bool{old: true}
+		for next, ok := current, true; ok; next, ok = s.TickerAliases[next] {
+			if seen[next] {
+				return fmt.Errorf("ticker aliases loop through %s", old)
+			}
+			seen[next] = true
+		}
+	}
+	return nil
+}
+
+// ApplyTickerAliases moves ticker configs, order and group entries under old names to their canonical names,
+// so collection continues under the new symbol; a config already present under the new name wins
+// Returns the old names that were renamed
+func (s *Settings) ApplyTickerAliases() []string {
+	if len(s.TickerAliases) == 0 {
+		return nil
+	}
+	renamed := make([]string, 0)
+	for ticker, tickerConfig := range s.TickerConfigs {
+		canonical := s.CanonicalTicker(ticker)
+		if canonical == ticker {
+			continue
+		}
+		if _, exists := s.TickerConfigs[canonical]; !exists {
+			s.TickerConfigs[canonical] = tickerConfig
+		}
+		delete(s.TickerConfigs, ticker)
+		renamed = append(renamed, ticker)
+	}
+	s.TickerOrder = s.canonicalTickerList(s.TickerOrder)
+	for i := range s.TickerGroups {
+		s.TickerGroups[i].Tickers = s.canonicalTickerList(s.TickerGroups[i].Tickers)
+	}
+	sort.Strings(renamed)
+	return renamed
+}
+
+// canonicalTickerList renames a list's tickers, dropping names that become duplicates
+func (s *Settings) canonicalTickerList(tickers []string) []string {
+	if tickers == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(tickers))
+	result := make([]string, 0, len(tickers))
+	for _, ticker := range tickers {
+		canonical := s.CanonicalTicker(ticker)
+		if !seen[canonical] {
+			seen[canonical] = true
+			result = append(result, canonical)
+		}
+	}
+	return result
+}
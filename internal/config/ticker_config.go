@@ -1,11 +1,87 @@
 package config
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 // TickerConfig represents configuration for a single ticker
 type TickerConfig struct {
 	Display           bool   `yaml:"display" json:"Display"`
 	CollectionEnabled bool   `yaml:"collection_enabled" json:"CollectionEnabled"`
 	Priority          string `yaml:"priority" json:"Priority"` // "high", "medium", "low"
 	RefreshRateMs     *int   `yaml:"refresh_rate_ms" json:"RefreshRateMs"` // Optional override, 0 = use priority-based scheduling
+	ActiveWindows     []string `yaml:"active_windows,omitempty" json:"ActiveWindows"` // Market-time (ET) "HH:MM-HH:MM" windows to collect in, e.g. ["09:30-10:30", "15:00-16:00"] (empty = whole session)
+}
+
+// ParseTimeWindow parses an "HH:MM-HH:MM" window into start/end minutes after midnight (end exclusive)
+func ParseTimeWindow(value string) (int, int, error) {
+	parts := strings.Split(value, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid time window %q (expected HH:MM-HH:MM, e.g. 09:30-10:30)", value)
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time window %q (expected HH:MM-HH:MM, e.g. 09:30-10:30)", value)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(parts[1]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time window %q (expected HH:MM-HH:MM, e.g. 09:30-10:30)", value)
+	}
+	startMinute, endMinute := start.Hour()*60+start.Minute(), end.Hour()*60+end.Minute()
+	if endMinute <= startMinute {
+		return 0, 0, fmt.Errorf("time window %q ends before it starts", value)
+	}
+	return startMinute, endMinute, nil
+}
+
+// ValidateActiveWindows checks every ticker's active windows
+func ValidateActiveWindows(tickerConfigs map[string]TickerConfig) error {
+	for ticker, tickerConfig := range tickerConfigs {
+		for _, window := range tickerConfig.ActiveWindows {
+			if _, _, err := ParseTimeWindow(window); err != nil {
+				return fmt.Errorf("%s: %w", ticker, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ActiveAt reports whether collection is allowed at t (a market-time clock)
+// No windows means always active; malformed windows are ignored (rejected on save)
+func (tc TickerConfig) ActiveAt(t time.Time) bool {
+	if len(tc.ActiveWindows) == 0 {
+		return true
+	}
+	minute := t.Hour()*60 + t.Minute()
+	for _, window := range tc.ActiveWindows {
+		start, end, err := ParseTimeWindow(window)
+		if err == nil && minute >= start && minute < end {
+			return true
+		}
+	}
+	return false
+}
+
+// UntilActive returns how long after t the next window opens today (0 if active now, -1 if none is left today)
+func (tc TickerConfig) UntilActive(t time.Time) time.Duration {
+	if tc.ActiveAt(t) {
+		return 0
+	}
+	next := time.Duration(-1)
+	for _, window := range tc.ActiveWindows {
+		start, _, err := ParseTimeWindow(window)
+		if err != nil {
+			continue
+		}
+		// Wall-clock start (not midnight + minutes, which is an hour off on DST transition days)
+		wait := time.Date(t.Year(), t.Month(), t.Day(), start/60, start%60, 0, 0, t.Location()).Sub(t)
+		if wait > 0 && (next < 0 || wait < next) {
+			next = wait
+		}
+	}
+	return next
 }
 
 // GetEnabledTickers filters ticker configs to return only those with collection_enabled=true
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TickerGroup is a named, ordered set of tickers (e.g. "Indices", "Mag7")
+type TickerGroup struct {
+	Name    string   `yaml:"name" json:"Name"`
+	Tickers []string `yaml:"tickers" json:"Tickers"` // Display/open order within the group
+}
+
+// Validate checks the group has a name and no empty or duplicate tickers
+func (g TickerGroup) Validate() error {
+	if strings.TrimSpace(g.Name) == "" {
+		return fmt.Errorf("ticker group name is required")
+	}
+	seen := make(map[string]bool, len(g.Tickers))
+	for _, ticker := range g.Tickers {
+		if ticker == "" {
+			return fmt.Errorf("ticker group %q contains an empty ticker", g.Name)
+		}
+		if seen[ticker] {
+			return fmt.Errorf("ticker group %q contains %s more than once", g.Name, ticker)
+		}
+		seen[ticker] = true
+	}
+	return nil
+}
+
+// FindTickerGroup returns the index of a group by name (case-insensitive), or -1
+func (s *Settings) FindTickerGroup(name string) int {
+	for i, group := range s.TickerGroups {
+		if strings.EqualFold(group.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetGroupCollectionEnabled enables or disables collection for every ticker in a group
+// Tickers without a config yet get one with default priority
+func (s *Settings) SetGroupCollectionEnabled(group TickerGroup, enabled bool) {
+	if s.TickerConfigs == nil {
+		s.TickerConfigs = make(map[string]TickerConfig)
+	}
+	for _, ticker := range group.Tickers {
+		tickerConfig, exists := s.TickerConfigs[ticker]
+		if !exists {
+			tickerConfig = TickerConfig{Display: true, Priority: "medium"}
+		}
+		tickerConfig.CollectionEnabled = enabled
+		s.TickerConfigs[ticker] = tickerConfig
+	}
+}
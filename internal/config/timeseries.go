@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Time-series sink types
+const (
+	TimeSeriesSinkInflux    = "influx"    // InfluxDB line protocol over HTTP (v1 /write or v2 /api/v2/write)
+	TimeSeriesSinkTimescale = "timescale" // TimescaleDB (needs a PostgreSQL database/sql driver linked into the build)
+)
+
+// TimeSeriesSinkSettings configures mirroring of collected scalar fields to a time-series database
+// (e.g. for Grafana dashboards). SQLite stays the primary store; the mirror never blocks or fails a flush
+type TimeSeriesSinkSettings struct {
+	Enabled     bool     `yaml:"enabled" json:"Enabled"`
+	Type        string   `yaml:"type" json:"Type"`                         // "influx" or "timescale"
+	URL         string   `yaml:"url,omitempty" json:"URL"`                 // influx: server URL, e.g. http://localhost:8086
+	Database    string   `yaml:"database,omitempty" json:"Database"`       // influx: v1 database or v2 bucket
+	Org         string   `yaml:"org,omitempty" json:"Org"`                 // influx: v2 organization (empty = v1 /write API)
+	Token       string   `yaml:"token,omitempty" json:"Token"`             // influx: v2 API token, or "user:password" for v1
+	DSN         string   `yaml:"dsn,omitempty" json:"DSN"`                 // timescale: PostgreSQL connection string
+	Measurement string   `yaml:"measurement,omitempty" json:"Measurement"` // influx measurement / timescale table (default "ticker_data")
+	Tickers     []string `yaml:"tickers,omitempty" json:"Tickers"`         // Tickers to mirror (empty = all collected tickers)
+}
+
+// Validate checks that the selected sink type is fully configured
+func (t TimeSeriesSinkSettings) Validate() error {
+	if !t.Enabled {
+		return nil
+	}
+	switch t.Type {
+	case TimeSeriesSinkInflux:
+		if t.URL == "" || t.Database == "" {
+			return fmt.Errorf("InfluxDB sink needs a URL and a database/bucket")
+		}
+		if !strings.HasPrefix(t.URL, "http://") && !strings.HasPrefix(t.URL, "https://") {
+			return fmt.Errorf("InfluxDB URL must start with http:// or https:// (got %q)", t.URL)
+		}
+	case TimeSeriesSinkTimescale:
+		if t.DSN == "" {
+			return fmt.Errorf("TimescaleDB sink needs a connection string (dsn)")
+		}
+	default:
+		return fmt.Errorf("unknown time-series sink type: %q", t.Type)
+	}
+	for _, r := range t.Measurement {
+		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_') {
+			return fmt.Errorf("measurement/table name may only contain letters, digits and underscores (got %q)", t.Measurement)
+		}
+	}
+	return nil
+}
+
+// MeasurementName returns the measurement/table name, defaulting to "ticker_data"
+func (t TimeSeriesSinkSettings) MeasurementName() string {
+	if t.Measurement == "" {
+		return "ticker_data"
+	}
+	return t.Measurement
+}
+
+// MirrorsTicker reports whether a ticker's data is mirrored (all tickers when the list is empty)
+func (t TimeSeriesSinkSettings) MirrorsTicker(ticker string) bool {
+	if len(t.Tickers) == 0 {
+		return true
+	}
+	for _, mirrored := range t.Tickers {
+		if strings.EqualFold(mirrored, ticker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TracingSettings configures export of collection traces (scheduler wakeup -> fetch -> write -> flush)
+// Traces are always kept in memory for GetRecentTraces; this only adds an OpenTelemetry (OTLP/HTTP) export
+type TracingSettings struct {
+	Enabled     bool              `yaml:"enabled" json:"Enabled"`
+	Endpoint    string            `yaml:"endpoint,omitempty" json:"Endpoint"`        // OTLP/HTTP collector, e.g. http://localhost:4318 (/v1/traces is appended)
+	ServiceName string            `yaml:"service_name,omitempty" json:"ServiceName"` // service.name resource attribute (default "market-terminal")
+	Headers     map[string]string `yaml:"headers,omitempty" json:"Headers"`          // Extra request headers (e.g. an API key for a hosted collector)
+}
+
+// Validate checks that an enabled export has a usable endpoint
+func (t TracingSettings) Validate() error {
+	if !t.Enabled {
+		return nil
+	}
+	if t.Endpoint == "" {
+		return fmt.Errorf("trace export needs an OTLP endpoint")
+	}
+	if !strings.HasPrefix(t.Endpoint, "http://") && !strings.HasPrefix(t.Endpoint, "https://") {
+		return fmt.Errorf("OTLP endpoint must start with http:// or https:// (got %q)", t.Endpoint)
+	}
+	return nil
+}
+
+// TracesURL returns the OTLP/HTTP traces URL (the endpoint with /v1/traces appended unless already present)
+func (t TracingSettings) TracesURL() string {
+	endpoint := strings.TrimRight(t.Endpoint, "/")
+	if strings.HasSuffix(endpoint, "/v1/traces") {
+		return endpoint
+	}
+	return endpoint + "/v1/traces"
+}
+
+// ServiceNameOrDefault returns the service name, defaulting to "market-terminal"
+func (t TracingSettings) ServiceNameOrDefault() string {
+	if t.ServiceName == "" {
+		return "market-terminal"
+	}
+	return t.ServiceName
+}
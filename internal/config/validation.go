@@ -0,0 +1,259 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SettingsIssue is one problem found in config.yaml when it was loaded
+type SettingsIssue struct {
+	Severity string `json:"severity"`       // "error" (value unusable or rejected on save) or "warning" (loaded, but likely a mistake)
+	Path     string `json:"path"`           // Dotted key path, e.g. "ticker_configs.SPX.priority" ("" = the whole file)
+	Line     int    `json:"line,omitempty"` // Line in config.yaml (0 = not tied to a line)
+	Message  string `json:"message"`
+}
+
+// SettingsValidation is the result of the validation pass run by LoadSettings
+type SettingsValidation struct {
+	ConfigFile string          `json:"config_file"`
+	Issues     []SettingsIssue `json:"issues"`
+}
+
+// HasErrors reports whether any issue is an error
+func (v SettingsValidation) HasErrors() bool {
+	for _, issue := range v.Issues {
+		if issue.Severity == "error" {
+			return true
+		}
+	}
+	return false
+}
+
+// Validation returns the result of the last LoadSettings validation pass
+func (sm *SettingsManager) Validation() SettingsValidation {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	validation := sm.validation
+	validation.Issues = append([]SettingsIssue(nil), sm.validation.Issues...)
+	return validation
+}
+
+// settingsValidator walks the parsed YAML alongside the Settings struct
+type settingsValidator struct {
+	issues   []SettingsIssue
+	settings reflect.Value // Loaded settings (mistyped fields are reset to their defaults)
+	defaults reflect.Value
+}
+
+// validateSettingsYAML checks config.yaml against the Settings schema: unknown keys are warnings, values of
+// the wrong type are errors and the field falls back to its default (yaml.v3 leaves it zero otherwise)
+// Semantic checks (the same ones SaveSettings rejects) run on the loaded values
+func validateSettingsYAML(data []byte, settings *Settings) []SettingsIssue {
+	v := &settingsValidator{
+		issues:   make([]SettingsIssue, 0),
+		settings: reflect.ValueOf(settings).Elem(),
+		defaults: reflect.ValueOf(getDefaultSettings()).Elem(),
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return v.issues // Syntax errors are reported by LoadSettings
+	}
+	if len(root.Content) > 0 {
+		v.checkNode(root.Content[0], reflect.TypeOf(Settings{}), "", []int{})
+	}
+	v.checkSemantics(settings)
+	return v.issues
+}
+
+func (v *settingsValidator) add(severity, path string, line int, message string) {
+	v.issues = append(v.issues, SettingsIssue{Severity: severity, Path: path, Line: line, Message: message})
+}
+
+// checkNode validates node against type t; index is the field path from Settings while only structs
+// have been traversed (nil inside maps, lists and pointers, where a mistyped value can't be reset)
+func (v *settingsValidator) checkNode(node *yaml.Node, t reflect.Type, path string, index []int) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	if node.Tag == "!!null" {
+		return
+	}
+	if t.Kind() == reflect.Ptr {
+		v.checkNode(node, t.Elem(), path, nil)
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			v.typeMismatch(node, t, path, index)
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			field, ok := fields[key.Value]
+			if !ok {
+				v.add("warning", joinPath(path, key.Value), key.Line, fmt.Sprintf("unknown setting %q is ignored (typo?)", key.Value))
+				continue
+			}
+			var fieldIndex []int
+			if index != nil {
+				fieldIndex = append(append([]int{}, index...), field.Index...)
+			}
+			v.checkNode(value, field.Type, joinPath(path, key.Value), fieldIndex)
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			v.typeMismatch(node, t, path, index)
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v.checkNode(node.Content[i+1], t.Elem(), joinPath(path, node.Content[i].Value), nil)
+		}
+	case reflect.Slice, reflect.Array:
+		if node.Kind != yaml.SequenceNode {
+			v.typeMismatch(node, t, path, index)
+			return
+		}
+		for i, item := range node.Content {
+			v.checkNode(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), nil)
+		}
+	default:
+		if err := node.Decode(reflect.New(t).Interface()); err != nil {
+			v.typeMismatch(node, t, path, index)
+		}
+	}
+}
+
+// typeMismatch records a value of the wrong type and resets the field to its default
+func (v *settingsValidator) typeMismatch(node *yaml.Node, t reflect.Type, path string, index []int) {
+	got := node.Value
+	switch node.Kind {
+	case yaml.MappingNode:
+		got = "a section"
+	case yaml.SequenceNode:
+		got = "a list"
+	default:
+		got = fmt.Sprintf("%q", got)
+	}
+	message := fmt.Sprintf("expected %s, got %s", describeType(t), got)
+
+	if len(index) > 0 {
+		def := v.defaults.FieldByIndex(index)
+		v.settings.FieldByIndex(index).Set(def)
+		switch def.Kind() {
+		case reflect.Struct, reflect.Map, reflect.Slice, reflect.Ptr, reflect.Interface:
+			message += " - using the default"
+		default:
+			message += fmt.Sprintf(" - using the default (%v)", def.Interface())
+		}
+	} else {
+		message += " - value ignored"
+	}
+	v.add("error", path, node.Line, message)
+}
+
+// checkSemantics runs the settings validators SaveSettings applies, so a hand edit that would be
+// rejected on save is reported at load time
+func (v *settingsValidator) checkSemantics(settings *Settings) {
+	check := func(path string, err error) {
+		if err != nil {
+			v.add("error", path, 0, err.Error())
+		}
+	}
+	if settings.PollingIntervals != nil {
+		check("polling_intervals", settings.PollingIntervals.Validate())
+	}
+	if settings.WALCheckpoint != nil {
+		check("wal_checkpoint", settings.WALCheckpoint.Validate())
+	}
+	if settings.ConnectionPool != nil {
+		check("connection_pool", settings.ConnectionPool.Validate())
+	}
+	if settings.RequestPolicies != nil {
+		check("request_policies", settings.RequestPolicies.Validate())
+	}
+	check("custom_endpoints", ValidateCustomEndpoints(settings.CustomEndpoints, nil))
+	check("ticker_configs", ValidateActiveWindows(settings.TickerConfigs))
+	for i, group := range settings.TickerGroups {
+		check(fmt.Sprintf("ticker_groups[%d]", i), group.Validate())
+	}
+	for i, workspace := range settings.Workspaces {
+		check(fmt.Sprintf("workspaces[%d]", i), workspace.Validate())
+	}
+	check("theme_series_overrides", ValidateThemeOverrides(settings.ThemeSeriesOverrides))
+	check("chart_snapshots", settings.ChartSnapshots.Validate())
+	check("profiler_address", settings.ValidateProfilerAddress())
+	check("timeseries_sink", settings.TimeSeriesSink.Validate())
+	check("spot_check", settings.SpotCheck.Validate())
+	check("live_alerts", settings.LiveAlerts.Validate())
+	check("processors", settings.Processors.Validate())
+	check("scripts", settings.Scripts.Validate())
+	check("tracing", settings.Tracing.Validate())
+	check("hotkeys", settings.Hotkeys.Validate())
+	check("eco_mode", settings.ValidateEcoMode())
+	check("data_layout", settings.ValidateDataLayout())
+	check("batch_overlap_policy", settings.ValidateBatchOverlapPolicy())
+	check("ticker_aliases", settings.ValidateTickerAliases())
+}
+
+// yamlFields maps a struct's YAML keys to its fields (same naming rules as yaml.v3)
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // Unexported
+		}
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// describeType names the kind of value a field expects, for messages
+func describeType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a whole number"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "text"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	case reflect.Map, reflect.Struct:
+		return "a section (key: value pairs)"
+	}
+	return t.String()
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// isTypeError reports whether a yaml.Unmarshal error only concerns mistyped values (the rest decoded)
+func isTypeError(err error) bool {
+	var typeErr *yaml.TypeError
+	return errors.As(err, &typeErr)
+}
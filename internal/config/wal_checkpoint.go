@@ -0,0 +1,38 @@
+package config
+
+import "fmt"
+
+// WALCheckpointSettings controls how the writer checkpoints SQLite WAL files after flushes
+type WALCheckpointSettings struct {
+	Adaptive        bool `yaml:"adaptive" json:"Adaptive"`                 // false = TRUNCATE after every flush
+	ForceSizeMB     int  `yaml:"force_size_mb" json:"ForceSizeMB"`         // WAL size that forces a TRUNCATE checkpoint, even during market hours
+	IdleTruncateSec int  `yaml:"idle_truncate_sec" json:"IdleTruncateSec"` // Seconds without a flush before a file's WAL is truncated
+}
+
+// DefaultWALCheckpointSettings returns the built-in checkpoint settings
+func DefaultWALCheckpointSettings() WALCheckpointSettings {
+	return WALCheckpointSettings{
+		Adaptive:        true,
+		ForceSizeMB:     64,
+		IdleTruncateSec: 60,
+	}
+}
+
+// Validate checks that thresholds are positive
+func (w WALCheckpointSettings) Validate() error {
+	if w.ForceSizeMB < 1 {
+		return fmt.Errorf("WAL force checkpoint size must be at least 1 MB (got %d)", w.ForceSizeMB)
+	}
+	if w.IdleTruncateSec < 1 {
+		return fmt.Errorf("WAL idle truncate delay must be at least 1 second (got %d)", w.IdleTruncateSec)
+	}
+	return nil
+}
+
+// GetWALCheckpointSettings returns the configured checkpoint settings, or the defaults if unset
+func (s *Settings) GetWALCheckpointSettings() WALCheckpointSettings {
+	if s.WALCheckpoint == nil {
+		return DefaultWALCheckpointSettings()
+	}
+	return *s.WALCheckpoint
+}
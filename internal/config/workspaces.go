@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Workspace is a named chart layout (e.g. "open", "power hour"): a set of chart windows with their
+// geometry and per-chart options, reopened together by LoadWorkspace
+type Workspace struct {
+	Name   string        `yaml:"name" json:"Name"`
+	Charts []ChartLayout `yaml:"charts" json:"Charts"`
+}
+
+// ChartLayout is one chart window of a workspace
+type ChartLayout struct {
+	Ticker      string   `yaml:"ticker" json:"Ticker"`
+	Date        string   `yaml:"date,omitempty" json:"Date"` // "" = the current market date when the workspace is loaded
+	X           int      `yaml:"x" json:"X"`
+	Y           int      `yaml:"y" json:"Y"`
+	Width       int      `yaml:"width" json:"Width"`
+	Height      int      `yaml:"height" json:"Height"`
+	HiddenPlots []string `yaml:"hidden_plots,omitempty" json:"HiddenPlots"` // Plots hidden in this chart; nil = hidden_plots setting
+}
+
+// Validate checks the workspace has a name and each chart a ticker and a usable size
+func (w Workspace) Validate() error {
+	if strings.TrimSpace(w.Name) == "" {
+		return fmt.Errorf("workspace name is required")
+	}
+	seen := make(map[string]bool, len(w.Charts))
+	for _, chart := range w.Charts {
+		if chart.Ticker == "" {
+			return fmt.Errorf("workspace %q has a chart without a ticker", w.Name)
+		}
+		if seen[chart.Ticker] {
+			return fmt.Errorf("workspace %q has more than one %s chart", w.Name, chart.Ticker)
+		}
+		seen[chart.Ticker] = true
+		if chart.Width < ChartWindowMinWidth || chart.Height < ChartWindowMinHeight {
+			return fmt.Errorf("workspace %q: %s chart is smaller than %dx%d", w.Name, chart.Ticker, ChartWindowMinWidth, ChartWindowMinHeight)
+		}
+	}
+	return nil
+}
+
+// FindWorkspace returns the index of a workspace by name (case-insensitive), or -1
+func (s *Settings) FindWorkspace(name string) int {
+	for i, workspace := range s.Workspaces {
+		if strings.EqualFold(workspace.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
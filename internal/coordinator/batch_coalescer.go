@@ -0,0 +1,128 @@
+package coordinator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"market-terminal/internal/crash"
+)
+
+// BatchCoalescer merges ticker timers that fire within a short window into one batch, so the query planner can
+// dedupe shared endpoints and the fetches go out together instead of as N single-ticker batches
+// Tickers open in a chart aren't coalesced: they'd otherwise wait on every background ticker in the batch
+type BatchCoalescer struct {
+	mu         sync.Mutex
+	window     time.Duration // 0 = every ticker runs as its own batch
+	process    func(tickers []string)
+	isActive   func(ticker string) bool // Reports whether a ticker is open in a chart (nil = none are)
+	pending    *coalescedBatch          // Batch collecting tickers until its window ends (nil = none open)
+	status     BatchCoalesceStatus
+	debugPrint func(string, string)
+}
+
+// coalescedBatch is one batch being collected; done is closed once it has been processed
+type coalescedBatch struct {
+	tickers []string
+	seen    map[string]bool
+	done    chan struct{}
+}
+
+// BatchCoalesceStatus counts how ticker timers were merged into batches
+type BatchCoalesceStatus struct {
+	WindowMs     int64 `json:"window_ms"`
+	Submitted    int64 `json:"submitted"` // Ticker timers that fired
+	Batches      int64 `json:"batches"`   // Batches processed
+	Coalesced    int64 `json:"coalesced"` // Timers that joined a batch opened by another ticker
+	Active       int64 `json:"active"`    // Timers of charted tickers, run as their own batch
+	LargestBatch int   `json:"largest_batch"`
+}
+
+// NewBatchCoalescer creates a coalescer running process for each merged batch
+// isActive reports whether a ticker is open in a chart; those bypass the window
+func NewBatchCoalescer(window time.Duration, process func(tickers []string), isActive func(ticker string) bool, debugPrint func(string, string)) *BatchCoalescer {
+	return &BatchCoalescer{window: window, process: process, isActive: isActive, debugPrint: debugPrint}
+}
+
+// SetWindow sets how long a batch waits for other tickers after the first one fires (0 = no coalescing)
+func (c *BatchCoalescer) SetWindow(window time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.window = window
+}
+
+// Submit adds a ticker to the batch being collected (opening one if none is) and returns once that batch has been
+// processed, so the ticker's timer isn't rescheduled while its fetch is still running
+// A ticker open in a chart runs as its own batch right away instead of waiting on the merged one
+func (c *BatchCoalescer) Submit(ticker string) {
+	active := c.isActive != nil && c.isActive(ticker)
+
+	c.mu.Lock()
+	c.status.Submitted++
+	if active {
+		c.status.Active++
+	}
+	if c.window <= 0 || active {
+		c.status.Batches++
+		if c.status.LargestBatch < 1 {
+			c.status.LargestBatch = 1
+		}
+		c.mu.Unlock()
+		c.run([]string{ticker})
+		return
+	}
+
+	batch := c.pending
+	if batch == nil {
+		batch = &coalescedBatch{seen: make(map[string]bool), done: make(chan struct{})}
+		c.pending = batch
+		time.AfterFunc(c.window, func() { c.flush(batch) })
+	} else {
+		c.status.Coalesced++
+	}
+	if !batch.seen[ticker] {
+		batch.seen[ticker] = true
+		batch.tickers = append(batch.tickers, ticker)
+	}
+	c.mu.Unlock()
+	<-batch.done
+}
+
+// flush closes a batch's window and processes it
+func (c *BatchCoalescer) flush(batch *coalescedBatch) {
+	defer close(batch.done)
+	c.mu.Lock()
+	if c.pending == batch {
+		c.pending = nil
+	}
+	c.status.Batches++
+	if len(batch.tickers) > c.status.LargestBatch {
+		c.status.LargestBatch = len(batch.tickers)
+	}
+	c.mu.Unlock()
+
+	if len(batch.tickers) > 1 {
+		c.debugPrint(fmt.Sprintf("Coalesced %d ticker timers into one batch: %v", len(batch.tickers), batch.tickers), "coordinator")
+	}
+	c.run(batch.tickers)
+}
+
+// run processes a batch; a panic is reported instead of taking the timers waiting on it down with it
+func (c *BatchCoalescer) run(tickers []string) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.debugPrint(fmt.Sprintf("PANIC processing batch %v: %v", tickers, r), "error")
+			crash.Report("batch coalescer", r)
+		}
+	}()
+	c.process(tickers)
+}
+
+// Status returns the coalescing counters
+func (c *BatchCoalescer) Status() BatchCoalesceStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	status := c.status
+	status.WindowMs = c.window.Milliseconds()
+	return status
+}
@@ -0,0 +1,254 @@
+package coordinator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"market-terminal/internal/api"
+	"market-terminal/internal/config"
+	"market-terminal/internal/utils"
+)
+
+// Circuit breaker states
+const (
+	CircuitClosed   = "closed"    // Requests flow normally
+	CircuitOpen     = "open"      // Family is skipped until the cooldown expires
+	CircuitHalfOpen = "half_open" // One probe request decides whether to close or re-open
+)
+
+// CircuitStatus is the breaker state of one endpoint family
+type CircuitStatus struct {
+	Family              string  `json:"family"`
+	State               string  `json:"state"`
+	ConsecutiveFailures int     `json:"consecutive_failures"`
+	OpenedAt            float64 `json:"opened_at"`    // Unix seconds (0 if never opened)
+	RetryInSec          float64 `json:"retry_in_sec"` // Seconds until the next probe (open only)
+	LastError           string  `json:"last_error"`
+}
+
+// familyCircuit is the breaker for one endpoint family
+type familyCircuit struct {
+	state               string
+	consecutiveFailures int
+	probeSuccesses      int // Successful probes while half-open
+	openedAt            time.Time
+	probeStartedAt      time.Time // Zero when no probe is in flight
+	lastError           string
+}
+
+// CircuitBreaker stops planning requests for an endpoint family (classic, state, orderflow)
+// after repeated failures, so an outage on one API path doesn't burn requests every cycle
+type CircuitBreaker struct {
+	mu           sync.Mutex
+	families     map[string]*familyCircuit
+	threshold    int
+	cooldown     time.Duration
+	successReset int
+	clock        utils.Clock // Source of "now" for cooldowns (the coordinator's clock)
+	debugPrint   func(string, string)
+}
+
+// NewCircuitBreaker creates a breaker using the configured thresholds
+func NewCircuitBreaker(debugPrint func(string, string)) *CircuitBreaker {
+	return &CircuitBreaker{
+		families:     make(map[string]*familyCircuit),
+		threshold:    config.BatchTimeoutCircuitBreakerThreshold,
+		cooldown:     time.Duration(config.BatchTimeoutCircuitBreakerBackoffSec) * time.Second,
+		successReset: config.BatchTimeoutCircuitBreakerSuccessReset,
+		clock:        utils.InstalledClock,
+		debugPrint:   debugPrint,
+	}
+}
+
+// endpointFamily returns the API path family an endpoint belongs to ("classic", "state", "orderflow")
+// Derived from the URL template so greek endpoints served under /state/ share the state breaker
+func endpointFamily(endpoint string) string {
+	template, ok := api.Endpoints[endpoint]
+	if !ok {
+		return endpoint
+	}
+	parts := strings.Split(template, "/")
+	if len(parts) < 3 {
+		return endpoint
+	}
+	return parts[2]
+}
+
+// circuit returns the breaker for a family, creating it closed (caller holds mu)
+func (cb *CircuitBreaker) circuit(family string) *familyCircuit {
+	fc, ok := cb.families[family]
+	if !ok {
+		fc = &familyCircuit{state: CircuitClosed}
+		cb.families[family] = fc
+	}
+	return fc
+}
+
+// FilterPlan removes endpoints whose family is open
+// A half-open family keeps exactly one endpoint in the plan as its probe request
+func (cb *CircuitBreaker) FilterPlan(plan []QueryPlanItem) []QueryPlanItem {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := cb.clock.Now()
+	filtered := make([]QueryPlanItem, 0, len(plan))
+	skipped := 0
+	for _, item := range plan {
+		endpoints := make([]string, 0, len(item.Endpoints))
+		for _, endpoint := range item.Endpoints {
+			if cb.allow(endpointFamily(endpoint), now) {
+				endpoints = append(endpoints, endpoint)
+			} else {
+				skipped++
+			}
+		}
+		if len(endpoints) > 0 {
+			filtered = append(filtered, QueryPlanItem{Ticker: item.Ticker, Endpoints: endpoints})
+		}
+	}
+	if skipped > 0 {
+		cb.debugPrint(fmt.Sprintf("Circuit breaker: Skipped %d endpoint(s) in open families", skipped), "coordinator")
+	}
+	return filtered
+}
+
+// allow reports whether one more request may be planned for a family (caller holds mu)
+func (cb *CircuitBreaker) allow(family string, now time.Time) bool {
+	fc := cb.circuit(family)
+	switch fc.state {
+	case CircuitOpen:
+		if now.Sub(fc.openedAt) < cb.cooldown {
+			return false
+		}
+		fc.state = CircuitHalfOpen
+		fc.probeSuccesses = 0
+		fc.probeStartedAt = now
+		cb.debugPrint(fmt.Sprintf("Circuit breaker: %s half-open - sending probe request", family), "coordinator")
+		return true
+	case CircuitHalfOpen:
+		// Only one probe at a time; a probe that never reported back (filtered out, shutdown)
+		// is abandoned after a cooldown so the family can't get stuck half-open
+		if !fc.probeStartedAt.IsZero() && now.Sub(fc.probeStartedAt) < cb.cooldown {
+			return false
+		}
+		fc.probeStartedAt = now
+		return true
+	default:
+		return true
+	}
+}
+
+// RecordSuccess records a successful request for an endpoint
+func (cb *CircuitBreaker) RecordSuccess(endpoint string) {
+	family := endpointFamily(endpoint)
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	fc := cb.circuit(family)
+	fc.consecutiveFailures = 0
+	if fc.state != CircuitHalfOpen {
+		return
+	}
+	fc.probeStartedAt = time.Time{}
+	fc.probeSuccesses++
+	if fc.probeSuccesses >= cb.successReset {
+		fc.state = CircuitClosed
+		fc.lastError = ""
+		cb.debugPrint(fmt.Sprintf("Circuit breaker: %s closed after %d successful probe(s)", family, fc.probeSuccesses), "coordinator")
+	}
+}
+
+// RecordFailure records a failed request for an endpoint
+// Opens the family after threshold consecutive failures, or immediately if a probe fails
+func (cb *CircuitBreaker) RecordFailure(endpoint string, err error) {
+	family := endpointFamily(endpoint)
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	fc := cb.circuit(family)
+	fc.consecutiveFailures++
+	if err != nil {
+		fc.lastError = err.Error()
+	}
+
+	switch fc.state {
+	case CircuitHalfOpen:
+		fc.state = CircuitOpen
+		fc.openedAt = cb.clock.Now()
+		fc.probeStartedAt = time.Time{}
+		cb.debugPrint(fmt.Sprintf("Circuit breaker: %s probe failed - open for another %v", family, cb.cooldown), "coordinator")
+	case CircuitClosed:
+		if fc.consecutiveFailures >= cb.threshold {
+			fc.state = CircuitOpen
+			fc.openedAt = cb.clock.Now()
+			cb.debugPrint(fmt.Sprintf("Circuit breaker: %s open after %d consecutive failures (last: %s) - skipping for %v",
+				family, fc.consecutiveFailures, fc.lastError, cb.cooldown), "coordinator")
+		}
+	}
+}
+
+// RecordResults feeds a batch's fetch results into the breaker
+// Subscription and rate limit errors don't count: they aren't outages and have their own handling
+func (cb *CircuitBreaker) RecordResults(results map[api.Query]map[string]interface{}, fetchErrors map[api.Query]error) {
+	for query := range results {
+		cb.RecordSuccess(query.Endpoint)
+	}
+	for query, err := range fetchErrors {
+		if !countsAsOutage(err) {
+			continue
+		}
+		cb.RecordFailure(query.Endpoint, err)
+	}
+}
+
+// countsAsOutage reports whether a fetch error indicates the endpoint family is unhealthy
+// (network errors, timeouts, 5xx) rather than a problem with the request itself
+func countsAsOutage(err error) bool {
+	var subscriptionErr *api.SubscriptionError
+	if errors.As(err, &subscriptionErr) {
+		return false
+	}
+	var rateLimitErr *api.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return false
+	}
+	var requestErr *api.RequestError
+	if errors.As(err, &requestErr) && requestErr.StatusCode >= 400 && requestErr.StatusCode < 500 {
+		return false
+	}
+	return true
+}
+
+// GetStatus returns the state of every family the breaker has seen, sorted by family
+func (cb *CircuitBreaker) GetStatus() []CircuitStatus {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := cb.clock.Now()
+	statuses := make([]CircuitStatus, 0, len(cb.families))
+	for family, fc := range cb.families {
+		status := CircuitStatus{
+			Family:              family,
+			State:               fc.state,
+			ConsecutiveFailures: fc.consecutiveFailures,
+			LastError:           fc.lastError,
+		}
+		if !fc.openedAt.IsZero() {
+			status.OpenedAt = float64(fc.openedAt.UnixNano()) / 1e9
+		}
+		if fc.state == CircuitOpen {
+			if remaining := cb.cooldown - now.Sub(fc.openedAt); remaining > 0 {
+				status.RetryInSec = remaining.Seconds()
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Family < statuses[j].Family })
+	return statuses
+}
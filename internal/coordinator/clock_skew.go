@@ -0,0 +1,149 @@
+package coordinator
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/utils"
+)
+
+// ClockSkewStatus reports the measured difference between the local clock and API timestamps
+type ClockSkewStatus struct {
+	SkewSec      float64 `json:"skew_sec"`       // Local clock minus API time (positive = local clock ahead)
+	Samples      int     `json:"samples"`        // Samples in the current window
+	Warning      bool    `json:"warning"`        // |skew| exceeds ClockSkewWarnSec
+	Correcting   bool    `json:"correcting"`     // Market time is being offset by the skew
+	OffsetSec    float64 `json:"offset_sec"`     // Correction currently applied to market time
+	LastSampleAt float64 `json:"last_sample_at"` // Unix seconds (0 = no samples yet)
+}
+
+// ClockSkewMonitor compares API response timestamps with the local clock
+// API timestamps lag real time by data/network latency, so the skew estimate is the smallest
+// (local - API) difference over the recent window - latency only ever makes samples larger
+type ClockSkewMonitor struct {
+	mu           sync.Mutex
+	samples      []float64 // Ring buffer of local - API differences (seconds)
+	next         int
+	count        int
+	correct      bool // Offset market time by the skew once it exceeds the warning threshold
+	warning      bool
+	lastSampleAt time.Time
+	onWarning    func(ClockSkewStatus) // Called when the skew first crosses the threshold
+	debugPrint   func(string, string)
+}
+
+// NewClockSkewMonitor creates a clock skew monitor
+func NewClockSkewMonitor(correct bool, debugPrint func(string, string)) *ClockSkewMonitor {
+	return &ClockSkewMonitor{
+		samples:    make([]float64, config.ClockSkewSampleWindow),
+		correct:    correct,
+		debugPrint: debugPrint,
+	}
+}
+
+// SetOnWarning sets the callback for skew warnings (e.g. to notify the UI)
+func (csm *ClockSkewMonitor) SetOnWarning(onWarning func(ClockSkewStatus)) {
+	csm.mu.Lock()
+	defer csm.mu.Unlock()
+	csm.onWarning = onWarning
+}
+
+// SetCorrection enables or disables offsetting market time by the measured skew
+func (csm *ClockSkewMonitor) SetCorrection(correct bool) {
+	csm.mu.Lock()
+	defer csm.mu.Unlock()
+	csm.correct = correct
+	csm.applyCorrectionLocked()
+}
+
+// Record adds a sample from an API timestamp (Unix seconds) received at localNow
+// Returns the current skew estimate (0 until enough samples have been collected)
+func (csm *ClockSkewMonitor) Record(apiTimestamp float64, localNow time.Time) float64 {
+	diff := float64(localNow.UnixNano())/1e9 - apiTimestamp
+	if math.Abs(diff) > config.ClockSkewMaxSampleSec {
+		return 0 // Stale snapshot (e.g. pre-market data) or bad timestamp - says nothing about the clock
+	}
+
+	csm.mu.Lock()
+	csm.samples[csm.next] = diff
+	csm.next = (csm.next + 1) % len(csm.samples)
+	if csm.count < len(csm.samples) {
+		csm.count++
+	}
+	csm.lastSampleAt = localNow
+
+	skew := csm.skewLocked()
+	wasWarning := csm.warning
+	csm.warning = csm.count >= config.ClockSkewMinSamples && math.Abs(skew) > config.ClockSkewWarnSec
+	csm.applyCorrectionLocked()
+	status := csm.statusLocked()
+	onWarning := csm.onWarning
+	csm.mu.Unlock()
+
+	if status.Warning && !wasWarning {
+		direction := "behind"
+		if skew > 0 {
+			direction = "ahead of"
+		}
+		csm.debugPrint(fmt.Sprintf("⚠️ Clock skew: local clock is %.1fs %s API time (correction: %v) - check the system clock / NTP",
+			math.Abs(skew), direction, status.Correcting), "error")
+		if onWarning != nil {
+			onWarning(status)
+		}
+	} else if !status.Warning && wasWarning {
+		csm.debugPrint(fmt.Sprintf("Clock skew back within %.0fs (%.1fs)", config.ClockSkewWarnSec, skew), "coordinator")
+	}
+
+	if csm.count < config.ClockSkewMinSamples {
+		return 0
+	}
+	return skew
+}
+
+// skewLocked returns the minimum difference in the window (caller holds mu)
+func (csm *ClockSkewMonitor) skewLocked() float64 {
+	if csm.count == 0 {
+		return 0
+	}
+	skew := math.Inf(1)
+	for i := 0; i < csm.count; i++ {
+		skew = math.Min(skew, csm.samples[i])
+	}
+	return skew
+}
+
+// applyCorrectionLocked sets or clears the market time offset (caller holds mu)
+func (csm *ClockSkewMonitor) applyCorrectionLocked() {
+	if csm.correct && csm.warning {
+		utils.SetClockOffset(time.Duration(csm.skewLocked() * float64(time.Second)))
+	} else {
+		utils.SetClockOffset(0)
+	}
+}
+
+// statusLocked builds a status snapshot (caller holds mu)
+func (csm *ClockSkewMonitor) statusLocked() ClockSkewStatus {
+	status := ClockSkewStatus{
+		Samples:   csm.count,
+		Warning:   csm.warning,
+		OffsetSec: utils.GetClockOffset().Seconds(),
+	}
+	status.Correcting = status.OffsetSec != 0
+	if csm.count >= config.ClockSkewMinSamples {
+		status.SkewSec = csm.skewLocked()
+	}
+	if !csm.lastSampleAt.IsZero() {
+		status.LastSampleAt = float64(csm.lastSampleAt.UnixNano()) / 1e9
+	}
+	return status
+}
+
+// GetStatus returns the current skew estimate
+func (csm *ClockSkewMonitor) GetStatus() ClockSkewStatus {
+	csm.mu.Lock()
+	defer csm.mu.Unlock()
+	return csm.statusLocked()
+}
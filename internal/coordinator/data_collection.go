@@ -1,14 +1,22 @@
 package coordinator
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
+	"market-terminal/internal/alerts"
 	"market-terminal/internal/api"
+	"market-terminal/internal/config"
 	"market-terminal/internal/database"
+	"market-terminal/internal/processors"
 	"market-terminal/internal/scheduler"
+	"market-terminal/internal/tracing"
+	"market-terminal/internal/utils"
 )
 
 // DataCollectionCoordinator coordinates data collection operations
@@ -23,8 +31,29 @@ type DataCollectionCoordinator struct {
 	getOpenCharts       func() []interface{}
 	debugPrint          func(string, string)
 	tickersInProgress   map[string]bool
+	overlapPolicy       string          // batch_overlap_policy: skip or queue a ticker that is due while in flight
+	overlapPending      map[string]bool // Tickers to fetch again once their running batch ends (queue policy)
+	overlaps            overlapStats
 	inProgressLock      sync.RWMutex
 	healthCheck         *HealthCheck // Optional health check reference
+	apiErrorCounts      map[string]int // ticker -> API errors for apiErrorCountsDate (end-of-day report)
+	apiErrorCountsDate  string         // Market date ("2006-01-02") the error counts belong to
+	errorCountsLock     sync.Mutex
+	workerPool          *FetchWorkerPool // Persistent fetch workers shared by all batches
+	coalescer           *BatchCoalescer  // Merges ticker timers firing together into one batch
+	circuitBreaker      *CircuitBreaker  // Skips endpoint families that keep failing
+	quotaSaver          *QuotaSaver      // Limits background tickers to chart endpoints when the quota won't last the day
+	clockSkew           *ClockSkewMonitor // Compares API timestamps with the local clock
+	spotCheck           *SpotChecker      // Compares spot with a secondary quote source (spot_check)
+	alertEngine         *alerts.Engine    // Live alert rules (live_alerts), evaluated on every collected row
+	levelCross          *LevelCrossTracker // Logs spot crossing zero_gamma and the major levels
+	processors          *processors.Runner // Custom data processors adding columns/events to each row
+	tracer              *tracing.Tracer   // Correlation IDs per batch (plan -> fetch -> write -> flush)
+	fieldSources        map[string]map[string]FieldSource // ticker -> field -> endpoint of the last merged row
+	fieldSourcesLock    sync.RWMutex
+	fallback            *FetchFallbackCache // Last good response per ticker/endpoint, fills in for failed fetches
+	latest              *LatestStore        // Last collected row per ticker (serves the current market date without SQLite)
+	clock               utils.ClockSource   // Source of "now" for row timestamps and the error counts' market date
 }
 
 // NewDataCollectionCoordinator creates a new data collection coordinator
@@ -38,7 +67,7 @@ func NewDataCollectionCoordinator(
 	getOpenCharts func() []interface{},
 	debugPrint func(string, string),
 ) *DataCollectionCoordinator {
-	return &DataCollectionCoordinator{
+	dcc := &DataCollectionCoordinator{
 		querySystem:       querySystem,
 		dataWriter:        dataWriter,
 		scheduler:         scheduler,
@@ -48,8 +77,111 @@ func NewDataCollectionCoordinator(
 		getOpenCharts:     getOpenCharts,
 		debugPrint:        debugPrint,
 		tickersInProgress: make(map[string]bool),
+		overlapPolicy:     config.BatchOverlapSkip,
+		overlapPending:    make(map[string]bool),
+		overlaps:          overlapStats{perTicker: make(map[string]int64)},
 		healthCheck:       nil, // Will be set by app.go after health check is created
+		apiErrorCounts:    make(map[string]int),
+		circuitBreaker:    NewCircuitBreaker(debugPrint),
+		quotaSaver:        NewQuotaSaver(debugPrint),
+		clockSkew:         NewClockSkewMonitor(false, debugPrint),
+		spotCheck:         NewSpotChecker(debugPrint),
+		alertEngine:       alerts.NewEngine(config.AlertRecentLimit),
+		levelCross:        NewLevelCrossTracker(dataWriter, debugPrint),
+		processors:        processors.NewRunner(debugPrint),
+		fieldSources:      make(map[string]map[string]FieldSource),
+		fallback:          NewFetchFallbackCache(config.DefaultFetchFallbackMaxAgeSec),
+		latest:            NewLatestStore(),
+		tracer:            tracing.NewTracer(config.RecentTraceCount),
 	}
+
+	// Persistent worker pool sized by config (shared across batches instead of per-batch goroutines)
+	dcc.workerPool = NewFetchWorkerPool(config.APIExecutorWorkers, func(ctx context.Context, endpoint, ticker string) (map[string]interface{}, error) {
+		return dcc.querySystem.GetClient().FetchEndpointContext(ctx, endpoint, ticker)
+	}, debugPrint)
+	dcc.workerPool.Start()
+
+	// Components timing cooldowns and staleness follow the coordinator's clock
+	dcc.circuitBreaker.clock = &dcc.clock
+	dcc.spotCheck.clock = &dcc.clock
+	dcc.fallback.clock = &dcc.clock
+
+	dcc.coalescer = NewBatchCoalescer(time.Duration(config.DefaultBatchCoalesceWindowMs)*time.Millisecond, dcc.ProcessTickerBatch, dcc.isChartOpen, debugPrint)
+
+	return dcc
+}
+
+// SetClock replaces the coordinator's clock (tests and simulated sessions; nil = follow the installed clock)
+// The circuit breaker, spot check, fetch fallback and health check follow it too
+func (dcc *DataCollectionCoordinator) SetClock(clock utils.Clock) {
+	dcc.clock.Set(clock)
+}
+
+// Stop stops the fetch worker pool (in-flight fetches are cancelled)
+func (dcc *DataCollectionCoordinator) Stop() {
+	dcc.workerPool.Stop()
+}
+
+// isChartOpen reports whether a ticker is displayed in an open chart
+func (dcc *DataCollectionCoordinator) isChartOpen(ticker string) bool {
+	for _, chartTicker := range dcc.getOpenCharts() {
+		if chartTickerStr, ok := chartTicker.(string); ok && chartTickerStr == ticker {
+			return true
+		}
+	}
+	return false
+}
+
+// GetBatchCoalescer returns the coalescer the per-ticker timers submit through
+func (dcc *DataCollectionCoordinator) GetBatchCoalescer() *BatchCoalescer {
+	return dcc.coalescer
+}
+
+// GetTracer returns the tracer holding recent collection traces
+func (dcc *DataCollectionCoordinator) GetTracer() *tracing.Tracer {
+	return dcc.tracer
+}
+
+// claimTickers marks tickers as in progress and returns only those that weren't already
+// Prevents overlapping timer fires from fetching the same ticker twice at once: each ticker left out counts as an
+// overlap avoided and, with the queue policy, is fetched once more when its running batch ends
+func (dcc *DataCollectionCoordinator) claimTickers(tickers []string) []string {
+	dcc.inProgressLock.Lock()
+	defer dcc.inProgressLock.Unlock()
+
+	claimed := make([]string, 0, len(tickers))
+	for _, ticker := range tickers {
+		if dcc.tickersInProgress[ticker] {
+			dcc.overlaps.avoided++
+			dcc.overlaps.perTicker[ticker]++
+			dcc.overlaps.lastOverlapAt = float64(dcc.clock.Now().UnixMilli()) / 1000
+			if dcc.overlapPolicy == config.BatchOverlapQueue && !dcc.overlapPending[ticker] {
+				dcc.overlapPending[ticker] = true
+				dcc.overlaps.queued++
+			}
+			continue
+		}
+		dcc.tickersInProgress[ticker] = true
+		claimed = append(claimed, ticker)
+	}
+	return claimed
+}
+
+// releaseTickers clears in-progress tracking for tickers claimed by claimTickers
+// Returns the released tickers that have a queued follow-up fetch
+func (dcc *DataCollectionCoordinator) releaseTickers(tickers []string) []string {
+	dcc.inProgressLock.Lock()
+	defer dcc.inProgressLock.Unlock()
+
+	var queued []string
+	for _, ticker := range tickers {
+		delete(dcc.tickersInProgress, ticker)
+		if dcc.overlapPending[ticker] {
+			delete(dcc.overlapPending, ticker)
+			queued = append(queued, ticker)
+		}
+	}
+	return queued
 }
 
 // SetHealthCheck sets the health check reference (called by app.go)
@@ -72,13 +204,50 @@ func (dcc *DataCollectionCoordinator) UpdateEnabledTickers(tickers []string) {
 
 // ProcessTickerBatch processes a batch of tickers
 func (dcc *DataCollectionCoordinator) ProcessTickerBatch(tickers []string) {
+	dcc.processTickerBatch(tickers, "scheduler")
+}
+
+// FetchNow fetches one ticker immediately, outside its scheduled interval
+// Returns the timestamp of the row queued for writing; fails when rate limited, when the ticker is
+// already being fetched, or when no endpoint returned data
+func (dcc *DataCollectionCoordinator) FetchNow(ticker string) (float64, error) {
+	if dcc.getShuttingDown() {
+		return 0, fmt.Errorf("shutting down")
+	}
+	tracker := dcc.scheduler.GetRateLimitTracker()
+	if tracker.IsRateLimited() || !tracker.CanMakeRequest() {
+		return 0, fmt.Errorf("API rate limit reached - try again shortly")
+	}
+	if dcc.IsTickerInProgress(ticker) {
+		return 0, fmt.Errorf("%s is already being fetched", ticker)
+	}
+
+	timestamps := dcc.processTickerBatch([]string{ticker}, "fetch_now")
+	timestamp, ok := timestamps[ticker]
+	if !ok {
+		return 0, fmt.Errorf("no data returned for %s", ticker)
+	}
+	return timestamp, nil
+}
+
+// processTickerBatch fetches, aggregates and queues writes for a batch of tickers
+// Each batch gets a trace (source names what started it) whose ID tags its log lines and follows
+// the rows through the write queue to the flush
+// Returns the row timestamp queued for each ticker that returned data
+func (dcc *DataCollectionCoordinator) processTickerBatch(tickers []string, source string) map[string]float64 {
+	timestamps := make(map[string]float64)
 	if len(tickers) == 0 {
 		dcc.debugPrint("ProcessTickerBatch called with empty ticker list", "coordinator")
-		return
+		return timestamps
 	}
 
-	dcc.debugPrint(fmt.Sprintf("ProcessTickerBatch called with %d tickers: %v", len(tickers), tickers), "coordinator")
-	log.Printf("DataCollectionCoordinator: Processing batch of %d tickers: %v", len(tickers), tickers)
+	trace := dcc.tracer.StartTrace(source, tickers)
+	defer trace.End(nil)
+	ctx := tracing.WithTrace(context.Background(), trace)
+	prefix := trace.LogPrefix()
+
+	dcc.debugPrint(fmt.Sprintf("%sProcessTickerBatch called with %d tickers: %v", prefix, len(tickers), tickers), "coordinator")
+	log.Printf("DataCollectionCoordinator: %sProcessing batch of %d tickers: %v", prefix, len(tickers), tickers)
 	
 	// Log open charts for priority calculation
 	openCharts := dcc.getOpenCharts()
@@ -95,15 +264,42 @@ func (dcc *DataCollectionCoordinator) ProcessTickerBatch(tickers []string) {
 	// Check if shutting down
 	if dcc.getShuttingDown() {
 		dcc.debugPrint("Shutting down, skipping batch", "coordinator")
-		return
+		trace.SetAttr("skipped", "shutting down")
+		return timestamps
 	}
 
+	// Skip tickers that are already being fetched by an earlier batch (queued for right after it with the queue policy)
+	claimed := dcc.claimTickers(tickers)
+	if len(claimed) < len(tickers) {
+		dcc.debugPrint(fmt.Sprintf("%sProcessTickerBatch: Skipping %d ticker(s) already in flight", prefix, len(tickers)-len(claimed)), "coordinator")
+		trace.SetAttr("already_in_flight", fmt.Sprintf("%d", len(tickers)-len(claimed)))
+	}
+	if len(claimed) == 0 {
+		return timestamps
+	}
+	defer func() {
+		if queued := dcc.releaseTickers(claimed); len(queued) > 0 {
+			go dcc.runQueuedBatch(queued)
+		}
+	}()
+	tickers = claimed
+
 	// Build query plan
+	planSpan := trace.StartSpan("plan", "")
 	plan := dcc.queryPlanner.BuildOptimizedPlan(tickers)
-	log.Printf("DataCollectionCoordinator: Query plan generated with %d items", len(plan))
+
+	// Drop endpoint families whose circuit is open (half-open families keep one probe)
+	plan = dcc.circuitBreaker.FilterPlan(plan)
+
+	// Tickers not open in a chart fetch only chart endpoints once the quota is projected to run out before the close
+	dcc.quotaSaver.Evaluate(dcc.scheduler.GetRateLimitTracker(), dcc.clock.Now())
+	plan = dcc.quotaSaver.FilterPlan(plan, dcc.queryPlanner.ChartEndpoints(), openCharts)
+	log.Printf("DataCollectionCoordinator: %sQuery plan generated with %d items", prefix, len(plan))
 	if len(plan) == 0 {
-		log.Printf("DataCollectionCoordinator: No query plan items - skipping batch")
-		return
+		log.Printf("DataCollectionCoordinator: %sNo query plan items - skipping batch", prefix)
+		planSpan.SetAttr("items", "0")
+		planSpan.End(nil)
+		return timestamps
 	}
 	
 	// Log plan details
@@ -133,62 +329,63 @@ func (dcc *DataCollectionCoordinator) ProcessTickerBatch(tickers []string) {
 
 	// Validate and filter queries
 	validatedQueries := dcc.querySystem.ValidateAndFilterQueries(planItems)
-	log.Printf("DataCollectionCoordinator: Validated %d queries (from %d plan items)", len(validatedQueries), len(planItems))
+	log.Printf("DataCollectionCoordinator: %sValidated %d queries (from %d plan items)", prefix, len(validatedQueries), len(planItems))
+	planSpan.SetAttr("items", fmt.Sprintf("%d", len(plan)))
+	planSpan.SetAttr("queries", fmt.Sprintf("%d", len(validatedQueries)))
+	planSpan.End(nil)
 
 	// Set update in progress for health check
 	if dcc.healthCheck != nil {
 		dcc.healthCheck.SetUpdateInProgress(true)
 	}
 	
-	// Track tickers in progress
-	dcc.inProgressLock.Lock()
-	for _, item := range plan {
-		dcc.tickersInProgress[item.Ticker] = true
-	}
-	dcc.inProgressLock.Unlock()
-
-	// Execute queries in parallel
+	// Execute queries in parallel on the shared worker pool
 	results := make(map[api.Query]map[string]interface{})
 	errors := make(map[api.Query]error)
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 
-	maxWorkers := 96 // From config
-	semaphore := make(chan struct{}, maxWorkers)
-
 	for _, query := range validatedQueries {
+		q := query
 		wg.Add(1)
-		go func(q api.Query) {
+		log.Printf("DataCollectionCoordinator: %sFetching %s for %s", prefix, q.Endpoint, q.Ticker)
+		submitted := dcc.workerPool.Submit(ctx, q, func(result map[string]interface{}, err error) {
 			defer wg.Done()
 
-			// Acquire semaphore
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
-
-			// Fetch endpoint
-			log.Printf("DataCollectionCoordinator: Fetching %s for %s", q.Endpoint, q.Ticker)
-			result, err := dcc.querySystem.GetClient().FetchEndpoint(q.Endpoint, q.Ticker)
-			
 			mu.Lock()
-			if err != nil {
+			if isCancellation(err) {
+				// Aborted by shutdown - not an API failure (no error count or circuit breaker hit)
+				log.Printf("DataCollectionCoordinator: %sFetch of %s for %s cancelled", prefix, q.Endpoint, q.Ticker)
+			} else if err != nil {
 				errors[q] = err
-				log.Printf("DataCollectionCoordinator: Error fetching %s for %s: %v", q.Endpoint, q.Ticker, err)
+				log.Printf("DataCollectionCoordinator: %sError fetching %s for %s: %v", prefix, q.Endpoint, q.Ticker, err)
 			} else {
 				results[q] = result
+				dcc.fallback.Store(q, result)
 				fieldCount := 0
 				if result != nil {
 					fieldCount = len(result)
 				}
-				log.Printf("DataCollectionCoordinator: Successfully fetched %s for %s (fields: %d)", q.Endpoint, q.Ticker, fieldCount)
+				log.Printf("DataCollectionCoordinator: %sSuccessfully fetched %s for %s (fields: %d)", prefix, q.Endpoint, q.Ticker, fieldCount)
 			}
 			mu.Unlock()
-		}(query)
+		})
+		if !submitted {
+			mu.Lock()
+			errors[q] = fmt.Errorf("fetch worker pool is not running")
+			mu.Unlock()
+			wg.Done()
+		}
 	}
 
 	wg.Wait()
 
 	// Aggregate results by ticker
+	aggregateSpan := trace.StartSpan("aggregate", "")
 	tickerData := dcc.aggregateResults(plan, results, errors)
+	aggregateSpan.SetAttr("results", fmt.Sprintf("%d", len(results)))
+	aggregateSpan.SetAttr("errors", fmt.Sprintf("%d", len(errors)))
+	aggregateSpan.End(nil)
 
 	// Process each ticker's data
 	log.Printf("DataCollectionCoordinator: Processing data for %d tickers", len(tickerData))
@@ -196,40 +393,45 @@ func (dcc *DataCollectionCoordinator) ProcessTickerBatch(tickers []string) {
 		if data != nil {
 			dcc.debugPrint(fmt.Sprintf("Processing completed data for %s (fields: %d)", ticker, len(data)), "coordinator")
 			log.Printf("DataCollectionCoordinator: Processing data for %s with %d fields", ticker, len(data))
-			result := dcc.ProcessCompletedTickerData(ticker, data, float64(time.Now().Unix()))
-			log.Printf("DataCollectionCoordinator: Completed processing for %s - timestamp: %.2f, priority: %v, interval: %.2f", 
-				ticker, result["timestamp_seconds"], result["priority"], result["interval"])
+			result := dcc.ProcessCompletedTickerDataContext(ctx, ticker, data, float64(dcc.clock.Now().Unix()))
+			if timestamp, ok := result["timestamp_seconds"].(float64); ok && result["skipped"] == nil && len(data) > 0 {
+				timestamps[ticker] = timestamp
+			}
+			log.Printf("DataCollectionCoordinator: %sCompleted processing for %s - timestamp: %.2f, priority: %v, interval: %.2f", 
+				prefix, ticker, result["timestamp_seconds"], result["priority"], result["interval"])
 		} else {
 			dcc.debugPrint(fmt.Sprintf("No data collected for %s", ticker), "coordinator")
 			log.Printf("DataCollectionCoordinator: No data collected for %s", ticker)
 		}
 	}
 
-	// Clean up in-progress tracking
-	dcc.inProgressLock.Lock()
-	for _, item := range plan {
-		delete(dcc.tickersInProgress, item.Ticker)
-	}
-	dcc.inProgressLock.Unlock()
-	
 	// Clear update in progress for health check
 	if dcc.healthCheck != nil {
 		dcc.healthCheck.SetUpdateInProgress(false)
 	}
+
+	return timestamps
+}
+
+// isCancellation reports whether a fetch was aborted (worker pool stopped) rather than failing
+func isCancellation(err error) bool {
+	return errors.Is(err, context.Canceled)
 }
 
 // aggregateResults aggregates API results by ticker
+// Fields returned by several endpoints take the freshest value, then the preferred source (see fieldMerger)
 func (dcc *DataCollectionCoordinator) aggregateResults(
 	plan []QueryPlanItem,
 	results map[api.Query]map[string]interface{},
 	errors map[api.Query]error,
 ) map[string]map[string]interface{} {
 	tickerData := make(map[string]map[string]interface{})
+	mergers := make(map[string]*fieldMerger)
 
 	// Initialize ticker data structures
 	for _, item := range plan {
-		if _, exists := tickerData[item.Ticker]; !exists {
-			tickerData[item.Ticker] = make(map[string]interface{})
+		if _, exists := mergers[item.Ticker]; !exists {
+			mergers[item.Ticker] = newFieldMerger()
 		}
 	}
 
@@ -238,52 +440,212 @@ func (dcc *DataCollectionCoordinator) aggregateResults(
 		if result == nil {
 			continue
 		}
+		merger, exists := mergers[query.Ticker]
+		if !exists {
+			merger = newFieldMerger()
+			mergers[query.Ticker] = merger
+		}
+		merger.add(query.Endpoint, result)
+	}
 
-		ticker := query.Ticker
-		data := tickerData[ticker]
+	// Fill failed endpoints from their last good response, only for tickers that got fresh data in this
+	// batch (a batch where everything failed must not write an old row again)
+	for query := range errors {
+		merger, exists := mergers[query.Ticker]
+		if !exists || len(merger.sources) == 0 {
+			continue
+		}
+		if cached, age, ok := dcc.fallback.Lookup(query); ok {
+			merger.addCached(query.Endpoint, cached)
+			dcc.debugPrint(fmt.Sprintf("Fallback: Filled %s for %s from the response of %v ago", query.Endpoint, query.Ticker, age.Round(time.Second)), "coordinator")
+		}
+	}
 
-		// Merge result into ticker data
-		for key, value := range result {
-			// Skip metadata keys
-			if key == "_response_headers" || key == "_response_time" {
-				continue
-			}
-			data[key] = value
+	dcc.fieldSourcesLock.Lock()
+	for ticker, merger := range mergers {
+		if len(merger.data) > 0 {
+			database.AddQualityFlags(merger.data, merger.qualityFlags())
+		}
+		tickerData[ticker] = merger.data
+		if len(merger.sources) > 0 {
+			dcc.fieldSources[ticker] = merger.sources
+		}
+		if len(merger.stale) > 0 {
+			dcc.debugPrint(fmt.Sprintf("Merge: %s kept the fresher value over %s", ticker, strings.Join(merger.stale, ", ")), "coordinator")
 		}
 	}
+	dcc.fieldSourcesLock.Unlock()
 
 	// Log errors
 	for query, err := range errors {
 		dcc.debugPrint("Error fetching "+query.Endpoint+" for "+query.Ticker+": "+err.Error(), "api")
 	}
+	dcc.recordAPIErrors(errors)
+	dcc.recordRateLimits(results, errors)
+	dcc.circuitBreaker.RecordResults(results, errors)
 
 	return tickerData
 }
 
+// GetLatestStore returns the in-memory store of each ticker's latest row
+func (dcc *DataCollectionCoordinator) GetLatestStore() *LatestStore {
+	return dcc.latest
+}
+
+// GetFetchFallback returns the cache that fills in for failed endpoint fetches
+func (dcc *DataCollectionCoordinator) GetFetchFallback() *FetchFallbackCache {
+	return dcc.fallback
+}
+
+// GetFieldSources returns the endpoint each field of a ticker's last merged row was taken from
+func (dcc *DataCollectionCoordinator) GetFieldSources(ticker string) map[string]FieldSource {
+	dcc.fieldSourcesLock.RLock()
+	defer dcc.fieldSourcesLock.RUnlock()
+	sources := make(map[string]FieldSource, len(dcc.fieldSources[ticker]))
+	for field, source := range dcc.fieldSources[ticker] {
+		sources[field] = source
+	}
+	return sources
+}
+
+// recordRateLimits feeds response headers and 429s into the scheduler's rate limit tracker
+func (dcc *DataCollectionCoordinator) recordRateLimits(results map[api.Query]map[string]interface{}, fetchErrors map[api.Query]error) {
+	tracker := dcc.scheduler.GetRateLimitTracker()
+	now := float64(time.Now().Unix())
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		headers, _ := result["_response_headers"].(map[string]string)
+		keyName, _ := result["_api_key_name"].(string)
+		tracker.RecordRequest(keyName, now, true, headers)
+	}
+	for _, err := range fetchErrors {
+		var rateLimitErr *api.RateLimitError
+		if errors.As(err, &rateLimitErr) {
+			var retryAfter float64
+			fmt.Sscanf(rateLimitErr.RetryAfter, "%f", &retryAfter)
+			tracker.HandleRateLimitError(rateLimitErr.KeyName, retryAfter)
+		}
+	}
+}
+
+// GetSpotChecker returns the spot price cross-check
+func (dcc *DataCollectionCoordinator) GetSpotChecker() *SpotChecker {
+	return dcc.spotCheck
+}
+
+// GetAlertEngine returns the live alert engine
+func (dcc *DataCollectionCoordinator) GetAlertEngine() *alerts.Engine {
+	return dcc.alertEngine
+}
+
+// GetProcessorRunner returns the runner of the enabled data processors
+func (dcc *DataCollectionCoordinator) GetProcessorRunner() *processors.Runner {
+	return dcc.processors
+}
+
+// GetLevelCrossTracker returns the level cross event log
+func (dcc *DataCollectionCoordinator) GetLevelCrossTracker() *LevelCrossTracker {
+	return dcc.levelCross
+}
+
+// GetClockSkewMonitor returns the clock skew monitor
+func (dcc *DataCollectionCoordinator) GetClockSkewMonitor() *ClockSkewMonitor {
+	return dcc.clockSkew
+}
+
+// GetQuotaSaver returns the quota saver limiting background tickers to chart endpoints
+func (dcc *DataCollectionCoordinator) GetQuotaSaver() *QuotaSaver {
+	return dcc.quotaSaver
+}
+
+// GetCircuitBreakerStatus returns the circuit breaker state per endpoint family
+func (dcc *DataCollectionCoordinator) GetCircuitBreakerStatus() []CircuitStatus {
+	return dcc.circuitBreaker.GetStatus()
+}
+
+// recordAPIErrors counts API errors per ticker for the current market date
+// Counts reset automatically when the market date rolls over
+func (dcc *DataCollectionCoordinator) recordAPIErrors(errors map[api.Query]error) {
+	if len(errors) == 0 {
+		return
+	}
+
+	dateStr := utils.GetMarketDateAt(dcc.clock.Now()).Format("2006-01-02")
+
+	dcc.errorCountsLock.Lock()
+	defer dcc.errorCountsLock.Unlock()
+
+	if dcc.apiErrorCountsDate != dateStr {
+		dcc.apiErrorCounts = make(map[string]int)
+		dcc.apiErrorCountsDate = dateStr
+	}
+	for query := range errors {
+		dcc.apiErrorCounts[query.Ticker]++
+	}
+}
+
+// GetAPIErrorCounts returns API error counts per ticker for a market date ("2006-01-02")
+// Only the current market date is tracked - other dates return an empty map
+func (dcc *DataCollectionCoordinator) GetAPIErrorCounts(dateStr string) map[string]int {
+	dcc.errorCountsLock.Lock()
+	defer dcc.errorCountsLock.Unlock()
+
+	counts := make(map[string]int)
+	if dcc.apiErrorCountsDate != dateStr {
+		return counts
+	}
+	for ticker, count := range dcc.apiErrorCounts {
+		counts[ticker] = count
+	}
+	return counts
+}
+
 // ProcessCompletedTickerData processes completed ticker data
 func (dcc *DataCollectionCoordinator) ProcessCompletedTickerData(ticker string, data map[string]interface{}, scheduledUpdateTime float64) map[string]interface{} {
+	return dcc.ProcessCompletedTickerDataContext(context.Background(), ticker, data, scheduledUpdateTime)
+}
+
+// ProcessCompletedTickerDataContext is ProcessCompletedTickerData carrying the batch's trace (if any) to the write queue
+func (dcc *DataCollectionCoordinator) ProcessCompletedTickerDataContext(ctx context.Context, ticker string, data map[string]interface{}, scheduledUpdateTime float64) map[string]interface{} {
 	// Update scheduler state
-	currentTime := float64(time.Now().Unix())
+	currentTime := float64(dcc.clock.Now().Unix())
 	dcc.scheduler.RecordFetch(ticker)
 
 	// Calculate timestamp
 	var timestampSeconds float64
-	if apiTimestamp, ok := data["timestamp"].(float64); ok {
-		// Check if timestamp is in milliseconds (> 1e10)
-		if apiTimestamp > 1e10 {
-			timestampSeconds = apiTimestamp / 1000.0
-		} else {
-			timestampSeconds = apiTimestamp
+	if apiTimestamp, ok := apiTimestampSeconds(data); ok {
+		timestampSeconds = apiTimestamp
+
+		// Measure clock skew against the API and store it with the row
+		if skew := dcc.clockSkew.Record(timestampSeconds, time.Now()); skew != 0 {
+			data["clock_skew"] = skew
 		}
 	} else {
 		timestampSeconds = currentTime
 	}
 
+	// Flag rows whose spot disagrees with the secondary quote source
+	dcc.spotCheck.Check(ticker, data, dcc.clock.Now())
+
+	// Custom processors add their columns before the row is cached, evaluated and stored
+	dcc.processors.Process(ticker, data)
+
+	// Latest values are served from memory; the database only keeps the history
+	dcc.latest.Update(ticker, timestampSeconds, data)
+
+	// Live alert rules see the row as it arrives
+	dcc.alertEngine.Evaluate(ticker, timestampSeconds, data)
+
 	// Check if shutting down
 	if dcc.getShuttingDown() {
 		return map[string]interface{}{"timestamp_seconds": timestampSeconds, "skipped": true}
 	}
 
+	// Log spot crossing zero_gamma and the major levels (the session event timeline)
+	dcc.levelCross.Track(ticker, timestampSeconds, dcc.clock.Now(), data)
+
 	// Determine priority based on ticker visibility
 	priority := 1 // Default to MEDIUM priority
 	openCharts := dcc.getOpenCharts()
@@ -298,9 +660,9 @@ func (dcc *DataCollectionCoordinator) ProcessCompletedTickerData(ticker string,
 	}
 
 	// Enqueue write
-	dcc.debugPrint(fmt.Sprintf("Enqueuing write for %s (timestamp: %.0f, fields: %d, priority: %d)", 
-		ticker, timestampSeconds, len(data), priority), "coordinator")
-	dcc.writeQueue.Enqueue(ticker, timestampSeconds, data, priority)
+	dcc.debugPrint(fmt.Sprintf("%sEnqueuing write for %s (timestamp: %.0f, fields: %d, priority: %d)", 
+		tracing.FromContext(ctx).LogPrefix(), ticker, timestampSeconds, len(data), priority), "coordinator")
+	dcc.writeQueue.EnqueueContext(ctx, ticker, timestampSeconds, data, priority)
 	dcc.debugPrint(fmt.Sprintf("Write enqueued for %s", ticker), "coordinator")
 
 	// Calculate interval
@@ -313,6 +675,13 @@ func (dcc *DataCollectionCoordinator) ProcessCompletedTickerData(ticker string,
 	}
 }
 
+// apiTimestampSeconds returns the row timestamp reported by the API, in seconds
+// (api.ParseResponse already converted millisecond timestamps)
+func apiTimestampSeconds(data map[string]interface{}) (float64, bool) {
+	apiTimestamp, ok := data["timestamp"].(float64)
+	return apiTimestamp, ok && apiTimestamp > 0
+}
+
 // IsTickerInProgress checks if a ticker is currently being processed
 func (dcc *DataCollectionCoordinator) IsTickerInProgress(ticker string) bool {
 	dcc.inProgressLock.RLock()
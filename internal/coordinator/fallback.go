@@ -0,0 +1,88 @@
+package coordinator
+
+import (
+	"sync"
+	"time"
+
+	"market-terminal/internal/api"
+	"market-terminal/internal/utils"
+)
+
+// FetchFallbackCache keeps the last successful response per ticker/endpoint so a failed fetch (e.g. HTTP 500)
+// can be filled from it instead of leaving NULL columns in the row
+type FetchFallbackCache struct {
+	mu      sync.RWMutex
+	maxAge  time.Duration // 0 = disabled
+	entries map[api.Query]cachedFetch
+	used    int         // Fetches filled from the cache since startup
+	clock   utils.Clock // Source of "now" for response ages (the coordinator's clock)
+}
+
+type cachedFetch struct {
+	data      map[string]interface{}
+	fetchedAt time.Time
+}
+
+// FetchFallbackStatus summarizes the cache for the UI/diagnostics
+type FetchFallbackStatus struct {
+	MaxAgeSec int `json:"max_age_sec"` // 0 = disabled
+	Entries   int `json:"entries"`
+	Used      int `json:"used"` // Failed fetches filled from the cache since startup
+}
+
+// NewFetchFallbackCache creates a cache serving responses up to maxAgeSec old (<= 0 disables it)
+func NewFetchFallbackCache(maxAgeSec int) *FetchFallbackCache {
+	c := &FetchFallbackCache{entries: make(map[api.Query]cachedFetch), clock: utils.InstalledClock}
+	c.SetMaxAgeSec(maxAgeSec)
+	return c
+}
+
+// SetMaxAgeSec changes the staleness window (<= 0 disables the fallback and drops cached responses)
+func (c *FetchFallbackCache) SetMaxAgeSec(maxAgeSec int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if maxAgeSec <= 0 {
+		c.maxAge = 0
+		c.entries = make(map[api.Query]cachedFetch)
+		return
+	}
+	c.maxAge = time.Duration(maxAgeSec) * time.Second
+}
+
+// Store remembers a successful response
+func (c *FetchFallbackCache) Store(query api.Query, data map[string]interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxAge == 0 || data == nil {
+		return
+	}
+	c.entries[query] = cachedFetch{data: data, fetchedAt: c.clock.Now()}
+}
+
+// Lookup returns the last successful response for a failed query if it is within the staleness window
+func (c *FetchFallbackCache) Lookup(query api.Query) (map[string]interface{}, time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[query]
+	if !ok || c.maxAge == 0 {
+		return nil, 0, false
+	}
+	age := c.clock.Now().Sub(entry.fetchedAt)
+	if age > c.maxAge {
+		delete(c.entries, query)
+		return nil, 0, false
+	}
+	c.used++
+	return entry.data, age, true
+}
+
+// Status returns the staleness window and how often the cache was used
+func (c *FetchFallbackCache) Status() FetchFallbackStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return FetchFallbackStatus{
+		MaxAgeSec: int(c.maxAge / time.Second),
+		Entries:   len(c.entries),
+		Used:      c.used,
+	}
+}
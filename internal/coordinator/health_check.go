@@ -5,6 +5,8 @@ import (
 	"log"
 	"sync"
 	"time"
+
+	"market-terminal/internal/utils"
 )
 
 // HealthCheck monitors system health and detects stuck updates
@@ -16,6 +18,7 @@ type HealthCheck struct {
 		GetActiveTickerCount() int
 	}
 	debugPrint            func(string, string)
+	clock                 utils.Clock // Source of "now" for stall detection (the coordinator's clock)
 	
 	// Tracking state
 	lastFetchTimes        map[string]float64 // ticker -> last fetch time
@@ -24,6 +27,9 @@ type HealthCheck struct {
 	updateInProgress      bool
 	recoveryAttempts      int
 	lastRecoveryTime      float64
+	paused                bool // Collection paused by the user - skip checks so it isn't reported as a stall
+	ecoMode               bool   // Eco (battery saving) mode is on (reported in the status)
+	ecoReason             string
 	
 	// Thresholds
 	stuckThresholdMs      float64 // 30 seconds
@@ -45,12 +51,17 @@ func NewHealthCheck(
 	},
 	debugPrint func(string, string),
 ) *HealthCheck {
+	var clock utils.Clock = utils.InstalledClock
+	if coordinator != nil {
+		clock = &coordinator.clock
+	}
 	return &HealthCheck{
 		coordinator:        coordinator,
 		perTickerScheduler: perTickerScheduler,
 		debugPrint:         debugPrint,
+		clock:              clock,
 		lastFetchTimes:     make(map[string]float64),
-		lastCheckTime:      float64(time.Now().Unix()),
+		lastCheckTime:      float64(clock.Now().Unix()),
 		stuckThresholdMs:   30000,  // 30 seconds
 		criticalStuckMs:    60000,  // 60 seconds
 		checkIntervalMs:    2000,   // 2 seconds
@@ -95,12 +106,31 @@ func (hc *HealthCheck) Stop() {
 	hc.debugPrint("Health check system stopped", "system")
 }
 
+// SetPaused suspends or resumes health checks while collection is paused
+func (hc *HealthCheck) SetPaused(paused bool) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	
+	hc.paused = paused
+	// Don't count the pause as a stall when checks resume
+	hc.lastCheckTime = float64(hc.clock.Now().Unix()) * 1000
+}
+
+// SetEcoMode records whether eco mode is on (reported in the status) and why
+func (hc *HealthCheck) SetEcoMode(active bool, reason string) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	
+	hc.ecoMode = active
+	hc.ecoReason = reason
+}
+
 // RecordFetch records that a ticker was fetched (called by coordinator)
 func (hc *HealthCheck) RecordFetch(ticker string) {
 	hc.mu.Lock()
 	defer hc.mu.Unlock()
 	
-	hc.lastFetchTimes[ticker] = float64(time.Now().Unix())
+	hc.lastFetchTimes[ticker] = float64(hc.clock.Now().Unix())
 }
 
 // SetUpdateInProgress sets the update in progress flag
@@ -110,7 +140,7 @@ func (hc *HealthCheck) SetUpdateInProgress(inProgress bool) {
 	
 	hc.updateInProgress = inProgress
 	if inProgress {
-		now := float64(time.Now().Unix())
+		now := float64(hc.clock.Now().Unix())
 		hc.updateStartTime = &now
 	} else {
 		hc.updateStartTime = nil
@@ -132,7 +162,11 @@ func (hc *HealthCheck) run() {
 // performCheck performs a health check
 func (hc *HealthCheck) performCheck() {
 	hc.mu.Lock()
-	currentTime := float64(time.Now().Unix()) * 1000 // milliseconds
+	if hc.paused {
+		hc.mu.Unlock()
+		return
+	}
+	currentTime := float64(hc.clock.Now().Unix()) * 1000 // milliseconds
 	lastCheckTime := hc.lastCheckTime
 	updateInProgress := hc.updateInProgress
 	updateStartTime := hc.updateStartTime
@@ -196,7 +230,7 @@ func (hc *HealthCheck) performCheck() {
 // triggerRecovery triggers a recovery action
 func (hc *HealthCheck) triggerRecovery(reason string) {
 	hc.mu.Lock()
-	currentTime := float64(time.Now().Unix()) * 1000
+	currentTime := float64(hc.clock.Now().Unix()) * 1000
 	
 	// Throttle recovery attempts (max 1 per 30 seconds)
 	timeSinceLastRecovery := currentTime - hc.lastRecoveryTime
@@ -232,9 +266,11 @@ func (hc *HealthCheck) GetStatus() map[string]interface{} {
 	status["update_in_progress"] = hc.updateInProgress
 	status["recovery_attempts"] = hc.recoveryAttempts
 	status["last_check_time"] = hc.lastCheckTime
+	status["eco_mode"] = hc.ecoMode
+	status["eco_reason"] = hc.ecoReason
 	
 	if hc.updateStartTime != nil {
-		status["update_duration_ms"] = (float64(time.Now().Unix())*1000) - (*hc.updateStartTime * 1000)
+		status["update_duration_ms"] = (float64(hc.clock.Now().Unix())*1000) - (*hc.updateStartTime * 1000)
 	}
 	
 	return status
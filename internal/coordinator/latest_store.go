@@ -0,0 +1,106 @@
+package coordinator
+
+import (
+	"sync"
+	"time"
+
+	"market-terminal/internal/utils"
+)
+
+// LatestStore keeps the most recent scalar snapshot per ticker in memory so the main table and GetTickerData
+// never read SQLite for the current market date; the database is only the historical record
+type LatestStore struct {
+	mu        sync.RWMutex
+	snapshots map[string]LatestSnapshot
+}
+
+// LatestSnapshot is the last row collected for a ticker (scalar fields only - profiles stay in the database)
+type LatestSnapshot struct {
+	Date      string                 `json:"date"`      // Market date the row was filed under ("2006-01-02")
+	Timestamp float64                `json:"timestamp"` // Row timestamp in seconds
+	Fields    map[string]interface{} `json:"fields"`
+}
+
+// latestSkipKeys are row keys that aren't scalar data fields
+var latestSkipKeys = map[string]bool{"timestamp": true, "ticker": true, "profiles": true, "_response_headers": true, "_response_time": true, "_api_key_name": true}
+
+// latestCarriedFields keep their last known value when a row lacks them (LoadTickerData's fallback for the main table)
+var latestCarriedFields = []string{"spot", "zero_gamma", "major_pos_vol", "major_neg_vol"}
+
+// NewLatestStore creates an empty store
+func NewLatestStore() *LatestStore {
+	return &LatestStore{snapshots: make(map[string]LatestSnapshot)}
+}
+
+// Update records a collected row for the current market date; an older row than the stored one is ignored
+// Zero values are dropped like the writer drops them, and the main table fields fall back to their last known value
+func (s *LatestStore) Update(ticker string, timestamp float64, data map[string]interface{}) {
+	fields := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		if latestSkipKeys[key] {
+			continue
+		}
+		switch value.(type) {
+		case []interface{}, map[string]interface{}:
+			continue // Profiles/arrays
+		}
+		if value == nil || value == 0 || value == 0.0 || value == "" || value == false {
+			continue
+		}
+		fields[key] = value
+	}
+
+	date := currentStoreDate()
+	if previous, ok := s.Get(ticker, date); ok {
+		for _, field := range latestCarriedFields {
+			if _, present := fields[field]; !present && previous.Fields[field] != nil {
+				fields[field] = previous.Fields[field]
+			}
+		}
+	}
+	s.put(ticker, LatestSnapshot{Date: date, Timestamp: timestamp, Fields: fields})
+}
+
+// Seed fills in a ticker's snapshot from the database (e.g. after a restart) unless a newer row is already stored
+// Rows of other dates than the current market date are ignored
+func (s *LatestStore) Seed(ticker, date string, timestamp float64, fields map[string]interface{}) {
+	if date != currentStoreDate() {
+		return
+	}
+	s.put(ticker, LatestSnapshot{Date: date, Timestamp: timestamp, Fields: fields})
+}
+
+func (s *LatestStore) put(ticker string, snapshot LatestSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current, ok := s.snapshots[ticker]; ok && current.Date == snapshot.Date && current.Timestamp > snapshot.Timestamp {
+		return
+	}
+	s.snapshots[ticker] = snapshot
+}
+
+// Get returns a ticker's snapshot for a market date ("2006-01-02")
+func (s *LatestStore) Get(ticker, date string) (LatestSnapshot, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	snapshot, ok := s.snapshots[ticker]
+	if !ok || snapshot.Date != date {
+		return LatestSnapshot{}, false
+	}
+	return snapshot, true
+}
+
+// Current returns a ticker's latest row if it is from the current market date
+func (s *LatestStore) Current(ticker string) (LatestSnapshot, bool) {
+	return s.Get(ticker, currentStoreDate())
+}
+
+// currentStoreDate is the market date new rows are filed under (the writer's rule: weekends go to the last trading day)
+func currentStoreDate() string {
+	marketDate := utils.GetMarketDate()
+	date := time.Date(marketDate.Year(), marketDate.Month(), marketDate.Day(), 0, 0, 0, 0, utils.GetMarketTimezone())
+	if utils.IsWeekend(date) {
+		date = utils.GetLastTradingDay(date)
+	}
+	return date.Format("2006-01-02")
+}
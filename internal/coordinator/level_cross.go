@@ -0,0 +1,101 @@
+package coordinator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"market-terminal/internal/database"
+	"market-terminal/internal/utils"
+)
+
+// levelCrossFields are the levels whose crosses by spot are logged
+var levelCrossFields = []string{"zero_gamma", "major_pos_vol", "major_neg_vol"}
+
+// levelCrossState is the side of each level spot was last on (-1 below, 1 above) and the market date it belongs to
+type levelCrossState struct {
+	date  string
+	sides map[string]int
+}
+
+// LevelCrossTracker records every time spot crosses zero_gamma or a major level into the day's
+// level_cross_events table. A cross is a change of side between rows of the same market date, whether spot or
+// the level moved; touching the level without passing it is not a cross
+type LevelCrossTracker struct {
+	mu         sync.Mutex
+	writer     *database.DataWriter
+	state      map[string]*levelCrossState
+	onCross    func(ticker string, event database.LevelCrossEvent)
+	debugPrint func(string, string)
+}
+
+// NewLevelCrossTracker creates a tracker writing through writer
+func NewLevelCrossTracker(writer *database.DataWriter, debugPrint func(string, string)) *LevelCrossTracker {
+	return &LevelCrossTracker{
+		writer:     writer,
+		state:      make(map[string]*levelCrossState),
+		debugPrint: debugPrint,
+	}
+}
+
+// SetOnCross sets the callback run for each recorded cross (e.g. to notify the UI)
+func (lt *LevelCrossTracker) SetOnCross(fn func(ticker string, event database.LevelCrossEvent)) {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	lt.onCross = fn
+}
+
+// Track compares a collected row with the ticker's previous one and stores any crosses
+func (lt *LevelCrossTracker) Track(ticker string, timestamp float64, now time.Time, data map[string]interface{}) {
+	spot, ok := data["spot"].(float64)
+	if !ok || spot <= 0 {
+		return
+	}
+	marketDate := utils.GetMarketDateAt(now)
+	date := marketDate.Format("2006-01-02")
+
+	lt.mu.Lock()
+	state := lt.state[ticker]
+	if state == nil || state.date != date {
+		// A new day starts without a side, so the first row never counts as a cross
+		state = &levelCrossState{date: date, sides: make(map[string]int, len(levelCrossFields))}
+		lt.state[ticker] = state
+	}
+	events := make([]database.LevelCrossEvent, 0)
+	for _, field := range levelCrossFields {
+		level, ok := data[field].(float64)
+		if !ok || level <= 0 || spot == level {
+			continue // Sitting on the level keeps the side spot came from
+		}
+		side := 1
+		if spot < level {
+			side = -1
+		}
+		previous := state.sides[field]
+		state.sides[field] = side
+		if previous == 0 || previous == side {
+			continue
+		}
+		direction := database.CrossUp
+		if side < 0 {
+			direction = database.CrossDown
+		}
+		events = append(events, database.LevelCrossEvent{Timestamp: timestamp, Level: field, Direction: direction, Spot: spot, LevelValue: level})
+	}
+	onCross := lt.onCross
+	lt.mu.Unlock()
+
+	if len(events) == 0 {
+		return
+	}
+	if err := lt.writer.AddLevelCrossEvents(ticker, marketDate, events); err != nil {
+		lt.debugPrint(fmt.Sprintf("Failed to record level crosses for %s: %v", ticker, err), "error")
+		return
+	}
+	for _, event := range events {
+		lt.debugPrint(fmt.Sprintf("%s spot %.2f crossed %s %s (%.2f)", ticker, spot, event.Level, event.Direction, event.LevelValue), "coordinator")
+		if onCross != nil {
+			onCross(ticker, event)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package coordinator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/database"
+)
+
+// FieldSource records which endpoint a merged field was taken from (GetFieldSources, for debugging)
+type FieldSource struct {
+	Endpoint  string   `json:"endpoint"`
+	Timestamp float64  `json:"timestamp"`          // API timestamp of that response in seconds (0 = none)
+	Rejected  []string `json:"rejected,omitempty"` // Other endpoints that also returned the field
+	Cached    bool     `json:"cached,omitempty"`   // From the endpoint's last successful response (this fetch failed)
+}
+
+// fieldSourcePriority lists the preferred endpoints for fields several endpoints return, best first
+// Fields not listed (and endpoints not listed for a field) fall back to expiryRank
+var fieldSourcePriority = map[string][]string{
+	"spot":       {"classic_zero", "state_zero", "classic_full", "state_full", "classic_one", "state_one"},
+	"zero_gamma": {"classic_zero", "state_zero", "gamma_zero", "classic_full", "state_full", "classic_one", "state_one", "gamma_one"},
+}
+
+// mergeMetadataKeys are per-response keys that aren't row fields
+var mergeMetadataKeys = map[string]bool{"_response_headers": true, "_response_time": true, "_api_key_name": true}
+
+// fieldMerger combines one ticker's endpoint responses into a single row, field by field: the freshest
+// response wins, and responses within MergeTimestampToleranceSec of each other are ranked by source priority
+// The result doesn't depend on the order responses are added in
+type fieldMerger struct {
+	data    map[string]interface{}
+	sources map[string]FieldSource
+	stale   []string // "field: endpoint (Ns older than source)" for values dropped because they were older
+	slowest float64  // Longest response time of the fresh responses, in seconds
+}
+
+func newFieldMerger() *fieldMerger {
+	return &fieldMerger{
+		data:    make(map[string]interface{}),
+		sources: make(map[string]FieldSource),
+	}
+}
+
+// add merges one endpoint's response
+func (m *fieldMerger) add(endpoint string, result map[string]interface{}) {
+	m.merge(endpoint, result, false)
+}
+
+// addCached merges an endpoint's cached response in place of a failed fetch; its older API timestamp
+// means it only fills fields no fresh response returned
+func (m *fieldMerger) addCached(endpoint string, result map[string]interface{}) {
+	m.merge(endpoint, result, true)
+}
+
+func (m *fieldMerger) merge(endpoint string, result map[string]interface{}, cached bool) {
+	result = database.GroupMaxChange(endpoint, result) // Max-change endpoints share field names; keep each one's own
+	timestamp, _ := apiTimestampSeconds(result)
+	if responseTime, ok := result["_response_time"].(float64); ok && !cached && responseTime > m.slowest {
+		m.slowest = responseTime
+	}
+	for key, value := range result {
+		if mergeMetadataKeys[key] {
+			continue
+		}
+		current, exists := m.sources[key]
+		if !exists {
+			m.data[key] = value
+			m.sources[key] = FieldSource{Endpoint: endpoint, Timestamp: timestamp, Cached: cached}
+			continue
+		}
+
+		candidate := FieldSource{Endpoint: endpoint, Timestamp: timestamp, Cached: cached}
+		winner, loser := current, candidate
+		if preferSource(key, candidate, current) {
+			winner, loser = candidate, current
+			m.data[key] = value
+		}
+		if key != "timestamp" && !loser.Cached && winner.Timestamp-loser.Timestamp > config.MergeTimestampToleranceSec && loser.Timestamp > 0 {
+			m.stale = append(m.stale, fmt.Sprintf("%s: %s (%.0fs older than %s)", key, loser.Endpoint, winner.Timestamp-loser.Timestamp, winner.Endpoint))
+		}
+		rejected := append(append([]string{}, current.Rejected...), loser.Endpoint)
+		sort.Strings(rejected)
+		m.sources[key] = FieldSource{Endpoint: winner.Endpoint, Timestamp: winner.Timestamp, Rejected: dedupe(rejected, winner.Endpoint), Cached: winner.Cached}
+	}
+}
+
+// qualityFlags returns the database quality flags for the merged row: back-filled fields and delayed fetches
+func (m *fieldMerger) qualityFlags() int {
+	flags := 0
+	for field, source := range m.sources {
+		if !source.Cached {
+			continue
+		}
+		if field == "spot" {
+			flags |= database.QualitySpotBackfilled
+		} else if field != "timestamp" {
+			flags |= database.QualityFieldsBackfilled
+		}
+	}
+	if m.slowest > config.QualityDelayedFetchSec {
+		flags |= database.QualityDelayedFetch
+	}
+	return flags
+}
+
+// preferSource reports whether candidate should replace current as the source of field
+func preferSource(field string, candidate, current FieldSource) bool {
+	// The freshest response wins (a response without a timestamp only wins on priority against another one)
+	if diff := candidate.Timestamp - current.Timestamp; diff > config.MergeTimestampToleranceSec || diff < -config.MergeTimestampToleranceSec {
+		return diff > 0
+	}
+	candidateRank, currentRank := sourceRank(field, candidate.Endpoint), sourceRank(field, current.Endpoint)
+	if candidateRank != currentRank {
+		return candidateRank < currentRank
+	}
+	return candidate.Endpoint < current.Endpoint // Deterministic tie-break
+}
+
+// sourceRank ranks an endpoint as the source of a field (lower is better)
+func sourceRank(field, endpoint string) int {
+	if preferred, ok := fieldSourcePriority[field]; ok {
+		for i, name := range preferred {
+			if name == endpoint {
+				return i
+			}
+		}
+		return len(preferred) + expiryRank(endpoint)
+	}
+	return expiryRank(endpoint)
+}
+
+// expiryRank prefers the 0DTE endpoints (updated most often), then full, then next-expiry
+func expiryRank(endpoint string) int {
+	switch {
+	case strings.Contains(endpoint, "_zero"):
+		return 0
+	case strings.Contains(endpoint, "_full"):
+		return 1
+	case strings.Contains(endpoint, "_one"):
+		return 2
+	default:
+		return 3
+	}
+}
+
+func dedupe(endpoints []string, except string) []string {
+	result := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if endpoint != except && (len(result) == 0 || result[len(result)-1] != endpoint) {
+			result = append(result, endpoint)
+		}
+	}
+	return result
+}
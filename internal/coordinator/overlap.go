@@ -0,0 +1,81 @@
+package coordinator
+
+import (
+	"sort"
+
+	"market-terminal/internal/config"
+)
+
+// BatchOverlapStatus counts the overlapping batches the coordinator avoided: a ticker due again while its
+// previous batch was still fetching (a slow API or a fetch slower than the ticker's interval)
+type BatchOverlapStatus struct {
+	Policy        string           `json:"policy"`          // batch_overlap_policy (skip or queue)
+	Avoided       int64            `json:"avoided"`         // Ticker fetches not started because one was in flight
+	Queued        int64            `json:"queued"`          // Follow-up fetches queued (queue policy; repeats coalesce)
+	Reruns        int64            `json:"reruns"`          // Queued follow-up fetches that ran
+	PerTicker     map[string]int64 `json:"per_ticker"`      // ticker -> overlaps avoided
+	LastOverlapAt float64          `json:"last_overlap_at"` // Unix seconds (0 = none yet)
+	InFlight      []string         `json:"in_flight"`       // Tickers being fetched right now
+	Pending       []string         `json:"pending"`         // Tickers with a queued follow-up fetch
+}
+
+// overlapStats are the counters behind BatchOverlapStatus (guarded by inProgressLock)
+type overlapStats struct {
+	avoided       int64
+	queued        int64
+	reruns        int64
+	perTicker     map[string]int64
+	lastOverlapAt float64
+}
+
+// SetBatchOverlapPolicy sets what happens to a ticker that is due while its previous batch is still running
+// (config.BatchOverlapSkip or config.BatchOverlapQueue); switching to skip drops queued follow-ups
+func (dcc *DataCollectionCoordinator) SetBatchOverlapPolicy(policy string) {
+	dcc.inProgressLock.Lock()
+	defer dcc.inProgressLock.Unlock()
+	dcc.overlapPolicy = policy
+	if policy != config.BatchOverlapQueue {
+		dcc.overlapPending = make(map[string]bool)
+	}
+}
+
+// GetBatchOverlapStatus returns the overlap policy, the overlaps avoided so far and the tickers in flight
+func (dcc *DataCollectionCoordinator) GetBatchOverlapStatus() BatchOverlapStatus {
+	dcc.inProgressLock.RLock()
+	defer dcc.inProgressLock.RUnlock()
+
+	status := BatchOverlapStatus{
+		Policy:        dcc.overlapPolicy,
+		Avoided:       dcc.overlaps.avoided,
+		Queued:        dcc.overlaps.queued,
+		Reruns:        dcc.overlaps.reruns,
+		PerTicker:     make(map[string]int64, len(dcc.overlaps.perTicker)),
+		LastOverlapAt: dcc.overlaps.lastOverlapAt,
+		InFlight:      make([]string, 0, len(dcc.tickersInProgress)),
+		Pending:       make([]string, 0, len(dcc.overlapPending)),
+	}
+	for ticker, count := range dcc.overlaps.perTicker {
+		status.PerTicker[ticker] = count
+	}
+	for ticker := range dcc.tickersInProgress {
+		status.InFlight = append(status.InFlight, ticker)
+	}
+	for ticker := range dcc.overlapPending {
+		status.Pending = append(status.Pending, ticker)
+	}
+	sort.Strings(status.InFlight)
+	sort.Strings(status.Pending)
+	return status
+}
+
+// runQueuedBatch fetches the tickers whose follow-up was queued while their batch was running
+// (unless collection is shutting down or rate limited - the next interval fetches them then)
+func (dcc *DataCollectionCoordinator) runQueuedBatch(tickers []string) {
+	if dcc.getShuttingDown() || dcc.scheduler.GetRateLimitTracker().IsRateLimited() {
+		return
+	}
+	dcc.inProgressLock.Lock()
+	dcc.overlaps.reruns += int64(len(tickers))
+	dcc.inProgressLock.Unlock()
+	dcc.processTickerBatch(tickers, "overlap_queue")
+}
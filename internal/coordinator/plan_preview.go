@@ -0,0 +1,92 @@
+package coordinator
+
+import (
+	"sort"
+
+	"market-terminal/internal/api"
+)
+
+// QueryPlanPreview is what the planner would fetch right now for a ticker configuration (nothing is fetched)
+type QueryPlanPreview struct {
+	Tickers                []TickerPlanPreview `json:"tickers"`
+	TickerCount            int                 `json:"ticker_count"`              // Enabled plus previewed tickers (drives the interval matrix)
+	RequestsPerMinute      float64             `json:"requests_per_minute"`       // All tickers in the preview
+	AddedRequestsPerMinute float64             `json:"added_requests_per_minute"` // Tickers that aren't enabled yet
+	RateLimit              int                 `json:"rate_limit"`                // Requests allowed per minute (0 = not yet known)
+	QuotaPercent           float64             `json:"quota_percent"`             // RequestsPerMinute as a share of RateLimit (0 = limit unknown)
+}
+
+// TickerPlanPreview is one ticker of a QueryPlanPreview
+type TickerPlanPreview struct {
+	Ticker            string   `json:"ticker"`
+	Enabled           bool     `json:"enabled"`
+	Endpoints         []string `json:"endpoints"`
+	IntervalSec       float64  `json:"interval_sec"`
+	RequestsPerMinute float64  `json:"requests_per_minute"`
+}
+
+// PreviewQueryPlan returns the queries the planner would issue for the enabled tickers plus the given ones,
+// with the polling interval each would get and the resulting request rate; nothing is fetched
+// Open circuits are ignored (they are temporary), so the estimate is the steady-state cost
+func (dcc *DataCollectionCoordinator) PreviewQueryPlan(tickers []string) QueryPlanPreview {
+	enabled := dcc.scheduler.GetEnabledTickers()
+	isEnabled := make(map[string]bool, len(enabled))
+	all := make([]string, 0, len(enabled)+len(tickers))
+	for _, ticker := range enabled {
+		if !isEnabled[ticker] {
+			isEnabled[ticker] = true
+			all = append(all, ticker)
+		}
+	}
+	added := make(map[string]bool)
+	for _, ticker := range tickers {
+		if ticker != "" && !isEnabled[ticker] && !added[ticker] {
+			added[ticker] = true
+			all = append(all, ticker)
+		}
+	}
+	sort.Strings(all)
+
+	dcc.mu.RLock()
+	plan := dcc.queryPlanner.PreviewPlan(all)
+	dcc.mu.RUnlock()
+
+	planItems := make([]api.QueryPlanItem, 0, len(plan))
+	for _, item := range plan {
+		planItems = append(planItems, api.QueryPlanItem{Ticker: item.Ticker, Endpoints: item.Endpoints})
+	}
+	endpoints := make(map[string][]string, len(all))
+	for _, query := range dcc.querySystem.ValidateAndFilterQueries(planItems) {
+		endpoints[query.Ticker] = append(endpoints[query.Ticker], query.Endpoint)
+	}
+
+	openCharts := dcc.getOpenCharts()
+	preview := QueryPlanPreview{
+		Tickers:     make([]TickerPlanPreview, 0, len(all)),
+		TickerCount: len(all),
+		RateLimit:   dcc.scheduler.GetRateLimitTracker().GetStatus().Limit,
+	}
+	for _, ticker := range all {
+		item := TickerPlanPreview{
+			Ticker:      ticker,
+			Enabled:     isEnabled[ticker],
+			Endpoints:   endpoints[ticker],
+			IntervalSec: dcc.scheduler.EstimateInterval(ticker, openCharts, len(all)),
+		}
+		if item.Endpoints == nil {
+			item.Endpoints = []string{}
+		}
+		if item.IntervalSec > 0 {
+			item.RequestsPerMinute = float64(len(item.Endpoints)) * 60.0 / item.IntervalSec
+		}
+		preview.RequestsPerMinute += item.RequestsPerMinute
+		if !item.Enabled {
+			preview.AddedRequestsPerMinute += item.RequestsPerMinute
+		}
+		preview.Tickers = append(preview.Tickers, item)
+	}
+	if preview.RateLimit > 0 {
+		preview.QuotaPercent = preview.RequestsPerMinute / float64(preview.RateLimit) * 100
+	}
+	return preview
+}
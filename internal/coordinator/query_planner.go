@@ -38,30 +38,12 @@ var endpointPlotsMap = map[string][]string{
 	"classic_zero":        {"spot", "zero_gamma"},
 	"classic_zero_majors": {"major_pos_vol", "major_neg_vol", "major_positive", "major_negative", "major_pos_oi", "major_neg_oi", "major_long_gamma", "major_short_gamma"},
 	"gamma_zero":          {"zero_gamma", "major_long_gamma", "major_short_gamma"},
+	"orderflow":           {"delta", "volume"},
 }
 
 // BuildOptimizedPlan builds an optimized query plan for the given tickers
 func (sqp *SmartQueryPlanner) BuildOptimizedPlan(tickersToFetch []string) []QueryPlanItem {
-	// Get endpoints based on subscription tiers and collection mode
-	tiers := sqp.settings.APISubscriptionTiers
-	if len(tiers) == 0 {
-		tiers = []string{"classic"}
-	}
-
-	var endpoints []string
-	if sqp.settings.CollectAllEndpoints {
-		// Collect all available endpoints for the user's subscription tiers
-		endpoints = api.GetEndpointsForTiers(tiers)
-	} else {
-		// Only collect endpoints needed for chart display
-		endpoints = api.GetChartEndpointsForTiers(tiers)
-		
-		// When in chart-only mode, filter out endpoints where ALL plots are hidden
-		hiddenPlots := sqp.settings.HiddenPlots
-		if len(hiddenPlots) > 0 {
-			endpoints = sqp.filterEndpointsByHiddenPlots(endpoints, hiddenPlots)
-		}
-	}
+	endpoints := sqp.planEndpoints()
 
 	// Build plan
 	plan := make([]QueryPlanItem, 0)
@@ -87,6 +69,60 @@ func (sqp *SmartQueryPlanner) BuildOptimizedPlan(tickersToFetch []string) []Quer
 	return plan
 }
 
+// PreviewPlan builds the plan BuildOptimizedPlan would build for the tickers if they were all enabled
+func (sqp *SmartQueryPlanner) PreviewPlan(tickers []string) []QueryPlanItem {
+	endpoints := sqp.planEndpoints()
+	plan := make([]QueryPlanItem, 0, len(tickers))
+	for _, ticker := range tickers {
+		plan = append(plan, QueryPlanItem{
+			Ticker:    ticker,
+			Endpoints: endpoints,
+		})
+	}
+	return plan
+}
+
+// planEndpoints returns the endpoints fetched for every ticker
+func (sqp *SmartQueryPlanner) planEndpoints() []string {
+	// Get endpoints based on subscription tiers and collection mode
+	tiers := sqp.subscriptionTiers()
+
+	var endpoints []string
+	if sqp.settings.CollectAllEndpoints {
+		// Collect all available endpoints for the user's subscription tiers
+		endpoints = api.GetEndpointsForTiers(tiers)
+	} else {
+		// Only collect endpoints needed for chart display
+		endpoints = api.GetChartEndpointsForTiers(tiers)
+		
+		// When in chart-only mode, filter out endpoints where ALL plots are hidden
+		hiddenPlots := sqp.settings.HiddenPlots
+		if len(hiddenPlots) > 0 {
+			endpoints = sqp.filterEndpointsByHiddenPlots(endpoints, hiddenPlots)
+		}
+	}
+	return endpoints
+}
+
+// ChartEndpoints returns the endpoints chart display needs for the subscription tiers (spot and key levels),
+// regardless of collection mode
+func (sqp *SmartQueryPlanner) ChartEndpoints() []string {
+	return api.GetChartEndpointsForTiers(sqp.subscriptionTiers())
+}
+
+// subscriptionTiers returns the configured API subscription tiers (classic when none are set)
+func (sqp *SmartQueryPlanner) subscriptionTiers() []string {
+	if len(sqp.settings.APISubscriptionTiers) == 0 {
+		return []string{"classic"}
+	}
+	return sqp.settings.APISubscriptionTiers
+}
+
+// PlannedEndpoints returns the endpoints collection would fetch per ticker with the given settings
+func PlannedEndpoints(settings *config.Settings) []string {
+	return NewSmartQueryPlanner(settings, nil, nil).planEndpoints()
+}
+
 // filterEndpointsByHiddenPlots filters out endpoints where ALL plots are hidden
 // An endpoint is only skipped if every plot it provides is in the hiddenPlots list
 func (sqp *SmartQueryPlanner) filterEndpointsByHiddenPlots(endpoints []string, hiddenPlots []string) []string {
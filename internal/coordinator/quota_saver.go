@@ -0,0 +1,162 @@
+package coordinator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"market-terminal/internal/scheduler"
+	"market-terminal/internal/utils"
+)
+
+// QuotaSaverStatus reports whether background tickers were switched to the chart endpoints to save quota
+type QuotaSaverStatus struct {
+	Enabled     bool                      `json:"enabled"`
+	Active      bool                      `json:"active"`       // Tickers not open in a chart fetch only chart endpoints
+	MarketDate  string                    `json:"market_date"`  // Market date the status belongs to (the switch lasts until it changes)
+	ActivatedAt float64                   `json:"activated_at"` // Unix seconds (0 = not active)
+	Projection  scheduler.QuotaProjection `json:"projection"`   // Projection that triggered the switch, or the latest one
+	Skipped     int64                     `json:"skipped"`      // Endpoint requests left out for background tickers today
+}
+
+// QuotaSaver switches tickers that aren't open in a chart to the chart endpoint subset once the API quota is
+// projected to run out before market close. The subset keeps spot and the key levels, so those series stay
+// continuous; the switch lasts for the rest of the market date rather than flapping with the request rate
+type QuotaSaver struct {
+	mu          sync.Mutex
+	enabled     bool
+	active      bool
+	marketDate  string
+	activatedAt time.Time
+	projection  scheduler.QuotaProjection
+	skipped     int64
+	onActivate  func(QuotaSaverStatus) // Called when background tickers are switched (e.g. to notify the UI)
+	debugPrint  func(string, string)
+}
+
+// NewQuotaSaver creates an enabled quota saver
+func NewQuotaSaver(debugPrint func(string, string)) *QuotaSaver {
+	return &QuotaSaver{enabled: true, debugPrint: debugPrint}
+}
+
+// SetEnabled enables or disables the quota saver; disabling restores the full plan immediately
+func (qs *QuotaSaver) SetEnabled(enabled bool) {
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+	qs.enabled = enabled
+	if !enabled {
+		qs.active = false
+		qs.activatedAt = time.Time{}
+	}
+}
+
+// SetOnActivate sets the callback for when background tickers are switched to the chart endpoints
+func (qs *QuotaSaver) SetOnActivate(onActivate func(QuotaSaverStatus)) {
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+	qs.onActivate = onActivate
+}
+
+// Evaluate projects the quota from the tracker and switches to the chart subset when it would run out before
+// today's market close. A new market date resets the switch
+func (qs *QuotaSaver) Evaluate(tracker *scheduler.RateLimitTracker, now time.Time) {
+	now = now.In(utils.MARKET_TIMEZONE)
+	date := utils.GetMarketDateAt(now).Format("2006-01-02")
+	_, marketClose := utils.MarketOpenCloseTimes(now)
+
+	qs.mu.Lock()
+	if qs.marketDate != date {
+		if qs.active {
+			qs.debugPrint(fmt.Sprintf("Quota saver: New market date %s, restoring the full query plan", date), "coordinator")
+		}
+		qs.marketDate = date
+		qs.active = false
+		qs.activatedAt = time.Time{}
+		qs.skipped = 0
+	}
+	if !qs.enabled || qs.active || !now.Before(marketClose) {
+		qs.mu.Unlock()
+		return
+	}
+	qs.projection = tracker.ProjectQuota(now, marketClose)
+	if !qs.projection.Exhausts() {
+		qs.mu.Unlock()
+		return
+	}
+	qs.active = true
+	qs.activatedAt = now
+	status := qs.statusLocked()
+	onActivate := qs.onActivate
+	qs.mu.Unlock()
+
+	qs.debugPrint(fmt.Sprintf("⚠️ Quota saver: %d requests left at %.0f/min would run out at %s ET, before the close - "+
+		"tickers not open in a chart now fetch only chart endpoints for the rest of the day",
+		status.Projection.Remaining, status.Projection.RatePerMinute,
+		time.Unix(int64(status.Projection.ExhaustsAt), 0).In(utils.MARKET_TIMEZONE).Format("15:04")), "error")
+	if onActivate != nil {
+		onActivate(status)
+	}
+}
+
+// FilterPlan limits tickers that aren't open in a chart to the chart endpoints while the switch is active
+// Displayed tickers keep their full plan
+func (qs *QuotaSaver) FilterPlan(plan []QueryPlanItem, chartEndpoints []string, openCharts []interface{}) []QueryPlanItem {
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+	if !qs.active {
+		return plan
+	}
+
+	isChartEndpoint := make(map[string]bool, len(chartEndpoints))
+	for _, endpoint := range chartEndpoints {
+		isChartEndpoint[endpoint] = true
+	}
+	isOpen := make(map[string]bool, len(openCharts))
+	for _, chartTicker := range openCharts {
+		if ticker, ok := chartTicker.(string); ok {
+			isOpen[ticker] = true
+		}
+	}
+
+	filtered := make([]QueryPlanItem, 0, len(plan))
+	for _, item := range plan {
+		if isOpen[item.Ticker] {
+			filtered = append(filtered, item)
+			continue
+		}
+		endpoints := make([]string, 0, len(item.Endpoints))
+		for _, endpoint := range item.Endpoints {
+			if isChartEndpoint[endpoint] {
+				endpoints = append(endpoints, endpoint)
+			} else {
+				qs.skipped++
+			}
+		}
+		if len(endpoints) > 0 {
+			filtered = append(filtered, QueryPlanItem{Ticker: item.Ticker, Endpoints: endpoints})
+		}
+	}
+	return filtered
+}
+
+// statusLocked builds a status snapshot (caller holds mu)
+func (qs *QuotaSaver) statusLocked() QuotaSaverStatus {
+	status := QuotaSaverStatus{
+		Enabled:    qs.enabled,
+		Active:     qs.active,
+		MarketDate: qs.marketDate,
+		Projection: qs.projection,
+		Skipped:    qs.skipped,
+	}
+	if !qs.activatedAt.IsZero() {
+		status.ActivatedAt = float64(qs.activatedAt.Unix())
+	}
+	return status
+}
+
+// GetStatus returns whether background tickers are limited to the chart endpoints
+func (qs *QuotaSaver) GetStatus() QuotaSaverStatus {
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+	return qs.statusLocked()
+}
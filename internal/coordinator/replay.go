@@ -0,0 +1,114 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"market-terminal/internal/api"
+	"market-terminal/internal/config"
+	"market-terminal/internal/database"
+	"market-terminal/internal/utils"
+)
+
+// ReplayResult summarizes a ReplayDay run
+type ReplayResult struct {
+	Ticker      string `json:"ticker"`
+	Date        string `json:"date"`
+	Responses   int    `json:"responses"`    // Recorded responses read
+	Rows        int    `json:"rows"`         // Rows rewritten
+	ParseErrors int    `json:"parse_errors"` // Responses the current parser still rejects (skipped)
+}
+
+// replayGroup collects the responses of one batch (one row)
+type replayGroup struct {
+	key        string
+	merger     *fieldMerger // Merged like a live batch (freshest value, then source priority)
+	receivedAt float64
+}
+
+// ReplayDay re-runs extraction over a day's recorded raw responses and rewrites the rows they produced
+// Lets a parsing fix correct days that were collected with the bug; needs record mode to have been on that day
+// Only dates before the current market date (on the coordinator's clock) can be replayed: the current day's
+// recording and rows are still being written
+func (dcc *DataCollectionCoordinator) ReplayDay(ctx context.Context, ticker string, date time.Time) (ReplayResult, error) {
+	result := ReplayResult{Ticker: ticker, Date: date.Format("2006-01-02")}
+	if today := utils.GetMarketDateAt(dcc.clock.Now()).Format("2006-01-02"); result.Date >= today {
+		return result, fmt.Errorf("%s is not a completed market date (the current one is %s) - replay it after the rollover", result.Date, today)
+	}
+	path := dcc.dataWriter.RawResponsesPath(ticker, date)
+	if _, err := os.Stat(path); err != nil {
+		return result, fmt.Errorf("no recorded responses for %s on %s (record mode was off)", ticker, result.Date)
+	}
+
+	var group *replayGroup
+	batch := make([]database.ReplacementRow, 0, config.ReplayBatchRows)
+
+	writeBatch := func() error {
+		if err := dcc.dataWriter.ReplaceRows(ctx, ticker, date, batch); err != nil {
+			return err
+		}
+		result.Rows += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+	finishGroup := func() error {
+		if group == nil || len(group.merger.data) == 0 {
+			return nil
+		}
+		timestamp, ok := apiTimestampSeconds(group.merger.data)
+		if !ok {
+			timestamp = group.receivedAt
+		}
+		batch = append(batch, database.ReplacementRow{Timestamp: timestamp, Data: group.merger.data})
+		group = nil
+		if len(batch) >= config.ReplayBatchRows {
+			return writeBatch()
+		}
+		return nil
+	}
+
+	err := database.ScanRawResponses(path, func(response database.RawResponse) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		result.Responses++
+
+		// Responses of one batch share its trace ID; untraced ones are grouped by the second they arrived in
+		key := response.Trace
+		if key == "" {
+			key = fmt.Sprintf("t%d", int64(response.ReceivedAt))
+		}
+		if group == nil || group.key != key {
+			if err := finishGroup(); err != nil {
+				return err
+			}
+			group = &replayGroup{key: key, merger: newFieldMerger()}
+		}
+		if response.ReceivedAt > group.receivedAt {
+			group.receivedAt = response.ReceivedAt
+		}
+
+		data, err := api.ParseResponse(response.Endpoint, ticker, response.Body)
+		if err != nil {
+			result.ParseErrors++
+			return nil
+		}
+		group.merger.add(response.Endpoint, data)
+		return nil
+	})
+	if err == nil {
+		err = finishGroup()
+	}
+	if err == nil {
+		err = writeBatch()
+	}
+	if err != nil {
+		return result, fmt.Errorf("replay of %s on %s stopped after %d rows: %w", ticker, result.Date, result.Rows, err)
+	}
+
+	dcc.debugPrint(fmt.Sprintf("ReplayDay: Rewrote %d rows for %s on %s from %d recorded responses (%d unparseable)",
+		result.Rows, ticker, result.Date, result.Responses, result.ParseErrors), "coordinator")
+	return result, nil
+}
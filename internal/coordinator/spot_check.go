@@ -0,0 +1,249 @@
+package coordinator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/database"
+	"market-terminal/internal/utils"
+)
+
+// SpotSecondaryColumn is the column a divergent row's secondary quote is recorded in
+const SpotSecondaryColumn = "spot_secondary"
+
+// SpotCheckTickerStatus is the last comparison of a ticker's spot with the secondary source
+type SpotCheckTickerStatus struct {
+	Symbol      string  `json:"symbol"`
+	Spot        float64 `json:"spot"`        // GEXBot spot of the last compared row
+	Secondary   float64 `json:"secondary"`   // Secondary quote it was compared with
+	DiffPct     float64 `json:"diff_pct"`    // (spot - secondary) / secondary, in percent
+	Divergent   bool    `json:"divergent"`   // |diff_pct| exceeds the threshold
+	Divergences int     `json:"divergences"` // Rows flagged since startup
+	QuoteAt     float64 `json:"quote_at"`    // Unix seconds the secondary quote was received (0 = none yet)
+	LastError   string  `json:"last_error"`  // Last quote request failure ("" = last request succeeded)
+}
+
+// SpotCheckStatus summarizes the cross-check for the UI/diagnostics
+type SpotCheckStatus struct {
+	Enabled      bool                             `json:"enabled"`
+	ThresholdPct float64                          `json:"threshold_pct"`
+	Divergent    []string                         `json:"divergent"` // Tickers whose spot currently diverges
+	Tickers      map[string]SpotCheckTickerStatus `json:"tickers"`
+}
+
+type spotQuote struct {
+	price      float64
+	receivedAt time.Time
+}
+
+// SpotChecker compares each collected row's spot with a secondary quote source (spot_check)
+// Quotes are requested in the background at most every interval_sec per ticker, so the collection path never waits
+// on the secondary source; a row is compared with the latest quote that is at most two intervals old
+type SpotChecker struct {
+	mu           sync.Mutex
+	settings     config.SpotCheckSettings
+	client       *http.Client
+	quotes       map[string]spotQuote
+	requestedAt  map[string]time.Time // ticker -> last quote request (in flight or done)
+	status       map[string]*SpotCheckTickerStatus
+	onDivergence func(ticker string, status SpotCheckTickerStatus) // Called when a ticker starts diverging
+	clock        utils.Clock                                       // Source of "now" for quote ages (the coordinator's clock)
+	debugPrint   func(string, string)
+}
+
+// NewSpotChecker creates a spot checker (disabled until SetSettings enables it)
+func NewSpotChecker(debugPrint func(string, string)) *SpotChecker {
+	return &SpotChecker{
+		client:      &http.Client{Timeout: time.Duration(config.SpotCheckRequestTimeoutSec) * time.Second},
+		quotes:      make(map[string]spotQuote),
+		requestedAt: make(map[string]time.Time),
+		status:      make(map[string]*SpotCheckTickerStatus),
+		clock:       utils.InstalledClock,
+		debugPrint:  debugPrint,
+	}
+}
+
+// SetSettings applies the spot_check settings; cached quotes are dropped so a changed source starts fresh
+func (sc *SpotChecker) SetSettings(settings config.SpotCheckSettings) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.settings = settings
+	sc.quotes = make(map[string]spotQuote)
+	sc.requestedAt = make(map[string]time.Time)
+	if !settings.Enabled {
+		sc.status = make(map[string]*SpotCheckTickerStatus)
+	}
+}
+
+// SetOnDivergence sets the callback for a ticker whose spot starts diverging (e.g. to warn in the UI)
+func (sc *SpotChecker) SetOnDivergence(onDivergence func(ticker string, status SpotCheckTickerStatus)) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.onDivergence = onDivergence
+}
+
+// Check compares a collected row's spot with the latest secondary quote, flagging the row (and recording the
+// quote in spot_secondary) when they diverge beyond the threshold; a new quote is requested when one is due
+func (sc *SpotChecker) Check(ticker string, data map[string]interface{}, now time.Time) {
+	sc.mu.Lock()
+	settings := sc.settings
+	if !settings.Checks(ticker) {
+		sc.mu.Unlock()
+		return
+	}
+	interval := time.Duration(settings.GetIntervalSec()) * time.Second
+	if now.Sub(sc.requestedAt[ticker]) >= interval {
+		sc.requestedAt[ticker] = now
+		go sc.fetchQuote(ticker, settings)
+	}
+
+	spot, hasSpot := data["spot"].(float64)
+	quote, hasQuote := sc.quotes[ticker]
+	if !hasSpot || spot <= 0 || !hasQuote || now.Sub(quote.receivedAt) > 2*interval {
+		sc.mu.Unlock()
+		return // Nothing to compare (yet)
+	}
+
+	status := sc.tickerStatusLocked(ticker, settings)
+	wasDivergent := status.Divergent
+	status.Spot, status.Secondary = spot, quote.price
+	status.DiffPct = (spot - quote.price) / quote.price * 100
+	status.Divergent = math.Abs(status.DiffPct) > settings.GetThresholdPct()
+	if status.Divergent {
+		status.Divergences++
+		database.AddQualityFlags(data, database.QualitySpotDivergent)
+		data[SpotSecondaryColumn] = quote.price
+	}
+	snapshot := *status
+	onDivergence := sc.onDivergence
+	sc.mu.Unlock()
+
+	if snapshot.Divergent && !wasDivergent {
+		sc.debugPrint(fmt.Sprintf("⚠️ Spot check: %s spot %.2f is %.2f%% off the secondary source (%s %.2f) - GEXBot spot may be stale",
+			ticker, snapshot.Spot, snapshot.DiffPct, snapshot.Symbol, snapshot.Secondary), "error")
+		if onDivergence != nil {
+			onDivergence(ticker, snapshot)
+		}
+	} else if !snapshot.Divergent && wasDivergent {
+		sc.debugPrint(fmt.Sprintf("Spot check: %s spot back within %.2f%% of the secondary source", ticker, settings.GetThresholdPct()), "coordinator")
+	}
+}
+
+// fetchQuote requests a ticker's secondary quote and stores it for the next comparison
+func (sc *SpotChecker) fetchQuote(ticker string, settings config.SpotCheckSettings) {
+	symbol := settings.Symbol(ticker)
+	price, err := sc.requestQuote(symbol, settings)
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.settings.URLTemplate != settings.URLTemplate || sc.settings.PriceField != settings.PriceField {
+		return // Source changed while the request was in flight
+	}
+	status := sc.tickerStatusLocked(ticker, settings)
+	if err != nil {
+		if status.LastError == "" {
+			sc.debugPrint(fmt.Sprintf("Spot check: Quote request for %s (%s) failed: %v", ticker, symbol, err), "error")
+		}
+		status.LastError = err.Error()
+		return
+	}
+	receivedAt := sc.clock.Now()
+	status.LastError = ""
+	status.QuoteAt = float64(receivedAt.UnixNano()) / 1e9
+	sc.quotes[ticker] = spotQuote{price: price, receivedAt: receivedAt}
+}
+
+// requestQuote fetches one quote and extracts the price
+func (sc *SpotChecker) requestQuote(symbol string, settings config.SpotCheckSettings) (float64, error) {
+	request, err := http.NewRequest(http.MethodGet, strings.ReplaceAll(settings.URLTemplate, "{symbol}", url.PathEscape(symbol)), nil)
+	if err != nil {
+		return 0, err
+	}
+	for name, value := range settings.Headers {
+		request.Header.Set(name, value)
+	}
+	response, err := sc.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("HTTP %d", response.StatusCode)
+	}
+
+	var body interface{}
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("invalid JSON: %w", err)
+	}
+	price, ok := quotePrice(body, settings.GetPriceField())
+	if !ok || price <= 0 {
+		return 0, fmt.Errorf("no price at %q in the response", settings.GetPriceField())
+	}
+	return price, nil
+}
+
+// quotePrice follows a dotted path ("quote.last", "data.0.price") to a number or numeric string
+func quotePrice(body interface{}, path string) (float64, bool) {
+	for _, part := range strings.Split(path, ".") {
+		switch node := body.(type) {
+		case map[string]interface{}:
+			body = node[part]
+		case []interface{}:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(node) {
+				return 0, false
+			}
+			body = node[index]
+		default:
+			return 0, false
+		}
+	}
+	switch value := body.(type) {
+	case float64:
+		return value, true
+	case string:
+		price, err := strconv.ParseFloat(value, 64)
+		return price, err == nil
+	}
+	return 0, false
+}
+
+// tickerStatusLocked returns a ticker's status entry, creating it (caller holds mu)
+func (sc *SpotChecker) tickerStatusLocked(ticker string, settings config.SpotCheckSettings) *SpotCheckTickerStatus {
+	status, ok := sc.status[ticker]
+	if !ok {
+		status = &SpotCheckTickerStatus{}
+		sc.status[ticker] = status
+	}
+	status.Symbol = settings.Symbol(ticker)
+	return status
+}
+
+// GetStatus returns the last comparison per ticker
+func (sc *SpotChecker) GetStatus() SpotCheckStatus {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	status := SpotCheckStatus{
+		Enabled:      sc.settings.Enabled,
+		ThresholdPct: sc.settings.GetThresholdPct(),
+		Divergent:    make([]string, 0),
+		Tickers:      make(map[string]SpotCheckTickerStatus, len(sc.status)),
+	}
+	for ticker, tickerStatus := range sc.status {
+		status.Tickers[ticker] = *tickerStatus
+		if tickerStatus.Divergent {
+			status.Divergent = append(status.Divergent, ticker)
+		}
+	}
+	sort.Strings(status.Divergent)
+	return status
+}
@@ -0,0 +1,168 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"market-terminal/internal/api"
+	"market-terminal/internal/crash"
+	"market-terminal/internal/tracing"
+)
+
+// fetchJob is a single endpoint fetch submitted to the worker pool
+type fetchJob struct {
+	query  api.Query
+	done   func(result map[string]interface{}, err error)
+	trace  *tracing.Trace // Trace of the submitting batch (nil if untraced)
+	queued time.Time      // When the job was submitted (fetch spans include the wait for a worker)
+}
+
+// FetchWorkerPool is a persistent pool of API fetch workers shared by all batches
+// Replaces per-batch goroutines + semaphore so total concurrency is bounded globally
+type FetchWorkerPool struct {
+	mu         sync.Mutex
+	submitMu   sync.RWMutex // Held (read) by Submit while queueing; Stop takes it to wait out in-flight submits
+	jobs       chan fetchJob
+	workers    int
+	fetch      func(ctx context.Context, endpoint, ticker string) (map[string]interface{}, error)
+	debugPrint func(string, string)
+	wg         sync.WaitGroup
+	stopChan   chan struct{}
+	ctx        context.Context // Passed to every fetch; cancelled by Stop
+	cancel     context.CancelFunc
+	isRunning  bool
+}
+
+// NewFetchWorkerPool creates a new worker pool (call Start before submitting)
+func NewFetchWorkerPool(
+	workers int,
+	fetch func(ctx context.Context, endpoint, ticker string) (map[string]interface{}, error),
+	debugPrint func(string, string),
+) *FetchWorkerPool {
+	if workers < 1 {
+		workers = 1
+	}
+	return &FetchWorkerPool{
+		jobs:       make(chan fetchJob, workers*2),
+		workers:    workers,
+		fetch:      fetch,
+		debugPrint: debugPrint,
+		stopChan:   make(chan struct{}),
+	}
+}
+
+// Start starts the worker goroutines
+func (p *FetchWorkerPool) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.isRunning {
+		return
+	}
+	p.isRunning = true
+	p.stopChan = make(chan struct{})
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+
+	for i := 0; i < p.workers; i++ {
+		p.wg.Add(1)
+		go p.worker(p.stopChan)
+	}
+
+	p.debugPrint(fmt.Sprintf("Fetch worker pool started with %d workers", p.workers), "coordinator")
+}
+
+// Stop stops the workers, cancelling in-flight fetches (they return a context error instead of
+// finishing their retries) - jobs still queued are completed with an error so waiting batches don't hang
+func (p *FetchWorkerPool) Stop() {
+	p.mu.Lock()
+	if !p.isRunning {
+		p.mu.Unlock()
+		return
+	}
+	p.isRunning = false
+	close(p.stopChan)
+	p.cancel()
+	p.mu.Unlock()
+
+	// Wait for submits that saw the pool running - after this none can queue a job the drain below misses
+	p.submitMu.Lock()
+	p.submitMu.Unlock()
+
+	p.wg.Wait()
+
+	// Fail anything left in the queue
+	for {
+		select {
+		case job := <-p.jobs:
+			job.done(nil, fmt.Errorf("fetch worker pool stopped"))
+		default:
+			p.debugPrint("Fetch worker pool stopped", "coordinator")
+			return
+		}
+	}
+}
+
+// Submit queues a fetch; done is called from a worker goroutine when it completes
+// ctx only carries the batch's trace - fetches are cancelled by Stop, not by ctx
+// Returns false (without calling done) if the pool isn't running
+func (p *FetchWorkerPool) Submit(ctx context.Context, query api.Query, done func(result map[string]interface{}, err error)) bool {
+	p.submitMu.RLock()
+	defer p.submitMu.RUnlock()
+
+	p.mu.Lock()
+	running := p.isRunning
+	stopChan := p.stopChan
+	p.mu.Unlock()
+
+	if !running {
+		return false
+	}
+
+	select {
+	case p.jobs <- fetchJob{query: query, done: done, trace: tracing.FromContext(ctx), queued: time.Now()}:
+		return true
+	case <-stopChan:
+		return false
+	}
+}
+
+// worker runs fetch jobs until stopped
+func (p *FetchWorkerPool) worker(stopChan chan struct{}) {
+	defer p.wg.Done()
+
+	p.mu.Lock()
+	ctx := p.ctx
+	p.mu.Unlock()
+
+	for {
+		select {
+		case job := <-p.jobs:
+			p.run(ctx, job)
+		case <-stopChan:
+			return
+		}
+	}
+}
+
+// run executes a single job, recovering from panics so a worker is never lost
+func (p *FetchWorkerPool) run(ctx context.Context, job fetchJob) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("FetchWorkerPool: PANIC fetching %s for %s: %v", job.query.Endpoint, job.query.Ticker, r)
+			crash.Report("fetch worker", r)
+			job.done(nil, fmt.Errorf("panic fetching %s for %s: %v", job.query.Endpoint, job.query.Ticker, r))
+		}
+	}()
+
+	span := job.trace.StartSpanAt("fetch", job.query.Ticker, job.queued)
+	span.SetAttr("endpoint", job.query.Endpoint)
+	span.SetAttr("queue_wait_ms", fmt.Sprintf("%d", time.Since(job.queued).Milliseconds()))
+
+	// The fetch sees the batch's trace (raw response recording groups a row's responses by it)
+	result, err := p.fetch(tracing.WithTrace(ctx, job.trace), job.query.Endpoint, job.query.Ticker)
+	span.End(err)
+	job.done(result, err)
+}
@@ -1,11 +1,14 @@
 package coordinator
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
 
+	"market-terminal/internal/crash"
 	"market-terminal/internal/database"
+	"market-terminal/internal/tracing"
 )
 
 // WriteTask represents a database write task
@@ -14,6 +17,8 @@ type WriteTask struct {
 	Timestamp float64
 	Data      map[string]interface{}
 	Priority  int // 0=high, 1=medium, 2=low
+	Trace     *tracing.Trace // Trace of the batch that produced the row (nil if untraced)
+	span      *tracing.Span  // Write span, started when the task is queued (nil if untraced)
 }
 
 // PriorityWriteQueue manages priority-based database writes
@@ -35,19 +40,34 @@ func NewPriorityWriteQueue(dataWriter *database.DataWriter, debugPrint func(stri
 
 // Enqueue enqueues a write task
 func (pwq *PriorityWriteQueue) Enqueue(ticker string, timestamp float64, data map[string]interface{}, priority int) {
+	pwq.EnqueueContext(context.Background(), ticker, timestamp, data, priority)
+}
+
+// EnqueueContext enqueues a write task, carrying ctx's trace (if any) through the write and flush
+func (pwq *PriorityWriteQueue) EnqueueContext(ctx context.Context, ticker string, timestamp float64, data map[string]interface{}, priority int) {
 	pwq.mu.Lock()
 	defer pwq.mu.Unlock()
 
+	trace := tracing.FromContext(ctx)
+
+	// A task still waiting is replaced - record on its trace that the row was never written
+	if previous, exists := pwq.pendingWrites[ticker]; exists {
+		previous.span.SetAttr("superseded_by", trace.ShortID())
+		previous.span.End(nil)
+	}
+
 	// Store latest task per ticker (overwrites previous if exists)
 	pwq.pendingWrites[ticker] = &WriteTask{
 		Ticker:    ticker,
 		Timestamp: timestamp,
 		Data:      data,
 		Priority:  priority,
+		Trace:     trace,
+		span:      trace.StartSpan("write", ticker),
 	}
 
-	pwq.debugPrint(fmt.Sprintf("Enqueue: Queued write for %s (timestamp: %.0f, fields: %d, priority: %d)", 
-		ticker, timestamp, len(data), priority), "write_queue")
+	pwq.debugPrint(fmt.Sprintf("%sEnqueue: Queued write for %s (timestamp: %.0f, fields: %d, priority: %d)", 
+		trace.LogPrefix(), ticker, timestamp, len(data), priority), "write_queue")
 
 	// Process immediately (non-blocking)
 	go pwq.processTask(ticker)
@@ -55,6 +75,7 @@ func (pwq *PriorityWriteQueue) Enqueue(ticker string, timestamp float64, data ma
 
 // processTask processes a write task
 func (pwq *PriorityWriteQueue) processTask(ticker string) {
+	defer crash.Recover("write queue")
 	pwq.debugPrint(fmt.Sprintf("processTask: Starting processing for %s", ticker), "write_queue")
 	
 	pwq.mu.Lock()
@@ -71,8 +92,14 @@ func (pwq *PriorityWriteQueue) processTask(ticker string) {
 	// Determine if ticker is active (priority 0)
 	isActive := task.Priority == 0
 
-	pwq.debugPrint(fmt.Sprintf("processTask: Processing write for %s (timestamp: %.0f, fields: %d, active: %v, priority: %d)", 
-		task.Ticker, task.Timestamp, len(task.Data), isActive, task.Priority), "write_queue")
+	pwq.debugPrint(fmt.Sprintf("%sprocessTask: Processing write for %s (timestamp: %.0f, fields: %d, active: %v, priority: %d)", 
+		task.Trace.LogPrefix(), task.Ticker, task.Timestamp, len(task.Data), isActive, task.Priority), "write_queue")
+
+	// The write span covers the queue wait and any retries; the writer records the flush span
+	ctx := tracing.WithTrace(context.Background(), task.Trace)
+	span := task.span
+	span.SetAttr("fields", fmt.Sprintf("%d", len(task.Data)))
+	span.SetAttr("priority", fmt.Sprintf("%d", task.Priority))
 
 	// Write to database with retry logic
 	maxRetries := 3
@@ -83,9 +110,10 @@ func (pwq *PriorityWriteQueue) processTask(ticker string) {
 		pwq.debugPrint(fmt.Sprintf("processTask: Calling WriteDataEntry for %s (attempt %d/%d)", 
 			task.Ticker, attempt+1, maxRetries), "write_queue")
 		
-		err := pwq.dataWriter.WriteDataEntry(task.Ticker, task.Timestamp, task.Data, isActive)
+		err := pwq.dataWriter.WriteDataEntryContext(ctx, task.Ticker, task.Timestamp, task.Data, isActive)
 		if err == nil {
 			// Success
+			span.SetAttr("attempts", fmt.Sprintf("%d", attempt+1))
 			pwq.debugPrint(fmt.Sprintf("processTask: Successfully queued write for %s (attempt %d)", 
 				task.Ticker, attempt+1), "write_queue")
 			break
@@ -107,15 +135,18 @@ func (pwq *PriorityWriteQueue) processTask(ticker string) {
 			task.Ticker, lastErr), "error")
 		
 		// Synchronous fallback - write directly without queue
-		err := pwq.dataWriter.WriteDataEntry(task.Ticker, task.Timestamp, task.Data, isActive)
+		err := pwq.dataWriter.WriteDataEntryContext(ctx, task.Ticker, task.Timestamp, task.Data, isActive)
 		if err != nil {
 			pwq.debugPrint(fmt.Sprintf("❌ CRITICAL: Synchronous fallback also failed for %s: %v", task.Ticker, err), "error")
+			span.End(err)
 			// Data collection must continue even if write fails
 			return
 		}
 		
 		pwq.debugPrint(fmt.Sprintf("✅ CRITICAL RECOVERY: Synchronous write succeeded for %s after async failure", task.Ticker), "system")
+		span.SetAttr("sync_fallback", "true")
 	}
+	span.End(nil)
 	
 	pwq.debugPrint(fmt.Sprintf("Successfully queued write for %s", task.Ticker), "write_queue")
 
@@ -123,6 +154,7 @@ func (pwq *PriorityWriteQueue) processTask(ticker string) {
 	if isActive {
 		pwq.debugPrint(fmt.Sprintf("processTask: Scheduling immediate flush for active ticker %s", task.Ticker), "write_queue")
 		go func() {
+			defer crash.Recover("writer flush")
 			time.Sleep(100 * time.Millisecond) // Small delay to allow batching
 			pwq.debugPrint(fmt.Sprintf("processTask: Executing flush for active ticker %s", task.Ticker), "write_queue")
 			if err := pwq.dataWriter.FlushTicker(task.Ticker); err != nil {
@@ -143,3 +175,24 @@ func (pwq *PriorityWriteQueue) GetPendingCount() int {
 	defer pwq.mu.RUnlock()
 	return len(pwq.pendingWrites)
 }
+
+// WritePending hands every queued task to the data writer now (before its final flush at shutdown)
+func (pwq *PriorityWriteQueue) WritePending() {
+	pwq.mu.RLock()
+	tickers := make([]string, 0, len(pwq.pendingWrites))
+	for ticker := range pwq.pendingWrites {
+		tickers = append(tickers, ticker)
+	}
+	pwq.mu.RUnlock()
+	for _, ticker := range tickers {
+		pwq.processTask(ticker)
+	}
+}
+
+// DrainTicker writes any pending task for a ticker and flushes its buffered rows to disk
+// Used when a ticker is disabled so nothing collected before the change is lost
+func (pwq *PriorityWriteQueue) DrainTicker(ticker string) error {
+	// processTask is a no-op if the task was already picked up
+	pwq.processTask(ticker)
+	return pwq.dataWriter.FlushTicker(ticker)
+}
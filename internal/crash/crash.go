@@ -0,0 +1,212 @@
+package crash
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/utils"
+)
+
+// Dump is the crash report written when a goroutine panics
+type Dump struct {
+	Time       string           `json:"time"`
+	Component  string           `json:"component"` // Where the panic was caught (e.g. "scheduler", "writer flush")
+	Panic      string           `json:"panic"`
+	Stack      string           `json:"stack"`
+	AppVersion string           `json:"app_version"`
+	GoVersion  string           `json:"go_version"`
+	Platform   string           `json:"platform"`
+	Goroutines int              `json:"goroutines"`
+	RecentLogs []string         `json:"recent_logs"`
+	Settings   *config.Settings `json:"settings,omitempty"` // API keys and credentials removed
+}
+
+// ReportInfo describes a crash dump on disk
+type ReportInfo struct {
+	Path      string `json:"path"`
+	Time      string `json:"time"`
+	Component string `json:"component"`
+	Panic     string `json:"panic"`
+}
+
+// Reporter state is global: panics are caught in packages that know nothing about the app
+var (
+	mu          sync.Mutex
+	appVersion  string
+	getSettings func() *config.Settings
+	onReport    func(ReportInfo)
+	lastReport  = make(map[string]time.Time) // Component -> last dump time (rate limiting)
+)
+
+// Configure sets what crash dumps include and the callback run after a dump is written
+// (e.g. to show the user a button to open it)
+func Configure(version string, settings func() *config.Settings, onDump func(ReportInfo)) {
+	mu.Lock()
+	defer mu.Unlock()
+	appVersion = version
+	getSettings = settings
+	onReport = onDump
+}
+
+// GetCrashDir returns the folder crash dumps are written to
+func GetCrashDir() (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, config.CrashDirName), nil
+}
+
+// Recover reports a panic in the calling goroutine and stops it from crashing the process
+// Use as `defer crash.Recover("component")` at the top of a goroutine
+func Recover(component string) {
+	if r := recover(); r != nil {
+		log.Printf("PANIC in %s: %v", component, r)
+		Report(component, r)
+	}
+}
+
+// Report writes a crash dump for a recovered panic value and returns its path
+// Must be called from the deferred function that recovered, so the stack still shows the panic site
+// Repeated panics from the same component within CrashReportMinIntervalSec are only logged
+func Report(component string, recovered interface{}) string {
+	stack := string(debug.Stack())
+	now := time.Now()
+
+	mu.Lock()
+	if last, ok := lastReport[component]; ok && now.Sub(last) < config.CrashReportMinIntervalSec*time.Second {
+		mu.Unlock()
+		utils.Logf("[error] Crash: repeated panic in %s (dump skipped): %v", component, recovered)
+		return ""
+	}
+	lastReport[component] = now
+	version, settingsFunc, onDump := appVersion, getSettings, onReport
+	mu.Unlock()
+
+	dump := Dump{
+		Time:       now.Format(time.RFC3339),
+		Component:  component,
+		Panic:      fmt.Sprintf("%v", recovered),
+		Stack:      stack,
+		AppVersion: version,
+		GoVersion:  runtime.Version(),
+		Platform:   runtime.GOOS + "/" + runtime.GOARCH,
+		Goroutines: runtime.NumGoroutine(),
+		RecentLogs: utils.RecentLogLines(config.CrashReportLogLines),
+	}
+	if settingsFunc != nil {
+		if settings := settingsFunc(); settings != nil {
+			if shared, err := settings.WithoutSecrets(); err == nil {
+				dump.Settings = shared
+			}
+		}
+	}
+
+	path, err := writeDump(dump, now)
+	if err != nil {
+		utils.Logf("[error] Crash: failed to write crash dump for %s: %v", component, err)
+		return ""
+	}
+	utils.Logf("[error] Crash: panic in %s: %v - crash dump written to %s", component, recovered, path)
+
+	if onDump != nil {
+		onDump(ReportInfo{Path: path, Time: dump.Time, Component: component, Panic: dump.Panic})
+	}
+	return path
+}
+
+// writeDump saves a dump to the crash dir and prunes the oldest dumps
+func writeDump(dump Dump, now time.Time) (string, error) {
+	dir, err := GetCrashDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create crash directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal crash dump: %w", err)
+	}
+	name := fmt.Sprintf("crash_%s_%s.json", now.Format("2006-01-02_15-04-05"), fileSafe(dump.Component))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write crash dump: %w", err)
+	}
+
+	pruneDumps(dir)
+	return path, nil
+}
+
+// fileSafe turns a component name into a file name fragment
+func fileSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// dumpFiles returns crash dump paths in dir, newest first
+func dumpFiles(dir string) []string {
+	paths, _ := filepath.Glob(filepath.Join(dir, "crash_*.json"))
+	sort.Sort(sort.Reverse(sort.StringSlice(paths))) // Names start with the timestamp
+	return paths
+}
+
+// pruneDumps deletes dumps beyond CrashReportMaxFiles
+func pruneDumps(dir string) {
+	paths := dumpFiles(dir)
+	for i := config.CrashReportMaxFiles; i < len(paths); i++ {
+		os.Remove(paths[i])
+	}
+}
+
+// ListReports returns the crash dumps on disk, newest first
+func ListReports() ([]ReportInfo, error) {
+	dir, err := GetCrashDir()
+	if err != nil {
+		return nil, err
+	}
+	reports := make([]ReportInfo, 0)
+	for _, path := range dumpFiles(dir) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var dump Dump
+		if err := json.Unmarshal(data, &dump); err != nil {
+			continue
+		}
+		reports = append(reports, ReportInfo{Path: path, Time: dump.Time, Component: dump.Component, Panic: dump.Panic})
+	}
+	return reports, nil
+}
+
+// ResolveReport checks that path is a crash dump inside the crash dir (bindings only open/read those)
+func ResolveReport(path string) (string, error) {
+	dir, err := GetCrashDir()
+	if err != nil {
+		return "", err
+	}
+	clean := filepath.Clean(path)
+	if filepath.Dir(clean) != filepath.Clean(dir) || !strings.HasPrefix(filepath.Base(clean), "crash_") {
+		return "", fmt.Errorf("not a crash report: %s", path)
+	}
+	if _, err := os.Stat(clean); err != nil {
+		return "", fmt.Errorf("crash report not found: %w", err)
+	}
+	return clean, nil
+}
@@ -0,0 +1,46 @@
+package crash
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// Open shows a crash dump in the system's default viewer (or its folder, when reveal is set)
+func Open(path string, reveal bool) error {
+	path, err := ResolveReport(path)
+	if err != nil {
+		return err
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		if reveal {
+			cmd = exec.Command("open", "-R", path)
+		} else {
+			cmd = exec.Command("open", path)
+		}
+	case "windows":
+		if reveal {
+			cmd = exec.Command("explorer", "/select,"+path)
+		} else {
+			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", path)
+		}
+	default:
+		target := path
+		if reveal {
+			dir, err := GetCrashDir()
+			if err != nil {
+				return err
+			}
+			target = dir
+		}
+		cmd = exec.Command("xdg-open", target)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open crash report: %w", err)
+	}
+	go cmd.Wait() // Reap the viewer process without blocking the caller
+	return nil
+}
@@ -0,0 +1,130 @@
+package database
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// adaptiveBatching scales how many rows (and how long) collection tickers batch per flush: smaller batches while
+// the writer is under pressure (deep pending queue or slow flushes), larger ones again once it is idle
+type adaptiveBatching struct {
+	mu        sync.Mutex
+	scale     float64       // Multiplier on the base count and interval thresholds
+	latency   time.Duration // Moving average of flush durations (0 = no flush measured yet)
+	lastDepth int           // Pending rows across tickers after the last flush
+	shrinks   int64
+	grows     int64
+}
+
+// AdaptiveBatchStatus reports the current flush batch sizing
+type AdaptiveBatchStatus struct {
+	Enabled        bool    `json:"enabled"`
+	Scale          float64 `json:"scale"`           // Multiplier on the base thresholds
+	CountThreshold int     `json:"count_threshold"` // Rows a collection ticker batches before flushing
+	IntervalMs     int64   `json:"interval_ms"`     // ...or how long it batches them
+	FlushLatencyMs float64 `json:"flush_latency_ms"`
+	PendingDepth   int     `json:"pending_depth"` // Pending rows across tickers after the last flush
+	Shrinks        int64   `json:"shrinks"`
+	Grows          int64   `json:"grows"`
+}
+
+func newAdaptiveBatching() *adaptiveBatching {
+	return &adaptiveBatching{scale: 1}
+}
+
+// observe records a flush's duration and the pending depth after it, and adjusts the scale
+func (ab *adaptiveBatching) observe(duration time.Duration, depth int) {
+	if !config.AdaptiveBatchingEnabled {
+		return
+	}
+	ab.mu.Lock()
+	defer ab.mu.Unlock()
+
+	if ab.latency == 0 {
+		ab.latency = duration
+	} else {
+		ab.latency += time.Duration(config.AdaptiveBatchLatencySmoothing * float64(duration-ab.latency))
+	}
+	ab.lastDepth = depth
+
+	target := time.Duration(config.MaxWriteBatchTimeMs) * time.Millisecond
+	warnDepth := int(float64(config.MaxWriteQueueSize) * config.QueueSizeWarningThresholdRatio)
+	idleDepth := int(float64(config.MaxWriteQueueSize) * config.AdaptiveBatchIdleDepthRatio)
+	switch {
+	case depth >= warnDepth || ab.latency > target:
+		if scale := math.Max(ab.scale*config.AdaptiveBatchReductionRatio, config.AdaptiveBatchMinScale); scale < ab.scale {
+			ab.scale = scale
+			ab.shrinks++
+		}
+	case depth <= idleDepth && ab.latency < target/2:
+		if scale := math.Min(ab.scale*config.AdaptiveBatchGrowthRatio, config.AdaptiveBatchMaxScale); scale > ab.scale {
+			ab.scale = scale
+			ab.grows++
+		}
+	}
+}
+
+// thresholds scales a base count and interval threshold; at emergency depth every write is flushed
+func (ab *adaptiveBatching) thresholds(count int, interval time.Duration, depth int) (int, time.Duration) {
+	if !config.AdaptiveBatchingEnabled {
+		return count, interval
+	}
+	if depth >= int(float64(config.MaxWriteQueueSize)*config.QueueSizeEmergencyThresholdRatio) {
+		return config.MinBatchCount, 0
+	}
+	ab.mu.Lock()
+	scale := ab.scale
+	ab.mu.Unlock()
+	scaled := int(math.Round(float64(count) * scale))
+	if scaled < config.MinBatchCount {
+		scaled = config.MinBatchCount
+	}
+	return scaled, time.Duration(float64(interval) * scale)
+}
+
+// status reports the sizing for the base thresholds
+func (ab *adaptiveBatching) status(count int, interval time.Duration) AdaptiveBatchStatus {
+	ab.mu.Lock()
+	status := AdaptiveBatchStatus{
+		Enabled:        config.AdaptiveBatchingEnabled,
+		Scale:          ab.scale,
+		FlushLatencyMs: float64(ab.latency) / float64(time.Millisecond),
+		PendingDepth:   ab.lastDepth,
+		Shrinks:        ab.shrinks,
+		Grows:          ab.grows,
+	}
+	ab.mu.Unlock()
+	scaledCount, scaledInterval := ab.thresholds(count, interval, status.PendingDepth)
+	status.CountThreshold = scaledCount
+	status.IntervalMs = scaledInterval.Milliseconds()
+	return status
+}
+
+// pendingDepth is the number of rows waiting to be flushed across tickers (caller holds dw.mu)
+func (dw *DataWriter) pendingDepth() int {
+	depth := 0
+	for _, pending := range dw.pendingWrites {
+		depth += len(pending)
+	}
+	return depth
+}
+
+// baseFlushThresholds are the rows and time a collection ticker batches before flushing, before adaptive scaling
+// (caller holds dw.mu)
+func (dw *DataWriter) baseFlushThresholds() (int, time.Duration) {
+	if dw.eco {
+		return config.EcoFlushCountThreshold, time.Duration(config.EcoFlushIntervalSec) * time.Second
+	}
+	return config.FileWriteCountThresholdCollection, time.Duration(config.FileWriteIntervalCollectionSec) * time.Second
+}
+
+// GetAdaptiveBatchStatus returns the current flush batch sizing
+func (dw *DataWriter) GetAdaptiveBatchStatus() AdaptiveBatchStatus {
+	dw.mu.RLock()
+	count, interval := dw.baseFlushThresholds()
+	dw.mu.RUnlock()
+	return dw.batching.status(count, interval)
+}
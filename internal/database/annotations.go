@@ -0,0 +1,118 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Annotation is a user note pinned to a point on a ticker's chart (e.g. "FOMC 14:00")
+type Annotation struct {
+	ID        int64   `json:"id"`
+	Timestamp float64 `json:"timestamp"` // Chart time the note refers to (Unix seconds)
+	Text      string  `json:"text"`
+	CreatedAt float64 `json:"created_at"` // When the note was added (Unix seconds)
+}
+
+// AddAnnotation stores an annotation in the ticker's database for a market date
+// The database must already exist unless date is the current collection day
+func (dw *DataWriter) AddAnnotation(ticker string, date time.Time, timestamp float64, text string) (*Annotation, error) {
+	dbPath := dw.getDBPath(ticker, date)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		if _, encErr := os.Stat(EncryptedPath(dbPath)); encErr == nil {
+			return nil, fmt.Errorf("cannot annotate %s on %s: the day is encrypted", ticker, date.Format("2006-01-02"))
+		}
+	}
+
+	db, err := dw.pool.GetConnection(dbPath, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS annotations (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		timestamp REAL NOT NULL,
+		text TEXT NOT NULL,
+		created_at REAL NOT NULL
+	)`); err != nil {
+		return nil, fmt.Errorf("failed to create annotations table: %w", err)
+	}
+
+	annotation := &Annotation{
+		Timestamp: timestamp,
+		Text:      text,
+		CreatedAt: float64(time.Now().UnixNano()) / 1e9,
+	}
+	result, err := db.Exec("INSERT INTO annotations (timestamp, text, created_at) VALUES (?, ?, ?)",
+		annotation.Timestamp, annotation.Text, annotation.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save annotation: %w", err)
+	}
+	annotation.ID, _ = result.LastInsertId()
+
+	dw.debugPrint(fmt.Sprintf("AddAnnotation: Added annotation %d for %s on %s", annotation.ID, ticker, date.Format("2006-01-02")), "writer")
+	return annotation, nil
+}
+
+// DeleteAnnotation removes an annotation by ID
+func (dw *DataWriter) DeleteAnnotation(ticker string, date time.Time, id int64) error {
+	dbPath := dw.getDBPath(ticker, date)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return fmt.Errorf("no database for %s on %s", ticker, date.Format("2006-01-02"))
+	}
+
+	db, err := dw.pool.GetConnection(dbPath, false)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	result, err := db.Exec("DELETE FROM annotations WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete annotation: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("annotation %d not found", id)
+	}
+	return nil
+}
+
+// LoadAnnotations loads a ticker's annotations for a market date, ordered by chart time
+// Returns an empty list if the day has no database or no annotations
+func (dl *DataLoader) LoadAnnotations(ticker string, date time.Time) ([]Annotation, error) {
+	annotations := make([]Annotation, 0)
+
+	dbPath := dl.getDBPath(ticker, date)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return annotations, nil
+	}
+
+	db, err := dl.pool.GetConnection(dbPath, true) // Read-only
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	var tableName string
+	err = db.QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name='annotations'").Scan(&tableName)
+	if err == sql.ErrNoRows {
+		return annotations, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check annotations table: %w", err)
+	}
+
+	rows, err := db.Query("SELECT id, timestamp, text, created_at FROM annotations ORDER BY timestamp, id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query annotations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a Annotation
+		if err := rows.Scan(&a.ID, &a.Timestamp, &a.Text, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan annotation: %w", err)
+		}
+		annotations = append(annotations, a)
+	}
+	return annotations, rows.Err()
+}
@@ -0,0 +1,327 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// chartBarsTable holds 1-minute bars derived from ticker_data
+// Bars are always rebuilt from raw rows, so the table can be dropped and regenerated at any time
+const chartBarsTable = "ticker_data_1m"
+
+// chartBarLevelColumns are carried into bars as the last value of each minute
+var chartBarLevelColumns = []string{
+	"zero_gamma",
+	"major_pos_vol",
+	"major_neg_vol",
+	"major_long_gamma",
+	"major_short_gamma",
+	"major_positive",
+	"major_negative",
+	"major_pos_oi",
+	"major_neg_oi",
+}
+
+// chartBar is one minute of aggregated chart data
+type chartBar struct {
+	timestamp              float64       // Minute start (Unix seconds)
+	open, high, low, close float64       // Spot OHLC (0 when the minute had no spot)
+	levels                 []interface{} // Last non-null value of each chartBarLevelColumns entry
+	lastTimestamp          float64       // Newest raw row folded into the bar
+	rows                   int
+}
+
+// chartBarStart returns the start of the minute containing timestamp
+func chartBarStart(timestamp float64) float64 {
+	return math.Floor(timestamp/config.ChartBarIntervalSec) * config.ChartBarIntervalSec
+}
+
+// chartBarColumns returns the bars table columns in insert/select order
+func chartBarColumns() []string {
+	columns := []string{"timestamp", "spot_open", "spot_high", "spot_low", "spot"}
+	columns = append(columns, chartBarLevelColumns...)
+	return append(columns, "last_timestamp", "row_count")
+}
+
+// barsCoverFields reports whether the bars table has every requested column (nil = default chart columns)
+func barsCoverFields(columns []string) bool {
+	columnsInBars := chartBarColumns()
+	barColumns := make(map[string]bool)
+	for _, col := range columnsInBars[:len(columnsInBars)-2] { // Skip last_timestamp/row_count bookkeeping
+		barColumns[col] = true
+	}
+	for _, col := range columns {
+		if !barColumns[col] {
+			return false
+		}
+	}
+	return true
+}
+
+// ensureChartBarsTable creates the bars table, reporting whether it was newly created
+func ensureChartBarsTable(db *sql.DB) (bool, error) {
+	var name string
+	err := db.QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name=?", chartBarsTable).Scan(&name)
+	if err == nil {
+		return false, nil
+	}
+	if err != sql.ErrNoRows {
+		return false, fmt.Errorf("failed to check %s table: %w", chartBarsTable, err)
+	}
+
+	definitions := make([]string, 0)
+	for _, col := range chartBarColumns() {
+		switch col {
+		case "timestamp":
+			definitions = append(definitions, "timestamp REAL PRIMARY KEY")
+		case "row_count":
+			definitions = append(definitions, "row_count INTEGER")
+		default:
+			definitions = append(definitions, col+" REAL")
+		}
+	}
+	if _, err := db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s) WITHOUT ROWID", chartBarsTable, strings.Join(definitions, ", "))); err != nil {
+		return false, fmt.Errorf("failed to create %s table: %w", chartBarsTable, err)
+	}
+	return true, nil
+}
+
+// aggregateChartBars folds raw rows with start <= timestamp < end into 1-minute bars
+func aggregateChartBars(db *sql.DB, start, end float64) ([]*chartBar, error) {
+	existing, err := NewSchemaManager(db).getExistingColumns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing columns: %w", err)
+	}
+
+	// Columns missing from older databases are read as NULL
+	selectCols := []string{"timestamp"}
+	for _, col := range append([]string{"spot"}, chartBarLevelColumns...) {
+		if existing[col] {
+			selectCols = append(selectCols, col)
+		} else {
+			selectCols = append(selectCols, "NULL")
+		}
+	}
+
+	rows, err := db.Query(fmt.Sprintf("SELECT %s FROM ticker_data WHERE timestamp >= ? AND timestamp < ? ORDER BY timestamp ASC",
+		strings.Join(selectCols, ", ")), start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query raw rows: %w", err)
+	}
+	defer rows.Close()
+
+	bars := make([]*chartBar, 0)
+	var bar *chartBar
+	for rows.Next() {
+		values := make([]sql.NullFloat64, len(selectCols))
+		valuePtrs := make([]interface{}, len(selectCols))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		timestamp := values[0].Float64
+		if bar == nil || bar.timestamp != chartBarStart(timestamp) {
+			bar = &chartBar{timestamp: chartBarStart(timestamp), levels: make([]interface{}, len(chartBarLevelColumns))}
+			bars = append(bars, bar)
+		}
+		bar.rows++
+		bar.lastTimestamp = timestamp
+
+		if spot := values[1]; spot.Valid && spot.Float64 != 0 && !math.IsNaN(spot.Float64) && !math.IsInf(spot.Float64, 0) {
+			if bar.open == 0 {
+				bar.open, bar.high, bar.low = spot.Float64, spot.Float64, spot.Float64
+			}
+			bar.high = math.Max(bar.high, spot.Float64)
+			bar.low = math.Min(bar.low, spot.Float64)
+			bar.close = spot.Float64
+		}
+		for i := range chartBarLevelColumns {
+			if value := values[i+2]; value.Valid {
+				bar.levels[i] = value.Float64
+			}
+		}
+	}
+	return bars, rows.Err()
+}
+
+// writeChartBars upserts bars in a single transaction
+func writeChartBars(db *sql.DB, bars []*chartBar) error {
+	if len(bars) == 0 {
+		return nil
+	}
+
+	columns := chartBarColumns()
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf("INSERT OR REPLACE INTO %s (%s) VALUES (%s)", chartBarsTable, strings.Join(columns, ", "), placeholders))
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	nullIfZero := func(v float64) interface{} {
+		if v == 0 {
+			return nil
+		}
+		return v
+	}
+	for _, bar := range bars {
+		args := []interface{}{bar.timestamp, nullIfZero(bar.open), nullIfZero(bar.high), nullIfZero(bar.low), nullIfZero(bar.close)}
+		args = append(args, bar.levels...)
+		args = append(args, bar.lastTimestamp, bar.rows)
+		if _, err := stmt.Exec(args...); err != nil {
+			return fmt.Errorf("failed to write bar: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// updateChartBars re-aggregates the minutes touched by a flush (called after the raw rows commit)
+// The first flush into a database without bars aggregates the whole day so the table is complete
+func (dw *DataWriter) updateChartBars(db *sql.DB, writes []*PendingWrite) error {
+	if len(writes) == 0 {
+		return nil
+	}
+
+	created, err := ensureChartBarsTable(db)
+	if err != nil {
+		return err
+	}
+
+	start, end := 0.0, math.MaxFloat64
+	if !created {
+		start, end = writes[0].Timestamp, writes[0].Timestamp
+		for _, write := range writes {
+			start = math.Min(start, write.Timestamp)
+			end = math.Max(end, write.Timestamp)
+		}
+		start = chartBarStart(start)
+		end = chartBarStart(end) + config.ChartBarIntervalSec
+	}
+
+	bars, err := aggregateChartBars(db, start, end)
+	if err != nil {
+		return err
+	}
+	return writeChartBars(db, bars)
+}
+
+// RebuildChartBars aggregates a whole day's 1-minute bars from its raw rows
+// Backfills days recorded before bars existed; skipped if the day has no database (e.g. encrypted)
+// Concurrent rebuilds of the same file are coalesced
+func (dw *DataWriter) RebuildChartBars(ticker string, date time.Time) error {
+	dbPath := dw.getDBPath(ticker, date)
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("no database for %s on %s", ticker, date.Format("2006-01-02"))
+	}
+
+	dw.mu.Lock()
+	if dw.barRebuilds[dbPath] {
+		dw.mu.Unlock()
+		return nil
+	}
+	dw.barRebuilds[dbPath] = true
+	dw.mu.Unlock()
+	defer func() {
+		dw.mu.Lock()
+		delete(dw.barRebuilds, dbPath)
+		dw.mu.Unlock()
+	}()
+
+	db, err := dw.pool.GetConnection(dbPath, false)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	if _, err := ensureChartBarsTable(db); err != nil {
+		return err
+	}
+	bars, err := aggregateChartBars(db, 0, math.MaxFloat64)
+	if err != nil {
+		return err
+	}
+	if err := writeChartBars(db, bars); err != nil {
+		return err
+	}
+
+	dw.debugPrint(fmt.Sprintf("RebuildChartBars: Built %d 1m bars for %s on %s", len(bars), ticker, date.Format("2006-01-02")), "writer")
+	return nil
+}
+
+// HasChartBars reports whether a day's database has 1-minute bars
+func (dl *DataLoader) HasChartBars(ticker string, date time.Time) bool {
+	dbPath := dl.getDBPath(ticker, date)
+	if _, err := os.Stat(dbPath); err != nil {
+		return false
+	}
+	db, err := dl.pool.GetConnection(dbPath, true) // Read-only
+	if err != nil {
+		return false
+	}
+	var name string
+	return db.QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name=?", chartBarsTable).Scan(&name) == nil
+}
+
+// loadChartBars loads a day's 1-minute bars in the LoadChartData layout, plus spot_open/spot_high/spot_low
+// (spot is the bar's close). Returns nil without an error when the database has no bars yet
+func (dl *DataLoader) loadChartBars(ctx context.Context, db *sql.DB, maxRows int) (map[string][]interface{}, error) {
+	var name string
+	err := db.QueryRowContext(ctx, "SELECT name FROM sqlite_master WHERE type='table' AND name=?", chartBarsTable).Scan(&name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check %s table: %w", chartBarsTable, err)
+	}
+
+	// last_timestamp/row_count are bookkeeping and not sent to charts
+	columns := chartBarColumns()
+	columns = columns[:len(columns)-2]
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s ORDER BY timestamp ASC LIMIT %d", strings.Join(columns, ", "), chartBarsTable, maxRows))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bars: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string][]interface{})
+	for _, col := range columns {
+		result[col] = make([]interface{}, 0)
+	}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		valuePtrs := make([]interface{}, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, fmt.Errorf("failed to scan bar: %w", err)
+		}
+		for i, col := range columns {
+			result[col] = append(result[col], values[i])
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bars: %w", err)
+	}
+
+	if len(result["timestamp"]) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/utils"
+)
+
+// SQLite WAL checkpoint modes used by the writer
+const (
+	checkpointPassive  = "PASSIVE"  // Copies what it can without blocking writers or readers
+	checkpointTruncate = "TRUNCATE" // Waits for writers, copies everything and truncates the WAL file
+)
+
+// SetWALCheckpointSettings updates the checkpoint policy used after flushes
+func (dw *DataWriter) SetWALCheckpointSettings(cfg config.WALCheckpointSettings) {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	dw.walSettings = cfg
+}
+
+// checkpointMode picks the checkpoint mode after a flush to dbPath
+// Adaptive: PASSIVE during market hours (many tickers flush at once), TRUNCATE when closed
+// or when the WAL has grown past the forced checkpoint size
+func (dw *DataWriter) checkpointMode(dbPath string) string {
+	dw.mu.RLock()
+	cfg := dw.walSettings
+	dw.mu.RUnlock()
+
+	if !cfg.Adaptive {
+		return checkpointTruncate
+	}
+	if info, err := os.Stat(dbPath + "-wal"); err == nil && info.Size() >= int64(cfg.ForceSizeMB)*1024*1024 {
+		dw.debugPrint(fmt.Sprintf("WAL for %s is %d MB - forcing TRUNCATE checkpoint", dbPath, info.Size()/1024/1024), "writer")
+		return checkpointTruncate
+	}
+	if utils.IsMarketOpen() {
+		return checkpointPassive
+	}
+	return checkpointTruncate
+}
+
+// checkpoint runs a WAL checkpoint in the given mode
+// Files left with a PASSIVE checkpoint are tracked so they get truncated once idle
+func (dw *DataWriter) checkpoint(db *sql.DB, dbPath, mode string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("PRAGMA wal_checkpoint(%s)", mode)); err != nil {
+		return err
+	}
+
+	dw.mu.Lock()
+	if mode == checkpointPassive {
+		dw.passiveCheckpoints[dbPath] = time.Now()
+	} else {
+		delete(dw.passiveCheckpoints, dbPath)
+	}
+	dw.mu.Unlock()
+	return nil
+}
+
+// truncateIdleWALs truncates WAL files that have only had PASSIVE checkpoints and
+// haven't been flushed for IdleTruncateSec (called from the background flusher)
+func (dw *DataWriter) truncateIdleWALs() {
+	dw.dayFilesMu.RLock()
+	defer dw.dayFilesMu.RUnlock()
+
+	dw.mu.RLock()
+	idleAfter := time.Duration(dw.walSettings.IdleTruncateSec) * time.Second
+	idle := make([]string, 0)
+	for dbPath, last := range dw.passiveCheckpoints {
+		if time.Since(last) >= idleAfter {
+			idle = append(idle, dbPath)
+		}
+	}
+	dw.mu.RUnlock()
+
+	for _, dbPath := range idle {
+		// File may have been encrypted or removed since (don't recreate it)
+		if _, err := os.Stat(dbPath); err != nil {
+			dw.mu.Lock()
+			delete(dw.passiveCheckpoints, dbPath)
+			dw.mu.Unlock()
+			continue
+		}
+		db, err := dw.pool.GetConnection(dbPath, false)
+		if err != nil {
+			dw.mu.Lock()
+			delete(dw.passiveCheckpoints, dbPath)
+			dw.mu.Unlock()
+			continue
+		}
+		if err := dw.checkpoint(db, dbPath, checkpointTruncate); err != nil {
+			dw.debugPrint(fmt.Sprintf("Idle WAL truncate warning for %s: %v", dbPath, err), "writer")
+		} else {
+			dw.debugPrint(fmt.Sprintf("Idle WAL truncated for %s", dbPath), "writer")
+		}
+	}
+}
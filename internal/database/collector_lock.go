@@ -0,0 +1,267 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+const (
+	collectorLockFile      = ".collector.lock"       // Marks the data directory of the instance collecting into it
+	collectorLockGuardFile = ".collector.lock.guard" // Held while an instance reads and then replaces the lock file
+)
+
+// CollectorLockInfo identifies the instance holding a data directory's collector lock
+type CollectorLockInfo struct {
+	PID       int       `json:"pid"`
+	Host      string    `json:"host"`
+	StartedAt time.Time `json:"started_at"`
+	Heartbeat time.Time `json:"heartbeat"` // Refreshed every CollectorLockHeartbeatSec while the instance runs
+}
+
+// live reports whether the holder refreshed its heartbeat recently (a crashed instance's lock goes stale)
+func (info CollectorLockInfo) live(now time.Time) bool {
+	return now.Sub(info.Heartbeat) < time.Duration(config.CollectorLockStaleSec)*time.Second
+}
+
+// DataDirectoryInUseError is returned by AcquireCollectorLock when another live instance collects into the directory
+// Holder is zero when the lock file couldn't be read
+type DataDirectoryInUseError struct {
+	Dir    string
+	Holder CollectorLockInfo
+}
+
+func (e *DataDirectoryInUseError) Error() string {
+	if e.Holder.PID == 0 {
+		return fmt.Sprintf("data directory %s is in use by another collector (its lock was modified within the last %ds)",
+			e.Dir, config.CollectorLockStaleSec)
+	}
+	return fmt.Sprintf("data directory %s is in use by another collector (PID %d on %s, running since %s)",
+		e.Dir, e.Holder.PID, e.Holder.Host, e.Holder.StartedAt.Local().Format("2006-01-02 15:04:05"))
+}
+
+// errLockGuardBusy is returned when another instance held the lock guard for longer than CollectorLockGuardWaitMs
+var errLockGuardBusy = errors.New("collector lock is busy")
+
+// CollectorLock is this instance's claim on a data directory: two instances writing the same day files
+// corrupt them, so only the lock holder collects. The heartbeat is refreshed until Release
+type CollectorLock struct {
+	mu         sync.Mutex
+	path       string
+	info       CollectorLockInfo
+	onLost     func(holder CollectorLockInfo) // Called once if another instance takes the lock over
+	stop       chan struct{}
+	done       chan struct{}
+	once       sync.Once
+	debugPrint func(string, string)
+}
+
+// AcquireCollectorLock claims dataDir for this instance
+// A lock whose heartbeat is older than CollectorLockStaleSec (an instance that crashed or was killed) is taken
+// over; a live one returns *DataDirectoryInUseError describing the other instance. A lock file that can't be read
+// counts as live until its modification time is as old as a stale heartbeat
+func AcquireCollectorLock(dataDir string, debugPrint func(string, string)) (*CollectorLock, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	path := filepath.Join(dataDir, collectorLockFile)
+
+	// Checking the existing lock and replacing it happen under the guard, so two instances finding the same
+	// stale lock can't both take it over (the second would remove the first one's new lock)
+	release, err := acquireLockGuard(dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to take collector lock: %w", err)
+	}
+	defer release()
+
+	now := time.Now()
+	if holder, err := readCollectorLock(path); err == nil {
+		if holder.live(now) {
+			return nil, &DataDirectoryInUseError{Dir: dataDir, Holder: holder}
+		}
+		debugPrint(fmt.Sprintf("Collector lock: Taking over stale lock of PID %d on %s (last heartbeat %s)",
+			holder.PID, holder.Host, holder.Heartbeat.Local().Format("2006-01-02 15:04:05")), "system")
+		os.Remove(path)
+	} else if !os.IsNotExist(err) {
+		info, statErr := os.Stat(path)
+		if statErr == nil && now.Sub(info.ModTime()) < time.Duration(config.CollectorLockStaleSec)*time.Second {
+			return nil, &DataDirectoryInUseError{Dir: dataDir}
+		}
+		debugPrint(fmt.Sprintf("Collector lock: Replacing unreadable lock file %s: %v", path, err), "error")
+		os.Remove(path)
+	}
+
+	host, _ := os.Hostname()
+	lock := &CollectorLock{
+		path:       path,
+		info:       CollectorLockInfo{PID: os.Getpid(), Host: host, StartedAt: now, Heartbeat: now},
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+		debugPrint: debugPrint,
+	}
+
+	// O_EXCL: the file appearing since the check means another instance created it without the guard
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if os.IsExist(err) {
+		holder, _ := readCollectorLock(path)
+		return nil, &DataDirectoryInUseError{Dir: dataDir, Holder: holder}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create collector lock: %w", err)
+	}
+	err = json.NewEncoder(file).Encode(lock.info)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("failed to write collector lock: %w", err)
+	}
+
+	go lock.heartbeat()
+	return lock, nil
+}
+
+// acquireLockGuard creates the guard file next to the lock, waiting up to CollectorLockGuardWaitMs for another
+// instance to finish with it, and returns the function that removes it
+// A guard older than CollectorLockStaleSec was left by an instance that died holding it and is removed
+func acquireLockGuard(dataDir string) (func(), error) {
+	path := filepath.Join(dataDir, collectorLockGuardFile)
+	deadline := time.Now().Add(config.CollectorLockGuardWaitMs * time.Millisecond)
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > time.Duration(config.CollectorLockStaleSec)*time.Second {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, errLockGuardBusy
+		}
+		time.Sleep(config.CollectorLockGuardRetryMs * time.Millisecond)
+	}
+}
+
+// readCollectorLock reads a lock file
+func readCollectorLock(path string) (CollectorLockInfo, error) {
+	var info CollectorLockInfo
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return info, err
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return info, fmt.Errorf("invalid collector lock: %w", err)
+	}
+	return info, nil
+}
+
+// isThisInstance reports whether a lock file's holder is this instance
+func (l *CollectorLock) isThisInstance(holder CollectorLockInfo) bool {
+	return holder.PID == l.info.PID && holder.Host == l.info.Host && holder.StartedAt.Equal(l.info.StartedAt)
+}
+
+// SetOnLost sets the callback for when another instance has taken the lock over
+func (l *CollectorLock) SetOnLost(onLost func(holder CollectorLockInfo)) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onLost = onLost
+}
+
+// heartbeat refreshes the lock until Release; if another instance took the lock over meanwhile (e.g. this
+// machine slept past CollectorLockStaleSec), the lock is left to it and the lost callback stops collection here
+func (l *CollectorLock) heartbeat() {
+	defer close(l.done)
+	ticker := time.NewTicker(time.Duration(config.CollectorLockHeartbeatSec) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			holder, lost, err := l.refresh()
+			if err != nil {
+				l.debugPrint(fmt.Sprintf("Collector lock: Heartbeat failed: %v", err), "error")
+			}
+			if !lost {
+				continue
+			}
+			l.debugPrint(fmt.Sprintf("Collector lock: %s was taken over by another instance (PID %d on %s) - stopping collection here",
+				l.path, holder.PID, holder.Host), "error")
+			l.mu.Lock()
+			onLost := l.onLost
+			l.mu.Unlock()
+			if onLost != nil {
+				onLost(holder)
+			}
+			return
+		}
+	}
+}
+
+// refresh writes a new heartbeat unless the lock file now belongs to another instance (lost)
+// The file is replaced by renaming a complete temp file, so a reader never sees it half written
+func (l *CollectorLock) refresh() (holder CollectorLockInfo, lost bool, err error) {
+	release, err := acquireLockGuard(filepath.Dir(l.path))
+	if err != nil {
+		return holder, false, err // Someone else is checking the lock; the next heartbeat is well before it goes stale
+	}
+	defer release()
+
+	holder, err = readCollectorLock(l.path)
+	if err == nil && !l.isThisInstance(holder) {
+		return holder, true, nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return holder, true, err // Only this instance writes its lock: anything else has replaced it
+	}
+
+	l.info.Heartbeat = time.Now()
+	data, err := json.Marshal(l.info)
+	if err != nil {
+		return holder, false, err
+	}
+	temp := fmt.Sprintf("%s.%d.tmp", l.path, l.info.PID)
+	if err := os.WriteFile(temp, append(data, '\n'), 0644); err != nil {
+		return holder, false, err
+	}
+	if err := os.Rename(temp, l.path); err != nil {
+		os.Remove(temp)
+		return holder, false, err
+	}
+	return holder, false, nil
+}
+
+// Release stops the heartbeat and removes the lock file (if it is still this instance's)
+func (l *CollectorLock) Release() {
+	if l == nil {
+		return
+	}
+	l.once.Do(func() {
+		close(l.stop)
+		<-l.done
+		release, err := acquireLockGuard(filepath.Dir(l.path))
+		if err != nil {
+			l.debugPrint(fmt.Sprintf("Collector lock: Not removed: %v", err), "error")
+			return
+		}
+		defer release()
+		if holder, err := readCollectorLock(l.path); err == nil && l.isThisInstance(holder) {
+			os.Remove(l.path)
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"database/sql"
+	"strconv"
+	"sync"
+
+	"market-terminal/internal/config"
+)
+
+// scanFloat is a scan destination that accepts any SQLite value: numbers (and numeric text) become
+// floats, NULL and anything else is invalid, so one odd value can't fail a whole chart load
+type scanFloat struct {
+	value float64
+	valid bool
+}
+
+// Scan implements sql.Scanner
+func (f *scanFloat) Scan(src interface{}) error {
+	f.value, f.valid = 0, false
+	switch v := src.(type) {
+	case float64:
+		f.value, f.valid = v, true
+	case int64:
+		f.value, f.valid = float64(v), true
+	case []byte:
+		if parsed, err := strconv.ParseFloat(string(v), 64); err == nil {
+			f.value, f.valid = parsed, true
+		}
+	case string:
+		if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+			f.value, f.valid = parsed, true
+		}
+	}
+	return nil
+}
+
+// columnBuffer accumulates one numeric column while scanning rows
+// Values are kept unboxed with a validity bitmap for NULLs, instead of appending a boxed
+// interface{} per value per column; toInterfaces converts to the transport format once at the end
+type columnBuffer struct {
+	values []float64
+	valid  []uint64 // Bit i set = values[i] is not NULL
+}
+
+func newColumnBuffer(capacity int) *columnBuffer {
+	return &columnBuffer{
+		values: make([]float64, 0, capacity),
+		valid:  make([]uint64, 0, (capacity+63)/64),
+	}
+}
+
+// append adds a scanned value (NULL when !v.valid)
+func (b *columnBuffer) append(v scanFloat) {
+	i := len(b.values)
+	b.values = append(b.values, v.value)
+	if i%64 == 0 {
+		b.valid = append(b.valid, 0)
+	}
+	if v.valid {
+		b.valid[i/64] |= 1 << (uint(i) % 64)
+	}
+}
+
+// isValid reports whether row i holds a value
+func (b *columnBuffer) isValid(i int) bool {
+	return b.valid[i/64]&(1<<(uint(i)%64)) != 0
+}
+
+// len returns the number of rows
+func (b *columnBuffer) len() int {
+	return len(b.values)
+}
+
+// toInterfaces converts to the []interface{} transport format (float64 values, nil for NULL)
+func (b *columnBuffer) toInterfaces() []interface{} {
+	out := make([]interface{}, len(b.values))
+	for i, v := range b.values {
+		if b.isValid(i) {
+			out[i] = v
+		}
+	}
+	return out
+}
+
+// columnScanner scans query rows into typed column buffers, reusing one set of scan destinations
+type columnScanner struct {
+	columns []string
+	buffers []*columnBuffer
+	dest    []scanFloat
+	ptrs    []interface{}
+}
+
+func newColumnScanner(columns []string, capacity int) *columnScanner {
+	cs := &columnScanner{
+		columns: columns,
+		buffers: make([]*columnBuffer, len(columns)),
+		dest:    make([]scanFloat, len(columns)),
+		ptrs:    make([]interface{}, len(columns)),
+	}
+	for i := range columns {
+		cs.buffers[i] = newColumnBuffer(capacity)
+		cs.ptrs[i] = &cs.dest[i]
+	}
+	return cs
+}
+
+// scan reads the current row into the buffers
+func (cs *columnScanner) scan(rows *sql.Rows) error {
+	if err := rows.Scan(cs.ptrs...); err != nil {
+		return err
+	}
+	for i, buffer := range cs.buffers {
+		buffer.append(cs.dest[i])
+	}
+	return nil
+}
+
+// rowCount returns the number of scanned rows
+func (cs *columnScanner) rowCount() int {
+	if len(cs.buffers) == 0 {
+		return 0
+	}
+	return cs.buffers[0].len()
+}
+
+// into converts every buffer to the transport format and stores it in result
+// Large results convert columns concurrently (each column is independent)
+func (cs *columnScanner) into(result map[string][]interface{}) {
+	converted := make([][]interface{}, len(cs.buffers))
+	if cs.rowCount() < config.ParallelColumnConvertRows {
+		for i, buffer := range cs.buffers {
+			converted[i] = buffer.toInterfaces()
+		}
+	} else {
+		var wg sync.WaitGroup
+		for i, buffer := range cs.buffers {
+			wg.Add(1)
+			go func(i int, buffer *columnBuffer) {
+				defer wg.Done()
+				converted[i] = buffer.toInterfaces()
+			}(i, buffer)
+		}
+		wg.Wait()
+	}
+	for i, col := range cs.columns {
+		result[col] = converted[i]
+	}
+}
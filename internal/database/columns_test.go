@@ -0,0 +1,153 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"market-terminal/internal/config"
+)
+
+// benchmarkChartColumns is roughly a full chart load: a handful of numeric columns, a few of them sparse
+var benchmarkChartColumns = []string{"timestamp", "spot", "zero_gamma", "major_pos_vol", "major_neg_vol", "sum_gex_vol", "delta_risk_reversal"}
+
+// openBenchmarkDay creates a day database with n rows (every 7th value of the sparse columns NULL)
+func openBenchmarkDay(b *testing.B, n int) *sql.DB {
+	b.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(b.TempDir(), "SPX.db"))
+	if err != nil {
+		b.Fatalf("open: %v", err)
+	}
+	b.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(fmt.Sprintf("CREATE TABLE data (%s REAL)", strings.Join(benchmarkChartColumns, " REAL, "))); err != nil {
+		b.Fatalf("create: %v", err)
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		b.Fatalf("begin: %v", err)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(benchmarkChartColumns)), ",")
+	stmt, err := tx.Prepare(fmt.Sprintf("INSERT INTO data VALUES (%s)", placeholders))
+	if err != nil {
+		b.Fatalf("prepare: %v", err)
+	}
+	values := make([]interface{}, len(benchmarkChartColumns))
+	for row := 0; row < n; row++ {
+		values[0] = float64(1767225600 + row)
+		for i := 1; i < len(values); i++ {
+			if i >= 4 && row%7 == 0 {
+				values[i] = nil
+			} else {
+				values[i] = 5000 + float64(row*i)/10
+			}
+		}
+		if _, err := stmt.Exec(values...); err != nil {
+			b.Fatalf("insert: %v", err)
+		}
+	}
+	stmt.Close()
+	if err := tx.Commit(); err != nil {
+		b.Fatalf("commit: %v", err)
+	}
+	return db
+}
+
+// scanRowsBoxed is the row scan loadChartData used before the column buffers: a fresh []interface{}
+// per row and one boxed append per value per column
+func scanRowsBoxed(rows *sql.Rows, columns []string) (map[string][]interface{}, error) {
+	result := make(map[string][]interface{}, len(columns))
+	for _, col := range columns {
+		result[col] = make([]interface{}, 0)
+	}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		valuePtrs := make([]interface{}, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, err
+		}
+		for i, col := range columns {
+			result[col] = append(result[col], values[i])
+		}
+	}
+	return result, rows.Err()
+}
+
+// scanColumns is the current loadChartData scan
+func scanColumns(rows *sql.Rows, columns []string) (map[string][]interface{}, error) {
+	result := make(map[string][]interface{}, len(columns))
+	scanner := newColumnScanner(columns, config.ChartScanInitialCapacity)
+	for rows.Next() {
+		if err := scanner.scan(rows); err != nil {
+			return nil, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	scanner.into(result)
+	return result, nil
+}
+
+func benchmarkChartScan(b *testing.B, n int, scan func(*sql.Rows, []string) (map[string][]interface{}, error)) {
+	db := openBenchmarkDay(b, n)
+	query := fmt.Sprintf("SELECT %s FROM data ORDER BY timestamp", strings.Join(benchmarkChartColumns, ", "))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rows, err := db.Query(query)
+		if err != nil {
+			b.Fatalf("query: %v", err)
+		}
+		result, err := scan(rows, benchmarkChartColumns)
+		rows.Close()
+		if err != nil {
+			b.Fatalf("scan: %v", err)
+		}
+		if len(result["spot"]) != n {
+			b.Fatalf("scanned %d rows, want %d", len(result["spot"]), n)
+		}
+	}
+}
+
+// A regular session at the default interval is ~25k rows; the large case is above ParallelColumnConvertRows
+
+func BenchmarkChartScanRows5k(b *testing.B)     { benchmarkChartScan(b, 5000, scanRowsBoxed) }
+func BenchmarkChartScanColumns5k(b *testing.B)  { benchmarkChartScan(b, 5000, scanColumns) }
+func BenchmarkChartScanRows25k(b *testing.B)    { benchmarkChartScan(b, 25000, scanRowsBoxed) }
+func BenchmarkChartScanColumns25k(b *testing.B) { benchmarkChartScan(b, 25000, scanColumns) }
+
+// TestColumnScannerMatchesRowScan checks the column buffers produce what the row scan did (nil for NULL)
+func TestColumnScannerMatchesRowScan(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "SPX.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("CREATE TABLE data (timestamp REAL, spot REAL); INSERT INTO data VALUES (1, 5000.5), (2, NULL), (3, 5001)"); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	load := func(scan func(*sql.Rows, []string) (map[string][]interface{}, error)) map[string][]interface{} {
+		rows, err := db.Query("SELECT timestamp, spot FROM data ORDER BY timestamp")
+		if err != nil {
+			t.Fatalf("query: %v", err)
+		}
+		defer rows.Close()
+		result, err := scan(rows, []string{"timestamp", "spot"})
+		if err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		return result
+	}
+	boxed, columnar := load(scanRowsBoxed), load(scanColumns)
+	for _, col := range []string{"timestamp", "spot"} {
+		if fmt.Sprint(boxed[col]) != fmt.Sprint(columnar[col]) {
+			t.Errorf("%s: row scan %v, column scan %v", col, boxed[col], columnar[col])
+		}
+	}
+}
@@ -3,34 +3,76 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"path/filepath"
 	"sync"
 	"time"
 
+	"market-terminal/internal/config"
+
 	_ "modernc.org/sqlite" // Pure Go SQLite driver - full memory visibility
 )
 
 // ConnectionPool manages database connections with idle timeout
+// Each file gets separate read-only and read-write handles: readers never receive the writer's connection,
+// and the read-write handle is limited to a single SQLite connection, so all writes to a file go through
+// one connection in turn instead of competing for the write lock (SQLITE_BUSY)
+// At most maxSize handles stay open: opening another closes the least recently used idle one first
 type ConnectionPool struct {
 	mu                sync.RWMutex
-	connections       map[string]*pooledConnection
+	connections       map[connKey]*pooledConnection
 	maxSize           int
 	idleTimeout       time.Duration
 	cleanupInterval   time.Duration
 	cleanupTimer      *time.Timer
 	stopCleanup       chan struct{}
+	hits              int64 // GetConnection calls served by an open connection
+	misses            int64 // GetConnection calls that opened a connection
+	evictions         int64 // Connections closed to stay within maxSize
+	idleClosed        int64 // Connections closed by the idle timeout
+}
+
+// PoolStats describes a connection pool for GetPoolStats
+type PoolStats struct {
+	Open               int     `json:"open"`
+	MaxSize            int     `json:"max_size"`
+	Hits               int64   `json:"hits"`
+	Misses             int64   `json:"misses"`
+	HitRate            float64 `json:"hit_rate"` // Hits / (hits + misses), 0 before the first request
+	Evictions          int64   `json:"evictions"`
+	IdleClosed         int64   `json:"idle_closed"`
+	IdleTimeoutSec     float64 `json:"idle_timeout_sec"`
+	CleanupIntervalSec float64 `json:"cleanup_interval_sec"`
+}
+
+// readerPragmas are applied to every connection of a reader handle: read-only, wait for the writer's
+// checkpoints instead of failing, and the same cache/mmap sizes as the writer
+// (no journal_mode - the writer sets WAL, and readers simply follow the file's mode)
+const readerPragmas = "&_pragma=busy_timeout(10000)&_pragma=query_only(1)&_pragma=cache_size(-20000)" +
+	"&_pragma=temp_store(MEMORY)&_pragma=mmap_size(268435456)"
+
+// connKey identifies a pooled handle: one read-only and one read-write handle per file
+type connKey struct {
+	path     string
+	readOnly bool
 }
 
 type pooledConnection struct {
 	db          *sql.DB
 	lastUsed    time.Time
 	filepath    string
+	readOnly    bool
+}
+
+func (pc *pooledConnection) key() connKey {
+	return connKey{path: pc.filepath, readOnly: pc.readOnly}
 }
 
 // NewConnectionPool creates a new connection pool
 func NewConnectionPool(maxSize int, idleTimeout, cleanupInterval time.Duration) *ConnectionPool {
 	pool := &ConnectionPool{
-		connections:     make(map[string]*pooledConnection),
+		connections:     make(map[connKey]*pooledConnection),
 		maxSize:         maxSize,
 		idleTimeout:     idleTimeout,
 		cleanupInterval: cleanupInterval,
@@ -44,21 +86,26 @@ func NewConnectionPool(maxSize int, idleTimeout, cleanupInterval time.Duration)
 }
 
 // GetConnection gets or creates a database connection
+// Read-only callers get the file's reader handle, others its single-connection writer handle
+// Writer callers must not use the handle while holding a transaction or open rows on it (they would wait on themselves)
 func (p *ConnectionPool) GetConnection(filepath string, readOnly bool) (*sql.DB, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	key := connKey{path: filepath, readOnly: readOnly}
+
 	// Check if connection exists and is still valid
-	if pc, exists := p.connections[filepath]; exists {
+	if pc, exists := p.connections[key]; exists {
 		// Check if connection is still valid
 		if err := pc.db.Ping(); err == nil {
 			// Update last used time
 			pc.lastUsed = time.Now()
+			p.hits++
 			return pc.db, nil
 		}
 		// Connection is invalid - remove it
 		pc.db.Close()
-		delete(p.connections, filepath)
+		delete(p.connections, key)
 	}
 
 	// Create new connection
@@ -66,8 +113,9 @@ func (p *ConnectionPool) GetConnection(filepath string, readOnly bool) (*sql.DB,
 	var err error
 
 	if readOnly {
-		// Read-only connection
-		db, err = sql.Open("sqlite", fmt.Sprintf("file:%s?mode=ro", filepath))
+		// Reader: an ordinary WAL participant (sees every committed transaction without anyone checkpointing),
+		// kept read-only by query_only; mode=rw never creates a missing file
+		db, err = sql.Open("sqlite", fmt.Sprintf("file:%s?mode=rw%s", filepath, readerPragmas))
 	} else {
 		// Read-write connection
 		db, err = sql.Open("sqlite", filepath)
@@ -76,6 +124,13 @@ func (p *ConnectionPool) GetConnection(filepath string, readOnly bool) (*sql.DB,
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	p.misses++
+
+	if !readOnly {
+		// Single writer: database/sql queues callers for the one connection (which also keeps the PRAGMAs below)
+		db.SetMaxOpenConns(1)
+		db.SetMaxIdleConns(1)
+	}
 
 	// Configure connection
 	if err := p.configureConnection(db, readOnly); err != nil {
@@ -83,28 +138,35 @@ func (p *ConnectionPool) GetConnection(filepath string, readOnly bool) (*sql.DB,
 		return nil, fmt.Errorf("failed to configure connection: %w", err)
 	}
 
-	// Add to pool
-	p.connections[filepath] = &pooledConnection{
+	// Add to pool (making room first)
+	p.evictLocked(p.maxSize - 1)
+	p.connections[key] = &pooledConnection{
 		db:       db,
 		lastUsed: time.Now(),
 		filepath: filepath,
+		readOnly: readOnly,
 	}
 
 	return db, nil
 }
 
-// configureConnection sets SQLite PRAGMA options
+// configureConnection sets SQLite PRAGMA options on a writer's connection
+// Readers get theirs from readerPragmas in the DSN (database/sql may open several connections per reader
+// handle and each needs them); for a reader this only opens the first connection so a bad file fails here
 func (p *ConnectionPool) configureConnection(db *sql.DB, readOnly bool) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	if readOnly {
+		return db.PingContext(ctx)
+	}
+
 	conn, err := db.Conn(ctx)
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
 
-	// Common settings
 	_, err = conn.ExecContext(nil, "PRAGMA journal_mode=WAL")
 	if err != nil {
 		return err
@@ -134,29 +196,24 @@ func (p *ConnectionPool) configureConnection(db *sql.DB, readOnly bool) error {
 		// Ignore if not supported
 	}
 
-	if readOnly {
-		// Read-only specific settings
-		_, err = conn.ExecContext(nil, "PRAGMA query_only=1")
-		if err != nil {
-			return err
-		}
+	// Page size (only affects new databases)
+	_, err = conn.ExecContext(nil, "PRAGMA page_size=8192")
+	if err != nil {
+		// Ignore if database already exists
+	}
 
-		_, err = conn.ExecContext(nil, "PRAGMA read_uncommitted=1")
-		if err != nil {
-			return err
-		}
+	// Incremental auto-vacuum lets compaction reclaim free pages without rewriting the file
+	// (only affects new databases, like page_size)
+	_, err = conn.ExecContext(nil, "PRAGMA auto_vacuum=INCREMENTAL")
+	if err != nil {
+		// Ignore if database already exists
+	}
 
-		_, err = conn.ExecContext(nil, "PRAGMA busy_timeout=10000") // 10 seconds
-		if err != nil {
-			return err
-		}
-	} else {
-		// Write connection settings
-		// Page size (only affects new databases)
-		_, err = conn.ExecContext(nil, "PRAGMA page_size=8192")
-		if err != nil {
-			// Ignore if database already exists
-		}
+	// Writes within the process are serialized by the single connection; this only covers
+	// another process holding the write lock
+	_, err = conn.ExecContext(nil, "PRAGMA busy_timeout=10000") // 10 seconds
+	if err != nil {
+		return err
 	}
 
 	return nil
@@ -170,7 +227,10 @@ func (p *ConnectionPool) startCleanup() {
 			select {
 			case <-p.cleanupTimer.C:
 				p.cleanupIdleConnections()
-				p.cleanupTimer.Reset(p.cleanupInterval)
+				p.mu.RLock()
+				interval := p.cleanupInterval
+				p.mu.RUnlock()
+				p.cleanupTimer.Reset(interval)
 			case <-p.stopCleanup:
 				return
 			}
@@ -184,14 +244,69 @@ func (p *ConnectionPool) cleanupIdleConnections() {
 	defer p.mu.Unlock()
 
 	now := time.Now()
-	for filepath, pc := range p.connections {
+	for key, pc := range p.connections {
 		if now.Sub(pc.lastUsed) > p.idleTimeout {
 			pc.db.Close()
-			delete(p.connections, filepath)
+			delete(p.connections, key)
+			p.idleClosed++
 		}
 	}
 }
 
+// evictLocked closes least recently used connections until at most limit remain
+// Connections with a query in flight are skipped, so the pool can briefly exceed maxSize under load
+// Caller must hold p.mu
+func (p *ConnectionPool) evictLocked(limit int) {
+	for len(p.connections) > limit {
+		var oldest *pooledConnection
+		for _, pc := range p.connections {
+			if pc.db.Stats().InUse > 0 {
+				continue
+			}
+			if oldest == nil || pc.lastUsed.Before(oldest.lastUsed) {
+				oldest = pc
+			}
+		}
+		if oldest == nil {
+			return // Everything is busy
+		}
+		oldest.db.Close()
+		delete(p.connections, oldest.key())
+		p.evictions++
+	}
+}
+
+// Configure changes the pool limits; a smaller maxSize closes idle connections right away
+// The new cleanup interval applies from the next cleanup run
+func (p *ConnectionPool) Configure(maxSize int, idleTimeout, cleanupInterval time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maxSize = maxSize
+	p.idleTimeout = idleTimeout
+	p.cleanupInterval = cleanupInterval
+	p.evictLocked(maxSize)
+}
+
+// Stats returns the pool's size, limits and counters
+func (p *ConnectionPool) Stats() PoolStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	stats := PoolStats{
+		Open:               len(p.connections),
+		MaxSize:            p.maxSize,
+		Hits:               p.hits,
+		Misses:             p.misses,
+		Evictions:          p.evictions,
+		IdleClosed:         p.idleClosed,
+		IdleTimeoutSec:     p.idleTimeout.Seconds(),
+		CleanupIntervalSec: p.cleanupInterval.Seconds(),
+	}
+	if total := p.hits + p.misses; total > 0 {
+		stats.HitRate = float64(p.hits) / float64(total)
+	}
+	return stats
+}
+
 // Close closes all connections and stops cleanup
 // Ensures WAL checkpoint is performed before closing to clean up .db-wal and .db-shm files
 func (p *ConnectionPool) Close() error {
@@ -207,6 +322,11 @@ func (p *ConnectionPool) Close() error {
 	// Checkpoint WAL and close all connections
 	// This ensures WAL data is merged into main DB and WAL/SHM files are deleted
 	for _, pc := range p.connections {
+		if pc.readOnly {
+			pc.db.Close()
+			continue
+		}
+
 		// Perform WAL checkpoint before closing
 		// This merges WAL data into main DB and deletes .db-wal and .db-shm files
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -228,14 +348,97 @@ func (p *ConnectionPool) Close() error {
 	}
 	
 	// Clear connections map
-	p.connections = make(map[string]*pooledConnection)
+	p.connections = make(map[connKey]*pooledConnection)
 
 	return nil
 }
 
+// CloseConnection checkpoints and closes the connections for a single file (if open)
+// Used before the file is moved, encrypted or deleted
+// Returns an error if the checkpoint failed or a reader (e.g. another process) kept part of the WAL out of the
+// file: the connection is closed either way, and the committed rows left in -wal aren't in the .db yet
+func (p *ConnectionPool) CloseConnection(filepath string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Readers first, so the writer's TRUNCATE checkpoint isn't held back by them
+	if pc, exists := p.connections[connKey{path: filepath, readOnly: true}]; exists {
+		pc.db.Close()
+		delete(p.connections, pc.key())
+	}
+
+	pc, exists := p.connections[connKey{path: filepath, readOnly: false}]
+	if !exists {
+		return nil
+	}
+
+	var checkpointErr error
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if conn, err := pc.db.Conn(ctx); err != nil {
+		checkpointErr = fmt.Errorf("failed to checkpoint %s: %w", filepath, err)
+	} else {
+		// busy = 1 when a reader held the TRUNCATE checkpoint back
+		var busy, walFrames, checkpointed int
+		if err := conn.QueryRowContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &walFrames, &checkpointed); err != nil {
+			checkpointErr = fmt.Errorf("failed to checkpoint %s: %w", filepath, err)
+		} else if busy != 0 {
+			checkpointErr = fmt.Errorf("checkpoint of %s blocked by a reader (%d of %d WAL frames copied)", filepath, checkpointed, walFrames)
+		}
+		conn.Close()
+	}
+	cancel()
+
+	pc.db.Close()
+	delete(p.connections, pc.key())
+	return checkpointErr
+}
+
+// CloseConnectionsInDir closes every pooled connection for files in a directory
+// Returns the checkpoint errors (see CloseConnection); every connection is closed either way
+func (p *ConnectionPool) CloseConnectionsInDir(dir string) error {
+	p.mu.RLock()
+	paths := make([]string, 0)
+	seen := make(map[string]bool)
+	for key := range p.connections {
+		if filepath.Dir(key.path) == filepath.Clean(dir) && !seen[key.path] {
+			seen[key.path] = true
+			paths = append(paths, key.path)
+		}
+	}
+	p.mu.RUnlock()
+
+	var errs []error
+	for _, path := range paths {
+		if err := p.CloseConnection(path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // Size returns current pool size
 func (p *ConnectionPool) Size() int {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 	return len(p.connections)
 }
+
+// SetConnectionPoolSettings applies new limits to the loader's read pool
+func (dl *DataLoader) SetConnectionPoolSettings(cfg config.ConnectionPoolSettings) {
+	dl.pool.Configure(cfg.MaxSize, time.Duration(cfg.ReadIdleTimeoutSec)*time.Second, time.Duration(cfg.CleanupIntervalSec)*time.Second)
+}
+
+// PoolStats returns the loader's read pool statistics
+func (dl *DataLoader) PoolStats() PoolStats {
+	return dl.pool.Stats()
+}
+
+// SetConnectionPoolSettings applies new limits to the writer's pool
+func (dw *DataWriter) SetConnectionPoolSettings(cfg config.ConnectionPoolSettings) {
+	dw.pool.Configure(cfg.MaxSize, time.Duration(cfg.WriteIdleTimeoutSec)*time.Second, time.Duration(cfg.CleanupIntervalSec)*time.Second)
+}
+
+// PoolStats returns the writer's pool statistics
+func (dw *DataWriter) PoolStats() PoolStats {
+	return dw.pool.Stats()
+}
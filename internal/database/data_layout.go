@@ -0,0 +1,124 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/utils"
+)
+
+// LayoutMigrationResult summarizes a MigrateDataLayout run
+type LayoutMigrationResult struct {
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	DryRun  bool     `json:"dry_run"`
+	Days    int      `json:"days"`    // Day directories moved (or that would be moved)
+	Merged  int      `json:"merged"`  // Files moved into a day directory that already existed in the new layout
+	Reports int      `json:"reports"` // End-of-day reports moved
+	Errors  []string `json:"errors"`
+}
+
+// MigrateDataLayout moves every day directory of the other layout (and its end-of-day report) into layout
+// A day that already exists in the new layout gets the files it lacks; conflicting files are left in place and reported
+// Nothing may have the databases open: the --migrate-data-layout command runs it before the app starts
+func MigrateDataLayout(settings *config.Settings, layout string, dryRun bool) (*LayoutMigrationResult, error) {
+	if err := config.ValidateDataLayout(layout); err != nil {
+		return nil, err
+	}
+	if layout == "" {
+		layout = config.DataLayoutFlat
+	}
+	from := config.DataLayoutFlat
+	if layout == config.DataLayoutFlat {
+		from = config.DataLayoutISO
+	}
+
+	dataDir := settings.GetDataDirectory()
+	days, err := config.ListDayDirectoriesFor(dataDir, from, utils.GetMarketTimezone())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s day directories: %w", from, err)
+	}
+
+	result := &LayoutMigrationResult{From: from, To: layout, DryRun: dryRun, Errors: make([]string, 0)}
+	for _, day := range days {
+		target := config.DayDirectoryFor(dataDir, layout, day.Date)
+		if err := moveDayDirectory(day.Path, target, dryRun, result); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		result.Days++
+
+		report := day.Path + " report.json"
+		if _, err := os.Stat(report); err == nil {
+			if err := moveFile(report, target+" report.json", dryRun); err != nil {
+				result.Errors = append(result.Errors, err.Error())
+			} else {
+				result.Reports++
+			}
+		}
+		if from == config.DataLayoutISO && !dryRun {
+			removeEmptyParents(day.Path, dataDir)
+		}
+	}
+	return result, nil
+}
+
+// moveDayDirectory renames source to target, or moves its entries one by one when target already exists
+func moveDayDirectory(source, target string, dryRun bool, result *LayoutMigrationResult) error {
+	if _, err := os.Stat(target); os.IsNotExist(err) {
+		return moveFile(source, target, dryRun)
+	}
+
+	entries, err := os.ReadDir(source)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	conflicts := 0
+	for _, entry := range entries {
+		from, to := filepath.Join(source, entry.Name()), filepath.Join(target, entry.Name())
+		if _, err := os.Stat(to); err == nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s already exists - left %s in place", to, from))
+			conflicts++
+			continue
+		}
+		if err := moveFile(from, to, dryRun); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			conflicts++
+			continue
+		}
+		result.Merged++
+	}
+	if conflicts > 0 {
+		return fmt.Errorf("%s: %d file(s) not moved", source, conflicts)
+	}
+	if !dryRun {
+		os.Remove(source) // Empty now
+	}
+	return nil
+}
+
+// moveFile renames a file or directory, creating the target's parent directories
+func moveFile(source, target string, dryRun bool) error {
+	if dryRun {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+	}
+	if err := os.Rename(source, target); err != nil {
+		return fmt.Errorf("failed to move %s to %s: %w", source, target, err)
+	}
+	return nil
+}
+
+// removeEmptyParents removes the month and year directories an iso day directory left empty
+func removeEmptyParents(dayPath, dataDir string) {
+	dataDir = filepath.Clean(dataDir)
+	for dir := filepath.Dir(dayPath); dir != dataDir && dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			return // Not empty (or already gone)
+		}
+	}
+}
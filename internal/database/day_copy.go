@@ -0,0 +1,76 @@
+package database
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CopyDay copies a market date's ticker databases (plain and encrypted) into dst as consistent files, e.g. for
+// upload: pending rows are flushed, then flushes are held off while the day's connections are checkpointed
+// (TRUNCATE) and closed and the files copied. Copies keep the source modification time
+// Returns the names of the files copied
+func (dw *DataWriter) CopyDay(date time.Time, dst string) ([]string, error) {
+	dir := filepath.Dir(dw.getDBPath("_", date))
+	dw.flushAllPending("CopyDay")
+
+	dw.dayFilesMu.Lock()
+	defer dw.dayFilesMu.Unlock()
+	if err := dw.pool.CloseConnectionsInDir(dir); err != nil {
+		dw.debugPrint(fmt.Sprintf("CopyDay: %v", err), "error")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	if err := os.MkdirAll(dst, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+
+	copied := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !(strings.HasSuffix(name, ".db") || strings.HasSuffix(name, ".db"+encryptedFileExtension)) {
+			continue
+		}
+		src := filepath.Join(dir, name)
+		// A WAL left after the checkpoint (e.g. a reader outside this process held it back) isn't in the .db yet
+		if info, err := os.Stat(src + "-wal"); err == nil && info.Size() > 0 {
+			return copied, fmt.Errorf("%s still has %d bytes in its WAL after the checkpoint", src, info.Size())
+		}
+		if err := copyFileWithModTime(src, filepath.Join(dst, name)); err != nil {
+			return copied, err
+		}
+		copied = append(copied, name)
+	}
+	return copied, nil
+}
+
+// copyFileWithModTime copies src to dst and gives the copy src's modification time
+func copyFileWithModTime(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
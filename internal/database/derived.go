@@ -0,0 +1,223 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+
+	"market-terminal/internal/config"
+)
+
+// Derived series, computed by the writer on flush and stored as ordinary columns, so charts, exports and replays
+// all read the same values without recomputing them
+const (
+	SpotVWAPColumn = "spot_vwap" // Session volume-weighted average of spot (equal weights for rows without volume)
+)
+
+// derivedDistanceLevels get a <level>_dist column (spot - level) and a <level>_dist_avg column (its rolling
+// average over DerivedRollingWindowSec)
+var derivedDistanceLevels = []string{"zero_gamma"}
+
+// DerivedColumns returns the columns the writer computes on flush
+func DerivedColumns() []string {
+	columns := []string{SpotVWAPColumn}
+	for _, level := range derivedDistanceLevels {
+		columns = append(columns, level+"_dist", level+"_dist_avg")
+	}
+	return columns
+}
+
+// derivedSample is a level distance inside the rolling window
+type derivedSample struct {
+	timestamp float64
+	distance  float64
+}
+
+// derivedState carries a ticker's session aggregates from one flush to the next
+type derivedState struct {
+	dbPath        string
+	lastTimestamp float64
+	weightedSpot  float64                    // Sum of spot * weight
+	weight        float64                    // Sum of weights
+	windows       map[string][]derivedSample // level -> distances within the rolling window, oldest first
+}
+
+func newDerivedState(dbPath string) *derivedState {
+	return &derivedState{dbPath: dbPath, windows: make(map[string][]derivedSample)}
+}
+
+// add folds a row into the aggregates and returns its derived values (nil without spot)
+func (s *derivedState) add(timestamp float64, spot, volume float64, levels map[string]float64) map[string]float64 {
+	s.lastTimestamp = timestamp
+	if !(spot > 0) {
+		return nil
+	}
+	weight := 1.0
+	if volume > 0 {
+		weight = volume
+	}
+	s.weightedSpot += spot * weight
+	s.weight += weight
+
+	values := map[string]float64{SpotVWAPColumn: s.weightedSpot / s.weight}
+	for _, level := range derivedDistanceLevels {
+		value, ok := levels[level]
+		if !ok || !(value > 0) {
+			continue
+		}
+		window := append(s.windows[level], derivedSample{timestamp: timestamp, distance: spot - value})
+		start := 0
+		for start < len(window) && window[start].timestamp <= timestamp-config.DerivedRollingWindowSec {
+			start++
+		}
+		window = window[start:]
+		s.windows[level] = window
+
+		sum := 0.0
+		for _, sample := range window {
+			sum += sample.distance
+		}
+		values[level+"_dist"] = spot - value
+		values[level+"_dist_avg"] = sum / float64(len(window))
+	}
+	return values
+}
+
+// addDerivedSeries sets the derived columns on each write (in timestamp order), continuing the ticker's session
+// aggregates. The aggregates are rebuilt from the day's stored rows when the writer has none for this database or
+// the batch goes back in time (after a restart, a failed flush or when a replay rewrites the day)
+func (dw *DataWriter) addDerivedSeries(ctx context.Context, db *sql.DB, ticker, dbPath string, writes []*PendingWrite) error {
+	if len(writes) == 0 {
+		return nil
+	}
+	ordered := append([]*PendingWrite(nil), writes...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Timestamp < ordered[j].Timestamp })
+
+	dw.mu.Lock()
+	state := dw.derived[ticker]
+	dw.mu.Unlock()
+	if state == nil || state.dbPath != dbPath || state.lastTimestamp >= ordered[0].Timestamp {
+		var err error
+		if state, err = loadDerivedState(ctx, db, dbPath, ordered[0].Timestamp); err != nil {
+			return err
+		}
+	}
+
+	for _, write := range ordered {
+		levels := make(map[string]float64, len(derivedDistanceLevels))
+		for _, level := range derivedDistanceLevels {
+			if value, ok := scalarFloat(write.Scalars[level]); ok {
+				levels[level] = value
+			}
+		}
+		spot, _ := scalarFloat(write.Scalars["spot"])
+		volume, _ := scalarFloat(write.Scalars["volume"])
+		for column, value := range state.add(write.Timestamp, spot, volume, levels) {
+			write.Scalars[column] = value
+		}
+	}
+
+	dw.mu.Lock()
+	dw.derived[ticker] = state
+	dw.mu.Unlock()
+	return nil
+}
+
+// loadDerivedState replays the day's stored rows before a timestamp into fresh aggregates
+// (the table's spot, volume and level columns exist: flushDate ensures them first)
+func loadDerivedState(ctx context.Context, db *sql.DB, dbPath string, before float64) (*derivedState, error) {
+	state := newDerivedState(dbPath)
+	columns := "timestamp, spot, volume"
+	for _, level := range derivedDistanceLevels {
+		columns += ", " + level
+	}
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM ticker_data WHERE timestamp < ? ORDER BY timestamp", columns), before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rows for derived series: %w", err)
+	}
+	defer rows.Close()
+
+	values := make([]interface{}, 3+len(derivedDistanceLevels))
+	pointers := make([]interface{}, len(values))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("failed to scan row for derived series: %w", err)
+		}
+		levels := make(map[string]float64, len(derivedDistanceLevels))
+		for i, level := range derivedDistanceLevels {
+			if value, ok := scalarFloat(values[3+i]); ok {
+				levels[level] = value
+			}
+		}
+		timestamp, _ := scalarFloat(values[0])
+		spot, _ := scalarFloat(values[1])
+		volume, _ := scalarFloat(values[2])
+		state.add(timestamp, spot, volume, levels)
+	}
+	return state, rows.Err()
+}
+
+// DerivedScriptFunc computes extra columns for a row from its numeric scalars (the scripting engine's Columns)
+type DerivedScriptFunc func(ticker string, timestamp float64, row map[string]float64) map[string]float64
+
+// SetDerivedScripts sets the function adding script columns on flush (nil disables them)
+func (dw *DataWriter) SetDerivedScripts(fn DerivedScriptFunc) {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	dw.derivedScripts = fn
+}
+
+// addScriptColumns runs the derived scripts on each write (after the built-in derived series, so scripts can use
+// them) and returns the columns they added that are not in known yet. A script column never replaces a value
+// already in the row, and values outside the column check are dropped rather than failing the insert
+func (dw *DataWriter) addScriptColumns(ticker string, writes []*PendingWrite, known map[string]bool) []string {
+	dw.mu.RLock()
+	fn := dw.derivedScripts
+	dw.mu.RUnlock()
+	if fn == nil {
+		return nil
+	}
+
+	var added []string
+	for _, write := range writes {
+		row := make(map[string]float64, len(write.Scalars))
+		for field, value := range write.Scalars {
+			if v, ok := scalarFloat(value); ok {
+				row[field] = v
+			}
+		}
+		for column, value := range fn(ticker, write.Timestamp, row) {
+			if _, exists := write.Scalars[column]; exists || column == "timestamp" || column == "profiles_blob" ||
+				math.Abs(value) >= config.ScalarColumnMaxMagnitude {
+				continue
+			}
+			write.Scalars[column] = value
+			if !known[column] {
+				known[column] = true
+				added = append(added, column)
+			}
+		}
+	}
+	return added
+}
+
+// scalarFloat converts a row or scanned value to float64
+func scalarFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
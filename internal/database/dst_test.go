@@ -0,0 +1,114 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/utils"
+)
+
+// Rows are filed under the market date at the time they are written (8:30 ET rollover, weekends under
+// Friday), whatever the UTC offset of the day. 2026 DST starts Sunday March 8 and ends Sunday November 1
+
+func TestWriteDataEntryDSTDates(t *testing.T) {
+	tests := []struct {
+		name   string
+		now    time.Time
+		want   string // Market date the row is filed under
+		legacy string // Day directory name (legacy layout)
+	}{
+		{"Friday before spring forward", time.Date(2026, 3, 6, 15, 59, 0, 0, utils.MARKET_TIMEZONE), "2026-03-06", "data 03.06.2026"},
+		{"spring forward Sunday after the jump", time.Date(2026, 3, 8, 3, 30, 0, 0, utils.MARKET_TIMEZONE), "2026-03-06", "data 03.06.2026"},
+		{"first EDT Monday before rollover", time.Date(2026, 3, 9, 12, 29, 0, 0, time.UTC), "2026-03-06", "data 03.06.2026"},
+		{"first EDT Monday at rollover", time.Date(2026, 3, 9, 12, 30, 0, 0, time.UTC), "2026-03-09", "data 03.09.2026"},
+		{"first EDT Monday open", time.Date(2026, 3, 9, 9, 30, 0, 0, utils.MARKET_TIMEZONE), "2026-03-09", "data 03.09.2026"},
+		{"first EDT Monday after 20:00 (next day in UTC)", time.Date(2026, 3, 10, 0, 30, 0, 0, time.UTC), "2026-03-09", "data 03.09.2026"},
+		{"fall back Sunday, second 1:30", time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC), "2026-10-30", "data 10.30.2026"},
+		{"first EST Monday, 12:30 UTC is before rollover", time.Date(2026, 11, 2, 12, 30, 0, 0, time.UTC), "2026-10-30", "data 10.30.2026"},
+		{"first EST Monday, 13:30 UTC is rollover", time.Date(2026, 11, 2, 13, 30, 0, 0, time.UTC), "2026-11-02", "data 11.02.2026"},
+		{"first EST Monday close", time.Date(2026, 11, 2, 16, 0, 0, 0, utils.MARKET_TIMEZONE), "2026-11-02", "data 11.02.2026"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := config.GetDefaultSettings()
+			settings.DataDirectory = filepath.Join(t.TempDir(), "data")
+			dw := NewDataWriter(settings, func(string, string) {})
+			defer dw.Close()
+			dw.SetClock(utils.NewSimulatedClock(tt.now, 0))
+
+			timestamp := float64(tt.now.Unix())
+			if err := dw.WriteDataEntry("SPX", timestamp, map[string]interface{}{"spot": 5000.0}, false); err != nil {
+				t.Fatalf("WriteDataEntry: %v", err)
+			}
+			if err := dw.FlushTicker("SPX"); err != nil {
+				t.Fatalf("FlushTicker: %v", err)
+			}
+
+			want, _ := utils.ParseDateInET(tt.want)
+			dbPath := filepath.Join(settings.DayDirectory(want), "SPX.db")
+			if filepath.Base(filepath.Dir(dbPath)) != tt.legacy {
+				t.Errorf("day directory for %s = %q, want %q", tt.want, filepath.Base(filepath.Dir(dbPath)), tt.legacy)
+			}
+			if _, err := os.Stat(dbPath); err != nil {
+				dirs, _ := filepath.Glob(settings.DataDirectory + " *")
+				t.Fatalf("row not filed under %s (day directories: %v)", tt.want, dirs)
+			}
+
+			// The row keeps the instant it was collected, not a time shifted by the offset
+			db, err := dw.pool.GetConnection(dbPath, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var stored float64
+			if err := db.QueryRow("SELECT timestamp FROM ticker_data").Scan(&stored); err != nil {
+				t.Fatalf("read row: %v", err)
+			}
+			if stored != timestamp {
+				t.Errorf("stored timestamp %.0f, want %.0f", stored, timestamp)
+			}
+		})
+	}
+}
+
+func TestDayDirectoryDSTDates(t *testing.T) {
+	tests := []struct {
+		name   string
+		date   time.Time
+		legacy string
+		iso    string
+	}{
+		{"Monday at UTC midnight", time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), "data 03.09.2026", "2026/03/09"},
+		{"Monday at ET midnight", time.Date(2026, 3, 9, 0, 0, 0, 0, utils.MARKET_TIMEZONE), "data 03.09.2026", "2026/03/09"},
+		{"spring forward Sunday files under Friday", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), "data 03.06.2026", "2026/03/06"},
+		{"fall back Sunday files under Friday", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), "data 10.30.2026", "2026/10/30"},
+		{"first EST Monday at UTC midnight", time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC), "data 11.02.2026", "2026/11/02"},
+		{"first EST Monday evening", time.Date(2026, 11, 2, 23, 0, 0, 0, utils.MARKET_TIMEZONE), "data 11.02.2026", "2026/11/02"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, layout := range []string{"", config.DataLayoutISO} {
+				settings := config.GetDefaultSettings()
+				settings.DataDirectory = filepath.Join(root, "data")
+				settings.DataLayout = layout
+				dw := NewDataWriter(settings, func(string, string) {})
+
+				got, err := filepath.Rel(root, filepath.Dir(dw.getDBPath("SPX", tt.date)))
+				dw.Close()
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := tt.legacy
+				if layout == config.DataLayoutISO {
+					want = filepath.Join("data", filepath.FromSlash(tt.iso))
+				}
+				if got != want {
+					t.Errorf("layout %q: day directory for %s = %q, want %q", layout, tt.date.Format(time.RFC3339), got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package database
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"market-terminal/internal/utils"
+)
+
+// Encrypted database file format (file-level AES-256-GCM, SQLite can't open these directly):
+//
+//	magic (8 bytes) | base nonce (12 bytes) | chunks...
+//	chunk: ciphertext length (4 bytes, big endian) | ciphertext (plaintext chunk + 16 byte tag)
+//
+// Each chunk uses the base nonce XOR its index, and the index + final flag as additional data,
+// so chunks can't be reordered or truncated without failing authentication.
+const (
+	encryptedFileMagic     = "MGTENC01"
+	encryptedFileExtension = ".enc"
+	encryptionChunkSize    = 1 << 20 // 1MB plaintext per chunk
+	encryptionKeySize      = 32      // AES-256
+)
+
+// ErrEncryptionKeyRequired is returned when an encrypted database is read without a key
+var ErrEncryptionKeyRequired = errors.New("database is encrypted and no encryption key is available")
+
+// EncryptedPath returns the encrypted file path for a database path
+func EncryptedPath(dbPath string) string {
+	return dbPath + encryptedFileExtension
+}
+
+// chunkNonce derives the nonce for a chunk from the base nonce
+func chunkNonce(base []byte, index uint32) []byte {
+	nonce := make([]byte, len(base))
+	copy(nonce, base)
+	var idx [4]byte
+	binary.BigEndian.PutUint32(idx[:], index)
+	for i := 0; i < 4; i++ {
+		nonce[len(nonce)-4+i] ^= idx[i]
+	}
+	return nonce
+}
+
+// chunkAAD builds the additional authenticated data for a chunk
+func chunkAAD(index uint32, final bool) []byte {
+	aad := make([]byte, 5)
+	binary.BigEndian.PutUint32(aad, index)
+	if final {
+		aad[4] = 1
+	}
+	return aad
+}
+
+// newGCM creates an AES-GCM cipher for a key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != encryptionKeySize {
+		return nil, fmt.Errorf("invalid encryption key size: %d (expected %d)", len(key), encryptionKeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptFile encrypts src into dst (written atomically via a temp file)
+func EncryptFile(src, dst string, key []byte) error {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open source: %w", err)
+	}
+	defer in.Close()
+
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create destination: %w", err)
+	}
+
+	writeErr := func() error {
+		w := bufio.NewWriter(out)
+		baseNonce := make([]byte, gcm.NonceSize())
+		if _, err := rand.Read(baseNonce); err != nil {
+			return fmt.Errorf("failed to generate nonce: %w", err)
+		}
+		if _, err := w.WriteString(encryptedFileMagic); err != nil {
+			return err
+		}
+		if _, err := w.Write(baseNonce); err != nil {
+			return err
+		}
+
+		// Read one chunk ahead so the last chunk can be flagged as final
+		buf := make([]byte, encryptionChunkSize)
+		next := make([]byte, encryptionChunkSize)
+		n, err := io.ReadFull(in, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return fmt.Errorf("failed to read source: %w", err)
+		}
+		var index uint32
+		for {
+			m, nextErr := io.ReadFull(in, next)
+			if nextErr != nil && nextErr != io.EOF && nextErr != io.ErrUnexpectedEOF {
+				return fmt.Errorf("failed to read source: %w", nextErr)
+			}
+			final := m == 0
+
+			sealed := gcm.Seal(nil, chunkNonce(baseNonce, index), buf[:n], chunkAAD(index, final))
+			var length [4]byte
+			binary.BigEndian.PutUint32(length[:], uint32(len(sealed)))
+			if _, err := w.Write(length[:]); err != nil {
+				return err
+			}
+			if _, err := w.Write(sealed); err != nil {
+				return err
+			}
+
+			if final {
+				break
+			}
+			buf, next = next, buf
+			n = m
+			index++
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		// On disk before the rename, so a crash can't leave a renamed but empty file
+		return out.Sync()
+	}()
+
+	if closeErr := out.Close(); writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to encrypt %s: %w", src, writeErr)
+	}
+
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to finalize encrypted file: %w", err)
+	}
+	if err := syncDir(filepath.Dir(dst)); err != nil {
+		return fmt.Errorf("failed to sync %s: %w", filepath.Dir(dst), err)
+	}
+	return nil
+}
+
+// syncDir flushes a directory's entries (a rename) to disk
+// Windows can't open a directory for syncing; NTFS journals the rename itself
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// VerifyEncryptedFile decrypts an encrypted file and checks it matches the plaintext file byte for byte
+// (SHA-256), without writing the decrypted copy
+func VerifyEncryptedFile(plaintext, encrypted string, key []byte) error {
+	in, err := os.Open(plaintext)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", plaintext, err)
+	}
+	defer in.Close()
+	want := sha256.New()
+	if _, err := io.Copy(want, in); err != nil {
+		return fmt.Errorf("failed to read %s: %w", plaintext, err)
+	}
+
+	got := sha256.New()
+	if err := decryptStream(encrypted, key, got); err != nil {
+		return fmt.Errorf("failed to verify %s: %w", encrypted, err)
+	}
+	if !bytes.Equal(got.Sum(nil), want.Sum(nil)) {
+		return fmt.Errorf("%s does not decrypt to %s", encrypted, plaintext)
+	}
+	return nil
+}
+
+// DecryptFile decrypts src into dst (written atomically via a temp file)
+func DecryptFile(src, dst string, key []byte) error {
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create destination: %w", err)
+	}
+
+	readErr := func() error {
+		w := bufio.NewWriter(out)
+		if err := decryptStream(src, key, w); err != nil {
+			return err
+		}
+		return w.Flush()
+	}()
+
+	if closeErr := out.Close(); readErr == nil {
+		readErr = closeErr
+	}
+	if readErr != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to decrypt %s: %w", src, readErr)
+	}
+
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to finalize decrypted file: %w", err)
+	}
+	return nil
+}
+
+// decryptStream authenticates and decrypts src chunk by chunk into w
+func decryptStream(src string, key []byte, w io.Writer) error {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open source: %w", err)
+	}
+	defer in.Close()
+	r := bufio.NewReader(in)
+
+	magic := make([]byte, len(encryptedFileMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != encryptedFileMagic {
+		return fmt.Errorf("not an encrypted database file: %s", src)
+	}
+	baseNonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(r, baseNonce); err != nil {
+		return fmt.Errorf("failed to read nonce: %w", err)
+	}
+
+	var index uint32
+	var length [4]byte
+	for {
+		if _, err := io.ReadFull(r, length[:]); err != nil {
+			return fmt.Errorf("truncated encrypted file (chunk %d): %w", index, err)
+		}
+		size := binary.BigEndian.Uint32(length[:])
+		if size > encryptionChunkSize+uint32(gcm.Overhead()) {
+			return fmt.Errorf("invalid chunk size %d", size)
+		}
+		sealed := make([]byte, size)
+		if _, err := io.ReadFull(r, sealed); err != nil {
+			return fmt.Errorf("truncated encrypted file (chunk %d): %w", index, err)
+		}
+
+		// Final chunk is the one followed by EOF
+		_, peekErr := r.Peek(1)
+		final := peekErr == io.EOF
+
+		plain, err := gcm.Open(nil, chunkNonce(baseNonce, index), sealed, chunkAAD(index, final))
+		if err != nil {
+			return fmt.Errorf("authentication failed (wrong key or corrupted file): %w", err)
+		}
+		if _, err := w.Write(plain); err != nil {
+			return err
+		}
+		if final {
+			return nil
+		}
+		index++
+	}
+}
+
+// HasPlaintextDay reports whether a market date has unencrypted ticker databases
+func (dw *DataWriter) HasPlaintextDay(date time.Time) bool {
+	matches, _ := filepath.Glob(filepath.Join(dw.settings.DayDirectory(utils.MarketMidnight(date)), "*.db"))
+	return len(matches) > 0
+}
+
+// EncryptDay encrypts every ticker database for a completed market date (call it once the market date has rolled
+// over, so nothing collects into the day any more)
+// Pending rows are flushed first; flushes then wait until the day's files are replaced, and rows for the day
+// arriving after that are refused rather than recreating a plaintext database
+// Plaintext .db files (and their -wal/-shm files) are removed once the encrypted file is on disk and decrypts
+// to the same bytes. A database whose -wal still holds rows after the checkpoint (held back by a reader) is left
+// in plaintext: only the .db is encrypted, so removing the -wal would lose them
+// Returns the number of databases encrypted, and an error naming the databases left in plaintext
+func (dw *DataWriter) EncryptDay(date time.Time, key []byte) (int, error) {
+	dir := filepath.Dir(dw.getDBPath("_", date))
+	dw.flushAllPending("EncryptDay")
+
+	dw.dayFilesMu.Lock()
+	defer dw.dayFilesMu.Unlock()
+
+	// Checkpoint and close the day's connections, so the -wal content is in the .db that gets encrypted
+	// (a failed checkpoint shows up below as a non-empty -wal)
+	if err := dw.pool.CloseConnectionsInDir(dir); err != nil {
+		dw.debugPrint(fmt.Sprintf("EncryptDay: %v", err), "error")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read data directory: %w", err)
+	}
+
+	encrypted := 0
+	var skipped []error
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".db") {
+			continue
+		}
+		dbPath := filepath.Join(dir, entry.Name())
+
+		if info, err := os.Stat(dbPath + "-wal"); err == nil && info.Size() > 0 {
+			skipped = append(skipped, fmt.Errorf("%s still has %d bytes in its WAL after the checkpoint", dbPath, info.Size()))
+			continue
+		}
+		if err := EncryptFile(dbPath, EncryptedPath(dbPath), key); err != nil {
+			skipped = append(skipped, err)
+			continue
+		}
+		if err := VerifyEncryptedFile(dbPath, EncryptedPath(dbPath), key); err != nil {
+			os.Remove(EncryptedPath(dbPath))
+			skipped = append(skipped, err)
+			continue
+		}
+		for _, suffix := range []string{"", "-wal", "-shm"} {
+			if err := os.Remove(dbPath + suffix); err != nil && !os.IsNotExist(err) {
+				dw.debugPrint(fmt.Sprintf("EncryptDay: Failed to remove plaintext %s: %v", dbPath+suffix, err), "error")
+			}
+		}
+		encrypted++
+	}
+
+	dw.debugPrint(fmt.Sprintf("EncryptDay: Encrypted %d database(s) in %s", encrypted, dir), "writer")
+	if len(skipped) > 0 {
+		return encrypted, fmt.Errorf("%d database(s) left unencrypted: %w", len(skipped), errors.Join(skipped...))
+	}
+	return encrypted, nil
+}
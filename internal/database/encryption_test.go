@@ -0,0 +1,134 @@
+package database
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/utils"
+)
+
+// newEncryptionTestWriter writes a few rows for SPX on a market date and returns the writer and database path
+func newEncryptionTestWriter(t *testing.T) (*DataWriter, time.Time, string) {
+	t.Helper()
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, utils.MARKET_TIMEZONE)
+	settings := config.GetDefaultSettings()
+	settings.DataDirectory = filepath.Join(t.TempDir(), "data")
+	dw := NewDataWriter(settings, func(string, string) {})
+	t.Cleanup(func() { dw.Close() })
+	dw.SetClock(utils.NewSimulatedClock(day.Add(10*time.Hour), 0))
+
+	for i := 0; i < 3; i++ {
+		timestamp := float64(day.Add(10*time.Hour + time.Duration(i)*time.Minute).Unix())
+		if err := dw.WriteDataEntry("SPX", timestamp, map[string]interface{}{"spot": 5000.0 + float64(i)}, false); err != nil {
+			t.Fatalf("WriteDataEntry: %v", err)
+		}
+	}
+	if err := dw.FlushTicker("SPX"); err != nil {
+		t.Fatalf("FlushTicker: %v", err)
+	}
+	return dw, day, dw.getDBPath("SPX", day)
+}
+
+var encryptionTestKey = bytes.Repeat([]byte{7}, encryptionKeySize)
+
+func TestEncryptDayRoundTrip(t *testing.T) {
+	dw, day, dbPath := newEncryptionTestWriter(t)
+
+	count, err := dw.EncryptDay(day, encryptionTestKey)
+	if err != nil || count != 1 {
+		t.Fatalf("EncryptDay = %d, %v; want 1 database", count, err)
+	}
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		if _, err := os.Stat(dbPath + suffix); !os.IsNotExist(err) {
+			t.Errorf("plaintext %s still exists after encryption", filepath.Base(dbPath+suffix))
+		}
+	}
+
+	decrypted := filepath.Join(t.TempDir(), "SPX.db")
+	if err := DecryptFile(EncryptedPath(dbPath), decrypted, encryptionTestKey); err != nil {
+		t.Fatalf("DecryptFile: %v", err)
+	}
+	db, err := sql.Open("sqlite", decrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var rows int
+	if err := db.QueryRow("SELECT COUNT(*) FROM ticker_data").Scan(&rows); err != nil || rows != 3 {
+		t.Errorf("decrypted database has %d rows (%v), want 3", rows, err)
+	}
+}
+
+func TestEncryptDayKeepsPlaintextWithUncheckpointedWAL(t *testing.T) {
+	dw, day, dbPath := newEncryptionTestWriter(t)
+
+	// Checkpoints wait out the busy timeout for the reader; keep the test short
+	writer, err := dw.pool.GetConnection(dbPath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.Exec("PRAGMA busy_timeout=100"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A reader in another handle holds a snapshot, so the TRUNCATE checkpoint can't empty the WAL
+	reader, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	tx, err := reader.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	var rows int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM ticker_data").Scan(&rows); err != nil {
+		t.Fatal(err)
+	}
+
+	timestamp := float64(day.Add(11 * time.Hour).Unix())
+	if err := dw.WriteDataEntry("SPX", timestamp, map[string]interface{}{"spot": 5010.0}, false); err != nil {
+		t.Fatalf("WriteDataEntry: %v", err)
+	}
+	if err := dw.FlushTicker("SPX"); err != nil {
+		t.Fatalf("FlushTicker: %v", err)
+	}
+
+	count, err := dw.EncryptDay(day, encryptionTestKey)
+	if err == nil || count != 0 {
+		t.Fatalf("EncryptDay with a blocked checkpoint = %d, %v; want an error and nothing encrypted", count, err)
+	}
+	if _, err := os.Stat(EncryptedPath(dbPath)); !os.IsNotExist(err) {
+		t.Error("encrypted file written while rows were still in the WAL")
+	}
+	if info, err := os.Stat(dbPath + "-wal"); err != nil || info.Size() == 0 {
+		t.Errorf("WAL with the uncheckpointed rows was removed (%v)", err)
+	}
+}
+
+func TestVerifyEncryptedFileDetectsMismatch(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "a.db")
+	other := filepath.Join(dir, "b.db")
+	os.WriteFile(plain, bytes.Repeat([]byte("a"), encryptionChunkSize+10), 0600)
+	os.WriteFile(other, []byte("b"), 0600)
+
+	if err := EncryptFile(plain, EncryptedPath(plain), encryptionTestKey); err != nil {
+		t.Fatalf("EncryptFile: %v", err)
+	}
+	if err := VerifyEncryptedFile(plain, EncryptedPath(plain), encryptionTestKey); err != nil {
+		t.Errorf("VerifyEncryptedFile of a matching file: %v", err)
+	}
+	if err := VerifyEncryptedFile(other, EncryptedPath(plain), encryptionTestKey); err == nil {
+		t.Error("VerifyEncryptedFile accepted a file that doesn't match the plaintext")
+	}
+	if err := VerifyEncryptedFile(plain, EncryptedPath(plain), bytes.Repeat([]byte{8}, encryptionKeySize)); err == nil {
+		t.Error("VerifyEncryptedFile accepted the wrong key")
+	}
+}
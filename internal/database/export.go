@@ -0,0 +1,68 @@
+package database
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportCSV writes a ticker's day as CSV (timestamp first, then the requested scalar columns) and returns the
+// number of rows written; an empty column list exports every scalar column. NULL values are left empty
+func (dl *DataLoader) ExportCSV(ticker string, date time.Time, columns []string, w io.Writer) (int, error) {
+	dbPath := dl.getDBPath(ticker, date)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return 0, fmt.Errorf("no data for %s on %s", ticker, date.Format("2006-01-02"))
+	}
+	db, err := dl.pool.GetConnection(dbPath, true) // Read-only
+	if err != nil {
+		return 0, fmt.Errorf("failed to get connection: %w", err)
+	}
+	existing, err := dl.getExistingColumns(db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get existing columns: %w", err)
+	}
+
+	if len(columns) == 0 {
+		for col := range existing {
+			if col != "timestamp" && !strings.HasSuffix(col, "_blob") {
+				columns = append(columns, col)
+			}
+		}
+		sort.Strings(columns)
+	} else {
+		for _, col := range columns {
+			if !existing[sanitizeFieldName(col)] {
+				return 0, fmt.Errorf("%s on %s has no column %q", ticker, date.Format("2006-01-02"), col)
+			}
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append([]string{"timestamp"}, columns...)); err != nil {
+		return 0, err
+	}
+	rows := 0
+	record := make([]string, len(columns)+1)
+	err = dl.StreamRows(ticker, date, columns, func(timestamp float64, row map[string]float64) error {
+		record[0] = strconv.FormatFloat(timestamp, 'f', 3, 64)
+		for i, col := range columns {
+			record[i+1] = ""
+			if value := row[col]; !math.IsNaN(value) {
+				record[i+1] = strconv.FormatFloat(value, 'f', -1, 64)
+			}
+		}
+		rows++
+		return writer.Write(record)
+	})
+	if err != nil {
+		return rows, err
+	}
+	writer.Flush()
+	return rows, writer.Error()
+}
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Level cross directions
+const (
+	CrossUp   = "up"   // spot moved from below the level to above it
+	CrossDown = "down" // spot moved from above the level to below it
+)
+
+// LevelCrossEvent is a moment spot crossed a gamma level (zero_gamma, major_pos_vol, major_neg_vol)
+type LevelCrossEvent struct {
+	ID         int64   `json:"id"`
+	Timestamp  float64 `json:"timestamp"` // Row time of the cross (Unix seconds)
+	Level      string  `json:"level"`     // Level column that was crossed
+	Direction  string  `json:"direction"` // up, down
+	Spot       float64 `json:"spot"`
+	LevelValue float64 `json:"level_value"` // The level at the time of the cross
+}
+
+// AddLevelCrossEvents appends cross events to the ticker's events table for a market date
+func (dw *DataWriter) AddLevelCrossEvents(ticker string, date time.Time, events []LevelCrossEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	db, err := dw.pool.GetConnection(dw.getDBPath(ticker, date), false)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS level_cross_events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		timestamp REAL NOT NULL,
+		level TEXT NOT NULL,
+		direction TEXT NOT NULL,
+		spot REAL NOT NULL,
+		level_value REAL NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("failed to create level_cross_events table: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	for _, event := range events {
+		if _, err := tx.Exec("INSERT INTO level_cross_events (timestamp, level, direction, spot, level_value) VALUES (?, ?, ?, ?, ?)",
+			event.Timestamp, event.Level, event.Direction, event.Spot, event.LevelValue); err != nil {
+			return fmt.Errorf("failed to save level cross event: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// LoadLevelCrossEvents loads a ticker's level cross events for a market date, oldest first
+// Returns an empty list if the day has no database or no events
+func (dl *DataLoader) LoadLevelCrossEvents(ticker string, date time.Time) ([]LevelCrossEvent, error) {
+	events := make([]LevelCrossEvent, 0)
+
+	dbPath := dl.getDBPath(ticker, date)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return events, nil
+	}
+
+	db, err := dl.pool.GetConnection(dbPath, true) // Read-only
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	var tableName string
+	err = db.QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name='level_cross_events'").Scan(&tableName)
+	if err == sql.ErrNoRows {
+		return events, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check level_cross_events table: %w", err)
+	}
+
+	rows, err := db.Query("SELECT id, timestamp, level, direction, spot, level_value FROM level_cross_events ORDER BY timestamp, id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query level cross events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e LevelCrossEvent
+		if err := rows.Scan(&e.ID, &e.Timestamp, &e.Level, &e.Direction, &e.Spot, &e.LevelValue); err != nil {
+			return nil, fmt.Errorf("failed to scan level cross event: %w", err)
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
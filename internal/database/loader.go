@@ -1,18 +1,18 @@
 package database
 
 import (
-	"bytes"
-	"compress/gzip"
+	"context"
 	"database/sql"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"market-terminal/internal/config"
+	"market-terminal/internal/model"
 	"market-terminal/internal/utils"
 )
 
@@ -22,6 +22,12 @@ type DataLoader struct {
 	settings   *config.Settings
 	debugPrint func(string, string)
 	queryCache *QueryCache // Query result cache (5-second TTL, 50 query limit)
+	latestRows *QueryCache // Latest-row results for past dates (completed days don't change), warmed by PreloadDates
+
+	// Encrypted databases (completed days) are decrypted into decryptedDir on first read
+	encryptionKey []byte
+	decryptedDir  string
+	decryptMu     sync.Mutex
 }
 
 // getExistingColumns returns a map of existing column names in the ticker_data table
@@ -46,10 +52,11 @@ func (dl *DataLoader) getExistingColumns(db *sql.DB) (map[string]bool, error) {
 
 // NewDataLoader creates a new data loader
 func NewDataLoader(settings *config.Settings, debugPrint func(string, string)) *DataLoader {
+	poolSettings := settings.GetConnectionPoolSettings()
 	pool := NewConnectionPool(
-		config.DBConnectionPoolMaxSize,
-		time.Duration(config.DBConnectionIdleTimeoutSec)*time.Second,
-		time.Duration(config.SQLiteConnectionCleanupIntervalSeconds)*time.Second,
+		poolSettings.MaxSize,
+		time.Duration(poolSettings.ReadIdleTimeoutSec)*time.Second,
+		time.Duration(poolSettings.CleanupIntervalSec)*time.Second,
 	)
 
 	return &DataLoader{
@@ -57,6 +64,7 @@ func NewDataLoader(settings *config.Settings, debugPrint func(string, string)) *
 		settings:   settings,
 		debugPrint: debugPrint,
 		queryCache: NewQueryCache(50, 5.0), // 50 query limit, 5-second TTL (matches Python)
+		latestRows: NewQueryCache(config.PreloadCacheMaxEntries, config.PreloadCacheTTLSec),
 	}
 }
 
@@ -64,9 +72,106 @@ func NewDataLoader(settings *config.Settings, debugPrint func(string, string)) *
 // CRITICAL: Skips profiles_blob to prevent massive memory usage (28GB+ issue)
 // Loads: timestamp, spot, zero_gamma, major_pos_vol, major_neg_vol, major_long_gamma, major_short_gamma,
 //        major_positive, major_negative, major_pos_oi, major_neg_oi
+// Full-day views are served from the 1-minute bars table (plus spot_open/spot_high/spot_low) when the
+// day has one, falling back to raw rows for days recorded before bars existed
 // Does NOT use query cache (chart data changes frequently)
 func (dl *DataLoader) LoadChartData(ticker string, date time.Time, maxRows int) (map[string][]interface{}, error) {
+	return dl.loadChartData(context.Background(), ticker, date, 0, 0, maxRows, nil)
+}
+
+// LoadChartWindow loads raw chart rows between startTime and endTime (Unix seconds)
+// Used for zoomed views, which need full resolution instead of 1-minute bars
+func (dl *DataLoader) LoadChartWindow(ticker string, date time.Time, startTime, endTime float64, maxRows int) (map[string][]interface{}, error) {
+	return dl.loadChartData(context.Background(), ticker, date, startTime, endTime, maxRows, nil)
+}
+
+// LoadChartFields loads only the requested columns (timestamp is always included)
+// Lightweight views (e.g. sparklines) ask for timestamp+spot; heavier views can add OI/flow columns
+// Pass endTime = 0 for the full day; an empty fields list loads the default chart columns
+// Returns an error naming any field that is not a column of the day's table
+func (dl *DataLoader) LoadChartFields(ticker string, date time.Time, startTime, endTime float64, maxRows int, fields []string) (map[string][]interface{}, error) {
+	return dl.loadChartData(context.Background(), ticker, date, startTime, endTime, maxRows, fields)
+}
+
+// LoadChartFieldsContext is LoadChartFields with cancellation (covers every chart load: nil fields and
+// endTime = 0 is LoadChartData). Cancelling ctx - e.g. the chart window that asked went away -
+// interrupts the query and returns ctx's error
+func (dl *DataLoader) LoadChartFieldsContext(ctx context.Context, ticker string, date time.Time, startTime, endTime float64, maxRows int, fields []string) (map[string][]interface{}, error) {
+	return dl.loadChartData(ctx, ticker, date, startTime, endTime, maxRows, fields)
+}
+
+// ErrUnknownChartField is returned (wrapped) when a requested chart field is not a loadable column
+var ErrUnknownChartField = errors.New("unknown chart field")
+
+// defaultChartFields are the columns returned when no field list is requested
+// (always accepted in a field list - missing ones come back as empty arrays)
+func defaultChartFields() []string {
+	columns := []string{"timestamp", "spot_open", "spot_high", "spot_low"}
+	return append(columns, ChartColumns()...)
+}
+
+// chartFieldColumns returns the columns to load for a field list: timestamp first, then each field once
+func chartFieldColumns(fields []string) ([]string, error) {
+	columns := []string{"timestamp"}
+	seen := map[string]bool{"timestamp": true}
+	for _, field := range fields {
+		if !isColumnName(field) {
+			return nil, fmt.Errorf("%w: invalid name %q", ErrUnknownChartField, field)
+		}
+		if field == "profiles_blob" {
+			return nil, fmt.Errorf("%w: %q is not a chart column", ErrUnknownChartField, field)
+		}
+		if !seen[field] {
+			seen[field] = true
+			columns = append(columns, field)
+		}
+	}
+	return columns, nil
+}
+
+// isColumnName reports whether s is a plain column identifier (safe to embed in a query)
+func isColumnName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// selectChartFields keeps only the requested columns of a loaded result (nil columns = keep all)
+func selectChartFields(data map[string][]interface{}, columns []string) map[string][]interface{} {
+	if columns == nil {
+		return data
+	}
+	result := make(map[string][]interface{}, len(columns))
+	for _, col := range columns {
+		if values, ok := data[col]; ok {
+			result[col] = values
+		} else {
+			result[col] = []interface{}{}
+		}
+	}
+	return result
+}
+
+// loadChartData loads chart columns for a day, or raw rows within [startTime, endTime] when endTime > 0
+// fields selects the columns to return (nil = the default chart columns)
+func (dl *DataLoader) loadChartData(ctx context.Context, ticker string, date time.Time, startTime, endTime float64, maxRows int, fields []string) (map[string][]interface{}, error) {
 	dateStr := date.Format("2006-01-02")
+	windowed := endTime > 0
+
+	// Requested field list (nil = default chart columns)
+	var fieldColumns []string
+	if len(fields) > 0 {
+		var err error
+		if fieldColumns, err = chartFieldColumns(fields); err != nil {
+			return nil, err
+		}
+	}
 	
 	dbPath := dl.getDBPath(ticker, date)
 	dl.debugPrint(fmt.Sprintf("LoadChartData: [START] Loading chart data for %s on %s (maxRows=%d)", ticker, dateStr, maxRows), "loader")
@@ -77,6 +182,9 @@ func (dl *DataLoader) LoadChartData(ticker string, date time.Time, maxRows int)
 	if os.IsNotExist(err) {
 		dl.debugPrint(fmt.Sprintf("LoadChartData: Database file does not exist for %s: %s", ticker, dbPath), "loader")
 		emptyData := make(map[string][]interface{})
+		if fieldColumns != nil {
+			return selectChartFields(emptyData, fieldColumns), nil
+		}
 		emptyData["timestamp"] = []interface{}{}
 		emptyData["spot"] = []interface{}{}
 		emptyData["zero_gamma"] = []interface{}{}
@@ -104,6 +212,20 @@ func (dl *DataLoader) LoadChartData(ticker string, date time.Time, maxRows int)
 	}
 	dl.debugPrint(fmt.Sprintf("LoadChartData: Got database connection for %s", ticker), "loader")
 
+	// Full-day views use the pre-aggregated 1-minute bars when the day has them
+	// (only when every requested field is a bar column)
+	if !windowed && barsCoverFields(fieldColumns) {
+		bars, err := dl.loadChartBars(ctx, db, maxRows)
+		if err != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		} else if err != nil {
+			dl.debugPrint(fmt.Sprintf("LoadChartData: Failed to load 1m bars for %s, using raw rows: %v", ticker, err), "error")
+		} else if bars != nil {
+			dl.debugPrint(fmt.Sprintf("LoadChartData: [END] Returning %d 1m bars for %s on %s", len(bars["timestamp"]), ticker, dateStr), "loader")
+			return selectChartFields(bars, fieldColumns), nil
+		}
+	}
+
 	// Only load columns needed for charts (explicitly exclude profiles_blob)
 	requiredColumns := []string{
 		"timestamp",
@@ -118,6 +240,9 @@ func (dl *DataLoader) LoadChartData(ticker string, date time.Time, maxRows int)
 		"major_pos_oi",     // Major positive OI
 		"major_neg_oi",     // Major negative OI
 	}
+	if fieldColumns != nil {
+		requiredColumns = fieldColumns
+	}
 	
 	// Check which columns actually exist in the table
 	existingColumns, err := dl.getExistingColumns(db)
@@ -125,6 +250,19 @@ func (dl *DataLoader) LoadChartData(ticker string, date time.Time, maxRows int)
 		dl.debugPrint(fmt.Sprintf("LoadChartData: Failed to get existing columns for %s: %v", ticker, err), "error")
 		return nil, fmt.Errorf("failed to get existing columns: %w", err)
 	}
+
+	// Requested fields must be real columns (default chart columns, the order flow and derived series may be missing on old days)
+	if fieldColumns != nil {
+		defaults := make(map[string]bool)
+		for _, col := range append(append(defaultChartFields(), orderflowChartColumns...), DerivedColumns()...) {
+			defaults[col] = true
+		}
+		for _, col := range fieldColumns {
+			if !existingColumns[col] && !defaults[col] {
+				return nil, fmt.Errorf("%w: %q is not a column for %s on %s", ErrUnknownChartField, col, ticker, dateStr)
+			}
+		}
+	}
 	
 	// Filter to only include columns that exist
 	existingRequiredColumns := make([]string, 0)
@@ -149,12 +287,21 @@ func (dl *DataLoader) LoadChartData(ticker string, date time.Time, maxRows int)
 	// Build SELECT statement with only existing required columns
 	// NOTE: Embed limit directly in query string (modernc.org/sqlite may not handle LIMIT ? correctly)
 	selectCols := strings.Join(existingRequiredColumns, ", ")
-	query := fmt.Sprintf("SELECT %s FROM ticker_data ORDER BY timestamp ASC LIMIT %d", selectCols, maxRows)
+	whereClause := ""
+	args := []interface{}{}
+	if windowed {
+		whereClause = "WHERE timestamp >= ? AND timestamp <= ? "
+		args = append(args, startTime, endTime)
+	}
+	query := fmt.Sprintf("SELECT %s FROM ticker_data %sORDER BY timestamp ASC LIMIT %d", selectCols, whereClause, maxRows)
 	dl.debugPrint(fmt.Sprintf("LoadChartData: Executing query for %s: %s", ticker, query), "loader")
 
 	// Query data with row limit (embedded in query string)
-	rows, err := db.Query(query)
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		dl.debugPrint(fmt.Sprintf("LoadChartData: Query failed for %s: %v", ticker, err), "error")
 		// Check if table exists
 		tableCheckQuery := "SELECT name FROM sqlite_master WHERE type='table' AND name='ticker_data'"
@@ -178,33 +325,31 @@ func (dl *DataLoader) LoadChartData(ticker string, date time.Time, maxRows int)
 		result[col] = make([]interface{}, 0)
 	}
 
-	// Scan rows
-	rowCount := 0
+	// Scan rows into typed column buffers (no per-value boxing until the final conversion)
+	capacity := config.ChartScanInitialCapacity
+	if maxRows > 0 && maxRows < capacity {
+		capacity = maxRows
+	}
+	scanner := newColumnScanner(existingRequiredColumns, capacity)
 	for rows.Next() {
-		// Create slice for row values (only existing columns that we're querying)
-		values := make([]interface{}, len(existingRequiredColumns))
-		valuePtrs := make([]interface{}, len(existingRequiredColumns))
-		for i := range values {
-			valuePtrs[i] = &values[i]
-		}
-
-		if err := rows.Scan(valuePtrs...); err != nil {
+		if err := scanner.scan(rows); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
-
-		// Add to result - only for columns that exist and were queried
-		for i, col := range existingRequiredColumns {
-			result[col] = append(result[col], values[i])
-		}
-		// Missing columns already have empty arrays from initialization above
-		rowCount++
 	}
+	rowCount := scanner.rowCount()
 
 	if err := rows.Err(); err != nil {
+		if ctx.Err() != nil {
+			dl.debugPrint(fmt.Sprintf("LoadChartData: Cancelled loading %s on %s after %d rows", ticker, dateStr, rowCount), "loader")
+			return nil, ctx.Err()
+		}
 		dl.debugPrint(fmt.Sprintf("LoadChartData: Error iterating rows for %s: %v", ticker, err), "error")
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
+	// Missing columns keep the empty arrays from initialization above
+	scanner.into(result)
+
 	if rowCount == 0 {
 		dl.debugPrint(fmt.Sprintf("LoadChartData: WARNING - Query returned 0 rows for %s on %s (table exists but is empty)", ticker, dateStr), "loader")
 	} else {
@@ -224,11 +369,25 @@ func (dl *DataLoader) LoadChartData(ticker string, date time.Time, maxRows int)
 // LoadTickerData loads only the columns needed for main window ticker table display
 // CRITICAL: Skips profiles_blob to prevent massive memory usage
 // Loads: timestamp, spot, zero_gamma, major_pos_vol, major_neg_vol
-// Does NOT use query cache (ticker data changes frequently)
+// Does NOT use query cache for the current day (ticker data changes frequently); past dates
+// are served from the latest-row cache when warm
 // Returns only the latest values (last row) for efficient main window display
 func (dl *DataLoader) LoadTickerData(ticker string, date time.Time) (map[string]interface{}, error) {
 	dateStr := date.Format("2006-01-02")
 	
+	// Completed days don't change - serve from the latest-row cache (warmed by PreloadDates)
+	historical := dateStr < utils.GetMarketDate().Format("2006-01-02")
+	cacheKey := GenerateCacheKey(ticker, dateStr, 0, 0)
+	if historical {
+		if cached, found := dl.latestRows.Get(cacheKey); found {
+			result := make(map[string]interface{}, len(cached))
+			for col, values := range cached {
+				result[col] = values
+			}
+			return result, nil
+		}
+	}
+	
 	dbPath := dl.getDBPath(ticker, date)
 	dl.debugPrint(fmt.Sprintf("LoadTickerData: Checking database path for %s on %s: %s", ticker, dateStr, dbPath), "loader")
 
@@ -416,6 +575,16 @@ func (dl *DataLoader) LoadTickerData(ticker string, date time.Time) (map[string]
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 	
+	if historical {
+		cached := make(map[string][]interface{}, len(result))
+		for col, values := range result {
+			if arr, ok := values.([]interface{}); ok {
+				cached[col] = arr
+			}
+		}
+		dl.latestRows.Set(cacheKey, cached)
+	}
+	
 	return result, nil
 }
 
@@ -490,6 +659,7 @@ func (dl *DataLoader) LoadFromFile(ticker string, date time.Time) (map[string][]
 	}
 
 	// Scan rows
+	decoder := newProfileDecoder(db)
 	for rows.Next() {
 		// Create slice for row values
 		values := make([]interface{}, len(columns))
@@ -506,25 +676,17 @@ func (dl *DataLoader) LoadFromFile(ticker string, date time.Time) (map[string][]
 		for i, col := range columns {
 			val := values[i]
 
-			// Handle profiles_blob decompression
+			// Handle profiles_blob decompression (legacy gzip or keyframe/delta rows)
 			if col == "profiles_blob" && val != nil {
 				if blob, ok := val.([]byte); ok && len(blob) > 0 {
-					// Decompress
-					reader, err := gzip.NewReader(bytes.NewReader(blob))
-					if err == nil {
-						decompressed, err := io.ReadAll(reader)
-						reader.Close()
-						if err == nil {
-							var profiles map[string]interface{}
-							if err := json.Unmarshal(decompressed, &profiles); err == nil {
-								// Merge profiles into result
-								for key, value := range profiles {
-									if result[key] == nil {
-										result[key] = make([]interface{}, 0)
-									}
-									result[key] = append(result[key], value)
-								}
+					timestamp, _ := values[0].(float64)
+					if profiles, err := decoder.decode(timestamp, blob); err == nil {
+						// Merge profiles into result
+						for key, value := range profiles {
+							if result[key] == nil {
+								result[key] = make([]interface{}, 0)
 							}
+							result[key] = append(result[key], value)
 						}
 					}
 				}
@@ -577,26 +739,10 @@ func (dl *DataLoader) LoadTimeRange(ticker string, date time.Time, startTime, en
 		return nil, fmt.Errorf("failed to get connection: %w", err)
 	}
 
-	// Get column names first
-	columnRows, err := db.Query("PRAGMA table_info(ticker_data)")
+	columns, err := rangeColumns(db)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get table info: %w", err)
-	}
-
-	columns := []string{"timestamp", "profiles_blob"}
-	for columnRows.Next() {
-		var cid int
-		var name, colType string
-		var notnull, dfltValue, pk interface{}
-		if err := columnRows.Scan(&cid, &name, &colType, &notnull, &dfltValue, &pk); err != nil {
-			columnRows.Close()
-			return nil, fmt.Errorf("failed to scan column info: %w", err)
-		}
-		if name != "timestamp" && name != "profiles_blob" {
-			columns = append(columns, name)
-		}
+		return nil, err
 	}
-	columnRows.Close()
 
 	// Build SELECT statement with explicit columns
 	selectCols := strings.Join(columns, ", ")
@@ -609,61 +755,45 @@ func (dl *DataLoader) LoadTimeRange(ticker string, date time.Time, startTime, en
 	}
 	defer rows.Close()
 
-	// Initialize result map
-	result := make(map[string][]interface{})
-	for _, col := range columns {
-		result[col] = make([]interface{}, 0)
+	result, err := scanRangeRows(db, rows, columns)
+	if err != nil {
+		return nil, err
 	}
 
-	// Scan rows (same as LoadFromFile)
-	for rows.Next() {
-		values := make([]interface{}, len(columns))
-		valuePtrs := make([]interface{}, len(columns))
-		for i := range values {
-			valuePtrs[i] = &values[i]
-		}
-
-		if err := rows.Scan(valuePtrs...); err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
-		}
-
-		for i, col := range columns {
-			val := values[i]
+	// Cache result
+	dl.queryCache.Set(cacheKey, result)
+	
+	return result, nil
+}
 
-			if col == "profiles_blob" && val != nil {
-				if blob, ok := val.([]byte); ok && len(blob) > 0 {
-					reader, err := gzip.NewReader(bytes.NewReader(blob))
-					if err == nil {
-						decompressed, err := io.ReadAll(reader)
-						reader.Close()
-						if err == nil {
-							var profiles map[string]interface{}
-							if err := json.Unmarshal(decompressed, &profiles); err == nil {
-								for key, value := range profiles {
-									if result[key] == nil {
-										result[key] = make([]interface{}, 0)
-									}
-									result[key] = append(result[key], value)
-								}
-							}
-						}
-					}
-				}
-				continue
-			}
+// LoadProfile returns the full profiles for the row at or just before timestamp, strike ladders parsed
+// Delta-compressed rows are reconstructed from their keyframe; returns nil if there is no data
+func (dl *DataLoader) LoadProfile(ticker string, date time.Time, timestamp float64) (*model.ProfileLadder, error) {
+	dbPath := dl.getDBPath(ticker, date)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return nil, nil
+	}
 
-			result[col] = append(result[col], val)
-		}
+	db, err := dl.pool.GetConnection(dbPath, true) // Read-only
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
+	var rowTimestamp float64
+	var blob []byte
+	err = db.QueryRow("SELECT timestamp, profiles_blob FROM ticker_data WHERE timestamp <= ? ORDER BY timestamp DESC LIMIT 1", timestamp).Scan(&rowTimestamp, &blob)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query profile: %w", err)
 	}
 
-	// Cache result
-	dl.queryCache.Set(cacheKey, result)
-	
-	return result, nil
+	profiles, err := newProfileDecoder(db).decode(rowTimestamp, blob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode profile at %.3f: %w", rowTimestamp, err)
+	}
+	return model.ProfileLadderFromProfiles(ticker, rowTimestamp, profiles), nil
 }
 
 // getDBPath returns the database file path for a ticker and date
@@ -671,16 +801,14 @@ func (dl *DataLoader) LoadTimeRange(ticker string, date time.Time, startTime, en
 // The date passed here is already in ET at midnight (from ParseDateInET or GetMarketDate)
 // We only need to handle weekend adjustments if the date is a weekend
 func (dl *DataLoader) getDBPath(ticker string, date time.Time) string {
-	dataDir := dl.settings.DataDirectory
-	if dataDir == "" {
-		dataDir = "Tickers"
-	}
+	dataDir := dl.settings.GetDataDirectory()
 
 	// The date passed to this function is already in ET at midnight
 	// (from ParseDateInET() which ensures dates are parsed as ET, not UTC)
 	// No timezone conversion needed - just handle weekend adjustment if needed
 	
-	// Only handle weekend adjustment if needed
+	// Only handle weekend adjustment if needed (on the date's own calendar day, so a midnight UTC date isn't read as the previous evening ET)
+	date = utils.MarketMidnight(date)
 	var marketDate time.Time
 	if utils.IsWeekend(date) {
 		marketDate = utils.GetLastTradingDay(date)
@@ -690,26 +818,120 @@ func (dl *DataLoader) getDBPath(ticker string, date time.Time) string {
 		marketDate = date
 	}
 
-	// Format date as MM.DD.YYYY (date is already in ET)
-	dateStr := marketDate.Format("01.02.2006")
-	// Directory format: "Tickers 01.14.2026" (not "Tickers\Tickers 01.14.2026")
-	dir := fmt.Sprintf("%s %s", dataDir, dateStr)
+	// Directory format: "Tickers 01.14.2026" (not "Tickers\Tickers 01.14.2026"), or "Tickers/2026/01/14" with data_layout: iso
+	dir := dl.settings.DayDirectory(marketDate)
 	
 	// Log directory construction
-	dl.debugPrint(fmt.Sprintf("getDBPath: Constructing path for %s on %s (market date: %s): dataDir=%s, dateStr=%s, dir=%s", 
-		ticker, date.Format("2006-01-02"), marketDate.Format("2006-01-02"), dataDir, dateStr, dir), "loader")
+	dl.debugPrint(fmt.Sprintf("getDBPath: Constructing path for %s on %s (market date: %s): dataDir=%s, dir=%s", 
+		ticker, date.Format("2006-01-02"), marketDate.Format("2006-01-02"), dataDir, dir), "loader")
 	
 	// Ensure directory exists
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		dl.debugPrint(fmt.Sprintf("getDBPath: WARNING - Failed to create directory %s: %v", dir, err), "error")
 	}
 
-	dbPath := filepath.Join(dir, fmt.Sprintf("%s.db", ticker))
+	dbPath := filepath.Join(dir, fmt.Sprintf("%s.db", dl.resolveTickerFile(dir, ticker)))
 	dl.debugPrint(fmt.Sprintf("getDBPath: Final database path for %s: %s", ticker, dbPath), "loader")
 	
+	// Completed days may only exist encrypted - read from a decrypted copy instead
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		if _, err := os.Stat(EncryptedPath(dbPath)); err == nil {
+			if decryptedPath, err := dl.decryptedCopy(dbPath); err == nil {
+				return decryptedPath
+			} else {
+				dl.debugPrint(fmt.Sprintf("getDBPath: Failed to decrypt %s: %v", EncryptedPath(dbPath), err), "error")
+			}
+		}
+	}
+	
 	return dbPath
 }
 
+// resolveTickerFile returns the name a ticker's database is stored under in a day directory
+// Days recorded before a symbol was renamed (ticker_aliases) are stored under the old name
+func (dl *DataLoader) resolveTickerFile(dir, ticker string) string {
+	names := dl.settings.TickerFileNames(ticker)
+	for _, name := range names {
+		dbPath := filepath.Join(dir, name+".db")
+		if _, err := os.Stat(dbPath); err == nil {
+			return name
+		}
+		if _, err := os.Stat(EncryptedPath(dbPath)); err == nil {
+			return name
+		}
+	}
+	return names[0]
+}
+
+// SetEncryptionKey sets the key used to read encrypted databases
+func (dl *DataLoader) SetEncryptionKey(key []byte) {
+	dl.decryptMu.Lock()
+	defer dl.decryptMu.Unlock()
+	dl.encryptionKey = key
+}
+
+// decryptedCopy returns the path of a decrypted copy of an encrypted database
+// Copies live in a private temp directory and are removed when the loader is closed
+func (dl *DataLoader) decryptedCopy(dbPath string) (string, error) {
+	dl.decryptMu.Lock()
+	defer dl.decryptMu.Unlock()
+
+	if len(dl.encryptionKey) == 0 {
+		return "", ErrEncryptionKeyRequired
+	}
+
+	if dl.decryptedDir == "" {
+		dir, err := os.MkdirTemp("", "market-terminal-decrypted-")
+		if err != nil {
+			return "", fmt.Errorf("failed to create decryption directory: %w", err)
+		}
+		dl.decryptedDir = dir
+	}
+
+	// Mirror the day directory's path under the data directory so the same ticker on different days doesn't
+	// collide (under the ISO layout the day directory alone is just the day of the month); a database outside the
+	// data directory is keyed on its whole directory path, flattened
+	dayDir, err := filepath.Rel(dl.settings.GetDataDirectory(), filepath.Dir(dbPath))
+	if err != nil || dayDir == ".." || strings.HasPrefix(dayDir, ".."+string(filepath.Separator)) {
+		dayDir = strings.NewReplacer(string(filepath.Separator), "_", ":", "_").Replace(filepath.Dir(dbPath))
+	}
+	targetDir := filepath.Join(dl.decryptedDir, dayDir)
+	if err := os.MkdirAll(targetDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create decryption directory: %w", err)
+	}
+	target := filepath.Join(targetDir, filepath.Base(dbPath))
+	if _, err := os.Stat(target); err == nil {
+		return target, nil
+	}
+
+	if err := DecryptFile(EncryptedPath(dbPath), target, dl.encryptionKey); err != nil {
+		return "", err
+	}
+	dl.debugPrint(fmt.Sprintf("decryptedCopy: Decrypted %s for reading", EncryptedPath(dbPath)), "loader")
+	return target, nil
+}
+
+// ReleaseDay closes pooled connections for a market date's databases
+// Must be called before the day's files are encrypted or moved
+func (dl *DataLoader) ReleaseDay(date time.Time) {
+	dl.pool.CloseConnectionsInDir(filepath.Dir(dl.getDBPath("_", date)))
+	dl.queryCache.Clear()
+	dl.latestRows.Clear()
+}
+
+// ClearCaches drops cached query results (after rows of an existing day were rewritten)
+func (dl *DataLoader) ClearCaches() {
+	dl.queryCache.Clear()
+	dl.latestRows.Clear()
+}
+
+// ShrinkCaches frees memory under pressure: trims the query cache and closes idle connections
+func (dl *DataLoader) ShrinkCaches(keepQueries int) {
+	evicted := dl.queryCache.Shrink(keepQueries) + dl.latestRows.Shrink(keepQueries)
+	dl.pool.cleanupIdleConnections()
+	dl.debugPrint(fmt.Sprintf("ShrinkCaches: Evicted %d cached queries, %d connections open", evicted, dl.pool.Size()), "loader")
+}
+
 // Close closes all connections
 // Ensures WAL files are checkpointed and cleaned up
 func (dl *DataLoader) Close() error {
@@ -720,6 +942,16 @@ func (dl *DataLoader) Close() error {
 		return fmt.Errorf("failed to close connection pool: %w", err)
 	}
 	
+	// Remove decrypted copies so plaintext doesn't outlive the session
+	dl.decryptMu.Lock()
+	if dl.decryptedDir != "" {
+		if err := os.RemoveAll(dl.decryptedDir); err != nil {
+			dl.debugPrint(fmt.Sprintf("DataLoader: Failed to remove decrypted copies: %v", err), "error")
+		}
+		dl.decryptedDir = ""
+	}
+	dl.decryptMu.Unlock()
+	
 	dl.debugPrint("DataLoader: Closed successfully", "loader")
 	return nil
 }
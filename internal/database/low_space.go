@@ -0,0 +1,43 @@
+package database
+
+import "fmt"
+
+// essentialColumns are the scalar columns written while disk space is critical: what charts, the order flow view
+// and the quality markers read (derived columns are computed from them on flush)
+var essentialColumns = func() map[string]bool {
+	columns := map[string]bool{QualityColumn: true}
+	for _, col := range append(ChartColumns(), OrderflowChartColumns()...) {
+		columns[col] = true
+	}
+	return columns
+}()
+
+// SetEssentialOnly switches essential-columns-only mode (set by the disk monitor below its critical threshold):
+// new rows keep only the essential scalar columns and no profiles, so the session continues on little space
+func (dw *DataWriter) SetEssentialOnly(enabled bool) {
+	dw.mu.Lock()
+	changed := dw.essentialOnly != enabled
+	dw.essentialOnly = enabled
+	dw.mu.Unlock()
+	if changed {
+		dw.debugPrint(fmt.Sprintf("DataWriter: essential-columns-only mode %v", enabled), "writer")
+	}
+}
+
+// EssentialOnly reports whether new rows are stored with essential columns only
+func (dw *DataWriter) EssentialOnly() bool {
+	dw.mu.RLock()
+	defer dw.mu.RUnlock()
+	return dw.essentialOnly
+}
+
+// essentialScalars keeps a row's essential scalar columns
+func essentialScalars(scalars map[string]interface{}) map[string]interface{} {
+	kept := make(map[string]interface{}, len(essentialColumns))
+	for field, value := range scalars {
+		if essentialColumns[field] {
+			kept[field] = value
+		}
+	}
+	return kept
+}
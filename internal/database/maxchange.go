@@ -0,0 +1,210 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// maxChangeTable holds the *_maxchange endpoints' payloads: the strike with the largest change per lookback period
+const maxChangeTable = "maxchange"
+
+// MaxChangeRow is one endpoint's max-change strike for one lookback period at one collection timestamp
+type MaxChangeRow struct {
+	Timestamp float64 `json:"timestamp"`
+	Endpoint  string  `json:"endpoint"` // e.g. "classic_zero_maxchange"
+	Period    string  `json:"period"`   // Lookback as named by the API ("current", "one", "five", "ten", "fifteen", "thirty")
+	Strike    float64 `json:"strike"`
+	Change    float64 `json:"change"`
+}
+
+// MaxChangeStrike is a strike's largest gain or loss of the day and where it was seen
+type MaxChangeStrike struct {
+	Strike    float64 `json:"strike"`
+	Change    float64 `json:"change"`
+	Timestamp float64 `json:"timestamp"`
+	Endpoint  string  `json:"endpoint"`
+	Period    string  `json:"period"`
+}
+
+// MaxChangeDay is a ticker's max-change history for a market date
+type MaxChangeDay struct {
+	Rows    []MaxChangeRow    `json:"rows"`    // Ordered by timestamp, endpoint, period
+	Gainers []MaxChangeStrike `json:"gainers"` // Strikes with the largest positive change, largest first
+	Losers  []MaxChangeStrike `json:"losers"`  // Strikes with the largest negative change, largest drop first
+}
+
+// IsMaxChangeEndpoint reports whether an endpoint returns max-change payloads
+func IsMaxChangeEndpoint(endpoint string) bool {
+	return strings.HasSuffix(endpoint, "_maxchange")
+}
+
+// GroupMaxChange moves a max-change response's period fields ([strike, change] pairs) under the endpoint's name
+// Every *_maxchange endpoint uses the same field names, so left as they are they would overwrite each other
+// when a ticker's responses are merged into one row; other responses are returned unchanged
+func GroupMaxChange(endpoint string, result map[string]interface{}) map[string]interface{} {
+	if !IsMaxChangeEndpoint(endpoint) {
+		return result
+	}
+	grouped := make(map[string]interface{}, len(result))
+	periods := make(map[string]interface{})
+	for key, value := range result {
+		if _, _, ok := maxChangePair(value); ok {
+			periods[key] = value
+		} else {
+			grouped[key] = value
+		}
+	}
+	if len(periods) > 0 {
+		grouped[endpoint] = periods
+	}
+	return grouped
+}
+
+// maxChangePair reads a [strike, change] pair
+func maxChangePair(value interface{}) (float64, float64, bool) {
+	pair, ok := value.([]interface{})
+	if !ok || len(pair) < 2 {
+		return 0, 0, false
+	}
+	strike, strikeOK := pair[0].(float64)
+	change, changeOK := pair[1].(float64)
+	return strike, change, strikeOK && changeOK
+}
+
+// takeMaxChanges returns a write's grouped max-change payloads as rows and the profiles without them
+// (the original profiles map may be shared with the collected row, so it isn't modified)
+func takeMaxChanges(write *PendingWrite) ([]MaxChangeRow, map[string]interface{}) {
+	var rows []MaxChangeRow
+	var profiles map[string]interface{}
+	for key, value := range write.Profiles {
+		periods, ok := value.(map[string]interface{})
+		if !ok || !IsMaxChangeEndpoint(key) {
+			continue
+		}
+		if profiles == nil {
+			profiles = make(map[string]interface{}, len(write.Profiles))
+			for k, v := range write.Profiles {
+				profiles[k] = v
+			}
+		}
+		delete(profiles, key)
+		for period, pair := range periods {
+			if strike, change, ok := maxChangePair(pair); ok {
+				rows = append(rows, MaxChangeRow{Timestamp: write.Timestamp, Endpoint: key, Period: period, Strike: strike, Change: change})
+			}
+		}
+	}
+	if profiles == nil {
+		return nil, write.Profiles
+	}
+	return rows, profiles
+}
+
+// ensureMaxChangeTable creates the max-change table
+func ensureMaxChangeTable(db *sql.DB) error {
+	if _, err := db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		timestamp REAL NOT NULL,
+		endpoint TEXT NOT NULL,
+		period TEXT NOT NULL,
+		strike REAL NOT NULL,
+		change REAL NOT NULL,
+		PRIMARY KEY (timestamp, endpoint, period)
+	) STRICT, WITHOUT ROWID`, maxChangeTable)); err != nil {
+		return fmt.Errorf("failed to create %s table: %w", maxChangeTable, err)
+	}
+	return nil
+}
+
+// insertMaxChanges writes max-change rows in the flush transaction (a row for the same timestamp, endpoint and
+// period replaces the old one)
+func insertMaxChanges(ctx context.Context, tx *sql.Tx, rows []MaxChangeRow) error {
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT OR REPLACE INTO %s (timestamp, endpoint, period, strike, change) VALUES (?, ?, ?, ?, ?)", maxChangeTable))
+	if err != nil {
+		return fmt.Errorf("failed to prepare %s insert: %w", maxChangeTable, err)
+	}
+	defer stmt.Close()
+	for _, row := range rows {
+		if _, err := stmt.ExecContext(ctx, row.Timestamp, row.Endpoint, row.Period, row.Strike, row.Change); err != nil {
+			return fmt.Errorf("failed to insert into %s: %w", maxChangeTable, err)
+		}
+	}
+	return nil
+}
+
+// LoadMaxChange loads a ticker's max-change rows for a market date with the day's top gaining and losing strikes
+// Returns an empty result if the day has no database or no max-change data
+func (dl *DataLoader) LoadMaxChange(ticker string, date time.Time) (*MaxChangeDay, error) {
+	day := &MaxChangeDay{Rows: make([]MaxChangeRow, 0), Gainers: make([]MaxChangeStrike, 0), Losers: make([]MaxChangeStrike, 0)}
+
+	dbPath := dl.getDBPath(ticker, date)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return day, nil
+	}
+
+	db, err := dl.pool.GetConnection(dbPath, true) // Read-only
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	var tableName string
+	err = db.QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name=?", maxChangeTable).Scan(&tableName)
+	if err == sql.ErrNoRows {
+		return day, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check %s table: %w", maxChangeTable, err)
+	}
+
+	rows, err := db.Query(fmt.Sprintf("SELECT timestamp, endpoint, period, strike, change FROM %s ORDER BY timestamp, endpoint, period", maxChangeTable))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", maxChangeTable, err)
+	}
+	defer rows.Close()
+
+	gainers := make(map[float64]MaxChangeStrike)
+	losers := make(map[float64]MaxChangeStrike)
+	for rows.Next() {
+		var row MaxChangeRow
+		if err := rows.Scan(&row.Timestamp, &row.Endpoint, &row.Period, &row.Strike, &row.Change); err != nil {
+			return nil, fmt.Errorf("failed to scan %s row: %w", maxChangeTable, err)
+		}
+		day.Rows = append(day.Rows, row)
+
+		strike := MaxChangeStrike{Strike: row.Strike, Change: row.Change, Timestamp: row.Timestamp, Endpoint: row.Endpoint, Period: row.Period}
+		if best, ok := gainers[row.Strike]; row.Change > 0 && (!ok || row.Change > best.Change) {
+			gainers[row.Strike] = strike
+		}
+		if worst, ok := losers[row.Strike]; row.Change < 0 && (!ok || row.Change < worst.Change) {
+			losers[row.Strike] = strike
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	day.Gainers = topMaxChangeStrikes(gainers, func(a, b MaxChangeStrike) bool { return a.Change > b.Change })
+	day.Losers = topMaxChangeStrikes(losers, func(a, b MaxChangeStrike) bool { return a.Change < b.Change })
+	return day, nil
+}
+
+// topMaxChangeStrikes returns the first MaxChangeTopStrikes strikes in the given order
+func topMaxChangeStrikes(strikes map[float64]MaxChangeStrike, before func(a, b MaxChangeStrike) bool) []MaxChangeStrike {
+	result := make([]MaxChangeStrike, 0, len(strikes))
+	for _, strike := range strikes {
+		result = append(result, strike)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Change != result[j].Change {
+			return before(result[i], result[j])
+		}
+		return result[i].Strike < result[j].Strike
+	})
+	return result[:min(len(result), config.MaxChangeTopStrikes)]
+}
@@ -0,0 +1,246 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// MergeResult summarizes a MergeDatabases run
+type MergeResult struct {
+	Ticker        string `json:"ticker"`
+	Date          string `json:"date"`
+	Source        string `json:"source"`
+	SourceRows    int    `json:"source_rows"`    // Rows in the other machine's file
+	Added         int    `json:"added"`          // Rows copied into the local file
+	Duplicates    int    `json:"duplicates"`     // Rows the local file already had (within MergeDedupToleranceSec)
+	MaxChangeRows int    `json:"maxchange_rows"` // Max-change rows copied with the added rows
+	DurationMs    int64  `json:"duration_ms"`
+}
+
+// MergeDatabases merges another machine's database for a ticker and market date into the local one
+// (e.g. a day collected partly at home and partly at the office). Source rows within MergeDedupToleranceSec
+// of a local row are skipped - the local row wins - and the rest go through the normal write path, so
+// profiles are re-encoded for the local file, missing columns are added and the 1m bars are updated
+// The source file is only read; encrypted files must be decrypted first
+func (dw *DataWriter) MergeDatabases(ctx context.Context, sourcePath, ticker string, date time.Time) (*MergeResult, error) {
+	start := time.Now()
+	result := &MergeResult{Ticker: ticker, Date: date.Format("2006-01-02"), Source: sourcePath}
+
+	dbPath := dw.getDBPath(ticker, date)
+	if sameFile(sourcePath, dbPath) {
+		return nil, fmt.Errorf("%s is the local database for %s on %s", sourcePath, ticker, result.Date)
+	}
+	if strings.HasSuffix(sourcePath, encryptedFileExtension) {
+		return nil, fmt.Errorf("%s is encrypted - decrypt it before merging", sourcePath)
+	}
+	if _, err := os.Stat(sourcePath); err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", sourcePath, err)
+	}
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		if _, encErr := os.Stat(EncryptedPath(dbPath)); encErr == nil {
+			return nil, fmt.Errorf("%s on %s is encrypted - decrypt the day before merging", ticker, result.Date)
+		}
+	}
+
+	source, err := sql.Open("sqlite", fmt.Sprintf("file:%s?mode=ro", sourcePath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", sourcePath, err)
+	}
+	defer source.Close()
+
+	sourceColumns, err := NewSchemaManager(source).getExistingColumns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", sourcePath, err)
+	}
+	if !sourceColumns["timestamp"] {
+		return nil, fmt.Errorf("%s has no ticker_data table", sourcePath)
+	}
+	columns := make([]string, 0, len(sourceColumns))
+	for col := range sourceColumns {
+		if col != "timestamp" && !strings.HasSuffix(col, "_blob") {
+			columns = append(columns, col)
+		}
+	}
+	sort.Strings(columns)
+
+	local, err := dw.localTimestamps(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	maxChanges, err := loadMaxChangeRows(source)
+	if err != nil {
+		return nil, err
+	}
+
+	selectColumns := append([]string{"timestamp", "profiles_blob"}, columns...)
+	if !sourceColumns["profiles_blob"] {
+		selectColumns[1] = "NULL"
+	}
+	rows, err := source.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM ticker_data ORDER BY timestamp", strings.Join(selectColumns, ", ")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", sourcePath, err)
+	}
+	defer rows.Close()
+
+	// Delta-encoded profiles point at keyframes in the source file, so each row's profiles are decoded in full
+	decoder := newProfileDecoder(source)
+	values := make([]interface{}, len(selectColumns))
+	valuePtrs := make([]interface{}, len(selectColumns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+
+	batch := make([]*PendingWrite, 0, config.ReplayBatchRows)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := dw.flushDate(ctx, ticker, date, batch)
+		dw.resetProfileWindow(ticker) // The batch may have moved the ticker's keyframe window to this day
+		if err != nil {
+			return fmt.Errorf("merge of %s on %s stopped after %d rows: %w", ticker, result.Date, result.Added, err)
+		}
+		result.Added += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		result.SourceRows++
+		timestamp, _ := values[0].(float64)
+		if hasTimestampNear(local, timestamp, config.MergeDedupToleranceSec) {
+			result.Duplicates++
+			continue
+		}
+
+		blob, _ := values[1].([]byte)
+		profiles, err := decoder.decode(timestamp, blob)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode profiles of row %.3f: %w", timestamp, err)
+		}
+		if profiles == nil {
+			profiles = make(map[string]interface{})
+		}
+		for _, row := range maxChanges[timestamp] {
+			periods, _ := profiles[row.Endpoint].(map[string]interface{})
+			if periods == nil {
+				periods = make(map[string]interface{})
+				profiles[row.Endpoint] = periods
+			}
+			periods[row.Period] = []interface{}{row.Strike, row.Change}
+			result.MaxChangeRows++
+		}
+
+		scalars := make(map[string]interface{}, len(columns))
+		for i, col := range columns {
+			if values[i+2] != nil {
+				scalars[col] = values[i+2]
+			}
+		}
+		batch = append(batch, &PendingWrite{Ticker: ticker, Timestamp: timestamp, Scalars: scalars, Profiles: profiles, Date: date})
+		if len(batch) >= config.ReplayBatchRows {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", sourcePath, err)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	result.DurationMs = time.Since(start).Milliseconds()
+	dw.debugPrint(fmt.Sprintf("MergeDatabases: Merged %s into %s on %s: %d of %d rows added, %d duplicates, %d max-change rows (%dms)",
+		sourcePath, ticker, result.Date, result.Added, result.SourceRows, result.Duplicates, result.MaxChangeRows, result.DurationMs), "writer")
+	return result, nil
+}
+
+// localTimestamps returns the sorted row timestamps of a local database (none if it doesn't exist yet)
+func (dw *DataWriter) localTimestamps(dbPath string) ([]float64, error) {
+	timestamps := make([]float64, 0)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return timestamps, nil
+	}
+	db, err := dw.pool.GetConnection(dbPath, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	existing, err := NewSchemaManager(db).getExistingColumns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing columns: %w", err)
+	}
+	if !existing["timestamp"] {
+		return timestamps, nil
+	}
+	rows, err := db.Query("SELECT timestamp FROM ticker_data ORDER BY timestamp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query local rows: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var timestamp float64
+		if err := rows.Scan(&timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan local row: %w", err)
+		}
+		timestamps = append(timestamps, timestamp)
+	}
+	return timestamps, rows.Err()
+}
+
+// loadMaxChangeRows reads a database's max-change rows by timestamp (none if it has no max-change table)
+func loadMaxChangeRows(db *sql.DB) (map[float64][]MaxChangeRow, error) {
+	result := make(map[float64][]MaxChangeRow)
+	var tableName string
+	err := db.QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name=?", maxChangeTable).Scan(&tableName)
+	if err == sql.ErrNoRows {
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check %s table: %w", maxChangeTable, err)
+	}
+
+	rows, err := db.Query(fmt.Sprintf("SELECT timestamp, endpoint, period, strike, change FROM %s", maxChangeTable))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", maxChangeTable, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var row MaxChangeRow
+		if err := rows.Scan(&row.Timestamp, &row.Endpoint, &row.Period, &row.Strike, &row.Change); err != nil {
+			return nil, fmt.Errorf("failed to scan %s row: %w", maxChangeTable, err)
+		}
+		result[row.Timestamp] = append(result[row.Timestamp], row)
+	}
+	return result, rows.Err()
+}
+
+// hasTimestampNear reports whether sorted has a value within tolerance of timestamp
+func hasTimestampNear(sorted []float64, timestamp, tolerance float64) bool {
+	i := sort.SearchFloat64s(sorted, timestamp-tolerance)
+	return i < len(sorted) && sorted[i] <= timestamp+tolerance
+}
+
+// sameFile reports whether two paths name the same file
+func sameFile(a, b string) bool {
+	aInfo, errA := os.Stat(a)
+	bInfo, errB := os.Stat(b)
+	if errA == nil && errB == nil {
+		return os.SameFile(aInfo, bInfo)
+	}
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
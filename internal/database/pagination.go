@@ -0,0 +1,148 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// RangePage is one page of a time range load (LoadTimeRangePage)
+type RangePage struct {
+	Data      map[string][]interface{} `json:"data"`       // Same shape as LoadTimeRange, for this page's rows only
+	Total     int                      `json:"total"`      // Rows in the whole range
+	Returned  int                      `json:"returned"`   // Rows in this page
+	NextAfter float64                  `json:"next_after"` // Cursor for the next page (the last timestamp returned)
+	HasMore   bool                     `json:"has_more"`
+}
+
+// LoadTimeRangePage loads up to limit rows of a time range with timestamp > after, oldest first
+// Pass 0 as after for the first page and the page's NextAfter for the following ones; the cursor stays valid
+// while rows are being added, so a growing day can be paged through live
+// limit <= 0 uses config.RangePageDefaultLimit and is capped at config.RangePageMaxLimit
+func (dl *DataLoader) LoadTimeRangePage(ticker string, date time.Time, startTime, endTime, after float64, limit int) (*RangePage, error) {
+	if limit <= 0 {
+		limit = config.RangePageDefaultLimit
+	}
+	if limit > config.RangePageMaxLimit {
+		limit = config.RangePageMaxLimit
+	}
+	page := &RangePage{Data: make(map[string][]interface{}), NextAfter: after}
+
+	dbPath := dl.getDBPath(ticker, date)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return page, nil
+	}
+
+	db, err := dl.pool.GetConnection(dbPath, true) // Read-only
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	// Total rows in the range, and how many are left from the cursor on
+	var remaining sql.NullInt64
+	if err := db.QueryRow("SELECT COUNT(*), SUM(timestamp > ?) FROM ticker_data WHERE timestamp >= ? AND timestamp <= ?",
+		after, startTime, endTime).Scan(&page.Total, &remaining); err != nil {
+		return nil, fmt.Errorf("failed to count rows: %w", err)
+	}
+
+	columns, err := rangeColumns(db)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf("SELECT %s FROM ticker_data WHERE timestamp >= ? AND timestamp <= ? AND timestamp > ? ORDER BY timestamp ASC LIMIT ?",
+		strings.Join(columns, ", "))
+	rows, err := db.Query(query, startTime, endTime, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	page.Data, err = scanRangeRows(db, rows, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamps := page.Data["timestamp"]
+	page.Returned = len(timestamps)
+	if page.Returned > 0 {
+		page.NextAfter, _ = timestamps[page.Returned-1].(float64)
+	}
+	page.HasMore = int(remaining.Int64) > page.Returned
+	dl.debugPrint(fmt.Sprintf("LoadTimeRangePage: %s on %s after %.3f: %d of %d rows (more: %v)",
+		ticker, date.Format("2006-01-02"), after, page.Returned, page.Total, page.HasMore), "loader")
+	return page, nil
+}
+
+// rangeColumns returns the ticker_data columns a range load selects (timestamp and profiles_blob first)
+func rangeColumns(db *sql.DB) ([]string, error) {
+	columnRows, err := db.Query("PRAGMA table_info(ticker_data)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table info: %w", err)
+	}
+	defer columnRows.Close()
+
+	columns := []string{"timestamp", "profiles_blob"}
+	for columnRows.Next() {
+		var cid int
+		var name, colType string
+		var notnull, dfltValue, pk interface{}
+		if err := columnRows.Scan(&cid, &name, &colType, &notnull, &dfltValue, &pk); err != nil {
+			return nil, fmt.Errorf("failed to scan column info: %w", err)
+		}
+		if name != "timestamp" && name != "profiles_blob" {
+			columns = append(columns, name)
+		}
+	}
+	return columns, columnRows.Err()
+}
+
+// scanRangeRows reads range rows into one array per column, expanding profiles_blob into its profile fields
+func scanRangeRows(db *sql.DB, rows *sql.Rows, columns []string) (map[string][]interface{}, error) {
+	result := make(map[string][]interface{})
+	for _, col := range columns {
+		result[col] = make([]interface{}, 0)
+	}
+
+	decoder := newProfileDecoder(db)
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		valuePtrs := make([]interface{}, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		for i, col := range columns {
+			val := values[i]
+
+			if col == "profiles_blob" && val != nil {
+				if blob, ok := val.([]byte); ok && len(blob) > 0 {
+					timestamp, _ := values[0].(float64)
+					if profiles, err := decoder.decode(timestamp, blob); err == nil {
+						for key, value := range profiles {
+							if result[key] == nil {
+								result[key] = make([]interface{}, 0)
+							}
+							result[key] = append(result[key], value)
+						}
+					}
+				}
+				continue
+			}
+
+			result[col] = append(result[col], val)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return result, nil
+}
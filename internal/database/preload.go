@@ -0,0 +1,91 @@
+package database
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/utils"
+)
+
+// preloadJob is one ticker/date latest-row load
+type preloadJob struct {
+	ticker string
+	date   time.Time
+}
+
+// PreloadDates warms the latest-row cache for each ticker/date pair concurrently
+// Called when the date picker opens so switching to a past date doesn't hit cold SQLite files one by one
+// The current market date is skipped (its latest row keeps changing). Returns the number of pairs warmed
+func (dl *DataLoader) PreloadDates(tickers []string, dates []time.Time) int {
+	today := utils.GetMarketDate().Format("2006-01-02")
+
+	jobs := make([]preloadJob, 0, len(tickers)*len(dates))
+	for _, date := range dates {
+		if date.Format("2006-01-02") >= today {
+			continue
+		}
+		for _, ticker := range tickers {
+			jobs = append(jobs, preloadJob{ticker: ticker, date: date})
+		}
+	}
+
+	start := time.Now()
+	warmed := dl.runPreload("PreloadDates", jobs, nil)
+	dl.debugPrint(fmt.Sprintf("PreloadDates: Warmed %d ticker/date pairs (%d tickers, %d dates) in %v",
+		warmed, len(tickers), len(dates), time.Since(start)), "loader")
+	return warmed
+}
+
+// WarmDate opens each ticker's database for one date and reads its latest row, concurrently
+// Used at startup for the current market date so the first main window render finds open connections and
+// introspected columns instead of cold-opening every file in turn; loaded receives each ticker's latest row
+// Returns the number of tickers whose database was read
+func (dl *DataLoader) WarmDate(tickers []string, date time.Time, loaded func(ticker string, data map[string]interface{})) int {
+	jobs := make([]preloadJob, 0, len(tickers))
+	for _, ticker := range tickers {
+		jobs = append(jobs, preloadJob{ticker: ticker, date: date})
+	}
+
+	start := time.Now()
+	warmed := dl.runPreload("WarmDate", jobs, loaded)
+	dl.debugPrint(fmt.Sprintf("WarmDate: Warmed %d of %d tickers for %s in %v",
+		warmed, len(tickers), date.Format("2006-01-02"), time.Since(start)), "loader")
+	return warmed
+}
+
+// runPreload loads the latest row of each job on config.PreloadWorkers workers and returns the number loaded
+func (dl *DataLoader) runPreload(caller string, jobs []preloadJob, loaded func(ticker string, data map[string]interface{})) int {
+	queue := make(chan preloadJob)
+	var warmed int
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < config.PreloadWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range queue {
+				data, err := dl.LoadTickerData(j.ticker, j.date)
+				if err != nil {
+					dl.debugPrint(fmt.Sprintf("%s: Failed to preload %s on %s: %v", caller, j.ticker, j.date.Format("2006-01-02"), err), "loader")
+					continue
+				}
+				if loaded != nil {
+					loaded(j.ticker, data)
+				}
+				mu.Lock()
+				warmed++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, j := range jobs {
+		queue <- j
+	}
+	close(queue)
+	wg.Wait()
+	return warmed
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// expectedChartColumns are created with every ticker_data table, even before a row has them, so chart
+// queries never fail with "no such column" while not every field has been written yet
+func expectedChartColumns() []string {
+	columns := []string{
+		"spot",
+		"zero_gamma",
+		"major_pos_vol",
+		"major_neg_vol",
+		"major_long_gamma",
+		"major_short_gamma",
+		"major_positive",
+		"major_negative",
+		"major_pos_oi",
+		"major_neg_oi",
+	}
+	columns = append(columns, orderflowChartColumns...)
+	return append(columns, DerivedColumns()...)
+}
+
+// PrepareDate opens each ticker's database for a market date and creates its table with the chart columns
+// (the pre-open phase), so the session's first flushes don't pay for file, WAL and schema creation
+// Returns the number of tickers prepared; the error names the first ticker that failed
+func (dw *DataWriter) PrepareDate(tickers []string, date time.Time) (int, error) {
+	start := time.Now()
+	columns := expectedChartColumns()
+	prepared := 0
+	var firstErr error
+	for _, ticker := range tickers {
+		db, err := dw.pool.GetConnection(dw.getDBPath(ticker, date), false)
+		if err == nil {
+			err = NewSchemaManager(db).EnsureTable(columns)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to prepare %s for %s: %w", ticker, date.Format("2006-01-02"), err)
+			}
+			continue
+		}
+		prepared++
+	}
+	dw.debugPrint(fmt.Sprintf("PrepareDate: Prepared %d of %d ticker databases for %s in %v",
+		prepared, len(tickers), date.Format("2006-01-02"), time.Since(start)), "writer")
+	return prepared, firstErr
+}
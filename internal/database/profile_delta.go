@@ -0,0 +1,373 @@
+package database
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"market-terminal/internal/config"
+)
+
+// profiles_blob encodings
+// Legacy rows are gzip(JSON) with no prefix (gzip always starts with 0x1f 0x8b).
+// Delta-compressed rows start with a kind byte followed by gzip(JSON):
+//
+//	keyframe: the full profiles map (first row of a window)
+//	delta:    changes relative to the window's keyframe, so any row needs at most one extra read
+const (
+	profileBlobKeyframe byte = 0x01
+	profileBlobDelta    byte = 0x02
+)
+
+// profileDelta is the JSON body of a delta row
+type profileDelta struct {
+	Keyframe float64                 `json:"k"`           // Timestamp of the keyframe row this delta applies to
+	Changed  map[string]interface{}  `json:"c,omitempty"` // Keys replaced with a full value (new keys, shape changes)
+	Patched  map[string]profilePatch `json:"p,omitempty"` // Same-length arrays with only some elements changed
+	Removed  []string                `json:"r,omitempty"` // Keys present in the keyframe but not in this row
+}
+
+// profilePatch replaces array elements at the given indexes
+type profilePatch struct {
+	Indexes []int         `json:"i"`
+	Values  []interface{} `json:"v"`
+}
+
+// profileKeyframe is the writer's current window for one ticker
+type profileKeyframe struct {
+	dbPath    string
+	timestamp float64
+	profiles  map[string]interface{}
+	rows      int // Rows written in this window (including the keyframe)
+}
+
+// gzipJSON marshals v to JSON and gzips it
+func gzipJSON(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal profiles: %w", err)
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		gz.Close()
+		return nil, fmt.Errorf("failed to compress profiles: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close gzip writer: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// gunzipJSON decompresses gzip(JSON) into v
+func gunzipJSON(data []byte, v interface{}) error {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(decompressed, v)
+}
+
+// diffProfiles builds a delta from base to profiles
+func diffProfiles(base, profiles map[string]interface{}) profileDelta {
+	delta := profileDelta{
+		Changed: make(map[string]interface{}),
+		Patched: make(map[string]profilePatch),
+	}
+	for key, value := range profiles {
+		baseValue, exists := base[key]
+		if !exists {
+			delta.Changed[key] = value
+			continue
+		}
+		if reflect.DeepEqual(baseValue, value) {
+			continue
+		}
+
+		// Patch element-wise when the array shape is unchanged and most elements are the same
+		baseArr, baseOK := baseValue.([]interface{})
+		arr, arrOK := value.([]interface{})
+		if baseOK && arrOK && len(baseArr) == len(arr) {
+			patch := profilePatch{Indexes: make([]int, 0), Values: make([]interface{}, 0)}
+			for i := range arr {
+				if !reflect.DeepEqual(baseArr[i], arr[i]) {
+					patch.Indexes = append(patch.Indexes, i)
+					patch.Values = append(patch.Values, arr[i])
+				}
+			}
+			if len(patch.Indexes) <= len(arr)/2 {
+				delta.Patched[key] = patch
+				continue
+			}
+		}
+		delta.Changed[key] = value
+	}
+	for key := range base {
+		if _, exists := profiles[key]; !exists {
+			delta.Removed = append(delta.Removed, key)
+		}
+	}
+	return delta
+}
+
+// applyProfileDelta reconstructs a row's profiles from its keyframe (base is not modified)
+func applyProfileDelta(base map[string]interface{}, delta profileDelta) map[string]interface{} {
+	profiles := make(map[string]interface{}, len(base)+len(delta.Changed))
+	for key, value := range base {
+		profiles[key] = value
+	}
+	for _, key := range delta.Removed {
+		delete(profiles, key)
+	}
+	for key, value := range delta.Changed {
+		profiles[key] = value
+	}
+	for key, patch := range delta.Patched {
+		baseArr, ok := base[key].([]interface{})
+		if !ok {
+			continue
+		}
+		arr := make([]interface{}, len(baseArr))
+		copy(arr, baseArr)
+		for i, index := range patch.Indexes {
+			if index >= 0 && index < len(arr) && i < len(patch.Values) {
+				arr[index] = patch.Values[i]
+			}
+		}
+		profiles[key] = arr
+	}
+	return profiles
+}
+
+// profileEncoder encodes the profiles_blob of one flush's rows
+// With delta compression enabled, the first row of each window is stored in full and later rows as diffs against
+// it; otherwise rows are stored as gzip(JSON) like before. The encoder works on a copy of the ticker's window that
+// is stored back on the writer only once the flush commits (commitProfileWindow), so a rolled back batch can't
+// leave the writer pointing deltas at a keyframe that was never written
+type profileEncoder struct {
+	delta  bool
+	dbPath string
+	window *profileKeyframe // nil = the next row is a keyframe
+}
+
+// newProfileEncoder starts encoding a flush to dbPath from the ticker's committed window
+func (dw *DataWriter) newProfileEncoder(ticker, dbPath string) *profileEncoder {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	enc := &profileEncoder{delta: dw.profileDelta, dbPath: dbPath}
+	if window := dw.profileKeyframes[ticker]; enc.delta && window != nil && window.dbPath == dbPath {
+		copied := *window
+		enc.window = &copied
+	}
+	return enc
+}
+
+// encode builds a row's profiles_blob; keyframe forces a keyframe (the row replaces one) and isKeyframe reports
+// what was written
+func (enc *profileEncoder) encode(write *PendingWrite, keyframe bool) (blob []byte, isKeyframe bool, err error) {
+	if !enc.delta {
+		blob, err = gzipJSON(write.Profiles)
+		return blob, false, err
+	}
+
+	window := enc.window
+	if keyframe || window == nil || window.rows >= config.ProfileDeltaKeyframeInterval {
+		blob, err := gzipJSON(write.Profiles)
+		if err != nil {
+			return nil, false, err
+		}
+		enc.window = &profileKeyframe{
+			dbPath:    enc.dbPath,
+			timestamp: write.Timestamp,
+			profiles:  write.Profiles,
+			rows:      1,
+		}
+		return append([]byte{profileBlobKeyframe}, blob...), true, nil
+	}
+
+	delta := diffProfiles(window.profiles, write.Profiles)
+	delta.Keyframe = window.timestamp
+	blob, err = gzipJSON(delta)
+	if err != nil {
+		return nil, false, err
+	}
+	window.rows++
+	return append([]byte{profileBlobDelta}, blob...), false, nil
+}
+
+// rejected drops a row the database refused: a refused keyframe isn't on disk, so the next row starts a new window
+func (enc *profileEncoder) rejected(wasKeyframe bool) {
+	if wasKeyframe {
+		enc.window = nil
+	} else if enc.window != nil {
+		enc.window.rows--
+	}
+}
+
+// commitProfileWindow stores a committed flush's window as the ticker's current one
+func (dw *DataWriter) commitProfileWindow(ticker string, enc *profileEncoder) {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	if !enc.delta || !dw.profileDelta {
+		return
+	}
+	if enc.window == nil {
+		delete(dw.profileKeyframes, ticker)
+		return
+	}
+	dw.profileKeyframes[ticker] = enc.window
+}
+
+// existingKeyframe returns the profiles of the keyframe stored at a timestamp, and its blob (nil, nil if the row
+// doesn't exist or isn't a keyframe)
+func existingKeyframe(ctx context.Context, tx *sql.Tx, timestamp float64) (map[string]interface{}, []byte, error) {
+	var blob []byte
+	err := tx.QueryRowContext(ctx, "SELECT profiles_blob FROM ticker_data WHERE timestamp = ?", timestamp).Scan(&blob)
+	if err == sql.ErrNoRows || (err == nil && (len(blob) == 0 || blob[0] != profileBlobKeyframe)) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read row %.3f: %w", timestamp, err)
+	}
+	var profiles map[string]interface{}
+	if err := gunzipJSON(blob[1:], &profiles); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode keyframe %.3f: %w", timestamp, err)
+	}
+	return profiles, blob, nil
+}
+
+// rebaseProfileDeltas re-encodes the deltas based on the keyframe at timestamp after that keyframe was replaced:
+// each is decoded against the old profiles and diffed against the new ones, so every row keeps its content
+// Returns the number of rows rewritten
+func rebaseProfileDeltas(ctx context.Context, tx *sql.Tx, timestamp float64, oldProfiles, newProfiles map[string]interface{}) (int, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT timestamp, profiles_blob FROM ticker_data WHERE substr(profiles_blob, 1, 1) = X'02'")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read profile deltas: %w", err)
+	}
+	rebased := make(map[float64][]byte)
+	for rows.Next() {
+		var rowTimestamp float64
+		var blob []byte
+		if err := rows.Scan(&rowTimestamp, &blob); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		var delta profileDelta
+		if err := gunzipJSON(blob[1:], &delta); err != nil || delta.Keyframe != timestamp {
+			continue
+		}
+		rebasedDelta := diffProfiles(newProfiles, applyProfileDelta(oldProfiles, delta))
+		rebasedDelta.Keyframe = timestamp
+		encoded, err := gzipJSON(rebasedDelta)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		rebased[rowTimestamp] = append([]byte{profileBlobDelta}, encoded...)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	for rowTimestamp, blob := range rebased {
+		if _, err := tx.ExecContext(ctx, "UPDATE ticker_data SET profiles_blob = ? WHERE timestamp = ?", blob, rowTimestamp); err != nil {
+			return 0, fmt.Errorf("failed to rebase profile delta %.3f: %w", rowTimestamp, err)
+		}
+	}
+	return len(rebased), nil
+}
+
+// SetProfileDeltaCompression enables or disables delta compression for new rows
+// Existing rows keep their encoding (the loader reads both)
+func (dw *DataWriter) SetProfileDeltaCompression(enabled bool) {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	dw.profileDelta = enabled
+	if !enabled {
+		dw.profileKeyframes = make(map[string]*profileKeyframe)
+	}
+}
+
+// resetProfileWindow makes the ticker's next row a keyframe
+func (dw *DataWriter) resetProfileWindow(ticker string) {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	delete(dw.profileKeyframes, ticker)
+}
+
+// profileDecoder decodes profiles_blob values, caching keyframes while scanning a table
+type profileDecoder struct {
+	db        *sql.DB
+	keyframes map[float64]map[string]interface{}
+}
+
+func newProfileDecoder(db *sql.DB) *profileDecoder {
+	return &profileDecoder{db: db, keyframes: make(map[float64]map[string]interface{})}
+}
+
+// decode returns the full profiles for a row's blob (timestamp is the row's timestamp)
+func (pd *profileDecoder) decode(timestamp float64, blob []byte) (map[string]interface{}, error) {
+	if len(blob) == 0 {
+		return nil, nil
+	}
+
+	var profiles map[string]interface{}
+	switch blob[0] {
+	case profileBlobKeyframe:
+		if err := gunzipJSON(blob[1:], &profiles); err != nil {
+			return nil, err
+		}
+		pd.keyframes[timestamp] = profiles
+		return profiles, nil
+	case profileBlobDelta:
+		var delta profileDelta
+		if err := gunzipJSON(blob[1:], &delta); err != nil {
+			return nil, err
+		}
+		base, err := pd.keyframe(delta.Keyframe)
+		if err != nil {
+			return nil, err
+		}
+		return applyProfileDelta(base, delta), nil
+	default:
+		// Legacy gzip(JSON) row
+		if err := gunzipJSON(blob, &profiles); err != nil {
+			return nil, err
+		}
+		return profiles, nil
+	}
+}
+
+// keyframe returns a keyframe's profiles, reading it from the database if it wasn't scanned yet
+// (e.g. a time range query that starts in the middle of a window)
+func (pd *profileDecoder) keyframe(timestamp float64) (map[string]interface{}, error) {
+	if profiles, ok := pd.keyframes[timestamp]; ok {
+		return profiles, nil
+	}
+
+	var blob []byte
+	if err := pd.db.QueryRow("SELECT profiles_blob FROM ticker_data WHERE timestamp = ? AND substr(profiles_blob, 1, 1) = X'01' LIMIT 1", timestamp).Scan(&blob); err != nil {
+		return nil, fmt.Errorf("keyframe at %.3f not found: %w", timestamp, err)
+	}
+	if len(blob) == 0 || blob[0] != profileBlobKeyframe {
+		return nil, fmt.Errorf("row at %.3f is not a profile keyframe", timestamp)
+	}
+	var profiles map[string]interface{}
+	if err := gunzipJSON(blob[1:], &profiles); err != nil {
+		return nil, err
+	}
+	pd.keyframes[timestamp] = profiles
+	return profiles, nil
+}
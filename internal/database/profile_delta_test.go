@@ -0,0 +1,165 @@
+package database
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/utils"
+)
+
+// newDeltaTestWriter creates a writer with profile delta compression in a temporary data directory
+func newDeltaTestWriter(t *testing.T) *DataWriter {
+	t.Helper()
+	settings := config.GetDefaultSettings()
+	settings.DataDirectory = t.TempDir()
+	settings.ProfileDeltaCompression = true
+	dw := NewDataWriter(settings, func(string, string) {})
+	t.Cleanup(func() { dw.pool.Close() })
+	return dw
+}
+
+// deltaTestDay is the market date the tests write, and deltaTestTime a timestamp minutes after its open
+var deltaTestDay = time.Date(2026, 3, 10, 0, 0, 0, 0, utils.MARKET_TIMEZONE)
+
+func deltaTestTime(minutes int) float64 {
+	return float64(deltaTestDay.Add(9*time.Hour + 31*time.Minute + time.Duration(minutes)*time.Minute).Unix())
+}
+
+// deltaTestWrite builds a row whose gamma profile differs at one strike per version
+func deltaTestWrite(minutes int, version float64) *PendingWrite {
+	return &PendingWrite{
+		Ticker:    "SPX",
+		Timestamp: deltaTestTime(minutes),
+		Scalars:   map[string]interface{}{"spot": 5000.0 + float64(minutes)},
+		Profiles: map[string]interface{}{
+			"strikes": []interface{}{4990.0, 5000.0, 5010.0, 5020.0},
+			"gamma":   []interface{}{1.0, 2.0, version, 4.0},
+		},
+		Date: deltaTestDay,
+	}
+}
+
+// readDeltaTestRows decodes every stored row's profiles by timestamp, failing on a row that doesn't decode
+func readDeltaTestRows(t *testing.T, dw *DataWriter) (map[float64]map[string]interface{}, map[float64]byte) {
+	t.Helper()
+	db, err := dw.pool.GetConnection(dw.getDBPath("SPX", deltaTestDay), true)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	rows, err := db.Query("SELECT timestamp, profiles_blob FROM ticker_data ORDER BY timestamp")
+	if err != nil {
+		t.Fatalf("query rows: %v", err)
+	}
+	defer rows.Close()
+
+	decoder := newProfileDecoder(db)
+	profiles := make(map[float64]map[string]interface{})
+	kinds := make(map[float64]byte)
+	for rows.Next() {
+		var timestamp float64
+		var blob []byte
+		if err := rows.Scan(&timestamp, &blob); err != nil {
+			t.Fatalf("scan row: %v", err)
+		}
+		decoded, err := decoder.decode(timestamp, blob)
+		if err != nil {
+			t.Fatalf("row %.0f does not decode: %v", timestamp, err)
+		}
+		profiles[timestamp] = decoded
+		kinds[timestamp] = blob[0]
+	}
+	return profiles, kinds
+}
+
+// checkDeltaTestRows checks the stored rows are exactly the expected rows with their profiles
+func checkDeltaTestRows(t *testing.T, dw *DataWriter, expected []*PendingWrite) map[float64]byte {
+	t.Helper()
+	profiles, kinds := readDeltaTestRows(t, dw)
+	if len(profiles) != len(expected) {
+		t.Fatalf("stored %d rows, want %d", len(profiles), len(expected))
+	}
+	for _, write := range expected {
+		if !reflect.DeepEqual(profiles[write.Timestamp], write.Profiles) {
+			t.Errorf("row %.0f decoded to %v, want %v", write.Timestamp, profiles[write.Timestamp], write.Profiles)
+		}
+	}
+	return kinds
+}
+
+func TestProfileDeltaFailedFlushRoundTrip(t *testing.T) {
+	dw := newDeltaTestWriter(t)
+	ctx := context.Background()
+
+	// The second row can't be bound, so the whole batch (including its keyframe) rolls back
+	failing := []*PendingWrite{deltaTestWrite(0, 3), deltaTestWrite(1, 5)}
+	failing[1].Scalars["bogus"] = struct{}{}
+	if err := dw.flushDate(ctx, "SPX", deltaTestDay, failing); err == nil {
+		t.Fatal("flush with an unbindable value succeeded, want an error")
+	}
+
+	retried := []*PendingWrite{deltaTestWrite(2, 6), deltaTestWrite(3, 7), deltaTestWrite(4, 8)}
+	if err := dw.flushDate(ctx, "SPX", deltaTestDay, retried); err != nil {
+		t.Fatalf("flush after the failed batch: %v", err)
+	}
+
+	kinds := checkDeltaTestRows(t, dw, retried)
+	if kinds[retried[0].Timestamp] != profileBlobKeyframe {
+		t.Errorf("first row after the failed batch is kind %#x, want a keyframe", kinds[retried[0].Timestamp])
+	}
+	if kinds[retried[1].Timestamp] != profileBlobDelta {
+		t.Errorf("second row after the failed batch is kind %#x, want a delta", kinds[retried[1].Timestamp])
+	}
+}
+
+func TestProfileDeltaRejectedKeyframe(t *testing.T) {
+	dw := newDeltaTestWriter(t)
+	ctx := context.Background()
+
+	// A non-positive timestamp fails the row's CHECK: the row is dropped and the batch commits without it
+	writes := []*PendingWrite{deltaTestWrite(0, 3), deltaTestWrite(1, 5), deltaTestWrite(2, 6)}
+	writes[0].Timestamp = -1
+	if err := dw.flushDate(ctx, "SPX", deltaTestDay, writes); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if err := dw.flushDate(ctx, "SPX", deltaTestDay, []*PendingWrite{deltaTestWrite(3, 7)}); err != nil {
+		t.Fatalf("second flush: %v", err)
+	}
+
+	kinds := checkDeltaTestRows(t, dw, []*PendingWrite{deltaTestWrite(1, 5), deltaTestWrite(2, 6), deltaTestWrite(3, 7)})
+	if kinds[deltaTestTime(1)] != profileBlobKeyframe {
+		t.Errorf("row after the rejected keyframe is kind %#x, want a keyframe", kinds[deltaTestTime(1)])
+	}
+}
+
+func TestProfileDeltaReplacedKeyframe(t *testing.T) {
+	dw := newDeltaTestWriter(t)
+	ctx := context.Background()
+
+	original := []*PendingWrite{deltaTestWrite(0, 3), deltaTestWrite(1, 5), deltaTestWrite(2, 6)}
+	if err := dw.flushDate(ctx, "SPX", deltaTestDay, original); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	// Rewriting the keyframe's timestamp mid-window must not turn it into a delta or strand its deltas
+	replacement := deltaTestWrite(0, 9)
+	replacement.Profiles["vanna"] = []interface{}{0.5, 0.5, 0.5, 0.5}
+	if err := dw.flushDate(ctx, "SPX", deltaTestDay, []*PendingWrite{replacement}); err != nil {
+		t.Fatalf("replacing flush: %v", err)
+	}
+
+	kinds := checkDeltaTestRows(t, dw, []*PendingWrite{replacement, original[1], original[2]})
+	if kinds[replacement.Timestamp] != profileBlobKeyframe {
+		t.Errorf("replaced keyframe is kind %#x, want a keyframe", kinds[replacement.Timestamp])
+	}
+
+	// A row without profiles keeps the keyframe's blob
+	bare := deltaTestWrite(0, 0)
+	bare.Profiles = nil
+	if err := dw.flushDate(ctx, "SPX", deltaTestDay, []*PendingWrite{bare}); err != nil {
+		t.Fatalf("flush without profiles: %v", err)
+	}
+	checkDeltaTestRows(t, dw, []*PendingWrite{replacement, original[1], original[2]})
+}
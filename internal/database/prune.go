@@ -0,0 +1,166 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PruneResult summarizes a column pruning pass over closed databases
+type PruneResult struct {
+	DryRun         bool           `json:"dry_run"`
+	Keep           []string       `json:"keep"`            // Scalar columns kept (besides timestamp and profiles_blob)
+	Databases      int            `json:"databases"`       // Databases rewritten (or that would be, in a dry run)
+	Skipped        int            `json:"skipped"`         // Databases that only had kept columns
+	DroppedColumns map[string]int `json:"dropped_columns"` // Column -> number of databases it was dropped from
+	BytesBefore    int64          `json:"bytes_before"`
+	BytesAfter     int64          `json:"bytes_after"`
+	ReclaimedBytes int64          `json:"reclaimed_bytes"`
+	DurationMs     int64          `json:"duration_ms"`
+	Errors         []string       `json:"errors"`
+}
+
+// ChartColumns returns the scalar columns chart views read (the chart-only collection set)
+func ChartColumns() []string {
+	return append([]string{"spot"}, chartBarLevelColumns...)
+}
+
+// orderflowChartColumns are the orderflow endpoint's series shown by the chart's order flow view
+// They aren't price levels, so they stay out of ChartColumns (bars, outlier filter, chart images)
+var orderflowChartColumns = []string{"delta", "volume"}
+
+// OrderflowChartColumns returns the scalar columns the order flow chart view reads
+func OrderflowChartColumns() []string {
+	return append([]string(nil), orderflowChartColumns...)
+}
+
+// PruneColumns rewrites closed (previous market date) databases keeping only the given scalar columns
+// timestamp and profiles_blob are always kept; an empty keep list keeps ChartColumns(), OrderflowChartColumns() and
+// DerivedColumns()
+// Used to reclaim disk after switching from collect_all_endpoints to chart-only collection
+// dryRun reports what would be dropped without touching any file; encrypted days are skipped
+func (dw *DataWriter) PruneColumns(keep []string, dryRun bool) (*PruneResult, error) {
+	if len(keep) == 0 {
+		keep = append(append(ChartColumns(), orderflowChartColumns...), DerivedColumns()...)
+	}
+	keepSet := map[string]bool{"timestamp": true, "profiles_blob": true}
+	kept := make([]string, 0, len(keep))
+	for _, column := range keep {
+		sanitized := sanitizeFieldName(strings.TrimSpace(column))
+		if sanitized == "" || keepSet[sanitized] {
+			continue
+		}
+		keepSet[sanitized] = true
+		kept = append(kept, sanitized)
+	}
+
+	// Shares the compaction guard - both rewrite closed files
+	dw.mu.Lock()
+	if dw.compacting {
+		dw.mu.Unlock()
+		return nil, fmt.Errorf("database compaction is already running")
+	}
+	dw.compacting = true
+	dw.mu.Unlock()
+	defer func() {
+		dw.mu.Lock()
+		dw.compacting = false
+		dw.mu.Unlock()
+	}()
+
+	start := time.Now()
+	result := &PruneResult{
+		DryRun:         dryRun,
+		Keep:           kept,
+		DroppedColumns: make(map[string]int),
+		Errors:         make([]string, 0),
+	}
+	for _, dir := range dw.closedDayDirs(0) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("failed to read %s: %v", dir, err))
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".db") {
+				continue
+			}
+			dbPath := filepath.Join(dir, entry.Name())
+			before := dbFileSize(dbPath)
+			dropped, err := dw.pruneDatabase(dbPath, keepSet, dryRun)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", dbPath, err))
+				dw.debugPrint(fmt.Sprintf("PruneColumns: Failed for %s: %v", dbPath, err), "error")
+				continue
+			}
+			if len(dropped) == 0 {
+				result.Skipped++
+				continue
+			}
+			result.Databases++
+			for _, column := range dropped {
+				result.DroppedColumns[column]++
+			}
+			result.BytesBefore += before
+			if dryRun {
+				result.BytesAfter += before
+			} else {
+				result.BytesAfter += dbFileSize(dbPath)
+			}
+		}
+	}
+
+	result.ReclaimedBytes = result.BytesBefore - result.BytesAfter
+	result.DurationMs = time.Since(start).Milliseconds()
+	dw.debugPrint(fmt.Sprintf("PruneColumns: %d database(s) pruned (dry run: %v), %d skipped, %d column(s) dropped, %.1f MB reclaimed in %dms",
+		result.Databases, dryRun, result.Skipped, len(result.DroppedColumns), float64(result.ReclaimedBytes)/1024/1024, result.DurationMs), "writer")
+	return result, nil
+}
+
+// pruneDatabase drops ticker_data columns not in keepSet, then vacuums the file
+// Returns the dropped (or, in a dry run, droppable) columns
+func (dw *DataWriter) pruneDatabase(dbPath string, keepSet map[string]bool, dryRun bool) ([]string, error) {
+	db, err := dw.pool.GetConnection(dbPath, false)
+	if err != nil {
+		return nil, err
+	}
+	defer dw.pool.CloseConnection(dbPath)
+
+	existing, err := NewSchemaManager(db).getExistingColumns()
+	if err != nil {
+		return nil, err
+	}
+	dropped := make([]string, 0)
+	for column := range existing {
+		if !keepSet[column] {
+			dropped = append(dropped, column)
+		}
+	}
+	sort.Strings(dropped)
+	if len(dropped) == 0 || dryRun {
+		return dropped, nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	for _, column := range dropped {
+		if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE ticker_data DROP COLUMN %s", column)); err != nil {
+			return nil, fmt.Errorf("failed to drop column %s: %w", column, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// Dropping columns leaves the freed pages in the file until it is vacuumed
+	if _, err := dw.compactDatabase(dbPath); err != nil {
+		return dropped, fmt.Errorf("columns dropped but vacuum failed: %w", err)
+	}
+	return dropped, nil
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Row quality flags, OR'd into a row's quality column (NULL = nothing unusual about the row)
+const (
+	QualitySpotBackfilled   = 1 << iota // spot was missing from this fetch and taken from an earlier response
+	QualityFieldsBackfilled             // Other fields were taken from an earlier response (their endpoint failed)
+	QualityDedupMerged                  // Rows within the dedup tolerance were merged into this one (the last was kept)
+	QualityDelayedFetch                 // An endpoint took longer than QualityDelayedFetchSec to answer
+	QualitySpotDivergent                // spot diverged from the secondary quote source (spot_check) beyond its threshold
+)
+
+// QualityColumn is the ticker_data column holding the quality flags
+const QualityColumn = "quality"
+
+// qualityFlagNames describes each flag for the chart markers
+var qualityFlagNames = []struct {
+	flag int
+	name string
+}{
+	{QualitySpotBackfilled, "spot missing, back-filled"},
+	{QualityFieldsBackfilled, "fields back-filled"},
+	{QualityDedupMerged, "dedup merged"},
+	{QualityDelayedFetch, "delayed fetch"},
+	{QualitySpotDivergent, "spot diverges from secondary source"},
+}
+
+// QualityFlagNames returns the descriptions of the flags set in flags
+func QualityFlagNames(flags int) []string {
+	names := make([]string, 0, len(qualityFlagNames))
+	for _, f := range qualityFlagNames {
+		if flags&f.flag != 0 {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// AddQualityFlags ORs flags into a row's quality field
+func AddQualityFlags(data map[string]interface{}, flags int) {
+	if flags == 0 {
+		return
+	}
+	switch existing := data[QualityColumn].(type) {
+	case int:
+		flags |= existing
+	case float64:
+		flags |= int(existing)
+	}
+	data[QualityColumn] = flags
+}
+
+// QualityMarker is a row with quality flags, drawn as a marker on the chart
+type QualityMarker struct {
+	Timestamp float64  `json:"timestamp"`
+	Flags     int      `json:"flags"`
+	Reasons   []string `json:"reasons"`
+}
+
+// LoadQualityMarkers returns a ticker's flagged rows for a market date, oldest first
+// Returns an empty list if the day has no database or was recorded before quality flags existed
+func (dl *DataLoader) LoadQualityMarkers(ticker string, date time.Time) ([]QualityMarker, error) {
+	markers := make([]QualityMarker, 0)
+
+	dbPath := dl.getDBPath(ticker, date)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return markers, nil
+	}
+
+	db, err := dl.pool.GetConnection(dbPath, true) // Read-only
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	existing, err := NewSchemaManager(db).getExistingColumns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing columns: %w", err)
+	}
+	if !existing[QualityColumn] {
+		return markers, nil
+	}
+
+	rows, err := db.Query(fmt.Sprintf("SELECT timestamp, %[1]s FROM ticker_data WHERE %[1]s IS NOT NULL AND %[1]s != 0 ORDER BY timestamp", QualityColumn))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query quality flags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var marker QualityMarker
+		var flags sql.NullInt64
+		if err := rows.Scan(&marker.Timestamp, &flags); err != nil {
+			return nil, fmt.Errorf("failed to scan quality flags: %w", err)
+		}
+		marker.Flags = int(flags.Int64)
+		marker.Reasons = QualityFlagNames(marker.Flags)
+		markers = append(markers, marker)
+	}
+	return markers, rows.Err()
+}
@@ -123,6 +123,23 @@ func (qc *QueryCache) Clear() {
 	qc.accessOrder = make([]string, 0)
 }
 
+// Shrink evicts least recently used entries until at most maxEntries remain
+// Returns the number of entries evicted
+func (qc *QueryCache) Shrink(maxEntries int) int {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+	
+	qc.cleanupExpired()
+	evicted := 0
+	for len(qc.cache) > maxEntries && len(qc.accessOrder) > 0 {
+		oldestKey := qc.accessOrder[0]
+		delete(qc.cache, oldestKey)
+		qc.accessOrder = qc.accessOrder[1:]
+		evicted++
+	}
+	return evicted
+}
+
 // Size returns current cache size
 func (qc *QueryCache) Size() int {
 	qc.mu.RLock()
@@ -0,0 +1,212 @@
+package database
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/tracing"
+	"market-terminal/internal/utils"
+)
+
+// RawResponse is one recorded API response: a line of a day's "<ticker>.raw.jsonl.gz" file
+type RawResponse struct {
+	ReceivedAt float64         `json:"t"` // Unix seconds
+	Endpoint   string          `json:"endpoint"`
+	Ticker     string          `json:"ticker"`
+	Trace      string          `json:"trace,omitempty"` // Correlation ID of the batch (groups the responses that made one row)
+	Body       json.RawMessage `json:"body"`
+}
+
+// RawRecorder persists raw endpoint responses next to each day's database (record mode)
+// Lines are buffered per file and appended as one gzip member every RawRecordFlushSec (gzip readers
+// treat the members as one stream), so a crash loses at most that much raw data
+type RawRecorder struct {
+	dw         *DataWriter
+	mu         sync.Mutex
+	enabled    bool
+	buffers    map[string]*bytes.Buffer // Raw file path -> JSONL not yet written
+	lastFlush  time.Time
+	debugPrint func(string, string)
+}
+
+func newRawRecorder(dw *DataWriter, debugPrint func(string, string)) *RawRecorder {
+	return &RawRecorder{
+		dw:         dw,
+		buffers:    make(map[string]*bytes.Buffer),
+		lastFlush:  time.Now(),
+		debugPrint: debugPrint,
+	}
+}
+
+// RawRecorder returns the writer's raw response recorder (pass it to api.Client.SetResponseRecorder)
+func (dw *DataWriter) RawRecorder() *RawRecorder {
+	return dw.raw
+}
+
+// RawResponsesPath returns the raw response file of a ticker's day (next to its database)
+func (dw *DataWriter) RawResponsesPath(ticker string, date time.Time) string {
+	return rawPathFor(dw.getDBPath(ticker, date))
+}
+
+func rawPathFor(dbPath string) string {
+	return strings.TrimSuffix(dbPath, ".db") + ".raw.jsonl.gz"
+}
+
+// SetEnabled turns recording on or off (turning it off writes what is buffered)
+func (r *RawRecorder) SetEnabled(enabled bool) {
+	r.mu.Lock()
+	r.enabled = enabled
+	r.mu.Unlock()
+	if !enabled {
+		if err := r.Flush(); err != nil {
+			r.debugPrint(fmt.Sprintf("RawRecorder: Flush failed: %v", err), "error")
+		}
+	}
+}
+
+// RecordResponse implements api.ResponseRecorder
+func (r *RawRecorder) RecordResponse(ctx context.Context, endpoint, ticker string, body []byte, receivedAt time.Time) {
+	r.mu.Lock()
+	enabled := r.enabled
+	r.mu.Unlock()
+	if !enabled {
+		return
+	}
+
+	// One response per line: compact the body (the API may pretty-print)
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, body); err != nil {
+		r.debugPrint(fmt.Sprintf("RawRecorder: Not recording %s for %s (invalid JSON: %v)", endpoint, ticker, err), "writer")
+		return
+	}
+	traceID := ""
+	if trace := tracing.FromContext(ctx); trace != nil {
+		traceID = trace.ID
+	}
+	line, err := json.Marshal(RawResponse{
+		ReceivedAt: float64(receivedAt.UnixNano()) / 1e9,
+		Endpoint:   endpoint,
+		Ticker:     ticker,
+		Trace:      traceID,
+		Body:       compact.Bytes(),
+	})
+	if err != nil {
+		return
+	}
+
+	path := rawPathFor(r.dw.getDBPath(ticker, currentEntryDate()))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	buffer := r.buffers[path]
+	if buffer == nil {
+		buffer = &bytes.Buffer{}
+		r.buffers[path] = buffer
+	}
+	buffer.Write(line)
+	buffer.WriteByte('\n')
+}
+
+// flushIfDue writes buffered lines once RawRecordFlushSec has passed (called by the background flusher)
+func (r *RawRecorder) flushIfDue() {
+	r.mu.Lock()
+	due := time.Since(r.lastFlush) >= time.Duration(config.RawRecordFlushSec)*time.Second
+	r.mu.Unlock()
+	if !due {
+		return
+	}
+	if err := r.Flush(); err != nil {
+		r.debugPrint(fmt.Sprintf("RawRecorder: Flush failed: %v", err), "error")
+	}
+}
+
+// Flush appends every buffered file's lines as a gzip member
+func (r *RawRecorder) Flush() error {
+	r.mu.Lock()
+	buffers := r.buffers
+	r.buffers = make(map[string]*bytes.Buffer)
+	r.lastFlush = time.Now()
+	r.mu.Unlock()
+
+	var firstErr error
+	for path, buffer := range buffers {
+		if err := appendGzipMember(path, buffer.Bytes()); err != nil {
+			r.debugPrint(fmt.Sprintf("RawRecorder: Dropped %d bytes for %s: %v", buffer.Len(), path, err), "error")
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// appendGzipMember appends data to path as a complete gzip member
+func appendGzipMember(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(file)
+	if _, err := zw.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// ScanRawResponses calls fn for each recorded response in a raw file, in recording order
+// A truncated final member (crash while appending) ends the scan without an error
+func ScanRawResponses(path string, fn func(RawResponse) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	zr, err := gzip.NewReader(bufio.NewReader(file))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer zr.Close()
+
+	scanner := bufio.NewScanner(zr)
+	scanner.Buffer(make([]byte, 0, 1024*1024), config.RawResponseMaxLineBytes)
+	for scanner.Scan() {
+		var response RawResponse
+		if err := json.Unmarshal(scanner.Bytes(), &response); err != nil {
+			continue // Partial line from a truncated member
+		}
+		if err := fn(response); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return nil
+}
+
+// currentEntryDate returns the market date new rows are written to (the writer's date rules:
+// after the 8:30 AM ET rollover it's today, weekends go to the last trading day)
+func currentEntryDate() time.Time {
+	marketDate := utils.GetMarketDate()
+	date := time.Date(marketDate.Year(), marketDate.Month(), marketDate.Day(), 0, 0, 0, 0, utils.GetMarketTimezone())
+	if utils.IsWeekend(date) {
+		return utils.GetLastTradingDay(date)
+	}
+	return date
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"fmt"
+	"sync"
+)
+
+// RejectedValueStatus counts what flushes couldn't store as collected: scalar values their column's type or
+// range check refuses are stored as NULL (the rest of the row is kept), rows breaking a row constraint are dropped
+type RejectedValueStatus struct {
+	Values    int64            `json:"values"`     // Values stored as NULL since startup
+	PerColumn map[string]int64 `json:"per_column"` // column -> values stored as NULL
+	Rows      int64            `json:"rows"`       // Rows not written at all
+	Last      string           `json:"last"`       // Most recent rejection, e.g. "SPX spot=-1 at 1767225600.000"
+}
+
+// rejectedValues accumulates RejectedValueStatus for a writer
+type rejectedValues struct {
+	mu     sync.Mutex
+	status RejectedValueStatus
+}
+
+// value records a value stored as NULL
+func (r *rejectedValues) value(ticker, column string, value interface{}, timestamp float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.status.PerColumn == nil {
+		r.status.PerColumn = make(map[string]int64)
+	}
+	r.status.Values++
+	r.status.PerColumn[column]++
+	r.status.Last = fmt.Sprintf("%s %s=%v at %.3f", ticker, column, value, timestamp)
+}
+
+// row records a row that wasn't written
+func (r *rejectedValues) row(ticker string, timestamp float64, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status.Rows++
+	r.status.Last = fmt.Sprintf("%s row at %.3f: %v", ticker, timestamp, err)
+}
+
+// GetRejectedValueStatus returns the values and rows flushes refused since startup
+func (dw *DataWriter) GetRejectedValueStatus() RejectedValueStatus {
+	dw.rejected.mu.Lock()
+	defer dw.rejected.mu.Unlock()
+	status := dw.rejected.status
+	status.PerColumn = make(map[string]int64, len(dw.rejected.status.PerColumn))
+	for column, count := range dw.rejected.status.PerColumn {
+		status.PerColumn[column] = count
+	}
+	return status
+}
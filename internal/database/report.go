@@ -0,0 +1,123 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// reportLevelColumns are the key level columns whose ranges are included in collection reports
+var reportLevelColumns = []string{
+	"spot",
+	"zero_gamma",
+	"major_pos_vol",
+	"major_neg_vol",
+	"major_positive",
+	"major_negative",
+	"major_pos_oi",
+	"major_neg_oi",
+}
+
+// LevelRange is the min/max of a single column over a day
+type LevelRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// TickerReport summarizes collection quality for a single ticker on a single day
+type TickerReport struct {
+	Ticker         string                `json:"ticker"`
+	DatabasePath   string                `json:"database_path"`
+	DatabaseExists bool                  `json:"database_exists"`
+	FileSizeBytes  int64                 `json:"file_size_bytes"`
+	RowCount       int                   `json:"row_count"`
+	FirstTimestamp float64               `json:"first_timestamp"`
+	LastTimestamp  float64               `json:"last_timestamp"`
+	GapCount       int                   `json:"gap_count"`       // Gaps between consecutive rows longer than the threshold
+	LongestGapSec  float64               `json:"longest_gap_sec"` // Longest gap between consecutive rows
+	LongestGapAt   float64               `json:"longest_gap_at"`  // Timestamp of the row that ended the longest gap
+	LevelRanges    map[string]LevelRange `json:"level_ranges"`
+	APIErrorCount  int                   `json:"api_error_count"`
+}
+
+// BuildTickerReport builds the collection quality report for a ticker's database
+// gapThresholdSec controls which gaps between consecutive rows are counted
+func (dl *DataLoader) BuildTickerReport(ticker string, date time.Time, gapThresholdSec float64) (*TickerReport, error) {
+	dbPath := dl.getDBPath(ticker, date)
+	report := &TickerReport{
+		Ticker:       ticker,
+		DatabasePath: dbPath,
+		LevelRanges:  make(map[string]LevelRange),
+	}
+
+	fileInfo, err := os.Stat(dbPath)
+	if os.IsNotExist(err) {
+		return report, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check file existence: %w", err)
+	}
+	report.DatabaseExists = true
+	report.FileSizeBytes = fileInfo.Size()
+
+	db, err := dl.pool.GetConnection(dbPath, true) // Read-only
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	// Gaps are computed from the timestamp column only (cheap even for large files)
+	rows, err := db.Query("SELECT timestamp FROM ticker_data ORDER BY timestamp ASC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query timestamps: %w", err)
+	}
+	defer rows.Close()
+
+	var prev float64
+	for rows.Next() {
+		var ts float64
+		if err := rows.Scan(&ts); err != nil {
+			return nil, fmt.Errorf("failed to scan timestamp: %w", err)
+		}
+		if report.RowCount == 0 {
+			report.FirstTimestamp = ts
+		} else {
+			gap := ts - prev
+			if gap > gapThresholdSec {
+				report.GapCount++
+			}
+			if gap > report.LongestGapSec {
+				report.LongestGapSec = gap
+				report.LongestGapAt = ts
+			}
+		}
+		report.LastTimestamp = ts
+		prev = ts
+		report.RowCount++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	existingColumns, err := dl.getExistingColumns(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing columns: %w", err)
+	}
+
+	for _, col := range reportLevelColumns {
+		if !existingColumns[col] {
+			continue
+		}
+		var minVal, maxVal sql.NullFloat64
+		query := fmt.Sprintf("SELECT MIN(%s), MAX(%s) FROM ticker_data", col, col)
+		if err := db.QueryRow(query).Scan(&minVal, &maxVal); err != nil {
+			return nil, fmt.Errorf("failed to query range for %s: %w", col, err)
+		}
+		if minVal.Valid && maxVal.Valid && !math.IsNaN(minVal.Float64) && !math.IsNaN(maxVal.Float64) {
+			report.LevelRanges[col] = LevelRange{Min: minVal.Float64, Max: maxVal.Float64}
+		}
+	}
+
+	return report, nil
+}
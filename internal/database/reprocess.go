@@ -0,0 +1,195 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// ReprocessResult summarizes a ReprocessDay run
+type ReprocessResult struct {
+	Ticker      string         `json:"ticker"`
+	Date        string         `json:"date"`
+	Rows        int            `json:"rows"`         // Rows scanned
+	UpdatedRows int            `json:"updated_rows"` // Rows that got at least one value
+	Filled      map[string]int `json:"filled"`       // Column -> values filled
+}
+
+// reprocessUpdate is the scalar values a row gains from re-extraction
+type reprocessUpdate struct {
+	timestamp float64
+	values    map[string]interface{}
+}
+
+// ReprocessDay re-runs the current field extraction over a day's stored rows: each row's profiles_blob is
+// decoded, merged with its scalar columns and split again, and scalars that are NULL in the database but
+// extracted now are written back (new columns are added). Values already stored are never overwritten,
+// and profiles_blob is left untouched
+// Fixes days where a field-mapping bug left a column NULL while the value was kept in the profiles
+func (dw *DataWriter) ReprocessDay(ctx context.Context, ticker string, date time.Time) (ReprocessResult, error) {
+	result := ReprocessResult{Ticker: ticker, Date: date.Format("2006-01-02"), Filled: make(map[string]int)}
+	dbPath := dw.getDBPath(ticker, date)
+	if _, err := os.Stat(dbPath); err != nil {
+		if _, encErr := os.Stat(EncryptedPath(dbPath)); encErr == nil {
+			return result, fmt.Errorf("%s on %s is encrypted - decrypt the day before reprocessing", ticker, result.Date)
+		}
+		return result, fmt.Errorf("no database for %s on %s", ticker, result.Date)
+	}
+
+	db, err := dw.pool.GetConnection(dbPath, false)
+	if err != nil {
+		return result, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	existing, err := NewSchemaManager(db).getExistingColumns()
+	if err != nil {
+		return result, fmt.Errorf("failed to get existing columns: %w", err)
+	}
+	columns := make([]string, 0, len(existing))
+	for col := range existing {
+		if col != "timestamp" && !strings.HasSuffix(col, "_blob") {
+			columns = append(columns, col)
+		}
+	}
+	sort.Strings(columns)
+
+	// Collect the updates first (only the filled values are kept), then write them
+	// The scan uses the reader handle: its keyframe lookups run while rows are open, which the
+	// single-connection writer handle can't serve
+	readDB, err := dw.pool.GetConnection(dbPath, true)
+	if err != nil {
+		return result, fmt.Errorf("failed to get read connection: %w", err)
+	}
+	updates, err := dw.collectReprocessUpdates(ctx, readDB, columns, &result)
+	if err != nil {
+		return result, err
+	}
+	if len(updates) == 0 {
+		dw.debugPrint(fmt.Sprintf("ReprocessDay: Nothing to fill for %s on %s (%d rows)", ticker, result.Date, result.Rows), "writer")
+		return result, nil
+	}
+
+	newColumns := make([]string, 0)
+	for col := range result.Filled {
+		if !existing[col] {
+			newColumns = append(newColumns, col)
+		}
+	}
+	if err := NewSchemaManager(db).EnsureTable(newColumns); err != nil {
+		return result, fmt.Errorf("failed to ensure schema: %w", err)
+	}
+
+	for start := 0; start < len(updates); start += config.ReplayBatchRows {
+		end := start + config.ReplayBatchRows
+		if end > len(updates) {
+			end = len(updates)
+		}
+		if err := applyReprocessUpdates(ctx, db, updates[start:end]); err != nil {
+			return result, fmt.Errorf("reprocess of %s on %s stopped after %d rows: %w", ticker, result.Date, start, err)
+		}
+	}
+	result.UpdatedRows = len(updates)
+
+	// 1-minute bars are built from the scalar columns
+	if err := dw.RebuildChartBars(ticker, date); err != nil {
+		dw.debugPrint(fmt.Sprintf("ReprocessDay: 1m bar rebuild warning for %s: %v", ticker, err), "writer")
+	}
+
+	dw.debugPrint(fmt.Sprintf("ReprocessDay: Filled %d of %d rows for %s on %s: %v",
+		result.UpdatedRows, result.Rows, ticker, result.Date, result.Filled), "writer")
+	return result, nil
+}
+
+// collectReprocessUpdates scans the table and returns the values each row gains from re-extraction
+func (dw *DataWriter) collectReprocessUpdates(ctx context.Context, db *sql.DB, columns []string, result *ReprocessResult) ([]reprocessUpdate, error) {
+	selectColumns := append([]string{"timestamp", "profiles_blob"}, columns...)
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM ticker_data ORDER BY timestamp", strings.Join(selectColumns, ", ")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	decoder := newProfileDecoder(db)
+	values := make([]interface{}, len(selectColumns))
+	valuePtrs := make([]interface{}, len(selectColumns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+
+	updates := make([]reprocessUpdate, 0)
+	for rows.Next() {
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		result.Rows++
+		timestamp, _ := values[0].(float64)
+		blob, _ := values[1].([]byte)
+
+		profiles, err := decoder.decode(timestamp, blob)
+		if err != nil {
+			continue // Undecodable profiles: nothing to re-extract from
+		}
+
+		// Rebuild the row as the writer saw it: profile fields plus the stored scalars
+		data := make(map[string]interface{}, len(profiles)+len(columns))
+		for key, value := range profiles {
+			data[key] = value
+		}
+		stored := make(map[string]bool, len(columns))
+		for i, col := range columns {
+			if values[i+2] != nil {
+				data[col] = values[i+2]
+				stored[col] = true
+			}
+		}
+
+		scalars, _ := splitEntry(data)
+		filled := make(map[string]interface{})
+		for key, value := range scalars {
+			col := sanitizeFieldName(key)
+			if stored[col] {
+				continue
+			}
+			filled[col] = value
+			result.Filled[col]++
+		}
+		if len(filled) > 0 {
+			updates = append(updates, reprocessUpdate{timestamp: timestamp, values: filled})
+		}
+	}
+	return updates, rows.Err()
+}
+
+// applyReprocessUpdates writes a batch of filled values in one transaction
+func applyReprocessUpdates(ctx context.Context, db *sql.DB, updates []reprocessUpdate) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, update := range updates {
+		cols := make([]string, 0, len(update.values))
+		for col := range update.values {
+			cols = append(cols, col)
+		}
+		sort.Strings(cols)
+		assignments := make([]string, len(cols))
+		args := make([]interface{}, 0, len(cols)+1)
+		for i, col := range cols {
+			assignments[i] = col + " = ?"
+			args = append(args, update.values[col])
+		}
+		args = append(args, update.timestamp)
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("UPDATE ticker_data SET %s WHERE timestamp = ?", strings.Join(assignments, ", ")), args...); err != nil {
+			return fmt.Errorf("failed to update row %.3f: %w", update.timestamp, err)
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,75 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LoadLatestRows returns the last n rows of a ticker's day (oldest first) as column -> value maps
+// Blob columns (profiles) are left out; used for small data samples such as diagnostics bundles
+func (dl *DataLoader) LoadLatestRows(ticker string, date time.Time, n int) ([]map[string]interface{}, error) {
+	dbPath := dl.getDBPath(ticker, date)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return []map[string]interface{}{}, nil
+	}
+
+	db, err := dl.pool.GetConnection(dbPath, true) // Read-only
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	existingColumns, err := dl.getExistingColumns(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing columns: %w", err)
+	}
+	columns := make([]string, 0, len(existingColumns))
+	for col := range existingColumns {
+		if !strings.HasSuffix(col, "_blob") {
+			columns = append(columns, col)
+		}
+	}
+	if len(columns) == 0 {
+		return []map[string]interface{}{}, nil
+	}
+	sort.Strings(columns)
+
+	rows, err := db.Query(fmt.Sprintf("SELECT %s FROM ticker_data ORDER BY timestamp DESC LIMIT ?", strings.Join(columns, ", ")), n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]map[string]interface{}, 0, n)
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, col := range columns {
+			switch v := values[i].(type) {
+			case []byte:
+				row[col] = string(v)
+			default:
+				row[col] = v
+			}
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Newest-first from the query; flip to timestamp order
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result, nil
+}
@@ -3,7 +3,11 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"math"
 	"strings"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/model"
 )
 
 // SchemaManager manages database schema creation and migration
@@ -16,14 +20,99 @@ func NewSchemaManager(db *sql.DB) *SchemaManager {
 	return &SchemaManager{db: db}
 }
 
+// Lower bounds a ticker_data column can be declared with
+const (
+	boundNone        = iota // Only the magnitude check
+	boundNonNegative        // >= 0
+	boundPositive           // > 0
+)
+
+// columnSpec is the declared type and range check of a ticker_data column
+type columnSpec struct {
+	Type  string // REAL or INTEGER
+	Lower int    // boundNone, boundNonNegative or boundPositive
+}
+
+// knownColumns types the fields whose range is known; other scalar fields are REAL with only the magnitude check
+// (price levels are strikes or prices, never negative; a zero is never written - splitEntry skips it)
+var knownColumns = map[string]columnSpec{
+	"spot":              {Type: "REAL", Lower: boundPositive},
+	"zero_gamma":        {Type: "REAL", Lower: boundNonNegative},
+	"major_pos_vol":     {Type: "REAL", Lower: boundNonNegative},
+	"major_neg_vol":     {Type: "REAL", Lower: boundNonNegative},
+	"major_pos_oi":      {Type: "REAL", Lower: boundNonNegative},
+	"major_neg_oi":      {Type: "REAL", Lower: boundNonNegative},
+	"major_positive":    {Type: "REAL", Lower: boundNonNegative},
+	"major_negative":    {Type: "REAL", Lower: boundNonNegative},
+	"major_long_gamma":  {Type: "REAL", Lower: boundNonNegative},
+	"major_short_gamma": {Type: "REAL", Lower: boundNonNegative},
+	"volume":            {Type: "REAL", Lower: boundNonNegative},
+	SpotVWAPColumn:      {Type: "REAL", Lower: boundPositive},
+	QualityColumn:       {Type: "INTEGER", Lower: boundNonNegative},
+}
+
+// specFor returns a column's spec (REAL with only the magnitude check unless the column is known)
+func specFor(column string) columnSpec {
+	if spec, ok := knownColumns[column]; ok {
+		return spec
+	}
+	return columnSpec{Type: "REAL"}
+}
+
+// columnDefinition returns the type and constraints a new scalar column is created with
+// Every column also rejects non-finite and absurd values (abs >= ScalarColumnMaxMagnitude), which are writer bugs
+func columnDefinition(column string) string {
+	spec := specFor(column)
+	check := fmt.Sprintf("abs(%s) < %g", column, config.ScalarColumnMaxMagnitude)
+	switch spec.Lower {
+	case boundNonNegative:
+		check = fmt.Sprintf("%s >= 0 AND %s", column, check)
+	case boundPositive:
+		check = fmt.Sprintf("%s > 0 AND %s", column, check)
+	}
+	return fmt.Sprintf("%s %s CHECK (%s)", column, spec.Type, check)
+}
+
+// acceptsValue reports whether a column's type and CHECK constraint accept a value (nil always fits)
+// The writer stores a rejected value as NULL, so one bad field doesn't cost the rest of the row
+func acceptsValue(column string, value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	number, ok := model.Number(value)
+	if !ok {
+		switch value.(type) {
+		case float64, float32, string, []byte:
+			return false // Non-finite numbers, text and blobs never fit a REAL or INTEGER column
+		}
+		return true // Not a column value at all: binding it fails the flush
+	}
+	if math.Abs(number) >= config.ScalarColumnMaxMagnitude {
+		return false
+	}
+	spec := specFor(column)
+	if spec.Type == "INTEGER" && number != math.Trunc(number) {
+		return false
+	}
+	switch spec.Lower {
+	case boundNonNegative:
+		return number >= 0
+	case boundPositive:
+		return number > 0
+	}
+	return true
+}
+
 // EnsureTable ensures the ticker_data table exists with proper schema
+// New databases get a STRICT table, so a value of the wrong type fails the insert instead of surfacing as a
+// broken chart later; databases created before that keep their table (new columns still get types and checks)
 func (sm *SchemaManager) EnsureTable(scalarFields []string) error {
 	// Create base table if it doesn't exist
 	_, err := sm.db.Exec(`
 		CREATE TABLE IF NOT EXISTS ticker_data (
-			timestamp REAL PRIMARY KEY,
+			timestamp REAL NOT NULL PRIMARY KEY CHECK (timestamp > 0),
 			profiles_blob BLOB
-		) WITHOUT ROWID
+		) STRICT, WITHOUT ROWID
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to create base table: %w", err)
@@ -43,20 +132,14 @@ func (sm *SchemaManager) EnsureTable(scalarFields []string) error {
 		}
 
 		if !existingColumns[sanitized] {
-			// Determine column type (default to REAL for numbers)
-			colType := "REAL"
-			// Could check field name patterns here for TEXT fields if needed
-
-			_, err := sm.db.Exec(fmt.Sprintf(
-				"ALTER TABLE ticker_data ADD COLUMN %s %s",
-				sanitized, colType,
-			))
+			_, err := sm.db.Exec("ALTER TABLE ticker_data ADD COLUMN " + columnDefinition(sanitized))
 			if err != nil {
 				// Column might already exist (race condition) - ignore
 				if !strings.Contains(err.Error(), "duplicate column") {
 					return fmt.Errorf("failed to add column %s: %w", sanitized, err)
 				}
 			}
+			existingColumns[sanitized] = true
 		}
 	}
 
@@ -80,6 +163,16 @@ func (sm *SchemaManager) EnsureTable(scalarFields []string) error {
 	return nil
 }
 
+// isConstraintError reports whether an insert failed on a column type (STRICT) or CHECK constraint
+// The statement is rolled back on its own; the rest of the transaction is unaffected
+func isConstraintError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "constraint failed") || strings.Contains(msg, "cannot store")
+}
+
 // getExistingColumns returns a map of existing column names
 func (sm *SchemaManager) getExistingColumns() (map[string]bool, error) {
 	rows, err := sm.db.Query(`
@@ -0,0 +1,75 @@
+package database
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/utils"
+)
+
+// A value its column refuses is stored as NULL; the row and its other fields are kept
+func TestFlushStoresRefusedValueAsNull(t *testing.T) {
+	settings := config.GetDefaultSettings()
+	settings.DataDirectory = filepath.Join(t.TempDir(), "data")
+	dw := NewDataWriter(settings, func(string, string) {})
+	defer dw.Close()
+	now := time.Date(2026, 3, 9, 10, 0, 0, 0, utils.MARKET_TIMEZONE)
+	dw.SetClock(utils.NewSimulatedClock(now, 0))
+
+	timestamp := float64(now.Unix())
+	entry := map[string]interface{}{"spot": -5.0, "zero_gamma": 4990.0, "major_pos_vol": 5100.0}
+	if err := dw.WriteDataEntry("SPX", timestamp, entry, false); err != nil {
+		t.Fatalf("WriteDataEntry: %v", err)
+	}
+	if err := dw.FlushTicker("SPX"); err != nil {
+		t.Fatalf("FlushTicker: %v", err)
+	}
+
+	db, err := dw.pool.GetConnection(filepath.Join(settings.DayDirectory(utils.GetMarketDateAt(now)), "SPX.db"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spot, zeroGamma, majorPosVol sql.NullFloat64
+	if err := db.QueryRow("SELECT spot, zero_gamma, major_pos_vol FROM ticker_data WHERE timestamp = ?", timestamp).
+		Scan(&spot, &zeroGamma, &majorPosVol); err != nil {
+		t.Fatalf("row not written: %v", err)
+	}
+	if spot.Valid {
+		t.Errorf("spot = %v, want NULL (spot must be > 0)", spot.Float64)
+	}
+	if zeroGamma.Float64 != 4990 || majorPosVol.Float64 != 5100 {
+		t.Errorf("zero_gamma = %v, major_pos_vol = %v, want the collected 4990 and 5100", zeroGamma, majorPosVol)
+	}
+
+	status := dw.GetRejectedValueStatus()
+	if status.Values != 1 || status.PerColumn["spot"] != 1 || status.Rows != 0 {
+		t.Errorf("rejected value status = %+v, want one spot value and no rows", status)
+	}
+}
+
+func TestAcceptsValue(t *testing.T) {
+	tests := []struct {
+		column string
+		value  interface{}
+		want   bool
+	}{
+		{"spot", 5000.0, true},
+		{"spot", 0.0, false},
+		{"zero_gamma", 0.0, true},
+		{"zero_gamma", -1.0, false},
+		{"delta_risk_reversal", -0.25, true},
+		{"delta_risk_reversal", 2e15, false},
+		{QualityColumn, 3.0, true},
+		{QualityColumn, 1.5, false},
+		{"spot", "5000", false},
+		{"spot", nil, true},
+	}
+	for _, tt := range tests {
+		if got := acceptsValue(tt.column, tt.value); got != tt.want {
+			t.Errorf("acceptsValue(%s, %v) = %v, want %v", tt.column, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// DailyStats summarizes a single ticker's session for the main window summary card
+type DailyStats struct {
+	Ticker   string `json:"ticker"`
+	Date     string `json:"date"`
+	RowCount int    `json:"row_count"`
+
+	// Session spot range
+	SpotOpen     float64 `json:"spot_open"`
+	SpotClose    float64 `json:"spot_close"`
+	SpotHigh     float64 `json:"spot_high"`
+	SpotHighTime float64 `json:"spot_high_time"`
+	SpotLow      float64 `json:"spot_low"`
+	SpotLowTime  float64 `json:"spot_low_time"`
+
+	// Time spent with spot above/below zero gamma (seconds)
+	SecondsAboveZeroGamma float64 `json:"seconds_above_zero_gamma"`
+	SecondsBelowZeroGamma float64 `json:"seconds_below_zero_gamma"`
+
+	// Largest zero gamma shifts between consecutive rows
+	LargestGammaFlips []GammaFlip `json:"largest_gamma_flips"`
+
+	// Volume-weighted averages of the volume-based major levels: each row counts with its volume, or equally
+	// when it has none (the spot_vwap weighting)
+	AvgMajorPosVol float64 `json:"avg_major_pos_vol"`
+	AvgMajorNegVol float64 `json:"avg_major_neg_vol"`
+
+	ComputedAt float64 `json:"computed_at"`
+}
+
+// GammaFlip represents a single zero gamma shift between consecutive rows
+type GammaFlip struct {
+	Timestamp float64 `json:"timestamp"`
+	From      float64 `json:"from"`
+	To        float64 `json:"to"`
+	Change    float64 `json:"change"`
+}
+
+// dailyStatsRow is a single row of the columns needed to compute daily stats
+type dailyStatsRow struct {
+	timestamp   float64
+	spot        sql.NullFloat64
+	zeroGamma   sql.NullFloat64
+	majorPosVol sql.NullFloat64
+	majorNegVol sql.NullFloat64
+	volume      sql.NullFloat64
+}
+
+// ComputeDailyStats computes the session summary for a ticker from its database
+// Returns nil stats (no error) if the database doesn't exist or has no rows
+func (dl *DataLoader) ComputeDailyStats(ticker string, date time.Time) (*DailyStats, error) {
+	dbPath := dl.getDBPath(ticker, date)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		dl.debugPrint(fmt.Sprintf("ComputeDailyStats: Database file does not exist for %s: %s", ticker, dbPath), "loader")
+		return nil, nil
+	}
+
+	db, err := dl.pool.GetConnection(dbPath, true) // Read-only
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	existingColumns, err := dl.getExistingColumns(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing columns: %w", err)
+	}
+
+	// Missing columns are selected as NULL so older databases still produce partial stats
+	selectCol := func(col string) string {
+		if existingColumns[col] {
+			return col
+		}
+		return "NULL"
+	}
+	query := fmt.Sprintf("SELECT timestamp, %s, %s, %s, %s, %s FROM ticker_data ORDER BY timestamp ASC",
+		selectCol("spot"), selectCol("zero_gamma"), selectCol("major_pos_vol"), selectCol("major_neg_vol"), selectCol("volume"))
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	data := make([]dailyStatsRow, 0)
+	for rows.Next() {
+		var row dailyStatsRow
+		if err := rows.Scan(&row.timestamp, &row.spot, &row.zeroGamma, &row.majorPosVol, &row.majorNegVol, &row.volume); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		data = append(data, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	if len(data) == 0 {
+		dl.debugPrint(fmt.Sprintf("ComputeDailyStats: No rows for %s on %s", ticker, date.Format("2006-01-02")), "loader")
+		return nil, nil
+	}
+
+	stats := computeDailyStats(data)
+	stats.Ticker = ticker
+	stats.Date = date.Format("2006-01-02")
+	stats.ComputedAt = float64(time.Now().UnixNano()) / 1e9
+
+	dl.debugPrint(fmt.Sprintf("ComputeDailyStats: Computed stats for %s on %s from %d rows", ticker, stats.Date, stats.RowCount), "loader")
+	return stats, nil
+}
+
+// computeDailyStats does the actual aggregation over rows sorted by timestamp
+func computeDailyStats(data []dailyStatsRow) *DailyStats {
+	stats := &DailyStats{
+		RowCount:          len(data),
+		SpotHigh:          math.Inf(-1),
+		SpotLow:           math.Inf(1),
+		LargestGammaFlips: make([]GammaFlip, 0),
+	}
+
+	flips := make([]GammaFlip, 0)
+	var posVolWeighted, posVolWeight, negVolWeighted, negVolWeight float64
+	haveSpot := false
+
+	for i, row := range data {
+		if row.spot.Valid {
+			if !haveSpot {
+				stats.SpotOpen = row.spot.Float64
+				haveSpot = true
+			}
+			stats.SpotClose = row.spot.Float64
+			if row.spot.Float64 > stats.SpotHigh {
+				stats.SpotHigh = row.spot.Float64
+				stats.SpotHighTime = row.timestamp
+			}
+			if row.spot.Float64 < stats.SpotLow {
+				stats.SpotLow = row.spot.Float64
+				stats.SpotLowTime = row.timestamp
+			}
+		}
+
+		weight := 1.0
+		if row.volume.Valid && row.volume.Float64 > 0 {
+			weight = row.volume.Float64
+		}
+		if row.majorPosVol.Valid {
+			posVolWeighted += row.majorPosVol.Float64 * weight
+			posVolWeight += weight
+		}
+		if row.majorNegVol.Valid {
+			negVolWeighted += row.majorNegVol.Float64 * weight
+			negVolWeight += weight
+		}
+
+		if i == 0 {
+			continue
+		}
+		prev := data[i-1]
+
+		// Zero gamma shifts between consecutive rows
+		if prev.zeroGamma.Valid && row.zeroGamma.Valid && prev.zeroGamma.Float64 != row.zeroGamma.Float64 {
+			flips = append(flips, GammaFlip{
+				Timestamp: row.timestamp,
+				From:      prev.zeroGamma.Float64,
+				To:        row.zeroGamma.Float64,
+				Change:    row.zeroGamma.Float64 - prev.zeroGamma.Float64,
+			})
+		}
+
+		// Durations are attributed to the previous row's values
+		// Long gaps (app closed, collection paused) are skipped so they don't skew the totals
+		dt := row.timestamp - prev.timestamp
+		if dt <= 0 || dt > config.DailyStatsMaxGapSec {
+			continue
+		}
+
+		if prev.spot.Valid && prev.zeroGamma.Valid {
+			if prev.spot.Float64 >= prev.zeroGamma.Float64 {
+				stats.SecondsAboveZeroGamma += dt
+			} else {
+				stats.SecondsBelowZeroGamma += dt
+			}
+		}
+	}
+
+	if !haveSpot {
+		stats.SpotHigh = 0
+		stats.SpotLow = 0
+	}
+	if posVolWeight > 0 {
+		stats.AvgMajorPosVol = posVolWeighted / posVolWeight
+	}
+	if negVolWeight > 0 {
+		stats.AvgMajorNegVol = negVolWeighted / negVolWeight
+	}
+
+	// Keep only the largest shifts by magnitude
+	sort.Slice(flips, func(i, j int) bool {
+		return math.Abs(flips[i].Change) > math.Abs(flips[j].Change)
+	})
+	if len(flips) > config.DailyStatsTopGammaFlips {
+		flips = flips[:config.DailyStatsTopGammaFlips]
+	}
+	stats.LargestGammaFlips = flips
+
+	return stats
+}
+
+// LoadDailyStats loads persisted end-of-day stats for a ticker
+// Returns nil stats (no error) if stats haven't been persisted for that day
+func (dl *DataLoader) LoadDailyStats(ticker string, date time.Time) (*DailyStats, error) {
+	dbPath := dl.getDBPath(ticker, date)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	db, err := dl.pool.GetConnection(dbPath, true) // Read-only
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	var tableName string
+	err = db.QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name='daily_stats'").Scan(&tableName)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check daily_stats table: %w", err)
+	}
+
+	var statsJSON string
+	err = db.QueryRow("SELECT stats_json FROM daily_stats WHERE date = ?", date.Format("2006-01-02")).Scan(&statsJSON)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily stats: %w", err)
+	}
+
+	var stats DailyStats
+	if err := json.Unmarshal([]byte(statsJSON), &stats); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal daily stats: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// SaveDailyStats persists end-of-day stats into the ticker's database
+// Stored as JSON so new fields don't require schema changes
+func (dw *DataWriter) SaveDailyStats(ticker string, date time.Time, stats *DailyStats) error {
+	if stats == nil {
+		return nil
+	}
+
+	dbPath := dw.getDBPath(ticker, date)
+	db, err := dw.pool.GetConnection(dbPath, false)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS daily_stats (
+		date TEXT PRIMARY KEY,
+		computed_at REAL NOT NULL,
+		stats_json TEXT NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("failed to create daily_stats table: %w", err)
+	}
+
+	statsJSON, err := json.Marshal(stats)
+	if err != nil {
+		return fmt.Errorf("failed to marshal daily stats: %w", err)
+	}
+
+	if _, err := db.Exec("INSERT OR REPLACE INTO daily_stats (date, computed_at, stats_json) VALUES (?, ?, ?)",
+		date.Format("2006-01-02"), stats.ComputedAt, string(statsJSON)); err != nil {
+		return fmt.Errorf("failed to save daily stats: %w", err)
+	}
+
+	dw.debugPrint(fmt.Sprintf("SaveDailyStats: Saved daily stats for %s on %s", ticker, date.Format("2006-01-02")), "writer")
+	return nil
+}
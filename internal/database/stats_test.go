@@ -0,0 +1,27 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestComputeDailyStatsVolumeWeightedMajorLevels(t *testing.T) {
+	row := func(timestamp, majorPosVol, volume float64) dailyStatsRow {
+		r := dailyStatsRow{timestamp: timestamp, majorPosVol: sql.NullFloat64{Float64: majorPosVol, Valid: true}}
+		if volume > 0 {
+			r.volume = sql.NullFloat64{Float64: volume, Valid: true}
+		}
+		return r
+	}
+
+	// The level held for most of the session traded little: a time-weighted average would sit near 5000
+	data := []dailyStatsRow{row(0, 5000, 100), row(3000, 5100, 900), row(3010, 5100, 0)}
+	stats := computeDailyStats(data)
+	want := (5000.0*100 + 5100*900 + 5100) / 1001
+	if diff := stats.AvgMajorPosVol - want; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("AvgMajorPosVol = %v, want %v", stats.AvgMajorPosVol, want)
+	}
+	if stats.AvgMajorNegVol != 0 {
+		t.Errorf("AvgMajorNegVol = %v without major_neg_vol, want 0", stats.AvgMajorNegVol)
+	}
+}
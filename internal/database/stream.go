@@ -0,0 +1,83 @@
+package database
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// StreamRows replays a ticker's day in timestamp order, calling fn once per row with the requested columns
+// Rows are read one at a time (never the whole day in memory); NULL or missing values are passed as NaN
+// Used for historical evaluation (alert back-tests) off the live path
+func (dl *DataLoader) StreamRows(ticker string, date time.Time, columns []string, fn func(timestamp float64, row map[string]float64) error) error {
+	dbPath := dl.getDBPath(ticker, date)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return fmt.Errorf("no data for %s on %s", ticker, date.Format("2006-01-02"))
+	}
+
+	db, err := dl.pool.GetConnection(dbPath, true) // Read-only
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	existingColumns, err := dl.getExistingColumns(db)
+	if err != nil {
+		return fmt.Errorf("failed to get existing columns: %w", err)
+	}
+	selected := make([]string, 0, len(columns))
+	for _, col := range columns {
+		if existingColumns[sanitizeFieldName(col)] && col != "timestamp" {
+			selected = append(selected, col)
+		}
+	}
+
+	selectCols := []string{"timestamp"}
+	for _, col := range selected {
+		selectCols = append(selectCols, sanitizeFieldName(col))
+	}
+	rows, err := db.Query(fmt.Sprintf("SELECT %s FROM ticker_data ORDER BY timestamp ASC", strings.Join(selectCols, ", ")))
+	if err != nil {
+		return fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	values := make([]interface{}, len(selectCols))
+	valuePtrs := make([]interface{}, len(selectCols))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		timestamp := toFloat(values[0])
+		if math.IsNaN(timestamp) {
+			continue
+		}
+		row := make(map[string]float64, len(columns))
+		for _, col := range columns {
+			row[col] = math.NaN()
+		}
+		for i, col := range selected {
+			row[col] = toFloat(values[i+1])
+		}
+		if err := fn(timestamp, row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// toFloat converts a scanned SQLite value to float64 (NaN for NULL or non-numeric)
+func toFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	default:
+		return math.NaN()
+	}
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"fmt"
+	"reflect"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/tsdb"
+)
+
+// SetTimeSeriesSink starts, replaces or stops mirroring of flushed scalar fields to a time-series database
+// Unchanged settings keep the running mirror (and its counters)
+func (dw *DataWriter) SetTimeSeriesSink(cfg config.TimeSeriesSinkSettings) error {
+	dw.mu.RLock()
+	unchanged := reflect.DeepEqual(dw.tsSettings, cfg) && (dw.tsMirror != nil) == cfg.Enabled
+	dw.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	var mirror *tsdb.Mirror
+	if cfg.Enabled {
+		m, err := tsdb.NewMirror(cfg, dw.debugPrint)
+		if err != nil {
+			return fmt.Errorf("failed to start time-series sink: %w", err)
+		}
+		mirror = m
+	}
+
+	dw.mu.Lock()
+	old := dw.tsMirror
+	dw.tsMirror = mirror
+	dw.tsSettings = cfg
+	dw.mu.Unlock()
+
+	// Let the old mirror drain its queue in the background
+	if old != nil {
+		go old.Stop()
+	}
+	return nil
+}
+
+// GetTimeSeriesSinkStatus returns the mirror's write counters (Enabled is false when no sink is configured)
+func (dw *DataWriter) GetTimeSeriesSinkStatus() tsdb.MirrorStatus {
+	dw.mu.RLock()
+	mirror := dw.tsMirror
+	dw.mu.RUnlock()
+	if mirror == nil {
+		return tsdb.MirrorStatus{}
+	}
+	return mirror.Status()
+}
+
+// mirrorWrites queues a flushed batch's numeric scalar fields for the time-series sink
+func (dw *DataWriter) mirrorWrites(ticker string, writes []*PendingWrite) {
+	dw.mu.RLock()
+	mirror := dw.tsMirror
+	dw.mu.RUnlock()
+	if mirror == nil || !mirror.MirrorsTicker(ticker) {
+		return
+	}
+
+	points := make([]tsdb.Point, 0, len(writes))
+	for _, write := range writes {
+		fields := make(map[string]float64, len(write.Scalars))
+		for field, value := range write.Scalars {
+			switch v := value.(type) {
+			case float64:
+				fields[sanitizeFieldName(field)] = v
+			case float32:
+				fields[sanitizeFieldName(field)] = float64(v)
+			case int:
+				fields[sanitizeFieldName(field)] = float64(v)
+			case int64:
+				fields[sanitizeFieldName(field)] = float64(v)
+			}
+		}
+		if len(fields) > 0 {
+			points = append(points, tsdb.Point{Ticker: ticker, Timestamp: write.Timestamp, Fields: fields})
+		}
+	}
+	mirror.Enqueue(points)
+}
+
+// stopTimeSeriesSink stops the mirror after its queue is written (called on close)
+func (dw *DataWriter) stopTimeSeriesSink() {
+	dw.mu.Lock()
+	mirror := dw.tsMirror
+	dw.tsMirror = nil
+	dw.mu.Unlock()
+	if mirror != nil {
+		mirror.Stop()
+	}
+}
@@ -0,0 +1,160 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"market-terminal/internal/utils"
+)
+
+// CompactionResult summarizes a compaction pass over closed databases
+type CompactionResult struct {
+	Databases      int      `json:"databases"`       // Databases compacted
+	Skipped        int      `json:"skipped"`         // Databases with no free pages to reclaim
+	BytesBefore    int64    `json:"bytes_before"`    // Size of compacted databases (incl. WAL) before
+	BytesAfter     int64    `json:"bytes_after"`     // Size of compacted databases (incl. WAL) after
+	ReclaimedBytes int64    `json:"reclaimed_bytes"` // BytesBefore - BytesAfter
+	DurationMs     int64    `json:"duration_ms"`
+	Errors         []string `json:"errors"`
+}
+
+// dbFileSize returns the size of a database including its WAL file
+func dbFileSize(dbPath string) int64 {
+	var size int64
+	for _, suffix := range []string{"", "-wal"} {
+		if info, err := os.Stat(dbPath + suffix); err == nil {
+			size += info.Size()
+		}
+	}
+	return size
+}
+
+// closedDayDirs returns data directories for market dates before the current one, newest first
+// lookbackDays > 0 limits the result to that many days back
+func (dw *DataWriter) closedDayDirs(lookbackDays int) []string {
+	today := utils.GetMarketDate()
+	days, err := dw.settings.ListDayDirectories(today.Location())
+	if err != nil {
+		return nil
+	}
+
+	oldest := time.Time{}
+	if lookbackDays > 0 {
+		oldest = today.AddDate(0, 0, -lookbackDays)
+	}
+
+	dirs := make([]string, 0, len(days))
+	for i := len(days) - 1; i >= 0; i-- {
+		if !days[i].Date.Before(today) || days[i].Date.Before(oldest) {
+			continue
+		}
+		dirs = append(dirs, days[i].Path)
+	}
+	return dirs
+}
+
+// CompactDatabases reclaims free pages in closed (previous market date) databases
+// Databases created with auto_vacuum=INCREMENTAL use incremental_vacuum; older ones get a full VACUUM
+// Databases without free pages are skipped, so repeated passes are cheap
+// lookbackDays > 0 only considers that many days back; encrypted (.db.enc) days are skipped
+func (dw *DataWriter) CompactDatabases(lookbackDays int) (*CompactionResult, error) {
+	dw.mu.Lock()
+	if dw.compacting {
+		dw.mu.Unlock()
+		return nil, fmt.Errorf("database compaction is already running")
+	}
+	dw.compacting = true
+	dw.mu.Unlock()
+	defer func() {
+		dw.mu.Lock()
+		dw.compacting = false
+		dw.mu.Unlock()
+	}()
+
+	start := time.Now()
+	result := &CompactionResult{Errors: make([]string, 0)}
+	for _, dir := range dw.closedDayDirs(lookbackDays) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("failed to read %s: %v", dir, err))
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".db") {
+				continue
+			}
+			dbPath := filepath.Join(dir, entry.Name())
+			before := dbFileSize(dbPath)
+			compacted, err := dw.compactDatabase(dbPath)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", dbPath, err))
+				dw.debugPrint(fmt.Sprintf("Compaction: Failed for %s: %v", dbPath, err), "error")
+				continue
+			}
+			if !compacted {
+				result.Skipped++
+				continue
+			}
+			after := dbFileSize(dbPath)
+			result.Databases++
+			result.BytesBefore += before
+			result.BytesAfter += after
+		}
+	}
+
+	result.ReclaimedBytes = result.BytesBefore - result.BytesAfter
+	result.DurationMs = time.Since(start).Milliseconds()
+	dw.debugPrint(fmt.Sprintf("Compaction: %d database(s) compacted, %d skipped, %.1f MB reclaimed in %dms",
+		result.Databases, result.Skipped, float64(result.ReclaimedBytes)/1024/1024, result.DurationMs), "writer")
+	return result, nil
+}
+
+// compactDatabase vacuums one database if it has free pages
+// Returns false when there was nothing to reclaim
+func (dw *DataWriter) compactDatabase(dbPath string) (bool, error) {
+	db, err := dw.pool.GetConnection(dbPath, false)
+	if err != nil {
+		return false, err
+	}
+	// Release the file afterwards - closed days are rarely written again
+	defer dw.pool.CloseConnection(dbPath)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	var freePages int64
+	if err := conn.QueryRowContext(ctx, "PRAGMA freelist_count").Scan(&freePages); err != nil {
+		return false, err
+	}
+	if freePages == 0 {
+		return false, nil
+	}
+
+	var autoVacuum int
+	if err := conn.QueryRowContext(ctx, "PRAGMA auto_vacuum").Scan(&autoVacuum); err != nil {
+		return false, err
+	}
+	if autoVacuum == 2 { // INCREMENTAL
+		_, err = conn.ExecContext(ctx, "PRAGMA incremental_vacuum")
+	} else {
+		_, err = conn.ExecContext(ctx, "VACUUM")
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// VACUUM goes through the WAL - truncate it so the reclaimed space shows up on disk
+	if _, err := conn.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return true, err
+	}
+	return true, nil
+}
@@ -0,0 +1,105 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// VerifyResult is the outcome of checking one ticker's database for a day
+type VerifyResult struct {
+	Ticker          string   `json:"ticker"`
+	Date            string   `json:"date"`
+	Path            string   `json:"path"`
+	Rows            int      `json:"rows"`
+	FirstTimestamp  float64  `json:"first_timestamp"`
+	LastTimestamp   float64  `json:"last_timestamp"`
+	BrokenProfiles  int      `json:"broken_profiles"`  // Rows whose profiles_blob doesn't decode (e.g. a missing keyframe)
+	IntegrityErrors []string `json:"integrity_errors"` // SQLite integrity_check messages (empty = ok)
+}
+
+// OK reports whether the database passed every check
+func (r *VerifyResult) OK() bool {
+	return len(r.IntegrityErrors) == 0 && r.BrokenProfiles == 0
+}
+
+// VerifyDay checks a ticker's database for a day: SQLite's integrity check, then every row's profiles are
+// decoded so a delta whose keyframe is gone is found before a chart needs it
+func (dl *DataLoader) VerifyDay(ticker string, date time.Time) (*VerifyResult, error) {
+	dbPath := dl.getDBPath(ticker, date)
+	result := &VerifyResult{Ticker: ticker, Date: date.Format("2006-01-02"), Path: dbPath, IntegrityErrors: make([]string, 0)}
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("no data for %s on %s", ticker, result.Date)
+	}
+	db, err := dl.pool.GetConnection(dbPath, true) // Read-only
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	checks, err := db.Query("PRAGMA integrity_check")
+	if err != nil {
+		return nil, fmt.Errorf("failed to run integrity check: %w", err)
+	}
+	for checks.Next() {
+		var message string
+		if err := checks.Scan(&message); err != nil {
+			checks.Close()
+			return nil, fmt.Errorf("failed to read integrity check: %w", err)
+		}
+		if message != "ok" {
+			result.IntegrityErrors = append(result.IntegrityErrors, message)
+		}
+	}
+	checks.Close()
+	if len(result.IntegrityErrors) > 0 {
+		return result, nil // Reading rows out of a damaged file proves nothing more
+	}
+
+	existing, err := dl.getExistingColumns(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing columns: %w", err)
+	}
+	blobColumn := "profiles_blob"
+	if !existing[blobColumn] {
+		blobColumn = "NULL"
+	}
+	rows, err := db.Query(fmt.Sprintf("SELECT timestamp, %s FROM ticker_data ORDER BY timestamp", blobColumn))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	decoder := newProfileDecoder(db)
+	for rows.Next() {
+		var timestamp float64
+		var blob []byte
+		if err := rows.Scan(&timestamp, &blob); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if result.Rows == 0 {
+			result.FirstTimestamp = timestamp
+		}
+		result.LastTimestamp = timestamp
+		result.Rows++
+		if _, err := decoder.decode(timestamp, blob); err != nil {
+			if result.BrokenProfiles == 0 {
+				dl.debugPrint(fmt.Sprintf("VerifyDay: %s on %s: profiles of row %.3f don't decode: %v", ticker, result.Date, timestamp, err), "loader")
+			}
+			result.BrokenProfiles++
+		}
+	}
+	return result, rows.Err()
+}
+
+// String summarizes the result on one line
+func (r *VerifyResult) String() string {
+	if len(r.IntegrityErrors) > 0 {
+		return fmt.Sprintf("%s %s: CORRUPT (%s)", r.Ticker, r.Date, strings.Join(r.IntegrityErrors, "; "))
+	}
+	status := "ok"
+	if r.BrokenProfiles > 0 {
+		status = fmt.Sprintf("%d row(s) with undecodable profiles", r.BrokenProfiles)
+	}
+	return fmt.Sprintf("%s %s: %d row(s), %s", r.Ticker, r.Date, r.Rows, status)
+}
@@ -1,10 +1,8 @@
 package database
 
 import (
-	"bytes"
-	"compress/gzip"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,6 +11,10 @@ import (
 	"time"
 
 	"market-terminal/internal/config"
+	"market-terminal/internal/crash"
+	"market-terminal/internal/model"
+	"market-terminal/internal/tracing"
+	"market-terminal/internal/tsdb"
 	"market-terminal/internal/utils"
 )
 
@@ -24,6 +26,24 @@ type DataWriter struct {
 	pendingWrites     map[string][]*PendingWrite // ticker -> []PendingWrite
 	firstPendingTime  map[string]time.Time       // When first pending write was added (for flush timing)
 	lastFlushTime     map[string]time.Time       // When last flush occurred
+	walSettings        config.WALCheckpointSettings // Checkpoint policy after flushes
+	passiveCheckpoints map[string]time.Time         // DB path -> last PASSIVE checkpoint (truncated once idle)
+	profileDelta       bool                         // Store profiles as keyframes + deltas
+	profileKeyframes   map[string]*profileKeyframe  // ticker -> current delta window
+	barRebuilds        map[string]bool              // DB paths with a RebuildChartBars in progress
+	derived            map[string]*derivedState     // ticker -> session aggregates of the derived series
+	derivedScripts     DerivedScriptFunc            // Script columns added on flush (nil = no scripts)
+	tsMirror           *tsdb.Mirror                 // Optional time-series sink fed after each flush (nil = disabled)
+	tsSettings         config.TimeSeriesSinkSettings
+	compacting         bool                         // CompactDatabases pass in progress
+	raw                *RawRecorder                 // Raw API response recording (record mode)
+	eco                bool                         // Eco mode: collection tickers batch more rows per flush
+	batching           *adaptiveBatching            // Scales collection flush thresholds with write pressure
+	essentialOnly      bool                         // Low disk space: store essential columns only, no profiles
+	halted             bool                         // Another instance took over the data directory: writes are refused
+	dayFilesMu         sync.RWMutex                 // Flushes hold it for reading; EncryptDay and CopyDay hold it while they replace or copy a day's files
+	clock              utils.ClockSource            // Source of "now" for the market date rows are filed under
+	rejected           rejectedValues               // Values and rows refused by the schema's constraints
 	settings          *config.Settings
 	debugPrint        func(string, string)
 	
@@ -39,14 +59,16 @@ type PendingWrite struct {
 	Scalars   map[string]interface{}
 	Profiles  map[string]interface{}
 	Date      time.Time
+	Trace     *tracing.Trace // Trace of the batch that produced the row (nil if untraced)
 }
 
 // NewDataWriter creates a new data writer
 func NewDataWriter(settings *config.Settings, debugPrint func(string, string)) *DataWriter {
+	poolSettings := settings.GetConnectionPoolSettings()
 	pool := NewConnectionPool(
-		config.DBConnectionPoolMaxSize,
-		time.Duration(config.SQLiteConnectionIdleTimeoutSeconds)*time.Second,
-		time.Duration(config.SQLiteConnectionCleanupIntervalSeconds)*time.Second,
+		poolSettings.MaxSize,
+		time.Duration(poolSettings.WriteIdleTimeoutSec)*time.Second,
+		time.Duration(poolSettings.CleanupIntervalSec)*time.Second,
 	)
 
 	dw := &DataWriter{
@@ -54,10 +76,18 @@ func NewDataWriter(settings *config.Settings, debugPrint func(string, string)) *
 		pendingWrites:    make(map[string][]*PendingWrite),
 		firstPendingTime: make(map[string]time.Time),
 		lastFlushTime:    make(map[string]time.Time),
+		walSettings:        settings.GetWALCheckpointSettings(),
+		passiveCheckpoints: make(map[string]time.Time),
+		profileDelta:       settings.ProfileDeltaCompression,
+		profileKeyframes:   make(map[string]*profileKeyframe),
+		barRebuilds:        make(map[string]bool),
+		derived:            make(map[string]*derivedState),
+		batching:           newAdaptiveBatching(),
 		settings:         settings,
 		debugPrint:       debugPrint,
 		stopChan:         make(chan struct{}),
 	}
+	dw.raw = newRawRecorder(dw, debugPrint)
 	
 	// Start background flusher
 	dw.startBackgroundFlusher()
@@ -65,6 +95,19 @@ func NewDataWriter(settings *config.Settings, debugPrint func(string, string)) *
 	return dw
 }
 
+// SetClock replaces the writer's clock (tests and simulated sessions; nil = follow the installed clock)
+func (dw *DataWriter) SetClock(clock utils.Clock) {
+	dw.clock.Set(clock)
+}
+
+// backgroundFlush runs one flusher pass; a panic is reported without stopping the flusher
+func (dw *DataWriter) backgroundFlush() {
+	defer crash.Recover("writer flush")
+	dw.checkAndFlushPending()
+	dw.truncateIdleWALs()
+	dw.raw.flushIfDue()
+}
+
 // startBackgroundFlusher starts a goroutine that periodically flushes pending writes
 func (dw *DataWriter) startBackgroundFlusher() {
 	dw.wg.Add(1)
@@ -81,7 +124,7 @@ func (dw *DataWriter) startBackgroundFlusher() {
 				dw.debugPrint("Background flusher stopping", "writer")
 				return
 			case <-ticker.C:
-				dw.checkAndFlushPending()
+				dw.backgroundFlush()
 			}
 		}
 	}()
@@ -137,49 +180,69 @@ func (dw *DataWriter) Stop() {
 	dw.debugPrint("DataWriter stopped", "writer")
 }
 
+// flushAllPending flushes every ticker with pending writes (before a day's files are replaced or copied)
+func (dw *DataWriter) flushAllPending(caller string) {
+	dw.mu.RLock()
+	pending := make([]string, 0, len(dw.pendingWrites))
+	for ticker, writes := range dw.pendingWrites {
+		if len(writes) > 0 {
+			pending = append(pending, ticker)
+		}
+	}
+	dw.mu.RUnlock()
+	for _, ticker := range pending {
+		if err := dw.FlushTicker(ticker); err != nil {
+			dw.debugPrint(fmt.Sprintf("%s: Failed to flush %s: %v", caller, ticker, err), "error")
+		}
+	}
+}
+
+// ErrWriterHalted is returned for writes after Halt
+var ErrWriterHalted = errors.New("data writer halted: another instance collects into the data directory")
+
+// Halt drops the pending writes and refuses new ones, without flushing: another instance has taken over the
+// data directory, and rows written from here on would interleave with its own. Returns the rows dropped
+func (dw *DataWriter) Halt() int {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	dropped := 0
+	for ticker, pending := range dw.pendingWrites {
+		dropped += len(pending)
+		delete(dw.pendingWrites, ticker)
+		delete(dw.firstPendingTime, ticker)
+	}
+	dw.halted = true
+	dw.debugPrint(fmt.Sprintf("DataWriter: Halted, %d pending row(s) dropped", dropped), "error")
+	return dropped
+}
+
 // WriteDataEntry writes a single data entry (queues for batch write)
 func (dw *DataWriter) WriteDataEntry(ticker string, timestamp float64, data map[string]interface{}, isActive bool) error {
-	dw.debugPrint(fmt.Sprintf("WriteDataEntry: Called for %s (timestamp: %.0f, fields: %d, active: %v)", 
-		ticker, timestamp, len(data), isActive), "writer")
+	return dw.WriteDataEntryContext(context.Background(), ticker, timestamp, data, isActive)
+}
+
+// WriteDataEntryContext is WriteDataEntry keeping ctx's trace (if any) with the row, so its flush is recorded on the trace
+func (dw *DataWriter) WriteDataEntryContext(ctx context.Context, ticker string, timestamp float64, data map[string]interface{}, isActive bool) error {
+	trace := tracing.FromContext(ctx)
+	dw.debugPrint(fmt.Sprintf("%sWriteDataEntry: Called for %s (timestamp: %.0f, fields: %d, active: %v)", 
+		trace.LogPrefix(), ticker, timestamp, len(data), isActive), "writer")
 	
 	dw.mu.Lock()
+	if dw.halted {
+		dw.mu.Unlock()
+		return ErrWriterHalted
+	}
 	// Note: We unlock before calling shouldFlush() to avoid deadlock
 	// shouldFlush() needs its own read lock, and we can't hold a write lock while acquiring a read lock
 
-	// Extract scalars and profiles
-	scalars := make(map[string]interface{})
-	profiles := make(map[string]interface{})
-
-	if prof, ok := data["profiles"].(map[string]interface{}); ok {
-		profiles = prof
-	}
-
-	scalarCount := 0
-	profileCount := 0
-	
-	for key, value := range data {
-		if key == "profiles" || key == "timestamp" || key == "ticker" || key == "_response_headers" || key == "_response_time" {
-			continue // Skip metadata fields
-		}
-
-		// Check if value is array/dict (store in profiles)
-		switch v := value.(type) {
-		case []interface{}, map[string]interface{}:
-			profiles[key] = v
-			profileCount++
-		default:
-			// Skip zero values for scalar fields (optimization - matches Python version)
-			// This reduces database size and improves performance
-			if v == nil || v == 0 || v == 0.0 || v == "" || v == false {
-				continue // Skip zero/null values
-			}
-			scalars[key] = v
-			scalarCount++
-		}
+	// Extract scalars and profiles (essential columns only while disk space is critical)
+	scalars, profiles := splitEntry(data)
+	if dw.essentialOnly {
+		scalars, profiles = essentialScalars(scalars), nil
 	}
 	
 	dw.debugPrint(fmt.Sprintf("WriteDataEntry: Extracted %d scalars, %d profiles for %s", 
-		scalarCount, profileCount, ticker), "writer")
+		len(scalars), len(profiles), ticker), "writer")
 
 	// Determine date from timestamp
 	// Convert to Eastern Time first, then use market date logic to handle weekends and rollover
@@ -190,7 +253,8 @@ func (dw *DataWriter) WriteDataEntry(ticker string, timestamp float64, data map[
 	// This ensures data is always written to today's directory (after 8:30 AM ET rollover)
 	// The timestamp in the data still reflects when the data was collected
 	// GetMarketDate() already handles rollover logic (8:30 AM ET)
-	currentMarketDate := utils.GetMarketDate()
+	currentET := dw.clock.Now().In(utils.GetMarketTimezone())
+	currentMarketDate := utils.GetMarketDateAt(currentET)
 	
 	// Extract just the date part (set to midnight) to avoid time component issues
 	// GetMarketDate() returns a time with full time component, but we only need the date
@@ -209,7 +273,6 @@ func (dw *DataWriter) WriteDataEntry(ticker string, timestamp float64, data map[
 	}
 	
 	// Debug logging for date calculation
-	currentET := utils.NowMarketTime()
 	dw.debugPrint(fmt.Sprintf("WriteDataEntry: Timestamp %d (UTC: %s, ET: %s) -> Current ET: %s -> GetMarketDate() returned: %s -> dateOnly: %s -> Final entryDate: %s", 
 		int64(timestamp), 
 		timestampTime.Format("2006-01-02 15:04:05 MST"),
@@ -238,6 +301,7 @@ func (dw *DataWriter) WriteDataEntry(ticker string, timestamp float64, data map[
 		Scalars:   scalars,
 		Profiles:  profiles,
 		Date:      entryDate,
+		Trace:     trace,
 	})
 	
 	pendingCount := len(dw.pendingWrites[ticker])
@@ -249,7 +313,7 @@ func (dw *DataWriter) WriteDataEntry(ticker string, timestamp float64, data map[
 	// Track when first pending write was added (for flush interval calculation)
 	// Only set if this is the first pending write in the current batch
 	if pendingCount == 1 {
-		dw.firstPendingTime[ticker] = time.Now()
+		dw.firstPendingTime[ticker] = dw.clock.Now()
 	}
 	
 	dw.debugPrint(fmt.Sprintf("WriteDataEntry: Added write to pending queue for %s (total pending: %d, first ever: %v)", 
@@ -278,6 +342,7 @@ func (dw *DataWriter) WriteDataEntry(ticker string, timestamp float64, data map[
 		dw.debugPrint(fmt.Sprintf("WriteDataEntry: Triggering flush for %s (pending: %d, active: %v, first ever: %v)", 
 			ticker, pendingCount, isActive, !hasFlushHistory), "writer")
 		go func() {
+			defer crash.Recover("writer flush")
 			if err := dw.FlushTicker(ticker); err != nil {
 				dw.debugPrint(fmt.Sprintf("WriteDataEntry: ❌ Flush failed for %s: %v", ticker, err), "error")
 			} else {
@@ -292,6 +357,72 @@ func (dw *DataWriter) WriteDataEntry(ticker string, timestamp float64, data map[
 	return nil
 }
 
+// splitEntry separates a row's fields into scalar columns and profiles (arrays/objects stored in profiles_blob)
+// Scalars are stored as float64 (see model.Number); text, nil values and metadata fields ("_" prefix) aren't stored
+func splitEntry(data map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	scalars := make(map[string]interface{}, len(data))
+	profiles := make(map[string]interface{})
+	if nested, ok := data["profiles"].(map[string]interface{}); ok {
+		for key, value := range nested {
+			profiles[key] = value
+		}
+	}
+
+	for key, value := range data {
+		if key == "profiles" || key == "timestamp" || key == "ticker" || strings.HasPrefix(key, "_") {
+			continue // Skip metadata fields
+		}
+		switch v := value.(type) {
+		case []interface{}, map[string]interface{}:
+			profiles[key] = v
+		default:
+			// Skip zero values for scalar fields (optimization - matches Python version)
+			// This reduces database size and improves performance
+			if number, ok := model.Number(v); ok && number != 0 {
+				scalars[key] = number
+			}
+		}
+	}
+	return scalars, profiles
+}
+
+// ReplacementRow is a row rebuilt outside the live collection path (e.g. replayed from raw responses)
+type ReplacementRow struct {
+	Timestamp float64
+	Data      map[string]interface{}
+}
+
+// ReplaceRows writes rows straight into a day's database, replacing existing rows with the same timestamp
+// Bypasses the pending queue (the rows belong to a specific day, not the current market date)
+func (dw *DataWriter) ReplaceRows(ctx context.Context, ticker string, date time.Time, rows []ReplacementRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	writes := make([]*PendingWrite, 0, len(rows))
+	for _, row := range rows {
+		scalars, profiles := splitEntry(row.Data)
+		writes = append(writes, &PendingWrite{
+			Ticker:    ticker,
+			Timestamp: row.Timestamp,
+			Scalars:   scalars,
+			Profiles:  profiles,
+			Date:      date,
+		})
+	}
+	err := dw.flushDate(ctx, ticker, date, writes)
+	// The batch may have moved the ticker's profile keyframe window to another day - start a new one
+	dw.resetProfileWindow(ticker)
+	return err
+}
+
+// SetEcoMode switches eco mode: collection tickers flush every EcoFlushIntervalSec/EcoFlushCountThreshold
+// instead of the normal thresholds (charted tickers still flush on every write)
+func (dw *DataWriter) SetEcoMode(eco bool) {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	dw.eco = eco
+}
+
 // shouldFlush determines if we should flush based on thresholds
 func (dw *DataWriter) shouldFlush(ticker string, isActive bool) bool {
 	dw.mu.RLock()
@@ -311,12 +442,9 @@ func (dw *DataWriter) shouldFlush(ticker string, isActive bool) bool {
 		return true
 	}
 
-	// Collection tickers flush after threshold
-	var countThreshold int
-	var intervalThreshold time.Duration
-
-	countThreshold = config.FileWriteCountThresholdCollection
-	intervalThreshold = time.Duration(config.FileWriteIntervalCollectionSec) * time.Second
+	// Collection tickers flush after threshold (scaled down under write pressure, up when idle)
+	baseCount, baseInterval := dw.baseFlushThresholds()
+	countThreshold, intervalThreshold := dw.batching.thresholds(baseCount, baseInterval, dw.pendingDepth())
 
 	if pendingCount >= countThreshold {
 		dw.debugPrint(fmt.Sprintf("shouldFlush: %s - true (pending count %d >= threshold %d)", 
@@ -334,7 +462,7 @@ func (dw *DataWriter) shouldFlush(ticker string, isActive bool) bool {
 		return true
 	}
 
-	timeSinceFirstPending := time.Since(firstPending)
+	timeSinceFirstPending := dw.clock.Now().Sub(firstPending)
 	if timeSinceFirstPending >= intervalThreshold {
 		dw.debugPrint(fmt.Sprintf("shouldFlush: %s - true (time since first pending %.1fs >= threshold %.1fs, pending: %d)", 
 			ticker, timeSinceFirstPending.Seconds(), intervalThreshold.Seconds(), pendingCount), "writer")
@@ -348,6 +476,12 @@ func (dw *DataWriter) shouldFlush(ticker string, isActive bool) bool {
 
 // FlushTicker flushes all pending writes for a ticker
 func (dw *DataWriter) FlushTicker(ticker string) error {
+	return dw.FlushTickerContext(context.Background(), ticker)
+}
+
+// FlushTickerContext is FlushTicker with cancellation: cancelling ctx rolls back the batch being
+// inserted and puts the writes back in the pending queue (nothing is lost while the writer is open)
+func (dw *DataWriter) FlushTickerContext(ctx context.Context, ticker string) error {
 	dw.debugPrint(fmt.Sprintf("FlushTicker: Starting flush for %s", ticker), "writer")
 	
 	dw.mu.Lock()
@@ -364,19 +498,28 @@ func (dw *DataWriter) FlushTicker(ticker string) error {
 	// Clear pending writes and reset timing
 	dw.pendingWrites[ticker] = make([]*PendingWrite, 0)
 	delete(dw.firstPendingTime, ticker) // Clear first pending time after flush
-	dw.lastFlushTime[ticker] = time.Now() // Record flush time
+	dw.lastFlushTime[ticker] = dw.clock.Now() // Record flush time
 	dw.mu.Unlock()
 
 	// Group by date
 	byDate := make(map[time.Time][]*PendingWrite)
 	for _, write := range pending {
-		date := time.Date(write.Date.Year(), write.Date.Month(), write.Date.Day(), 0, 0, 0, 0, time.UTC)
+		date := utils.MarketMidnight(write.Date)
 		byDate[date] = append(byDate[date], write)
 	}
 
 	// Flush each date
 	for date, writes := range byDate {
-		if err := dw.flushDate(ticker, date, writes); err != nil {
+		flushStart := time.Now()
+		err := dw.flushDate(ctx, ticker, date, writes)
+		dw.recordFlushSpans(ticker, date, writes, flushStart, err)
+		if err == nil {
+			dw.mu.RLock()
+			depth := dw.pendingDepth()
+			dw.mu.RUnlock()
+			dw.batching.observe(time.Since(flushStart), depth)
+		}
+		if err != nil {
 			dw.debugPrint(fmt.Sprintf("Failed to flush %s for date %s: %v", ticker, date.Format("2006-01-02"), err), "error")
 			// Re-add failed writes
 			dw.mu.Lock()
@@ -389,8 +532,25 @@ func (dw *DataWriter) FlushTicker(ticker string) error {
 	return nil
 }
 
+// recordFlushSpans records a flush span on the trace of each traced row in a flushed batch
+func (dw *DataWriter) recordFlushSpans(ticker string, date time.Time, writes []*PendingWrite, start time.Time, err error) {
+	for _, write := range writes {
+		if write.Trace == nil {
+			continue
+		}
+		span := write.Trace.StartSpanAt("flush", ticker, start)
+		span.SetAttr("date", date.Format("2006-01-02"))
+		span.SetAttr("batch_rows", fmt.Sprintf("%d", len(writes)))
+		span.End(err)
+		if err == nil {
+			dw.debugPrint(fmt.Sprintf("%sFlushTicker: Row for %s (timestamp %.0f) on disk %.2fs after the batch started",
+				write.Trace.LogPrefix(), ticker, write.Timestamp, time.Since(write.Trace.Start).Seconds()), "writer")
+		}
+	}
+}
+
 // flushDate flushes writes for a specific date
-func (dw *DataWriter) flushDate(ticker string, date time.Time, writes []*PendingWrite) error {
+func (dw *DataWriter) flushDate(ctx context.Context, ticker string, date time.Time, writes []*PendingWrite) error {
 	// Deduplicate timestamps (100ms tolerance - matches Python TIMESTAMP_DEDUP_TOLERANCE_DATA_LOADING)
 	// This prevents duplicate data points in the database
 	const tolerance = 0.1 // 100ms in seconds
@@ -405,6 +565,13 @@ func (dw *DataWriter) flushDate(ticker string, date time.Time, writes []*Pending
 	dbPath := dw.getDBPath(ticker, date)
 	dw.debugPrint(fmt.Sprintf("flushDate: Flushing %d writes for %s to %s", len(writes), ticker, dbPath), "writer")
 
+	// An encrypted day is closed: writing would recreate a plaintext database that the loader reads instead
+	dw.dayFilesMu.RLock()
+	defer dw.dayFilesMu.RUnlock()
+	if _, err := os.Stat(EncryptedPath(dbPath)); err == nil {
+		return fmt.Errorf("%s is encrypted, rows for a closed day are not written", EncryptedPath(dbPath))
+	}
+
 	// Get connection
 	db, err := dw.pool.GetConnection(dbPath, false)
 	if err != nil {
@@ -427,21 +594,8 @@ func (dw *DataWriter) flushDate(ticker string, date time.Time, writes []*Pending
 	
 	// Pre-create expected chart columns even if not in current batch
 	// This prevents "no such column" errors when reading data before all fields are written
-	expectedChartColumns := []string{
-		"spot",
-		"zero_gamma",
-		"major_pos_vol",
-		"major_neg_vol",
-		"major_long_gamma",
-		"major_short_gamma",
-		"major_positive",
-		"major_negative",
-		"major_pos_oi",
-		"major_neg_oi",
-	}
-	
 	// Add expected columns that aren't already in scalarFields
-	for _, expectedCol := range expectedChartColumns {
+	for _, expectedCol := range expectedChartColumns() {
 		if !scalarFieldsSet[expectedCol] {
 			scalarFields = append(scalarFields, expectedCol)
 			scalarFieldsSet[expectedCol] = true
@@ -453,8 +607,28 @@ func (dw *DataWriter) flushDate(ticker string, date time.Time, writes []*Pending
 		return fmt.Errorf("failed to ensure schema: %w", err)
 	}
 
+	// Session VWAP and level distances are computed here so every reader sees the same values
+	if err := dw.addDerivedSeries(ctx, db, ticker, dbPath, writes); err != nil {
+		return err
+	}
+	if scriptColumns := dw.addScriptColumns(ticker, writes, scalarFieldsSet); len(scriptColumns) > 0 {
+		if err := schemaManager.EnsureTable(scriptColumns); err != nil {
+			return fmt.Errorf("failed to ensure script columns: %w", err)
+		}
+	}
+
+	// Max-change payloads are stored in their own table instead of the profiles blob
+	for _, write := range writes {
+		if maxChanges, _ := takeMaxChanges(write); len(maxChanges) > 0 {
+			if err := ensureMaxChangeTable(db); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
 	// Begin transaction
-	tx, err := db.Begin()
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -476,71 +650,122 @@ func (dw *DataWriter) flushDate(ticker string, date time.Time, writes []*Pending
 
 	// Prepare insert statement
 	insertSQL := dw.buildInsertStatement(scalarFieldsList)
-	stmt, err := tx.Prepare(insertSQL)
+	stmt, err := tx.PrepareContext(ctx, insertSQL)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
 	}
 	defer stmt.Close()
 
 	// Insert each write
+	rejected := 0
+	nulled := make([]string, 0)
+	profileEncoder := dw.newProfileEncoder(ticker, dbPath)
 	for _, write := range writes {
-		// Compress profiles to BLOB
-		var profilesBlob []byte
-		if len(write.Profiles) > 0 {
-			profilesJSON, err := json.Marshal(write.Profiles)
+		maxChanges, profiles := takeMaxChanges(write)
+		row := *write
+		row.Profiles = profiles
+
+		// A row replacing a keyframe stays a keyframe, so the deltas based on it keep a base
+		var replacedProfiles map[string]interface{}
+		var replacedBlob []byte
+		if profileEncoder.delta {
+			replacedProfiles, replacedBlob, err = existingKeyframe(ctx, tx, write.Timestamp)
 			if err != nil {
-				return fmt.Errorf("failed to marshal profiles: %w", err)
+				return err
 			}
+		}
 
-			// Compress with gzip
-			var buf bytes.Buffer
-			gz := gzip.NewWriter(&buf)
-			if _, err := gz.Write(profilesJSON); err != nil {
-				return fmt.Errorf("failed to compress profiles: %w", err)
-			}
-			if err := gz.Close(); err != nil {
-				return fmt.Errorf("failed to close gzip writer: %w", err)
+		// Compress profiles to BLOB (gzip, or keyframe/delta when delta compression is enabled)
+		var profilesBlob []byte
+		isKeyframe := false
+		if len(row.Profiles) > 0 {
+			profilesBlob, isKeyframe, err = profileEncoder.encode(&row, replacedBlob != nil)
+			if err != nil {
+				return err
 			}
-			profilesBlob = buf.Bytes()
+		} else if replacedBlob != nil {
+			profilesBlob = replacedBlob
 		}
 
-		// Build values for insert
+		// Build values for insert; a value its column's type or range check refuses (a bug upstream) is stored
+		// as NULL and left out of the bars and mirror, so it doesn't cost the row's other fields
 		args := []interface{}{write.Timestamp, profilesBlob}
 		for _, field := range scalarFieldsList {
-			if value, ok := write.Scalars[field]; ok {
-				args = append(args, value)
-			} else {
-				args = append(args, nil)
+			value := write.Scalars[field]
+			if !acceptsValue(sanitizeFieldName(field), value) {
+				dw.rejected.value(ticker, field, value, write.Timestamp)
+				nulled = append(nulled, fmt.Sprintf("%s=%v at %.3f", field, value, write.Timestamp))
+				delete(write.Scalars, field)
+				value = nil
 			}
+			args = append(args, value)
 		}
 
-		if _, err := stmt.Exec(args...); err != nil {
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			if isConstraintError(err) {
+				// The row itself breaks a constraint (e.g. its timestamp) - drop it rather than retry the batch forever
+				dw.debugPrint(fmt.Sprintf("flushDate: Rejected row %.3f for %s (%s): %v", write.Timestamp, ticker, dbPath, err), "error")
+				dw.rejected.row(ticker, write.Timestamp, err)
+				rejected++
+				if len(row.Profiles) > 0 {
+					profileEncoder.rejected(isKeyframe)
+				}
+				continue
+			}
 			return fmt.Errorf("failed to insert: %w", err)
 		}
+		if replacedProfiles != nil && len(row.Profiles) > 0 {
+			rebased, err := rebaseProfileDeltas(ctx, tx, write.Timestamp, replacedProfiles, row.Profiles)
+			if err != nil {
+				return err
+			}
+			if rebased > 0 {
+				dw.debugPrint(fmt.Sprintf("flushDate: Keyframe %.3f for %s replaced, rebased %d profile deltas", write.Timestamp, ticker, rebased), "writer")
+			}
+		}
+		if len(maxChanges) > 0 {
+			if err := insertMaxChanges(ctx, tx, maxChanges); err != nil {
+				return err
+			}
+		}
+	}
+	if rejected > 0 {
+		dw.debugPrint(fmt.Sprintf("flushDate: %d of %d rows for %s failed schema constraints and were not written", rejected, len(writes), ticker), "error")
+	}
+	if len(nulled) > 0 {
+		shown := nulled
+		if len(shown) > config.RejectedValuesLogged {
+			shown = shown[:config.RejectedValuesLogged]
+		}
+		dw.debugPrint(fmt.Sprintf("flushDate: %d value(s) for %s refused by their column's type or range check, stored as NULL: %s",
+			len(nulled), ticker, strings.Join(shown, ", ")), "error")
 	}
 
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit: %w", err)
 	}
+	dw.commitProfileWindow(ticker, profileEncoder)
 
 	dw.debugPrint(fmt.Sprintf("flushDate: Transaction committed for %s to %s", ticker, dbPath), "writer")
 
-	// WAL checkpointing: Checkpoint WAL file after every flush (prevents WAL file growth)
-	// This matches Python version which checkpoints every flush
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	conn, err := db.Conn(ctx)
-	if err == nil {
-		// Execute WAL checkpoint (TRUNCATE mode moves WAL data to main DB and truncates WAL file)
-		_, err = conn.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)")
-		if err != nil {
-			// Log but don't fail - checkpoint is optional
-			dw.debugPrint(fmt.Sprintf("WAL checkpoint warning for %s: %v", ticker, err), "writer")
-		} else {
-			dw.debugPrint(fmt.Sprintf("WAL checkpoint completed for %s", ticker), "writer")
-		}
-		conn.Close()
+	// Mirror scalar fields to the time-series sink (non-blocking, SQLite stays the primary store)
+	dw.mirrorWrites(ticker, writes)
+
+	// Keep the 1-minute bars for the touched minutes in sync (full-day charts read them)
+	if err := dw.updateChartBars(db, writes); err != nil {
+		// Log but don't fail - bars can be rebuilt from the raw rows
+		dw.debugPrint(fmt.Sprintf("flushDate: 1m bar update warning for %s: %v", ticker, err), "writer")
+	}
+
+	// WAL checkpointing after every flush (prevents WAL file growth)
+	// Adaptive mode uses PASSIVE during market hours so simultaneous flushes don't stall each other
+	mode := dw.checkpointMode(dbPath)
+	if err := dw.checkpoint(db, dbPath, mode); err != nil {
+		// Log but don't fail - checkpoint is optional
+		dw.debugPrint(fmt.Sprintf("WAL checkpoint (%s) warning for %s: %v", mode, ticker, err), "writer")
+	} else {
+		dw.debugPrint(fmt.Sprintf("WAL checkpoint (%s) completed for %s", mode, ticker), "writer")
 	}
 
 	// Verify database file exists after commit and checkpoint
@@ -596,10 +821,7 @@ func (dw *DataWriter) buildInsertStatement(scalarFields []string) string {
 // The date passed here is already the correct market date (from WriteDataEntry)
 // We only need to handle weekend adjustments if the date is a weekend
 func (dw *DataWriter) getDBPath(ticker string, date time.Time) string {
-	dataDir := dw.settings.DataDirectory
-	if dataDir == "" {
-		dataDir = "Tickers"
-	}
+	dataDir := dw.settings.GetDataDirectory()
 
 	// The date passed to this function is already the correct market date
 	// (it was calculated in WriteDataEntry using GetMarketDate() which handles rollover)
@@ -607,7 +829,8 @@ func (dw *DataWriter) getDBPath(ticker string, date time.Time) string {
 	// Since the date is at midnight, GetMarketDateForDate() would think it's before 8:30 AM
 	// and subtract a day, causing the wrong directory to be created
 	
-	// Only handle weekend adjustment if needed
+	// Only handle weekend adjustment if needed (on the date's own calendar day, so a midnight UTC date isn't read as the previous evening ET)
+	date = utils.MarketMidnight(date)
 	var marketDate time.Time
 	if utils.IsWeekend(date) {
 		marketDate = utils.GetLastTradingDay(date)
@@ -617,14 +840,12 @@ func (dw *DataWriter) getDBPath(ticker string, date time.Time) string {
 		marketDate = date
 	}
 
-	// Format date as MM.DD.YYYY
-	dateStr := marketDate.Format("01.02.2006")
-	// Directory format: "Tickers 01.14.2026" (not "Tickers\Tickers 01.14.2026")
-	dir := fmt.Sprintf("%s %s", dataDir, dateStr)
+	// Directory format: "Tickers 01.14.2026" (not "Tickers\Tickers 01.14.2026"), or "Tickers/2026/01/14" with data_layout: iso
+	dir := dw.settings.DayDirectory(marketDate)
 	
 	// Log directory construction
-	dw.debugPrint(fmt.Sprintf("getDBPath: Constructing path for %s on %s (market date: %s): dataDir=%s, dateStr=%s, dir=%s", 
-		ticker, date.Format("2006-01-02"), marketDate.Format("2006-01-02"), dataDir, dateStr, dir), "writer")
+	dw.debugPrint(fmt.Sprintf("getDBPath: Constructing path for %s on %s (market date: %s): dataDir=%s, dir=%s", 
+		ticker, date.Format("2006-01-02"), marketDate.Format("2006-01-02"), dataDir, dir), "writer")
 	
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		dw.debugPrint(fmt.Sprintf("getDBPath: WARNING - Failed to create directory %s: %v", dir, err), "error")
@@ -656,16 +877,22 @@ func (dw *DataWriter) deduplicateWrites(writes []*PendingWrite, tolerance float6
 	
 	// Deduplicate: keep last write within tolerance window
 	result := make([]*PendingWrite, 0)
+	merged := false
 	for i := 0; i < len(sorted); i++ {
 		// Check if this timestamp is within tolerance of next write
 		if i < len(sorted)-1 {
 			timeDiff := sorted[i+1].Timestamp - sorted[i].Timestamp
 			if timeDiff <= tolerance {
 				// Within tolerance - skip this one, keep the next (last in group)
+				merged = true
 				continue
 			}
 		}
-		// Keep this write (either last in group or unique)
+		// Keep this write (either last in group or unique), flagged if others were merged into it
+		if merged {
+			AddQualityFlags(sorted[i].Scalars, QualityDedupMerged)
+			merged = false
+		}
 		result = append(result, sorted[i])
 	}
 	
@@ -675,6 +902,31 @@ func (dw *DataWriter) deduplicateWrites(writes []*PendingWrite, tolerance float6
 // Close closes all connections and flushes any pending writes
 // Ensures all data is written to disk and WAL files are cleaned up
 func (dw *DataWriter) Close() error {
+	return dw.CloseContext(context.Background())
+}
+
+// CloseContext is Close with a bound on the final flush: once ctx is done the remaining tickers
+// are skipped (and logged) so a stuck database can't hold up shutdown
+func (dw *DataWriter) CloseContext(ctx context.Context) error {
+	return dw.CloseWithProgress(ctx, nil)
+}
+
+// PendingTickerCount returns how many tickers have writes waiting to be flushed
+func (dw *DataWriter) PendingTickerCount() int {
+	dw.mu.RLock()
+	defer dw.mu.RUnlock()
+	count := 0
+	for _, pending := range dw.pendingWrites {
+		if len(pending) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// CloseWithProgress is CloseContext reporting (flushed, total) tickers as the final flush goes (shutdown splash)
+// A cancelled ctx rolls back the batch being inserted; the database is still checkpointed and closed
+func (dw *DataWriter) CloseWithProgress(ctx context.Context, progress func(flushed, total int)) error {
 	dw.debugPrint("DataWriter: Closing - flushing all pending writes", "writer")
 	
 	// Flush all pending writes before closing
@@ -687,13 +939,32 @@ func (dw *DataWriter) Close() error {
 	}
 	dw.mu.Unlock()
 	
+	if progress != nil {
+		progress(0, len(tickersToFlush))
+	}
+	
 	// Flush each ticker synchronously (we're shutting down, so async doesn't matter)
-	for _, ticker := range tickersToFlush {
-		if err := dw.FlushTicker(ticker); err != nil {
+	for i, ticker := range tickersToFlush {
+		if ctx.Err() != nil {
+			dw.debugPrint(fmt.Sprintf("DataWriter: Warning - close deadline reached, %d ticker(s) not flushed: %v", len(tickersToFlush)-i, tickersToFlush[i:]), "error")
+			break
+		}
+		if err := dw.FlushTickerContext(ctx, ticker); err != nil {
 			dw.debugPrint(fmt.Sprintf("DataWriter: Warning - failed to flush %s on close: %v", ticker, err), "error")
 		} else {
 			dw.debugPrint(fmt.Sprintf("DataWriter: Flushed %s on close", ticker), "writer")
 		}
+		if progress != nil {
+			progress(i+1, len(tickersToFlush))
+		}
+	}
+	
+	// Write whatever the time-series mirror still has queued
+	dw.stopTimeSeriesSink()
+	
+	// Write buffered raw responses
+	if err := dw.raw.Flush(); err != nil {
+		dw.debugPrint(fmt.Sprintf("DataWriter: Warning - failed to write raw responses on close: %v", err), "error")
 	}
 	
 	// Close connection pool (this will checkpoint WAL and close all connections)
@@ -0,0 +1,144 @@
+package datasync
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// RemoteFile is a file stored at a sync destination
+// Name is "<YYYY-MM-DD>/<file>" (e.g. "2026-01-14/SPX.db")
+type RemoteFile struct {
+	Name    string
+	Size    int64
+	ModTime time.Time // Modification time, if the destination keeps the local file's (zero otherwise)
+	MD5     string    // Hex MD5 of the content, if the destination reports it (empty otherwise)
+}
+
+// Destination is a place completed daily databases are pushed to and pulled from
+type Destination interface {
+	// List returns every synced file at the destination
+	List() ([]RemoteFile, error)
+	// Upload copies a local file to the destination under name
+	Upload(name, localPath string) error
+	// Download copies a remote file to a local path
+	Download(name, localPath string) error
+	// Describe returns a human-readable description (no credentials)
+	Describe() string
+}
+
+// NewDestination creates the destination configured in sync settings
+func NewDestination(cfg config.SyncSettings) (Destination, error) {
+	switch cfg.Type {
+	case config.SyncTypeFolder:
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("sync folder path is not configured")
+		}
+		return &FolderDestination{root: cfg.Path}, nil
+	case config.SyncTypeS3:
+		if cfg.Bucket == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+			return nil, fmt.Errorf("sync S3 bucket and credentials must be configured")
+		}
+		return NewS3Destination(cfg), nil
+	case config.SyncTypeSFTP:
+		return nil, fmt.Errorf("SFTP sync is not supported yet - mount the server as a network share and use the folder type")
+	default:
+		return nil, fmt.Errorf("unknown sync destination type: %q", cfg.Type)
+	}
+}
+
+// FolderDestination syncs to a local folder or mounted network share
+type FolderDestination struct {
+	root string
+}
+
+// List returns every file in "<root>/<date>/" directories
+func (fd *FolderDestination) List() ([]RemoteFile, error) {
+	days, err := os.ReadDir(fd.root)
+	if os.IsNotExist(err) {
+		return []RemoteFile{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync folder: %w", err)
+	}
+
+	files := make([]RemoteFile, 0)
+	for _, day := range days {
+		if !day.IsDir() {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(fd.root, day.Name()))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || strings.HasSuffix(entry.Name(), ".tmp") {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			files = append(files, RemoteFile{Name: day.Name() + "/" + entry.Name(), Size: info.Size(), ModTime: info.ModTime()})
+		}
+	}
+	return files, nil
+}
+
+// Upload copies a local file into the folder
+func (fd *FolderDestination) Upload(name, localPath string) error {
+	return copyFile(localPath, filepath.Join(fd.root, filepath.FromSlash(name)))
+}
+
+// Download copies a file out of the folder
+func (fd *FolderDestination) Download(name, localPath string) error {
+	return copyFile(filepath.Join(fd.root, filepath.FromSlash(name)), localPath)
+}
+
+// Describe returns the folder path
+func (fd *FolderDestination) Describe() string {
+	return "folder " + fd.root
+}
+
+// copyFile copies src to dst via a temp file so a partial copy is never visible
+// The copy keeps src's modification time, which the next push compares
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", src, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", tmp, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to close %s: %w", tmp, err)
+	}
+	if err := os.Chtimes(tmp, info.ModTime(), info.ModTime()); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to set the time of %s: %w", tmp, err)
+	}
+	return os.Rename(tmp, dst)
+}
@@ -0,0 +1,251 @@
+package datasync
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/utils"
+)
+
+// Result summarizes a push or pull run
+type Result struct {
+	Direction   string   `json:"direction"` // "push" or "pull"
+	Destination string   `json:"destination"`
+	Transferred []string `json:"transferred"`
+	Skipped     int      `json:"skipped"`
+	Errors      []string `json:"errors"`
+	StartedAt   string   `json:"started_at"`
+	DurationSec float64  `json:"duration_sec"`
+}
+
+// Syncer pushes completed daily databases to a destination and pulls missing days
+type Syncer struct {
+	mu          sync.Mutex // Serializes sync runs
+	getSettings func() *config.Settings
+	copyDay     func(date time.Time, dst string) ([]string, error) // Consistent copy of a day's files (nil = read in place)
+	debugPrint  func(string, string)
+	lastResult  *Result
+}
+
+// NewSyncer creates a new syncer
+func NewSyncer(getSettings func() *config.Settings, debugPrint func(string, string)) *Syncer {
+	return &Syncer{
+		getSettings: getSettings,
+		debugPrint:  debugPrint,
+	}
+}
+
+// SetDayCopier sets how PushDay gets a day's files: copyDay copies them into dst with their WAL checkpointed and
+// the databases closed (the data writer's CopyDay), so a push never uploads a file mid-write
+func (s *Syncer) SetDayCopier(copyDay func(date time.Time, dst string) ([]string, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.copyDay = copyDay
+}
+
+// dayDir returns the local data directory for a market date ("Tickers 01.14.2026" or "Tickers/2026/01/14")
+func (s *Syncer) dayDir(date time.Time) string {
+	return s.getSettings().DayDirectory(date)
+}
+
+// isSyncedFile reports whether a file in a day directory should be synced
+func isSyncedFile(name string) bool {
+	return strings.HasSuffix(name, ".db") || strings.HasSuffix(name, ".db.enc")
+}
+
+// destination builds the configured destination, or returns an error if sync is disabled
+func (s *Syncer) destination() (Destination, error) {
+	cfg := s.getSettings().Sync
+	if !cfg.Enabled {
+		return nil, fmt.Errorf("sync is disabled in settings")
+	}
+	return NewDestination(cfg)
+}
+
+// PushDay uploads a completed market date's databases that are missing or differ remotely
+func (s *Syncer) PushDay(date time.Time) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dest, err := s.destination()
+	if err != nil {
+		return nil, err
+	}
+
+	result := s.newResult("push", dest)
+	start := time.Now()
+
+	remote, err := dest.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dest.Describe(), err)
+	}
+	remoteFiles := make(map[string]RemoteFile, len(remote))
+	for _, file := range remote {
+		remoteFiles[file.Name] = file
+	}
+
+	dir := s.dayDir(date)
+	if s.copyDay != nil {
+		tmp, err := os.MkdirTemp("", "market-terminal-sync-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create sync directory: %w", err)
+		}
+		defer os.RemoveAll(tmp)
+		if _, err := s.copyDay(date, tmp); err != nil {
+			return nil, fmt.Errorf("failed to copy %s: %w", dir, err)
+		}
+		dir = tmp
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !isSyncedFile(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		name := date.Format("2006-01-02") + "/" + entry.Name()
+		localPath := filepath.Join(dir, entry.Name())
+		if file, exists := remoteFiles[name]; exists && unchanged(file, localPath, info) {
+			result.Skipped++
+			continue
+		}
+
+		if err := dest.Upload(name, localPath); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			s.debugPrint(fmt.Sprintf("Sync: Failed to push %s: %v", name, err), "error")
+			continue
+		}
+		result.Transferred = append(result.Transferred, name)
+	}
+
+	return s.finish(result, start), nil
+}
+
+// PullMissing downloads remote databases that don't exist locally
+// The current market date is never pulled (it's still being collected locally)
+func (s *Syncer) PullMissing() (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dest, err := s.destination()
+	if err != nil {
+		return nil, err
+	}
+
+	result := s.newResult("pull", dest)
+	start := time.Now()
+
+	remote, err := dest.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dest.Describe(), err)
+	}
+
+	today := utils.GetMarketDate().Format("2006-01-02")
+	for _, file := range remote {
+		parts := strings.SplitN(file.Name, "/", 2)
+		if len(parts) != 2 || !isSyncedFile(parts[1]) || strings.Contains(parts[1], "/") {
+			continue
+		}
+		date, err := utils.ParseDateInET(parts[0])
+		if err != nil || parts[0] == today {
+			continue
+		}
+
+		// Either form (plain or encrypted) existing locally counts as present
+		localPath := filepath.Join(s.dayDir(date), parts[1])
+		basePath := strings.TrimSuffix(localPath, ".enc")
+		if fileExists(basePath) || fileExists(basePath+".enc") {
+			result.Skipped++
+			continue
+		}
+
+		if err := dest.Download(file.Name, localPath); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			s.debugPrint(fmt.Sprintf("Sync: Failed to pull %s: %v", file.Name, err), "error")
+			continue
+		}
+		result.Transferred = append(result.Transferred, file.Name)
+	}
+
+	return s.finish(result, start), nil
+}
+
+// GetLastResult returns the result of the most recent sync run (nil if none yet)
+func (s *Syncer) GetLastResult() *Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastResult
+}
+
+func (s *Syncer) newResult(direction string, dest Destination) *Result {
+	return &Result{
+		Direction:   direction,
+		Destination: dest.Describe(),
+		Transferred: make([]string, 0),
+		Errors:      make([]string, 0),
+		StartedAt:   time.Now().Format(time.RFC3339),
+	}
+}
+
+// finish records the result (caller holds mu)
+func (s *Syncer) finish(result *Result, start time.Time) *Result {
+	result.DurationSec = time.Since(start).Seconds()
+	s.lastResult = result
+	s.debugPrint(fmt.Sprintf("Sync: %s to %s complete - %d transferred, %d skipped, %d errors (%.1fs)",
+		result.Direction, result.Destination, len(result.Transferred), result.Skipped, len(result.Errors), result.DurationSec), "system")
+	return result
+}
+
+// unchanged reports whether a remote copy matches the local file: by content hash where the destination
+// reports one (S3), otherwise by size and modification time (folder copies keep the local time; a file rewritten
+// at the same size has a newer one). Times within syncModTimeSlack match, for filesystems with 2s resolution
+func unchanged(remote RemoteFile, localPath string, info os.FileInfo) bool {
+	if remote.Size != info.Size() {
+		return false
+	}
+	if remote.MD5 != "" {
+		sum, err := fileMD5(localPath)
+		return err == nil && sum == remote.MD5
+	}
+	if remote.ModTime.IsZero() {
+		return false
+	}
+	diff := remote.ModTime.Sub(info.ModTime())
+	return diff > -syncModTimeSlack && diff < syncModTimeSlack
+}
+
+// syncModTimeSlack is the modification time difference still counted as the same file
+const syncModTimeSlack = 2 * time.Second
+
+// fileMD5 returns the hex MD5 of a file's content
+func fileMD5(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := md5.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,77 @@
+// Package deeplink parses mgt:// links (e.g. mgt://chart/SPX?date=2026-01-14 in notes or chat) and registers
+// the scheme with the OS so clicking one starts the app, or hands the link to the running instance
+package deeplink
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// ActionChart opens a ticker's chart window
+const ActionChart = "chart"
+
+// Link is a parsed deep link
+type Link struct {
+	Action string // ActionChart
+	Ticker string // Upper-cased
+	Date   string // "YYYY-MM-DD", "" = current market date
+}
+
+var tickerPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9._^-]{0,15}$`)
+
+// IsLink reports whether a command-line argument is a deep link
+func IsLink(arg string) bool {
+	return strings.HasPrefix(strings.ToLower(arg), config.URLScheme+"://")
+}
+
+// Parse reads a deep link; links come from outside the app, so anything unexpected is rejected
+func Parse(raw string) (Link, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return Link{}, fmt.Errorf("invalid link %q: %w", raw, err)
+	}
+	if !strings.EqualFold(u.Scheme, config.URLScheme) {
+		return Link{}, fmt.Errorf("not a %s:// link: %q", config.URLScheme, raw)
+	}
+
+	link := Link{Action: strings.ToLower(u.Host)}
+	switch link.Action {
+	case ActionChart:
+		link.Ticker = strings.ToUpper(strings.Trim(u.Path, "/"))
+		if !tickerPattern.MatchString(link.Ticker) {
+			return Link{}, fmt.Errorf("invalid ticker in link %q", raw)
+		}
+		link.Date = u.Query().Get("date")
+		if link.Date != "" {
+			if _, err := time.Parse("2006-01-02", link.Date); err != nil {
+				return Link{}, fmt.Errorf("invalid date in link %q (expected YYYY-MM-DD)", raw)
+			}
+		}
+	default:
+		return Link{}, fmt.Errorf("unknown link action %q (expected %s://%s/TICKER)", u.Host, config.URLScheme, ActionChart)
+	}
+	return link, nil
+}
+
+// Register points the mgt:// scheme at the running executable for the current user
+// Skipped for `go run` builds (a temporary executable that is gone after the run)
+func Register() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate executable: %w", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return fmt.Errorf("failed to locate executable: %w", err)
+	}
+	if tmp, err := filepath.EvalSymlinks(os.TempDir()); err == nil && strings.HasPrefix(exe, tmp+string(filepath.Separator)) {
+		return fmt.Errorf("not registering temporary executable %s (go run)", exe)
+	}
+	return register(exe)
+}
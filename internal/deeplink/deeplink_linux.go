@@ -0,0 +1,52 @@
+//go:build linux
+
+package deeplink
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"market-terminal/internal/config"
+)
+
+// desktopFile is the handler entry written to the user's applications directory
+const desktopFile = "market-terminal-url.desktop"
+
+// register writes a desktop entry for x-scheme-handler/mgt and makes it the default via xdg-mime
+// The entry is only rewritten when it changed, and xdg-mime only run when another handler is the default
+func register(exe string) error {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	dir := filepath.Join(dataHome, "applications")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	entry := []byte(fmt.Sprintf("[Desktop Entry]\nType=Application\nName=Market Terminal\nExec=%q %%u\nNoDisplay=true\nMimeType=x-scheme-handler/%s;\n",
+		exe, config.URLScheme))
+	path := filepath.Join(dir, desktopFile)
+	if current, err := os.ReadFile(path); err != nil || !bytes.Equal(current, entry) {
+		if err := os.WriteFile(path, entry, 0644); err != nil {
+			return fmt.Errorf("failed to write desktop entry: %w", err)
+		}
+	}
+
+	mimeType := "x-scheme-handler/" + config.URLScheme
+	if out, err := exec.Command("xdg-mime", "query", "default", mimeType).Output(); err == nil && strings.TrimSpace(string(out)) == desktopFile {
+		return nil
+	}
+	if out, err := exec.Command("xdg-mime", "default", desktopFile, mimeType).CombinedOutput(); err != nil {
+		return fmt.Errorf("xdg-mime failed: %w (%s)", err, out)
+	}
+	return nil
+}
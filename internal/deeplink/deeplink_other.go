@@ -0,0 +1,11 @@
+//go:build !windows && !linux
+
+package deeplink
+
+import "errors"
+
+// On macOS the scheme is declared in the app bundle's Info.plist (CFBundleURLTypes) and registered by
+// Launch Services when the bundle is installed; links then arrive as ApplicationLaunchedWithUrl events
+func register(exe string) error {
+	return errors.New("URL scheme registration is part of the app bundle on this platform")
+}
@@ -0,0 +1,48 @@
+//go:build windows
+
+package deeplink
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows/registry"
+
+	"market-terminal/internal/config"
+)
+
+// register writes the scheme's handler under HKCU\Software\Classes (per user, no elevation needed)
+// Values that already hold the right data are left alone, so a normal start doesn't write the registry
+func register(exe string) error {
+	key := `Software\Classes\` + config.URLScheme
+	entries := []struct {
+		path, name, value string
+	}{
+		{key, "", "URL:Market Terminal"},
+		{key, "URL Protocol", ""},
+		{key + `\DefaultIcon`, "", fmt.Sprintf(`"%s",0`, exe)},
+		{key + `\shell\open\command`, "", fmt.Sprintf(`"%s" "%%1"`, exe)},
+	}
+	for _, entry := range entries {
+		if err := setUserString(entry.path, entry.name, entry.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setUserString sets a string value under HKCU (creating the key) unless it already holds value
+func setUserString(path, name, value string) error {
+	k, _, err := registry.CreateKey(registry.CURRENT_USER, path, registry.QUERY_VALUE|registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf(`failed to open HKCU\%s: %w`, path, err)
+	}
+	defer k.Close()
+
+	if current, valueType, err := k.GetStringValue(name); err == nil && valueType == registry.SZ && current == value {
+		return nil
+	}
+	if err := k.SetStringValue(name, value); err != nil {
+		return fmt.Errorf(`failed to set HKCU\%s [%s]: %w`, path, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package hotkeys
+
+import (
+	"fmt"
+	"sync"
+
+	"market-terminal/internal/config"
+)
+
+// Status is the registration state of one action's shortcut
+type Status struct {
+	Action     string `json:"action"`
+	Shortcut   string `json:"shortcut"`
+	Registered bool   `json:"registered"`      // Held by this app system-wide
+	Error      string `json:"error,omitempty"` // Why it isn't (invalid, taken by another app, unsupported platform)
+}
+
+// Manager registers system-wide shortcuts with the OS and calls the handler with the action when one is pressed
+// Registration is per platform (hotkeys_<os>.go); where the OS isn't supported every status carries the reason
+type Manager struct {
+	mu         sync.Mutex
+	handler    func(action string)
+	debugPrint func(string, string)
+	platform   *platform // Started on the first Apply that needs it
+	actions    map[int]string
+	status     []Status
+	nextID     int
+}
+
+// NewManager creates a manager; the handler runs on its own goroutine for each press
+func NewManager(handler func(action string), debugPrint func(string, string)) *Manager {
+	return &Manager{
+		handler:    handler,
+		debugPrint: debugPrint,
+		actions:    make(map[int]string),
+		status:     make([]Status, 0),
+	}
+}
+
+// Apply replaces the registered shortcuts with the settings' bindings (none when disabled)
+// A shortcut that can't be registered doesn't stop the others; see Status
+func (m *Manager) Apply(settings config.HotkeySettings) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.platform != nil {
+		for id := range m.actions {
+			m.platform.unregister(id)
+		}
+	}
+	m.actions = make(map[int]string)
+	m.status = make([]Status, 0)
+	if !settings.Enabled {
+		return
+	}
+
+	bindings := settings.Bindings()
+	actions := make([]string, 0, len(bindings))
+	for _, action := range config.HotkeyActions {
+		if _, ok := bindings[action]; ok {
+			actions = append(actions, action)
+		}
+	}
+
+	var platformErr error
+	if m.platform == nil {
+		m.platform, platformErr = newPlatform(m.pressed)
+	}
+	for _, action := range actions {
+		status := Status{Action: action, Shortcut: bindings[action]}
+		shortcut, err := config.ParseShortcut(bindings[action])
+		if err == nil {
+			status.Shortcut = shortcut.String()
+			err = platformErr
+		}
+		if err == nil {
+			m.nextID++
+			if err = m.platform.register(m.nextID, shortcut); err == nil {
+				m.actions[m.nextID] = action
+				status.Registered = true
+			}
+		}
+		if err != nil {
+			status.Error = err.Error()
+			m.debugPrint(fmt.Sprintf("Hotkeys: %s (%s) not registered: %v", action, status.Shortcut, err), "app")
+		}
+		m.status = append(m.status, status)
+	}
+	m.debugPrint(fmt.Sprintf("Hotkeys: Registered %d of %d shortcut(s)", len(m.actions), len(actions)), "app")
+}
+
+// Status returns each configured action's shortcut and whether it is registered
+func (m *Manager) Status() []Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Status(nil), m.status...)
+}
+
+// Stop unregisters every shortcut and stops the platform listener
+func (m *Manager) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.platform == nil {
+		return
+	}
+	for id := range m.actions {
+		m.platform.unregister(id)
+	}
+	m.actions = make(map[int]string)
+	m.platform.close()
+	m.platform = nil
+}
+
+// pressed is called by the platform listener with the ID of the shortcut that fired
+// It must not block: Apply holds the lock while it waits for the listener to register shortcuts
+func (m *Manager) pressed(id int) {
+	go func() {
+		m.mu.Lock()
+		action, ok := m.actions[id]
+		m.mu.Unlock()
+		if ok {
+			m.handler(action)
+		}
+	}()
+}
@@ -0,0 +1,27 @@
+//go:build !windows
+
+package hotkeys
+
+import (
+	"errors"
+
+	"market-terminal/internal/config"
+)
+
+// System-wide registration needs the OS event APIs (Carbon on macOS, X11/portals on Linux), which aren't
+// reachable without cgo; the main window still handles the shortcuts while it is focused
+var errUnsupported = errors.New("system-wide shortcuts are not supported on this platform (they work while the main window is focused)")
+
+type platform struct{}
+
+func newPlatform(onHotkey func(id int)) (*platform, error) {
+	return nil, errUnsupported
+}
+
+func (p *platform) register(id int, shortcut config.Shortcut) error {
+	return errUnsupported
+}
+
+func (p *platform) unregister(id int) {}
+
+func (p *platform) close() {}
@@ -0,0 +1,156 @@
+//go:build windows
+
+package hotkeys
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"market-terminal/internal/config"
+)
+
+// On Windows shortcuts are registered with RegisterHotKey on a dedicated OS thread; WM_HOTKEY is posted
+// to that thread's message queue, so (un)registration also has to run there
+
+var (
+	user32                 = syscall.NewLazyDLL("user32.dll")
+	kernel32               = syscall.NewLazyDLL("kernel32.dll")
+	procRegisterHotKey     = user32.NewProc("RegisterHotKey")
+	procUnregisterHotKey   = user32.NewProc("UnregisterHotKey")
+	procGetMessageW        = user32.NewProc("GetMessageW")
+	procPeekMessageW       = user32.NewProc("PeekMessageW")
+	procPostThreadMessageW = user32.NewProc("PostThreadMessageW")
+	procGetCurrentThreadId = kernel32.NewProc("GetCurrentThreadId")
+)
+
+const (
+	modAlt      = 0x0001
+	modControl  = 0x0002
+	modShift    = 0x0004
+	modWin      = 0x0008
+	modNoRepeat = 0x4000
+
+	wmQuit     = 0x0012
+	wmHotkey   = 0x0312
+	wmApp      = 0x8000 // Wakes the listener to run queued requests
+	pmNoRemove = 0x0000
+
+	vkF1 = 0x70
+)
+
+type msg struct {
+	hwnd     uintptr
+	message  uint32
+	wParam   uintptr
+	lParam   uintptr
+	time     uint32
+	pt       struct{ x, y int32 }
+	lPrivate uint32
+}
+
+type platform struct {
+	threadID uint32
+	requests chan func()
+	onHotkey func(id int)
+}
+
+func newPlatform(onHotkey func(id int)) (*platform, error) {
+	p := &platform{requests: make(chan func(), 16), onHotkey: onHotkey}
+	ready := make(chan struct{})
+	go p.loop(ready)
+	<-ready
+	return p, nil
+}
+
+// loop owns the hotkeys: it runs requests posted by do and reports WM_HOTKEY until WM_QUIT
+func (p *platform) loop(ready chan struct{}) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	id, _, _ := procGetCurrentThreadId.Call()
+	p.threadID = uint32(id)
+	// Create the thread's message queue before anything is posted to it
+	var m msg
+	procPeekMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, wmApp, wmApp, pmNoRemove)
+	close(ready)
+
+	for {
+		r, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+		if int32(r) <= 0 {
+			return // WM_QUIT or error
+		}
+		switch m.message {
+		case wmHotkey:
+			p.onHotkey(int(m.wParam))
+		case wmApp:
+			p.runRequests()
+		}
+	}
+}
+
+func (p *platform) runRequests() {
+	for {
+		select {
+		case fn := <-p.requests:
+			fn()
+		default:
+			return
+		}
+	}
+}
+
+// do runs fn on the listener thread and waits for its result
+func (p *platform) do(fn func() error) error {
+	result := make(chan error, 1)
+	p.requests <- func() { result <- fn() }
+	if r, _, err := procPostThreadMessageW.Call(uintptr(p.threadID), wmApp, 0, 0); r == 0 {
+		return fmt.Errorf("hotkey listener is not running: %v", err)
+	}
+	return <-result
+}
+
+func (p *platform) register(id int, shortcut config.Shortcut) error {
+	mods := uintptr(modNoRepeat)
+	if shortcut.Ctrl {
+		mods |= modControl
+	}
+	if shortcut.Alt {
+		mods |= modAlt
+	}
+	if shortcut.Shift {
+		mods |= modShift
+	}
+	if shortcut.Super {
+		mods |= modWin
+	}
+	vk := virtualKey(shortcut.Key)
+	return p.do(func() error {
+		if r, _, err := procRegisterHotKey.Call(0, uintptr(id), mods, vk); r == 0 {
+			return fmt.Errorf("%s is already used by another application (%v)", shortcut, err)
+		}
+		return nil
+	})
+}
+
+func (p *platform) unregister(id int) {
+	p.do(func() error {
+		procUnregisterHotKey.Call(0, uintptr(id))
+		return nil
+	})
+}
+
+func (p *platform) close() {
+	procPostThreadMessageW.Call(uintptr(p.threadID), wmQuit, 0, 0)
+}
+
+// virtualKey maps a Shortcut key to its virtual-key code (A-Z and 0-9 are their ASCII codes)
+func virtualKey(key string) uintptr {
+	if len(key) == 1 {
+		return uintptr(key[0])
+	}
+	var n int
+	fmt.Sscanf(key, "F%d", &n)
+	return uintptr(vkF1 + n - 1)
+}
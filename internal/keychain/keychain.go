@@ -0,0 +1,56 @@
+package keychain
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// ServiceName is the service name secrets are stored under in the OS keychain
+const ServiceName = "market-terminal"
+
+// ErrNotFound is returned when a secret doesn't exist in the keychain
+var ErrNotFound = errors.New("secret not found in keychain")
+
+// Get returns a secret from the OS keychain
+func Get(account string) ([]byte, error) {
+	encoded, err := loadSecret(account)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret in keychain: %w", err)
+	}
+	return secret, nil
+}
+
+// Set stores a secret in the OS keychain (replacing any existing value)
+func Set(account string, secret []byte) error {
+	return storeSecret(account, base64.StdEncoding.EncodeToString(secret))
+}
+
+// GetOrCreateKey returns a random key of the given size from the keychain,
+// generating and storing a new one the first time it is requested
+func GetOrCreateKey(account string, size int) ([]byte, error) {
+	key, err := Get(account)
+	if err == nil {
+		if len(key) != size {
+			return nil, fmt.Errorf("keychain key %q has wrong size: %d (expected %d)", account, len(key), size)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	key = make([]byte, size)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	if err := Set(account, key); err != nil {
+		return nil, fmt.Errorf("failed to store key in keychain: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,50 @@
+//go:build darwin
+
+package keychain
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// loadSecret reads a generic password from the macOS login keychain
+func loadSecret(account string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", ServiceName, "-a", account, "-w").Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 44 {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("security find-generic-password failed: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// storeSecret writes a generic password to the macOS login keychain
+// The command goes to `security -i` on stdin, so the secret never appears in a process listing
+func storeSecret(account, secret string) error {
+	if strings.ContainsAny(account+secret, "\r\n") {
+		return fmt.Errorf("keychain values can't contain line breaks")
+	}
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+		securityQuote(ServiceName), securityQuote(account), securityQuote(secret)))
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("security add-generic-password failed: %v (%s)", err, strings.TrimSpace(string(out)))
+	}
+	// Interactive mode exits 0 even when the command fails, so read the item back
+	stored, err := loadSecret(account)
+	if err != nil {
+		return fmt.Errorf("security add-generic-password failed: %w", err)
+	}
+	if stored != secret {
+		return fmt.Errorf("security add-generic-password failed: item not stored (%s)", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// securityQuote quotes an argument for a `security -i` command line
+func securityQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
@@ -0,0 +1,35 @@
+//go:build linux
+
+package keychain
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// loadSecret reads a secret from the Secret Service (GNOME Keyring/KWallet) via secret-tool
+func loadSecret(account string) (string, error) {
+	out, err := exec.Command("secret-tool", "lookup", "service", ServiceName, "account", account).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 && len(out) == 0 {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("secret-tool lookup failed: %w", err)
+	}
+	secret := strings.TrimSpace(string(out))
+	if secret == "" {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+// storeSecret writes a secret to the Secret Service via secret-tool (secret passed on stdin)
+func storeSecret(account, secret string) error {
+	cmd := exec.Command("secret-tool", "store", "--label="+ServiceName+" "+account, "service", ServiceName, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("secret-tool store failed: %v (%s)", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build !darwin && !linux && !windows
+
+package keychain
+
+import "errors"
+
+var errUnsupported = errors.New("OS keychain is not supported on this platform")
+
+func loadSecret(account string) (string, error) {
+	return "", errUnsupported
+}
+
+func storeSecret(account, secret string) error {
+	return errUnsupported
+}
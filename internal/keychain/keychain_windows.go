@@ -0,0 +1,100 @@
+//go:build windows
+
+package keychain
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+
+	"market-terminal/internal/config"
+)
+
+// On Windows secrets are protected with DPAPI (bound to the current user account)
+// and stored in the config directory - only the same Windows user can decrypt them
+
+var (
+	crypt32                = syscall.NewLazyDLL("crypt32.dll")
+	kernel32               = syscall.NewLazyDLL("kernel32.dll")
+	procCryptProtectData   = crypt32.NewProc("CryptProtectData")
+	procCryptUnprotectData = crypt32.NewProc("CryptUnprotectData")
+	procLocalFree          = kernel32.NewProc("LocalFree")
+)
+
+const cryptProtectUIForbidden = 0x1
+
+type dataBlob struct {
+	cbData uint32
+	pbData *byte
+}
+
+func newBlob(data []byte) *dataBlob {
+	if len(data) == 0 {
+		return &dataBlob{}
+	}
+	return &dataBlob{cbData: uint32(len(data)), pbData: &data[0]}
+}
+
+func (b *dataBlob) bytes() []byte {
+	out := make([]byte, b.cbData)
+	copy(out, unsafe.Slice(b.pbData, b.cbData))
+	return out
+}
+
+// secretPath returns the file a protected secret is stored in
+func secretPath(account string) (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "keys", account+".dpapi"), nil
+}
+
+// loadSecret reads and unprotects a secret
+func loadSecret(account string) (string, error) {
+	path, err := secretPath(account)
+	if err != nil {
+		return "", err
+	}
+	protected, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret: %w", err)
+	}
+
+	var out dataBlob
+	r, _, callErr := procCryptUnprotectData.Call(
+		uintptr(unsafe.Pointer(newBlob(protected))), 0, 0, 0, 0,
+		cryptProtectUIForbidden, uintptr(unsafe.Pointer(&out)))
+	if r == 0 {
+		return "", fmt.Errorf("CryptUnprotectData failed: %v", callErr)
+	}
+	defer procLocalFree.Call(uintptr(unsafe.Pointer(out.pbData)))
+	return string(out.bytes()), nil
+}
+
+// storeSecret protects and writes a secret
+func storeSecret(account, secret string) error {
+	path, err := secretPath(account)
+	if err != nil {
+		return err
+	}
+
+	var out dataBlob
+	r, _, callErr := procCryptProtectData.Call(
+		uintptr(unsafe.Pointer(newBlob([]byte(secret)))), 0, 0, 0, 0,
+		cryptProtectUIForbidden, uintptr(unsafe.Pointer(&out)))
+	if r == 0 {
+		return fmt.Errorf("CryptProtectData failed: %v", callErr)
+	}
+	defer procLocalFree.Call(uintptr(unsafe.Pointer(out.pbData)))
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create key directory: %w", err)
+	}
+	return os.WriteFile(path, out.bytes(), 0600)
+}
@@ -0,0 +1,187 @@
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// Disk space levels
+const (
+	DiskNormal   = "normal"
+	DiskLow      = "low"      // Below the warning threshold: the UI is warned
+	DiskCritical = "critical" // Below the critical threshold: the writer stores essential columns only
+)
+
+// DiskStatus is a snapshot of free space on the data directory's volume
+type DiskStatus struct {
+	Path       string `json:"path"`
+	FreeMB     int64  `json:"free_mb"`
+	TotalMB    int64  `json:"total_mb"`
+	WarnMB     int    `json:"warn_mb"`
+	CriticalMB int    `json:"critical_mb"`
+	Level      string `json:"level"`
+	Error      string `json:"error,omitempty"` // Free space couldn't be read (level stays as it was)
+}
+
+// DiskMonitor watches free space on the data directory's volume
+// When the level changes it runs the registered callbacks (warn the UI, switch the writer's mode)
+type DiskMonitor struct {
+	mu         sync.RWMutex
+	path       string
+	settings   config.DiskSpaceSettings
+	status     DiskStatus
+	onChange   []func(DiskStatus)
+	debugPrint func(string, string)
+
+	stopChan  chan struct{}
+	isRunning bool
+}
+
+// NewDiskMonitor creates a monitor for the volume holding path
+func NewDiskMonitor(path string, settings config.DiskSpaceSettings, debugPrint func(string, string)) *DiskMonitor {
+	return &DiskMonitor{
+		path:       path,
+		settings:   settings,
+		status:     DiskStatus{Path: path, Level: DiskNormal},
+		debugPrint: debugPrint,
+		stopChan:   make(chan struct{}),
+	}
+}
+
+// OnLevelChange registers a callback run when the level changes (in either direction)
+// Callbacks run on the monitor goroutine and should not block
+func (dm *DiskMonitor) OnLevelChange(fn func(DiskStatus)) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.onChange = append(dm.onChange, fn)
+}
+
+// SetPath changes the directory whose volume is checked (applies from the next check)
+func (dm *DiskMonitor) SetPath(path string) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.path = path
+}
+
+// SetSettings changes the thresholds (applies from the next check; the interval from the next start)
+func (dm *DiskMonitor) SetSettings(settings config.DiskSpaceSettings) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.settings = settings
+}
+
+// Start checks now and then periodically
+func (dm *DiskMonitor) Start() {
+	dm.mu.Lock()
+	if dm.isRunning {
+		dm.mu.Unlock()
+		return
+	}
+	dm.isRunning = true
+	interval := time.Duration(dm.settings.CheckIntervalSec) * time.Second
+	dm.mu.Unlock()
+
+	go dm.run(interval)
+	dm.debugPrint(fmt.Sprintf("Disk monitor started (every %s)", interval), "system")
+}
+
+// Stop stops the disk checks
+func (dm *DiskMonitor) Stop() {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	if !dm.isRunning {
+		return
+	}
+	dm.isRunning = false
+	close(dm.stopChan)
+}
+
+// run checks free space every interval
+func (dm *DiskMonitor) run(interval time.Duration) {
+	dm.Check()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			dm.Check()
+		case <-dm.stopChan:
+			return
+		}
+	}
+}
+
+// Check reads free space, updates the level and runs the callbacks if it changed
+func (dm *DiskMonitor) Check() DiskStatus {
+	dm.mu.RLock()
+	path, settings, previous := dm.path, dm.settings, dm.status.Level
+	dm.mu.RUnlock()
+
+	status := DiskStatus{Path: path, WarnMB: settings.WarnMB, CriticalMB: settings.CriticalMB, Level: previous}
+	free, total, err := freeDiskSpace(existingParent(path))
+	if err != nil {
+		status.Error = err.Error()
+	} else {
+		status.FreeMB = int64(free / 1024 / 1024)
+		status.TotalMB = int64(total / 1024 / 1024)
+		switch {
+		case status.FreeMB < int64(settings.CriticalMB):
+			status.Level = DiskCritical
+		case status.FreeMB < int64(settings.WarnMB):
+			status.Level = DiskLow
+		default:
+			status.Level = DiskNormal
+		}
+	}
+
+	dm.mu.Lock()
+	dm.status = status
+	callbacks := make([]func(DiskStatus), len(dm.onChange))
+	copy(callbacks, dm.onChange)
+	dm.mu.Unlock()
+
+	if err != nil {
+		dm.debugPrint(fmt.Sprintf("Disk monitor: failed to read free space for %s: %v", path, err), "error")
+		return status
+	}
+	if status.Level != previous {
+		category := "error"
+		if status.Level == DiskNormal {
+			category = "system"
+		}
+		dm.debugPrint(fmt.Sprintf("Disk space %s: %d MB free of %d MB on %s (warn < %d MB, critical < %d MB)",
+			status.Level, status.FreeMB, status.TotalMB, path, settings.WarnMB, settings.CriticalMB), category)
+		for _, fn := range callbacks {
+			fn(status)
+		}
+	}
+	return status
+}
+
+// Status returns the last disk snapshot
+func (dm *DiskMonitor) Status() DiskStatus {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+	return dm.status
+}
+
+// existingParent returns path or its nearest existing parent (the data directory may not be created yet)
+func existingParent(path string) string {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
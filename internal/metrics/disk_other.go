@@ -0,0 +1,10 @@
+//go:build !windows && !linux && !darwin && !freebsd
+
+package metrics
+
+import "errors"
+
+// freeDiskSpace can't read free space here; the disk monitor reports the error and never changes level
+func freeDiskSpace(path string) (uint64, uint64, error) {
+	return 0, 0, errors.New("disk space detection is not supported on this platform")
+}
@@ -0,0 +1,14 @@
+//go:build linux || darwin || freebsd
+
+package metrics
+
+import "syscall"
+
+// freeDiskSpace returns the bytes available to this user and the volume size for the volume holding path
+func freeDiskSpace(path string) (uint64, uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), uint64(stat.Blocks) * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,201 @@
+package metrics
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// Memory pressure levels
+const (
+	PressureNormal   = "normal"
+	PressureHigh     = "high"     // Approaching the budget: shrink caches, reduce chart loads
+	PressureCritical = "critical" // At or over the budget: also return freed memory to the OS
+)
+
+// MemoryStatus is a snapshot of process memory against the budget
+type MemoryStatus struct {
+	BudgetMB      int     `json:"budget_mb"` // 0 = no budget (monitoring disabled)
+	UsedMB        int     `json:"used_mb"`   // Memory obtained from the OS minus what was released back
+	HeapMB        int     `json:"heap_mb"`
+	UsagePercent  float64 `json:"usage_percent"`
+	Pressure      string  `json:"pressure"`
+	ChartRowLimit int     `json:"chart_row_limit"` // Row limit currently applied to chart loads
+}
+
+// MemoryMonitor watches process memory against a configurable budget
+// When usage approaches the budget it runs the registered relief callbacks (shrink caches,
+// warn the frontend), forces a GC, and lowers the row limit for chart loads
+type MemoryMonitor struct {
+	mu         sync.RWMutex
+	budgetMB   int
+	status     MemoryStatus
+	onPressure []func(MemoryStatus)
+	debugPrint func(string, string)
+
+	stopChan  chan struct{}
+	isRunning bool
+}
+
+// NewMemoryMonitor creates a memory monitor (budgetMB <= 0 disables it)
+func NewMemoryMonitor(budgetMB int, debugPrint func(string, string)) *MemoryMonitor {
+	if budgetMB < 0 {
+		budgetMB = 0
+	}
+	return &MemoryMonitor{
+		budgetMB:   budgetMB,
+		status:     MemoryStatus{BudgetMB: budgetMB, Pressure: PressureNormal, ChartRowLimit: config.ChartMaxRows},
+		debugPrint: debugPrint,
+		stopChan:   make(chan struct{}),
+	}
+}
+
+// OnPressure registers a callback run when pressure rises to high or critical
+// Callbacks run on the monitor goroutine and should not block
+func (mm *MemoryMonitor) OnPressure(fn func(MemoryStatus)) {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	mm.onPressure = append(mm.onPressure, fn)
+}
+
+// SetBudgetMB changes the budget (applies from the next check)
+func (mm *MemoryMonitor) SetBudgetMB(budgetMB int) {
+	if budgetMB < 0 {
+		budgetMB = 0
+	}
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	mm.budgetMB = budgetMB
+}
+
+// Start begins periodic memory checks
+func (mm *MemoryMonitor) Start() {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+
+	if mm.isRunning {
+		return
+	}
+	mm.isRunning = true
+
+	go mm.run()
+	mm.debugPrint(fmt.Sprintf("Memory monitor started (budget %d MB)", mm.budgetMB), "system")
+}
+
+// Stop stops the memory checks
+func (mm *MemoryMonitor) Stop() {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+
+	if !mm.isRunning {
+		return
+	}
+	mm.isRunning = false
+	close(mm.stopChan)
+}
+
+// run checks memory every MemoryCheckIntervalSec
+func (mm *MemoryMonitor) run() {
+	ticker := time.NewTicker(time.Duration(config.MemoryCheckIntervalSec) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			mm.Check()
+		case <-mm.stopChan:
+			return
+		}
+	}
+}
+
+// Check samples memory usage, updates the pressure level and applies relief if needed
+// Also called before large chart loads so the row limit reflects current usage
+func (mm *MemoryMonitor) Check() MemoryStatus {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	mm.mu.Lock()
+	budgetMB := mm.budgetMB
+	previous := mm.status.Pressure
+	status := MemoryStatus{
+		BudgetMB: budgetMB,
+		UsedMB:   int((m.Sys - m.HeapReleased) / 1024 / 1024),
+		HeapMB:   int(m.HeapAlloc / 1024 / 1024),
+		Pressure: PressureNormal,
+	}
+	if budgetMB > 0 {
+		status.UsagePercent = float64(status.UsedMB) / float64(budgetMB) * 100
+		if status.UsagePercent >= config.MemoryCriticalPercent {
+			status.Pressure = PressureCritical
+		} else if status.UsagePercent >= config.MemoryHighPercent {
+			status.Pressure = PressureHigh
+		}
+	}
+	status.ChartRowLimit = chartRowLimit(status.Pressure)
+	mm.status = status
+	callbacks := make([]func(MemoryStatus), len(mm.onPressure))
+	copy(callbacks, mm.onPressure)
+	mm.mu.Unlock()
+
+	if status.Pressure == PressureNormal {
+		if previous != PressureNormal {
+			mm.debugPrint(fmt.Sprintf("Memory pressure cleared: %d MB of %d MB", status.UsedMB, budgetMB), "memory")
+		}
+		return status
+	}
+
+	// Relieve pressure: GC every check while above the threshold, callbacks only when pressure rises
+	if status.Pressure == PressureCritical {
+		debug.FreeOSMemory() // Forces a GC and returns freed pages to the OS
+	} else {
+		runtime.GC()
+	}
+	if pressureRank(status.Pressure) > pressureRank(previous) {
+		mm.debugPrint(fmt.Sprintf("Memory pressure %s: %d MB of %d MB budget (%.0f%%) - chart loads limited to %d rows",
+			status.Pressure, status.UsedMB, budgetMB, status.UsagePercent, status.ChartRowLimit), "error")
+		for _, fn := range callbacks {
+			fn(status)
+		}
+	}
+	return status
+}
+
+// Status returns the last memory snapshot
+func (mm *MemoryMonitor) Status() MemoryStatus {
+	mm.mu.RLock()
+	defer mm.mu.RUnlock()
+	return mm.status
+}
+
+// ChartRowLimit returns the row limit for chart loads at the current pressure
+func (mm *MemoryMonitor) ChartRowLimit() int {
+	return mm.Status().ChartRowLimit
+}
+
+// chartRowLimit scales the chart row limit down as pressure rises
+func chartRowLimit(pressure string) int {
+	switch pressure {
+	case PressureCritical:
+		return config.ChartMaxRows / 4
+	case PressureHigh:
+		return config.ChartMaxRows / 2
+	default:
+		return config.ChartMaxRows
+	}
+}
+
+func pressureRank(pressure string) int {
+	switch pressure {
+	case PressureCritical:
+		return 2
+	case PressureHigh:
+		return 1
+	default:
+		return 0
+	}
+}
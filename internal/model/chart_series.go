@@ -0,0 +1,86 @@
+package model
+
+import (
+	"encoding/json"
+	"math"
+
+	"market-terminal/internal/utils"
+)
+
+// ChartSeries is chart data for one ticker: timestamps and columns aligned with them. Missing values are NaN
+// It marshals to the frontend's layout: {"timestamp": [...], "<column>": [... null ...], "timezone": {...}}
+type ChartSeries struct {
+	Timestamps []float64
+	Columns    map[string][]float64     // Column -> values (empty = requested but not recorded)
+	Timezone   *utils.ChartTimezoneInfo // Axis label timezone (nil unless requested)
+}
+
+// NewChartSeries returns a series with the given timestamps and no columns
+func NewChartSeries(timestamps []float64) *ChartSeries {
+	return &ChartSeries{Timestamps: timestamps, Columns: make(map[string][]float64)}
+}
+
+// ChartSeriesFromColumns converts loaded columns (column -> numbers/nil, including "timestamp")
+// Non-numeric values become NaN
+func ChartSeriesFromColumns(data map[string][]interface{}) *ChartSeries {
+	timestamps := toFloats(data["timestamp"])
+	series := NewChartSeries(timestamps)
+	for name, values := range data {
+		if name != "timestamp" {
+			series.Columns[name] = toFloats(values)
+		}
+	}
+	return series
+}
+
+// Len is the number of rows
+func (s *ChartSeries) Len() int {
+	return len(s.Timestamps)
+}
+
+// Column returns a column's values (nil if the series doesn't have it)
+func (s *ChartSeries) Column(name string) []float64 {
+	return s.Columns[name]
+}
+
+// SetColumn adds or replaces a column
+func (s *ChartSeries) SetColumn(name string, values []float64) {
+	s.Columns[name] = values
+}
+
+// MarshalJSON writes the series as column arrays keyed by name, NaN as null
+func (s *ChartSeries) MarshalJSON() ([]byte, error) {
+	out := make(map[string]interface{}, len(s.Columns)+2)
+	out["timestamp"] = NullableFloats(s.Timestamps)
+	for name, values := range s.Columns {
+		out[name] = NullableFloats(values)
+	}
+	if s.Timezone != nil {
+		out["timezone"] = s.Timezone
+	}
+	return json.Marshal(out)
+}
+
+// toFloats converts a loaded column, nil and non-numeric values to NaN
+func toFloats(values []interface{}) []float64 {
+	floats := make([]float64, len(values))
+	for i, value := range values {
+		if number, ok := Number(value); ok {
+			floats[i] = number
+		} else {
+			floats[i] = math.NaN()
+		}
+	}
+	return floats
+}
+
+// NullableFloats converts NaN to nil so a column can be encoded as JSON
+func NullableFloats(values []float64) []interface{} {
+	out := make([]interface{}, len(values))
+	for i, value := range values {
+		if !math.IsNaN(value) && !math.IsInf(value, 0) {
+			out[i] = value
+		}
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+package model
+
+import "encoding/json"
+
+// ProfileLevel is one strike of a profile: [strike, value, value, ..., extras...] in the API's layout
+type ProfileLevel struct {
+	Strike float64
+	Values []float64     // Numeric values after the strike
+	Extra  []interface{} // Anything after the numeric values (e.g. nested prior-value arrays), kept as-is
+}
+
+// ProfileLadder is the profiles of one stored row: each strike ladder parsed into levels
+// Profiles that aren't ladders (objects, arrays of non-numeric rows) stay in Other
+type ProfileLadder struct {
+	Ticker    string
+	Timestamp float64 // Timestamp of the row the profiles came from
+	Ladders   map[string][]ProfileLevel
+	Other     map[string]interface{}
+}
+
+// ProfileLadderFromProfiles parses decoded profiles (profile name -> JSON value)
+func ProfileLadderFromProfiles(ticker string, timestamp float64, profiles map[string]interface{}) *ProfileLadder {
+	ladder := &ProfileLadder{
+		Ticker:    ticker,
+		Timestamp: timestamp,
+		Ladders:   make(map[string][]ProfileLevel),
+		Other:     make(map[string]interface{}),
+	}
+	for name, value := range profiles {
+		if levels, ok := parseLevels(value); ok {
+			ladder.Ladders[name] = levels
+		} else {
+			ladder.Other[name] = value
+		}
+	}
+	return ladder
+}
+
+// parseLevels parses an array of [strike, values...] rows; false if any row doesn't start with a number
+func parseLevels(value interface{}) ([]ProfileLevel, bool) {
+	rows, ok := value.([]interface{})
+	if !ok || len(rows) == 0 {
+		return nil, false
+	}
+	levels := make([]ProfileLevel, 0, len(rows))
+	for _, row := range rows {
+		cells, ok := row.([]interface{})
+		if !ok || len(cells) == 0 {
+			return nil, false
+		}
+		strike, ok := cells[0].(float64)
+		if !ok {
+			return nil, false
+		}
+		level := ProfileLevel{Strike: strike}
+		i := 1
+		for ; i < len(cells); i++ {
+			number, ok := cells[i].(float64)
+			if !ok {
+				break
+			}
+			level.Values = append(level.Values, number)
+		}
+		if i < len(cells) {
+			level.Extra = cells[i:]
+		}
+		levels = append(levels, level)
+	}
+	return levels, true
+}
+
+// MarshalJSON writes the level as the API's row array
+func (l ProfileLevel) MarshalJSON() ([]byte, error) {
+	row := make([]interface{}, 0, 1+len(l.Values)+len(l.Extra))
+	row = append(row, l.Strike)
+	for _, value := range l.Values {
+		row = append(row, value)
+	}
+	row = append(row, l.Extra...)
+	return json.Marshal(row)
+}
+
+// MarshalJSON writes the profiles keyed by name, in the layout the API sent them
+func (p *ProfileLadder) MarshalJSON() ([]byte, error) {
+	out := make(map[string]interface{}, len(p.Ladders)+len(p.Other))
+	for name, value := range p.Other {
+		out[name] = value
+	}
+	for name, levels := range p.Ladders {
+		out[name] = levels
+	}
+	return json.Marshal(out)
+}
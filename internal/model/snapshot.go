@@ -0,0 +1,75 @@
+// Package model holds typed data at the edges of the pipeline: an endpoint response in canonical form
+// (TickerSnapshot, built by the API client), and the chart columns (ChartSeries) and row profiles (ProfileLadder)
+// the loader hands the bindings. Each marshals to the JSON shape the frontend already reads
+// Between the API client and the writer, rows stay maps: processors, scripts and alert rules add fields to them
+package model
+
+import "math"
+
+// TickerSnapshot is one endpoint response in canonical form: Unix-second timestamp, float64 scalars, profiles and
+// text kept apart. Built by api.Normalize
+type TickerSnapshot struct {
+	Endpoint  string                 `json:"endpoint,omitempty"`
+	Ticker    string                 `json:"ticker"`
+	Timestamp float64                `json:"timestamp"` // API time in Unix seconds (0 = the response had none)
+	Scalars   map[string]float64     `json:"scalars"`
+	Profiles  map[string]interface{} `json:"profiles"`
+	Text      map[string]string      `json:"text,omitempty"`
+	Dropped   []string               `json:"dropped,omitempty"` // Fields whose value didn't fit their kind, sorted
+}
+
+// NewTickerSnapshot returns an empty snapshot for an endpoint's response
+func NewTickerSnapshot(endpoint, ticker string) *TickerSnapshot {
+	return &TickerSnapshot{
+		Endpoint: endpoint,
+		Ticker:   ticker,
+		Scalars:  make(map[string]float64),
+		Profiles: make(map[string]interface{}),
+		Text:     make(map[string]string),
+	}
+}
+
+// Row returns the snapshot as the row map the coordinator merges, processors and alert rules read and the
+// writer stores
+func (s *TickerSnapshot) Row() map[string]interface{} {
+	row := make(map[string]interface{}, len(s.Scalars)+len(s.Profiles)+len(s.Text)+1)
+	for field, value := range s.Scalars {
+		row[field] = value
+	}
+	for field, value := range s.Profiles {
+		row[field] = value
+	}
+	for field, value := range s.Text {
+		row[field] = value
+	}
+	if s.Timestamp > 0 {
+		row["timestamp"] = s.Timestamp
+	}
+	return row
+}
+
+// Number converts a Go numeric value (as found in rows: float64 from JSON, int quality flags) to float64
+// NaN and infinities are rejected
+func Number(value interface{}) (float64, bool) {
+	var number float64
+	switch v := value.(type) {
+	case float64:
+		number = v
+	case float32:
+		number = float64(v)
+	case int:
+		number = float64(v)
+	case int64:
+		number = float64(v)
+	case bool:
+		if v {
+			number = 1
+		}
+	default:
+		return 0, false
+	}
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, false
+	}
+	return number, true
+}
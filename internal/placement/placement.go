@@ -0,0 +1,177 @@
+package placement
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"market-terminal/internal/config"
+)
+
+// Rect is a window or screen area in desktop coordinates
+type Rect struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// Monitor is one attached screen
+type Monitor struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Bounds      Rect    `json:"bounds"`
+	WorkArea    Rect    `json:"work_area"` // Bounds minus the taskbar/dock
+	ScaleFactor float32 `json:"scale_factor"`
+	Primary     bool    `json:"primary"`
+}
+
+// Manager remembers window geometry per monitor configuration and keeps restored windows on a screen
+// screens is asked for the attached monitors on every call, so docking changes are picked up without events
+type Manager struct {
+	screens func() []Monitor
+}
+
+// NewManager creates a manager; screens may return nil when the screen list isn't available (nothing is clamped)
+func NewManager(screens func() []Monitor) *Manager {
+	return &Manager{screens: screens}
+}
+
+// Monitors returns the attached monitors
+func (m *Manager) Monitors() []Monitor {
+	if m == nil || m.screens == nil {
+		return nil
+	}
+	return m.screens()
+}
+
+// ConfigurationKey identifies a set of monitors by their IDs, bounds and DPI scale (order-independent)
+func ConfigurationKey(monitors []Monitor) string {
+	parts := make([]string, 0, len(monitors))
+	for _, monitor := range monitors {
+		b := monitor.Bounds
+		parts = append(parts, fmt.Sprintf("%s=%dx%d%+d%+d@%g", monitor.ID, b.Width, b.Height, b.X, b.Y, monitor.ScaleFactor))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ";")
+}
+
+// Restore returns the window geometry saved for the current monitor configuration, moved onto a screen if
+// needed; false when the configuration hasn't been seen (the caller uses its default size and position)
+func (m *Manager) Restore(placements []config.WindowPlacement) (Rect, bool) {
+	monitors := m.Monitors()
+	if len(monitors) == 0 {
+		return Rect{}, false
+	}
+	key := ConfigurationKey(monitors)
+	for _, p := range placements {
+		if p.Monitors == key {
+			return Clamp(Rect{X: p.X, Y: p.Y, Width: p.Width, Height: p.Height}, monitors), true
+		}
+	}
+	return Rect{}, false
+}
+
+// Record returns placements with the window's geometry stored for the current monitor configuration
+// (moved to the front; the least recently used configurations beyond MaxWindowPlacements are dropped)
+func (m *Manager) Record(placements []config.WindowPlacement, window Rect) []config.WindowPlacement {
+	monitors := m.Monitors()
+	if len(monitors) == 0 {
+		return placements
+	}
+	p := config.WindowPlacement{
+		Monitors: ConfigurationKey(monitors),
+		X:        window.X,
+		Y:        window.Y,
+		Width:    window.Width,
+		Height:   window.Height,
+	}
+	if screen := screenOf(window, monitors); screen != nil {
+		p.Screen = screen.ID
+		p.ScaleFactor = screen.ScaleFactor
+	}
+
+	updated := []config.WindowPlacement{p}
+	for _, existing := range placements {
+		if existing.Monitors != p.Monitors && len(updated) < config.MaxWindowPlacements {
+			updated = append(updated, existing)
+		}
+	}
+	return updated
+}
+
+// Clamp keeps a window on the current monitors (see the package-level Clamp)
+func (m *Manager) Clamp(window Rect) Rect {
+	return Clamp(window, m.Monitors())
+}
+
+// Clamp returns the window unchanged while its title bar can still be grabbed on some screen; otherwise
+// it is shrunk to fit and moved inside the work area of the screen it overlaps most (the primary if none)
+func Clamp(window Rect, monitors []Monitor) Rect {
+	if len(monitors) == 0 {
+		return window
+	}
+	titleBar := Rect{X: window.X, Y: window.Y, Width: window.Width, Height: config.WindowTitleBarHeight}
+	for _, monitor := range monitors {
+		visible := intersect(titleBar, monitor.WorkArea)
+		if visible.Width >= config.WindowMinVisibleWidth && visible.Height >= config.WindowTitleBarHeight {
+			return window
+		}
+	}
+
+	target := screenOf(window, monitors)
+	if target == nil {
+		target = primary(monitors)
+	}
+	area := target.WorkArea
+	if window.Width > area.Width {
+		window.Width = area.Width
+	}
+	if window.Height > area.Height {
+		window.Height = area.Height
+	}
+	window.X = clampInt(window.X, area.X, area.X+area.Width-window.Width)
+	window.Y = clampInt(window.Y, area.Y, area.Y+area.Height-window.Height)
+	return window
+}
+
+// screenOf returns the monitor the window overlaps most, or nil when it is on none
+func screenOf(window Rect, monitors []Monitor) *Monitor {
+	var best *Monitor
+	bestArea := 0
+	for i := range monitors {
+		overlap := intersect(window, monitors[i].Bounds)
+		if area := overlap.Width * overlap.Height; area > bestArea {
+			best, bestArea = &monitors[i], area
+		}
+	}
+	return best
+}
+
+func primary(monitors []Monitor) *Monitor {
+	for i := range monitors {
+		if monitors[i].Primary {
+			return &monitors[i]
+		}
+	}
+	return &monitors[0]
+}
+
+func intersect(a, b Rect) Rect {
+	x1, y1 := max(a.X, b.X), max(a.Y, b.Y)
+	x2, y2 := min(a.X+a.Width, b.X+b.Width), min(a.Y+a.Height, b.Y+b.Height)
+	if x2 <= x1 || y2 <= y1 {
+		return Rect{}
+	}
+	return Rect{X: x1, Y: y1, Width: x2 - x1, Height: y2 - y1}
+}
+
+func clampInt(v, lo, hi int) int {
+	if v > hi {
+		v = hi
+	}
+	if v < lo {
+		v = lo
+	}
+	return v
+}
@@ -0,0 +1,56 @@
+package processors
+
+import (
+	"fmt"
+	"sync"
+)
+
+func init() {
+	Register("gamma_range", func() Processor { return &gammaRange{inside: make(map[string]bool)} })
+}
+
+// gammaRange measures spot within the range between the major negative and positive gamma levels:
+// gamma_range (major_pos_vol - major_neg_vol) and gamma_range_pos (0 at major_neg_vol, 1 at major_pos_vol),
+// with a range_exit / range_enter event when spot leaves or re-enters the range
+type gammaRange struct {
+	mu     sync.Mutex
+	inside map[string]bool // ticker -> spot was inside the range on the last row
+}
+
+func (g *gammaRange) Name() string { return "gamma_range" }
+
+func (g *gammaRange) OnTickerData(ticker string, data map[string]interface{}) (Result, error) {
+	spot, _ := data["spot"].(float64)
+	pos, _ := data["major_pos_vol"].(float64)
+	neg, _ := data["major_neg_vol"].(float64)
+	low, high := neg, pos
+	if low > high {
+		low, high = high, low
+	}
+	if spot <= 0 || low <= 0 || high <= low {
+		return Result{}, nil
+	}
+
+	result := Result{Columns: map[string]float64{
+		"gamma_range":     high - low,
+		"gamma_range_pos": (spot - neg) / (pos - neg),
+	}}
+
+	inside := spot >= low && spot <= high
+	g.mu.Lock()
+	wasInside, seen := g.inside[ticker]
+	g.inside[ticker] = inside
+	g.mu.Unlock()
+	if seen && inside != wasInside {
+		name, message := "range_exit", fmt.Sprintf("%s spot %.2f left the gamma range %.2f-%.2f", ticker, spot, low, high)
+		if inside {
+			name, message = "range_enter", fmt.Sprintf("%s spot %.2f is back inside the gamma range %.2f-%.2f", ticker, spot, low, high)
+		}
+		result.Events = append(result.Events, Event{
+			Name:    name,
+			Message: message,
+			Data:    map[string]interface{}{"spot": spot, "low": low, "high": high},
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,77 @@
+// Package processors lets custom code compute metrics from each collected row: a Processor sees the merged
+// endpoint payload (scalars and profiles) before it is stored and returns extra columns and events. Processors
+// register by name in init and are enabled in settings
+package processors
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// Processor computes extra columns and events from a ticker's row as it is collected
+// data is the merged payload (scalar fields and profiles) and must not be modified; OnTickerData is called from
+// the collection path, so it should return quickly
+type Processor interface {
+	Name() string
+	OnTickerData(ticker string, data map[string]interface{}) (Result, error)
+}
+
+// Result is what a processor adds to a row
+type Result struct {
+	Columns map[string]float64 // Stored with the row (a column may not replace a collected field)
+	Events  []Event            // Passed to the UI as processor:event
+}
+
+// Event is a notable moment a processor reports (e.g. "spread widened")
+type Event struct {
+	Name    string                 `json:"name"`
+	Message string                 `json:"message,omitempty"`
+	Data    map[string]interface{} `json:"data,omitempty"`
+}
+
+// Factory creates a processor (called once per enabled processor when settings are applied)
+type Factory func() Processor
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// namePattern keeps processor names usable as settings keys and log labels
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+
+// Register adds a processor under a name (lowercase letters, digits and _); registering a name twice panics,
+// so two plugins can't silently shadow each other
+func Register(name string, factory Factory) {
+	if !namePattern.MatchString(name) {
+		panic(fmt.Sprintf("processors: invalid name %q", name))
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("processors: %q registered twice", name))
+	}
+	registry[name] = factory
+}
+
+// Lookup returns the factory of a registered processor
+func Lookup(name string) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	factory, ok := registry[name]
+	return factory, ok
+}
+
+// Names returns the registered processor names, sorted
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
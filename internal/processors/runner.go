@@ -0,0 +1,177 @@
+package processors
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Status is a processor's activity since it was enabled
+type Status struct {
+	Name       string  `json:"name"`
+	Enabled    bool    `json:"enabled"`
+	Calls      int64   `json:"calls"`
+	Errors     int64   `json:"errors"`  // Calls that returned an error or panicked
+	Columns    int64   `json:"columns"` // Columns added to rows
+	Events     int64   `json:"events"`
+	AvgMs      float64 `json:"avg_ms"` // Average time per call
+	MaxMs      float64 `json:"max_ms"`
+	LastError  string  `json:"last_error"`
+	LastCallAt float64 `json:"last_call_at"` // Unix seconds (0 = never called)
+}
+
+// enabledProcessor is a running processor and its tickers filter
+type enabledProcessor struct {
+	processor Processor
+	tickers   map[string]bool // nil = every ticker
+	status    Status
+	totalMs   float64
+}
+
+// Runner runs the enabled processors on each collected row
+// A failing or panicking processor only loses its own output for that row; the row is stored either way
+type Runner struct {
+	mu         sync.Mutex
+	enabled    []*enabledProcessor
+	onEvent    func(processor, ticker string, event Event)
+	debugPrint func(string, string)
+}
+
+// NewRunner creates a runner with no processors enabled
+func NewRunner(debugPrint func(string, string)) *Runner {
+	return &Runner{debugPrint: debugPrint}
+}
+
+// SetEnabled replaces the enabled processors (by registered name); tickers limits a processor to some tickers
+// (missing or empty = every ticker). A processor that stays enabled keeps its instance and counters
+func (r *Runner) SetEnabled(names []string, tickers map[string][]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing := make(map[string]*enabledProcessor, len(r.enabled))
+	for _, p := range r.enabled {
+		existing[p.status.Name] = p
+	}
+	enabled := make([]*enabledProcessor, 0, len(names))
+	var unknown []string
+	for _, name := range names {
+		p := existing[name]
+		if p == nil {
+			factory, ok := Lookup(name)
+			if !ok {
+				unknown = append(unknown, name)
+				continue
+			}
+			p = &enabledProcessor{processor: factory(), status: Status{Name: name, Enabled: true}}
+		}
+		p.tickers = nil
+		if list := tickers[name]; len(list) > 0 {
+			p.tickers = make(map[string]bool, len(list))
+			for _, ticker := range list {
+				p.tickers[ticker] = true
+			}
+		}
+		enabled = append(enabled, p)
+	}
+	r.enabled = enabled
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown processors %v (registered: %v)", unknown, Names())
+	}
+	return nil
+}
+
+// SetOnEvent sets the callback for events reported by processors
+func (r *Runner) SetOnEvent(onEvent func(processor, ticker string, event Event)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onEvent = onEvent
+}
+
+// Process runs the enabled processors on a row and adds their columns to it
+// Processors run in the configured order and see the columns added by those before them
+func (r *Runner) Process(ticker string, data map[string]interface{}) {
+	r.mu.Lock()
+	if len(r.enabled) == 0 {
+		r.mu.Unlock()
+		return
+	}
+	enabled := append([]*enabledProcessor(nil), r.enabled...)
+	onEvent := r.onEvent
+	r.mu.Unlock()
+
+	for _, p := range enabled {
+		if p.tickers != nil && !p.tickers[ticker] {
+			continue
+		}
+		start := time.Now()
+		result, err := r.call(p.processor, ticker, data)
+		elapsedMs := float64(time.Since(start).Microseconds()) / 1000
+
+		added := int64(0)
+		if err == nil {
+			for column, value := range result.Columns {
+				if _, collected := data[column]; collected || math.IsNaN(value) || math.IsInf(value, 0) {
+					continue
+				}
+				data[column] = value
+				added++
+			}
+		}
+
+		r.mu.Lock()
+		p.status.Calls++
+		p.status.Columns += added
+		p.status.Events += int64(len(result.Events))
+		p.status.LastCallAt = float64(start.UnixMilli()) / 1000
+		p.totalMs += elapsedMs
+		p.status.AvgMs = p.totalMs / float64(p.status.Calls)
+		if elapsedMs > p.status.MaxMs {
+			p.status.MaxMs = elapsedMs
+		}
+		if err != nil {
+			p.status.Errors++
+			p.status.LastError = err.Error()
+		}
+		r.mu.Unlock()
+
+		if err != nil {
+			r.debugPrint(fmt.Sprintf("Processor %s failed for %s: %v", p.status.Name, ticker, err), "error")
+			continue
+		}
+		if onEvent != nil {
+			for _, event := range result.Events {
+				onEvent(p.status.Name, ticker, event)
+			}
+		}
+	}
+}
+
+// call runs one processor, turning a panic into an error
+func (r *Runner) call(processor Processor, ticker string, data map[string]interface{}) (result Result, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result, err = Result{}, fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return processor.OnTickerData(ticker, data)
+}
+
+// Status returns every registered processor's status (counters only for enabled ones)
+func (r *Runner) Status() []Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	enabled := make(map[string]Status, len(r.enabled))
+	for _, p := range r.enabled {
+		enabled[p.status.Name] = p.status
+	}
+	statuses := make([]Status, 0)
+	for _, name := range Names() {
+		status, ok := enabled[name]
+		if !ok {
+			status = Status{Name: name}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
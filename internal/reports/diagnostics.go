@@ -0,0 +1,153 @@
+package reports
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DiagnosticsBundle is everything collected for a support zip
+// Secrets must already be stripped from Settings; samples are obfuscated by the caller if requested
+type DiagnosticsBundle struct {
+	Manifest     map[string]interface{}              // App version, platform, export time, options
+	Settings     []byte                              // settings.yaml without credentials
+	Status       map[string]interface{}              // Health, rate limit, memory, circuit breakers, ...
+	RecentLogs   []string                            // In-memory log lines (newest last)
+	LogFile      string                              // Current log file; the tail is included ("" = skip)
+	LogTailBytes int64                               // How much of LogFile to include
+	Samples      map[string][]map[string]interface{} // Ticker -> latest rows
+	CrashReports []string                            // Crash dump files to include
+}
+
+// ObfuscateSamples scales every numeric value except timestamps by a random factor per ticker
+// Ratios and shapes within a ticker are preserved, so the data stays useful for debugging
+// without revealing actual levels
+func ObfuscateSamples(samples map[string][]map[string]interface{}) {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for _, rows := range samples {
+		factor := 0.5 + rng.Float64() // 0.5-1.5
+		for _, row := range rows {
+			for col, value := range row {
+				if col == "timestamp" {
+					continue
+				}
+				switch v := value.(type) {
+				case float64:
+					row[col] = v * factor
+				case int64:
+					row[col] = float64(v) * factor
+				}
+			}
+		}
+	}
+}
+
+// WriteDiagnosticsBundle writes the bundle as a zip archive at path
+func WriteDiagnosticsBundle(path string, bundle DiagnosticsBundle) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create diagnostics file: %w", err)
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	if err := writeDiagnosticsEntries(archive, bundle); err != nil {
+		archive.Close()
+		return err
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to finish diagnostics archive: %w", err)
+	}
+	return file.Close()
+}
+
+func writeDiagnosticsEntries(archive *zip.Writer, bundle DiagnosticsBundle) error {
+	if err := writeZipJSON(archive, "manifest.json", bundle.Manifest); err != nil {
+		return err
+	}
+	if err := writeZipFile(archive, "settings.yaml", bundle.Settings); err != nil {
+		return err
+	}
+	if err := writeZipJSON(archive, "status.json", bundle.Status); err != nil {
+		return err
+	}
+	if err := writeZipFile(archive, "logs/recent.log", []byte(strings.Join(bundle.RecentLogs, "\n")+"\n")); err != nil {
+		return err
+	}
+	if bundle.LogFile != "" {
+		tail, err := readFileTail(bundle.LogFile, bundle.LogTailBytes)
+		if err == nil {
+			if err := writeZipFile(archive, "logs/"+filepath.Base(bundle.LogFile), tail); err != nil {
+				return err
+			}
+		}
+	}
+
+	tickers := make([]string, 0, len(bundle.Samples))
+	for ticker := range bundle.Samples {
+		tickers = append(tickers, ticker)
+	}
+	sort.Strings(tickers)
+	for _, ticker := range tickers {
+		if err := writeZipJSON(archive, "samples/"+ticker+".json", bundle.Samples[ticker]); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range bundle.CrashReports {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue // Pruned since it was listed
+		}
+		if err := writeZipFile(archive, "crashes/"+filepath.Base(path), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeZipFile(archive *zip.Writer, name string, data []byte) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func writeZipJSON(archive *zip.Writer, name string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	return writeZipFile(archive, name, data)
+}
+
+// readFileTail returns the last maxBytes of a file (the whole file if it is smaller)
+func readFileTail(path string, maxBytes int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxBytes {
+		if _, err := file.Seek(info.Size()-maxBytes, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+	return io.ReadAll(file)
+}
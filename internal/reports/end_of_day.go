@@ -0,0 +1,156 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/database"
+)
+
+// DayReport is the end-of-day collection report for all tickers on a market date
+type DayReport struct {
+	Date           string                   `json:"date"`
+	GeneratedAt    string                   `json:"generated_at"`
+	TickerCount    int                      `json:"ticker_count"`
+	TotalRows      int                      `json:"total_rows"`
+	TotalAPIErrors int                      `json:"total_api_errors"`
+	Tickers        []*database.TickerReport `json:"tickers"`
+}
+
+// EndOfDayReporter generates, saves and optionally webhooks end-of-day collection reports
+type EndOfDayReporter struct {
+	dataLoader     *database.DataLoader
+	getSettings    func() *config.Settings
+	getErrorCounts func(dateStr string) map[string]int
+	httpClient     *http.Client
+	debugPrint     func(string, string)
+}
+
+// NewEndOfDayReporter creates a new end-of-day reporter
+// getErrorCounts returns API error counts per ticker for a market date ("2006-01-02")
+func NewEndOfDayReporter(
+	dataLoader *database.DataLoader,
+	getSettings func() *config.Settings,
+	getErrorCounts func(dateStr string) map[string]int,
+	debugPrint func(string, string),
+) *EndOfDayReporter {
+	return &EndOfDayReporter{
+		dataLoader:     dataLoader,
+		getSettings:    getSettings,
+		getErrorCounts: getErrorCounts,
+		httpClient:     &http.Client{Timeout: time.Duration(config.EndOfDayReportWebhookTimeoutSec) * time.Second},
+		debugPrint:     debugPrint,
+	}
+}
+
+// Generate builds the report for the given tickers on a market date
+// Tickers that fail to load are logged and included with only their path filled in
+func (r *EndOfDayReporter) Generate(marketDate time.Time, tickers []string) *DayReport {
+	dateStr := marketDate.Format("2006-01-02")
+	errorCounts := map[string]int{}
+	if r.getErrorCounts != nil {
+		errorCounts = r.getErrorCounts(dateStr)
+	}
+
+	sorted := make([]string, len(tickers))
+	copy(sorted, tickers)
+	sort.Strings(sorted)
+
+	report := &DayReport{
+		Date:        dateStr,
+		GeneratedAt: time.Now().Format(time.RFC3339),
+		TickerCount: len(sorted),
+		Tickers:     make([]*database.TickerReport, 0, len(sorted)),
+	}
+
+	for _, ticker := range sorted {
+		tickerReport, err := r.dataLoader.BuildTickerReport(ticker, marketDate, config.EndOfDayReportGapThresholdSec)
+		if err != nil {
+			r.debugPrint(fmt.Sprintf("EndOfDayReporter: Failed to build report for %s: %v", ticker, err), "error")
+			tickerReport = &database.TickerReport{Ticker: ticker, LevelRanges: map[string]database.LevelRange{}}
+		}
+		tickerReport.APIErrorCount = errorCounts[ticker]
+
+		report.TotalRows += tickerReport.RowCount
+		report.TotalAPIErrors += tickerReport.APIErrorCount
+		report.Tickers = append(report.Tickers, tickerReport)
+	}
+
+	return report
+}
+
+// ReportPath returns where the report for a market date is saved
+// Saved next to the day's data directory: "Tickers 01.14.2026 report.json" ("Tickers/2026/01/14 report.json" in the iso layout)
+func (r *EndOfDayReporter) ReportPath(marketDate time.Time) string {
+	return r.getSettings().DayDirectory(marketDate) + " report.json"
+}
+
+// Save writes the report as indented JSON and returns the path
+func (r *EndOfDayReporter) Save(marketDate time.Time, report *DayReport) (string, error) {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	path := r.ReportPath(marketDate)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write report: %w", err)
+	}
+
+	return path, nil
+}
+
+// SendWebhook POSTs the report as JSON to the configured webhook URL
+// Does nothing if no webhook URL is configured
+func (r *EndOfDayReporter) SendWebhook(report *DayReport) error {
+	url := r.getSettings().EndOfDayReportWebhookURL
+	if url == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	resp, err := r.httpClient.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Run generates, saves and webhooks the report for a market date
+// Intended to be registered with the market close watcher
+func (r *EndOfDayReporter) Run(marketDate time.Time, tickers []string) {
+	if !r.getSettings().EndOfDayReportEnabled {
+		r.debugPrint("EndOfDayReporter: End-of-day report disabled in settings", "system")
+		return
+	}
+
+	report := r.Generate(marketDate, tickers)
+
+	path, err := r.Save(marketDate, report)
+	if err != nil {
+		r.debugPrint(fmt.Sprintf("EndOfDayReporter: Failed to save report: %v", err), "error")
+	} else {
+		r.debugPrint(fmt.Sprintf("EndOfDayReporter: Saved report for %s (%d tickers, %d rows, %d API errors): %s",
+			report.Date, report.TickerCount, report.TotalRows, report.TotalAPIErrors, path), "system")
+	}
+
+	if err := r.SendWebhook(report); err != nil {
+		r.debugPrint(fmt.Sprintf("EndOfDayReporter: Failed to send webhook: %v", err), "error")
+	}
+}
@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// ActivityMonitor detects when the user hasn't focused any app window for a while
+// While idle, the scheduler demotes every ticker to the low (collection-only) interval
+type ActivityMonitor struct {
+	mu           sync.Mutex
+	lastActivity time.Time
+	idle         bool
+	idleAfter    time.Duration // 0 = never go idle
+	isFocused    func() bool   // Reports whether any app window currently has focus
+	onChange     func(idle bool)
+	debugPrint   func(string, string)
+	stopChan     chan struct{}
+	isRunning    bool
+}
+
+// NewActivityMonitor creates an activity monitor (idleAfterMinutes <= 0 disables idle detection)
+func NewActivityMonitor(idleAfterMinutes int, isFocused func() bool, onChange func(idle bool), debugPrint func(string, string)) *ActivityMonitor {
+	return &ActivityMonitor{
+		lastActivity: time.Now(),
+		idleAfter:    time.Duration(idleAfterMinutes) * time.Minute,
+		isFocused:    isFocused,
+		onChange:     onChange,
+		debugPrint:   debugPrint,
+	}
+}
+
+// Start begins periodic idle checks
+func (am *ActivityMonitor) Start() {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	if am.isRunning {
+		return
+	}
+	am.isRunning = true
+	am.stopChan = make(chan struct{})
+	am.lastActivity = time.Now()
+
+	go func(stop chan struct{}) {
+		ticker := time.NewTicker(time.Duration(config.ActivityCheckIntervalSec) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				am.check()
+			}
+		}
+	}(am.stopChan)
+}
+
+// Stop stops idle checks
+func (am *ActivityMonitor) Stop() {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	if !am.isRunning {
+		return
+	}
+	am.isRunning = false
+	close(am.stopChan)
+}
+
+// RecordActivity marks the user as active (window focused) and leaves idle mode immediately
+func (am *ActivityMonitor) RecordActivity() {
+	am.mu.Lock()
+	am.lastActivity = time.Now()
+	wasIdle := am.idle
+	am.idle = false
+	am.mu.Unlock()
+
+	if wasIdle {
+		am.debugPrint("Activity: Window focused - restoring normal polling", "scheduler")
+		if am.onChange != nil {
+			am.onChange(false)
+		}
+	}
+}
+
+// check treats a focused window as activity and enters idle mode after idleAfter without any
+func (am *ActivityMonitor) check() {
+	if am.isFocused != nil && am.isFocused() {
+		am.RecordActivity()
+		return
+	}
+
+	am.mu.Lock()
+	if am.idle || am.idleAfter <= 0 || time.Since(am.lastActivity) < am.idleAfter {
+		am.mu.Unlock()
+		return
+	}
+	am.idle = true
+	idleFor := time.Since(am.lastActivity).Round(time.Second)
+	am.mu.Unlock()
+
+	am.debugPrint(fmt.Sprintf("Activity: No window focused for %v - demoting all tickers to collection intervals", idleFor), "scheduler")
+	if am.onChange != nil {
+		am.onChange(true)
+	}
+}
+
+// SetIdleAfterMinutes updates the idle threshold (<= 0 disables idle detection and leaves idle mode)
+func (am *ActivityMonitor) SetIdleAfterMinutes(minutes int) {
+	am.mu.Lock()
+	am.idleAfter = time.Duration(minutes) * time.Minute
+	disabled := am.idleAfter <= 0
+	am.mu.Unlock()
+
+	if disabled {
+		am.RecordActivity()
+	}
+}
+
+// IsIdle reports whether the user is currently considered idle
+func (am *ActivityMonitor) IsIdle() bool {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	return am.idle
+}
@@ -0,0 +1,17 @@
+//go:build darwin
+
+package scheduler
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// onBatteryPower reports whether the machine is running on battery (pmset prints the current power source)
+func onBatteryPower() (bool, error) {
+	output, err := exec.Command("pmset", "-g", "batt").Output()
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(string(output), "'Battery Power'"), nil
+}
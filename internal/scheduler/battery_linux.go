@@ -0,0 +1,54 @@
+//go:build linux
+
+package scheduler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const powerSupplyDir = "/sys/class/power_supply"
+
+// onBatteryPower reports whether the machine is running on battery: no mains/USB adapter online while a
+// battery is present (machines without a battery are on AC)
+func onBatteryPower() (bool, error) {
+	entries, err := os.ReadDir(powerSupplyDir)
+	if err != nil {
+		return false, err
+	}
+	hasBattery, hasAdapter, adapterOnline, discharging := false, false, false, false
+	for _, entry := range entries {
+		dir := filepath.Join(powerSupplyDir, entry.Name())
+		switch readSysValue(filepath.Join(dir, "type")) {
+		case "Mains", "USB":
+			hasAdapter = true
+			if readSysValue(filepath.Join(dir, "online")) == "1" {
+				adapterOnline = true
+			}
+		case "Battery":
+			if readSysValue(filepath.Join(dir, "scope")) == "Device" {
+				continue // Mouse/keyboard batteries
+			}
+			hasBattery = true
+			if readSysValue(filepath.Join(dir, "status")) == "Discharging" {
+				discharging = true
+			}
+		}
+	}
+	if !hasBattery {
+		return false, nil
+	}
+	if hasAdapter {
+		return !adapterOnline, nil
+	}
+	return discharging, nil
+}
+
+func readSysValue(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
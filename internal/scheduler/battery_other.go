@@ -0,0 +1,10 @@
+//go:build !windows && !linux && !darwin
+
+package scheduler
+
+import "errors"
+
+// onBatteryPower can't read the power source here; eco mode only applies when turned on
+func onBatteryPower() (bool, error) {
+	return false, errors.New("power source detection is not supported on this platform")
+}
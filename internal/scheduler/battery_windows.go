@@ -0,0 +1,33 @@
+//go:build windows
+
+package scheduler
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+)
+
+var procGetSystemPowerStatus = syscall.NewLazyDLL("kernel32.dll").NewProc("GetSystemPowerStatus")
+
+// systemPowerStatus is SYSTEM_POWER_STATUS
+type systemPowerStatus struct {
+	acLineStatus        byte // 0 = offline (battery), 1 = online, 255 = unknown
+	batteryFlag         byte // 128 = no system battery
+	batteryLifePercent  byte
+	systemStatusFlag    byte
+	batteryLifeTime     uint32
+	batteryFullLifeTime uint32
+}
+
+// onBatteryPower reports whether the machine is running on battery
+func onBatteryPower() (bool, error) {
+	var status systemPowerStatus
+	if r, _, err := procGetSystemPowerStatus.Call(uintptr(unsafe.Pointer(&status))); r == 0 {
+		return false, err
+	}
+	if status.acLineStatus == 255 {
+		return false, errors.New("AC line status unknown")
+	}
+	return status.acLineStatus == 0 && status.batteryFlag&128 == 0, nil
+}
@@ -0,0 +1,157 @@
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// EcoStatus is the current eco (battery saving) state
+type EcoStatus struct {
+	Active       bool   `json:"active"`
+	Mode         string `json:"mode"`          // eco_mode setting (auto, on, off)
+	OnBattery    bool   `json:"on_battery"`    // Last power source reading
+	BatteryKnown bool   `json:"battery_known"` // false when the power source can't be read (eco only when forced on)
+	Reason       string `json:"reason"`
+}
+
+// EcoMonitor switches eco mode on while the machine runs on battery (eco_mode: auto) or when forced on
+// While eco is active the app lengthens polling intervals, batches more rows per flush and suspends the profiler
+type EcoMonitor struct {
+	mu         sync.Mutex
+	mode       string
+	onBattery  bool
+	batteryErr error
+	active     bool
+	checked    bool // The power source has been read at least once
+	onChange   func(status EcoStatus)
+	debugPrint func(string, string)
+	stopChan   chan struct{}
+	isRunning  bool
+}
+
+// NewEcoMonitor creates an eco monitor; onChange is called whenever eco mode turns on or off
+func NewEcoMonitor(mode string, onChange func(status EcoStatus), debugPrint func(string, string)) *EcoMonitor {
+	return &EcoMonitor{
+		mode:       mode,
+		onChange:   onChange,
+		debugPrint: debugPrint,
+	}
+}
+
+// Start reads the power source now and then periodically
+func (em *EcoMonitor) Start() {
+	em.mu.Lock()
+	if em.isRunning {
+		em.mu.Unlock()
+		return
+	}
+	em.isRunning = true
+	em.stopChan = make(chan struct{})
+	stop := em.stopChan
+	em.mu.Unlock()
+
+	em.check()
+	go func() {
+		ticker := time.NewTicker(time.Duration(config.PowerCheckIntervalSec) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				em.check()
+			}
+		}
+	}()
+}
+
+// Stop stops power source checks (eco mode is left as it is)
+func (em *EcoMonitor) Stop() {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	if !em.isRunning {
+		return
+	}
+	em.isRunning = false
+	close(em.stopChan)
+}
+
+// SetMode applies a new eco_mode setting immediately
+func (em *EcoMonitor) SetMode(mode string) {
+	em.mu.Lock()
+	em.mode = mode
+	em.mu.Unlock()
+	em.update()
+}
+
+// Status returns the current eco state
+func (em *EcoMonitor) Status() EcoStatus {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	return em.status()
+}
+
+// check reads the power source and re-evaluates eco mode
+func (em *EcoMonitor) check() {
+	onBattery, err := onBatteryPower()
+
+	em.mu.Lock()
+	if em.checked && onBattery != em.onBattery && err == nil {
+		source := "AC power"
+		if onBattery {
+			source = "battery"
+		}
+		em.debugPrint(fmt.Sprintf("Eco: Power source changed to %s", source), "scheduler")
+	}
+	em.onBattery, em.batteryErr, em.checked = onBattery, err, true
+	em.mu.Unlock()
+	em.update()
+}
+
+// update turns eco mode on or off to match the mode and power source, notifying onChange on a change
+func (em *EcoMonitor) update() {
+	em.mu.Lock()
+	active := em.mode == config.EcoModeOn || (em.mode == config.EcoModeAuto && em.batteryErr == nil && em.onBattery)
+	if active == em.active {
+		em.mu.Unlock()
+		return
+	}
+	em.active = active
+	status := em.status()
+	em.mu.Unlock()
+
+	if active {
+		em.debugPrint(fmt.Sprintf("Eco: On (%s) - longer polling intervals, fewer flushes, profiler suspended", status.Reason), "scheduler")
+	} else {
+		em.debugPrint(fmt.Sprintf("Eco: Off (%s) - normal polling restored", status.Reason), "scheduler")
+	}
+	if em.onChange != nil {
+		em.onChange(status)
+	}
+}
+
+// status builds the current status; the caller holds em.mu
+func (em *EcoMonitor) status() EcoStatus {
+	status := EcoStatus{
+		Active:       em.active,
+		Mode:         em.mode,
+		OnBattery:    em.onBattery,
+		BatteryKnown: em.checked && em.batteryErr == nil,
+	}
+	switch {
+	case em.mode == config.EcoModeOn:
+		status.Reason = "eco mode turned on"
+	case em.mode == config.EcoModeOff:
+		status.Reason = "eco mode turned off"
+	case em.batteryErr != nil:
+		status.Reason = fmt.Sprintf("power source unknown: %v", em.batteryErr)
+	case em.onBattery:
+		status.Reason = "running on battery"
+	default:
+		status.Reason = "running on AC power"
+	}
+	return status
+}
@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/utils"
+)
+
+// FetchHistory persists per-ticker last-fetch timestamps across restarts
+// so the scheduler can resume each ticker's interval instead of firing everything at startup
+type FetchHistory struct {
+	mu       sync.Mutex
+	path     string
+	times    map[string]float64 // ticker -> last fetch (Unix seconds)
+	dirty    bool
+	lastSave time.Time   // Wall time of the last write (saves are throttled in real time)
+	clock    utils.Clock // Source of the recorded fetch times (the scheduler's clock once attached)
+}
+
+// GetFetchHistoryPath returns the fetch history file path in the config directory
+func GetFetchHistoryPath() (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, config.FetchHistoryFileName), nil
+}
+
+// LoadFetchHistory reads the fetch history file (a missing or unreadable file starts an empty history)
+func LoadFetchHistory(path string) (*FetchHistory, error) {
+	h := &FetchHistory{
+		path:     path,
+		times:    make(map[string]float64),
+		lastSave: time.Now(),
+		clock:    utils.InstalledClock,
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return h, nil
+		}
+		return h, fmt.Errorf("failed to read fetch history: %w", err)
+	}
+	if err := json.Unmarshal(data, &h.times); err != nil {
+		h.times = make(map[string]float64)
+		return h, fmt.Errorf("failed to parse fetch history (starting fresh): %w", err)
+	}
+	return h, nil
+}
+
+// LastFetch returns the last recorded fetch time for a ticker (0 if never fetched)
+func (h *FetchHistory) LastFetch(ticker string) float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.times[ticker]
+}
+
+// Snapshot returns a copy of all recorded fetch times
+func (h *FetchHistory) Snapshot() map[string]float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	times := make(map[string]float64, len(h.times))
+	for ticker, ts := range h.times {
+		times[ticker] = ts
+	}
+	return times
+}
+
+// Record stores a fetch for a ticker, writing the file at most every FetchHistorySaveIntervalSec
+func (h *FetchHistory) Record(ticker string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.times[ticker] = float64(h.clock.Now().UnixNano()) / 1e9
+	h.dirty = true
+	if time.Since(h.lastSave) < time.Duration(config.FetchHistorySaveIntervalSec)*time.Second {
+		return nil
+	}
+	return h.saveLocked()
+}
+
+// Save writes pending changes to disk
+func (h *FetchHistory) Save() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.saveLocked()
+}
+
+// saveLocked writes the file via a temp file and rename (caller holds mu)
+func (h *FetchHistory) saveLocked() error {
+	if !h.dirty {
+		return nil
+	}
+	h.lastSave = time.Now()
+
+	data, err := json.Marshal(h.times)
+	if err != nil {
+		return fmt.Errorf("failed to encode fetch history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write fetch history: %w", err)
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write fetch history: %w", err)
+	}
+	h.dirty = false
+	return nil
+}
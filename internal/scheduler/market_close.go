@@ -0,0 +1,133 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/crash"
+	"market-terminal/internal/utils"
+)
+
+// MarketCloseWatcher fires registered callbacks once per market date after the close
+// Used for end-of-day work (daily stats, reports) that must run after the final flush
+type MarketCloseWatcher struct {
+	mu            sync.Mutex
+	callbacks     []func(marketDate time.Time)
+	lastFiredDate string // "2006-01-02" of the last market date callbacks ran for
+	delay         time.Duration
+	checkInterval time.Duration
+	debugPrint    func(string, string)
+	stopChan      chan struct{}
+	isRunning     bool
+}
+
+// NewMarketCloseWatcher creates a new market close watcher
+func NewMarketCloseWatcher(debugPrint func(string, string)) *MarketCloseWatcher {
+	return &MarketCloseWatcher{
+		callbacks:     make([]func(time.Time), 0),
+		delay:         time.Duration(config.EndOfDayProcessingDelaySec) * time.Second,
+		checkInterval: time.Duration(config.EndOfDayCheckIntervalSec) * time.Second,
+		debugPrint:    debugPrint,
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// OnMarketClose registers a callback that runs once per market date after the close
+// The callback receives the market date (midnight ET) that just closed
+func (mcw *MarketCloseWatcher) OnMarketClose(callback func(marketDate time.Time)) {
+	mcw.mu.Lock()
+	defer mcw.mu.Unlock()
+	mcw.callbacks = append(mcw.callbacks, callback)
+}
+
+// Start starts the watcher loop
+func (mcw *MarketCloseWatcher) Start() {
+	mcw.mu.Lock()
+	defer mcw.mu.Unlock()
+
+	if mcw.isRunning {
+		return
+	}
+
+	mcw.isRunning = true
+	mcw.stopChan = make(chan struct{})
+	go mcw.run(mcw.stopChan)
+
+	mcw.debugPrint("Market close watcher started", "scheduler")
+}
+
+// Stop stops the watcher loop
+func (mcw *MarketCloseWatcher) Stop() {
+	mcw.mu.Lock()
+	defer mcw.mu.Unlock()
+
+	if !mcw.isRunning {
+		return
+	}
+
+	mcw.isRunning = false
+	close(mcw.stopChan)
+
+	mcw.debugPrint("Market close watcher stopped", "scheduler")
+}
+
+// run checks the clock periodically and fires callbacks after the close
+func (mcw *MarketCloseWatcher) run(stopChan chan struct{}) {
+	ticker := time.NewTicker(mcw.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			mcw.check()
+		case <-stopChan:
+			return
+		}
+	}
+}
+
+// check fires callbacks if today's session has closed and they haven't run yet
+func (mcw *MarketCloseWatcher) check() {
+	now := utils.NowMarketTime()
+	if utils.IsWeekend(now) {
+		return
+	}
+
+	_, marketClose := utils.MarketOpenCloseTimes(now)
+	if now.Before(marketClose.Add(mcw.delay)) {
+		return
+	}
+
+	marketDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, utils.GetMarketTimezone())
+	dateStr := marketDate.Format("2006-01-02")
+
+	mcw.mu.Lock()
+	if mcw.lastFiredDate == dateStr {
+		mcw.mu.Unlock()
+		return
+	}
+	mcw.lastFiredDate = dateStr
+	callbacks := make([]func(time.Time), len(mcw.callbacks))
+	copy(callbacks, mcw.callbacks)
+	mcw.mu.Unlock()
+
+	mcw.debugPrint(fmt.Sprintf("Market closed for %s - running %d end-of-day callback(s)", dateStr, len(callbacks)), "scheduler")
+	for _, callback := range callbacks {
+		mcw.runCallback(callback, marketDate)
+	}
+}
+
+// runCallback runs a single callback, isolating panics so one failure doesn't skip the rest
+func (mcw *MarketCloseWatcher) runCallback(callback func(time.Time), marketDate time.Time) {
+	defer func() {
+		if r := recover(); r != nil {
+			mcw.debugPrint(fmt.Sprintf("❌ PANIC in end-of-day callback: %v", r), "error")
+			log.Printf("MarketCloseWatcher: PANIC in end-of-day callback: %v", r)
+			crash.Report("end-of-day callback", r)
+		}
+	}()
+	callback(marketDate)
+}
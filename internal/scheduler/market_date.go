@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/crash"
+	"market-terminal/internal/utils"
+)
+
+// MarketDateWatcher fires registered callbacks when the market date rolls over (8:30 AM ET, see
+// utils.GetMarketDate), so windows showing "today" can move to the new date
+// Weekends belong to Friday's market date, so the Friday -> Monday change fires Monday at 8:30
+type MarketDateWatcher struct {
+	mu         sync.Mutex
+	callbacks  []func(previous, current time.Time)
+	current    time.Time // Market date seen by the last check (zero before the first)
+	debugPrint func(string, string)
+	stopChan   chan struct{}
+	isRunning  bool
+}
+
+// NewMarketDateWatcher creates a market date watcher
+func NewMarketDateWatcher(debugPrint func(string, string)) *MarketDateWatcher {
+	return &MarketDateWatcher{
+		callbacks:  make([]func(time.Time, time.Time), 0),
+		debugPrint: debugPrint,
+	}
+}
+
+// OnMarketDateChange registers a callback that runs when the market date changes
+// It receives the previous and the new market date
+func (mdw *MarketDateWatcher) OnMarketDateChange(callback func(previous, current time.Time)) {
+	mdw.mu.Lock()
+	defer mdw.mu.Unlock()
+	mdw.callbacks = append(mdw.callbacks, callback)
+}
+
+// Start starts the watcher loop; the market date at start is the baseline (no callback)
+func (mdw *MarketDateWatcher) Start() {
+	mdw.mu.Lock()
+	defer mdw.mu.Unlock()
+	if mdw.isRunning {
+		return
+	}
+	mdw.isRunning = true
+	mdw.current = tradingMarketDate()
+	mdw.stopChan = make(chan struct{})
+	go mdw.run(mdw.stopChan)
+	mdw.debugPrint(fmt.Sprintf("Market date watcher started (market date %s)", mdw.current.Format("2006-01-02")), "scheduler")
+}
+
+// Stop stops the watcher loop
+func (mdw *MarketDateWatcher) Stop() {
+	mdw.mu.Lock()
+	defer mdw.mu.Unlock()
+	if !mdw.isRunning {
+		return
+	}
+	mdw.isRunning = false
+	close(mdw.stopChan)
+}
+
+// run checks the market date periodically
+func (mdw *MarketDateWatcher) run(stopChan chan struct{}) {
+	ticker := time.NewTicker(time.Duration(config.MarketDateCheckIntervalSec) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			mdw.check()
+		case <-stopChan:
+			return
+		}
+	}
+}
+
+// check fires the callbacks if the market date differs from the last check
+func (mdw *MarketDateWatcher) check() {
+	current := tradingMarketDate()
+
+	mdw.mu.Lock()
+	previous := mdw.current
+	if previous.Format("2006-01-02") == current.Format("2006-01-02") {
+		mdw.mu.Unlock()
+		return
+	}
+	mdw.current = current
+	callbacks := make([]func(time.Time, time.Time), len(mdw.callbacks))
+	copy(callbacks, mdw.callbacks)
+	mdw.mu.Unlock()
+
+	mdw.debugPrint(fmt.Sprintf("Market date changed: %s -> %s", previous.Format("2006-01-02"), current.Format("2006-01-02")), "scheduler")
+	for _, callback := range callbacks {
+		mdw.runCallback(callback, previous, current)
+	}
+}
+
+// Current returns the market date as of the last check (the trading day weekends belong to)
+func (mdw *MarketDateWatcher) Current() time.Time {
+	mdw.mu.Lock()
+	defer mdw.mu.Unlock()
+	if mdw.current.IsZero() {
+		return tradingMarketDate()
+	}
+	return mdw.current
+}
+
+// tradingMarketDate returns the current market date, moved back to Friday on weekends
+func tradingMarketDate() time.Time {
+	return utils.GetLastTradingDay(utils.GetMarketDate())
+}
+
+// runCallback runs a single callback, reporting a panic instead of skipping the rest
+func (mdw *MarketDateWatcher) runCallback(callback func(time.Time, time.Time), previous, current time.Time) {
+	defer crash.Recover("market date change")
+	callback(previous, current)
+}
@@ -3,9 +3,12 @@ package scheduler
 import (
 	"fmt"
 	"log"
+	"math/rand"
+	"sort"
 	"sync"
 	"time"
 
+	"market-terminal/internal/crash"
 	"market-terminal/internal/utils"
 )
 
@@ -22,6 +25,8 @@ type PerTickerScheduler struct {
 	stopChan          chan struct{}
 	isRunning         bool
 	allowAfterHours   bool // Allow data collection outside market hours
+	history           *FetchHistory // Persisted last-fetch times for warm starts (nil = always fetch on start)
+	startupStagger    float64       // Seconds Start() spreads initial fetches over (0 = all at once)
 }
 
 // TickerGoroutine manages a single ticker's scheduling goroutine
@@ -31,6 +36,9 @@ type TickerGoroutine struct {
 	timer       *time.Timer
 	mu          sync.Mutex
 	isRunning   bool
+	done        chan struct{} // Closed when the goroutine has exited (after any in-flight fetch)
+	wake        chan struct{} // Signals the goroutine to recalculate its interval now (buffered, 1)
+	startDelay  time.Duration // Wait before the initial fetch (startup staggering)
 }
 
 // NewPerTickerScheduler creates a new per-ticker scheduler
@@ -52,6 +60,58 @@ func NewPerTickerScheduler(
 	}
 }
 
+// SetFetchHistory sets the persisted fetch history used to resume intervals after a restart
+// Call before Start
+func (pts *PerTickerScheduler) SetFetchHistory(history *FetchHistory) {
+	pts.mu.Lock()
+	defer pts.mu.Unlock()
+	pts.history = history
+	if history != nil {
+		history.mu.Lock()
+		history.clock = &pts.scheduler.clock // Recorded times are compared with the scheduler's clock on the next start
+		history.mu.Unlock()
+		pts.scheduler.RestoreFetchTimes(history.Snapshot())
+	}
+}
+
+// SetStartupStagger sets the window (seconds) Start() spreads initial fetches over; 0 disables staggering
+// Applies from the next Start
+func (pts *PerTickerScheduler) SetStartupStagger(seconds float64) {
+	pts.mu.Lock()
+	defer pts.mu.Unlock()
+	pts.startupStagger = seconds
+}
+
+// startupOrder returns enabled tickers sorted by priority (high first) with each ticker's initial fetch delay
+// Tickers get evenly spaced slots across the stagger window plus random jitter within their slot
+func (pts *PerTickerScheduler) startupOrder() ([]string, map[string]time.Duration) {
+	tickers := make([]string, len(pts.enabledTickers))
+	copy(tickers, pts.enabledTickers)
+	delays := make(map[string]time.Duration, len(tickers))
+	if pts.startupStagger <= 0 || len(tickers) < 2 {
+		return tickers, delays
+	}
+
+	openCharts := pts.getOpenCharts()
+	priorities := make(map[string]int, len(tickers))
+	for _, ticker := range tickers {
+		priorities[ticker] = pts.scheduler.GetTickerPriority(ticker, openCharts)
+	}
+	sort.SliceStable(tickers, func(i, j int) bool {
+		return priorities[tickers[i]] < priorities[tickers[j]]
+	})
+
+	slot := pts.startupStagger / float64(len(tickers))
+	for i, ticker := range tickers {
+		if i == 0 {
+			continue // Highest priority ticker fetches immediately
+		}
+		delay := slot*float64(i) + rand.Float64()*slot
+		delays[ticker] = time.Duration(delay * float64(time.Second))
+	}
+	return tickers, delays
+}
+
 // Start starts the scheduler and spawns goroutines for enabled tickers
 func (pts *PerTickerScheduler) Start() {
 	pts.mu.Lock()
@@ -70,10 +130,11 @@ func (pts *PerTickerScheduler) Start() {
 	log.Printf("[SCHEDULER-START] Enabled tickers count: %d", len(pts.enabledTickers))
 	log.Printf("[SCHEDULER-START] Enabled tickers list: %v", pts.enabledTickers)
 
-	// Spawn goroutines for all enabled tickers
-	for i, ticker := range pts.enabledTickers {
-		log.Printf("[SCHEDULER-START] Spawning goroutine %d/%d for ticker: %s", i+1, len(pts.enabledTickers), ticker)
-		pts.spawnTickerGoroutine(ticker)
+	// Spawn goroutines for all enabled tickers (highest priority first, initial fetches staggered)
+	tickers, delays := pts.startupOrder()
+	for i, ticker := range tickers {
+		log.Printf("[SCHEDULER-START] Spawning goroutine %d/%d for ticker: %s (start delay: %v)", i+1, len(tickers), ticker, delays[ticker])
+		pts.spawnTickerGoroutine(ticker, delays[ticker])
 	}
 
 	pts.debugPrint("Per-ticker scheduler started", "system")
@@ -97,14 +158,21 @@ func (pts *PerTickerScheduler) Stop() {
 	close(pts.stopChan)
 	pts.isRunning = false
 
+	if pts.history != nil {
+		if err := pts.history.Save(); err != nil {
+			pts.debugPrint(fmt.Sprintf("Failed to save fetch history: %v", err), "error")
+		}
+	}
+
 	pts.debugPrint("Per-ticker scheduler stopped", "system")
 	log.Printf("PerTickerScheduler: Stopped")
 }
 
 // UpdateTickers updates the list of enabled tickers
 // Spawns new goroutines for newly enabled tickers
-// Stops goroutines for disabled tickers
-func (pts *PerTickerScheduler) UpdateTickers(tickers []string) {
+// Stops goroutines for disabled tickers - a fetch already in flight is allowed to finish
+// Returns a done channel per stopped ticker, closed once its goroutine has exited
+func (pts *PerTickerScheduler) UpdateTickers(tickers []string) map[string]<-chan struct{} {
 	pts.mu.Lock()
 	defer pts.mu.Unlock()
 
@@ -119,13 +187,13 @@ func (pts *PerTickerScheduler) UpdateTickers(tickers []string) {
 	}
 
 	// Stop goroutines for tickers that are no longer enabled
-	stoppedCount := 0
+	stopped := make(map[string]<-chan struct{})
 	for ticker, goroutine := range pts.tickerGoroutines {
 		if !newTickers[ticker] {
 			log.Printf("PerTickerScheduler: Stopping goroutine for disabled ticker: %s", ticker)
 			pts.stopTickerGoroutine(ticker, goroutine)
 			delete(pts.tickerGoroutines, ticker)
-			stoppedCount++
+			stopped[ticker] = goroutine.done
 		}
 	}
 
@@ -135,7 +203,7 @@ func (pts *PerTickerScheduler) UpdateTickers(tickers []string) {
 		for _, ticker := range tickers {
 			if _, exists := pts.tickerGoroutines[ticker]; !exists {
 				log.Printf("PerTickerScheduler: Spawning goroutine for enabled ticker: %s", ticker)
-				pts.spawnTickerGoroutine(ticker)
+				pts.spawnTickerGoroutine(ticker, 0)
 				spawnedCount++
 			}
 		}
@@ -147,19 +215,25 @@ func (pts *PerTickerScheduler) UpdateTickers(tickers []string) {
 	copy(pts.enabledTickers, tickers)
 
 	log.Printf("PerTickerScheduler: Updated to %d enabled tickers (stopped: %d, spawned: %d, active: %d)", 
-		len(pts.enabledTickers), stoppedCount, spawnedCount, len(pts.tickerGoroutines))
+		len(pts.enabledTickers), len(stopped), spawnedCount, len(pts.tickerGoroutines))
+
+	return stopped
 }
 
 // spawnTickerGoroutine spawns a goroutine for a single ticker
-func (pts *PerTickerScheduler) spawnTickerGoroutine(ticker string) {
+// startDelay postpones its initial fetch (0 = fetch immediately)
+func (pts *PerTickerScheduler) spawnTickerGoroutine(ticker string, startDelay time.Duration) {
 	if !pts.isRunning {
 		return
 	}
 
 	goroutine := &TickerGoroutine{
-		ticker:    ticker,
-		stopChan:  make(chan struct{}),
-		isRunning: true,
+		ticker:     ticker,
+		stopChan:   make(chan struct{}),
+		isRunning:  true,
+		done:       make(chan struct{}),
+		wake:       make(chan struct{}, 1),
+		startDelay: startDelay,
 	}
 
 	pts.tickerGoroutines[ticker] = goroutine
@@ -191,10 +265,13 @@ func (pts *PerTickerScheduler) stopTickerGoroutine(ticker string, goroutine *Tic
 
 // runTickerGoroutine runs the scheduling loop for a single ticker
 func (pts *PerTickerScheduler) runTickerGoroutine(ticker string, goroutine *TickerGoroutine) {
+	defer close(goroutine.done)
+
 	// Add panic recovery to prevent goroutine from crashing
 	defer func() {
 		if r := recover(); r != nil {
 			pts.debugPrint(fmt.Sprintf("Ticker %s: ❌ PANIC in goroutine: %v", ticker, r), "error")
+			crash.Report("scheduler", r)
 			// Try to restart the goroutine
 			pts.debugPrint(fmt.Sprintf("Ticker %s: Attempting to restart goroutine after panic", ticker), "scheduler")
 			// Don't restart automatically - let health check handle it
@@ -204,25 +281,48 @@ func (pts *PerTickerScheduler) runTickerGoroutine(ticker string, goroutine *Tick
 
 	// Check market hours before triggering immediate fetch on startup
 	// Only fetch if market is open (or after-hours is explicitly allowed)
-	marketIsOpen := utils.IsMarketOpen()
-	shouldFetchOnStartup := marketIsOpen || pts.allowAfterHours
-	pts.debugPrint(fmt.Sprintf("Ticker %s: Starting goroutine (market open: %v, after-hours allowed: %v)", 
-		ticker, marketIsOpen, pts.allowAfterHours), "scheduler")
+	marketIsOpen := utils.IsMarketOpenAt(pts.scheduler.clock.Now())
+	inWindow := pts.scheduler.UntilTickerActive(ticker) == 0
+	shouldFetchOnStartup := (marketIsOpen || pts.allowAfterHours) && inWindow
+	pts.debugPrint(fmt.Sprintf("Ticker %s: Starting goroutine (market open: %v, after-hours allowed: %v, in active window: %v)", 
+		ticker, marketIsOpen, pts.allowAfterHours, inWindow), "scheduler")
 	
+	// Warm start: a ticker fetched shortly before a restart waits out the rest of its interval
+	var warmStartDelay float64
 	if shouldFetchOnStartup {
+		warmStartDelay = pts.warmStartDelay(ticker)
+	}
+
+	if shouldFetchOnStartup && warmStartDelay > 0 {
+		pts.debugPrint(fmt.Sprintf("Ticker %s: Fetched shortly before restart, first fetch in %.1fs", ticker, warmStartDelay), "scheduler")
+	} else if shouldFetchOnStartup {
+		if goroutine.startDelay > 0 {
+			// Staggered start - don't fire every ticker's first fetch in the same instant
+			select {
+			case <-time.After(goroutine.startDelay):
+			case <-goroutine.stopChan:
+				return
+			case <-pts.stopChan:
+				return
+			}
+		}
 		pts.debugPrint(fmt.Sprintf("Ticker %s: Market is open, triggering immediate fetch", ticker), "scheduler")
 		if pts.onTickerReady != nil {
 			pts.onTickerReady(ticker)
+			pts.recordHistory(ticker)
 			pts.debugPrint(fmt.Sprintf("Ticker %s: Immediate fetch triggered", ticker), "scheduler")
 		} else {
 			pts.debugPrint(fmt.Sprintf("Ticker %s: WARNING - onTickerReady callback is nil!", ticker), "error")
 		}
+	} else if !inWindow {
+		pts.debugPrint(fmt.Sprintf("Ticker %s: Outside its active windows, skipping immediate fetch - will wait for the next window", ticker), "scheduler")
 	} else {
 		pts.debugPrint(fmt.Sprintf("Ticker %s: Market is closed, skipping immediate fetch - will wait for market open", ticker), "scheduler")
 	}
 
-	// Track last market state to only log on changes
+	// Track last market/window state to only log on changes
 	lastMarketState := marketIsOpen
+	lastWindowState := inWindow
 	loopCount := 0
 	for {
 		loopCount++
@@ -239,18 +339,39 @@ func (pts *PerTickerScheduler) runTickerGoroutine(ticker string, goroutine *Tick
 		goroutine.mu.Unlock()
 
 		// Check market hours first - if closed, use longer interval to avoid excessive checks
-		marketIsOpen := utils.IsMarketOpen()
+		now := pts.scheduler.clock.Now()
+		marketIsOpen := utils.IsMarketOpenAt(now)
 		var interval float64
 		
 		if !marketIsOpen && !pts.allowAfterHours {
 			// Market is closed - use a longer interval (60 seconds) to check again
 			interval = 60.0
+			// ...but wake exactly at the open (the pre-open phase has prepared the databases by then)
+			// instead of up to a minute after it
+			if marketOpen, _ := utils.MarketOpenCloseTimes(now); marketOpen.After(now) && marketOpen.Sub(now).Seconds() < interval {
+				interval = marketOpen.Sub(now).Seconds()
+			}
 			// Only log when market state changes
 			if marketIsOpen != lastMarketState {
 				pts.debugPrint(fmt.Sprintf("Ticker %s: Market is closed, using 60s interval for next check", ticker), "scheduler")
 				lastMarketState = marketIsOpen
 			}
+		} else if untilActive := pts.scheduler.UntilTickerActive(ticker); untilActive != 0 {
+			// Outside the ticker's active windows - sleep until the next one opens (checking at least
+			// every 60s so edited windows apply), without spending API requests
+			interval = 60.0
+			if untilActive > 0 && untilActive.Seconds() < interval {
+				interval = untilActive.Seconds()
+			}
+			if lastWindowState {
+				pts.debugPrint(fmt.Sprintf("Ticker %s: Outside its active windows, pausing collection", ticker), "scheduler")
+				lastWindowState = false
+			}
 		} else {
+			if !lastWindowState {
+				pts.debugPrint(fmt.Sprintf("Ticker %s: Active window opened, resuming collection", ticker), "scheduler")
+				lastWindowState = true
+			}
 			// Market is open - calculate normal interval
 			openCharts := pts.getOpenCharts()
 			if openCharts == nil {
@@ -261,8 +382,13 @@ func (pts *PerTickerScheduler) runTickerGoroutine(ticker string, goroutine *Tick
 			if interval <= 0 {
 				interval = 5.0 // Default to 5 seconds
 			}
+			if warmStartDelay > 0 && warmStartDelay < interval {
+				interval = warmStartDelay
+			}
 		}
 
+		warmStartDelay = 0 // Only applies to the first wait
+
 		// Record that we're about to fetch (prevents immediate re-fetch)
 		pts.scheduler.RecordFetch(ticker)
 
@@ -282,8 +408,9 @@ func (pts *PerTickerScheduler) runTickerGoroutine(ticker string, goroutine *Tick
 		select {
 		case <-timer.C:
 			// Timer fired - check market hours before fetching
-			marketIsOpen := utils.IsMarketOpen()
+			marketIsOpen := utils.IsMarketOpenAt(pts.scheduler.clock.Now())
 			shouldFetch := marketIsOpen || pts.allowAfterHours
+			inWindow := pts.scheduler.UntilTickerActive(ticker) == 0
 			
 			// Only log timer firing if market state changed or if market is open
 			if marketIsOpen != lastMarketState || marketIsOpen {
@@ -304,6 +431,10 @@ func (pts *PerTickerScheduler) runTickerGoroutine(ticker string, goroutine *Tick
 				// The next iteration will recalculate the interval, but we'll use a minimum of 60s when closed
 				continue
 			}
+			if !inWindow {
+				// Window closed while waiting - the next iteration waits for the next window
+				continue
+			}
 			
 			// Market is open - trigger fetch
 			log.Printf("[TICKER-FETCH] %s: Timer fired, triggering fetch (interval was: %.2fs)", ticker, interval)
@@ -311,6 +442,7 @@ func (pts *PerTickerScheduler) runTickerGoroutine(ticker string, goroutine *Tick
 				ticker, interval), "scheduler")
 			if pts.onTickerReady != nil {
 				pts.onTickerReady(ticker)
+				pts.recordHistory(ticker)
 				log.Printf("[TICKER-FETCH] %s: Fetch callback completed", ticker)
 				pts.debugPrint(fmt.Sprintf("Ticker %s: Fetch callback completed, continuing loop", ticker), "scheduler")
 			} else {
@@ -318,6 +450,12 @@ func (pts *PerTickerScheduler) runTickerGoroutine(ticker string, goroutine *Tick
 				pts.debugPrint(fmt.Sprintf("Ticker %s: WARNING - onTickerReady is nil, cannot fetch!", ticker), "error")
 			}
 			// Continue loop to schedule next timer
+		case <-goroutine.wake:
+			// Priorities changed (e.g. leaving idle mode) - recalculate the interval instead of
+			// waiting out the old, possibly much longer, one
+			pts.debugPrint(fmt.Sprintf("Ticker %s: Woken to recalculate interval", ticker), "scheduler")
+			timer.Stop()
+			continue
 		case <-goroutine.stopChan:
 			// Stop signal received
 			pts.debugPrint(fmt.Sprintf("Ticker %s: Stop signal received, exiting goroutine", ticker), "scheduler")
@@ -334,6 +472,53 @@ func (pts *PerTickerScheduler) runTickerGoroutine(ticker string, goroutine *Tick
 	}
 }
 
+// warmStartDelay returns how long to wait before a ticker's first fetch after a restart
+// 0 when there is no history or the ticker's interval has already elapsed (fetch immediately)
+// Higher priority tickers have shorter intervals, so they resume first
+func (pts *PerTickerScheduler) warmStartDelay(ticker string) float64 {
+	if pts.history == nil {
+		return 0
+	}
+	lastFetch := pts.history.LastFetch(ticker)
+	if lastFetch == 0 {
+		return 0
+	}
+
+	openCharts := pts.getOpenCharts()
+	if openCharts == nil {
+		openCharts = []interface{}{}
+	}
+	interval := pts.scheduler.CalculateInterval(ticker, openCharts)
+	elapsed := float64(pts.scheduler.clock.Now().UnixNano())/1e9 - lastFetch
+	if elapsed < 0 || elapsed >= interval {
+		return 0
+	}
+	return interval - elapsed
+}
+
+// recordHistory persists a completed fetch for warm starts
+func (pts *PerTickerScheduler) recordHistory(ticker string) {
+	if pts.history == nil {
+		return
+	}
+	if err := pts.history.Record(ticker); err != nil {
+		pts.debugPrint(fmt.Sprintf("Failed to save fetch history: %v", err), "error")
+	}
+}
+
+// Reschedule makes every ticker goroutine recalculate its interval immediately
+// Used when priorities change so tickers don't wait out an interval computed under the old ones
+func (pts *PerTickerScheduler) Reschedule() {
+	pts.mu.RLock()
+	defer pts.mu.RUnlock()
+	for _, goroutine := range pts.tickerGoroutines {
+		select {
+		case goroutine.wake <- struct{}{}:
+		default: // Already signalled
+		}
+	}
+}
+
 // IsRunning checks if the scheduler is running
 func (pts *PerTickerScheduler) IsRunning() bool {
 	pts.mu.RLock()
@@ -0,0 +1,219 @@
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/crash"
+	"market-terminal/internal/utils"
+)
+
+// Pre-open phases of a market date
+const (
+	PreOpenWaiting = "waiting"  // Before the pre-open phase (or a weekend or market holiday)
+	PreOpenReady   = "pre_open" // Prepared, counting down to the open
+	PreOpenOpen    = "open"     // Past the open
+)
+
+// PreOpenStatus reports the current market date's pre-open phase
+type PreOpenStatus struct {
+	MarketDate    string  `json:"market_date"`
+	Phase         string  `json:"phase"`
+	OpenAt        float64 `json:"open_at"`         // Unix seconds of the open
+	SecondsToOpen float64 `json:"seconds_to_open"` // Countdown to the open (0 once open)
+	PreparedAt    float64 `json:"prepared_at"`     // Unix seconds the phase ran (0 = not today, e.g. started after it)
+	APIVerified   bool    `json:"api_verified"`
+	APIError      string  `json:"api_error,omitempty"`
+	Prepared      int     `json:"prepared"` // Ticker databases opened with their schema created
+	PrepareError  string  `json:"prepare_error,omitempty"`
+	RampedAt      float64 `json:"ramped_at"` // Unix seconds the open callback ran (0 = not today)
+}
+
+// PreOpenWatcher runs the session warm start: PreOpenLeadMinutes before the open it verifies the API and
+// prepares today's databases, then runs the open callback at the open itself. Ticker goroutines wake at the
+// open on their own (see PerTickerScheduler); the open callback re-opens what the pool closed while idle
+// Starting the app after the open skips the warm start for that day; weekends and market holidays have none
+// (preparing would create empty day directories the date selector then lists)
+type PreOpenWatcher struct {
+	mu         sync.Mutex
+	verifyAPI  func() error
+	prepare    func(marketDate time.Time) (int, error)
+	open       func(marketDate time.Time)
+	onStatus   func(PreOpenStatus) // Called when the phase changes (e.g. to show the countdown)
+	status     PreOpenStatus
+	debugPrint func(string, string)
+	stopChan   chan struct{}
+	isRunning  bool
+}
+
+// NewPreOpenWatcher creates a pre-open watcher
+// verifyAPI makes one request to check the key and connectivity; prepare opens the market date's databases
+// and returns how many it prepared; open runs at the open
+func NewPreOpenWatcher(verifyAPI func() error, prepare func(marketDate time.Time) (int, error), open func(marketDate time.Time), debugPrint func(string, string)) *PreOpenWatcher {
+	return &PreOpenWatcher{
+		verifyAPI:  verifyAPI,
+		prepare:    prepare,
+		open:       open,
+		status:     PreOpenStatus{Phase: PreOpenWaiting},
+		debugPrint: debugPrint,
+	}
+}
+
+// OnStatus sets the callback for phase changes
+func (pow *PreOpenWatcher) OnStatus(onStatus func(PreOpenStatus)) {
+	pow.mu.Lock()
+	defer pow.mu.Unlock()
+	pow.onStatus = onStatus
+}
+
+// Start starts the watcher loop
+func (pow *PreOpenWatcher) Start() {
+	pow.mu.Lock()
+	defer pow.mu.Unlock()
+	if pow.isRunning {
+		return
+	}
+	pow.isRunning = true
+	pow.stopChan = make(chan struct{})
+	go pow.run(pow.stopChan)
+	pow.debugPrint("Pre-open watcher started", "scheduler")
+}
+
+// Stop stops the watcher loop
+func (pow *PreOpenWatcher) Stop() {
+	pow.mu.Lock()
+	defer pow.mu.Unlock()
+	if !pow.isRunning {
+		return
+	}
+	pow.isRunning = false
+	close(pow.stopChan)
+}
+
+// run checks the clock, sleeping until the next stage (exactly until the open once prepared)
+func (pow *PreOpenWatcher) run(stopChan chan struct{}) {
+	for {
+		timer := time.NewTimer(pow.check())
+		select {
+		case <-timer.C:
+		case <-stopChan:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// check runs any stage that is due and returns how long to wait before the next check
+func (pow *PreOpenWatcher) check() time.Duration {
+	wait := time.Duration(config.PreOpenCheckIntervalSec) * time.Second
+	now := utils.NowMarketTime()
+	if !utils.IsTradingDay(now) {
+		return wait
+	}
+	openAt, _ := utils.MarketOpenCloseTimes(now)
+	prepareAt := openAt.Add(-time.Duration(config.PreOpenLeadMinutes) * time.Minute)
+	marketDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, utils.GetMarketTimezone())
+	dateStr := marketDate.Format("2006-01-02")
+
+	pow.mu.Lock()
+	if pow.status.MarketDate != dateStr {
+		pow.status = PreOpenStatus{MarketDate: dateStr, Phase: PreOpenWaiting, OpenAt: float64(openAt.Unix())}
+	}
+	phase := pow.status.Phase
+	pow.mu.Unlock()
+
+	switch {
+	case now.Before(prepareAt):
+		if until := prepareAt.Sub(now); until < wait {
+			wait = until
+		}
+	case now.Before(openAt):
+		if phase == PreOpenWaiting {
+			pow.runPrepare(marketDate, now)
+		}
+		wait = openAt.Sub(utils.NowMarketTime())
+	default:
+		if phase != PreOpenOpen {
+			pow.runOpen(marketDate, now.Sub(openAt) < time.Duration(config.PreOpenRampGraceSec)*time.Second)
+		}
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return wait
+}
+
+// runPrepare verifies the API and prepares the market date's databases
+func (pow *PreOpenWatcher) runPrepare(marketDate, now time.Time) {
+	defer crash.Recover("pre-open")
+	pow.debugPrint(fmt.Sprintf("Pre-open: Preparing %s (verifying API, opening databases)", marketDate.Format("2006-01-02")), "scheduler")
+
+	apiErr := pow.verifyAPI()
+	prepared, prepareErr := pow.prepare(marketDate)
+
+	pow.mu.Lock()
+	pow.status.Phase = PreOpenReady
+	pow.status.PreparedAt = float64(now.Unix())
+	pow.status.APIVerified = apiErr == nil
+	pow.status.APIError = ""
+	if apiErr != nil {
+		pow.status.APIError = apiErr.Error()
+	}
+	pow.status.Prepared = prepared
+	pow.status.PrepareError = ""
+	if prepareErr != nil {
+		pow.status.PrepareError = prepareErr.Error()
+	}
+	pow.mu.Unlock()
+
+	if apiErr != nil {
+		pow.debugPrint(fmt.Sprintf("⚠️ Pre-open: API check failed: %v - collection will still start at the open", apiErr), "error")
+	}
+	if prepareErr != nil {
+		pow.debugPrint(fmt.Sprintf("⚠️ Pre-open: %v", prepareErr), "error")
+	}
+	pow.debugPrint(fmt.Sprintf("Pre-open: %d database(s) ready, API verified: %v", prepared, apiErr == nil), "scheduler")
+	pow.notify()
+}
+
+// runOpen marks the market date open, running the open callback if the open just passed
+func (pow *PreOpenWatcher) runOpen(marketDate time.Time, ramp bool) {
+	defer crash.Recover("pre-open")
+	pow.mu.Lock()
+	pow.status.Phase = PreOpenOpen // Set first: a failing callback isn't retried
+	pow.mu.Unlock()
+
+	if ramp {
+		pow.debugPrint(fmt.Sprintf("Pre-open: Market open for %s, ramping collection", marketDate.Format("2006-01-02")), "scheduler")
+		pow.open(marketDate)
+		pow.mu.Lock()
+		pow.status.RampedAt = float64(utils.NowMarketTime().Unix())
+		pow.mu.Unlock()
+	}
+	pow.notify()
+}
+
+// notify passes the current status to the status callback
+func (pow *PreOpenWatcher) notify() {
+	pow.mu.Lock()
+	onStatus := pow.onStatus
+	pow.mu.Unlock()
+	if onStatus != nil {
+		onStatus(pow.Status())
+	}
+}
+
+// Status returns the current market date's pre-open phase with the countdown to the open
+func (pow *PreOpenWatcher) Status() PreOpenStatus {
+	pow.mu.Lock()
+	defer pow.mu.Unlock()
+	status := pow.status
+	if status.OpenAt > 0 {
+		if until := status.OpenAt - float64(utils.NowMarketTime().UnixNano())/1e9; until > 0 {
+			status.SecondsToOpen = until
+		}
+	}
+	return status
+}
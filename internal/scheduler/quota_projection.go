@@ -0,0 +1,61 @@
+package scheduler
+
+import (
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// QuotaProjection projects the API's remaining request quota forward at the current request rate
+type QuotaProjection struct {
+	Known         bool    `json:"known"`           // The API reported a long-window quota (limit, remaining and reset time)
+	Remaining     int     `json:"remaining"`       // Remaining requests reported by the API
+	ResetTime     float64 `json:"reset_time"`      // Unix time the quota resets (0 = unknown)
+	RatePerMinute float64 `json:"rate_per_minute"` // Requests made in the last window, per minute
+	Deadline      float64 `json:"deadline"`        // Unix time the quota has to last until (the earlier of the reset and until)
+	ExhaustsAt    float64 `json:"exhausts_at"`     // Unix time the quota runs out at the current rate (0 = not before the deadline)
+}
+
+// Exhausts reports whether the quota is projected to run out before the deadline
+func (p QuotaProjection) Exhausts() bool {
+	return p.ExhaustsAt > 0
+}
+
+// ProjectQuota projects whether the remaining quota lasts until the given time (e.g. market close) at the
+// request rate of the last window. Quotas resetting within QuotaProjectionMinWindowSec (per-minute limits)
+// aren't projected: running out of those is a brief stall the throttling already handles
+func (rlt *RateLimitTracker) ProjectQuota(now, until time.Time) QuotaProjection {
+	status := rlt.GetStatus()
+
+	rlt.mu.RLock()
+	window := rlt.rateLimitWindow
+	rlt.mu.RUnlock()
+
+	projection := QuotaProjection{
+		Remaining:     status.Remaining,
+		ResetTime:     status.ResetTime,
+		RatePerMinute: float64(status.RequestsInWindow) * 60 / window,
+		Deadline:      float64(until.Unix()),
+	}
+	reset := time.Unix(int64(status.ResetTime), 0)
+	if status.Limit <= 0 || status.ResetTime <= 0 || reset.Sub(now).Seconds() < config.QuotaProjectionMinWindowSec {
+		return projection
+	}
+	projection.Known = true
+
+	deadline := until
+	if reset.Before(deadline) {
+		deadline = reset // The quota refills before it has to last until the deadline
+	}
+	projection.Deadline = float64(deadline.Unix())
+	if projection.RatePerMinute <= 0 || !now.Before(deadline) {
+		return projection
+	}
+
+	perSecond := projection.RatePerMinute / 60 * config.QuotaProjectionSafetyRatio
+	exhaustsAt := now.Add(time.Duration(float64(status.Remaining) / perSecond * float64(time.Second)))
+	if exhaustsAt.Before(deadline) {
+		projection.ExhaustsAt = float64(exhaustsAt.Unix())
+	}
+	return projection
+}
@@ -2,105 +2,125 @@ package scheduler
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 )
 
-// RateLimitTracker tracks API rate limits and ensures we respect them
+// RateLimitTracker tracks API rate limits per API key and ensures we respect them
+// Every key has its own quota (the X-RateLimit-* headers and 429s belong to the key that made the request), and
+// the client rotates away from limited keys, so the tracker is only rate limited when every key is
 type RateLimitTracker struct {
-	mu                    sync.RWMutex
-	requestTimes          []float64 // Track request times in current window
-	rateLimitWindow       float64   // Default 60 second window
-	rateLimitMaxRequests  int       // Max requests per window (discovered)
-	rateLimitRemaining    int       // Remaining requests (from headers)
-	rateLimitResetTime    float64   // When rate limit resets (from headers)
-	isRateLimited         bool      // Currently rate limited
-	retryAfter            float64   // When to retry after rate limit error
-
-	// 429 error monitoring and adaptive throttling
-	rateLimitErrors       []float64 // Track last 100 rate limit errors (timestamp)
-	lightThrottleEnabled  bool      // Enable light throttling if 429s are frequent
-	lightThrottleInterval float64   // 200ms minimum between same endpoint calls
-	lastEndpointCallTimes  map[string]float64 // endpoint -> last call time
-	rateLimitErrorThreshold int     // Enable light throttle if 5+ 429s in last 60 seconds
+	mu              sync.RWMutex
+	keys            map[string]*keyRateLimit // Key name ("primary" or an additional key's name) -> its limits
+	rateLimitWindow float64                  // Default 60 second window
+
+	// 429 error monitoring and adaptive throttling (across keys: spaces out calls to the same endpoint)
+	rateLimitErrors         []float64          // Track last 100 rate limit errors (timestamp)
+	lightThrottleEnabled    bool               // Enable light throttling if 429s are frequent
+	lightThrottleInterval   float64            // 200ms minimum between same endpoint calls
+	lastEndpointCallTimes   map[string]float64 // endpoint -> last call time
+	rateLimitErrorThreshold int                // Enable light throttle if 5+ 429s in last 60 seconds
+}
+
+// keyRateLimit is the rate limit state of one API key
+type keyRateLimit struct {
+	requestTimes  []float64 // Track request times in current window
+	maxRequests   int       // Max requests per window (discovered)
+	remaining     int       // Remaining requests (from headers)
+	resetTime     float64   // When rate limit resets (from headers)
+	isRateLimited bool      // Currently rate limited
+	retryAfter    float64   // When to retry after rate limit error
 }
 
 // NewRateLimitTracker creates a new rate limit tracker
 func NewRateLimitTracker() *RateLimitTracker {
 	return &RateLimitTracker{
-		requestTimes:          make([]float64, 0, 2000),
-		rateLimitWindow:       60.0,
-		rateLimitErrors:       make([]float64, 0, 100),
-		lastEndpointCallTimes: make(map[string]float64),
-		lightThrottleInterval: 0.2, // 200ms
+		keys:                    make(map[string]*keyRateLimit),
+		rateLimitWindow:         60.0,
+		rateLimitErrors:         make([]float64, 0, 100),
+		lastEndpointCallTimes:   make(map[string]float64),
+		lightThrottleInterval:   0.2, // 200ms
 		rateLimitErrorThreshold: 5,
 	}
 }
 
-// RecordRequest records an API request
-func (rlt *RateLimitTracker) RecordRequest(requestTime float64, success bool, headers map[string]string) {
+// keyLocked returns a key's state, creating it on first use (caller holds the write lock)
+func (rlt *RateLimitTracker) keyLocked(key string) *keyRateLimit {
+	state, ok := rlt.keys[key]
+	if !ok {
+		state = &keyRateLimit{requestTimes: make([]float64, 0, 2000)}
+		rlt.keys[key] = state
+	}
+	return state
+}
+
+// RecordRequest records an API request made with a key (the name the client reported it under)
+func (rlt *RateLimitTracker) RecordRequest(key string, requestTime float64, success bool, headers map[string]string) {
 	rlt.mu.Lock()
 	defer rlt.mu.Unlock()
+	state := rlt.keyLocked(key)
 
 	// Add to request history
-	rlt.requestTimes = append(rlt.requestTimes, requestTime)
+	state.requestTimes = append(state.requestTimes, requestTime)
 
 	// Clean old requests outside window (keep last 2000)
-	if len(rlt.requestTimes) > 2000 {
+	if len(state.requestTimes) > 2000 {
 		cutoffTime := requestTime - rlt.rateLimitWindow
 		// Remove old entries
 		newTimes := make([]float64, 0, 2000)
-		for _, t := range rlt.requestTimes {
+		for _, t := range state.requestTimes {
 			if t > cutoffTime {
 				newTimes = append(newTimes, t)
 			}
 		}
-		rlt.requestTimes = newTimes
+		state.requestTimes = newTimes
 	}
 
 	// Update from headers if available
 	if headers != nil {
-		rlt.updateFromHeaders(headers)
+		state.updateFromHeaders(headers)
 	}
 
 	// Check if we're rate limited
 	if !success {
-		rlt.isRateLimited = true
-	} else if rlt.rateLimitMaxRequests > 0 && len(rlt.requestTimes) >= rlt.rateLimitMaxRequests {
-		rlt.isRateLimited = true
+		state.isRateLimited = true
+	} else if state.maxRequests > 0 && len(state.requestTimes) >= state.maxRequests {
+		state.isRateLimited = true
 	} else {
-		rlt.isRateLimited = false
+		state.isRateLimited = false
 	}
 }
 
 // updateFromHeaders updates rate limit parameters from API response headers
-func (rlt *RateLimitTracker) updateFromHeaders(headers map[string]string) {
+func (state *keyRateLimit) updateFromHeaders(headers map[string]string) {
 	if limit, ok := headers["X-RateLimit-Limit"]; ok {
 		if val := parseInt(limit); val > 0 {
-			rlt.rateLimitMaxRequests = val
+			state.maxRequests = val
 		}
 	}
 
 	if remaining, ok := headers["X-RateLimit-Remaining"]; ok {
 		if val := parseInt(remaining); val >= 0 {
-			rlt.rateLimitRemaining = val
+			state.remaining = val
 		}
 	}
 
 	if reset, ok := headers["X-RateLimit-Reset"]; ok {
 		if val := parseFloat(reset); val > 0 {
-			rlt.rateLimitResetTime = val
+			state.resetTime = val
 		}
 	}
 }
 
-// HandleRateLimitError handles 429 Too Many Requests error
-func (rlt *RateLimitTracker) HandleRateLimitError(retryAfter float64) {
+// HandleRateLimitError handles a 429 Too Many Requests error for a key
+func (rlt *RateLimitTracker) HandleRateLimitError(key string, retryAfter float64) {
 	rlt.mu.Lock()
 	defer rlt.mu.Unlock()
+	state := rlt.keyLocked(key)
 
 	currentTime := time.Now().Unix()
-	rlt.isRateLimited = true
+	state.isRateLimited = true
 
 	// Track 429 error for monitoring
 	rlt.rateLimitErrors = append(rlt.rateLimitErrors, float64(currentTime))
@@ -112,12 +132,12 @@ func (rlt *RateLimitTracker) HandleRateLimitError(retryAfter float64) {
 	rlt.updateLightThrottleStatus(float64(currentTime))
 
 	if retryAfter > 0 {
-		rlt.retryAfter = float64(currentTime) + retryAfter
-	} else if rlt.rateLimitResetTime > 0 {
-		rlt.retryAfter = rlt.rateLimitResetTime
+		state.retryAfter = float64(currentTime) + retryAfter
+	} else if state.resetTime > 0 {
+		state.retryAfter = state.resetTime
 	} else {
 		// Default: wait 60 seconds if we don't know when to retry
-		rlt.retryAfter = float64(currentTime) + 60.0
+		state.retryAfter = float64(currentTime) + 60.0
 	}
 }
 
@@ -180,57 +200,76 @@ func (rlt *RateLimitTracker) RecordEndpointCall(endpoint string) {
 	}
 }
 
-// IsRateLimited checks if we're currently rate limited
-func (rlt *RateLimitTracker) IsRateLimited() bool {
-	rlt.mu.RLock()
-	defer rlt.mu.RUnlock()
+// clearExpiredLocked lifts the rate limit of keys whose retry_after time has passed (caller holds the write lock)
+func (rlt *RateLimitTracker) clearExpiredLocked() {
+	currentTime := float64(time.Now().Unix())
+	for _, state := range rlt.keys {
+		if state.retryAfter > 0 && currentTime >= state.retryAfter {
+			state.isRateLimited = false
+			state.retryAfter = 0
+		}
+	}
+}
 
-	// Check if retry_after time has passed
-	if rlt.retryAfter > 0 {
-		currentTime := float64(time.Now().Unix())
-		if currentTime >= rlt.retryAfter {
-			rlt.mu.RUnlock()
-			rlt.mu.Lock()
-			rlt.isRateLimited = false
-			rlt.retryAfter = 0
-			rlt.mu.Unlock()
-			rlt.mu.RLock()
+// allLimitedLocked reports whether every known key is rate limited (false before any request)
+func (rlt *RateLimitTracker) allLimitedLocked() bool {
+	if len(rlt.keys) == 0 {
+		return false
+	}
+	for _, state := range rlt.keys {
+		if !state.isRateLimited {
+			return false
 		}
 	}
+	return true
+}
 
-	return rlt.isRateLimited
+// IsRateLimited checks if we're currently rate limited (every key is)
+func (rlt *RateLimitTracker) IsRateLimited() bool {
+	rlt.mu.Lock()
+	defer rlt.mu.Unlock()
+	rlt.clearExpiredLocked()
+	return rlt.allLimitedLocked()
 }
 
-// CanMakeRequest checks if we can make a request based on rate limits
+// CanMakeRequest checks if any key can make a request based on its rate limits
 func (rlt *RateLimitTracker) CanMakeRequest() bool {
 	rlt.mu.RLock()
 	defer rlt.mu.RUnlock()
 
-	if rlt.isRateLimited {
-		return false
+	if len(rlt.keys) == 0 {
+		return true
 	}
-
-	// Check if we're within rate limit based on request history
-	if rlt.rateLimitMaxRequests > 0 {
-		return len(rlt.requestTimes) < rlt.rateLimitMaxRequests
+	for _, state := range rlt.keys {
+		if state.isRateLimited {
+			continue
+		}
+		// Check if we're within rate limit based on request history
+		if state.maxRequests <= 0 || len(state.requestTimes) < state.maxRequests {
+			return true
+		}
 	}
-
-	return true
+	return false
 }
 
 // GetMinimumInterval calculates minimum interval to respect rate limits
+// The keys' limits add up: the client spreads requests over them
 func (rlt *RateLimitTracker) GetMinimumInterval(tickerCount int) float64 {
 	rlt.mu.RLock()
 	defer rlt.mu.RUnlock()
 
-	if rlt.rateLimitMaxRequests <= 0 {
+	maxRequests := 0
+	for _, state := range rlt.keys {
+		maxRequests += state.maxRequests
+	}
+	if maxRequests <= 0 {
 		return 0.0 // No rate limit known
 	}
 
 	// Calculate minimum interval based on rate limit and ticker count
 	// Ensure we don't exceed rate limit even with all tickers polling
-	minInterval := rlt.rateLimitWindow / float64(rlt.rateLimitMaxRequests)
-	
+	minInterval := rlt.rateLimitWindow / float64(maxRequests)
+
 	// Scale by ticker count to ensure we don't exceed limit
 	if tickerCount > 0 {
 		minInterval *= float64(tickerCount)
@@ -239,6 +278,80 @@ func (rlt *RateLimitTracker) GetMinimumInterval(tickerCount int) float64 {
 	return minInterval
 }
 
+// RateLimitStatus is a snapshot of rate limit state for the UI
+// The top-level fields combine the keys: limits, remaining quota and requests add up, the reset time is the
+// latest key's, and the tracker is rate limited only while every key is
+type RateLimitStatus struct {
+	Limit            int                  `json:"limit"`              // Requests allowed per window (0 = not yet known)
+	Remaining        int                  `json:"remaining"`          // Remaining requests reported by the API
+	ResetTime        float64              `json:"reset_time"`         // Unix time the window resets (0 = unknown)
+	RequestsInWindow int                  `json:"requests_in_window"` // Requests made in the last window
+	Recent429s       int                  `json:"recent_429s"`        // 429 responses in the last 60 seconds
+	LightThrottle    bool                 `json:"light_throttle"`     // Light throttling active due to frequent 429s
+	IsRateLimited    bool                 `json:"is_rate_limited"`
+	RetryAfter       float64              `json:"retry_after"` // Unix time requests resume while rate limited (0 = not limited)
+	Keys             []KeyRateLimitStatus `json:"keys"`        // Per API key, sorted by name
+}
+
+// KeyRateLimitStatus is the rate limit state of one API key
+type KeyRateLimitStatus struct {
+	Key              string  `json:"key"` // Key name ("primary" or the name given in additional_api_keys)
+	Limit            int     `json:"limit"`
+	Remaining        int     `json:"remaining"`
+	ResetTime        float64 `json:"reset_time"`
+	RequestsInWindow int     `json:"requests_in_window"`
+	IsRateLimited    bool    `json:"is_rate_limited"`
+	RetryAfter       float64 `json:"retry_after"`
+}
+
+// GetStatus returns the current rate limit state
+func (rlt *RateLimitTracker) GetStatus() RateLimitStatus {
+	rlt.mu.Lock()
+	defer rlt.mu.Unlock()
+	rlt.clearExpiredLocked()
+
+	currentTime := float64(time.Now().Unix())
+	status := RateLimitStatus{
+		LightThrottle: rlt.lightThrottleEnabled,
+		IsRateLimited: rlt.allLimitedLocked(),
+		Keys:          make([]KeyRateLimitStatus, 0, len(rlt.keys)),
+	}
+	for key, state := range rlt.keys {
+		keyStatus := KeyRateLimitStatus{
+			Key:           key,
+			Limit:         state.maxRequests,
+			Remaining:     state.remaining,
+			ResetTime:     state.resetTime,
+			IsRateLimited: state.isRateLimited,
+			RetryAfter:    state.retryAfter,
+		}
+		for _, t := range state.requestTimes {
+			if t > currentTime-rlt.rateLimitWindow {
+				keyStatus.RequestsInWindow++
+			}
+		}
+		status.Keys = append(status.Keys, keyStatus)
+
+		status.Limit += keyStatus.Limit
+		status.Remaining += keyStatus.Remaining
+		status.RequestsInWindow += keyStatus.RequestsInWindow
+		if keyStatus.ResetTime > status.ResetTime {
+			status.ResetTime = keyStatus.ResetTime
+		}
+		// Requests resume as soon as the first key's limit lifts
+		if status.IsRateLimited && keyStatus.RetryAfter > 0 && (status.RetryAfter == 0 || keyStatus.RetryAfter < status.RetryAfter) {
+			status.RetryAfter = keyStatus.RetryAfter
+		}
+	}
+	sort.Slice(status.Keys, func(i, j int) bool { return status.Keys[i].Key < status.Keys[j].Key })
+	for _, t := range rlt.rateLimitErrors {
+		if t > currentTime-60.0 {
+			status.Recent429s++
+		}
+	}
+	return status
+}
+
 // Helper functions
 func parseInt(s string) int {
 	var val int
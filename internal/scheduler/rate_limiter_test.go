@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimitTrackerPerKey(t *testing.T) {
+	rlt := NewRateLimitTracker()
+	now := float64(time.Now().Unix())
+	rlt.RecordRequest("primary", now, true, map[string]string{"X-RateLimit-Limit": "100", "X-RateLimit-Remaining": "40"})
+	rlt.RecordRequest("backup", now, true, map[string]string{"X-RateLimit-Limit": "50", "X-RateLimit-Remaining": "50"})
+
+	// A 429 on one key leaves the other usable
+	rlt.HandleRateLimitError("primary", 30)
+	if rlt.IsRateLimited() {
+		t.Fatal("IsRateLimited with one of two keys limited")
+	}
+	if !rlt.CanMakeRequest() {
+		t.Fatal("CanMakeRequest false with an unlimited key")
+	}
+
+	status := rlt.GetStatus()
+	if status.Limit != 150 || status.Remaining != 90 || status.RequestsInWindow != 2 {
+		t.Errorf("status limit/remaining/requests = %d/%d/%d, want 150/90/2", status.Limit, status.Remaining, status.RequestsInWindow)
+	}
+	if len(status.Keys) != 2 || status.Keys[0].Key != "backup" || status.Keys[1].Key != "primary" {
+		t.Fatalf("status keys = %+v, want backup then primary", status.Keys)
+	}
+	if status.Keys[0].IsRateLimited || !status.Keys[1].IsRateLimited {
+		t.Errorf("per-key limited = %v/%v, want false/true", status.Keys[0].IsRateLimited, status.Keys[1].IsRateLimited)
+	}
+
+	// Once every key is limited, requests resume when the first limit lifts
+	rlt.HandleRateLimitError("backup", 10)
+	status = rlt.GetStatus()
+	if !status.IsRateLimited {
+		t.Fatal("status not rate limited with every key limited")
+	}
+	if status.RetryAfter != status.Keys[0].RetryAfter {
+		t.Errorf("RetryAfter = %v, want the backup key's %v", status.RetryAfter, status.Keys[0].RetryAfter)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"market-terminal/internal/config"
+	"market-terminal/internal/utils"
 )
 
 // UnifiedAdaptiveScheduler provides priority-based scheduling for ticker data collection
@@ -19,6 +20,10 @@ type UnifiedAdaptiveScheduler struct {
 	isTestingBranch       bool
 	endpointFetchTimes    map[string]float64 // endpoint -> last fetch time
 	endpointFetchLock     sync.RWMutex
+	idle                  bool // No app window focused recently: every ticker uses the low priority interval
+	ecoMultiplier         float64 // Eco mode (battery saving): intervals are this many times longer; 0 = off
+	clock                 utils.ClockSource // Source of "now" for fetch times and ticker active windows
+	chartRefreshRates     map[string]int // ticker -> refresh rate requested by its open chart window (ms)
 }
 
 // NewUnifiedAdaptiveScheduler creates a new unified adaptive scheduler
@@ -31,9 +36,15 @@ func NewUnifiedAdaptiveScheduler(settings *config.Settings, isTestingBranch bool
 		settings:           settings,
 		isTestingBranch:    isTestingBranch,
 		endpointFetchTimes: make(map[string]float64),
+		chartRefreshRates:  make(map[string]int),
 	}
 }
 
+// SetClock replaces the scheduler's clock (tests and simulated sessions; nil = follow the installed clock)
+func (uas *UnifiedAdaptiveScheduler) SetClock(clock utils.Clock) {
+	uas.clock.Set(clock)
+}
+
 // SetEnabledTickers sets the list of enabled tickers
 func (uas *UnifiedAdaptiveScheduler) SetEnabledTickers(tickers []string) {
 	uas.mu.Lock()
@@ -49,63 +60,150 @@ func (uas *UnifiedAdaptiveScheduler) SetSettings(settings *config.Settings) {
 	uas.settings = settings
 }
 
+// SetIdle switches idle mode on or off (see ActivityMonitor)
+func (uas *UnifiedAdaptiveScheduler) SetIdle(idle bool) {
+	uas.mu.Lock()
+	defer uas.mu.Unlock()
+	uas.idle = idle
+}
+
+// SetEco switches eco mode on (multiplier > 1 lengthens every interval) or off (multiplier 0)
+func (uas *UnifiedAdaptiveScheduler) SetEco(multiplier float64) {
+	uas.mu.Lock()
+	defer uas.mu.Unlock()
+	uas.ecoMultiplier = multiplier
+}
+
+// SetChartRefreshRate sets the refresh rate an open chart window requested for its ticker (ms); 0 clears it
+// It takes precedence over the priority matrix and the ticker's refresh_rate_ms while set
+func (uas *UnifiedAdaptiveScheduler) SetChartRefreshRate(ticker string, ms int) {
+	uas.mu.Lock()
+	defer uas.mu.Unlock()
+	if ms <= 0 {
+		delete(uas.chartRefreshRates, ticker)
+		return
+	}
+	uas.chartRefreshRates[ticker] = ms
+}
+
+// GetChartRefreshRate returns the refresh rate requested by a ticker's chart window (ms, 0 = none)
+func (uas *UnifiedAdaptiveScheduler) GetChartRefreshRate(ticker string) int {
+	uas.mu.RLock()
+	defer uas.mu.RUnlock()
+	return uas.chartRefreshRates[ticker]
+}
+
+// IsIdle reports whether idle mode is on
+func (uas *UnifiedAdaptiveScheduler) IsIdle() bool {
+	uas.mu.RLock()
+	defer uas.mu.RUnlock()
+	return uas.idle
+}
+
 // CalculateInterval calculates the polling interval for a ticker based on priority
 func (uas *UnifiedAdaptiveScheduler) CalculateInterval(ticker string, openCharts []interface{}) float64 {
 	uas.mu.RLock()
 	defer uas.mu.RUnlock()
 
-	// Determine priority based on ticker visibility
+	// Determine priority based on ticker visibility (everything is low priority while idle)
 	priority := uas.getTickerPriority(ticker, openCharts)
+	if uas.idle {
+		priority = 2
+	}
 	
 	// Get ticker count
 	tickerCount := len(uas.enabledTickers)
 
-	// Calculate interval based on priority and ticker count
-	var interval float64
+	interval, baseInterval, refreshRateMs := uas.intervalFor(ticker, priority, tickerCount)
 	var priorityName string
 	switch priority {
-	case 0: // High priority (in chart) - ALWAYS 1 second regardless of ticker count
-		interval = 1.0
+	case 0: // High priority (in chart)
 		priorityName = "HIGH"
 	case 1: // Medium priority (enabled but not in chart)
 		priorityName = "MEDIUM"
-		if tickerCount <= 5 {
-			interval = 6.0
-		} else if tickerCount <= 20 {
-			interval = 10.0
-		} else {
-			interval = 15.0
-		}
 	default: // Low priority
 		priorityName = "LOW"
-		if tickerCount <= 5 {
-			interval = 16.0
-		} else if tickerCount <= 20 {
-			interval = 22.0
-		} else {
-			interval = 30.0
-		}
 	}
 
-	baseInterval := interval // Store for logging
+	// Log interval calculation for debugging
+	log.Printf("[SCHEDULER] %s: priority=%s(%d), tickerCount=%d, baseInterval=%.1fs, refreshOverride=%dms, finalInterval=%.1fs, openCharts=%d, idle=%v, eco=%v",
+		ticker, priorityName, priority, tickerCount, baseInterval, refreshRateMs, interval, len(openCharts), uas.idle, uas.ecoMultiplier > 1)
 
-	// Check for per-ticker refresh rate override
-	refreshRateMs := uas.getTickerRefreshRate(ticker)
-	if refreshRateMs > 0 {
+	return interval
+}
+
+// EstimateInterval returns the interval CalculateInterval would give a ticker if tickerCount tickers were
+// enabled, without logging; a ticker that isn't enabled yet is estimated at the priority it would get once
+// enabled (medium unless configured otherwise)
+func (uas *UnifiedAdaptiveScheduler) EstimateInterval(ticker string, openCharts []interface{}, tickerCount int) float64 {
+	uas.mu.RLock()
+	defer uas.mu.RUnlock()
+
+	priority := uas.getTickerPriority(ticker, openCharts)
+	if priority == 2 && !uas.hasTickerConfig(ticker) {
+		priority = 1
+	}
+	if uas.idle {
+		priority = 2
+	}
+	interval, _, _ := uas.intervalFor(ticker, priority, tickerCount)
+	return interval
+}
+
+// intervalFor applies the interval matrix, chart/ticker refresh override, eco multiplier and rate limit floor
+// Returns the final interval, the matrix interval and the override in ms; the caller holds uas.mu
+func (uas *UnifiedAdaptiveScheduler) intervalFor(ticker string, priority int, tickerCount int) (float64, float64, int) {
+	// Calculate interval based on priority and ticker count (configurable interval matrix)
+	interval := uas.settings.GetPollingIntervals().IntervalFor(priority, tickerCount)
+	baseInterval := interval
+
+	// Check for a chart window's refresh rate, then the per-ticker refresh rate override
+	// Idle mode only lets an override slow a ticker down further
+	refreshRateMs := uas.chartRefreshRates[ticker]
+	if refreshRateMs == 0 {
+		refreshRateMs = uas.getTickerRefreshRate(ticker)
+	}
+	if refreshRateMs > 0 && (!uas.idle || float64(refreshRateMs)/1000.0 > interval) {
 		interval = float64(refreshRateMs) / 1000.0
 	}
 
+	// Eco mode stretches whatever interval applies (overrides included)
+	if uas.ecoMultiplier > 1 {
+		interval *= uas.ecoMultiplier
+	}
+
 	// Ensure minimum interval based on rate limits
 	minInterval := uas.rateLimitTracker.GetMinimumInterval(tickerCount)
 	if minInterval > 0 && interval < minInterval {
 		interval = minInterval
 	}
 
-	// Log interval calculation for debugging
-	log.Printf("[SCHEDULER] %s: priority=%s(%d), tickerCount=%d, baseInterval=%.1fs, refreshOverride=%dms, finalInterval=%.1fs, openCharts=%d",
-		ticker, priorityName, priority, tickerCount, baseInterval, refreshRateMs, interval, len(openCharts))
+	return interval, baseInterval, refreshRateMs
+}
 
-	return interval
+// GetEnabledTickers returns a copy of the enabled ticker list
+func (uas *UnifiedAdaptiveScheduler) GetEnabledTickers() []string {
+	uas.mu.RLock()
+	defer uas.mu.RUnlock()
+	tickers := make([]string, len(uas.enabledTickers))
+	copy(tickers, uas.enabledTickers)
+	return tickers
+}
+
+// hasTickerConfig reports whether the ticker has an entry in ticker_configs; the caller holds uas.mu
+func (uas *UnifiedAdaptiveScheduler) hasTickerConfig(ticker string) bool {
+	if uas.settings == nil || uas.settings.TickerConfigs == nil {
+		return false
+	}
+	_, exists := uas.settings.TickerConfigs[ticker]
+	return exists
+}
+
+// GetTickerPriority returns a ticker's priority (0=high, 1=medium, 2=low), ignoring idle mode
+func (uas *UnifiedAdaptiveScheduler) GetTickerPriority(ticker string, openCharts []interface{}) int {
+	uas.mu.RLock()
+	defer uas.mu.RUnlock()
+	return uas.getTickerPriority(ticker, openCharts)
 }
 
 // getTickerPriority determines the priority of a ticker (0=high, 1=medium, 2=low)
@@ -174,12 +272,27 @@ func (uas *UnifiedAdaptiveScheduler) getTickerRefreshRate(ticker string) int {
 	return 0 // Default: use priority-based scheduling
 }
 
+// UntilTickerActive returns how long until a ticker's next active collection window opens
+// 0 = collecting now (no windows configured, or inside one); -1 = no window left today
+func (uas *UnifiedAdaptiveScheduler) UntilTickerActive(ticker string) time.Duration {
+	uas.mu.RLock()
+	defer uas.mu.RUnlock()
+	if uas.settings == nil || uas.settings.TickerConfigs == nil {
+		return 0
+	}
+	tickerConfig, exists := uas.settings.TickerConfigs[ticker]
+	if !exists {
+		return 0
+	}
+	return tickerConfig.UntilActive(uas.clock.Now().In(utils.GetMarketTimezone()))
+}
+
 // ShouldFetchTicker checks if a ticker should be fetched now
 func (uas *UnifiedAdaptiveScheduler) ShouldFetchTicker(ticker string, openCharts []interface{}) bool {
 	uas.mu.RLock()
 	defer uas.mu.RUnlock()
 
-	currentTime := time.Now().Unix()
+	currentTime := uas.clock.Now().Unix()
 	lastFetch := uas.lastFetchTimes[ticker]
 	interval := uas.CalculateInterval(ticker, openCharts)
 
@@ -202,7 +315,18 @@ func (uas *UnifiedAdaptiveScheduler) ShouldFetchTicker(ticker string, openCharts
 func (uas *UnifiedAdaptiveScheduler) RecordFetch(ticker string) {
 	uas.mu.Lock()
 	defer uas.mu.Unlock()
-	uas.lastFetchTimes[ticker] = float64(time.Now().Unix())
+	uas.lastFetchTimes[ticker] = float64(uas.clock.Now().Unix())
+}
+
+// RestoreFetchTimes seeds last-fetch times from a previous run (existing entries are kept)
+func (uas *UnifiedAdaptiveScheduler) RestoreFetchTimes(times map[string]float64) {
+	uas.mu.Lock()
+	defer uas.mu.Unlock()
+	for ticker, ts := range times {
+		if _, exists := uas.lastFetchTimes[ticker]; !exists {
+			uas.lastFetchTimes[ticker] = ts
+		}
+	}
 }
 
 // CanFetchEndpoint checks if an endpoint can be fetched now (per-endpoint throttling)
@@ -210,7 +334,7 @@ func (uas *UnifiedAdaptiveScheduler) CanFetchEndpoint(endpoint string) bool {
 	uas.endpointFetchLock.RLock()
 	defer uas.endpointFetchLock.RUnlock()
 
-	currentTime := time.Now().Unix()
+	currentTime := uas.clock.Now().Unix()
 	lastFetch := uas.endpointFetchTimes[endpoint]
 	timeSinceLastFetch := float64(currentTime) - lastFetch
 
@@ -223,7 +347,7 @@ func (uas *UnifiedAdaptiveScheduler) CanFetchEndpoint(endpoint string) bool {
 func (uas *UnifiedAdaptiveScheduler) RecordEndpointFetch(endpoint string) {
 	uas.endpointFetchLock.Lock()
 	defer uas.endpointFetchLock.Unlock()
-	uas.endpointFetchTimes[endpoint] = float64(time.Now().Unix())
+	uas.endpointFetchTimes[endpoint] = float64(uas.clock.Now().Unix())
 }
 
 // GetRateLimitTracker returns the rate limit tracker
@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/crash"
+	"market-terminal/internal/utils"
+)
+
+// SnapshotScheduler fires a capture callback at the configured market times on weekdays
+// A slot only fires within SnapshotGraceMinutes of its time, so starting the app mid-day
+// doesn't backfill the morning's snapshots with afternoon data
+type SnapshotScheduler struct {
+	mu          sync.Mutex
+	getSettings func() config.ChartSnapshotSettings
+	capture     func(marketDate time.Time, slot string)
+	fired       map[string]bool // "2006-01-02 HH:MM" slots already captured
+	debugPrint  func(string, string)
+	stopChan    chan struct{}
+	isRunning   bool
+}
+
+// NewSnapshotScheduler creates a snapshot scheduler
+// getSettings is read on every check, so saved settings apply without a restart
+func NewSnapshotScheduler(getSettings func() config.ChartSnapshotSettings, capture func(marketDate time.Time, slot string), debugPrint func(string, string)) *SnapshotScheduler {
+	return &SnapshotScheduler{
+		getSettings: getSettings,
+		capture:     capture,
+		fired:       make(map[string]bool),
+		debugPrint:  debugPrint,
+	}
+}
+
+// Start starts the scheduler loop
+func (ss *SnapshotScheduler) Start() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.isRunning {
+		return
+	}
+	ss.isRunning = true
+	ss.stopChan = make(chan struct{})
+	go ss.run(ss.stopChan)
+	ss.debugPrint("Chart snapshot scheduler started", "scheduler")
+}
+
+// Stop stops the scheduler loop
+func (ss *SnapshotScheduler) Stop() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if !ss.isRunning {
+		return
+	}
+	ss.isRunning = false
+	close(ss.stopChan)
+}
+
+// run checks the clock periodically
+func (ss *SnapshotScheduler) run(stopChan chan struct{}) {
+	ticker := time.NewTicker(time.Duration(config.SnapshotCheckIntervalSec) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ss.check()
+		case <-stopChan:
+			return
+		}
+	}
+}
+
+// check captures every due slot that hasn't fired today
+func (ss *SnapshotScheduler) check() {
+	settings := ss.getSettings()
+	if !settings.Enabled {
+		return
+	}
+	now := utils.NowMarketTime()
+	if utils.IsWeekend(now) {
+		return
+	}
+	marketDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, utils.GetMarketTimezone())
+	dateStr := marketDate.Format("2006-01-02")
+	minuteOfDay := now.Hour()*60 + now.Minute()
+
+	for _, value := range settings.Times {
+		slotMinute, err := config.ParseSnapshotTime(value)
+		if err != nil {
+			continue
+		}
+		if minuteOfDay < slotMinute || minuteOfDay >= slotMinute+config.SnapshotGraceMinutes {
+			continue
+		}
+		slot := fmt.Sprintf("%02d:%02d", slotMinute/60, slotMinute%60)
+		key := dateStr + " " + slot
+
+		ss.mu.Lock()
+		if ss.fired[key] {
+			ss.mu.Unlock()
+			continue
+		}
+		ss.fired[key] = true
+		for firedKey := range ss.fired {
+			if firedKey[:10] != dateStr {
+				delete(ss.fired, firedKey) // Forget previous days
+			}
+		}
+		ss.mu.Unlock()
+
+		ss.debugPrint(fmt.Sprintf("Chart snapshots: capturing %s %s", dateStr, slot), "scheduler")
+		ss.runCapture(marketDate, slot)
+	}
+}
+
+// runCapture runs the capture callback, reporting a panic instead of stopping the loop
+func (ss *SnapshotScheduler) runCapture(marketDate time.Time, slot string) {
+	defer crash.Recover("chart snapshots")
+	ss.capture(marketDate, slot)
+}
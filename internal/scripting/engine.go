@@ -0,0 +1,354 @@
+// Package scripting runs user Lua scripts from the config directory: alert() is the condition of alert rules
+// with condition: script, and columns() adds derived columns to each row on flush.
+//
+// Scripts run in a sandbox: only the base (without file loading or require), string, table and math libraries
+// are available, each call is stopped after the configured CPU time or once it allocates ScriptMaxCallAllocMB
+// (64 MB), the call depth and value stack are fixed, string.rep, string.format and table.concat refuse results
+// over ScriptMaxStringBytes (1 MB), and a script that keeps failing is disabled until the scripts are reloaded.
+//
+//	-- <config dir>/scripts/vwap_gap.lua
+//	fields = {"spot", "spot_vwap"}   -- columns alert() reads when a rule is back-tested
+//	function columns(ticker, row)      -- extra columns, stored with the row
+//	  return { vwap_gap = row.spot - row.spot_vwap }
+//	end
+//	function alert(ticker, row)        -- fires when this turns true
+//	  return row.spot > row.spot_vwap + 10
+//	end
+package scripting
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"market-terminal/internal/config"
+)
+
+// Status describes a loaded script and how its calls went
+type Status struct {
+	Name              string   `json:"name"` // File name without .lua (alert rules refer to it)
+	Path              string   `json:"path"`
+	Columns           bool     `json:"columns"` // Defines columns(ticker, row)
+	Alert             bool     `json:"alert"`   // Defines alert(ticker, row)
+	Fields            []string `json:"fields"`
+	Calls             int64    `json:"calls"`
+	Errors            int64    `json:"errors"`
+	ConsecutiveErrors int      `json:"consecutive_errors"`
+	Disabled          bool     `json:"disabled"` // Failed to load or failed ScriptMaxFailures calls in a row
+	LastError         string   `json:"last_error"`
+	AvgMs             float64  `json:"avg_ms"`
+}
+
+// script is one loaded file with its own Lua state (globals persist between calls, e.g. a previous value)
+type script struct {
+	mu      sync.Mutex
+	source  string
+	state   *lua.LState
+	totalMs float64
+	status  Status
+}
+
+// Engine loads the scripts directory and runs the scripts
+type Engine struct {
+	mu         sync.RWMutex
+	dir        string
+	timeout    time.Duration
+	scripts    map[string]*script
+	debugPrint func(string, string)
+}
+
+var (
+	columnNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,63}$`)
+	scriptNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// NewEngine creates an engine for a scripts directory (nothing is loaded until Load)
+func NewEngine(dir string, debugPrint func(string, string)) *Engine {
+	return &Engine{
+		dir:        dir,
+		timeout:    time.Duration(config.DefaultScriptTimeoutMs) * time.Millisecond,
+		scripts:    make(map[string]*script),
+		debugPrint: debugPrint,
+	}
+}
+
+// Dir returns the scripts directory
+func (e *Engine) Dir() string {
+	return e.dir
+}
+
+// SetTimeoutMs sets the CPU time one call may take
+func (e *Engine) SetTimeoutMs(ms int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.timeout = time.Duration(ms) * time.Millisecond
+}
+
+// Load (re)loads every .lua file of the directory, replacing the loaded scripts and their state
+// A script that fails to load is listed as disabled with its error; a missing directory means no scripts
+func (e *Engine) Load() error {
+	entries, err := os.ReadDir(e.dir)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read scripts directory %s: %w", e.dir, err)
+	}
+
+	scripts := make(map[string]*script)
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".lua")
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".lua") || !scriptNamePattern.MatchString(name) {
+			continue
+		}
+		s := &script{status: Status{Name: name, Path: filepath.Join(e.dir, entry.Name()), Fields: []string{}}}
+		if err := s.load(); err != nil {
+			s.status.Disabled = true
+			s.status.LastError = err.Error()
+			e.debugPrint(fmt.Sprintf("Script %s not loaded: %v", name, err), "error")
+		}
+		scripts[name] = s
+	}
+
+	e.mu.Lock()
+	old := e.scripts
+	e.scripts = scripts
+	e.mu.Unlock()
+	for _, s := range old {
+		s.close()
+	}
+	e.debugPrint(fmt.Sprintf("Loaded %d scripts from %s", len(scripts), e.dir), "app")
+	return nil
+}
+
+// Close releases every script's Lua state
+func (e *Engine) Close() {
+	e.mu.Lock()
+	old := e.scripts
+	e.scripts = make(map[string]*script)
+	e.mu.Unlock()
+	for _, s := range old {
+		s.close()
+	}
+}
+
+// Status returns every script, sorted by name
+func (e *Engine) Status() []Status {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	statuses := make([]Status, 0, len(e.scripts))
+	for _, s := range e.scripts {
+		s.mu.Lock()
+		statuses = append(statuses, s.status)
+		s.mu.Unlock()
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// Fields returns the columns a script lists in its fields global (implements alerts.ScriptRunner)
+func (e *Engine) Fields(name string) []string {
+	e.mu.RLock()
+	s := e.scripts[name]
+	e.mu.RUnlock()
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.status.Fields...)
+}
+
+// Condition runs a script's alert(ticker, row) and reports whether it returned a true value
+// (implements alerts.ScriptRunner)
+func (e *Engine) Condition(name, ticker string, timestamp float64, row map[string]float64) (bool, error) {
+	e.mu.RLock()
+	s := e.scripts[name]
+	timeout := e.timeout
+	e.mu.RUnlock()
+	if s == nil {
+		return false, fmt.Errorf("no script %q in %s", name, e.dir)
+	}
+
+	result, err := s.call("alert", ticker, timestamp, row, timeout, e.debugPrint)
+	if err != nil {
+		return false, err
+	}
+	return lua.LVAsBool(result), nil
+}
+
+// Columns runs every script's columns(ticker, row) and returns the columns they add (the database writer calls
+// this on flush). Names must be lowercase column names and values finite numbers; a column already in the row
+// is never replaced
+func (e *Engine) Columns(ticker string, timestamp float64, row map[string]float64) map[string]float64 {
+	e.mu.RLock()
+	scripts := make([]*script, 0, len(e.scripts))
+	for _, s := range e.scripts {
+		if s.status.Columns {
+			scripts = append(scripts, s)
+		}
+	}
+	timeout := e.timeout
+	e.mu.RUnlock()
+	sort.Slice(scripts, func(i, j int) bool { return scripts[i].status.Name < scripts[j].status.Name })
+
+	columns := make(map[string]float64)
+	for _, s := range scripts {
+		result, err := s.call("columns", ticker, timestamp, row, timeout, e.debugPrint)
+		if err != nil {
+			continue
+		}
+		table, ok := result.(*lua.LTable)
+		if !ok {
+			continue
+		}
+		added := 0
+		table.ForEach(func(key, value lua.LValue) {
+			name, nameOK := key.(lua.LString)
+			number, numberOK := value.(lua.LNumber)
+			if !nameOK || !numberOK || added >= config.ScriptMaxColumns || !columnNamePattern.MatchString(string(name)) {
+				return
+			}
+			v := float64(number)
+			if _, exists := row[string(name)]; exists || math.IsNaN(v) || math.IsInf(v, 0) {
+				return
+			}
+			if _, exists := columns[string(name)]; exists {
+				return
+			}
+			columns[string(name)] = v
+			added++
+		})
+	}
+	return columns
+}
+
+// load reads and runs the file in a fresh sandboxed state, then records which functions and fields it defines
+func (s *script) load() error {
+	info, err := os.Stat(s.status.Path)
+	if err != nil {
+		return err
+	}
+	if info.Size() > config.ScriptMaxFileBytes {
+		return fmt.Errorf("%s is larger than %d bytes", s.status.Path, config.ScriptMaxFileBytes)
+	}
+	data, err := os.ReadFile(s.status.Path)
+	if err != nil {
+		return err
+	}
+	s.source = string(data)
+	if err := s.reset(); err != nil {
+		return err
+	}
+
+	s.status.Columns = s.state.GetGlobal("columns").Type() == lua.LTFunction
+	s.status.Alert = s.state.GetGlobal("alert").Type() == lua.LTFunction
+	if !s.status.Columns && !s.status.Alert {
+		return fmt.Errorf("defines neither columns(ticker, row) nor alert(ticker, row)")
+	}
+	if fields, ok := s.state.GetGlobal("fields").(*lua.LTable); ok {
+		fields.ForEach(func(_, value lua.LValue) {
+			if name, ok := value.(lua.LString); ok && columnNamePattern.MatchString(string(name)) {
+				s.status.Fields = append(s.status.Fields, string(name))
+			}
+		})
+	}
+	return nil
+}
+
+// reset replaces the script's state with a fresh one running the source (also after a timeout, which can leave
+// the old state mid-call)
+func (s *script) reset() error {
+	s.close()
+	state := newSandbox()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(config.ScriptMaxTimeoutMs)*time.Millisecond)
+	state.SetContext(ctx)
+	watch := watchAllocations(cancel)
+	err := state.DoString(s.source)
+	overAlloc := watch.stop()
+	state.RemoveContext()
+	cancel()
+	if overAlloc {
+		err = fmt.Errorf("loading allocated more than %d MB", config.ScriptMaxCallAllocMB)
+	}
+	if err != nil {
+		state.Close()
+		return err
+	}
+	s.state = state
+	return nil
+}
+
+func (s *script) close() {
+	if s.state != nil {
+		s.state.Close()
+		s.state = nil
+	}
+}
+
+// call runs one of the script's functions with (ticker, row) under the timeout and records the outcome
+func (s *script) call(function, ticker string, timestamp float64, row map[string]float64, timeout time.Duration, debugPrint func(string, string)) (lua.LValue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status.Disabled || s.state == nil {
+		return lua.LNil, fmt.Errorf("script %s is disabled: %s", s.status.Name, s.status.LastError)
+	}
+
+	L := s.state
+	fn := L.GetGlobal(function)
+	if fn.Type() != lua.LTFunction {
+		return lua.LNil, fmt.Errorf("script %s has no %s(ticker, row) function", s.status.Name, function)
+	}
+	rowTable := L.CreateTable(0, len(row)+1)
+	for column, value := range row {
+		if !math.IsNaN(value) {
+			rowTable.RawSetString(column, lua.LNumber(value))
+		}
+	}
+	rowTable.RawSetString("timestamp", lua.LNumber(timestamp))
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	L.SetContext(ctx)
+	watch := watchAllocations(cancel)
+	start := time.Now()
+	err := L.CallByParam(lua.P{Fn: fn, NRet: 1, Protect: true}, lua.LString(ticker), rowTable)
+	elapsedMs := float64(time.Since(start).Microseconds()) / 1000
+	overAlloc := watch.stop()
+	L.RemoveContext()
+	timedOut := ctx.Err() != nil
+	cancel()
+
+	s.status.Calls++
+	s.totalMs += elapsedMs
+	s.status.AvgMs = s.totalMs / float64(s.status.Calls)
+	if err != nil {
+		if timedOut {
+			err = fmt.Errorf("%s() took longer than %v", function, timeout)
+			if overAlloc {
+				err = fmt.Errorf("%s() allocated more than %d MB", function, config.ScriptMaxCallAllocMB)
+			}
+			// Reset also drops whatever the call built up in globals
+			if resetErr := s.reset(); resetErr != nil {
+				s.status.Disabled = true
+			}
+		}
+		s.status.Errors++
+		s.status.ConsecutiveErrors++
+		s.status.LastError = err.Error()
+		if s.status.ConsecutiveErrors >= config.ScriptMaxFailures {
+			s.status.Disabled = true
+			debugPrint(fmt.Sprintf("Script %s disabled after %d failed calls: %v", s.status.Name, s.status.ConsecutiveErrors, err), "error")
+		}
+		return lua.LNil, err
+	}
+	s.status.ConsecutiveErrors = 0
+	result := L.Get(-1)
+	L.Pop(1)
+	return result, nil
+}
@@ -0,0 +1,221 @@
+package scripting
+
+import (
+	"context"
+	"runtime/metrics"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"market-terminal/internal/config"
+)
+
+// removedGlobals are base library functions scripts may not use (file access, loading code, the module system)
+var removedGlobals = []string{"dofile", "loadfile", "load", "loadstring", "require", "module", "collectgarbage",
+	"getfenv", "setfenv", "newproxy", "_printregs", "print"}
+
+// newSandbox creates a Lua state with fixed stack limits and only the base, table, string and math libraries
+func newSandbox() *lua.LState {
+	L := lua.NewState(lua.Options{
+		SkipOpenLibs:        true,
+		CallStackSize:       config.ScriptCallStackSize,
+		RegistrySize:        config.ScriptRegistrySize,
+		MinimizeStackMemory: true,
+	})
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		L.Push(L.NewFunction(lib.open))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	for _, name := range removedGlobals {
+		L.SetGlobal(name, lua.LNil)
+	}
+
+	// The library calls that can build an arbitrarily large string at once are capped at ScriptMaxStringBytes
+	// (the .. operator runs inside the VM and is bounded by the call's allocation limit instead, see watchAllocations)
+	if stringLib, ok := L.GetGlobal(lua.StringLibName).(*lua.LTable); ok {
+		stringLib.RawSetString("rep", L.NewFunction(cappedRep))
+		capLibFunction(L, stringLib, "format", formatSizeBound)
+	}
+	if tableLib, ok := L.GetGlobal(lua.TabLibName).(*lua.LTable); ok {
+		capLibFunction(L, tableLib, "concat", concatSizeBound)
+	}
+	return L
+}
+
+// capLibFunction wraps a library function so it refuses calls whose result could exceed ScriptMaxStringBytes
+// bound returns an upper bound on the result size from the call's arguments, before anything is allocated
+func capLibFunction(L *lua.LState, lib *lua.LTable, name string, bound func(L *lua.LState) int) {
+	original, ok := lib.RawGetString(name).(*lua.LFunction)
+	if !ok || original.GFunction == nil {
+		return
+	}
+	lib.RawSetString(name, L.NewFunction(func(L *lua.LState) int {
+		if bound(L) > config.ScriptMaxStringBytes {
+			L.RaiseError("%s result could be larger than %d bytes", name, config.ScriptMaxStringBytes)
+			return 0
+		}
+		return original.GFunction(L)
+	}))
+}
+
+// Upper bounds on a number converted to a string: by a string.format directive (%f of the largest float is ~320
+// digits) and by concatenation (%.14g)
+const (
+	formattedNumberBytes    = 512
+	concatenatedNumberBytes = 32
+)
+
+// formatSizeBound bounds the string.format result: the format itself, plus for each directive its width and
+// precision and the largest argument (an argument can be used by several directives, e.g. %[1]s%[1]s)
+func formatSizeBound(L *lua.LState) int {
+	format := L.CheckString(1)
+	largest := 0
+	for i := 2; i <= L.GetTop(); i++ {
+		size := formattedNumberBytes
+		if s, ok := L.Get(i).(lua.LString); ok && len(s) > size {
+			size = len(s)
+		}
+		if size > largest {
+			largest = size
+		}
+	}
+
+	bound := len(format)
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		if i < len(format) && format[i] == '%' {
+			continue
+		}
+		// Flags, argument index, width and precision; every number among them is counted (an index only adds slack)
+		for i < len(format) && strings.IndexByte("-+ #[].0123456789", format[i]) >= 0 {
+			start, n := i, 0
+			for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+				if n <= config.ScriptMaxStringBytes {
+					n = n*10 + int(format[i]-'0')
+				}
+				i++
+			}
+			if i == start {
+				i++
+			}
+			bound += n
+		}
+		bound += largest
+		if bound > config.ScriptMaxStringBytes {
+			return bound
+		}
+	}
+	return bound
+}
+
+// concatSizeBound is the table.concat result size: the elements in range plus a separator between each
+func concatSizeBound(L *lua.LState) int {
+	tbl := L.CheckTable(1)
+	sep := L.OptString(2, "")
+	first := L.OptInt(3, 1)
+	last := L.OptInt(4, tbl.Len())
+	if first < 1 {
+		first = 1
+	}
+	if last > tbl.Len() {
+		last = tbl.Len()
+	}
+	bound := 0
+	for i := first; i <= last; i++ {
+		switch value := tbl.RawGetInt(i).(type) {
+		case lua.LString:
+			bound += len(value)
+		case lua.LNumber:
+			bound += concatenatedNumberBytes
+		}
+		if i < last {
+			bound += len(sep)
+		}
+		if bound > config.ScriptMaxStringBytes {
+			return bound
+		}
+	}
+	return bound
+}
+
+// cappedRep is string.rep refusing results over ScriptMaxStringBytes
+func cappedRep(L *lua.LState) int {
+	s := L.CheckString(1)
+	n := L.CheckInt(2)
+	if n < 0 {
+		n = 0
+	}
+	// Compare by division: len(s)*n overflows for a large n
+	if len(s) > 0 && n > config.ScriptMaxStringBytes/len(s) {
+		L.RaiseError("string.rep result larger than %d bytes", config.ScriptMaxStringBytes)
+		return 0
+	}
+	result := make([]byte, 0, len(s)*n)
+	for i := 0; i < n; i++ {
+		result = append(result, s...)
+	}
+	L.Push(lua.LString(result))
+	return 1
+}
+
+// allocWatch stops a script call that allocates more than ScriptMaxCallAllocMB by cancelling its context
+// String concatenation (..) happens inside the VM where no library wrapper sees it; counting the bytes allocated
+// while the call runs stops a doubling loop within a few steps of the limit. The count is process-wide, so it can
+// only overestimate the script's share
+type allocWatch struct {
+	exceeded atomic.Bool
+	done     chan struct{}
+}
+
+// watchAllocations starts checking the call's allocations every ScriptAllocCheckMs; stop it when the call returns
+func watchAllocations(cancel context.CancelFunc) *allocWatch {
+	w := &allocWatch{done: make(chan struct{})}
+	start := heapAllocatedBytes()
+	go func() {
+		ticker := time.NewTicker(config.ScriptAllocCheckMs * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if heapAllocatedBytes()-start > config.ScriptMaxCallAllocMB<<20 {
+					w.exceeded.Store(true)
+					cancel()
+					return
+				}
+			case <-w.done:
+				return
+			}
+		}
+	}()
+	return w
+}
+
+// stop stops the watch and reports whether the call went over the limit
+func (w *allocWatch) stop() bool {
+	close(w.done)
+	return w.exceeded.Load()
+}
+
+// heapAllocatedBytes returns the bytes allocated on the heap since the process started
+func heapAllocatedBytes() uint64 {
+	sample := []metrics.Sample{{Name: "/gc/heap/allocs:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
@@ -0,0 +1,163 @@
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Progress is the state of a shutdown, shown in the shutdown splash window
+type Progress struct {
+	Started    bool    `json:"started"`
+	Step       string  `json:"step"`       // Name of the running step
+	StepIndex  int     `json:"step_index"` // 1-based
+	Steps      int     `json:"steps"`
+	Flushed    int     `json:"flushed"`  // Tickers flushed so far by the final flush
+	ToFlush    int     `json:"to_flush"` // Tickers that had pending writes when the final flush started
+	ElapsedSec float64 `json:"elapsed_sec"`
+	BudgetSec  float64 `json:"budget_sec"`
+	TimedOut   bool    `json:"timed_out"` // The budget ran out and the remaining work was aborted
+	Done       bool    `json:"done"`
+}
+
+// Step is one stage of a shutdown
+// Run gets a context cancelled when the budget runs out and reports progress through report(done, total)
+// Once the budget is spent only Always steps still run (they release files and connections), each bounded by the grace period
+type Step struct {
+	Name   string
+	Always bool
+	Run    func(ctx context.Context, report func(done, total int)) error
+}
+
+// Coordinator runs the shutdown steps once, in order, within a time budget
+// A step still running grace after the budget ran out is abandoned (left to finish or die with the process)
+type Coordinator struct {
+	budget     time.Duration
+	grace      time.Duration
+	debugPrint func(string, string)
+	mu         sync.Mutex
+	progress   Progress
+	start      time.Time
+	once       sync.Once
+}
+
+// NewCoordinator creates a coordinator with a total time budget and a grace period for aborting steps
+func NewCoordinator(budget, grace time.Duration, debugPrint func(string, string)) *Coordinator {
+	return &Coordinator{
+		budget:     budget,
+		grace:      grace,
+		debugPrint: debugPrint,
+		progress:   Progress{BudgetSec: budget.Seconds()},
+	}
+}
+
+// Run runs the steps the first time it is called; later calls wait for that run to finish and return
+// Returns whether everything finished within the budget
+func (c *Coordinator) Run(steps []Step) bool {
+	c.once.Do(func() {
+		c.run(steps)
+	})
+	return !c.Progress().TimedOut
+}
+
+// Started reports whether Run has been called
+func (c *Coordinator) Started() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.progress.Started
+}
+
+// Progress returns the current progress
+func (c *Coordinator) Progress() Progress {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	progress := c.progress
+	if progress.Started && !progress.Done {
+		progress.ElapsedSec = time.Since(c.start).Seconds()
+	}
+	return progress
+}
+
+func (c *Coordinator) run(steps []Step) {
+	c.mu.Lock()
+	c.start = time.Now()
+	c.progress.Started = true
+	c.progress.Steps = len(steps)
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.budget)
+	defer cancel()
+
+	for i, step := range steps {
+		if ctx.Err() != nil {
+			c.markTimedOut()
+			if !step.Always {
+				c.debugPrint(fmt.Sprintf("Shutdown: Budget of %.0fs spent - skipping %q", c.budget.Seconds(), step.Name), "error")
+				continue
+			}
+		}
+
+		c.mu.Lock()
+		c.progress.Step = step.Name
+		c.progress.StepIndex = i + 1
+		c.mu.Unlock()
+		c.debugPrint(fmt.Sprintf("Shutdown: %s (%d/%d)", step.Name, i+1, len(steps)), "system")
+
+		if ctx.Err() == nil {
+			c.runStep(ctx, step)
+			continue
+		}
+		// Late Always step: it gets the grace period on its own
+		graceCtx, graceCancel := context.WithTimeout(context.Background(), c.grace)
+		c.runStep(graceCtx, step)
+		graceCancel()
+	}
+
+	c.mu.Lock()
+	c.progress.Done = true
+	c.progress.ElapsedSec = time.Since(c.start).Seconds()
+	timedOut := c.progress.TimedOut
+	c.mu.Unlock()
+	if timedOut {
+		c.debugPrint(fmt.Sprintf("Shutdown: Aborted after the %.0fs budget (some pending work was not finished)", c.budget.Seconds()), "error")
+	} else {
+		c.debugPrint(fmt.Sprintf("Shutdown: Completed in %.1fs", time.Since(c.start).Seconds()), "system")
+	}
+}
+
+// runStep runs one step, abandoning it when it outlives its context by the grace period
+func (c *Coordinator) runStep(ctx context.Context, step Step) {
+	report := func(done, total int) {
+		c.mu.Lock()
+		c.progress.Flushed, c.progress.ToFlush = done, total
+		c.mu.Unlock()
+	}
+
+	finished := make(chan error, 1)
+	go func() {
+		finished <- step.Run(ctx, report)
+	}()
+
+	var err error
+	select {
+	case err = <-finished:
+	case <-ctx.Done():
+		c.markTimedOut()
+		select {
+		case err = <-finished:
+		case <-time.After(c.grace):
+			c.debugPrint(fmt.Sprintf("Shutdown: %q did not stop within %.0fs of the deadline - abandoned", step.Name, c.grace.Seconds()), "error")
+			return
+		}
+	}
+	if err != nil {
+		c.debugPrint(fmt.Sprintf("Shutdown: Warning - %s: %v", step.Name, err), "error")
+	}
+}
+
+func (c *Coordinator) markTimedOut() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.progress.TimedOut = true
+}
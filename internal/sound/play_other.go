@@ -0,0 +1,33 @@
+//go:build !windows
+
+package sound
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+)
+
+// players are tried in order (afplay ships with macOS; paplay/aplay cover PulseAudio/PipeWire and ALSA)
+func players() []string {
+	if runtime.GOOS == "darwin" {
+		return []string{"afplay"}
+	}
+	return []string{"paplay", "pw-play", "aplay"}
+}
+
+// play starts the first available command-line player on the file (not waited for)
+func play(path string) error {
+	for _, player := range players() {
+		if _, err := exec.LookPath(player); err != nil {
+			continue
+		}
+		cmd := exec.Command(player, path)
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		go cmd.Wait()
+		return nil
+	}
+	return errors.New("no audio player found (install pulseaudio-utils or alsa-utils)")
+}
@@ -0,0 +1,32 @@
+//go:build windows
+
+package sound
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	winmm         = syscall.NewLazyDLL("winmm.dll")
+	procPlaySound = winmm.NewProc("PlaySoundW")
+)
+
+const (
+	sndAsync     = 0x0001
+	sndNoDefault = 0x0002
+	sndFilename  = 0x00020000
+)
+
+// play starts the file with PlaySound (asynchronous; a new sound replaces one still playing)
+func play(path string) error {
+	name, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return err
+	}
+	if ok, _, callErr := procPlaySound.Call(uintptr(unsafe.Pointer(name)), 0, sndFilename|sndAsync|sndNoDefault); ok == 0 {
+		return fmt.Errorf("PlaySound failed for %s: %v", path, callErr)
+	}
+	return nil
+}
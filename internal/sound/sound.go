@@ -0,0 +1,128 @@
+// Package sound provides the alert sounds: bundled tones are synthesized as WAV data (no asset files), user
+// sounds are .wav files, and Play sends either to the OS audio player
+package sound
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"market-terminal/internal/config"
+)
+
+const sampleRate = 44100
+
+// note is one tone of a bundled sound (frequency 0 = silence)
+type note struct {
+	freq  float64
+	ms    int
+	decay bool // Fade out over the note (bell-like) instead of a flat tone
+}
+
+var bundled = map[string][]note{
+	"chime": {{880, 160, true}, {1320, 320, true}},
+	"beep":  {{1000, 150, false}},
+	"ding":  {{1568, 500, true}},
+	"alarm": {{880, 120, false}, {0, 60, false}, {660, 120, false}, {0, 60, false}, {880, 120, false}, {0, 60, false}, {660, 120, false}},
+}
+
+var (
+	cacheMu  sync.Mutex
+	cacheDir string
+)
+
+// WAV returns a sound's WAV data: a bundled sound is synthesized, anything else is read as a .wav file
+func WAV(name string) ([]byte, error) {
+	notes, ok := bundled[name]
+	if !ok {
+		if err := config.ValidateAlertSound(name); err != nil {
+			return nil, err
+		}
+		return os.ReadFile(name)
+	}
+
+	samples := make([]int16, 0)
+	for _, n := range notes {
+		count := sampleRate * n.ms / 1000
+		fade := sampleRate * 5 / 1000 // 5ms ramps avoid clicks at the note edges
+		for i := 0; i < count; i++ {
+			gain := 0.5
+			if n.decay {
+				gain *= math.Exp(-3 * float64(i) / float64(count))
+			}
+			if i < fade {
+				gain *= float64(i) / float64(fade)
+			} else if count-i < fade {
+				gain *= float64(count-i) / float64(fade)
+			}
+			samples = append(samples, int16(gain*math.MaxInt16*math.Sin(2*math.Pi*n.freq*float64(i)/sampleRate)))
+		}
+	}
+
+	// 16-bit mono PCM
+	dataSize := uint32(len(samples) * 2)
+	header := struct {
+		Riff          [4]byte
+		Size          uint32
+		Wave, Fmt     [4]byte
+		FmtSize       uint32
+		Format        uint16
+		Channels      uint16
+		SampleRate    uint32
+		ByteRate      uint32
+		BlockAlign    uint16
+		BitsPerSample uint16
+		Data          [4]byte
+		DataSize      uint32
+	}{
+		[4]byte{'R', 'I', 'F', 'F'}, 36 + dataSize, [4]byte{'W', 'A', 'V', 'E'}, [4]byte{'f', 'm', 't', ' '},
+		16, 1, 1, sampleRate, sampleRate * 2, 2, 16, [4]byte{'d', 'a', 't', 'a'}, dataSize,
+	}
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, header)
+	binary.Write(&buf, binary.LittleEndian, samples)
+	return buf.Bytes(), nil
+}
+
+// Play plays a sound through the OS without waiting for it to finish
+func Play(name string) error {
+	path := name
+	if config.IsBundledAlertSound(name) {
+		var err error
+		if path, err = bundledFile(name); err != nil {
+			return err
+		}
+	} else if err := config.ValidateAlertSound(name); err != nil {
+		return err
+	}
+	return play(path)
+}
+
+// bundledFile writes a bundled sound to a temp file once (OS players take a path)
+func bundledFile(name string) (string, error) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if cacheDir == "" {
+		dir, err := os.MkdirTemp("", "market-terminal-sounds-")
+		if err != nil {
+			return "", fmt.Errorf("failed to create sound directory: %w", err)
+		}
+		cacheDir = dir
+	}
+	path := filepath.Join(cacheDir, name+".wav")
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	data, err := WAV(name)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,236 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// ExportStatus reports how the OTLP exporter is keeping up
+type ExportStatus struct {
+	Enabled    bool    `json:"enabled"`
+	Endpoint   string  `json:"endpoint"`
+	Exported   int64   `json:"exported"` // Spans accepted by the collector
+	Dropped    int64   `json:"dropped"`  // Spans dropped because the queue was full
+	Failed     int64   `json:"failed"`   // Spans in requests the collector rejected
+	LastError  string  `json:"last_error"`
+	LastSentAt float64 `json:"last_sent_at"` // Unix seconds (0 if nothing sent yet)
+}
+
+// OTLPExporter sends finished spans to an OpenTelemetry collector as OTLP/HTTP JSON
+// Export never blocks collection: spans are queued and sent in batches from a background goroutine
+type OTLPExporter struct {
+	url         string
+	headers     map[string]string
+	serviceName string
+	httpClient  *http.Client
+	debugPrint  func(string, string)
+
+	mu       sync.Mutex
+	queue    []SpanData
+	status   ExportStatus
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewOTLPExporter creates an exporter from settings and starts its send loop
+func NewOTLPExporter(cfg config.TracingSettings, debugPrint func(string, string)) (*OTLPExporter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	e := &OTLPExporter{
+		url:         cfg.TracesURL(),
+		headers:     cfg.Headers,
+		serviceName: cfg.ServiceNameOrDefault(),
+		httpClient:  &http.Client{Timeout: time.Duration(config.TraceExportTimeoutSec) * time.Second},
+		debugPrint:  debugPrint,
+		stopChan:    make(chan struct{}),
+	}
+	e.status = ExportStatus{Enabled: true, Endpoint: e.url}
+	e.wg.Add(1)
+	go e.run()
+
+	debugPrint(fmt.Sprintf("Trace export started: %s", e.url), "system")
+	return e, nil
+}
+
+// Export queues a finished span (the oldest queued span is dropped when the queue is full)
+func (e *OTLPExporter) Export(span SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.queue) >= config.TraceExportQueueSize {
+		e.queue = e.queue[1:]
+		e.status.Dropped++
+	}
+	e.queue = append(e.queue, span)
+}
+
+// Status returns the export counters
+func (e *OTLPExporter) Status() ExportStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.status
+}
+
+// Stop stops the send loop after sending what is still queued
+func (e *OTLPExporter) Stop() {
+	close(e.stopChan)
+	e.wg.Wait()
+}
+
+// run sends queued spans every TraceExportIntervalSec until stopped
+func (e *OTLPExporter) run() {
+	defer e.wg.Done()
+	ticker := time.NewTicker(time.Duration(config.TraceExportIntervalSec) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			e.sendQueued()
+		case <-e.stopChan:
+			e.sendQueued()
+			return
+		}
+	}
+}
+
+// sendQueued sends the queue in batches of up to TraceExportBatchSize spans
+func (e *OTLPExporter) sendQueued() {
+	for {
+		e.mu.Lock()
+		n := len(e.queue)
+		if n > config.TraceExportBatchSize {
+			n = config.TraceExportBatchSize
+		}
+		batch := append([]SpanData(nil), e.queue[:n]...)
+		e.queue = e.queue[n:]
+		e.mu.Unlock()
+		if len(batch) == 0 {
+			return
+		}
+
+		err := e.send(batch)
+
+		e.mu.Lock()
+		if err != nil {
+			e.status.Failed += int64(len(batch))
+			e.status.LastError = err.Error()
+		} else {
+			e.status.Exported += int64(len(batch))
+			e.status.LastSentAt = float64(time.Now().UnixNano()) / 1e9
+		}
+		e.mu.Unlock()
+
+		if err != nil {
+			e.debugPrint(fmt.Sprintf("Trace export: Dropped %d span(s): %v", len(batch), err), "error")
+			return // Retry with the next interval's spans rather than hammering a failing collector
+		}
+	}
+}
+
+// send POSTs one batch as an OTLP ExportTraceServiceRequest
+func (e *OTLPExporter) send(spans []SpanData) error {
+	body, err := json.Marshal(e.encode(spans))
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("collector returned %s: %s", resp.Status, bytes.TrimSpace(detail))
+	}
+	return nil
+}
+
+// OTLP JSON encoding (opentelemetry-proto, trace/v1); IDs are hex, times are nanosecond strings
+type otlpAttribute struct {
+	Key   string            `json:"key"`
+	Value map[string]string `json:"value"`
+}
+
+type otlpSpan struct {
+	TraceID           string                 `json:"traceId"`
+	SpanID            string                 `json:"spanId"`
+	ParentSpanID      string                 `json:"parentSpanId,omitempty"`
+	Name              string                 `json:"name"`
+	Kind              int                    `json:"kind"`
+	StartTimeUnixNano string                 `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string                 `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute        `json:"attributes,omitempty"`
+	Status            map[string]interface{} `json:"status,omitempty"`
+}
+
+func stringAttribute(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: map[string]string{"stringValue": value}}
+}
+
+// encode builds the request body for a batch of spans
+func (e *OTLPExporter) encode(spans []SpanData) map[string]interface{} {
+	encoded := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		attrs := make([]otlpAttribute, 0, len(span.Attrs)+1)
+		if span.Ticker != "" {
+			attrs = append(attrs, stringAttribute("ticker", span.Ticker))
+		}
+		keys := make([]string, 0, len(span.Attrs))
+		for key := range span.Attrs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			attrs = append(attrs, stringAttribute(key, span.Attrs[key]))
+		}
+
+		s := otlpSpan{
+			TraceID:           span.TraceID,
+			SpanID:            span.SpanID,
+			ParentSpanID:      span.ParentID,
+			Name:              span.Name,
+			Kind:              1, // SPAN_KIND_INTERNAL
+			StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+			Attributes:        attrs,
+		}
+		if span.Error != "" {
+			s.Status = map[string]interface{}{"code": 2, "message": span.Error} // STATUS_CODE_ERROR
+		}
+		encoded = append(encoded, s)
+	}
+
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": []otlpAttribute{stringAttribute("service.name", e.serviceName)},
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]string{"name": "market-terminal/collection"},
+						"spans": encoded,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,182 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Trace follows one scheduler wakeup through plan -> fetch -> aggregate -> enqueue -> write -> flush
+// Its ID is the correlation ID that appears in logs ("[trace 1a2b3c4d]") and in GetRecentTraces
+// All methods are safe on a nil *Trace, so untraced callers don't need to check
+type Trace struct {
+	ID      string   // 32 hex chars (OpenTelemetry trace ID)
+	Name    string   // What started the trace, e.g. "scheduler" or "fetch_now"
+	Tickers []string // Tickers in the batch
+	Start   time.Time
+
+	root   *Span
+	tracer *Tracer
+
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+// SpanData is a finished span
+type SpanData struct {
+	TraceID  string
+	SpanID   string
+	ParentID string // "" for the root span
+	Name     string
+	Ticker   string
+	Start    time.Time
+	End      time.Time
+	Attrs    map[string]string
+	Error    string
+}
+
+// Span is a span in progress; End records it on its trace
+type Span struct {
+	trace *Trace
+	data  SpanData
+	once  sync.Once
+}
+
+// newID returns n random bytes as hex
+func newID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ShortID returns the first 8 characters of the trace ID (used in log lines)
+func (t *Trace) ShortID() string {
+	if t == nil {
+		return "--------"
+	}
+	return t.ID[:8]
+}
+
+// LogPrefix returns "[trace xxxxxxxx] " for log lines ("" without a trace)
+func (t *Trace) LogPrefix() string {
+	if t == nil {
+		return ""
+	}
+	return "[trace " + t.ShortID() + "] "
+}
+
+// StartSpan starts a child span of the trace's root span
+func (t *Trace) StartSpan(name, ticker string) *Span {
+	if t == nil {
+		return nil
+	}
+	parent := ""
+	if t.root != nil {
+		parent = t.root.data.SpanID
+	}
+	return t.startSpan(name, ticker, parent, time.Now())
+}
+
+// StartSpanAt starts a child span with an explicit start time (e.g. when a job was queued)
+func (t *Trace) StartSpanAt(name, ticker string, start time.Time) *Span {
+	span := t.StartSpan(name, ticker)
+	if span != nil {
+		span.data.Start = start
+	}
+	return span
+}
+
+func (t *Trace) startSpan(name, ticker, parent string, start time.Time) *Span {
+	return &Span{
+		trace: t,
+		data: SpanData{
+			TraceID:  t.ID,
+			SpanID:   newID(8),
+			ParentID: parent,
+			Name:     name,
+			Ticker:   ticker,
+			Start:    start,
+		},
+	}
+}
+
+// SetAttr sets an attribute on the trace's root span
+func (t *Trace) SetAttr(key, value string) {
+	if t == nil {
+		return
+	}
+	t.root.SetAttr(key, value)
+}
+
+// End finishes the trace's root span (spans that finish later, such as flushes, are still recorded)
+func (t *Trace) End(err error) {
+	if t == nil || t.root == nil {
+		return
+	}
+	t.root.End(err)
+}
+
+// Spans returns the finished spans, in the order they ended
+func (t *Trace) Spans() []SpanData {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	spans := make([]SpanData, len(t.spans))
+	copy(spans, t.spans)
+	return spans
+}
+
+// record stores a finished span and hands it to the exporter
+func (t *Trace) record(data SpanData) {
+	t.mu.Lock()
+	t.spans = append(t.spans, data)
+	t.mu.Unlock()
+	if t.tracer != nil {
+		t.tracer.export(data)
+	}
+}
+
+// SetAttr sets a span attribute
+func (s *Span) SetAttr(key, value string) {
+	if s == nil {
+		return
+	}
+	if s.data.Attrs == nil {
+		s.data.Attrs = make(map[string]string)
+	}
+	s.data.Attrs[key] = value
+}
+
+// End finishes the span (err marks it failed); only the first call counts
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.once.Do(func() {
+		s.data.End = time.Now()
+		if err != nil {
+			s.data.Error = err.Error()
+		}
+		s.trace.record(s.data)
+	})
+}
+
+type contextKey struct{}
+
+// WithTrace returns a context carrying the trace
+func WithTrace(ctx context.Context, t *Trace) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the context's trace (nil if none)
+func FromContext(ctx context.Context) *Trace {
+	if ctx == nil {
+		return nil
+	}
+	t, _ := ctx.Value(contextKey{}).(*Trace)
+	return t
+}
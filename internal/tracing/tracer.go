@@ -0,0 +1,214 @@
+package tracing
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// TraceSummary is a trace as returned to the frontend (offsets relative to the trace start)
+type TraceSummary struct {
+	ID         string        `json:"id"`
+	Name       string        `json:"name"`
+	Tickers    []string      `json:"tickers"`
+	Start      float64       `json:"start"`       // Unix seconds
+	DurationMs float64       `json:"duration_ms"` // Trace start to the last finished span (includes the flush)
+	Error      bool          `json:"error"`       // Any span failed
+	Spans      []SpanSummary `json:"spans"`
+}
+
+// SpanSummary is one span of a TraceSummary
+type SpanSummary struct {
+	Name       string            `json:"name"`
+	Ticker     string            `json:"ticker,omitempty"`
+	OffsetMs   float64           `json:"offset_ms"` // Span start relative to the trace start
+	DurationMs float64           `json:"duration_ms"`
+	Attrs      map[string]string `json:"attrs,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// Tracer starts traces, keeps the most recent ones in memory and forwards finished spans to an exporter
+type Tracer struct {
+	mu       sync.Mutex
+	recent   []*Trace // Ring buffer
+	next     int
+	exporter *OTLPExporter
+	settings config.TracingSettings // Settings the exporter was created from
+}
+
+// NewTracer creates a tracer keeping the last capacity traces
+func NewTracer(capacity int) *Tracer {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Tracer{recent: make([]*Trace, capacity)}
+}
+
+// StartTrace starts a trace and its root span
+func (tr *Tracer) StartTrace(name string, tickers []string) *Trace {
+	t := &Trace{
+		ID:      newID(16),
+		Name:    name,
+		Tickers: append([]string(nil), tickers...),
+		Start:   time.Now(),
+		tracer:  tr,
+	}
+	t.root = t.startSpan(name, strings.Join(tickers, ","), "", t.Start)
+
+	tr.mu.Lock()
+	tr.recent[tr.next] = t
+	tr.next = (tr.next + 1) % len(tr.recent)
+	tr.mu.Unlock()
+	return t
+}
+
+// Configure starts, replaces or stops OTLP export from settings
+// Unchanged settings keep the running exporter (and its counters); a replaced one drains in the background
+func (tr *Tracer) Configure(cfg config.TracingSettings, debugPrint func(string, string)) error {
+	tr.mu.Lock()
+	unchanged := reflect.DeepEqual(tr.settings, cfg) && (tr.exporter != nil) == cfg.Enabled
+	tr.mu.Unlock()
+	if unchanged {
+		return nil
+	}
+
+	var exporter *OTLPExporter
+	if cfg.Enabled {
+		e, err := NewOTLPExporter(cfg, debugPrint)
+		if err != nil {
+			return fmt.Errorf("failed to start trace export: %w", err)
+		}
+		exporter = e
+	}
+
+	tr.mu.Lock()
+	old := tr.exporter
+	tr.exporter = exporter
+	tr.settings = cfg
+	tr.mu.Unlock()
+	if old != nil {
+		go old.Stop()
+	}
+	return nil
+}
+
+// Stop stops the exporter, sending any spans still queued
+func (tr *Tracer) Stop() {
+	tr.mu.Lock()
+	exporter := tr.exporter
+	tr.exporter = nil
+	tr.mu.Unlock()
+	if exporter != nil {
+		exporter.Stop()
+	}
+}
+
+// ExportStatus returns the exporter's counters (Enabled is false when export is off)
+func (tr *Tracer) ExportStatus() ExportStatus {
+	tr.mu.Lock()
+	exporter := tr.exporter
+	tr.mu.Unlock()
+	if exporter == nil {
+		return ExportStatus{}
+	}
+	return exporter.Status()
+}
+
+// export forwards a finished span to the exporter (if any)
+func (tr *Tracer) export(span SpanData) {
+	tr.mu.Lock()
+	exporter := tr.exporter
+	tr.mu.Unlock()
+	if exporter != nil {
+		exporter.Export(span)
+	}
+}
+
+// Recent returns up to limit recent traces, newest first
+// ticker "" = all traces, otherwise only traces that include the ticker
+func (tr *Tracer) Recent(ticker string, limit int) []TraceSummary {
+	tr.mu.Lock()
+	traces := make([]*Trace, 0, len(tr.recent))
+	for i := 1; i <= len(tr.recent); i++ {
+		t := tr.recent[(tr.next-i+len(tr.recent))%len(tr.recent)]
+		if t != nil {
+			traces = append(traces, t)
+		}
+	}
+	tr.mu.Unlock()
+
+	summaries := make([]TraceSummary, 0)
+	for _, t := range traces {
+		if limit > 0 && len(summaries) >= limit {
+			break
+		}
+		if ticker != "" && !containsTicker(t.Tickers, ticker) {
+			continue
+		}
+		summaries = append(summaries, t.Summary())
+	}
+	return summaries
+}
+
+// Find returns the trace with the given ID (a full ID or the 8-character prefix shown in logs)
+func (tr *Tracer) Find(id string) (TraceSummary, bool) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	for _, t := range tr.recent {
+		if t != nil && id != "" && strings.HasPrefix(t.ID, strings.ToLower(id)) {
+			return t.Summary(), true
+		}
+	}
+	return TraceSummary{}, false
+}
+
+func containsTicker(tickers []string, ticker string) bool {
+	for _, t := range tickers {
+		if strings.EqualFold(t, ticker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary converts the trace for the frontend, spans ordered by start time
+func (t *Trace) Summary() TraceSummary {
+	spans := t.Spans()
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].Start.Before(spans[j].Start) })
+
+	summary := TraceSummary{
+		ID:      t.ID,
+		Name:    t.Name,
+		Tickers: t.Tickers,
+		Start:   float64(t.Start.UnixNano()) / 1e9,
+		Spans:   make([]SpanSummary, 0, len(spans)),
+	}
+	last := t.Start
+	for _, span := range spans {
+		if span.End.After(last) {
+			last = span.End
+		}
+		if span.Error != "" {
+			summary.Error = true
+		}
+		summary.Spans = append(summary.Spans, SpanSummary{
+			Name:       span.Name,
+			Ticker:     span.Ticker,
+			OffsetMs:   milliseconds(span.Start.Sub(t.Start)),
+			DurationMs: milliseconds(span.End.Sub(span.Start)),
+			Attrs:      span.Attrs,
+			Error:      span.Error,
+		})
+	}
+	summary.DurationMs = milliseconds(last.Sub(t.Start))
+	return summary
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,138 @@
+package tsdb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// InfluxSink writes points as InfluxDB line protocol
+// Uses the v2 API (/api/v2/write, token auth) when an org is configured, otherwise the v1 /write API
+type InfluxSink struct {
+	writeURL    string
+	token       string
+	v2          bool
+	measurement string
+	httpClient  *http.Client
+}
+
+// NewInfluxSink creates an InfluxDB sink from settings
+func NewInfluxSink(cfg config.TimeSeriesSinkSettings) *InfluxSink {
+	base := strings.TrimRight(cfg.URL, "/")
+	params := url.Values{}
+	params.Set("precision", "ms")
+
+	var writeURL string
+	v2 := cfg.Org != ""
+	if v2 {
+		params.Set("org", cfg.Org)
+		params.Set("bucket", cfg.Database)
+		writeURL = base + "/api/v2/write?" + params.Encode()
+	} else {
+		params.Set("db", cfg.Database)
+		writeURL = base + "/write?" + params.Encode()
+	}
+
+	return &InfluxSink{
+		writeURL:    writeURL,
+		token:       cfg.Token,
+		v2:          v2,
+		measurement: cfg.MeasurementName(),
+		httpClient:  &http.Client{Timeout: time.Duration(config.TimeSeriesWriteTimeoutSec) * time.Second},
+	}
+}
+
+// escapeLineProtocol escapes commas, equals signs and spaces in measurement names, tag values and field keys
+func escapeLineProtocol(s string) string {
+	return strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `).Replace(s)
+}
+
+// encode renders points as line protocol (one line per point, fields sorted for stable output)
+func (s *InfluxSink) encode(points []Point) []byte {
+	var buf bytes.Buffer
+	for _, point := range points {
+		keys := make([]string, 0, len(point.Fields))
+		for key, value := range point.Fields {
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				continue // Not representable in line protocol
+			}
+			keys = append(keys, key)
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		sort.Strings(keys)
+
+		buf.WriteString(escapeLineProtocol(s.measurement))
+		buf.WriteString(",ticker=")
+		buf.WriteString(escapeLineProtocol(point.Ticker))
+		for i, key := range keys {
+			if i == 0 {
+				buf.WriteByte(' ')
+			} else {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(escapeLineProtocol(key))
+			buf.WriteByte('=')
+			buf.WriteString(strconv.FormatFloat(point.Fields[key], 'f', -1, 64))
+		}
+		buf.WriteByte(' ')
+		buf.WriteString(strconv.FormatInt(int64(math.Round(point.Timestamp*1000)), 10))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// Write posts a batch of points
+func (s *InfluxSink) Write(points []Point) error {
+	body := s.encode(points)
+	if len(body) == 0 {
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.writeURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if s.token != "" {
+		if s.v2 {
+			req.Header.Set("Authorization", "Token "+s.token)
+		} else if user, password, ok := strings.Cut(s.token, ":"); ok {
+			req.SetBasicAuth(user, password)
+		}
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("InfluxDB write failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("InfluxDB write failed: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// Describe returns the write endpoint without credentials
+func (s *InfluxSink) Describe() string {
+	if parsed, err := url.Parse(s.writeURL); err == nil {
+		return fmt.Sprintf("InfluxDB %s%s (%s)", parsed.Host, parsed.Path, s.measurement)
+	}
+	return "InfluxDB"
+}
+
+// Close is a no-op (HTTP connections are pooled by the client)
+func (s *InfluxSink) Close() error {
+	return nil
+}
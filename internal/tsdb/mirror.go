@@ -0,0 +1,123 @@
+package tsdb
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// MirrorStatus reports how the time-series mirror is keeping up
+type MirrorStatus struct {
+	Enabled     bool    `json:"enabled"`
+	Destination string  `json:"destination"`
+	Written     int64   `json:"written"` // Points written
+	Dropped     int64   `json:"dropped"` // Points dropped because the queue was full
+	Failed      int64   `json:"failed"`  // Points in batches the sink rejected
+	LastError   string  `json:"last_error"`
+	LastWriteAt float64 `json:"last_write_at"` // Unix seconds (0 if nothing written yet)
+}
+
+// Mirror forwards flushed rows to a sink on a background goroutine
+// Enqueue never blocks: when the sink can't keep up, batches are dropped (SQLite remains complete)
+type Mirror struct {
+	sink       Sink
+	settings   config.TimeSeriesSinkSettings
+	queue      chan []Point
+	debugPrint func(string, string)
+	wg         sync.WaitGroup
+
+	mu     sync.Mutex
+	status MirrorStatus
+	closed bool
+}
+
+// NewMirror creates the configured sink and starts the forwarding goroutine
+func NewMirror(settings config.TimeSeriesSinkSettings, debugPrint func(string, string)) (*Mirror, error) {
+	sink, err := NewSink(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Mirror{
+		sink:       sink,
+		settings:   settings,
+		queue:      make(chan []Point, config.TimeSeriesQueueBatches),
+		debugPrint: debugPrint,
+		status:     MirrorStatus{Enabled: true, Destination: sink.Describe()},
+	}
+	m.wg.Add(1)
+	go m.run()
+
+	debugPrint(fmt.Sprintf("Time-series mirror started: %s", sink.Describe()), "writer")
+	return m, nil
+}
+
+// run writes queued batches until the queue is closed
+func (m *Mirror) run() {
+	defer m.wg.Done()
+	for points := range m.queue {
+		err := m.sink.Write(points)
+
+		m.mu.Lock()
+		if err != nil {
+			m.status.Failed += int64(len(points))
+			m.status.LastError = err.Error()
+		} else {
+			m.status.Written += int64(len(points))
+			m.status.LastWriteAt = float64(time.Now().UnixNano()) / 1e9
+		}
+		m.mu.Unlock()
+
+		if err != nil {
+			m.debugPrint(fmt.Sprintf("Time-series mirror: Dropped %d point(s): %v", len(points), err), "error")
+		}
+	}
+}
+
+// MirrorsTicker reports whether the ticker is configured to be mirrored
+func (m *Mirror) MirrorsTicker(ticker string) bool {
+	return m.settings.MirrorsTicker(ticker)
+}
+
+// Enqueue queues points for the sink without blocking
+func (m *Mirror) Enqueue(points []Point) {
+	if len(points) == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	select {
+	case m.queue <- points:
+	default:
+		m.status.Dropped += int64(len(points))
+	}
+}
+
+// Status returns write counters and the last error
+func (m *Mirror) Status() MirrorStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status
+}
+
+// Stop writes what is already queued, then closes the sink (later Enqueue calls are ignored)
+func (m *Mirror) Stop() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	close(m.queue)
+	m.mu.Unlock()
+
+	m.wg.Wait()
+	if err := m.sink.Close(); err != nil {
+		m.debugPrint(fmt.Sprintf("Time-series mirror: Error closing sink: %v", err), "error")
+	}
+}
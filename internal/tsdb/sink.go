@@ -0,0 +1,39 @@
+package tsdb
+
+import (
+	"fmt"
+
+	"market-terminal/internal/config"
+)
+
+// Point is one collected row's scalar fields for a ticker
+type Point struct {
+	Ticker    string
+	Timestamp float64 // Unix seconds
+	Fields    map[string]float64
+}
+
+// Sink is a time-series database collected data is mirrored to
+type Sink interface {
+	// Write stores a batch of points
+	Write(points []Point) error
+	// Describe returns a human-readable description (no credentials)
+	Describe() string
+	// Close releases connections
+	Close() error
+}
+
+// NewSink creates the sink configured in settings
+func NewSink(cfg config.TimeSeriesSinkSettings) (Sink, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	switch cfg.Type {
+	case config.TimeSeriesSinkInflux:
+		return NewInfluxSink(cfg), nil
+	case config.TimeSeriesSinkTimescale:
+		return NewTimescaleSink(cfg)
+	default:
+		return nil, fmt.Errorf("unknown time-series sink type: %q", cfg.Type)
+	}
+}
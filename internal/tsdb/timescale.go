@@ -0,0 +1,114 @@
+package tsdb
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// postgresDrivers are database/sql driver names TimescaleDB can be reached through, in preference order
+var postgresDrivers = []string{"pgx", "postgres"}
+
+// TimescaleSink writes points to a TimescaleDB hypertable in long format (time, ticker, field, value),
+// so new fields never need a schema change
+// No PostgreSQL driver is bundled: the build must link one (e.g. pgx/stdlib or lib/pq) for this sink to work
+type TimescaleSink struct {
+	db    *sql.DB
+	table string
+}
+
+// NewTimescaleSink connects to TimescaleDB and creates the hypertable if needed
+func NewTimescaleSink(cfg config.TimeSeriesSinkSettings) (*TimescaleSink, error) {
+	driver := ""
+	registered := sql.Drivers()
+	for _, name := range postgresDrivers {
+		for _, r := range registered {
+			if r == name {
+				driver = name
+				break
+			}
+		}
+		if driver != "" {
+			break
+		}
+	}
+	if driver == "" {
+		return nil, fmt.Errorf("TimescaleDB sink is not available in this build (no PostgreSQL driver linked) - use the influx type instead")
+	}
+
+	db, err := sql.Open(driver, cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open TimescaleDB connection: %w", err)
+	}
+	db.SetMaxOpenConns(2)
+	db.SetConnMaxIdleTime(time.Duration(config.TimeSeriesWriteTimeoutSec) * time.Second)
+
+	sink := &TimescaleSink{db: db, table: cfg.MeasurementName()}
+	if err := sink.ensureTable(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return sink, nil
+}
+
+// ensureTable creates the table and turns it into a hypertable (no-op if it already is one)
+func (s *TimescaleSink) ensureTable() error {
+	if _, err := s.db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		time TIMESTAMPTZ NOT NULL,
+		ticker TEXT NOT NULL,
+		field TEXT NOT NULL,
+		value DOUBLE PRECISION
+	)`, s.table)); err != nil {
+		return fmt.Errorf("failed to create TimescaleDB table %s: %w", s.table, err)
+	}
+	if _, err := s.db.Exec("SELECT create_hypertable($1, 'time', if_not_exists => TRUE)", s.table); err != nil {
+		return fmt.Errorf("failed to create hypertable %s (is the timescaledb extension installed?): %w", s.table, err)
+	}
+	if _, err := s.db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_ticker_field_time ON %s (ticker, field, time DESC)", s.table, s.table)); err != nil {
+		return fmt.Errorf("failed to create index on %s: %w", s.table, err)
+	}
+	return nil
+}
+
+// Write inserts a batch of points in one transaction
+func (s *TimescaleSink) Write(points []Point) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("TimescaleDB write failed: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s (time, ticker, field, value) VALUES ($1, $2, $3, $4)", s.table))
+	if err != nil {
+		return fmt.Errorf("TimescaleDB write failed: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, point := range points {
+		sec, frac := math.Modf(point.Timestamp)
+		ts := time.Unix(int64(sec), int64(frac*1e9)).UTC()
+		for field, value := range point.Fields {
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				continue
+			}
+			if _, err := stmt.Exec(ts, point.Ticker, field, value); err != nil {
+				return fmt.Errorf("TimescaleDB write failed: %w", err)
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// Describe returns the table name (the DSN may contain credentials)
+func (s *TimescaleSink) Describe() string {
+	return fmt.Sprintf("TimescaleDB table %s", strings.ToLower(s.table))
+}
+
+// Close closes the connection pool
+func (s *TimescaleSink) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Clock is the source of "now" for market-time logic (market date rollover, market hours, row timestamps)
+// The system clock is used unless a SimulatedClock is installed with SetClock (tests, replays, --simulate-clock)
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the wall clock, corrected by the measured clock skew (see SetClockOffset)
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now().Add(-GetClockOffset())
+}
+
+// SystemClock returns the wall clock
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+// clockHolder wraps the installed clock (atomic.Value needs one concrete type)
+type clockHolder struct {
+	clock Clock
+}
+
+var currentClock atomic.Value
+
+// SetClock installs the clock used by NowMarketTime and the market-hours helpers (nil = system clock)
+// Components with a ClockSource (scheduler, coordinator, writer) follow it unless given their own with SetClock
+func SetClock(clock Clock) {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	currentClock.Store(clockHolder{clock: clock})
+}
+
+// GetClock returns the installed clock
+func GetClock() Clock {
+	if holder, ok := currentClock.Load().(clockHolder); ok {
+		return holder.clock
+	}
+	return systemClock{}
+}
+
+// InstalledClock is a Clock that always reads the clock installed with SetClock, including one installed later
+var InstalledClock Clock = installedClock{}
+
+type installedClock struct{}
+
+func (installedClock) Now() time.Time {
+	return GetClock().Now()
+}
+
+// ClockSource is a component's clock: it follows the installed clock until Set pins it to another one
+// Set may be called while other goroutines read Now, and a *ClockSource can be handed to sub-components
+// as their Clock so they follow the component's clock
+type ClockSource struct {
+	clock atomic.Value // clockHolder
+}
+
+// Set pins the source to a clock (nil = follow the installed clock again)
+func (s *ClockSource) Set(clock Clock) {
+	s.clock.Store(clockHolder{clock: clock})
+}
+
+// Now returns the time on the source's clock
+func (s *ClockSource) Now() time.Time {
+	if holder, ok := s.clock.Load().(clockHolder); ok && holder.clock != nil {
+		return holder.clock.Now()
+	}
+	return GetClock().Now()
+}
+
+// SimulatedClock is a Clock starting at a chosen instant: it runs at speed times real time (0 = frozen)
+// and can be moved with Set and Advance. The clock skew offset doesn't apply to it
+type SimulatedClock struct {
+	mu      sync.Mutex
+	base    time.Time // Simulated time at anchor
+	anchor  time.Time // Real time when base was set
+	speed   float64
+	realNow func() time.Time
+}
+
+// NewSimulatedClock creates a clock reading start now and advancing at speed (1 = real time, 0 = frozen)
+func NewSimulatedClock(start time.Time, speed float64) *SimulatedClock {
+	return &SimulatedClock{base: start, anchor: time.Now(), speed: speed, realNow: time.Now}
+}
+
+// Now returns the simulated time
+func (c *SimulatedClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.nowLocked()
+}
+
+func (c *SimulatedClock) nowLocked() time.Time {
+	if c.speed == 0 {
+		return c.base
+	}
+	elapsed := c.realNow().Sub(c.anchor)
+	return c.base.Add(time.Duration(float64(elapsed) * c.speed))
+}
+
+// Set jumps to t (the clock keeps running from there at its speed)
+func (c *SimulatedClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.base, c.anchor = t, c.realNow()
+}
+
+// Advance moves the clock forward by d
+func (c *SimulatedClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.base, c.anchor = c.nowLocked().Add(d), c.realNow()
+}
+
+// SetSpeed changes how fast the clock runs from now on (0 = frozen)
+func (c *SimulatedClock) SetSpeed(speed float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.base, c.anchor = c.nowLocked(), c.realNow()
+	c.speed = speed
+}
+
+// ParseSimulatedClock parses a --simulate-clock value: a start time ("2006-01-02T15:04:05", ET unless an
+// offset or Z is given) optionally followed by ",speed" (e.g. "2026-03-06T09:25:00,60" runs a minute per second)
+func ParseSimulatedClock(spec string) (*SimulatedClock, error) {
+	startStr, speedStr, hasSpeed := strings.Cut(spec, ",")
+	start, err := time.Parse(time.RFC3339, startStr)
+	if err != nil {
+		start, err = time.ParseInLocation("2006-01-02T15:04:05", startStr, MARKET_TIMEZONE)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start time %q (expected e.g. 2026-03-06T09:25:00)", startStr)
+		}
+	}
+	speed := 1.0
+	if hasSpeed {
+		speed, err = strconv.ParseFloat(speedStr, 64)
+		if err != nil || speed < 0 {
+			return nil, fmt.Errorf("invalid speed %q (expected a number >= 0)", speedStr)
+		}
+	}
+	return NewSimulatedClock(start, speed), nil
+}
+
+// clockOffset is subtracted from the system clock by NowMarketTime (nanoseconds, positive = local clock ahead)
+// Set from measured clock skew when correct_clock_skew is enabled, so a wrong system clock
+// doesn't file data under the wrong market date
+var clockOffset atomic.Int64
+
+// SetClockOffset sets the correction applied to market time (0 = trust the system clock)
+func SetClockOffset(offset time.Duration) {
+	clockOffset.Store(int64(offset))
+}
+
+// GetClockOffset returns the correction currently applied to market time
+func GetClockOffset() time.Duration {
+	return time.Duration(clockOffset.Load())
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClockSourceFollowsInstalledClock(t *testing.T) {
+	installed := time.Date(2026, 3, 6, 9, 25, 0, 0, MARKET_TIMEZONE)
+	SetClock(NewSimulatedClock(installed, 0))
+	defer SetClock(nil)
+
+	var source ClockSource
+	if got := source.Now(); !got.Equal(installed) {
+		t.Fatalf("unset source = %v, want the installed clock's %v", got, installed)
+	}
+
+	pinned := installed.Add(time.Hour)
+	source.Set(NewSimulatedClock(pinned, 0))
+	if got := source.Now(); !got.Equal(pinned) {
+		t.Errorf("pinned source = %v, want %v", got, pinned)
+	}
+	source.Set(nil)
+	if got := source.Now(); !got.Equal(installed) {
+		t.Errorf("source reset with nil = %v, want the installed clock's %v", got, installed)
+	}
+}
+
+// Set may race with readers (SetClock on a running scheduler or writer); run with -race
+func TestClockSourceConcurrentSet(t *testing.T) {
+	var source ClockSource
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			source.Set(NewSimulatedClock(time.Unix(int64(i), 0), 0))
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = source.Now()
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,85 @@
+package utils
+
+import "time"
+
+// IsMarketHoliday checks if a date is a full-day NYSE holiday (the exchange's rules, no early closes):
+// New Year's Day, Martin Luther King Jr. Day, Washington's Birthday, Good Friday, Memorial Day, Juneteenth
+// (from 2022), Independence Day, Labor Day, Thanksgiving and Christmas
+// Holidays on a Saturday are observed the Friday before and on a Sunday the Monday after, except New Year's Day
+// on a Saturday, which isn't observed (the exchange doesn't close the last trading day of the year)
+func IsMarketHoliday(date time.Time) bool {
+	date = date.In(MARKET_TIMEZONE)
+	year, month, day := date.Date()
+
+	holidays := []time.Time{
+		observedHoliday(year, time.January, 1),
+		nthWeekday(year, time.January, time.Monday, 3),
+		nthWeekday(year, time.February, time.Monday, 3),
+		easterSunday(year).AddDate(0, 0, -2),
+		lastWeekday(year, time.May, time.Monday),
+		observedHoliday(year, time.July, 4),
+		nthWeekday(year, time.September, time.Monday, 1),
+		nthWeekday(year, time.November, time.Thursday, 4),
+		observedHoliday(year, time.December, 25),
+	}
+	if year >= 2022 {
+		holidays = append(holidays, observedHoliday(year, time.June, 19))
+	}
+	for _, holiday := range holidays {
+		if holiday.Month() == month && holiday.Day() == day && !holiday.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTradingDay checks if a date is a weekday that isn't a market holiday
+func IsTradingDay(date time.Time) bool {
+	return !IsWeekend(date) && !IsMarketHoliday(date)
+}
+
+// observedHoliday returns the day a fixed-date holiday is observed (Saturday -> Friday, Sunday -> Monday)
+// New Year's Day on a Saturday isn't observed: it returns the zero time
+func observedHoliday(year int, month time.Month, day int) time.Time {
+	date := time.Date(year, month, day, 0, 0, 0, 0, MARKET_TIMEZONE)
+	switch date.Weekday() {
+	case time.Saturday:
+		if month == time.January && day == 1 {
+			return time.Time{}
+		}
+		return date.AddDate(0, 0, -1)
+	case time.Sunday:
+		return date.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+// nthWeekday returns the nth given weekday of a month (e.g. the third Monday of January)
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, MARKET_TIMEZONE)
+	offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+7*(n-1))
+}
+
+// lastWeekday returns the last given weekday of a month (e.g. the last Monday of May)
+func lastWeekday(year int, month time.Month, weekday time.Weekday) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, MARKET_TIMEZONE)
+	offset := (int(last.Weekday()) - int(weekday) + 7) % 7
+	return last.AddDate(0, 0, -offset)
+}
+
+// easterSunday returns Easter Sunday of a year (Gregorian calendar, anonymous computus)
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, MARKET_TIMEZONE)
+}
@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"market-terminal/internal/config"
+)
+
+// Log levels assigned to buffered log lines
+const (
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// LogEntry is one buffered log line
+type LogEntry struct {
+	Time     float64 `json:"time"`     // Unix seconds
+	Category string  `json:"category"` // From the "[category] " prefix debugPrint adds ("" when absent)
+	Level    string  `json:"level"`    // info, warn or error
+	Message  string  `json:"message"`
+}
+
+// logBuffer keeps the most recent log lines in memory (crash dumps, in-app log viewer)
+type logBuffer struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	next    int
+	count   int
+}
+
+var recentLogs = &logBuffer{entries: make([]LogEntry, config.RecentLogBufferLines)}
+
+// add appends an entry, overwriting the oldest once the buffer is full
+func (lb *logBuffer) add(entry LogEntry) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	lb.entries[lb.next] = entry
+	lb.next = (lb.next + 1) % len(lb.entries)
+	if lb.count < len(lb.entries) {
+		lb.count++
+	}
+}
+
+// snapshot returns the buffered entries, oldest first
+func (lb *logBuffer) snapshot() []LogEntry {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	result := make([]LogEntry, 0, lb.count)
+	for i := 0; i < lb.count; i++ {
+		result = append(result, lb.entries[(lb.next-lb.count+i+len(lb.entries))%len(lb.entries)])
+	}
+	return result
+}
+
+// recordLog adds a formatted log message to the in-memory buffer
+func recordLog(msg string) {
+	entry := LogEntry{Time: float64(time.Now().UnixNano()) / 1e9, Message: msg}
+	if strings.HasPrefix(msg, "[") {
+		if end := strings.Index(msg, "] "); end > 1 && !strings.ContainsAny(msg[1:end], " []") {
+			entry.Category = msg[1:end]
+			entry.Message = msg[end+2:]
+		}
+	}
+	entry.Level = logLevel(entry.Category, entry.Message)
+	recentLogs.add(entry)
+}
+
+// logLevel classifies a message (debugPrint only distinguishes errors by category)
+func logLevel(category, msg string) string {
+	switch {
+	case category == "error" || strings.Contains(msg, "ERROR") || strings.Contains(msg, "PANIC") || strings.Contains(msg, "❌"):
+		return LogLevelError
+	case strings.Contains(msg, "WARNING") || strings.Contains(msg, "Warning") || strings.Contains(msg, "⚠️"):
+		return LogLevelWarn
+	}
+	return LogLevelInfo
+}
+
+// logLevelRank orders levels for minimum-level filtering
+func logLevelRank(level string) int {
+	switch level {
+	case LogLevelWarn:
+		return 1
+	case LogLevelError:
+		return 2
+	}
+	return 0
+}
+
+// RecentLogs returns up to limit of the most recent buffered log entries, oldest first
+// category filters by exact category ("" = all); level is the minimum level ("" or "info" = all)
+// limit <= 0 returns every match. Lines are captured even when file logging is disabled
+func RecentLogs(category, level string, limit int) []LogEntry {
+	entries := recentLogs.snapshot()
+	minRank := logLevelRank(level)
+	matched := make([]LogEntry, 0, len(entries))
+	for _, entry := range entries {
+		if category != "" && entry.Category != category {
+			continue
+		}
+		if logLevelRank(entry.Level) < minRank {
+			continue
+		}
+		matched = append(matched, entry)
+	}
+	if limit > 0 && len(matched) > limit {
+		matched = matched[len(matched)-limit:]
+	}
+	return matched
+}
+
+// RecentLogLines returns up to n of the most recent log lines as text (n <= 0 = all buffered), oldest first
+func RecentLogLines(n int) []string {
+	entries := RecentLogs("", "", n)
+	lines := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		line := time.Unix(0, int64(entry.Time*1e9)).Format("2006-01-02 15:04:05.000") + " "
+		if entry.Category != "" {
+			line += "[" + entry.Category + "] "
+		}
+		lines = append(lines, line+entry.Message)
+	}
+	return lines
+}
+
+// RecentLogCategories returns the categories present in the buffer, in first-seen order
+func RecentLogCategories() []string {
+	seen := make(map[string]bool)
+	categories := make([]string, 0)
+	for _, entry := range recentLogs.snapshot() {
+		if entry.Category != "" && !seen[entry.Category] {
+			seen[entry.Category] = true
+			categories = append(categories, entry.Category)
+		}
+	}
+	return categories
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 )
@@ -143,19 +144,29 @@ func (l *Logger) Close() error {
 	return nil
 }
 
+// Path returns the current run's log file path
+func (l *Logger) Path() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.logPath
+}
+
 // Global convenience functions that use the global logger
 
 // Logf logs using the global logger
 func Logf(format string, v ...interface{}) {
+	recordLog(fmt.Sprintf(format, v...))
 	GetLogger().Printf(format, v...)
 }
 
 // Log logs using the global logger
 func Log(v ...interface{}) {
+	recordLog(fmt.Sprint(v...))
 	GetLogger().Print(v...)
 }
 
 // Logln logs using the global logger with newline
 func Logln(v ...interface{}) {
+	recordLog(strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
 	GetLogger().Println(v...)
 }
@@ -28,8 +28,10 @@ func GetMarketTimezone() *time.Location {
 }
 
 // NowMarketTime returns current time in market timezone (Eastern Time)
+// Read from the installed Clock (see SetClock): the system clock, corrected by the measured clock skew
+// when clock skew correction is enabled (see SetClockOffset), or a simulated clock
 func NowMarketTime() time.Time {
-	return time.Now().In(MARKET_TIMEZONE)
+	return GetClock().Now().In(MARKET_TIMEZONE)
 }
 
 // MarketOpenCloseTimes returns market open and close times for a given date in Eastern Time
@@ -50,7 +52,12 @@ func MarketOpenCloseTimes(date time.Time) (time.Time, time.Time) {
 // IsMarketOpen checks if the US stock market is currently open
 // Market hours are 9:30 AM - 4:00 PM Eastern Time, Monday-Friday only
 func IsMarketOpen() bool {
-	now := NowMarketTime()
+	return IsMarketOpenAt(NowMarketTime())
+}
+
+// IsMarketOpenAt checks if the US stock market is open at a given time
+func IsMarketOpenAt(now time.Time) bool {
+	now = now.In(MARKET_TIMEZONE)
 	today := now
 	
 	// Check if it's a weekend (Saturday=6, Sunday=0 in Go)
@@ -136,7 +143,12 @@ func GetLastTradingDay(date time.Time) time.Time {
 // Before 8:30 AM ET: returns yesterday's date
 // 8:30 AM ET or later: returns today's date
 func GetMarketDate() time.Time {
-	now := NowMarketTime()
+	return GetMarketDateAt(NowMarketTime())
+}
+
+// GetMarketDateAt returns the market date at a given time (same 8:30 AM ET rollover as GetMarketDate)
+func GetMarketDateAt(now time.Time) time.Time {
+	now = now.In(MARKET_TIMEZONE)
 	
 	// Date rollover happens at 8:30 AM ET
 	rolloverTime := time.Date(now.Year(), now.Month(), now.Day(), 8, 30, 0, 0, MARKET_TIMEZONE)
@@ -183,6 +195,13 @@ func GetMarketDateForDate(date time.Time) time.Time {
 	return date
 }
 
+// MarketMidnight returns midnight ET on date's calendar day, read in date's own location
+// A date built at midnight UTC keeps its day instead of becoming the previous evening in ET
+// (which would e.g. turn a Monday into a weekend day and file it under Friday)
+func MarketMidnight(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, MARKET_TIMEZONE)
+}
+
 // ParseDateInET parses a date string in "YYYY-MM-DD" format, assuming it's in Eastern Time
 // This ensures date strings are interpreted as ET dates, not UTC
 // Returns a time.Time at midnight ET for the given date
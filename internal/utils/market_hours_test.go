@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// DST in 2026 starts Sunday March 8 (2:00 EST -> 3:00 EDT) and ends Sunday November 1 (2:00 EDT -> 1:00 EST),
+// so the first trading days on the new offset are Monday March 9 and Monday November 2
+
+// withSimulatedClock freezes the clock at now for the rest of the test
+func withSimulatedClock(t *testing.T, now time.Time) {
+	t.Helper()
+	SetClock(NewSimulatedClock(now, 0))
+	t.Cleanup(func() { SetClock(nil) })
+}
+
+func TestGetMarketDateRolloverAcrossDST(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{"day before spring forward, before rollover", time.Date(2026, 3, 6, 8, 29, 59, 0, MARKET_TIMEZONE), "2026-03-05"},
+		{"day before spring forward, at rollover", time.Date(2026, 3, 6, 8, 30, 0, 0, MARKET_TIMEZONE), "2026-03-06"},
+		{"spring forward, just after the jump", time.Date(2026, 3, 8, 3, 0, 0, 0, MARKET_TIMEZONE), "2026-03-07"},
+		{"spring forward, at rollover", time.Date(2026, 3, 8, 8, 30, 0, 0, MARKET_TIMEZONE), "2026-03-08"},
+		{"first EDT trading day, before rollover", time.Date(2026, 3, 9, 8, 29, 59, 0, MARKET_TIMEZONE), "2026-03-08"},
+		{"first EDT trading day, at rollover", time.Date(2026, 3, 9, 8, 30, 0, 0, MARKET_TIMEZONE), "2026-03-09"},
+		{"first EDT trading day, 8:30 EDT given in UTC", time.Date(2026, 3, 9, 12, 30, 0, 0, time.UTC), "2026-03-09"},
+		{"first EDT trading day, 8:30 EST given in UTC", time.Date(2026, 3, 9, 13, 30, 0, 0, time.UTC), "2026-03-09"},
+		{"first EDT trading day, 12:29 UTC is 8:29 EDT", time.Date(2026, 3, 9, 12, 29, 0, 0, time.UTC), "2026-03-08"},
+		{"fall back, first 1:30", time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC), "2026-10-31"},
+		{"fall back, second 1:30", time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC), "2026-10-31"},
+		{"fall back, at rollover", time.Date(2026, 11, 1, 8, 30, 0, 0, MARKET_TIMEZONE), "2026-11-01"},
+		{"first EST trading day, before rollover", time.Date(2026, 11, 2, 8, 29, 59, 0, MARKET_TIMEZONE), "2026-11-01"},
+		{"first EST trading day, at rollover", time.Date(2026, 11, 2, 8, 30, 0, 0, MARKET_TIMEZONE), "2026-11-02"},
+		{"first EST trading day, 12:30 UTC is 7:30 EST", time.Date(2026, 11, 2, 12, 30, 0, 0, time.UTC), "2026-11-01"},
+		{"first EST trading day, 13:30 UTC is 8:30 EST", time.Date(2026, 11, 2, 13, 30, 0, 0, time.UTC), "2026-11-02"},
+		{"late evening ET is the next day in UTC", time.Date(2026, 11, 3, 2, 0, 0, 0, time.UTC), "2026-11-02"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withSimulatedClock(t, tt.now)
+			if got := GetMarketDate().Format("2006-01-02"); got != tt.want {
+				t.Errorf("GetMarketDate() at %s = %s, want %s", tt.now.In(MARKET_TIMEZONE).Format(time.RFC3339), got, tt.want)
+			}
+			if got := GetMarketDateAt(tt.now).Format("2006-01-02"); got != tt.want {
+				t.Errorf("GetMarketDateAt(%s) = %s, want %s", tt.now.Format(time.RFC3339), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarketMidnightUTCInput(t *testing.T) {
+	tests := []struct {
+		name       string
+		date       time.Time
+		want       string // RFC3339 in ET
+		tradingDay string // GetLastTradingDay of the result
+	}{
+		{"Monday at UTC midnight keeps its day", time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), "2026-03-09T00:00:00-04:00", "2026-03-09"},
+		{"spring forward Sunday at UTC midnight", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), "2026-03-08T00:00:00-05:00", "2026-03-06"},
+		{"fall back Sunday at UTC midnight", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), "2026-11-01T00:00:00-04:00", "2026-10-30"},
+		{"first EST Monday at UTC midnight", time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC), "2026-11-02T00:00:00-05:00", "2026-11-02"},
+		{"ET midnight is unchanged", time.Date(2026, 3, 9, 0, 0, 0, 0, MARKET_TIMEZONE), "2026-03-09T00:00:00-04:00", "2026-03-09"},
+		{"ET afternoon moves to midnight", time.Date(2026, 11, 2, 15, 45, 0, 0, MARKET_TIMEZONE), "2026-11-02T00:00:00-05:00", "2026-11-02"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MarketMidnight(tt.date)
+			if got.Format(time.RFC3339) != tt.want {
+				t.Errorf("MarketMidnight(%s) = %s, want %s", tt.date.Format(time.RFC3339), got.Format(time.RFC3339), tt.want)
+			}
+			if day := GetLastTradingDay(got).Format("2006-01-02"); day != tt.tradingDay {
+				t.Errorf("GetLastTradingDay(MarketMidnight(%s)) = %s, want %s", tt.date.Format(time.RFC3339), day, tt.tradingDay)
+			}
+		})
+	}
+}
+
+func TestParseDateInETAcrossDST(t *testing.T) {
+	tests := []struct {
+		date string
+		want string
+	}{
+		{"2026-03-08", "2026-03-08T00:00:00-05:00"},
+		{"2026-03-09", "2026-03-09T00:00:00-04:00"},
+		{"2026-11-01", "2026-11-01T00:00:00-04:00"},
+		{"2026-11-02", "2026-11-02T00:00:00-05:00"},
+	}
+	for _, tt := range tests {
+		got, err := ParseDateInET(tt.date)
+		if err != nil {
+			t.Fatalf("ParseDateInET(%s): %v", tt.date, err)
+		}
+		if got.Format(time.RFC3339) != tt.want {
+			t.Errorf("ParseDateInET(%s) = %s, want %s", tt.date, got.Format(time.RFC3339), tt.want)
+		}
+		if open, _ := MarketOpenCloseTimes(got); open.Format("2006-01-02 15:04") != tt.date+" 09:30" {
+			t.Errorf("market open on %s = %s, want 09:30 ET that day", tt.date, open.Format(time.RFC3339))
+		}
+	}
+}
+
+func TestIsMarketHoliday(t *testing.T) {
+	holidays := []string{
+		"2026-01-01", "2026-01-19", "2026-02-16", "2026-04-03", "2026-05-25", "2026-06-19",
+		"2026-07-03", // July 4 is a Saturday
+		"2026-09-07", "2026-11-26", "2026-12-25",
+		"2027-06-18", // Juneteenth is a Saturday
+		"2027-12-24", // Christmas is a Saturday
+		"2023-01-02", // New Year's Day is a Sunday
+		"2025-04-18", // Good Friday
+	}
+	for _, date := range holidays {
+		day, _ := ParseDateInET(date)
+		if !IsMarketHoliday(day) || IsTradingDay(day) {
+			t.Errorf("%s is a market holiday", date)
+		}
+	}
+
+	tradingDays := []string{
+		"2026-01-02", "2026-04-06", "2026-07-06", "2026-11-27",
+		"2021-06-18", // Before Juneteenth was a market holiday
+		"2021-12-31", // New Year's Day 2022 is a Saturday and isn't observed
+	}
+	for _, date := range tradingDays {
+		day, _ := ParseDateInET(date)
+		if IsMarketHoliday(day) || !IsTradingDay(day) {
+			t.Errorf("%s is a trading day", date)
+		}
+	}
+
+	// Read in ET: the evening before a holiday in ET is already the holiday in UTC
+	if IsMarketHoliday(time.Date(2026, 11, 26, 2, 0, 0, 0, time.UTC)) {
+		t.Error("2026-11-25 21:00 ET is not Thanksgiving")
+	}
+}
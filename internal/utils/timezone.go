@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimezoneTransition is a UTC offset change (DST start/end) inside a chart's time span
+type TimezoneTransition struct {
+	Timestamp    float64 `json:"timestamp"`    // Unix seconds of the first instant with the new offset
+	OffsetSec    int     `json:"offset_sec"`   // UTC offset from then on (seconds east of UTC)
+	Abbreviation string  `json:"abbreviation"` // e.g. "EDT"
+}
+
+// ChartTimezoneInfo tells the frontend how to label a chart's epoch timestamps
+// Axis label = timestamp + offset in effect at that timestamp
+type ChartTimezoneInfo struct {
+	Name         string               `json:"name"`         // Resolved IANA name ("America/New_York", "UTC", "Local")
+	OffsetSec    int                  `json:"offset_sec"`   // UTC offset at the start of the span
+	Abbreviation string               `json:"abbreviation"` // Abbreviation at the start of the span
+	Transitions  []TimezoneTransition `json:"transitions"`  // Offset changes within the span (usually none)
+}
+
+// ResolveChartTimezone maps a chart timezone setting to a location
+// Accepts "market" (exchange time), "local", "UTC" or any IANA name; empty means market
+func ResolveChartTimezone(name string) (*time.Location, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "market":
+		return GetMarketTimezone(), nil
+	case "local":
+		return time.Local, nil
+	case "utc":
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(strings.TrimSpace(name))
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q (use market, local, UTC or an IANA name like Europe/London)", name)
+	}
+	return loc, nil
+}
+
+// GetChartTimezoneInfo returns the UTC offset at start and any DST transitions in [start, end]
+func GetChartTimezoneInfo(loc *time.Location, start, end time.Time) ChartTimezoneInfo {
+	abbreviation, offset := start.In(loc).Zone()
+	info := ChartTimezoneInfo{
+		Name:         loc.String(),
+		OffsetSec:    offset,
+		Abbreviation: abbreviation,
+		Transitions:  make([]TimezoneTransition, 0),
+	}
+
+	// Offsets only change on whole-hour-ish boundaries, so hourly steps find every transition;
+	// each one is then narrowed to the second
+	prev := start
+	prevOffset := offset
+	for prev.Before(end) {
+		next := prev.Add(time.Hour)
+		if next.After(end) {
+			next = end
+		}
+		if _, nextOffset := next.In(loc).Zone(); nextOffset != prevOffset {
+			lo, hi := prev, next
+			for hi.Sub(lo) > time.Second {
+				mid := lo.Add(hi.Sub(lo) / 2)
+				if _, midOffset := mid.In(loc).Zone(); midOffset == prevOffset {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			abbreviation, nextOffset := hi.In(loc).Zone()
+			info.Transitions = append(info.Transitions, TimezoneTransition{
+				Timestamp:    float64(hi.Unix()),
+				OffsetSec:    nextOffset,
+				Abbreviation: abbreviation,
+			})
+			prevOffset = nextOffset
+		}
+		prev = next
+	}
+	return info
+}
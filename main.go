@@ -1,18 +1,32 @@
 package main
 
 import (
+	"context"
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"net"
 	"net/http"
+	"net/http/httputil"
 	_ "net/http/pprof" // Memory profiling
+	"net/url"
+	"os"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	_ "time/tzdata" // Embed IANA timezone database for Windows compatibility
 
 	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
 
+	"market-terminal/internal/charts"
 	"market-terminal/internal/config"
+	"market-terminal/internal/database"
+	"market-terminal/internal/model"
 	"market-terminal/internal/utils"
 )
 
@@ -37,7 +51,65 @@ func (w *appWrapper) Window() interface {
 	return nil // This will be handled differently
 }
 
+// profilerAddr is the address the pprof server is listening on ("" = not running), read by GetProfilerAddress
+var (
+	profilerAddr     string
+	profilerListener net.Listener // Closed by stopProfiler (eco mode suspends the profiler)
+	profilerAddrLock sync.RWMutex
+)
+
+// startProfiler serves pprof on addr; a port of 0 picks any free port
+// Failing to bind (e.g. port in use) is logged, not fatal
+func startProfiler(addr string) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		utils.Logf("Memory profiler unavailable (%s may be in use): %v", addr, err)
+		return
+	}
+	addr = listener.Addr().String()
+	profilerAddrLock.Lock()
+	profilerAddr = addr
+	profilerListener = listener
+	profilerAddrLock.Unlock()
+
+	utils.Logf("Memory profiler starting on http://%s/debug/pprof/", addr)
+	utils.Logf("  - Heap: http://%s/debug/pprof/heap", addr)
+	utils.Logf("  - Allocs: http://%s/debug/pprof/allocs", addr)
+	utils.Logf("  - Goroutine: http://%s/debug/pprof/goroutine", addr)
+	if err := http.Serve(listener, nil); err != nil {
+		utils.Logf("Memory profiler stopped: %v", err)
+	}
+
+	profilerAddrLock.Lock()
+	if profilerListener == listener { // Not already replaced by a restarted profiler
+		profilerAddr = ""
+		profilerListener = nil
+	}
+	profilerAddrLock.Unlock()
+}
+
+// stopProfiler closes the pprof server; returns the address it was listening on ("" = it wasn't running)
+func stopProfiler() string {
+	profilerAddrLock.Lock()
+	defer profilerAddrLock.Unlock()
+	if profilerListener == nil {
+		return ""
+	}
+	addr := profilerAddr
+	profilerListener.Close()
+	profilerListener = nil
+	profilerAddr = ""
+	return addr
+}
+
 func main() {
+	// --profile=NAME: load a named configuration profile for this launch (before any settings are read)
+	for _, arg := range os.Args[1:] {
+		if strings.HasPrefix(arg, "--profile=") {
+			config.SetLaunchProfile(strings.TrimPrefix(arg, "--profile="))
+		}
+	}
+
 	// Load settings first to check EnableLogging
 	settingsManager := config.NewSettingsManager("")
 	settings, err := settingsManager.LoadSettings()
@@ -57,18 +129,78 @@ func main() {
 		log.Printf("File logging disabled by user setting")
 	}
 
-	// Start memory profiler (for debugging)
-	go func() {
-		// Try to start profiler, but don't fail if port is in use
-		addr := "localhost:6060"
-		utils.Logf("Memory profiler starting on http://%s/debug/pprof/", addr)
-		utils.Logf("  - Heap: http://%s/debug/pprof/heap", addr)
-		utils.Logf("  - Allocs: http://%s/debug/pprof/allocs", addr)
-		utils.Logf("  - Goroutine: http://%s/debug/pprof/goroutine", addr)
-		if err := http.ListenAndServe(addr, nil); err != nil {
-			utils.Logf("Memory profiler unavailable (port 6060 may be in use): %v", err)
+	// Start memory profiler (for debugging) unless disabled (enable_profiler: false)
+	profilerSettings := settings
+	if profilerSettings == nil {
+		profilerSettings = config.GetDefaultSettings()
+	}
+	if profilerSettings.ProfilerEnabled() {
+		go startProfiler(profilerSettings.GetProfilerAddress())
+	} else {
+		utils.Logf("Memory profiler disabled by user setting")
+	}
+
+	// Subcommands (export, verify, backfill, compact, list-dates) run without a window and exit - see cli.go
+	if command, args, ok := findCLICommand(os.Args[1:]); ok {
+		os.Exit(runCLI(settings, command, args))
+	}
+
+	// --read-only: browse existing data without collecting (e.g. second machine on a synced copy)
+	// --dev-server[=URL]: serve frontend assets from a running Vite dev server (no rebuild/re-embed per change)
+	// --prune-columns[=col1,col2] [--dry-run]: drop other scalar columns from previous days' databases and exit
+	// --migrate-data-layout=iso|flat [--dry-run]: move day directories into that layout, switch data_layout and exit
+	// --simulate-clock=START[,SPEED]: collect on a simulated market clock, into "<data directory>-simulated"
+	// --merge-database=SOURCE,TICKER,DATE: merge another machine's database for that day into the local one and exit
+	// --open-chart=TICKER: open that ticker's chart window (handled in ServiceStartup, or by the already running instance)
+	devServerURL := ""
+	pruneColumns := false
+	pruneKeep := []string{}
+	pruneDryRun := false
+	migrateLayout := ""
+	mergeDatabase := ""
+	for _, arg := range os.Args[1:] {
+		if arg == "--read-only" {
+			SetLaunchReadOnly(true)
+		} else if arg == "--dev-server" {
+			devServerURL = config.DevServerDefaultURL
+		} else if strings.HasPrefix(arg, "--dev-server=") {
+			devServerURL = strings.TrimPrefix(arg, "--dev-server=")
+		} else if arg == "--prune-columns" {
+			pruneColumns = true
+		} else if strings.HasPrefix(arg, "--prune-columns=") {
+			pruneColumns = true
+			pruneKeep = strings.Split(strings.TrimPrefix(arg, "--prune-columns="), ",")
+		} else if arg == "--dry-run" {
+			pruneDryRun = true
+		} else if strings.HasPrefix(arg, "--migrate-data-layout=") {
+			migrateLayout = strings.TrimPrefix(arg, "--migrate-data-layout=")
+		} else if strings.HasPrefix(arg, "--merge-database=") {
+			mergeDatabase = strings.TrimPrefix(arg, "--merge-database=")
+		} else if strings.HasPrefix(arg, "--simulate-clock=") {
+			clock, err := utils.ParseSimulatedClock(strings.TrimPrefix(arg, "--simulate-clock="))
+			if err != nil {
+				log.Fatalf("Invalid --simulate-clock: %v", err)
+			}
+			utils.SetClock(clock)
+			dataSettings := settings
+			if dataSettings == nil {
+				dataSettings = config.GetDefaultSettings()
+			}
+			dataDir := dataSettings.GetDataDirectory() + config.SimulatedDataDirectorySuffix
+			config.SetDataDirectoryOverride(dataDir)
+			utils.Logf("Simulated clock: starting at %s, collecting into %s",
+				clock.Now().In(utils.GetMarketTimezone()).Format("2006-01-02 15:04:05 MST"), dataDir)
 		}
-	}()
+	}
+	if pruneColumns {
+		os.Exit(runPruneColumns(settings, pruneKeep, pruneDryRun))
+	}
+	if migrateLayout != "" {
+		os.Exit(runMigrateDataLayout(settingsManager, settings, migrateLayout, pruneDryRun))
+	}
+	if mergeDatabase != "" {
+		os.Exit(runMergeDatabase(settings, mergeDatabase))
+	}
 
 	// Create app instance
 	appInstance := NewApp()
@@ -76,6 +208,23 @@ func main() {
 	// Create custom handler that serves assets and API routes
 	assetHandler := application.AssetFileServerFS(frontend)
 
+	// Frontend assets come from the embedded FS, or are proxied to the dev server in --dev-server mode
+	// (/wails/* and /api/* are still served by the app either way)
+	var frontendHandler http.Handler = assetHandler
+	if devServerURL != "" {
+		target, err := url.Parse(devServerURL)
+		if err != nil || target.Scheme == "" || target.Host == "" {
+			log.Fatalf("Invalid --dev-server URL %q (expected e.g. %s)", devServerURL, config.DevServerDefaultURL)
+		}
+		proxy := httputil.NewSingleHostReverseProxy(target)
+		proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+			utils.Logf("[dev-server] Proxy error for %s: %v", r.URL.Path, err)
+			http.Error(w, fmt.Sprintf("Frontend dev server unavailable at %s: %v", devServerURL, err), http.StatusBadGateway)
+		}
+		frontendHandler = proxy
+		utils.Logf("Dev server mode: proxying frontend assets to %s", devServerURL)
+	}
+
 	// Wrap handler to add API routes
 	// IMPORTANT: Don't intercept /wails/* paths - let Wails handle them
 	apiHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -125,11 +274,28 @@ func main() {
 			return
 		}
 
+		if r.URL.Path == "/api/market-date/charts" && r.Method == "POST" {
+			// Move the chart windows still on the previous market date (?keep=true leaves them with a banner)
+			tickers := appInstance.RetargetChartsToMarketDate(r.URL.Query().Get("keep") == "true")
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string][]string{"charts": tickers})
+			return
+		}
+
+		if r.URL.Path == "/api/market-date/charts" {
+			// Chart windows still showing the previous market date after the 8:30 AM ET rollover
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetMarketDateChange())
+			return
+		}
+
 		if r.URL.Path == "/api/market-hours-local" {
 			// Get market hours in local timezone
+			// ?date=YYYY-MM-DD picks the UTC offset for that market date (EST or EDT)
 			openTime, closeTime := appInstance.GetMarketHoursLocal()
+			offset := appInstance.GetMarketUTCOffset(r.URL.Query().Get("date"))
 			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(map[string]string{"open": openTime, "close": closeTime})
+			json.NewEncoder(w).Encode(map[string]string{"open": openTime, "close": closeTime, "utc_offset": offset})
 			return
 		}
 
@@ -141,14 +307,763 @@ func main() {
 			return
 		}
 
+		if r.URL.Path == "/api/theme" && r.Method == "POST" {
+			// Switch the chart theme from the settings page: {"name": "light", "overrides": {"spot": "#00FF00"}}
+			var request struct {
+				Name      string            `json:"name"`
+				Overrides map[string]string `json:"overrides"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				http.Error(w, "invalid request body", http.StatusBadRequest)
+				return
+			}
+			if err := appInstance.SetTheme(request.Name, request.Overrides); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetTheme())
+			return
+		}
+
+		if r.URL.Path == "/api/theme" {
+			// Active chart theme (series overrides applied) and the built-in theme names
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"theme":     appInstance.GetTheme(),
+				"available": appInstance.GetAvailableThemes(),
+			})
+			return
+		}
+
+		if r.URL.Path == "/api/rate-limit" {
+			// Get rate limit status (main window quota gauge)
+			status := appInstance.GetRateLimitStatus()
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(status)
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/api/field-sources/") {
+			// Endpoint each field of a ticker's last row was merged from (debugging stale/conflicting values)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetFieldSources(strings.TrimPrefix(r.URL.Path, "/api/field-sources/")))
+			return
+		}
+
+		if r.URL.Path == "/api/batch-overlaps" {
+			// Ticker batches not started because the ticker's previous batch was still fetching (skipped or queued)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetBatchOverlapStatus())
+			return
+		}
+
+		if r.URL.Path == "/api/batch-coalescing" {
+			// Ticker timers merged into shared batches (batch_coalesce_window_ms)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetBatchCoalesceStatus())
+			return
+		}
+
+		if r.URL.Path == "/api/disk-space" {
+			// Free space on the data volume (disk_space thresholds) and whether only essential columns are written
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetDiskSpaceStatus())
+			return
+		}
+
+		if r.URL.Path == "/api/pre-open" {
+			// Today's pre-open phase: countdown to the open, API check and prepared databases
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetPreOpenStatus())
+			return
+		}
+
+		if r.URL.Path == "/api/clock-skew" {
+			// Local clock vs API timestamps and the correction applied to market time (header warning badge)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetClockSkewStatus())
+			return
+		}
+
+		if r.URL.Path == "/api/quota-saver" {
+			// Whether background tickers fetch only chart endpoints because the quota won't last until the close
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetQuotaSaverStatus())
+			return
+		}
+
+		if r.URL.Path == "/api/adaptive-batching" {
+			// Collection flush thresholds scaled by pending depth and flush latency
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetAdaptiveBatchStatus())
+			return
+		}
+
+		if r.URL.Path == "/api/rejected-values" {
+			// Values the schema's type or range checks refused (stored as NULL) and rows dropped, since startup
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetRejectedValueStatus())
+			return
+		}
+
+		if r.URL.Path == "/api/shutdown-progress" {
+			// Polled by the shutdown splash (N/M tickers flushed)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetShutdownProgress())
+			return
+		}
+
+		if r.URL.Path == "/api/query-plan-preview" {
+			// Cost of a ticker configuration before enabling it (?tickers=SPX,QQQ; none = the current tickers)
+			var tickers []string
+			if list := r.URL.Query().Get("tickers"); list != "" {
+				tickers = strings.Split(list, ",")
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetQueryPlanPreview(tickers))
+			return
+		}
+
+		if r.URL.Path == "/api/health" {
+			// Health check state, including whether eco (battery saving) mode is on
+			status := appInstance.GetHealthStatus()
+			status["eco"] = appInstance.GetEcoStatus()
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(status)
+			return
+		}
+
+		if r.URL.Path == "/api/daily-stats" {
+			// Session summary for a ticker (main window summary card); null when the day has no data
+			stats, err := appInstance.GetDailyStats(r.URL.Query().Get("ticker"), r.URL.Query().Get("date"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(stats)
+			return
+		}
+
+		if r.URL.Path == "/api/crash-reports/open" && r.Method == "POST" {
+			// Open a crash dump in the default viewer (?reveal=1 shows it in its folder)
+			if err := appInstance.OpenCrashReport(r.URL.Query().Get("path"), r.URL.Query().Get("reveal") == "1"); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]bool{"opened": true})
+			return
+		}
+
+		if r.URL.Path == "/api/crash-reports/read" {
+			// A crash dump's contents, for copying into a bug report
+			contents, err := appInstance.ReadCrashReport(r.URL.Query().Get("path"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]string{"contents": contents})
+			return
+		}
+
+		if r.URL.Path == "/api/crash-reports" {
+			// Crash dumps written after recovered panics, newest first (header badge)
+			reports, err := appInstance.GetCrashReports()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(reports)
+			return
+		}
+
+		if r.URL.Path == "/api/spot-check" {
+			// GEXBot spot vs the secondary quote source, per ticker (header warning badge)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetSpotCheckStatus())
+			return
+		}
+
+		if r.URL.Path == "/api/data-directory-lock" {
+			// Whether another collector held the data directory at startup (startup warning dialog)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetDataDirectoryLockStatus())
+			return
+		}
+
+		if r.URL.Path == "/api/alerts/recent" {
+			// Alerts triggered after ?after=SEQ (the main window polls this and plays their sounds)
+			afterSeq, _ := strconv.ParseInt(r.URL.Query().Get("after"), 10, 64)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"muted":  appInstance.settingsManager.GetSettings().LiveAlerts.Muted,
+				"alerts": appInstance.GetRecentAlerts(afterSeq),
+			})
+			return
+		}
+
+		if r.URL.Path == "/api/alerts/mute" && r.Method == "POST" {
+			// Global alert mute (?muted=true|false), e.g. from the header's alert badge
+			if err := appInstance.SetAlertsMuted(r.URL.Query().Get("muted") == "true"); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]bool{"muted": appInstance.settingsManager.GetSettings().LiveAlerts.Muted})
+			return
+		}
+
+		if r.URL.Path == "/api/alert-sound" {
+			// WAV data of a bundled or configured alert sound (?name=chime)
+			data, err := appInstance.GetAlertSoundWAV(r.URL.Query().Get("name"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "audio/wav")
+			w.Header().Set("Cache-Control", "max-age=3600")
+			w.Write(data)
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/api/price-alerts/") {
+			// Alert lines on a chart: GET /api/price-alerts/{ticker} lists, POST /api/price-alerts/{ticker}
+			// {"level", "direction"} creates, POST /api/price-alerts/{ticker}/{id}/move {"level"} and .../{id}/delete
+			parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/price-alerts/"), "/")
+			var result interface{}
+			var err error
+			switch {
+			case len(parts) == 1 && r.Method == "GET":
+				result = appInstance.ListPriceAlerts(parts[0])
+			case len(parts) == 1 && r.Method == "POST":
+				var body struct {
+					Level     float64 `json:"level"`
+					Direction string  `json:"direction"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				result, err = appInstance.CreatePriceAlert(parts[0], body.Level, body.Direction)
+			case len(parts) == 3 && parts[2] == "move" && r.Method == "POST":
+				var body struct {
+					Level float64 `json:"level"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				result, err = appInstance.MovePriceAlert(parts[1], body.Level)
+			case len(parts) == 3 && parts[2] == "delete" && r.Method == "POST":
+				err = appInstance.DeletePriceAlert(parts[1])
+				result = map[string]string{"deleted": parts[1]}
+			default:
+				http.Error(w, "Invalid API path", http.StatusBadRequest)
+				return
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+
+		if r.URL.Path == "/api/eco-mode" && r.Method == "POST" {
+			// Set eco_mode (?mode=auto|on|off), e.g. from the header's eco badge
+			if err := appInstance.SetEcoMode(r.URL.Query().Get("mode")); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetEcoStatus())
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/api/fetch-now/") && r.Method == "POST" {
+			// Fetch one ticker immediately (chart "Fetch now" button)
+			ticker := strings.TrimPrefix(r.URL.Path, "/api/fetch-now/")
+			timestamp, err := appInstance.FetchNow(ticker)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]float64{"timestamp": timestamp})
+			return
+		}
+
+		if r.URL.Path == "/api/compact" && r.Method == "POST" {
+			// Reclaim free space in previous days' databases
+			result, err := appInstance.CompactDatabases()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/api/export-chart/") && r.Method == "POST" {
+			// Export a chart image (chart "Export" button): /api/export-chart/{ticker}/{date}, JSON ImageOptions body
+			parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/export-chart/"), "/")
+			if len(parts) < 2 {
+				http.Error(w, "Invalid API path", http.StatusBadRequest)
+				return
+			}
+			var options charts.ImageOptions
+			if r.ContentLength != 0 {
+				if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+					http.Error(w, "Invalid export options", http.StatusBadRequest)
+					return
+				}
+			}
+			path, err := appInstance.ExportChartImage(parts[0], parts[1], options)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]string{"path": path})
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/api/replay/") && r.Method == "POST" {
+			// Re-extract a day from recorded raw responses: /api/replay/{ticker}/{date}
+			parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/replay/"), "/")
+			if len(parts) < 2 {
+				http.Error(w, "Invalid API path", http.StatusBadRequest)
+				return
+			}
+			result, err := appInstance.ReplayRecordedDay(parts[0], parts[1])
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/api/reprocess/") && r.Method == "POST" {
+			// Fill NULL scalar columns from stored profiles: /api/reprocess/{ticker}/{date}
+			parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/reprocess/"), "/")
+			if len(parts) < 2 {
+				http.Error(w, "Invalid API path", http.StatusBadRequest)
+				return
+			}
+			result, err := appInstance.ReprocessDay(parts[0], parts[1])
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/api/merge-database/") && r.Method == "POST" {
+			// Merge another machine's file into the local day: /api/merge-database/{ticker}/{date} {"source": path}
+			parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/merge-database/"), "/")
+			if len(parts) < 2 {
+				http.Error(w, "expected /api/merge-database/{ticker}/{date}", http.StatusBadRequest)
+				return
+			}
+			var request struct {
+				Source string `json:"source"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Source == "" {
+				http.Error(w, "expected a JSON body with the source database path", http.StatusBadRequest)
+				return
+			}
+			result, err := appInstance.MergeDatabases(request.Source, parts[0], parts[1])
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+
+		if r.URL.Path == "/api/diagnostics" && r.Method == "POST" {
+			// Write a diagnostics zip (log viewer "Export diagnostics" button); ?obfuscate=1 scales sampled values
+			path, err := appInstance.ExportDiagnostics("", r.URL.Query().Get("obfuscate") == "1")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]string{"path": path})
+			return
+		}
+
+		if r.URL.Path == "/api/profiles" {
+			// Configuration profiles and the one this process is running
+			profiles, err := appInstance.ListProfiles()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"running":  appInstance.GetRunningProfile(),
+				"profiles": profiles,
+			})
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/api/profiles/") && r.Method == "POST" {
+			// /api/profiles/{name}/create[?copy=1], /api/profiles/{name}/switch, /api/profiles/{name}/delete
+			parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/profiles/"), "/")
+			if len(parts) < 2 {
+				http.Error(w, "Invalid API path", http.StatusBadRequest)
+				return
+			}
+			var result interface{}
+			var err error
+			switch parts[1] {
+			case "create":
+				result, err = appInstance.CreateProfile(parts[0], r.URL.Query().Get("copy") == "1")
+			case "switch":
+				result, err = appInstance.SwitchProfile(parts[0])
+			case "delete":
+				err = appInstance.DeleteProfile(parts[0])
+				result = map[string]string{"deleted": parts[0]}
+			default:
+				http.Error(w, "Invalid API path", http.StatusBadRequest)
+				return
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+
+		if r.URL.Path == "/api/hotkeys" {
+			// Configured shortcuts and whether each is registered system-wide
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetHotkeyStatus())
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/api/hotkeys/") && r.Method == "POST" {
+			// Run a hotkey action seen by the focused main window: /api/hotkeys/{action}
+			if err := appInstance.TriggerHotkeyAction(strings.TrimPrefix(r.URL.Path, "/api/hotkeys/")); err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if r.URL.Path == "/api/hovered-ticker" && r.Method == "POST" {
+			// Ticker row under the cursor (?ticker=, empty when it leaves the table) for the open_chart hotkey
+			appInstance.SetHoveredTicker(r.URL.Query().Get("ticker"))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if r.URL.Path == "/api/workspaces" {
+			// Saved chart layouts
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"last":       appInstance.GetSettings().LastWorkspace,
+				"workspaces": appInstance.GetWorkspaces(),
+			})
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/api/workspaces/") && r.Method == "POST" {
+			// /api/workspaces/{name}/save, /api/workspaces/{name}/load, /api/workspaces/{name}/delete
+			parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/workspaces/"), "/")
+			if len(parts) < 2 {
+				http.Error(w, "Invalid API path", http.StatusBadRequest)
+				return
+			}
+			var result interface{}
+			var err error
+			switch parts[1] {
+			case "save":
+				result, err = appInstance.SaveWorkspace(parts[0])
+			case "load":
+				err = appInstance.LoadWorkspace(parts[0])
+				result = map[string]string{"loaded": parts[0]}
+			case "delete":
+				err = appInstance.DeleteWorkspace(parts[0])
+				result = map[string]string{"deleted": parts[0]}
+			default:
+				http.Error(w, "Invalid API path", http.StatusBadRequest)
+				return
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/api/chart-options/") {
+			// Per-chart options of an open chart window: GET on load, POST {"hidden_plots": [...]} on legend toggles
+			ticker := strings.TrimPrefix(r.URL.Path, "/api/chart-options/")
+			if r.Method == "POST" {
+				var options struct {
+					HiddenPlots []string `json:"hidden_plots"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				appInstance.SetChartHiddenPlots(ticker, options.HiddenPlots)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"hidden_plots": appInstance.GetChartHiddenPlots(ticker),
+			})
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/api/chart-refresh-rate/") {
+			// Refresh rate requested by a chart window: GET, or POST {"ms": 500} (0 = back to the normal rules)
+			ticker := strings.TrimPrefix(r.URL.Path, "/api/chart-refresh-rate/")
+			if r.Method == "POST" {
+				var request struct {
+					Ms int `json:"ms"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				if err := appInstance.SetChartRefreshRate(ticker, request.Ms); err != nil {
+					http.Error(w, err.Error(), http.StatusConflict)
+					return
+				}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]int{"ms": appInstance.GetChartRefreshRate(ticker)})
+			return
+		}
+
+		if r.URL.Path == "/api/monitors" {
+			// Attached screens and the monitor configuration key window placements are saved under
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetMonitors())
+			return
+		}
+
+		if r.URL.Path == "/api/settings-diff" && r.Method == "POST" {
+			// What saving the posted settings would change (confirmation dialog before SaveSettings)
+			var proposed config.Settings
+			if err := json.NewDecoder(r.Body).Decode(&proposed); err != nil {
+				http.Error(w, "Invalid settings", http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetSettingsDiff(&proposed))
+			return
+		}
+
+		if r.URL.Path == "/api/settings-history" {
+			// Previous versions of the settings file, newest first
+			versions, err := appInstance.ListSettingsHistory()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(versions)
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/api/settings-history/") && strings.HasSuffix(r.URL.Path, "/rollback") && r.Method == "POST" {
+			// Restore a previous settings version: /api/settings-history/{version}/rollback
+			version := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/settings-history/"), "/rollback")
+			diff, err := appInstance.RollbackSettings(version)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(diff)
+			return
+		}
+
+		if r.URL.Path == "/api/settings-validation" {
+			// Problems found in config.yaml at load (shown in a dialog on startup)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetSettingsValidation())
+			return
+		}
+
+		if r.URL.Path == "/api/traces" {
+			// Recent collection traces (?ticker=&limit=), or one trace with ?id= (full ID or log prefix)
+			w.Header().Set("Content-Type", "application/json")
+			if id := r.URL.Query().Get("id"); id != "" {
+				trace, err := appInstance.GetTrace(id)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusNotFound)
+					return
+				}
+				json.NewEncoder(w).Encode(trace)
+				return
+			}
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"traces": appInstance.GetRecentTraces(r.URL.Query().Get("ticker"), limit),
+				"export": appInstance.GetTraceExportStatus(),
+			})
+			return
+		}
+
+		if r.URL.Path == "/api/logs" {
+			// Recent log lines for the log viewer (?category=&level=&limit=)
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"entries":    appInstance.GetRecentLogs(r.URL.Query().Get("category"), r.URL.Query().Get("level"), limit),
+				"categories": appInstance.GetLogCategories(),
+			})
+			return
+		}
+
+		if r.URL.Path == "/api/log-viewer" && r.Method == "POST" {
+			// Open the log viewer window (main window "Logs" button)
+			if err := appInstance.OpenLogViewer(); err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
 		if r.URL.Path == "/api/available-dates" {
-			// Get available dates
+			// Get available dates (?ticker=X: only days with that ticker's database, under any of its names)
 			dates := appInstance.GetAvailableDates()
+			if ticker := r.URL.Query().Get("ticker"); ticker != "" {
+				dates = appInstance.GetAvailableDatesForTicker(ticker)
+			}
 			w.Header().Set("Content-Type", "application/json")
 			json.NewEncoder(w).Encode(dates)
 			return
 		}
 
+		if strings.HasPrefix(r.URL.Path, "/api/quality-markers/") {
+			// Flagged rows for chart markers: /api/quality-markers/{ticker}/{date}
+			parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/quality-markers/"), "/")
+			if len(parts) < 2 {
+				http.Error(w, "expected /api/quality-markers/{ticker}/{date}", http.StatusBadRequest)
+				return
+			}
+			markers, err := appInstance.GetQualityMarkers(parts[0], parts[1])
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(markers)
+			return
+		}
+
+		if r.URL.Path == "/api/processors" {
+			// Registered data processors, whether each is enabled and its counters
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetProcessorStatus())
+			return
+		}
+
+		if r.URL.Path == "/api/scripts" {
+			// Loaded Lua scripts, what they define and their call counters
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(appInstance.GetScriptStatus())
+			return
+		}
+
+		if r.URL.Path == "/api/scripts/reload" && r.Method == "POST" {
+			// Re-read the scripts directory after editing a script
+			result, err := appInstance.ReloadScripts()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/api/level-crosses/") {
+			// Spot crossing zero_gamma and the major levels: /api/level-crosses/{ticker}/{date}
+			parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/level-crosses/"), "/")
+			if len(parts) < 2 {
+				http.Error(w, "expected /api/level-crosses/{ticker}/{date}", http.StatusBadRequest)
+				return
+			}
+			events, err := appInstance.GetLevelCrossEvents(parts[0], parts[1])
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(events)
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/api/maxchange/") {
+			// Max-change history and top strikes: /api/maxchange/{ticker}/{date}
+			parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/maxchange/"), "/")
+			if len(parts) < 2 {
+				http.Error(w, "expected /api/maxchange/{ticker}/{date}", http.StatusBadRequest)
+				return
+			}
+			day, err := appInstance.GetMaxChange(parts[0], parts[1])
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(day)
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/api/data-range/") {
+			// One page of a time range: /api/data-range/{ticker}/{date}?start=&end=&after=&limit=
+			// (start/end default to the whole day, after to the first page, limit to the default page size)
+			parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/data-range/"), "/")
+			if len(parts) < 2 {
+				http.Error(w, "expected /api/data-range/{ticker}/{date}", http.StatusBadRequest)
+				return
+			}
+			query := r.URL.Query()
+			floatParam := func(name string, fallback float64) float64 {
+				if value, err := strconv.ParseFloat(query.Get(name), 64); err == nil {
+					return value
+				}
+				return fallback
+			}
+			limit, _ := strconv.Atoi(query.Get("limit"))
+			page, err := appInstance.GetTickerDataRangePage(parts[0], parts[1],
+				floatParam("start", 0), floatParam("end", math.MaxFloat64), floatParam("after", 0), limit)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(page)
+			return
+		}
+
 		if strings.HasPrefix(r.URL.Path, "/api/chart-data/") {
 			utils.Logf("[HTTP] Received chart-data request: %s", r.URL.Path)
 
@@ -160,9 +1075,61 @@ func main() {
 
 				utils.Logf("[HTTP] Parsed ticker=%s, date=%s", ticker, dateStr)
 
-				// Call GetChartData method
+				// Call GetChartData method (?start=&end= requests a raw zoomed window instead of the full day,
+				// ?tz= adds a "timezone" entry with the UTC offset and DST transitions for axis labels,
+				// ?fields=spot,zero_gamma returns only those columns plus timestamp)
 				utils.Logf("[HTTP] Calling GetChartData for %s on %s", ticker, dateStr)
-				data, err := appInstance.GetChartData(ticker, dateStr)
+				var data *model.ChartSeries
+				var err error
+				// A closed chart window aborts its request - stop loading instead of finishing for nobody
+				ctx, cancel := appInstance.requestContext(r.Context())
+				defer cancel()
+				tz, withTimezone := r.URL.Query()["tz"]
+				if fieldsStr := r.URL.Query().Get("fields"); fieldsStr != "" {
+					var startTime, endTime float64
+					if startStr, endStr := r.URL.Query().Get("start"), r.URL.Query().Get("end"); startStr != "" && endStr != "" {
+						var startErr, endErr error
+						startTime, startErr = strconv.ParseFloat(startStr, 64)
+						endTime, endErr = strconv.ParseFloat(endStr, 64)
+						if startErr != nil || endErr != nil {
+							http.Error(w, "Invalid start/end (expected Unix seconds)", http.StatusBadRequest)
+							return
+						}
+					}
+					data, err = appInstance.chartDataFields(ctx, ticker, dateStr, startTime, endTime, strings.Split(fieldsStr, ","))
+					if errors.Is(err, database.ErrUnknownChartField) {
+						http.Error(w, err.Error(), http.StatusBadRequest)
+						return
+					}
+					if err == nil && withTimezone {
+						var info utils.ChartTimezoneInfo
+						if info, err = appInstance.chartTimezoneInfo(dateStr, tz[0], startTime, endTime); err == nil {
+							data.Timezone = &info
+						}
+					}
+				} else if startStr, endStr := r.URL.Query().Get("start"), r.URL.Query().Get("end"); startStr != "" && endStr != "" {
+					startTime, startErr := strconv.ParseFloat(startStr, 64)
+					endTime, endErr := strconv.ParseFloat(endStr, 64)
+					if startErr != nil || endErr != nil {
+						http.Error(w, "Invalid start/end (expected Unix seconds)", http.StatusBadRequest)
+						return
+					}
+					data, err = appInstance.chartDataRange(ctx, ticker, dateStr, startTime, endTime)
+					if err == nil && withTimezone {
+						var info utils.ChartTimezoneInfo
+						if info, err = appInstance.chartTimezoneInfo(dateStr, tz[0], startTime, endTime); err == nil {
+							data.Timezone = &info
+						}
+					}
+				} else if withTimezone {
+					data, err = appInstance.chartDataInTimezone(ctx, ticker, dateStr, tz[0])
+				} else {
+					data, err = appInstance.loadChartData(ctx, ticker, dateStr, 0, 0, nil)
+				}
+				if ctx.Err() != nil {
+					utils.Logf("[HTTP] GetChartData for %s cancelled (request closed or shutting down)", ticker)
+					return
+				}
 				if err != nil {
 					utils.Logf("[HTTP] ERROR: GetChartData failed for %s: %v", ticker, err)
 					http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -170,17 +1137,28 @@ func main() {
 				}
 
 				// Log response data summary
-				timestampCount := 0
-				if timestamps, ok := data["timestamp"].([]interface{}); ok {
-					timestampCount = len(timestamps)
-					// Debug: Log first and last timestamp values to diagnose TZ issues
-					if len(timestamps) > 0 {
-						utils.Logf("[HTTP] First timestamp for %s: %v (type: %T)", ticker, timestamps[0], timestamps[0])
-						if len(timestamps) > 1 {
-							utils.Logf("[HTTP] Last timestamp for %s: %v (type: %T)", ticker, timestamps[len(timestamps)-1], timestamps[len(timestamps)-1])
-						}
+				timestampCount := data.Len()
+				// Debug: Log first and last timestamp values to diagnose TZ issues
+				if timestampCount > 0 {
+					utils.Logf("[HTTP] First timestamp for %s: %v", ticker, data.Timestamps[0])
+					if timestampCount > 1 {
+						utils.Logf("[HTTP] Last timestamp for %s: %v", ticker, data.Timestamps[timestampCount-1])
 					}
 				}
+				// ?format=binary returns typed columns instead of JSON (decoded by chart-binary.js)
+				if r.URL.Query().Get("format") == "binary" {
+					encoded, err := charts.EncodeChartBinary(data)
+					if err != nil {
+						utils.Logf("[HTTP] ERROR: Failed to encode binary chart data for %s: %v", ticker, err)
+						http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+						return
+					}
+					utils.Logf("[HTTP] GetChartData succeeded for %s: %d timestamps, sending binary response (%d bytes)", ticker, timestampCount, len(encoded))
+					w.Header().Set("Content-Type", charts.BinaryChartContentType)
+					w.Write(encoded)
+					return
+				}
+
 				utils.Logf("[HTTP] GetChartData succeeded for %s: %d timestamps, sending JSON response", ticker, timestampCount)
 
 				// Return JSON
@@ -199,10 +1177,20 @@ func main() {
 		}
 
 		// Serve static assets
-		assetHandler.ServeHTTP(w, r)
+		frontendHandler.ServeHTTP(w, r)
 	})
 
 	// Create application
+	// A second launch of the same profile activates this instance (forwarding --open-chart=TICKER) instead of
+	// starting another scheduler on the same API quota; read-only launches don't collect and may run alongside
+	var singleInstance *application.SingleInstanceOptions
+	if !launchReadOnly {
+		singleInstance = &application.SingleInstanceOptions{
+			UniqueID:               config.SingleInstanceID + "." + config.GetActiveProfile(),
+			OnSecondInstanceLaunch: appInstance.onSecondInstanceLaunch,
+		}
+	}
+
 	app := application.New(application.Options{
 		Name:        "Market Terminal Gexbot",
 		Description: "Market data terminal for GEXBot API",
@@ -215,14 +1203,38 @@ func main() {
 		Mac: application.MacOptions{
 			ApplicationShouldTerminateAfterLastWindowClosed: true,
 		},
+		SingleInstance: singleInstance,
+		// Quit runs the soft shutdown (final flush with a progress splash) first
+		ShouldQuit: appInstance.shouldQuit,
 	})
 
 	// Set function to create windows in appInstance
 	SetCreateWindowFunc(func(options application.WebviewWindowOptions) *application.WebviewWindow {
 		return app.Window.NewWithOptions(options)
 	})
+	SetEmitEventFunc(func(name string, data interface{}) {
+		app.Event.Emit(name, data)
+	})
+	SetScreensFunc(func() []*application.Screen {
+		return app.Screen.GetAll()
+	})
 	appInstance.SetApp(app)
 
+	// macOS delivers mgt:// links as an application event (at launch and while running); elsewhere they
+	// arrive on the command line, directly or forwarded by the single-instance check
+	app.Event.OnApplicationEvent(events.Common.ApplicationLaunchedWithUrl, func(e *application.ApplicationEvent) {
+		if runtime.GOOS != "darwin" {
+			return
+		}
+		appInstance.ShowMainWindow()
+		if err := appInstance.OpenDeepLink(e.Context().URL()); err != nil {
+			utils.Logf("[app] Deep link: %v", err)
+		}
+	})
+
+	// System tray icon with quick actions
+	setupSystemTray(app, appInstance)
+
 	utils.Logf("Application created, services registered")
 	utils.Logf("Starting app - window will be created in ServiceStartup after backend initialization")
 
@@ -232,3 +1244,123 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// runMigrateDataLayout moves the day directories into a layout and saves data_layout (--migrate-data-layout)
+// Returns the process exit code; the setting is only switched when every day moved
+func runMigrateDataLayout(settingsManager *config.SettingsManager, settings *config.Settings, layout string, dryRun bool) int {
+	if settings == nil {
+		fmt.Fprintf(os.Stderr, "Data layout migration failed: settings could not be loaded\n")
+		return 1
+	}
+	lock, ok := lockForCLI(settings)
+	if !ok {
+		return 1
+	}
+	defer lock.Release()
+	result, err := database.MigrateDataLayout(settings, layout, dryRun)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Data layout migration failed: %v\n", err)
+		return 1
+	}
+	if dryRun {
+		fmt.Printf("Dry run: %d day(s) and %d report(s) would move from the %s to the %s layout\n", result.Days, result.Reports, result.From, result.To)
+	} else {
+		fmt.Printf("%d day(s) and %d report(s) moved from the %s to the %s layout (%d file(s) merged into existing days)\n",
+			result.Days, result.Reports, result.From, result.To, result.Merged)
+	}
+	for _, message := range result.Errors {
+		fmt.Fprintf(os.Stderr, "  error: %s\n", message)
+	}
+	if len(result.Errors) > 0 {
+		fmt.Fprintf(os.Stderr, "data_layout left at %q - fix the errors above and run the migration again\n", settings.GetDataLayout())
+		return 1
+	}
+	if dryRun {
+		return 0
+	}
+
+	settings.DataLayout = result.To
+	if err := settingsManager.SaveSettings(settings); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save data_layout: %v\n", err)
+		return 1
+	}
+	fmt.Printf("data_layout set to %q\n", result.To)
+	return 0
+}
+
+// runMergeDatabase merges another machine's database into the local day from the command line (--merge-database)
+// and returns the exit code; spec is SOURCE,TICKER,DATE (the source path may itself contain commas)
+func runMergeDatabase(settings *config.Settings, spec string) int {
+	parts := strings.Split(spec, ",")
+	if len(parts) < 3 {
+		fmt.Fprintf(os.Stderr, "Invalid --merge-database %q (expected SOURCE,TICKER,DATE)\n", spec)
+		return 1
+	}
+	source := strings.Join(parts[:len(parts)-2], ",")
+	ticker := strings.ToUpper(strings.TrimSpace(parts[len(parts)-2]))
+	date, err := utils.ParseDateInET(strings.TrimSpace(parts[len(parts)-1]))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --merge-database date: %v\n", err)
+		return 1
+	}
+	if settings == nil {
+		settings = config.GetDefaultSettings()
+	}
+	lock, ok := lockForCLI(settings)
+	if !ok {
+		return 1
+	}
+	defer lock.Release()
+	dataWriter := database.NewDataWriter(settings, func(msg, category string) {
+		utils.Logf("[%s] %s", category, msg)
+	})
+	defer dataWriter.Close()
+
+	result, err := dataWriter.MergeDatabases(context.Background(), source, ticker, date)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Database merge failed: %v\n", err)
+		return 1
+	}
+	fmt.Printf("%s on %s: %d of %d row(s) added from %s, %d already present\n",
+		result.Ticker, result.Date, result.Added, result.SourceRows, result.Source, result.Duplicates)
+	return 0
+}
+
+// runPruneColumns runs column pruning from the command line (no window) and returns the exit code
+func runPruneColumns(settings *config.Settings, keep []string, dryRun bool) int {
+	if settings == nil {
+		settings = config.GetDefaultSettings()
+	}
+	lock, ok := lockForCLI(settings)
+	if !ok {
+		return 1
+	}
+	defer lock.Release()
+	dataWriter := database.NewDataWriter(settings, func(msg, category string) {
+		utils.Logf("[%s] %s", category, msg)
+	})
+	defer dataWriter.Close()
+
+	result, err := dataWriter.PruneColumns(keep, dryRun)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Column pruning failed: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Keeping columns: %s\n", strings.Join(result.Keep, ", "))
+	for column, count := range result.DroppedColumns {
+		fmt.Printf("  %s: dropped from %d database(s)\n", column, count)
+	}
+	if dryRun {
+		fmt.Printf("Dry run: %d database(s) would be rewritten, %d already pruned\n", result.Databases, result.Skipped)
+	} else {
+		fmt.Printf("%d database(s) rewritten, %d already pruned, %.1f MB reclaimed\n",
+			result.Databases, result.Skipped, float64(result.ReclaimedBytes)/1024/1024)
+	}
+	for _, message := range result.Errors {
+		fmt.Fprintf(os.Stderr, "  error: %s\n", message)
+	}
+	if len(result.Errors) > 0 {
+		return 1
+	}
+	return 0
+}
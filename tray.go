@@ -0,0 +1,83 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+
+	"market-terminal/internal/config"
+	"market-terminal/internal/utils"
+)
+
+//go:embed MGT.png
+var trayIcon []byte
+
+// trayChartTicker is the ticker opened by the tray's quick chart action
+const trayChartTicker = "SPX"
+
+// setupSystemTray adds the tray icon with quick actions (the app usually runs all day in the background)
+func setupSystemTray(app *application.App, appInstance *App) {
+	tray := app.SystemTray.New()
+	tray.SetIcon(trayIcon)
+	tray.SetTooltip("Market Terminal Gexbot")
+
+	menu := application.NewMenu()
+	menu.Add("Open Main Window").OnClick(func(ctx *application.Context) {
+		appInstance.ShowMainWindow()
+	})
+
+	pauseItem := menu.AddCheckbox("Pause Collection", false)
+	pauseItem.OnClick(func(ctx *application.Context) {
+		var err error
+		if appInstance.IsCollectionPaused() {
+			err = appInstance.ResumeCollection()
+		} else {
+			err = appInstance.PauseCollection()
+		}
+		if err != nil {
+			utils.Logf("[tray] Pause/resume collection failed: %v", err)
+		}
+		pauseItem.SetChecked(appInstance.IsCollectionPaused())
+	})
+	if appInstance.IsReadOnly() {
+		pauseItem.SetLabel("Collection Disabled (Read-Only)")
+	}
+
+	// Manual eco toggle; unchecking forces it off even on battery (eco_mode: auto is set in config.yaml/the header badge)
+	ecoItem := menu.AddCheckbox("Eco Mode (Battery Saver)", appInstance.GetEcoStatus().Active)
+	ecoItem.OnClick(func(ctx *application.Context) {
+		mode := config.EcoModeOn
+		if appInstance.GetEcoStatus().Active {
+			mode = config.EcoModeOff
+		}
+		if err := appInstance.SetEcoMode(mode); err != nil {
+			utils.Logf("[tray] Eco mode toggle failed: %v", err)
+		}
+		ecoItem.SetChecked(appInstance.GetEcoStatus().Active)
+	})
+
+	menu.Add(fmt.Sprintf("Open Latest %s Chart", trayChartTicker)).OnClick(func(ctx *application.Context) {
+		// Newest day with data, or today if nothing has been collected yet
+		dateStr := appInstance.GetCurrentMarketDate()
+		if dates := appInstance.GetAvailableDates(); len(dates) > 0 {
+			dateStr = dates[0]
+		}
+		if err := appInstance.OpenChartWindow(trayChartTicker, dateStr); err != nil {
+			utils.Logf("[tray] Failed to open %s chart: %v", trayChartTicker, err)
+		}
+	})
+
+	menu.AddSeparator()
+	menu.Add("Quit").OnClick(func(ctx *application.Context) {
+		// Quit runs the soft shutdown: pending writes are flushed (with a progress splash) and databases checkpointed
+		utils.Logf("[tray] Quit requested - flushing and shutting down")
+		appInstance.RequestQuit()
+		app.Quit()
+	})
+
+	tray.SetMenu(menu)
+	tray.OnClick(func() {
+		appInstance.ShowMainWindow()
+	})
+}